@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+)
+
+// Set the caBundle of every webhook in the MutatingWebhookConfiguration to
+// the self-provisioned CA, using the pod's service account
+func patchCABundle(configName string, caPEM []byte) error {
+	client, err := newInClusterClient()
+	if err != nil {
+		return err
+	}
+	path := "/apis/admissionregistration.k8s.io/v1/mutatingwebhookconfigurations/" + configName
+
+	// Get the configuration to patch each of its webhooks
+	config := admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := client.get(path, &config); err != nil {
+		return err
+	}
+	patches, err := caBundlePatches(config, caPEM)
+	if err != nil {
+		return fmt.Errorf("MutatingWebhookConfiguration %s: %w", configName, err)
+	}
+	return client.patch(path, jsonPatchType, patches)
+}
+
+// JSON patch setting the caBundle of every webhook of a
+// MutatingWebhookConfiguration
+func caBundlePatches(config admissionregistrationv1.MutatingWebhookConfiguration, caPEM []byte) ([]map[string]any, error) {
+	if len(config.Webhooks) == 0 {
+		return nil, fmt.Errorf("no webhooks")
+	}
+
+	patches := []map[string]any{}
+	for i := range config.Webhooks {
+		patches = append(patches, map[string]any{
+			"op":    "add",
+			"path":  fmt.Sprintf("/webhooks/%d/clientConfig/caBundle", i),
+			"value": caPEM, // base64 encoded like the []byte field
+		})
+	}
+	return patches, nil
+}
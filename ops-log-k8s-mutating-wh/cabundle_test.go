@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+)
+
+func TestCABundlePatches(t *testing.T) {
+	caPEM := []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n")
+	config := admissionregistrationv1.MutatingWebhookConfiguration{}
+	raw := `{"metadata": {"name": "prysm-webhook"}, "webhooks": [{"name": "a.prysm.io"}, {"name": "b.prysm.io"}]}`
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		t.Fatalf("unmarshal configuration: %v", err)
+	}
+
+	built, err := caBundlePatches(config, caPEM)
+	if err != nil {
+		t.Fatalf("build patches: %v", err)
+	}
+	// As sent to the API server
+	patchBytes, err := json.Marshal(built)
+	if err != nil {
+		t.Fatalf("marshal patches: %v", err)
+	}
+	var patches []map[string]string
+	if err := json.Unmarshal(patchBytes, &patches); err != nil {
+		t.Fatalf("unmarshal patches: %v", err)
+	}
+	if len(patches) != 2 {
+		t.Fatalf("expected a patch per webhook, got %v", patches)
+	}
+	for i, path := range []string{"/webhooks/0/clientConfig/caBundle", "/webhooks/1/clientConfig/caBundle"} {
+		if patches[i]["op"] != "add" || patches[i]["path"] != path {
+			t.Fatalf("unexpected patch %v", patches[i])
+		}
+		if bundle, err := base64.StdEncoding.DecodeString(patches[i]["value"]); err != nil || string(bundle) != string(caPEM) {
+			t.Fatalf("caBundle is not the base64 encoded CA: %q", patches[i]["value"])
+		}
+	}
+}
+
+func TestCABundlePatches_NoWebhooks(t *testing.T) {
+	config := admissionregistrationv1.MutatingWebhookConfiguration{}
+	if _, err := caBundlePatches(config, []byte("ca")); err == nil {
+		t.Fatalf("expected an error for a configuration without webhooks")
+	}
+}
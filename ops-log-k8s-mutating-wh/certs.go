@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// How often the certificate files are checked for changes, and how long
+// self-provisioned certificates are valid
+const (
+	certReloadInterval = 30 * time.Second
+	caValidity         = 10 * 365 * 24 * time.Hour
+	certValidity       = 365 * 24 * time.Hour
+)
+
+// Serves the certificate in certFile and keyFile, reloaded when either
+// changes, e.g. when cert-manager renews the Secret mounted at /certs
+type certReloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Reload the certificate when the files change; a certificate that fails
+// to load, e.g. while the files are being replaced, is retried next time
+func (r *certReloader) watch(interval time.Duration) {
+	lastModified := r.modTime()
+	for range time.Tick(interval) {
+		modified := r.modTime()
+		if modified.Equal(lastModified) {
+			continue
+		}
+		if err := r.load(); err != nil {
+			log.Error().Err(err).Msg("Keeping previous certificate")
+			continue
+		}
+		lastModified = modified
+		log.Info().Msgf("Reloaded certificate from %s", r.certFile)
+	}
+}
+
+// Latest modification time of the certificate and key files
+func (r *certReloader) modTime() time.Time {
+	latest := time.Time{}
+	for _, file := range []string{r.certFile, r.keyFile} {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// Serves a certificate issued by a CA generated at startup, for
+// installations without cert-manager. The certificate is reissued before it
+// expires; the CA outlives the process.
+type selfSignedCerts struct {
+	dnsNames []string
+	ca       *x509.Certificate
+	caKey    *ecdsa.PrivateKey
+	caPEM    []byte
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+func newSelfSignedCerts(dnsNames []string) (*selfSignedCerts, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "prysm-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA: %w", err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA: %w", err)
+	}
+
+	certs := &selfSignedCerts{
+		dnsNames: dnsNames,
+		ca:       ca,
+		caKey:    caKey,
+		caPEM:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+	if err := certs.issue(); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// Issue a new serving certificate signed by the CA
+func (c *selfSignedCerts) issue() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: c.dnsNames[0]},
+		DNSNames:     c.dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.ca, &key.PublicKey, c.caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	c.cert.Store(&tls.Certificate{
+		Certificate: [][]byte{der, c.ca.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	})
+	return nil
+}
+
+func (c *selfSignedCerts) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+// Reissue the certificate once two thirds of its validity have passed
+func (c *selfSignedCerts) renew(interval time.Duration) {
+	for range time.Tick(interval) {
+		leaf := c.cert.Load().Leaf
+		renewAt := leaf.NotBefore.Add(leaf.NotAfter.Sub(leaf.NotBefore) * 2 / 3)
+		if time.Now().Before(renewAt) {
+			continue
+		}
+		if err := c.issue(); err != nil {
+			log.Error().Err(err).Msg("Failed to renew certificate")
+			continue
+		}
+		log.Info().Msgf("Renewed certificate for %v", c.dnsNames)
+	}
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		panic(err) // crypto/rand does not fail
+	}
+	return serial
+}
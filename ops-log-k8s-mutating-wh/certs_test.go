@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Write the serving certificate of a new self-signed CA to certFile and keyFile
+func writeCert(t *testing.T, certFile, keyFile string) *x509.Certificate {
+	t.Helper()
+	certs, err := newSelfSignedCerts([]string{"prysm-webhook-service.rook-ceph.svc"})
+	if err != nil {
+		t.Fatalf("issue certificate: %v", err)
+	}
+	cert := certs.cert.Load()
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return cert.Leaf
+}
+
+func servedSerial(t *testing.T, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) string {
+	t.Helper()
+	cert, err := getCertificate(nil)
+	if err != nil {
+		t.Fatalf("get certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return leaf.SerialNumber.String()
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := writeCert(t, certFile, keyFile)
+
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("load certificate: %v", err)
+	}
+	if serial := servedSerial(t, reloader.GetCertificate); serial != first.SerialNumber.String() {
+		t.Fatalf("expected the certificate of the files, got serial %s", serial)
+	}
+	go reloader.watch(10 * time.Millisecond)
+
+	// A key not matching the certificate, e.g. while cert-manager writes
+	// the Secret, keeps the previous certificate
+	if err := os.WriteFile(keyFile, []byte("invalid"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(keyFile, future, future); err != nil {
+		t.Fatalf("touch key: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if serial := servedSerial(t, reloader.GetCertificate); serial != first.SerialNumber.String() {
+		t.Fatalf("certificate replaced by an invalid one")
+	}
+
+	renewed := writeCert(t, certFile, keyFile)
+	later := future.Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatalf("touch %s: %v", file, err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for servedSerial(t, reloader.GetCertificate) != renewed.SerialNumber.String() {
+		if time.Now().After(deadline) {
+			t.Fatalf("renewed certificate not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewCertReloader_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := newCertReloader(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")); err == nil {
+		t.Fatalf("expected an error for missing files")
+	}
+}
+
+func TestSelfSignedCerts(t *testing.T) {
+	dnsNames := []string{"prysm-webhook-service.rook-ceph.svc", "prysm-webhook-service.rook-ceph.svc.cluster.local"}
+	certs, err := newSelfSignedCerts(dnsNames)
+	if err != nil {
+		t.Fatalf("issue certificate: %v", err)
+	}
+
+	block, _ := pem.Decode(certs.caPEM)
+	if block == nil || !bytes.Equal(block.Bytes, certs.ca.Raw) {
+		t.Fatalf("caPEM does not hold the CA")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(certs.ca)
+	leaf := certs.cert.Load().Leaf
+	for _, name := range dnsNames {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: name, Roots: roots}); err != nil {
+			t.Fatalf("certificate not valid for %s: %v", name, err)
+		}
+	}
+}
+
+func TestSelfSignedCerts_Renew(t *testing.T) {
+	certs, err := newSelfSignedCerts([]string{"prysm-webhook-service.rook-ceph.svc"})
+	if err != nil {
+		t.Fatalf("issue certificate: %v", err)
+	}
+	first := servedSerial(t, certs.GetCertificate)
+
+	// Not renewed before two thirds of the validity have passed
+	go certs.renew(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if serial := servedSerial(t, certs.GetCertificate); serial != first {
+		t.Fatalf("certificate renewed too early")
+	}
+
+	// A certificate close to its expiry is reissued by the same CA
+	current := *certs.cert.Load()
+	leaf := *current.Leaf
+	leaf.NotBefore = time.Now().Add(-certValidity)
+	leaf.NotAfter = time.Now().Add(time.Hour)
+	current.Leaf = &leaf
+	certs.cert.Store(&current)
+	deadline := time.Now().Add(5 * time.Second)
+	for servedSerial(t, certs.GetCertificate) == first {
+		if time.Now().After(deadline) {
+			t.Fatalf("certificate not renewed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := certs.cert.Load().Leaf.CheckSignatureFrom(certs.ca); err != nil {
+		t.Fatalf("renewed certificate not signed by the CA: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PrysmOpsLog resources, see manifest-examples/09-prysmopslog-crd.yaml,
+// and how often they are reconciled
+const (
+	opsLogResourcePath     = "/apis/prysm.cobaltcore.dev/v1alpha1"
+	controllerSyncInterval = 30 * time.Second
+)
+
+// Label and annotation the controller sets on the deployments it manages.
+// Changing them sends the deployment through the webhook again.
+const (
+	managedLabel             = "prysm.cobaltcore.dev/ops-log"
+	managedByAnnotation      = "prysm.cobaltcore.dev/ops-log-resource"
+	opsLogResourceRulePrefix = "prysmopslog/"
+)
+
+// PrysmOpsLog describes the ops-log collection for deployments in its
+// namespace, like an injection rule restricted to that namespace
+type PrysmOpsLog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              PrysmOpsLogSpec   `json:"spec"`
+	Status            PrysmOpsLogStatus `json:"status,omitempty"`
+}
+
+type PrysmOpsLogSpec struct {
+	Selector     *metav1.LabelSelector `json:"selector"`
+	Sidecar      *corev1.Container     `json:"sidecar,omitempty"`
+	Env          []corev1.EnvVar       `json:"env,omitempty"`
+	Containers   []string              `json:"containers,omitempty"`
+	OpsLogSocket bool                  `json:"opsLogSocket,omitempty"`
+	RGWContainer string                `json:"rgwContainer,omitempty"`
+}
+
+type PrysmOpsLogStatus struct {
+	ObservedGeneration int64    `json:"observedGeneration,omitempty"`
+	Deployments        []string `json:"deployments"`
+	Error              string   `json:"error,omitempty"`
+}
+
+type PrysmOpsLogList struct {
+	Items []PrysmOpsLog `json:"items"`
+}
+
+// The injection rule a PrysmOpsLog stands for
+func (opsLog *PrysmOpsLog) rule() InjectionRule {
+	return InjectionRule{
+		Name:         opsLogResourceRulePrefix + opsLog.Namespace + "/" + opsLog.Name,
+		Namespaces:   []string{opsLog.Namespace},
+		Selector:     opsLog.Spec.Selector,
+		Sidecar:      opsLog.Spec.Sidecar,
+		Env:          opsLog.Spec.Env,
+		Containers:   opsLog.Spec.Containers,
+		OpsLogSocket: opsLog.Spec.OpsLogSocket,
+		RGWContainer: opsLog.Spec.RGWContainer,
+	}
+}
+
+// Reconciles PrysmOpsLog resources: turns them into injection rules and
+// sends the deployments they select, or no longer select, through the
+// webhook by updating the managed label and annotation
+type opsLogController struct {
+	client *kubeClient
+}
+
+func (c *opsLogController) run(interval time.Duration) {
+	for {
+		if err := c.sync(); err != nil {
+			log.Error().Err(err).Msg("Failed to reconcile PrysmOpsLog resources")
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (c *opsLogController) sync() error {
+	list := PrysmOpsLogList{}
+	if err := c.client.get(opsLogResourcePath+"/prysmopslogs", &list); err != nil {
+		return err
+	}
+	// Resources are matched in a stable order, the first one claims a deployment
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Namespace+"/"+list.Items[i].Name < list.Items[j].Namespace+"/"+list.Items[j].Name
+	})
+
+	rules := []InjectionRule{}
+	statuses := make([]PrysmOpsLogStatus, len(list.Items))
+	desired := map[string]desiredDeployment{} // by namespace/name
+	for i := range list.Items {
+		opsLog := &list.Items[i]
+		status := &statuses[i]
+		*status = PrysmOpsLogStatus{ObservedGeneration: opsLog.Generation, Deployments: []string{}}
+
+		rule := []InjectionRule{opsLog.rule()}
+		if err := prepareRules(rule); err != nil {
+			status.Error = err.Error()
+			continue
+		}
+
+		deployments, err := c.listDeployments(opsLog.Namespace, rule[0].selector.String())
+		if err != nil {
+			return err
+		}
+		for _, deployment := range deployments {
+			key := deployment.Namespace + "/" + deployment.Name
+			if _, claimed := desired[key]; claimed {
+				continue
+			}
+			desired[key] = desiredDeployment{
+				deployment: deployment,
+				managedBy:  opsLog.Name + "/" + strconv.FormatInt(opsLog.Generation, 10),
+			}
+			status.Deployments = append(status.Deployments, deployment.Name)
+		}
+		rules = append(rules, rule[0])
+	}
+
+	// Store the rules before touching deployments, the webhook applies them
+	storeRules(&resourceRules, rules)
+
+	// Every replica needs the rules, but one of them writing is enough
+	if decisions != nil && !decisions.allow("controller-sync", controllerSyncInterval/2) {
+		return nil
+	}
+	for i := range list.Items {
+		c.updateStatus(&list.Items[i], statuses[i])
+	}
+
+	managed, err := c.listDeployments("", managedLabel)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range managed {
+		key := deployment.Namespace + "/" + deployment.Name
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		log.Info().Msgf("Releasing deployment %s, no PrysmOpsLog selects it", key)
+		c.patchDeployment(deployment, nil)
+	}
+
+	for key, want := range desired {
+		deployment := want.deployment
+		if deployment.Annotations[managedByAnnotation] == want.managedBy && deployment.Labels[managedLabel] == "true" {
+			continue
+		}
+		log.Info().Msgf("Reconciling deployment %s for PrysmOpsLog %s", key, want.managedBy)
+		c.patchDeployment(deployment, &want.managedBy)
+	}
+	return nil
+}
+
+// A deployment selected by a PrysmOpsLog, and its managed-by annotation:
+// the resource name and generation, so spec changes reach the deployment
+type desiredDeployment struct {
+	deployment appsv1.Deployment
+	managedBy  string
+}
+
+// List deployments matching a label selector, in all namespaces if empty
+func (c *opsLogController) listDeployments(namespace, selector string) ([]appsv1.Deployment, error) {
+	list := appsv1.DeploymentList{}
+	path := deploymentPath(namespace)
+	if selector != "" {
+		path += "?labelSelector=" + url.QueryEscape(selector)
+	}
+	if err := c.client.get(path, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Set the managed label and annotation, or remove them if managedBy is nil
+func (c *opsLogController) patchDeployment(deployment appsv1.Deployment, managedBy *string) {
+	var label *string
+	if managedBy != nil {
+		label = pointerTo("true")
+	}
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"labels":      map[string]any{managedLabel: label},
+			"annotations": map[string]any{managedByAnnotation: managedBy},
+		},
+	}
+	path := deploymentPath(deployment.Namespace) + "/" + deployment.Name
+	if err := c.client.patch(path, mergePatchType, patch); err != nil {
+		log.Error().Err(err).Msgf("Failed to patch deployment %s/%s", deployment.Namespace, deployment.Name)
+	}
+}
+
+// Update the status of a PrysmOpsLog if it changed
+func (c *opsLogController) updateStatus(opsLog *PrysmOpsLog, status PrysmOpsLogStatus) {
+	if equalJSON(opsLog.Status, status) {
+		return
+	}
+	if status.Error != "" {
+		log.Error().Msgf("Invalid PrysmOpsLog %s/%s: %s", opsLog.Namespace, opsLog.Name, status.Error)
+	}
+	path := fmt.Sprintf("%s/namespaces/%s/prysmopslogs/%s/status", opsLogResourcePath, opsLog.Namespace, opsLog.Name)
+	if err := c.client.patch(path, mergePatchType, map[string]any{"status": status}); err != nil {
+		log.Error().Err(err).Msgf("Failed to update status of PrysmOpsLog %s/%s", opsLog.Namespace, opsLog.Name)
+	}
+}
+
+func deploymentPath(namespace string) string {
+	if namespace == "" {
+		return "/apis/apps/v1/deployments"
+	}
+	return "/apis/apps/v1/namespaces/" + namespace + "/deployments"
+}
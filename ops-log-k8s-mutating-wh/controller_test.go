@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fake API server holding PrysmOpsLog resources and deployments, recording
+// the patches it receives
+type fakeAPIServer struct {
+	mu          sync.Mutex
+	opsLogs     []PrysmOpsLog
+	deployments []appsv1.Deployment
+	patches     map[string]map[string]any // by path
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == opsLogResourcePath+"/prysmopslogs":
+		_ = json.NewEncoder(w).Encode(PrysmOpsLogList{Items: s.opsLogs})
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/deployments"):
+		namespace := ""
+		if parts := strings.Split(r.URL.Path, "/"); len(parts) == 7 {
+			namespace = parts[5]
+		}
+		list := appsv1.DeploymentList{}
+		for _, deployment := range s.deployments {
+			if (namespace == "" || deployment.Namespace == namespace) && selects(r.URL.Query().Get("labelSelector"), deployment.Labels) {
+				list.Items = append(list.Items, deployment)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPatch && r.Header.Get("Content-Type") == mergePatchType:
+		body, _ := io.ReadAll(r.Body)
+		patch := map[string]any{}
+		if err := json.Unmarshal(body, &patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.patches[r.URL.Path] = patch
+		_, _ = w.Write([]byte("{}"))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// Match a selector of key and key=value terms
+func selects(selector string, labels map[string]string) bool {
+	if selector == "" {
+		return true
+	}
+	for _, term := range strings.Split(selector, ",") {
+		key, value, hasValue := strings.Cut(term, "=")
+		actual, ok := labels[key]
+		if !ok || hasValue && actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func newTestController(t *testing.T, server *fakeAPIServer) *opsLogController {
+	t.Helper()
+	server.patches = map[string]map[string]any{}
+	api := httptest.NewServer(server)
+	t.Cleanup(api.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	// Start and end without resource rules
+	t.Cleanup(func() { storeRules(&resourceRules, nil) })
+	return &opsLogController{client: &kubeClient{client: api.Client(), server: api.URL, tokenFile: tokenFile}}
+}
+
+func testOpsLog(namespace, name string, generation int64, selector map[string]string) PrysmOpsLog {
+	return PrysmOpsLog{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: generation},
+		Spec: PrysmOpsLogSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Sidecar:  &corev1.Container{Image: "prysm:1"},
+		},
+	}
+}
+
+func labeledDeployment(namespace, name string, labels, annotations map[string]string) appsv1.Deployment {
+	return appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Annotations: annotations}}
+}
+
+// Labels and annotations of a merge patch, nil values removing them
+func patchedMetadata(t *testing.T, patch map[string]any) (map[string]any, map[string]any) {
+	t.Helper()
+	metadata, ok := patch["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("patch without metadata: %v", patch)
+	}
+	labels, _ := metadata["labels"].(map[string]any)
+	annotations, _ := metadata["annotations"].(map[string]any)
+	return labels, annotations
+}
+
+func TestOpsLogController_ManagesSelectedDeployments(t *testing.T) {
+	server := &fakeAPIServer{
+		opsLogs: []PrysmOpsLog{testOpsLog("rook-ceph", "rgw", 3, map[string]string{"app": "rgw"})},
+		deployments: []appsv1.Deployment{
+			labeledDeployment("rook-ceph", "rgw-a", map[string]string{"app": "rgw"}, nil),
+			labeledDeployment("rook-ceph", "mgr", map[string]string{"app": "mgr"}, nil),
+			labeledDeployment("other", "rgw-b", map[string]string{"app": "rgw"}, nil),
+		},
+	}
+	controller := newTestController(t, server)
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	if len(server.patches) != 2 {
+		t.Fatalf("expected a deployment and a status patch, got %v", server.patches)
+	}
+	labels, annotations := patchedMetadata(t, server.patches[deploymentPath("rook-ceph")+"/rgw-a"])
+	if labels[managedLabel] != "true" || annotations[managedByAnnotation] != "rgw/3" {
+		t.Fatalf("deployment not managed: %v %v", labels, annotations)
+	}
+	status := server.patches[opsLogResourcePath+"/namespaces/rook-ceph/prysmopslogs/rgw/status"]["status"].(map[string]any)
+	if deployments := status["deployments"].([]any); len(deployments) != 1 || deployments[0] != "rgw-a" {
+		t.Fatalf("unexpected status %v", status)
+	}
+
+	rules := *activeRules.Load()
+	if len(rules) == 0 || rules[0].Name != opsLogResourceRulePrefix+"rook-ceph/rgw" {
+		t.Fatalf("resource rule not active first: %+v", rules)
+	}
+	if rule := matchRule("rook-ceph", map[string]string{"app": "rgw"}); rule == nil || rule.Name != rules[0].Name {
+		t.Fatalf("resource rule does not match the deployment: %+v", rule)
+	}
+}
+
+func TestOpsLogController_UpToDateDeploymentNotPatched(t *testing.T) {
+	opsLogResource := testOpsLog("rook-ceph", "rgw", 3, map[string]string{"app": "rgw"})
+	opsLogResource.Status = PrysmOpsLogStatus{ObservedGeneration: 3, Deployments: []string{"rgw-a"}}
+	server := &fakeAPIServer{
+		opsLogs: []PrysmOpsLog{opsLogResource},
+		deployments: []appsv1.Deployment{labeledDeployment("rook-ceph", "rgw-a",
+			map[string]string{"app": "rgw", managedLabel: "true"},
+			map[string]string{managedByAnnotation: "rgw/3"})},
+	}
+	controller := newTestController(t, server)
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(server.patches) != 0 {
+		t.Fatalf("expected no patches, got %v", server.patches)
+	}
+
+	// A new generation sends the deployment through the webhook again
+	server.opsLogs[0].Generation = 4
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, annotations := patchedMetadata(t, server.patches[deploymentPath("rook-ceph")+"/rgw-a"]); annotations[managedByAnnotation] != "rgw/4" {
+		t.Fatalf("deployment not updated for the new generation: %v", annotations)
+	}
+}
+
+func TestOpsLogController_ReleasesUnselectedDeployments(t *testing.T) {
+	server := &fakeAPIServer{
+		deployments: []appsv1.Deployment{labeledDeployment("rook-ceph", "rgw-a",
+			map[string]string{"app": "rgw", managedLabel: "true"},
+			map[string]string{managedByAnnotation: "rgw/3"})},
+	}
+	controller := newTestController(t, server)
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	patch, ok := server.patches[deploymentPath("rook-ceph")+"/rgw-a"]
+	if !ok {
+		t.Fatalf("deployment not released: %v", server.patches)
+	}
+	labels, annotations := patchedMetadata(t, patch)
+	if value, ok := labels[managedLabel]; !ok || value != nil {
+		t.Fatalf("managed label not removed: %v", labels)
+	}
+	if value, ok := annotations[managedByAnnotation]; !ok || value != nil {
+		t.Fatalf("managed-by annotation not removed: %v", annotations)
+	}
+}
+
+func TestOpsLogController_FirstResourceClaimsDeployment(t *testing.T) {
+	server := &fakeAPIServer{
+		opsLogs: []PrysmOpsLog{
+			testOpsLog("rook-ceph", "b", 1, map[string]string{"app": "rgw"}),
+			testOpsLog("rook-ceph", "a", 1, map[string]string{"app": "rgw"}),
+		},
+		deployments: []appsv1.Deployment{labeledDeployment("rook-ceph", "rgw-a", map[string]string{"app": "rgw"}, nil)},
+	}
+	controller := newTestController(t, server)
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	if _, annotations := patchedMetadata(t, server.patches[deploymentPath("rook-ceph")+"/rgw-a"]); annotations[managedByAnnotation] != "a/1" {
+		t.Fatalf("expected the first resource to claim the deployment: %v", annotations)
+	}
+	status := server.patches[opsLogResourcePath+"/namespaces/rook-ceph/prysmopslogs/b/status"]["status"].(map[string]any)
+	if deployments := status["deployments"].([]any); len(deployments) != 0 {
+		t.Fatalf("deployment claimed twice: %v", status)
+	}
+}
+
+func TestOpsLogController_InvalidResource(t *testing.T) {
+	invalid := testOpsLog("rook-ceph", "rgw", 1, map[string]string{"app": "rgw"})
+	invalid.Spec.Sidecar = nil
+	server := &fakeAPIServer{
+		opsLogs:     []PrysmOpsLog{invalid},
+		deployments: []appsv1.Deployment{labeledDeployment("rook-ceph", "rgw-a", map[string]string{"app": "rgw"}, nil)},
+	}
+	controller := newTestController(t, server)
+	if err := controller.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	if _, ok := server.patches[deploymentPath("rook-ceph")+"/rgw-a"]; ok {
+		t.Fatalf("deployment managed by an invalid resource")
+	}
+	status := server.patches[opsLogResourcePath+"/namespaces/rook-ceph/prysmopslogs/rgw/status"]["status"].(map[string]any)
+	if message, _ := status["error"].(string); !strings.Contains(message, "injects neither a sidecar nor env") {
+		t.Fatalf("error not reported in the status: %v", status)
+	}
+	if rules := resourceRules.Load(); rules == nil || len(*rules) != 0 {
+		t.Fatalf("expected no resource rules, got %v", rules)
+	}
+}
+
+func TestOpsLogController_APIError(t *testing.T) {
+	controller := newTestController(t, &fakeAPIServer{})
+	controller.client.tokenFile = filepath.Join(t.TempDir(), "missing")
+	if err := controller.sync(); err == nil {
+		t.Fatalf("expected an error without a token")
+	}
+	if rules := resourceRules.Load(); rules != nil && len(*rules) != 0 {
+		t.Fatalf("rules replaced after a failed sync: %v", *rules)
+	}
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Key prefixes in the decision cache bucket
+const (
+	decisionKeyPrefix = "decision."
+	limitKeyPrefix    = "limit."
+)
+
+// Outcome of mutating a deployment, what replicas share through the
+// decision cache
+type mutation struct {
+	Result  string          `json:"result"`
+	Rule    string          `json:"rule,omitempty"`
+	Reason  string          `json:"reason,omitempty"`
+	Patch   json.RawMessage `json:"patch,omitempty"`
+	Patches int             `json:"patches,omitempty"`
+}
+
+// Decision cache and rate limiter shared by the webhook replicas, in a
+// NATS JetStream key-value bucket. Replicas answer identical admission
+// requests with the decision the first of them stored, and take turns at
+// work that would otherwise be repeated by each of them.
+type decisionCache struct {
+	kv nats.KeyValue
+}
+
+// Shared decision cache, nil without NATS_URL
+var decisions *decisionCache
+
+func newDecisionCache(nc *nats.Conn, bucket string, ttl time.Duration) (*decisionCache, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize JetStream: %w", err)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "Admission decisions of the prysm webhook",
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+	return &decisionCache{kv: kv}, nil
+}
+
+// Key of the decision for a deployment: everything the mutation depends
+// on, the rules included so replicas with different rules don't share
+func decisionKey(rules []InjectionRule, namespace string, labels map[string]string, template corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(struct {
+		Rules     []InjectionRule        `json:"rules"`
+		Namespace string                 `json:"namespace"`
+		Labels    map[string]string      `json:"labels"`
+		Template  corev1.PodTemplateSpec `json:"template"`
+	}{rules, namespace, labels, template})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return decisionKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// Return the stored decision for key, or make it with decide and store it.
+// If another replica stored one first, its decision is returned. Errored
+// decisions are not stored, the next request decides again.
+func (c *decisionCache) decide(key string, decide func() mutation) (mutation, bool) {
+	if cached, ok := c.get(key); ok {
+		return cached, true
+	}
+
+	decided := decide()
+	if decided.Result == resultErrored {
+		return decided, false
+	}
+	data, err := json.Marshal(decided)
+	if err != nil {
+		return decided, false
+	}
+	_, err = c.kv.Create(key, data)
+	if errors.Is(err, nats.ErrKeyExists) {
+		// Another replica was faster, answer like it did
+		if cached, ok := c.get(key); ok {
+			return cached, true
+		}
+		return decided, false
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to store admission decision")
+	}
+	return decided, false
+}
+
+func (c *decisionCache) get(key string) (mutation, bool) {
+	entry, err := c.kv.Get(key)
+	if err != nil {
+		if !errors.Is(err, nats.ErrKeyNotFound) {
+			log.Error().Err(err).Msg("Failed to read admission decision")
+		}
+		return mutation{}, false
+	}
+	cached := mutation{}
+	if err := json.Unmarshal(entry.Value(), &cached); err != nil {
+		log.Error().Err(err).Msgf("Failed to decode admission decision %s", key)
+		return mutation{}, false
+	}
+	return cached, true
+}
+
+// Allow an action at most once per interval across all replicas. Replicas
+// race to update the time it last ran, only the one that succeeds runs it.
+// If the bucket is unavailable every replica runs it, as without NATS.
+func (c *decisionCache) allow(name string, interval time.Duration) bool {
+	key := limitKeyPrefix + name
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	entry, err := c.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		_, err = c.kv.Create(key, []byte(now))
+		return wonRace(name, err)
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read rate limit %s", name)
+		return true
+	}
+
+	last, err := strconv.ParseInt(string(entry.Value()), 10, 64)
+	if err == nil && time.Since(time.Unix(0, last)) < interval {
+		return false
+	}
+	_, err = c.kv.Update(key, []byte(now), entry.Revision())
+	return wonRace(name, err)
+}
+
+// Whether storing the time of an action won the race against other replicas
+func wonRace(name string, err error) bool {
+	var apiErr *nats.APIError
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, nats.ErrKeyExists),
+		errors.As(err, &apiErr) && apiErr.ErrorCode == nats.JSErrCodeStreamWrongLastSequence:
+		return false
+	default:
+		log.Error().Err(err).Msgf("Failed to update rate limit %s", name)
+		return true
+	}
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testBucket = "prysm-webhook-test"
+
+// Start an embedded NATS server with JetStream, stopped with the test
+func startNATS(t *testing.T) *server.Server {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("create NATS server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("NATS server did not start")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+// Decision cache of a replica, with its own connection
+func newTestDecisionCache(t *testing.T, s *server.Server) (*decisionCache, *nats.Conn) {
+	t.Helper()
+	nc, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("connect to NATS: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	cache, err := newDecisionCache(nc, testBucket, time.Minute)
+	if err != nil {
+		t.Fatalf("open decision cache: %v", err)
+	}
+	return cache, nc
+}
+
+func TestDecisionCache_Decide(t *testing.T) {
+	cache, _ := newTestDecisionCache(t, startNATS(t))
+
+	calls := 0
+	decide := func() mutation {
+		calls++
+		return mutation{Result: resultMutated, Rule: "radosgw", Patch: []byte(`[{"op":"add"}]`), Patches: 1}
+	}
+	first, cached := cache.decide("decision.a", decide)
+	if cached || first.Result != resultMutated || calls != 1 {
+		t.Fatalf("expected a new decision, got %+v cached=%v after %d calls", first, cached, calls)
+	}
+	second, cached := cache.decide("decision.a", decide)
+	if !cached || calls != 1 {
+		t.Fatalf("expected the stored decision, got cached=%v after %d calls", cached, calls)
+	}
+	if second.Rule != first.Rule || string(second.Patch) != string(first.Patch) || second.Patches != first.Patches {
+		t.Fatalf("stored decision differs: %+v, expected %+v", second, first)
+	}
+}
+
+func TestDecisionCache_ErroredNotStored(t *testing.T) {
+	cache, _ := newTestDecisionCache(t, startNATS(t))
+
+	calls := 0
+	decide := func() mutation {
+		calls++
+		return mutation{Result: resultErrored, Reason: "invalid patch"}
+	}
+	for range 2 {
+		if m, cached := cache.decide("decision.a", decide); cached || m.Result != resultErrored {
+			t.Fatalf("unexpected decision %+v cached=%v", m, cached)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected an errored decision to be made again, decided %d times", calls)
+	}
+}
+
+func TestDecisionCache_SharedBetweenReplicas(t *testing.T) {
+	s := startNATS(t)
+	first, _ := newTestDecisionCache(t, s)
+	second, _ := newTestDecisionCache(t, s)
+
+	// The second replica decides while the first stores its decision: it
+	// answers like the first
+	stored := mutation{Result: resultMutated, Rule: "first"}
+	m, cached := second.decide("decision.a", func() mutation {
+		first.decide("decision.a", func() mutation { return stored })
+		return mutation{Result: resultMutated, Rule: "second"}
+	})
+	if !cached || m.Rule != "first" {
+		t.Fatalf("expected the decision of the first replica, got %+v cached=%v", m, cached)
+	}
+}
+
+func TestDecisionCache_Allow(t *testing.T) {
+	s := startNATS(t)
+	first, _ := newTestDecisionCache(t, s)
+	second, _ := newTestDecisionCache(t, s)
+
+	interval := 200 * time.Millisecond
+	if !first.allow("controller-sync", interval) {
+		t.Fatalf("expected the first action to be allowed")
+	}
+	if first.allow("controller-sync", interval) || second.allow("controller-sync", interval) {
+		t.Fatalf("expected no replica to run the action again within the interval")
+	}
+	if !second.allow("other", interval) {
+		t.Fatalf("expected other actions to be limited separately")
+	}
+
+	time.Sleep(interval)
+	if !second.allow("controller-sync", interval) {
+		t.Fatalf("expected the action to be allowed after the interval")
+	}
+	if first.allow("controller-sync", interval) {
+		t.Fatalf("expected the action to run once per interval")
+	}
+}
+
+func TestDecisionCache_BucketUnavailable(t *testing.T) {
+	cache, nc := newTestDecisionCache(t, startNATS(t))
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatalf("initialize JetStream: %v", err)
+	}
+	if err := js.DeleteKeyValue(testBucket); err != nil {
+		t.Fatalf("delete bucket: %v", err)
+	}
+
+	// Every replica runs rate limited actions, as without NATS
+	for range 2 {
+		if !cache.allow("controller-sync", time.Hour) {
+			t.Fatalf("expected the action to be allowed without a bucket")
+		}
+	}
+	// Requests are still answered, each deciding again
+	calls := 0
+	for range 2 {
+		m, cached := cache.decide("decision.a", func() mutation { calls++; return mutation{Result: resultSkipped} })
+		if cached || m.Result != resultSkipped {
+			t.Fatalf("unexpected decision %+v cached=%v", m, cached)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected every request to be decided, decided %d times", calls)
+	}
+}
+
+func TestMutateDeployment_CachedDecision(t *testing.T) {
+	cache, _ := newTestDecisionCache(t, startNATS(t))
+	decisions = cache
+	t.Cleanup(func() { decisions = nil })
+	useRules(t, []InjectionRule{{
+		Name:       "radosgw",
+		Namespaces: []string{"rook-ceph"},
+		Sidecar:    &corev1.Container{Image: "prysm:1"},
+	}})
+
+	deployment := deploymentWith("rook-ceph", nil, "rgw")
+	first, firstDecision := admit(t, deployment)
+	second, secondDecision := admit(t, deployment)
+	if firstDecision.cached || !secondDecision.cached {
+		t.Fatalf("expected the second request to be answered from the cache: %+v %+v", firstDecision, secondDecision)
+	}
+	if !equalJSON(first, second) {
+		t.Fatalf("cached patches differ: %v %v", first, second)
+	}
+}
+
+func TestDecisionKey(t *testing.T) {
+	rules := []InjectionRule{{Name: "radosgw", Namespaces: []string{"rook-ceph"}}}
+	template := corev1.PodTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{Name: "rgw"}}
+	key := func(rules []InjectionRule, namespace string, labels map[string]string) string {
+		t.Helper()
+		key, err := decisionKey(rules, namespace, labels, template)
+		if err != nil {
+			t.Fatalf("decision key: %v", err)
+		}
+		return key
+	}
+
+	base := key(rules, "rook-ceph", map[string]string{"app": "rgw"})
+	if key(rules, "rook-ceph", map[string]string{"app": "rgw"}) != base {
+		t.Fatalf("expected identical deployments to share a key")
+	}
+	for name, other := range map[string]string{
+		"namespace": key(rules, "other", map[string]string{"app": "rgw"}),
+		"labels":    key(rules, "rook-ceph", map[string]string{"app": "mgr"}),
+		"rules":     key([]InjectionRule{{Name: "other"}}, "rook-ceph", map[string]string{"app": "rgw"}),
+	} {
+		if other == base {
+			t.Fatalf("expected a different key for a different %s", name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Listener settings of the webhook server, from the environment
+type listenOptions struct {
+	address           string // empty listens on all IPv4 and IPv6 addresses
+	port              string
+	tls               bool // false behind a service mesh terminating TLS
+	http2             bool
+	readTimeout       time.Duration
+	readHeaderTimeout time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+}
+
+func loadListenOptions() (listenOptions, error) {
+	opts := listenOptions{
+		address: os.Getenv("WEBHOOK_BIND_ADDRESS"),
+		port:    getEnv("WEBHOOK_PORT", "8443"),
+	}
+
+	var err error
+	if opts.tls, err = envBool("WEBHOOK_TLS", true); err != nil {
+		return opts, err
+	}
+	if opts.http2, err = envBool("WEBHOOK_HTTP2", true); err != nil {
+		return opts, err
+	}
+	for _, setting := range []struct {
+		key      string
+		value    *time.Duration
+		fallback time.Duration
+	}{
+		{"WEBHOOK_READ_TIMEOUT", &opts.readTimeout, 10 * time.Second},
+		{"WEBHOOK_READ_HEADER_TIMEOUT", &opts.readHeaderTimeout, 5 * time.Second},
+		{"WEBHOOK_WRITE_TIMEOUT", &opts.writeTimeout, 10 * time.Second},
+		{"WEBHOOK_IDLE_TIMEOUT", &opts.idleTimeout, 120 * time.Second},
+	} {
+		if *setting.value, err = envDuration(setting.key, setting.fallback); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// Address to listen on, IPv6 addresses in brackets
+func (opts listenOptions) addr() string {
+	return net.JoinHostPort(opts.address, opts.port)
+}
+
+// Build the webhook server; getCertificate is unused without TLS
+func (opts listenOptions) server(handler http.Handler, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) *http.Server {
+	server := &http.Server{
+		Addr:              opts.addr(),
+		Handler:           handler,
+		ReadTimeout:       opts.readTimeout,
+		ReadHeaderTimeout: opts.readHeaderTimeout,
+		WriteTimeout:      opts.writeTimeout,
+		IdleTimeout:       opts.idleTimeout,
+	}
+	if opts.tls {
+		server.TLSConfig = &tls.Config{GetCertificate: getCertificate, MinVersion: tls.VersionTLS12}
+	}
+	if !opts.http2 {
+		// A non-nil empty map disables HTTP/2 negotiation
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	return server
+}
+
+func envBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
+
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Log levels and formats, the same as the prysm commands accept
+var logLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
+
+const (
+	logFormatJSON    = "json"
+	logFormatConsole = "console"
+)
+
+// Set up the global logger from LOG_LEVEL and LOG_FORMAT: JSON lines on
+// stdout at info level by default
+func setupLogging() error {
+	return configureLogging(os.Stdout, getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", logFormatJSON))
+}
+
+func configureLogging(out io.Writer, level, format string) error {
+	if !slices.Contains(logLevels, level) {
+		return fmt.Errorf("unknown log level %q, expected one of %s", level, strings.Join(logLevels, ", "))
+	}
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case logFormatJSON:
+	case logFormatConsole:
+		out = zerolog.ConsoleWriter{Out: out}
+	default:
+		return fmt.Errorf("unknown log format %q, expected %s or %s", format, logFormatJSON, logFormatConsole)
+	}
+
+	zerolog.SetGlobalLevel(lvl)
+	log.Logger = zerolog.New(out).With().Timestamp().Logger()
+	return nil
+}
@@ -5,32 +5,159 @@
 package main
 
 import (
+	"crypto/tls"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
-	"k8s.io/klog/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
 )
 
 func main() {
-	klog.Info("Starting webhook server...")
+	if err := setupLogging(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid logging options")
+	}
+	log.Info().Msg("Starting webhook server...")
+
+	// Load the injection rules, the default RADOSGW rule without a rules file
+	rulesFile := os.Getenv("RULES_FILE")
+	rules, err := loadRules(rulesFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load injection rules")
+	}
+	storeRules(&fileRules, rules)
+	log.Info().Msgf("Loaded %d injection rules", len(rules))
+	if rulesFile != "" {
+		go watchRules(rulesFile, rulesReloadInterval)
+	}
+
+	// Share decisions and rate limits with the other replicas through NATS
+	if natsURL := os.Getenv("NATS_URL"); natsURL != "" {
+		ttl, err := envDuration("DECISION_CACHE_TTL", 10*time.Minute)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid decision cache options")
+		}
+		nc, err := nats.Connect(natsURL, nats.MaxReconnects(-1))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to NATS")
+		}
+		bucket := getEnv("DECISION_CACHE_BUCKET", "prysm-webhook-decisions")
+		decisions, err = newDecisionCache(nc, bucket, ttl)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to open decision cache")
+		}
+		log.Info().Msgf("Sharing admission decisions in key-value bucket %s", bucket)
+	}
+
+	// Reconcile PrysmOpsLog resources. The first sync completes before
+	// serving, otherwise deployments they select would lose their sidecar.
+	if enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_CONTROLLER")); enabled {
+		client, err := newInClusterClient()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to start controller")
+		}
+		controller := &opsLogController{client: client}
+		if err := controller.sync(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to reconcile PrysmOpsLog resources")
+		}
+		go func() {
+			time.Sleep(controllerSyncInterval)
+			controller.run(controllerSyncInterval)
+		}()
+	}
 
 	r := mux.NewRouter()
-	r.HandleFunc("/mutate", mutateHandler)
+	r.HandleFunc("/mutate", admissionHandler(mutateDeployment))
+	r.HandleFunc("/validate", admissionHandler(validateConfigMap))
+	r.HandleFunc("/healthz", healthzHandler)
 
-	// Start the HTTP server
-	port := os.Getenv("WEBHOOK_PORT")
-	if port == "" {
-		port = "8443" // Default webhook server port
+	opts, err := loadListenOptions()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid listen options")
 	}
 
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: r,
+	// Serve metrics over plain HTTP, scrapers need no webhook CA
+	metricsPort := getEnv("METRICS_PORT", "8080")
+	metricsRouter := mux.NewRouter()
+	metricsRouter.Handle("/metrics", metrics)
+	metricsRouter.HandleFunc("/healthz", healthzHandler)
+	go func() {
+		if err := http.ListenAndServe(net.JoinHostPort(opts.address, metricsPort), metricsRouter); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start metrics server")
+		}
+	}()
+
+	// Start the HTTP server, plain HTTP if TLS is terminated in front of it
+	if !opts.tls {
+		log.Info().Msgf("Serving plain HTTP on %s", opts.addr())
+		if err := opts.server(r, nil).ListenAndServe(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start webhook")
+		}
+		return
+	}
+
+	getCertificate, err := serverCertificate()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up certificate")
 	}
 
-	err := server.ListenAndServeTLS("/certs/tls.crt", "/certs/tls.key")
+	log.Info().Msgf("Serving HTTPS on %s", opts.addr())
+	err = opts.server(r, getCertificate).ListenAndServeTLS("", "")
 	if err != nil {
-		klog.Fatalf("Failed to start webhook: %v", err)
+		log.Fatal().Err(err).Msg("Failed to start webhook")
+	}
+}
+
+// Set up the serving certificate: self-provisioned if SELF_PROVISION_CERTS
+// is set, otherwise read from the certificate files and reloaded on change
+func serverCertificate() (func(*tls.ClientHelloInfo) (*tls.Certificate, error), error) {
+	selfProvision, _ := strconv.ParseBool(os.Getenv("SELF_PROVISION_CERTS"))
+	if !selfProvision {
+		certFile := getEnv("TLS_CERT_FILE", "/certs/tls.crt")
+		keyFile := getEnv("TLS_KEY_FILE", "/certs/tls.key")
+		reloader, err := newCertReloader(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		go reloader.watch(certReloadInterval)
+		return reloader.GetCertificate, nil
+	}
+
+	service := getEnv("WEBHOOK_SERVICE_NAME", "prysm-webhook-service")
+	namespace := os.Getenv("WEBHOOK_NAMESPACE")
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, err
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	certs, err := newSelfSignedCerts([]string{
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc.cluster.local",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configName := getEnv("WEBHOOK_CONFIG_NAME", "prysm-webhook")
+	if err := patchCABundle(configName, certs.caPEM); err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Self-provisioned certificate for %v, patched caBundle of %s", certs.dnsNames, configName)
+
+	go certs.renew(certReloadInterval)
+	return certs.GetCertificate, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return defaultValue
 }
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Admission results, the values of the result label: mutated and skipped
+// for /mutate, allowed and denied for /validate
+const (
+	resultMutated = "mutated"
+	resultSkipped = "skipped"
+	resultAllowed = "allowed"
+	resultDenied  = "denied"
+	resultErrored = "errored"
+)
+
+var admissionResults = []string{resultMutated, resultSkipped, resultAllowed, resultDenied, resultErrored}
+
+// Upper bounds of the admission duration histogram, in seconds
+var admissionDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Admission metrics in the Prometheus text format; the webhook has few
+// enough metrics to do without the client library
+type admissionMetrics struct {
+	mu       sync.Mutex
+	results  map[string]uint64
+	buckets  []uint64 // observations per bucket, not cumulative
+	sum      float64
+	observed uint64
+}
+
+var metrics = newAdmissionMetrics()
+
+func newAdmissionMetrics() *admissionMetrics {
+	return &admissionMetrics{
+		results: map[string]uint64{},
+		buckets: make([]uint64, len(admissionDurationBuckets)),
+	}
+}
+
+// Count an admission request and its duration
+func (m *admissionMetrics) observe(result string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results[result]++
+	seconds := duration.Seconds()
+	for i, bound := range admissionDurationBuckets {
+		if seconds <= bound {
+			m.buckets[i]++
+			break
+		}
+	}
+	m.sum += seconds
+	m.observed++
+}
+
+// Serve the metrics on /metrics
+func (m *admissionMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP prysm_webhook_admissions_total Admission requests by result.")
+	fmt.Fprintln(w, "# TYPE prysm_webhook_admissions_total counter")
+	for _, result := range admissionResults {
+		fmt.Fprintf(w, "prysm_webhook_admissions_total{result=%q} %d\n", result, m.results[result])
+	}
+
+	fmt.Fprintln(w, "# HELP prysm_webhook_admission_duration_seconds Time to handle an admission request.")
+	fmt.Fprintln(w, "# TYPE prysm_webhook_admission_duration_seconds histogram")
+	cumulative := uint64(0)
+	for i, bound := range admissionDurationBuckets {
+		cumulative += m.buckets[i]
+		fmt.Fprintf(w, "prysm_webhook_admission_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "prysm_webhook_admission_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.observed)
+	fmt.Fprintf(w, "prysm_webhook_admission_duration_seconds_sum %g\n", m.sum)
+	fmt.Fprintf(w, "prysm_webhook_admission_duration_seconds_count %d\n", m.observed)
+}
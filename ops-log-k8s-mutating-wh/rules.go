@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// How often the rules file is checked for changes (ConfigMap updates)
+const rulesReloadInterval = 30 * time.Second
+
+// InjectionRule selects deployments by namespace and labels and describes
+// what is injected into their pod template
+type InjectionRule struct {
+	Name string `json:"name"`
+	// Namespace names, may contain wildcards; all namespaces if empty
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selector on the deployment labels; all deployments if unset
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Sidecar to inject, fields left empty are taken from the default sidecar
+	Sidecar *corev1.Container `json:"sidecar,omitempty"`
+	// Env is injected into the containers named in Containers, or all
+	// containers except the sidecar if Containers is empty
+	Env        []corev1.EnvVar `json:"env,omitempty"`
+	Containers []string        `json:"containers,omitempty"`
+	// Wire the ops log through a socket, as the ops-log-socket annotation
+	OpsLogSocket bool `json:"opsLogSocket,omitempty"`
+	// Name of the RGW container, "rgw" if empty
+	RGWContainer string `json:"rgwContainer,omitempty"`
+
+	selector labels.Selector
+}
+
+// RuleSet is the content of the rules file
+type RuleSet struct {
+	Rules []InjectionRule `json:"rules"`
+}
+
+// Rules from the rules file and from PrysmOpsLog resources; the active rules
+// combine both, resources first
+var (
+	fileRules     atomic.Pointer[[]InjectionRule]
+	resourceRules atomic.Pointer[[]InjectionRule]
+	activeRules   atomic.Pointer[[]InjectionRule]
+	rulesMu       sync.Mutex
+)
+
+// Replace the file or resource rules and recombine the active rules
+func storeRules(target *atomic.Pointer[[]InjectionRule], rules []InjectionRule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	target.Store(&rules)
+	combined := []InjectionRule{}
+	for _, source := range []*atomic.Pointer[[]InjectionRule]{&resourceRules, &fileRules} {
+		if sourceRules := source.Load(); sourceRules != nil {
+			combined = append(combined, *sourceRules...)
+		}
+	}
+	activeRules.Store(&combined)
+}
+
+// Default rule: inject the sidecar into Rook RADOSGW deployments labeled
+// prysm-sidecar: "yes"
+func defaultRules() []InjectionRule {
+	matchLabels := map[string]string{sidecarPolicyLabel: "yes"}
+	for key, value := range rookRGWLabels {
+		matchLabels[key] = value
+	}
+	return []InjectionRule{{
+		Name:     "radosgw",
+		Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		Sidecar:  &corev1.Container{},
+	}}
+}
+
+// Load the rules from a JSON file, or the default rule if path is empty
+func loadRules(rulesPath string) ([]InjectionRule, error) {
+	if rulesPath == "" {
+		rules := defaultRules()
+		if err := prepareRules(rules); err != nil {
+			return nil, err
+		}
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(rulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	ruleSet := RuleSet{}
+	if err := json.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", rulesPath, err)
+	}
+	if err := prepareRules(ruleSet.Rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", rulesPath, err)
+	}
+	return ruleSet.Rules, nil
+}
+
+// Validate the rules, compile their selectors and complete their sidecars
+func prepareRules(rules []InjectionRule) error {
+	names := map[string]bool{}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if len(rule.Namespaces) == 0 && rule.Selector == nil {
+			return fmt.Errorf("rule %s selects every deployment, set namespaces or a selector", rule.Name)
+		}
+		if rule.Sidecar == nil && len(rule.Env) == 0 {
+			return fmt.Errorf("rule %s injects neither a sidecar nor env", rule.Name)
+		}
+		for _, namespace := range rule.Namespaces {
+			if _, err := path.Match(namespace, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid namespace pattern %q: %w", rule.Name, namespace, err)
+			}
+		}
+
+		rule.selector = labels.Everything()
+		if rule.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rule.Selector)
+			if err != nil {
+				return fmt.Errorf("rule %s: invalid selector: %w", rule.Name, err)
+			}
+			rule.selector = selector
+		}
+
+		if rule.Sidecar != nil {
+			rule.Sidecar = completeSidecar(*rule.Sidecar)
+			if rule.Sidecar.Image == "" {
+				return fmt.Errorf("rule %s: no sidecar image, set it in the rule or SIDECAR_IMAGE", rule.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Fill the empty fields of a rule's sidecar from the default sidecar
+func completeSidecar(sidecar corev1.Container) *corev1.Container {
+	defaults := sidecarContainer.DeepCopy()
+	if sidecar.Name == "" {
+		sidecar.Name = defaults.Name
+	}
+	if sidecar.Image == "" {
+		sidecar.Image = defaults.Image
+	}
+	if len(sidecar.Command) == 0 && len(sidecar.Args) == 0 {
+		sidecar.Args = defaults.Args
+	}
+	if len(sidecar.Ports) == 0 {
+		sidecar.Ports = defaults.Ports
+	}
+	if len(sidecar.VolumeMounts) == 0 {
+		sidecar.VolumeMounts = defaults.VolumeMounts
+	}
+	if len(sidecar.Env) == 0 {
+		sidecar.Env = defaults.Env
+	}
+	return &sidecar
+}
+
+// Check if the rule applies to a deployment in the namespace
+func (rule *InjectionRule) matches(namespace string, deploymentLabels map[string]string) bool {
+	if len(rule.Namespaces) > 0 {
+		found := false
+		for _, pattern := range rule.Namespaces {
+			if ok, _ := path.Match(pattern, namespace); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return rule.selector.Matches(labels.Set(deploymentLabels))
+}
+
+// Find the first active rule matching a deployment
+func matchRule(namespace string, deploymentLabels map[string]string) *InjectionRule {
+	rules := activeRules.Load()
+	if rules == nil {
+		return nil
+	}
+	for i := range *rules {
+		if (*rules)[i].matches(namespace, deploymentLabels) {
+			return &(*rules)[i]
+		}
+	}
+	return nil
+}
+
+// Reload the rules file when it changes; invalid rules are logged and the
+// previous rules are kept
+func watchRules(rulesPath string, interval time.Duration) {
+	lastModified := time.Time{}
+	if info, err := os.Stat(rulesPath); err == nil {
+		lastModified = info.ModTime()
+	}
+
+	for range time.Tick(interval) {
+		info, err := os.Stat(rulesPath)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to check rules file")
+			continue
+		}
+		if info.ModTime().Equal(lastModified) {
+			continue
+		}
+		lastModified = info.ModTime()
+
+		rules, err := loadRules(rulesPath)
+		if err != nil {
+			log.Error().Err(err).Msg("Keeping previous injection rules")
+			continue
+		}
+		storeRules(&fileRules, rules)
+		log.Info().Msgf("Reloaded %d injection rules from %s", len(rules), rulesPath)
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod template annotations wiring the RGW ops log to the sidecar through a
+// Unix socket instead of the log file, and naming the RGW container
+const (
+	opsLogSocketAnnotation = "prysm-sidecar/ops-log-socket"
+	rgwContainerAnnotation = "prysm-sidecar/rgw-container"
+)
+
+// Shared emptyDir holding the ops-log socket
+const (
+	opsLogSocketVolume  = "prysm-ops-log-socket"
+	opsLogSocketDir     = "/var/run/prysm"
+	opsLogSocketPath    = opsLogSocketDir + "/ops-log.sock"
+	defaultRGWContainer = "rgw" // as named by Rook
+)
+
+// RGW options sending the ops log to the socket, passed in CEPH_ARGS which
+// Ceph daemons append to their command line
+var rgwSocketArgs = []string{
+	"--rgw_enable_ops_log=true",
+	"--rgw_ops_log_socket_path=" + opsLogSocketPath,
+}
+
+// Check if the pod template asks for the ops-log socket
+func opsLogSocketEnabled(annotations map[string]string) bool {
+	enabled, _ := strconv.ParseBool(annotations[opsLogSocketAnnotation])
+	return enabled
+}
+
+// Switch the sidecar from reading the log file to listening on the socket
+func socketSidecar(sidecar *corev1.Container) {
+	args := []string{}
+	for _, arg := range sidecar.Args {
+		if strings.HasPrefix(arg, "--log-file=") ||
+			strings.HasPrefix(arg, "--max-log-file-size=") ||
+			strings.HasPrefix(arg, "--socket-path=") {
+			continue
+		}
+		args = append(args, arg)
+	}
+	sidecar.Args = append(args, "--socket-path="+opsLogSocketPath)
+	sidecar.VolumeMounts = socketVolumeMounts(sidecar.VolumeMounts, true)
+}
+
+// Mount the socket volume into the RGW container and point RGW at the
+// socket, or undo both
+func wireRGWContainer(container *corev1.Container, socket bool) {
+	container.VolumeMounts = socketVolumeMounts(container.VolumeMounts, socket)
+
+	index := slices.IndexFunc(container.Env, func(env corev1.EnvVar) bool { return env.Name == "CEPH_ARGS" })
+	if index >= 0 && container.Env[index].ValueFrom != nil {
+		log.Warn().Msgf("Not wiring container %s, CEPH_ARGS is set from a reference", container.Name)
+		return
+	}
+
+	var args []string
+	wired := false
+	if index >= 0 {
+		for _, arg := range strings.Fields(container.Env[index].Value) {
+			if slices.Contains(rgwSocketArgs, arg) {
+				wired = true
+				continue
+			}
+			args = append(args, arg)
+		}
+	}
+	if !socket && !wired {
+		return // leave CEPH_ARGS as it is
+	}
+	if socket {
+		args = append(args, rgwSocketArgs...)
+	}
+
+	switch {
+	case len(args) == 0:
+		container.Env = slices.Delete(container.Env, index, index+1)
+	case index >= 0:
+		container.Env[index].Value = strings.Join(args, " ")
+	default:
+		container.Env = append(container.Env, corev1.EnvVar{Name: "CEPH_ARGS", Value: strings.Join(args, " ")})
+	}
+}
+
+// Add or remove the socket volume mount
+func socketVolumeMounts(mounts []corev1.VolumeMount, socket bool) []corev1.VolumeMount {
+	mounts = slices.DeleteFunc(mounts, func(mount corev1.VolumeMount) bool { return mount.Name == opsLogSocketVolume })
+	if socket {
+		mounts = append(mounts, corev1.VolumeMount{Name: opsLogSocketVolume, MountPath: opsLogSocketDir})
+	}
+	return mounts
+}
+
+// Add or remove the socket volume
+func socketVolumes(volumes []corev1.Volume, socket bool) []corev1.Volume {
+	volumes = slices.DeleteFunc(volumes, func(volume corev1.Volume) bool { return volume.Name == opsLogSocketVolume })
+	if socket {
+		volumes = append(volumes, corev1.Volume{
+			Name:         opsLogSocketVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+	return volumes
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func cephArgs(container corev1.Container) string {
+	for _, env := range container.Env {
+		if env.Name == "CEPH_ARGS" {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+func hasSocketMount(container corev1.Container) bool {
+	return slices.ContainsFunc(container.VolumeMounts, func(mount corev1.VolumeMount) bool {
+		return mount.Name == opsLogSocketVolume && mount.MountPath == opsLogSocketDir
+	})
+}
+
+func TestSocketSidecar(t *testing.T) {
+	sidecar := sidecarContainer.DeepCopy()
+	sidecar.Args = append(sidecar.Args, "--socket-path=/old.sock")
+	socketSidecar(sidecar)
+
+	for _, arg := range sidecar.Args {
+		if strings.HasPrefix(arg, "--log-file=") || strings.HasPrefix(arg, "--max-log-file-size=") || arg == "--socket-path=/old.sock" {
+			t.Fatalf("argument %s left in %v", arg, sidecar.Args)
+		}
+	}
+	if last := sidecar.Args[len(sidecar.Args)-1]; last != "--socket-path="+opsLogSocketPath {
+		t.Fatalf("expected the socket path, got %v", sidecar.Args)
+	}
+	if !hasSocketMount(*sidecar) {
+		t.Fatalf("socket volume not mounted: %v", sidecar.VolumeMounts)
+	}
+
+	// Idempotent on a sidecar wired before
+	args := strings.Join(sidecar.Args, " ")
+	socketSidecar(sidecar)
+	if strings.Join(sidecar.Args, " ") != args || len(sidecar.VolumeMounts) != len(sidecarContainer.VolumeMounts)+1 {
+		t.Fatalf("sidecar changed when wired again: %v %v", sidecar.Args, sidecar.VolumeMounts)
+	}
+}
+
+func TestWireRGWContainer(t *testing.T) {
+	wired := strings.Join(rgwSocketArgs, " ")
+	tests := []struct {
+		name   string
+		env    []corev1.EnvVar
+		socket bool
+		args   string
+		mount  bool
+	}{
+		{"wire", nil, true, wired, true},
+		{"keep other args", []corev1.EnvVar{{Name: "CEPH_ARGS", Value: "--debug-rgw=1"}}, true, "--debug-rgw=1 " + wired, true},
+		{"wired before", []corev1.EnvVar{{Name: "CEPH_ARGS", Value: wired}}, true, wired, true},
+		{"unwire", []corev1.EnvVar{{Name: "CEPH_ARGS", Value: "--debug-rgw=1 " + wired}}, false, "--debug-rgw=1", false},
+		{"unwire only args", []corev1.EnvVar{{Name: "CEPH_ARGS", Value: wired}}, false, "", false},
+		{"never wired", []corev1.EnvVar{{Name: "CEPH_ARGS", Value: "--debug-rgw=1"}}, false, "--debug-rgw=1", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			container := corev1.Container{Name: "rgw", Env: test.env}
+			wireRGWContainer(&container, test.socket)
+			if args := cephArgs(container); args != test.args {
+				t.Fatalf("expected CEPH_ARGS %q, got %q", test.args, args)
+			}
+			if hasSocketMount(container) != test.mount {
+				t.Fatalf("unexpected volume mounts %v", container.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestWireRGWContainer_CephArgsFromReference(t *testing.T) {
+	reference := corev1.EnvVar{Name: "CEPH_ARGS", ValueFrom: &corev1.EnvVarSource{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "args"},
+	}}
+	container := corev1.Container{Name: "rgw", Env: []corev1.EnvVar{reference}}
+	wireRGWContainer(&container, true)
+	if len(container.Env) != 1 || container.Env[0].ValueFrom == nil || container.Env[0].Value != "" {
+		t.Fatalf("CEPH_ARGS from a reference changed: %+v", container.Env)
+	}
+}
+
+func TestSocketVolumes(t *testing.T) {
+	volumes := socketVolumes([]corev1.Volume{{Name: "data"}}, true)
+	if len(volumes) != 2 || volumes[1].Name != opsLogSocketVolume || volumes[1].EmptyDir == nil {
+		t.Fatalf("socket volume not added: %v", volumes)
+	}
+	if volumes = socketVolumes(volumes, true); len(volumes) != 2 {
+		t.Fatalf("socket volume added twice: %v", volumes)
+	}
+	if volumes = socketVolumes(volumes, false); len(volumes) != 1 || volumes[0].Name != "data" {
+		t.Fatalf("socket volume not removed: %v", volumes)
+	}
+}
+
+func TestInjectPodSpec_Socket(t *testing.T) {
+	useRules(t, []InjectionRule{{
+		Name:       "radosgw",
+		Namespaces: []string{"rook-ceph"},
+		Sidecar:    &corev1.Container{Image: "prysm:1"},
+	}})
+	rule := matchRule("rook-ceph", nil)
+
+	annotations := map[string]string{opsLogSocketAnnotation: "true"}
+	deployment := deploymentWith("rook-ceph", annotations, defaultRGWContainer)
+	spec := injectPodSpec(rule, deployment.Spec.Template, injectedSidecar(deployment))
+	if names := containerNames(spec); len(names) != 2 || names[1] != sidecarContainer.Name {
+		t.Fatalf("unexpected containers %v", names)
+	}
+	rgw, sidecar := spec.Containers[0], spec.Containers[1]
+	if cephArgs(rgw) != strings.Join(rgwSocketArgs, " ") || !hasSocketMount(rgw) {
+		t.Fatalf("RGW container not wired: %+v", rgw)
+	}
+	if !slices.Contains(sidecar.Args, "--socket-path="+opsLogSocketPath) || !hasSocketMount(sidecar) {
+		t.Fatalf("sidecar not wired: %+v", sidecar)
+	}
+	if len(spec.Volumes) != 1 || spec.Volumes[0].Name != opsLogSocketVolume {
+		t.Fatalf("socket volume missing: %v", spec.Volumes)
+	}
+
+	// The rule no longer matches: the sidecar and the wiring are removed
+	deployment.Spec.Template.Annotations = injectedAnnotations(rule, annotations)
+	deployment.Spec.Template.Spec = spec
+	spec = injectPodSpec(nil, deployment.Spec.Template, injectedSidecar(deployment))
+	if names := containerNames(spec); len(names) != 1 || names[0] != defaultRGWContainer {
+		t.Fatalf("unexpected containers %v", names)
+	}
+	if cephArgs(spec.Containers[0]) != "" || hasSocketMount(spec.Containers[0]) || len(spec.Volumes) != 0 {
+		t.Fatalf("socket not unwired: %+v %v", spec.Containers[0], spec.Volumes)
+	}
+}
+
+func TestInjectPodSpec_RGWContainerAnnotation(t *testing.T) {
+	useRules(t, []InjectionRule{{
+		Name:       "radosgw",
+		Namespaces: []string{"rook-ceph"},
+		Sidecar:    &corev1.Container{Image: "prysm:1"},
+	}})
+
+	annotations := map[string]string{opsLogSocketAnnotation: "true", rgwContainerAnnotation: "gateway"}
+	deployment := deploymentWith("rook-ceph", annotations, defaultRGWContainer, "gateway")
+	spec := injectPodSpec(matchRule("rook-ceph", nil), deployment.Spec.Template, "")
+	if cephArgs(spec.Containers[0]) != "" || cephArgs(spec.Containers[1]) == "" {
+		t.Fatalf("expected only the annotated container to be wired: %+v", spec.Containers)
+	}
+}
@@ -0,0 +1,522 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label marking a ConfigMap as prysm configuration, its value names the
+// producer the ConfigMap configures through environment variables
+const configLabel = "prysm.cobaltcore.dev/config"
+
+// Environment variables a producer reads, by type, and the checks across
+// them. The producers ignore values that do not parse, which silently falls
+// back to the flag defaults, so those are rejected here.
+type configSchema struct {
+	bools   []string
+	ints    []string
+	strings []string
+	check   func(cfg producerConfig, result *configValidation)
+}
+
+// Environment variables every prysm subcommand reads: logging, metrics TLS
+// and NATS connection settings
+var commonConfigSchema = configSchema{
+	bools: []string{"METRICS_LEGACY_NAMES", "SELF_LIMIT"},
+	ints:  []string{"DEBUG_PORT", "NATS_SPOOL_MAX_SIZE", "METRICS_RATE_BURST", "MEMORY_LIMIT", "MEMORY_LIMIT_PERCENT"},
+	strings: []string{
+		"LOG_LEVEL", "LOG_FORMAT", "LOG_DEDUP_WINDOW", "METRICS_TLS_CERT", "METRICS_TLS_KEY", "METRICS_PREFIX",
+		"METRICS_BASIC_AUTH_USERNAME", "METRICS_BASIC_AUTH_PASSWORD", "METRICS_BEARER_TOKEN", "METRICS_RATE_LIMIT",
+		"NATS_CREDS", "NATS_TLS_CA", "NATS_TLS_CERT", "NATS_TLS_KEY", "CPU_LIMIT",
+		"NATS_SPOOL_DIR", "NATS_SPOOL_MAX_AGE", "DEBUG_ADDRESS",
+		"VAULT_ADDR", "VAULT_TOKEN", "VAULT_TOKEN_FILE", "VAULT_KUBERNETES_ROLE", "VAULT_KUBERNETES_MOUNT", "VAULT_NAMESPACE", "VAULT_CACERT",
+	},
+}
+
+// Classes of the NATS routes of ops-log, as validated by the producer
+var natsRouteClasses = []string{"health_check", "read", "write", "error", "all"}
+
+// Prefix of the metric names, as validated by the producers
+var metricPrefixPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*_$`)
+
+var configSchemas = map[string]configSchema{
+	"ops-log": {
+		bools: []string{
+			"PROMETHEUS_ENABLED", "TRUNCATE_LOG_ON_START", "LOG_TO_STDOUT", "LOG_PRETTY_PRINT", "IGNORE_ANONYMOUS_REQUESTS",
+			"NATS_TENANT_SUBJECTS", "NATS_SECURITY_EVENTS", "NATS_SLOW_REQUESTS",
+			"TRACK_EVERYTHING", "TRACK_BUCKET_SLO", "TRACK_SECURITY", "TRACK_BUCKET_CONCURRENCY", "TRACK_AUTH_METHODS",
+			"TRACK_HEALTH_CHECKS",
+			"TRACK_REQUESTS_DETAILED", "TRACK_REQUESTS_PER_USER", "TRACK_REQUESTS_PER_BUCKET", "TRACK_REQUESTS_PER_TENANT",
+			"TRACK_REQUESTS_BY_METHOD_DETAILED", "TRACK_REQUESTS_BY_METHOD_PER_USER", "TRACK_REQUESTS_BY_METHOD_PER_BUCKET",
+			"TRACK_REQUESTS_BY_METHOD_PER_TENANT", "TRACK_REQUESTS_BY_METHOD_GLOBAL",
+			"TRACK_REQUESTS_BY_OPERATION_DETAILED", "TRACK_REQUESTS_BY_OPERATION_PER_USER", "TRACK_REQUESTS_BY_OPERATION_PER_BUCKET",
+			"TRACK_REQUESTS_BY_OPERATION_PER_TENANT", "TRACK_REQUESTS_BY_OPERATION_GLOBAL",
+			"TRACK_REQUESTS_BY_STATUS_DETAILED", "TRACK_REQUESTS_BY_STATUS_PER_USER", "TRACK_REQUESTS_BY_STATUS_PER_BUCKET",
+			"TRACK_REQUESTS_BY_STATUS_PER_TENANT",
+			"TRACK_BYTES_SENT_DETAILED", "TRACK_BYTES_SENT_PER_USER", "TRACK_BYTES_SENT_PER_BUCKET", "TRACK_BYTES_SENT_PER_TENANT",
+			"TRACK_BYTES_RECEIVED_DETAILED", "TRACK_BYTES_RECEIVED_PER_USER", "TRACK_BYTES_RECEIVED_PER_BUCKET",
+			"TRACK_BYTES_RECEIVED_PER_TENANT",
+			"TRACK_ERRORS_DETAILED", "TRACK_ERRORS_PER_USER", "TRACK_ERRORS_PER_BUCKET", "TRACK_ERRORS_PER_TENANT",
+			"TRACK_ERRORS_PER_STATUS", "TRACK_ERRORS_BY_IP", "TRACK_TIMEOUT_ERRORS", "TRACK_ERRORS_BY_CATEGORY",
+			"TRACK_REQUESTS_BY_IP_DETAILED", "TRACK_REQUESTS_BY_IP_PER_TENANT", "TRACK_REQUESTS_BY_IP_BUCKET_METHOD_TENANT",
+			"TRACK_REQUESTS_BY_IP_GLOBAL_PER_TENANT",
+			"TRACK_BYTES_SENT_BY_IP_DETAILED", "TRACK_BYTES_SENT_BY_IP_PER_TENANT", "TRACK_BYTES_SENT_BY_IP_GLOBAL_PER_TENANT",
+			"TRACK_BYTES_RECEIVED_BY_IP_DETAILED", "TRACK_BYTES_RECEIVED_BY_IP_PER_TENANT",
+			"TRACK_BYTES_RECEIVED_BY_IP_GLOBAL_PER_TENANT",
+			"TRACK_LATENCY_DETAILED", "TRACK_LATENCY_PER_USER", "TRACK_LATENCY_PER_BUCKET", "TRACK_LATENCY_PER_TENANT",
+			"TRACK_LATENCY_PER_METHOD", "TRACK_LATENCY_PER_BUCKET_AND_METHOD", "TRACK_API_CATEGORIES",
+			"AUDIT_ENABLED", "AUDIT_REQUIRE_TENANT", "AUDIT_INCLUDE_READS", "AUDIT_DEBUG",
+		},
+		ints: []string{"LOG_RETENTION_DAYS", "PROMETHEUS_PORT", "PROMETHEUS_INTERVAL", "AUDIT_QUEUE_SIZE", "MAX_LOG_FILE_SIZE", "REMOTE_WRITE_INTERVAL",
+			"LOKI_BATCH_SIZE", "LOKI_BATCH_WAIT", "LOKI_QUEUE_SIZE", "BACKPRESSURE_QUEUE_SIZE",
+			"HEALTH_CHECK_MIN_PER_MINUTE", "HEALTH_CHECK_TOP", "SLOW_REQUESTS_BUFFER_SIZE"},
+		strings: []string{
+			"LOG_FILE_PATH", "SOCKET_PATH", "JOURNALD_UNIT", "JOURNALD_CURSOR_FILE", "NATS_URL", "NATS_SUBJECT", "NATS_METRICS_SUBJECT", "NATS_SECURITY_SUBJECT", "NATS_ROUTES", "POD_NAME", "INSTANCE_ID", "NODE_NAME",
+			"AUDIT_RABBITMQ_URL", "AUDIT_RABBITMQ_USERNAME", "AUDIT_RABBITMQ_PASSWORD", "AUDIT_QUEUE_NAME",
+			"AUDIT_REGION", "AUDIT_OBSERVER_NAME", "AUDIT_SKIP_BUCKETS", "AUDIT_ALLOW_DOMAINS", "AUDIT_DENY_DOMAINS",
+			"REMOTE_WRITE_URL", "REMOTE_WRITE_EXTERNAL_LABELS", "REMOTE_WRITE_HEADERS",
+			"LOKI_URL", "LOKI_TENANT", "LOKI_LABELS",
+			"KEYSTONE_URL", "KEYSTONE_APPLICATION_CREDENTIAL_ID", "KEYSTONE_APPLICATION_CREDENTIAL_SECRET", "KEYSTONE_CACHE_TTL",
+			"IP_INTERNAL_CIDRS", "IP_CROSS_REGION_CIDRS", "BUCKET_TAGS_KV", "BUCKET_TAGS",
+			"REPLICATION_USERS", "REPLICATION_CIDRS", "COST_PRICES",
+			"BACKPRESSURE_HEALTH_CHECK_USER_AGENTS", "BACKPRESSURE_HEALTH_CHECK_CIDRS",
+			"SLOW_REQUEST_THRESHOLD", "SLOW_REQUEST_BUCKET_THRESHOLDS", "NATS_SLOW_REQUESTS_SUBJECT",
+		},
+		check: checkOpsLogConfig,
+	},
+	"radosgw-usage": {
+		bools: []string{"PROMETHEUS_ENABLED", "SYNC_CONTROL_NATS", "SYNC_EXTERNAL_NATS", "RESHARD_NOTIFY", "AUDIT_BUCKET_ACCESS", "PUBLIC_BUCKET_NOTIFY", "TENANT_ANOMALY_NOTIFY", "DRY_RUN"},
+		ints: []string{"PROMETHEUS_PORT", "COOLDOWN_INTERVAL", "RESHARD_OBJECTS_PER_SHARD", "LARGE_OMAP_KEYS_PER_SHARD", "REMOTE_WRITE_INTERVAL", "KV_WRITE_WORKERS",
+			"TENANT_ANOMALY_HISTORY", "TENANT_ANOMALY_GROWTH_FACTOR", "TENANT_ANOMALY_DELETION_PERCENT", "TENANT_ANOMALY_MIN_GIB",
+			"COMPLETENESS_PERCENT"},
+		strings: []string{
+			"RGW_USAGE_SOURCE", "ADMIN_URL", "ACCESS_KEY", "SECRET_KEY", "NODE_NAME", "INSTANCE_ID", "RGW_CLUSTER_ID",
+			"RADOSGW_ADMIN_BINARY", "CEPH_CONF", "CEPH_NAME", "CEPH_KEYRING",
+			"SYNC_CONTROL_URL", "SYNC_CONTROL_BUCKET_PREFIX", "KV_TTL", "KV_COMPACT_AGE", "KV_COMPACT_INTERVAL",
+			"REMOTE_WRITE_URL", "REMOTE_WRITE_EXTERNAL_LABELS", "REMOTE_WRITE_HEADERS",
+		},
+		check: checkRadosGWUsageConfig,
+	},
+}
+
+// Parsed values of a ConfigMap; unset keys keep the producer's flag value
+type producerConfig struct {
+	bools   map[string]bool
+	ints    map[string]int64
+	strings map[string]string
+}
+
+func (cfg producerConfig) isTrue(key string) bool {
+	return cfg.bools[key]
+}
+
+func (cfg producerConfig) isFalse(key string) bool {
+	value, ok := cfg.bools[key]
+	return ok && !value
+}
+
+// Rejections and warnings for a ConfigMap
+type configValidation struct {
+	errors   []string
+	warnings []string
+}
+
+func (v *configValidation) errorf(format string, args ...any) {
+	v.errors = append(v.errors, fmt.Sprintf(format, args...))
+}
+
+func (v *configValidation) warnf(format string, args ...any) {
+	v.warnings = append(v.warnings, fmt.Sprintf(format, args...))
+}
+
+// Validate prysm configuration ConfigMaps, rejecting values the producers
+// would ignore or refuse to start with
+func validateConfigMap(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, admissionDecision) {
+	if req.Kind.Kind != "ConfigMap" || req.Operation == admissionv1.Delete {
+		return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID},
+			admissionDecision{result: resultAllowed, name: req.Name, reason: "not validated"}
+	}
+
+	configMap := corev1.ConfigMap{}
+	if err := json.Unmarshal(req.Object.Raw, &configMap); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal ConfigMap")
+		return &admissionv1.AdmissionResponse{Allowed: false, UID: req.UID},
+			admissionDecision{result: resultErrored, name: req.Name, reason: "invalid configmap"}
+	}
+	decision := admissionDecision{name: configMap.Name}
+
+	// Without the label, e.g. if the webhook configuration lacks the object
+	// selector, the ConfigMap is none of prysm's business
+	producer, labeled := configMap.Labels[configLabel]
+	if !labeled {
+		decision.result = resultAllowed
+		decision.reason = "not validated"
+		return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID}, decision
+	}
+	schema, ok := configSchemas[producer]
+	if !ok {
+		decision.result = resultDenied
+		decision.reason = fmt.Sprintf("unknown producer %q", producer)
+		return deny(req, fmt.Sprintf("label %s must be one of %s, not %q", configLabel, strings.Join(configProducers(), ", "), producer)), decision
+	}
+
+	result := validateProducerConfig(schema, configMap.Data)
+	if len(result.errors) > 0 {
+		decision.result = resultDenied
+		decision.reason = strings.Join(result.errors, "; ")
+		resp := deny(req, fmt.Sprintf("invalid %s configuration: %s", producer, decision.reason))
+		resp.Warnings = result.warnings
+		return resp, decision
+	}
+
+	decision.result = resultAllowed
+	return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID, Warnings: result.warnings}, decision
+}
+
+// Parse the ConfigMap data by the schema and run the producer's checks
+func validateProducerConfig(schema configSchema, data map[string]string) configValidation {
+	result := configValidation{}
+	cfg := producerConfig{bools: map[string]bool{}, ints: map[string]int64{}, strings: map[string]string{}}
+
+	known := map[string]bool{}
+	for _, key := range slices.Concat(schema.bools, commonConfigSchema.bools) {
+		known[key] = true
+		if value := data[key]; value != "" { // empty keeps the flag value
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				result.errorf("%s=%q is not a boolean", key, value)
+				continue
+			}
+			cfg.bools[key] = parsed
+		}
+	}
+	for _, key := range slices.Concat(schema.ints, commonConfigSchema.ints) {
+		known[key] = true
+		if value := data[key]; value != "" { // empty keeps the flag value
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				result.errorf("%s=%q is not an integer", key, value)
+				continue
+			}
+			cfg.ints[key] = parsed
+		}
+	}
+	for _, key := range slices.Concat(schema.strings, commonConfigSchema.strings) {
+		known[key] = true
+		if value, ok := data[key]; ok {
+			cfg.strings[key] = value
+		}
+	}
+
+	unknown := []string{}
+	for key := range data {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		result.warnf("%s is not read by the producer", key)
+	}
+
+	if port, ok := cfg.ints["PROMETHEUS_PORT"]; ok && (port < 1 || port > 65535) {
+		result.errorf("PROMETHEUS_PORT=%d is not a port", port)
+	}
+	if format, ok := cfg.strings["LOG_FORMAT"]; ok && format != "" && format != "json" && format != "console" {
+		result.errorf("LOG_FORMAT=%q is not json or console", format)
+	}
+	if window, ok := cfg.strings["LOG_DEDUP_WINDOW"]; ok && window != "" {
+		if d, err := time.ParseDuration(window); err != nil || d < 0 {
+			result.errorf("LOG_DEDUP_WINDOW=%q is not a duration, e.g. 10s or 0 to disable", window)
+		}
+	}
+	if age, ok := cfg.strings["NATS_SPOOL_MAX_AGE"]; ok && age != "" {
+		if d, err := time.ParseDuration(age); err != nil || d < 0 {
+			result.errorf("NATS_SPOOL_MAX_AGE=%q is not a duration, e.g. 72h or 0 to keep the messages", age)
+		}
+	}
+	if size, ok := cfg.ints["NATS_SPOOL_MAX_SIZE"]; ok && size < 0 {
+		result.errorf("NATS_SPOOL_MAX_SIZE must not be negative")
+	}
+	if prefix := cfg.strings["METRICS_PREFIX"]; prefix != "" && !metricPrefixPattern.MatchString(prefix) {
+		result.errorf("METRICS_PREFIX=%q is not letters, digits and underscores ending in an underscore, e.g. prysm_", prefix)
+	}
+	if rate, ok := cfg.strings["METRICS_RATE_LIMIT"]; ok && rate != "" {
+		if r, err := strconv.ParseFloat(rate, 64); err != nil || r < 0 {
+			result.errorf("METRICS_RATE_LIMIT=%q is not a number of requests a second, e.g. 5 or 0 for unlimited", rate)
+		}
+	}
+	if burst, ok := cfg.ints["METRICS_RATE_BURST"]; ok && burst <= 0 {
+		result.errorf("METRICS_RATE_BURST must be positive")
+	}
+	if cpus, ok := cfg.strings["CPU_LIMIT"]; ok && cpus != "" {
+		if c, err := strconv.ParseFloat(cpus, 64); err != nil || c < 0 {
+			result.errorf("CPU_LIMIT=%q is not a number of CPU cores, e.g. 0.5 or 0 to derive it", cpus)
+		}
+	}
+	if limit, ok := cfg.ints["MEMORY_LIMIT"]; ok && limit < 0 {
+		result.errorf("MEMORY_LIMIT must not be negative")
+	}
+	if percent, ok := cfg.ints["MEMORY_LIMIT_PERCENT"]; ok && (percent < 0 || percent > 100) {
+		result.errorf("MEMORY_LIMIT_PERCENT must be between 0 and 100")
+	}
+	for _, pair := range [][2]string{{"METRICS_TLS_CERT", "METRICS_TLS_KEY"}, {"METRICS_BASIC_AUTH_USERNAME", "METRICS_BASIC_AUTH_PASSWORD"}, {"NATS_TLS_CERT", "NATS_TLS_KEY"}} {
+		// The other one may come from the flags
+		if (cfg.strings[pair[0]] == "") != (cfg.strings[pair[1]] == "") {
+			result.warnf("%s and %s are used together, only one of them is set", pair[0], pair[1])
+		}
+	}
+	if cfg.strings["VAULT_TOKEN"] != "" {
+		result.warnf("VAULT_TOKEN belongs in a Secret, not a ConfigMap; or use VAULT_KUBERNETES_ROLE")
+	}
+	hasVaultRefs := false
+	for _, value := range cfg.strings {
+		hasVaultRefs = hasVaultRefs || strings.HasPrefix(value, "vault:")
+	}
+	if hasVaultRefs && cfg.strings["VAULT_ADDR"] == "" {
+		result.warnf("vault: references without VAULT_ADDR cannot be read, unless the address is set by a flag")
+	}
+	schema.check(cfg, &result)
+	return result
+}
+
+func checkOpsLogConfig(cfg producerConfig, result *configValidation) {
+	if cfg.isTrue("TRACK_BUCKET_SLO") && cfg.isFalse("IGNORE_ANONYMOUS_REQUESTS") {
+		result.errorf("TRACK_BUCKET_SLO requires IGNORE_ANONYMOUS_REQUESTS, anonymous requests pollute the SLI metrics")
+	}
+	logFile, logFileSet := cfg.strings["LOG_FILE_PATH"]
+	socket, socketSet := cfg.strings["SOCKET_PATH"]
+	if logFileSet && socketSet && logFile == "" && socket == "" {
+		result.errorf("LOG_FILE_PATH or SOCKET_PATH must be set")
+	}
+	if cfg.strings["JOURNALD_UNIT"] != "" && cfg.strings["SOCKET_PATH"] != "" {
+		result.errorf("JOURNALD_UNIT and SOCKET_PATH are mutually exclusive")
+	}
+	if cursorFile, ok := cfg.strings["JOURNALD_CURSOR_FILE"]; ok && cursorFile == "" && cfg.strings["JOURNALD_UNIT"] != "" {
+		result.errorf("JOURNALD_CURSOR_FILE must not be empty with JOURNALD_UNIT")
+	}
+	for _, key := range []string{"LOG_RETENTION_DAYS", "MAX_LOG_FILE_SIZE", "PROMETHEUS_INTERVAL", "AUDIT_QUEUE_SIZE", "REMOTE_WRITE_INTERVAL",
+		"LOKI_BATCH_SIZE", "LOKI_BATCH_WAIT", "LOKI_QUEUE_SIZE", "HEALTH_CHECK_MIN_PER_MINUTE", "HEALTH_CHECK_TOP", "SLOW_REQUESTS_BUFFER_SIZE"} {
+		if value, ok := cfg.ints[key]; ok && value <= 0 {
+			result.errorf("%s must be positive", key)
+		}
+	}
+
+	if cfg.isTrue("NATS_SECURITY_EVENTS") && cfg.strings["NATS_URL"] == "" {
+		result.warnf("NATS_SECURITY_EVENTS without NATS_URL publishes no security events, unless the URL is set by a flag")
+	}
+	if cfg.isTrue("TRACK_API_CATEGORIES") && cfg.strings["NATS_URL"] == "" {
+		result.warnf("TRACK_API_CATEGORIES without NATS_URL publishes no API category metrics, unless the URL is set by a flag")
+	}
+	if cfg.strings["BUCKET_TAGS_KV"] != "" && cfg.strings["NATS_URL"] == "" {
+		result.warnf("BUCKET_TAGS_KV without NATS_URL cannot read the bucket tags, unless the URL is set by a flag")
+	}
+	if subject, ok := cfg.strings["NATS_SECURITY_SUBJECT"]; ok && subject == "" {
+		result.errorf("NATS_SECURITY_SUBJECT must not be empty")
+	}
+	if cfg.isTrue("NATS_SLOW_REQUESTS") && cfg.strings["NATS_URL"] == "" {
+		result.warnf("NATS_SLOW_REQUESTS without NATS_URL publishes no slow requests, unless the URL is set by a flag")
+	}
+	if threshold := cfg.strings["SLOW_REQUEST_THRESHOLD"]; threshold != "" {
+		if d, err := time.ParseDuration(threshold); err != nil || d < 0 {
+			result.errorf("SLOW_REQUEST_THRESHOLD=%q is not a duration, e.g. 2s or 0 to disable", threshold)
+		}
+	}
+	for _, threshold := range strings.Split(cfg.strings["SLOW_REQUEST_BUCKET_THRESHOLDS"], ",") {
+		if threshold = strings.TrimSpace(threshold); threshold == "" {
+			continue
+		}
+		bucket, value, _ := strings.Cut(threshold, "=")
+		if d, err := time.ParseDuration(strings.TrimSpace(value)); strings.TrimSpace(bucket) == "" || err != nil || d <= 0 {
+			result.errorf("SLOW_REQUEST_BUCKET_THRESHOLDS: %q is not a <bucket>=<duration> threshold", threshold)
+		}
+	}
+
+	for _, route := range strings.Split(cfg.strings["NATS_ROUTES"], ",") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+		class, subject, _ := strings.Cut(route, "=")
+		if !slices.Contains(natsRouteClasses, strings.TrimSpace(class)) || strings.TrimSpace(subject) == "" {
+			result.errorf("NATS_ROUTES: %q is not a <class>=<subject> route, the class one of %s", route, strings.Join(natsRouteClasses, ", "))
+		}
+	}
+
+	if value, ok := cfg.ints["BACKPRESSURE_QUEUE_SIZE"]; ok && value < 0 {
+		result.errorf("BACKPRESSURE_QUEUE_SIZE must not be negative")
+	}
+	if cfg.strings["BACKPRESSURE_HEALTH_CHECK_USER_AGENTS"] != "" || cfg.strings["BACKPRESSURE_HEALTH_CHECK_CIDRS"] != "" {
+		if value := cfg.ints["BACKPRESSURE_QUEUE_SIZE"]; value <= 0 {
+			result.warnf("BACKPRESSURE_HEALTH_CHECK_* without BACKPRESSURE_QUEUE_SIZE drops nothing, unless the size is set by a flag")
+		}
+	}
+
+	for _, key := range []string{"IP_INTERNAL_CIDRS", "IP_CROSS_REGION_CIDRS", "REPLICATION_CIDRS", "BACKPRESSURE_HEALTH_CHECK_CIDRS"} {
+		for _, cidr := range strings.Split(cfg.strings[key], ",") {
+			if cidr = strings.TrimSpace(cidr); cidr == "" {
+				continue
+			}
+			if _, err := netip.ParsePrefix(cidr); err != nil {
+				result.errorf("%s: %q is not a CIDR", key, cidr)
+			}
+		}
+	}
+
+	if cfg.isTrue("AUDIT_ENABLED") && cfg.strings["AUDIT_RABBITMQ_URL"] == "" {
+		result.warnf("AUDIT_ENABLED without AUDIT_RABBITMQ_URL publishes no audit events, unless the URL comes from a Secret")
+	}
+	if cfg.strings["AUDIT_RABBITMQ_PASSWORD"] != "" {
+		result.warnf("AUDIT_RABBITMQ_PASSWORD belongs in a Secret, not a ConfigMap")
+	}
+	if cfg.strings["KEYSTONE_APPLICATION_CREDENTIAL_SECRET"] != "" {
+		result.warnf("KEYSTONE_APPLICATION_CREDENTIAL_SECRET belongs in a Secret, not a ConfigMap")
+	}
+	if ttl := cfg.strings["KEYSTONE_CACHE_TTL"]; ttl != "" {
+		if d, err := time.ParseDuration(ttl); err != nil || d <= 0 {
+			result.errorf("KEYSTONE_CACHE_TTL=%q is not a positive duration, e.g. 1h", ttl)
+		}
+	}
+
+	if cfg.isTrue("TRACK_EVERYTHING") {
+		individual := 0
+		for key := range cfg.bools {
+			if strings.HasPrefix(key, "TRACK_") && key != "TRACK_EVERYTHING" {
+				individual++
+			}
+		}
+		if individual > 0 {
+			result.warnf("TRACK_EVERYTHING enables all detailed metrics, %d other TRACK_ settings have no effect", individual)
+		}
+		if interval, ok := cfg.ints["PROMETHEUS_INTERVAL"]; ok && interval < 30 {
+			result.warnf("PROMETHEUS_INTERVAL below 30 seconds with TRACK_EVERYTHING may impact performance")
+		}
+		return
+	}
+
+	detailed := 0
+	for key, enabled := range cfg.bools {
+		if enabled && strings.HasPrefix(key, "TRACK_") && strings.HasSuffix(key, "_DETAILED") && key != "TRACK_LATENCY_DETAILED" {
+			detailed++
+		}
+	}
+	if detailed > 5 {
+		result.warnf("%d detailed metrics enabled, these have the highest memory usage", detailed)
+	}
+}
+
+func checkRadosGWUsageConfig(cfg producerConfig, result *configValidation) {
+	for _, key := range []string{"COOLDOWN_INTERVAL", "REMOTE_WRITE_INTERVAL"} {
+		if value, ok := cfg.ints[key]; ok && value <= 0 {
+			result.errorf("%s must be positive", key)
+		}
+	}
+	for _, key := range []string{"RESHARD_OBJECTS_PER_SHARD", "LARGE_OMAP_KEYS_PER_SHARD"} {
+		if value, ok := cfg.ints[key]; ok && value < 0 {
+			result.errorf("%s must not be negative", key)
+		}
+	}
+	for _, key := range []string{"TENANT_ANOMALY_HISTORY", "TENANT_ANOMALY_GROWTH_FACTOR"} {
+		if value, ok := cfg.ints[key]; ok && value <= 0 {
+			result.errorf("%s must be positive", key)
+		}
+	}
+	if percent, ok := cfg.ints["TENANT_ANOMALY_DELETION_PERCENT"]; ok && (percent <= 0 || percent > 100) {
+		result.errorf("TENANT_ANOMALY_DELETION_PERCENT must be between 1 and 100")
+	}
+	if percent, ok := cfg.ints["COMPLETENESS_PERCENT"]; ok && (percent < 0 || percent > 100) {
+		result.errorf("COMPLETENESS_PERCENT must be between 0 and 100")
+	}
+	if gib, ok := cfg.ints["TENANT_ANOMALY_MIN_GIB"]; ok && gib < 0 {
+		result.errorf("TENANT_ANOMALY_MIN_GIB must not be negative")
+	}
+	if workers, ok := cfg.ints["KV_WRITE_WORKERS"]; ok && workers < 1 {
+		result.errorf("KV_WRITE_WORKERS must be positive")
+	}
+	if cfg.isFalse("SYNC_CONTROL_NATS") {
+		result.errorf("SYNC_CONTROL_NATS=false is not supported by radosgw-usage")
+	}
+	if cfg.isTrue("DRY_RUN") {
+		result.errorf("DRY_RUN exits after one cycle, the producer would be restarted in a loop")
+	}
+	if cfg.isTrue("SYNC_EXTERNAL_NATS") && cfg.strings["SYNC_CONTROL_URL"] == "" {
+		result.errorf("SYNC_EXTERNAL_NATS requires SYNC_CONTROL_URL")
+	}
+	if prefix, ok := cfg.strings["SYNC_CONTROL_BUCKET_PREFIX"]; ok && prefix == "" {
+		result.errorf("SYNC_CONTROL_BUCKET_PREFIX must not be empty")
+	}
+	for _, key := range []string{"KV_COMPACT_AGE", "KV_COMPACT_INTERVAL"} {
+		if value := cfg.strings[key]; value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				result.errorf("%s=%q is not a duration, e.g. 24h", key, value)
+			}
+		}
+	}
+	for _, pair := range strings.Split(cfg.strings["KV_TTL"], ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		if _, ttl, found := strings.Cut(pair, "="); !found {
+			result.errorf("KV_TTL %q is not bucket=duration", pair)
+		} else if d, err := time.ParseDuration(strings.TrimSpace(ttl)); err != nil || d < 0 {
+			result.errorf("KV_TTL %q is not bucket=duration, e.g. user_usage_data=72h", pair)
+		}
+	}
+	if cfg.isTrue("PUBLIC_BUCKET_NOTIFY") && cfg.isFalse("AUDIT_BUCKET_ACCESS") {
+		result.errorf("PUBLIC_BUCKET_NOTIFY requires AUDIT_BUCKET_ACCESS")
+	}
+	source, ok := cfg.strings["RGW_USAGE_SOURCE"]
+	if ok && source != "admin-api" && source != "cli" {
+		result.errorf("RGW_USAGE_SOURCE=%q is not admin-api or cli", source)
+	}
+	required := []string{"ADMIN_URL", "RGW_CLUSTER_ID"}
+	if source == "cli" {
+		required = required[1:]
+	}
+	for _, key := range required {
+		if value, ok := cfg.strings[key]; ok && value == "" {
+			result.errorf("%s must not be empty", key)
+		}
+	}
+	if cfg.strings["SECRET_KEY"] != "" {
+		result.warnf("SECRET_KEY belongs in a Secret, not a ConfigMap")
+	}
+}
+
+// Names of the producers with a configuration schema
+func configProducers() []string {
+	producers := make([]string, 0, len(configSchemas))
+	for producer := range configSchemas {
+		producers = append(producers, producer)
+	}
+	sort.Strings(producers)
+	return producers
+}
+
+func deny(req *admissionv1.AdmissionRequest, message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		UID:     req.UID,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: message,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    422,
+		},
+	}
+}
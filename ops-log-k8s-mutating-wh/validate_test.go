@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Check that every message contains the expected substring, in order
+func matchMessages(t *testing.T, kind string, messages, expected []string) {
+	t.Helper()
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %d %s %q, got %q", len(expected), kind, expected, messages)
+	}
+	for i := range expected {
+		if !strings.Contains(messages[i], expected[i]) {
+			t.Fatalf("expected %s %d to contain %q, got %q", kind, i, expected[i], messages[i])
+		}
+	}
+}
+
+func TestValidateProducerConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		producer string
+		data     map[string]string
+		errors   []string
+		warnings []string
+	}{
+		// ops-log
+		{
+			name:     "ops-log accepted",
+			producer: "ops-log",
+			data:     map[string]string{"LOG_FILE_PATH": "/var/log/ceph/ops-log.log", "PROMETHEUS_PORT": "9090", "PROMETHEUS_INTERVAL": "60", "TRACK_REQUESTS_PER_USER": "true"},
+		},
+		{
+			name:     "ops-log empty values keep the flags",
+			producer: "ops-log",
+			data:     map[string]string{"PROMETHEUS_PORT": "", "TRACK_EVERYTHING": ""},
+		},
+		{
+			name:     "ops-log bad boolean",
+			producer: "ops-log",
+			data:     map[string]string{"TRACK_EVERYTHING": "yes please"},
+			errors:   []string{`TRACK_EVERYTHING="yes please" is not a boolean`},
+		},
+		{
+			name:     "ops-log bad integer",
+			producer: "ops-log",
+			data:     map[string]string{"PROMETHEUS_INTERVAL": "1m"},
+			errors:   []string{`PROMETHEUS_INTERVAL="1m" is not an integer`},
+		},
+		{
+			name:     "ops-log non-positive interval",
+			producer: "ops-log",
+			data:     map[string]string{"PROMETHEUS_INTERVAL": "0"},
+			errors:   []string{"PROMETHEUS_INTERVAL must be positive"},
+		},
+		{
+			name:     "ops-log port out of range",
+			producer: "ops-log",
+			data:     map[string]string{"PROMETHEUS_PORT": "70000"},
+			errors:   []string{"PROMETHEUS_PORT=70000 is not a port"},
+		},
+		{
+			name:     "ops-log no input",
+			producer: "ops-log",
+			data:     map[string]string{"LOG_FILE_PATH": "", "SOCKET_PATH": ""},
+			errors:   []string{"LOG_FILE_PATH or SOCKET_PATH must be set"},
+		},
+		{
+			name:     "ops-log SLO with anonymous requests",
+			producer: "ops-log",
+			data:     map[string]string{"TRACK_BUCKET_SLO": "true", "IGNORE_ANONYMOUS_REQUESTS": "false"},
+			errors:   []string{"TRACK_BUCKET_SLO requires IGNORE_ANONYMOUS_REQUESTS"},
+		},
+		{
+			name:     "ops-log unknown field",
+			producer: "ops-log",
+			data:     map[string]string{"TRACK_EVERYTHIN": "true", "PROMETHEUS_PORT": "9090"},
+			warnings: []string{"TRACK_EVERYTHIN is not read by the producer"},
+		},
+		{
+			name:     "ops-log audit without RabbitMQ and with a password",
+			producer: "ops-log",
+			data:     map[string]string{"AUDIT_ENABLED": "true", "AUDIT_RABBITMQ_PASSWORD": "secret"},
+			warnings: []string{"AUDIT_ENABLED without AUDIT_RABBITMQ_URL", "AUDIT_RABBITMQ_PASSWORD belongs in a Secret"},
+		},
+		{
+			name:     "ops-log track everything with other settings and a short interval",
+			producer: "ops-log",
+			data:     map[string]string{"TRACK_EVERYTHING": "true", "TRACK_LATENCY_PER_USER": "false", "PROMETHEUS_INTERVAL": "10"},
+			warnings: []string{"1 other TRACK_ settings have no effect", "PROMETHEUS_INTERVAL below 30 seconds"},
+		},
+		{
+			name:     "ops-log many detailed metrics",
+			producer: "ops-log",
+			data: map[string]string{
+				"TRACK_REQUESTS_DETAILED": "true", "TRACK_BYTES_SENT_DETAILED": "true", "TRACK_BYTES_RECEIVED_DETAILED": "true",
+				"TRACK_ERRORS_DETAILED": "true", "TRACK_REQUESTS_BY_IP_DETAILED": "true", "TRACK_REQUESTS_BY_STATUS_DETAILED": "true",
+			},
+			warnings: []string{"6 detailed metrics enabled"},
+		},
+
+		// radosgw-usage
+		{
+			name:     "radosgw-usage accepted",
+			producer: "radosgw-usage",
+			data:     map[string]string{"ADMIN_URL": "http://rgw:8080", "COOLDOWN_INTERVAL": "30", "SYNC_EXTERNAL_NATS": "true", "SYNC_CONTROL_URL": "nats://nats:4222"},
+		},
+		{
+			name:     "radosgw-usage non-positive interval",
+			producer: "radosgw-usage",
+			data:     map[string]string{"COOLDOWN_INTERVAL": "-1"},
+			errors:   []string{"COOLDOWN_INTERVAL must be positive"},
+		},
+		{
+			name:     "radosgw-usage bad interval",
+			producer: "radosgw-usage",
+			data:     map[string]string{"COOLDOWN_INTERVAL": "30s"},
+			errors:   []string{`COOLDOWN_INTERVAL="30s" is not an integer`},
+		},
+		{
+			name:     "radosgw-usage external NATS without URL",
+			producer: "radosgw-usage",
+			data:     map[string]string{"SYNC_EXTERNAL_NATS": "true"},
+			errors:   []string{"SYNC_EXTERNAL_NATS requires SYNC_CONTROL_URL"},
+		},
+		{
+			name:     "radosgw-usage control NATS disabled",
+			producer: "radosgw-usage",
+			data:     map[string]string{"SYNC_CONTROL_NATS": "false"},
+			errors:   []string{"SYNC_CONTROL_NATS=false is not supported"},
+		},
+		{
+			name:     "radosgw-usage empty settings",
+			producer: "radosgw-usage",
+			data:     map[string]string{"SYNC_CONTROL_BUCKET_PREFIX": "", "ADMIN_URL": "", "RGW_CLUSTER_ID": "", "RESHARD_OBJECTS_PER_SHARD": "-5"},
+			errors: []string{
+				"RESHARD_OBJECTS_PER_SHARD must not be negative", "SYNC_CONTROL_BUCKET_PREFIX must not be empty",
+				"ADMIN_URL must not be empty", "RGW_CLUSTER_ID must not be empty",
+			},
+		},
+		{
+			name:     "radosgw-usage unknown fields and secret key",
+			producer: "radosgw-usage",
+			data:     map[string]string{"SECRET_KEY": "secret", "TRACK_EVERYTHING": "true", "ADMIN_PORT": "8080"},
+			warnings: []string{"ADMIN_PORT is not read", "TRACK_EVERYTHING is not read", "SECRET_KEY belongs in a Secret"},
+		},
+
+		// common settings
+		{
+			name:     "vault references with an address and role",
+			producer: "ops-log",
+			data:     map[string]string{"VAULT_ADDR": "https://vault:8200", "VAULT_KUBERNETES_ROLE": "prysm", "NATS_CREDS": "vault:prysm/nats#creds"},
+		},
+		{
+			name:     "vault references without an address",
+			producer: "ops-log",
+			data:     map[string]string{"NATS_CREDS": "vault:prysm/nats#creds"},
+			warnings: []string{"vault: references without VAULT_ADDR"},
+		},
+		{
+			name:     "vault token in the configmap",
+			producer: "radosgw-usage",
+			data:     map[string]string{"VAULT_ADDR": "https://vault:8200", "VAULT_TOKEN": "hvs.secret"},
+			warnings: []string{"VAULT_TOKEN belongs in a Secret"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, ok := configSchemas[test.producer]
+			if !ok {
+				t.Fatalf("no schema for %s", test.producer)
+			}
+			result := validateProducerConfig(schema, test.data)
+			matchMessages(t, "errors", result.errors, test.errors)
+			matchMessages(t, "warnings", result.warnings, test.warnings)
+		})
+	}
+}
+
+func configMapRequest(t *testing.T, operation admissionv1.Operation, labels, data map[string]string) *admissionv1.AdmissionRequest {
+	t.Helper()
+	configMap := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "prysm-config", Labels: labels}, Data: data}
+	object, err := json.Marshal(configMap)
+	if err != nil {
+		t.Fatalf("marshal configmap: %v", err)
+	}
+	req := admissionv1.AdmissionRequest{}
+	raw := `{"uid":"1","kind":{"kind":"ConfigMap"},"operation":"` + string(operation) + `","object":` + string(object) + `}`
+	if err := json.Unmarshal([]byte(raw), &req); err != nil {
+		t.Fatalf("unmarshal request: %v", err)
+	}
+	return &req
+}
+
+func TestValidateConfigMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		labels    map[string]string
+		data      map[string]string
+		allowed   bool
+		result    string
+		message   string
+		warnings  int
+	}{
+		{"not labeled", admissionv1.Create, nil, map[string]string{"PROMETHEUS_PORT": "x"}, true, resultAllowed, "", 0},
+		{"deleted", admissionv1.Delete, map[string]string{configLabel: "ops-log"}, map[string]string{"PROMETHEUS_PORT": "x"}, true, resultAllowed, "", 0},
+		{"unknown producer", admissionv1.Create, map[string]string{configLabel: "nope"}, nil, false, resultDenied, "must be one of ops-log, radosgw-usage", 0},
+		{"invalid", admissionv1.Update, map[string]string{configLabel: "ops-log"}, map[string]string{"PROMETHEUS_PORT": "x", "UNKNOWN": "1"}, false, resultDenied, "invalid ops-log configuration", 1},
+		{"valid with warnings", admissionv1.Create, map[string]string{configLabel: "radosgw-usage"}, map[string]string{"SECRET_KEY": "s"}, true, resultAllowed, "", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, decision := validateConfigMap(configMapRequest(t, test.operation, test.labels, test.data))
+			if resp.Allowed != test.allowed || decision.result != test.result {
+				t.Fatalf("expected allowed=%v %s, got allowed=%v %+v", test.allowed, test.result, resp.Allowed, decision)
+			}
+			if test.message != "" && (resp.Result == nil || !strings.Contains(resp.Result.Message, test.message)) {
+				t.Fatalf("expected a message containing %q, got %+v", test.message, resp.Result)
+			}
+			if len(resp.Warnings) != test.warnings {
+				t.Fatalf("expected %d warnings, got %q", test.warnings, resp.Warnings)
+			}
+		})
+	}
+}
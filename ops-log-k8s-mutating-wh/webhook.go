@@ -6,18 +6,18 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	admissionv1 "k8s.io/api/admission/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/klog/v2"
 )
 
-// Define the sidecar container
+// Default sidecar container, completing the sidecars of injection rules
 var sidecarContainer = corev1.Container{
 	Name:  "prysm-sidecar",
 	Image: os.Getenv("SIDECAR_IMAGE"),
@@ -52,147 +52,361 @@ var sidecarContainer = corev1.Container{
 				},
 			},
 		},
+		{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		},
 	},
 }
 
-// Check if Deployment belongs to RADOSGW (based on labels)
-func isRadosgwDeployment(deployment *appsv1.Deployment) bool {
-	labels := deployment.Labels
-	if labels == nil {
-		return false
-	}
-	return labels["app"] == "rook-ceph-rgw" &&
-		labels["app.kubernetes.io/component"] == "cephobjectstores.ceph.rook.io" &&
-		labels["app.kubernetes.io/created-by"] == "rook-ceph-operator" &&
-		labels["app.kubernetes.io/managed-by"] == "rook-ceph-operator"
+// Label selecting the sidecar policy, used by the default rule
+const sidecarPolicyLabel = "prysm-sidecar"
+
+// Pod template annotations naming a Secret or ConfigMap for the sidecar env
+const (
+	sidecarEnvSecretAnnotation    = "prysm-sidecar/sidecar-env-secret"
+	sidecarEnvConfigMapAnnotation = "prysm-sidecar/sidecar-env-configmap"
+)
+
+// Pod template annotation naming the sidecar the webhook injected, the only
+// container it removes again
+const injectedSidecarAnnotation = "prysm-sidecar/injected-sidecar"
+
+// Labels Rook sets on RADOSGW deployments
+var rookRGWLabels = map[string]string{
+	"app":                          "rook-ceph-rgw",
+	"app.kubernetes.io/component":  "cephobjectstores.ceph.rook.io",
+	"app.kubernetes.io/created-by": "rook-ceph-operator",
+	"app.kubernetes.io/managed-by": "rook-ceph-operator",
+}
+
+// Outcome of an admission request, for the metrics and the audit log
+type admissionDecision struct {
+	result  string // one of admissionResults
+	name    string // of the deployment, also set on CREATE
+	rule    string
+	reason  string
+	patches int
+	cached  bool // taken from the shared decision cache
 }
 
-// Mutate deployments to add a sidecar (only for RADOSGW)
-func mutateDeployment(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+// Mutate deployments according to the first matching injection rule
+func mutateDeployment(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, admissionDecision) {
 	if req.Kind.Kind != "Deployment" {
-		return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID}
+		return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID},
+			admissionDecision{result: resultSkipped, name: req.Name, reason: "not a deployment"}
 	}
 
 	// Deserialize the Deployment object
 	deployment := appsv1.Deployment{}
 	if err := json.Unmarshal(req.Object.Raw, &deployment); err != nil {
-		klog.Errorf("Failed to unmarshal Deployment: %v", err)
-		return &admissionv1.AdmissionResponse{Allowed: false, UID: req.UID}
+		log.Error().Err(err).Msg("Failed to unmarshal Deployment")
+		return &admissionv1.AdmissionResponse{Allowed: false, UID: req.UID},
+			admissionDecision{result: resultErrored, name: req.Name, reason: "invalid deployment"}
 	}
-
-	// Skip mutation if not a RADOSGW deployment
-	if !isRadosgwDeployment(&deployment) {
-		return &admissionv1.AdmissionResponse{Allowed: true, UID: req.UID}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = deployment.Namespace
 	}
 
-	klog.Infof("Mutating deployment: %s", deployment.Name)
+	// Replicas share decisions, identical deployments are mutated alike
+	m, cached := mutation{}, false
+	decide := func() mutation { return decideMutation(namespace, &deployment) }
+	if decisions == nil {
+		m = decide()
+	} else if key, err := decisionKey(*activeRules.Load(), namespace, deployment.Labels, deployment.Spec.Template); err != nil {
+		log.Error().Err(err).Msg("Failed to compute decision key")
+		m = decide()
+	} else {
+		m, cached = decisions.decide(key, decide)
+	}
 
-	// Determine sidecar policy
-	sidecarPolicy := deployment.Labels["prysm-sidecar"]
-	klog.Infof("Evaluating sidecar policy: %s for deployment %s", sidecarPolicy, deployment.Name)
+	decision := admissionDecision{
+		result:  m.Result,
+		name:    deployment.Name,
+		rule:    m.Rule,
+		reason:  m.Reason,
+		patches: m.Patches,
+		cached:  cached,
+	}
+	if m.Result != resultMutated {
+		return &admissionv1.AdmissionResponse{Allowed: m.Result != resultErrored, UID: req.UID}, decision
+	}
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		UID:       req.UID,
+		Patch:     m.Patch,
+		PatchType: func() *admissionv1.PatchType { pt := admissionv1.PatchTypeJSONPatch; return &pt }(),
+	}, decision
+}
 
-	// Find if the sidecar already exists
-	sidecarIndex := -1
-	for i, container := range deployment.Spec.Template.Spec.Containers {
-		if container.Name == sidecarContainer.Name {
-			sidecarIndex = i
-			break
-		}
+// Decide how to mutate a deployment, by the first matching injection rule
+func decideMutation(namespace string, deployment *appsv1.Deployment) mutation {
+	m := mutation{}
+	rule := matchRule(namespace, deployment.Labels)
+	if rule != nil {
+		log.Info().Msgf("Mutating deployment %s/%s using rule %s", namespace, deployment.Name, rule.Name)
+		m.Rule = rule.Name
 	}
 
-	var patches []map[string]any
+	injected := injectedSidecar(deployment)
+	spec := injectPodSpec(rule, deployment.Spec.Template, injected)
+	annotations := injectedAnnotations(rule, deployment.Spec.Template.Annotations)
 
-	// Decide patching logic based on policy
-	switch sidecarPolicy {
-	case "yes":
-		// Handle annotations (Secret / ConfigMap)
-		annotations := deployment.Spec.Template.Annotations
-		if annotations == nil {
-			annotations = map[string]string{}
-		}
-		sidecarContainer.EnvFrom = nil // Clear old ones before injecting fresh ones
-
-		if secretName, ok := annotations["prysm-sidecar/sidecar-env-secret"]; ok && secretName != "" {
-			klog.Infof("Injecting envFrom using secret: %s", secretName)
-			sidecarContainer.EnvFrom = append(sidecarContainer.EnvFrom, corev1.EnvFromSource{
-				SecretRef: &corev1.SecretEnvSource{
-					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
-					Optional:             pointerTo(true),
-				},
-			})
-		}
-		if configMapName, ok := annotations["prysm-sidecar/sidecar-env-configmap"]; ok && configMapName != "" {
-			klog.Infof("Injecting envFrom using configMap: %s", configMapName)
-			sidecarContainer.EnvFrom = append(sidecarContainer.EnvFrom, corev1.EnvFromSource{
-				ConfigMapRef: &corev1.ConfigMapEnvSource{
-					LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
-					Optional:             pointerTo(true),
-				},
-			})
-		}
-
-		if sidecarIndex >= 0 {
-			// Replace existing sidecar
-			klog.Infof("Replacing existing sidecar container in deployment: %s", deployment.Name)
+	// Patch only what changed, e.g. nothing without a rule and a sidecar to remove
+	var patches []map[string]any
+	if !equalJSON(deployment.Spec.Template.Annotations, annotations) {
+		if len(annotations) == 0 {
 			patches = append(patches, map[string]any{
-				"op":    "replace",
-				"path":  fmt.Sprintf("/spec/template/spec/containers/%d", sidecarIndex),
-				"value": sidecarContainer,
+				"op":   "remove",
+				"path": "/spec/template/metadata/annotations",
 			})
 		} else {
-			// Add the sidecar if it does not exist
-			klog.Infof("Adding new sidecar container in deployment: %s", deployment.Name)
 			patches = append(patches, map[string]any{
 				"op":    "add",
-				"path":  "/spec/template/spec/containers/-",
-				"value": sidecarContainer,
+				"path":  "/spec/template/metadata/annotations",
+				"value": annotations,
 			})
 		}
-
-	case "no", "":
-		// Remove sidecar if it exists
-		if sidecarIndex >= 0 {
-			klog.Infof("Removing sidecar from deployment: %s", deployment.Name)
-			patches = append(patches, map[string]any{
-				"op":   "remove",
-				"path": fmt.Sprintf("/spec/template/spec/containers/%d", sidecarIndex),
-			})
-		} else {
-			klog.Infof("No sidecar to remove in deployment: %s", deployment.Name)
+	}
+	if !equalJSON(deployment.Spec.Template.Spec.Containers, spec.Containers) {
+		patches = append(patches, map[string]any{
+			"op":    "replace",
+			"path":  "/spec/template/spec/containers",
+			"value": spec.Containers,
+		})
+	}
+	if !equalJSON(deployment.Spec.Template.Spec.Volumes, spec.Volumes) {
+		// "add" also replaces, and creates the list if the pod has no volumes
+		patches = append(patches, map[string]any{
+			"op":    "add",
+			"path":  "/spec/template/spec/volumes",
+			"value": spec.Volumes,
+		})
+	}
+	if len(patches) == 0 {
+		m.Result = resultSkipped
+		m.Reason = "no matching rule"
+		if rule != nil {
+			m.Reason = "up to date"
 		}
+		return m
 	}
 
 	// Marshal JSON patch
 	patchBytes, err := json.Marshal(patches)
 	if err != nil {
-		klog.Errorf("Failed to marshal JSON patch: %v", err)
-		return &admissionv1.AdmissionResponse{Allowed: false, UID: req.UID}
+		log.Error().Err(err).Msg("Failed to marshal JSON patch")
+		m.Result = resultErrored
+		m.Reason = "invalid patch"
+		return m
 	}
 
-	return &admissionv1.AdmissionResponse{
-		Allowed:   true,
-		UID:       req.UID,
-		Patch:     patchBytes,
-		PatchType: func() *admissionv1.PatchType { pt := admissionv1.PatchTypeJSONPatch; return &pt }(),
+	m.Result = resultMutated
+	m.Patch = patchBytes
+	m.Patches = len(patches)
+	if rule == nil {
+		m.Reason = "sidecar removed"
 	}
+	return m
 }
 
-// Handle admission requests
-func mutateHandler(w http.ResponseWriter, r *http.Request) {
+// Name of the sidecar the webhook injected into a deployment before, empty
+// if it did not. RGW deployments of Rook were the only ones mutated before
+// the injected sidecar was annotated, with the default sidecar.
+func injectedSidecar(deployment *appsv1.Deployment) string {
+	if name := deployment.Spec.Template.Annotations[injectedSidecarAnnotation]; name != "" {
+		return name
+	}
+	for key, value := range rookRGWLabels {
+		if deployment.Labels[key] != value {
+			return ""
+		}
+	}
+	return sidecarContainer.Name
+}
+
+// Pod template annotations with the injected sidecar of the rule recorded,
+// or the record removed if the rule injects none
+func injectedAnnotations(rule *InjectionRule, annotations map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range annotations {
+		if key != injectedSidecarAnnotation {
+			result[key] = value
+		}
+	}
+	if rule != nil && rule.Sidecar != nil {
+		result[injectedSidecarAnnotation] = rule.Sidecar.Name
+	}
+	if len(result) == 0 && annotations == nil {
+		return nil
+	}
+	return result
+}
+
+// Build the pod spec of a pod template with the rule applied. The sidecar
+// injected before is removed unless the matching rule injects it again, a
+// rule's env replaces variables of the same name. The ops-log socket is
+// wired if the sidecar is injected and the template asks for it, and
+// unwired otherwise. Pods the webhook never injected a sidecar into keep
+// their containers and volumes apart from the rule's env.
+func injectPodSpec(rule *InjectionRule, template corev1.PodTemplateSpec, injected string) corev1.PodSpec {
+	spec := *template.Spec.DeepCopy()
+
+	var sidecar *corev1.Container
+	if rule != nil && rule.Sidecar != nil {
+		sidecar = rule.Sidecar.DeepCopy()
+		sidecar.EnvFrom = append(sidecar.EnvFrom, annotatedEnvFrom(template.Annotations)...)
+	}
+	socket := sidecar != nil && (rule.OpsLogSocket || opsLogSocketEnabled(template.Annotations))
+	if socket {
+		log.Info().Msgf("Wiring ops-log socket %s", opsLogSocketPath)
+		socketSidecar(sidecar)
+	}
+	rgwContainer := template.Annotations[rgwContainerAnnotation]
+	if rgwContainer == "" && rule != nil {
+		rgwContainer = rule.RGWContainer
+	}
+	if rgwContainer == "" {
+		rgwContainer = defaultRGWContainer
+	}
+
+	wire := sidecar != nil || injected != ""
+	containers := []corev1.Container{}
+	sidecarIndex := -1
+	for _, container := range spec.Containers {
+		if sidecar != nil && container.Name == sidecar.Name {
+			sidecarIndex = len(containers)
+			containers = append(containers, *sidecar)
+			continue
+		}
+		if container.Name == injected {
+			log.Info().Msgf("Removing sidecar %s", container.Name)
+			continue
+		}
+		if rule != nil && rule.injectsEnvInto(container.Name) {
+			container.Env = mergeEnv(container.Env, rule.Env)
+		}
+		if wire && container.Name == rgwContainer {
+			wireRGWContainer(&container, socket)
+		}
+		containers = append(containers, container)
+	}
+
+	if sidecar != nil && sidecarIndex < 0 {
+		log.Info().Msgf("Adding sidecar %s", sidecar.Name)
+		containers = append(containers, *sidecar)
+	}
+	spec.Containers = containers
+	if wire {
+		spec.Volumes = socketVolumes(spec.Volumes, socket)
+	}
+	return spec
+}
+
+// Compare two values by their JSON encoding
+func equalJSON(a, b any) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aJSON) == string(bJSON)
+}
+
+// Sidecar envFrom sources named by the pod template annotations
+func annotatedEnvFrom(annotations map[string]string) []corev1.EnvFromSource {
+	var envFrom []corev1.EnvFromSource
+	if secretName := annotations[sidecarEnvSecretAnnotation]; secretName != "" {
+		log.Info().Msgf("Injecting envFrom using secret: %s", secretName)
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Optional:             pointerTo(true),
+			},
+		})
+	}
+	if configMapName := annotations[sidecarEnvConfigMapAnnotation]; configMapName != "" {
+		log.Info().Msgf("Injecting envFrom using configMap: %s", configMapName)
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+				Optional:             pointerTo(true),
+			},
+		})
+	}
+	return envFrom
+}
+
+// Check if the rule's env is injected into the container
+func (rule *InjectionRule) injectsEnvInto(name string) bool {
+	if len(rule.Env) == 0 {
+		return false
+	}
+	if len(rule.Containers) == 0 {
+		return true
+	}
+	for _, container := range rule.Containers {
+		if container == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge env vars, replacing existing variables of the same name
+func mergeEnv(env, injected []corev1.EnvVar) []corev1.EnvVar {
+	merged := append([]corev1.EnvVar{}, env...)
+	for _, variable := range injected {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == variable.Name {
+				merged[i] = variable
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, variable)
+		}
+	}
+	return merged
+}
+
+// Handle admission requests with review, /mutate or /validate
+func admissionHandler(review func(*admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, admissionDecision)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveAdmission(w, r, review)
+	}
+}
+
+func serveAdmission(w http.ResponseWriter, r *http.Request, review func(*admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, admissionDecision)) {
+	start := time.Now()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		metrics.observe(resultErrored, time.Since(start))
 		http.Error(w, "Failed to read request body", http.StatusInternalServerError)
 		return
 	}
 
 	ar := admissionv1.AdmissionReview{}
-	if err := json.Unmarshal(body, &ar); err != nil {
+	if err := json.Unmarshal(body, &ar); err != nil || ar.Request == nil {
+		metrics.observe(resultErrored, time.Since(start))
 		http.Error(w, "Failed to decode request body", http.StatusBadRequest)
 		return
 	}
 
-	// Mutate if necessary
-	resp := mutateDeployment(ar.Request)
+	// Mutate or validate
+	resp, decision := review(ar.Request)
+	metrics.observe(decision.result, time.Since(start))
+	auditDecision(ar.Request, decision, time.Since(start))
 
 	// Wrap response in AdmissionReview
 	response := admissionv1.AdmissionReview{
@@ -209,6 +423,31 @@ func mutateHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(responseBytes)
 }
 
+// Log every admission decision as a structured audit record
+func auditDecision(req *admissionv1.AdmissionRequest, decision admissionDecision, duration time.Duration) {
+	log.Info().
+		Str("uid", string(req.UID)).
+		Str("operation", string(req.Operation)).
+		Str("kind", req.Kind.Kind).
+		Str("namespace", req.Namespace).
+		Str("name", decision.name).
+		Str("user", req.UserInfo.Username).
+		Bool("dryRun", req.DryRun != nil && *req.DryRun).
+		Str("result", decision.result).
+		Str("rule", decision.rule).
+		Str("reason", decision.reason).
+		Int("patches", decision.patches).
+		Bool("cached", decision.cached).
+		Dur("duration", duration).
+		Msg("Admission decision")
+}
+
+// Report the webhook as healthy once it serves requests
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte("ok"))
+}
+
 func pointerTo[T any](v T) *T {
 	return &v
 }
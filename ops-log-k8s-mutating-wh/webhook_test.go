@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func useRules(t *testing.T, rules []InjectionRule) {
+	t.Helper()
+	if err := prepareRules(rules); err != nil {
+		t.Fatalf("prepare rules: %v", err)
+	}
+	previous := fileRules.Load()
+	storeRules(&fileRules, rules)
+	t.Cleanup(func() {
+		if previous == nil {
+			storeRules(&fileRules, nil)
+			return
+		}
+		storeRules(&fileRules, *previous)
+	})
+}
+
+func deploymentWith(namespace string, annotations map[string]string, containers ...string) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace}}
+	deployment.Spec.Template.Annotations = annotations
+	for _, name := range containers {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{Name: name, Image: name})
+	}
+	return deployment
+}
+
+// Admit a deployment and return the JSON patch operations by path
+func admit(t *testing.T, deployment *appsv1.Deployment) (map[string]map[string]any, admissionDecision) {
+	t.Helper()
+	object, err := json.Marshal(deployment)
+	if err != nil {
+		t.Fatalf("marshal deployment: %v", err)
+	}
+	req := admissionv1.AdmissionRequest{}
+	raw := `{"uid":"1","kind":{"kind":"Deployment"},"namespace":"` + deployment.Namespace + `","object":` + string(object) + `}`
+	if err := json.Unmarshal([]byte(raw), &req); err != nil {
+		t.Fatalf("unmarshal request: %v", err)
+	}
+
+	resp, decision := mutateDeployment(&req)
+	if !resp.Allowed {
+		t.Fatalf("deployment denied: %+v", decision)
+	}
+	patches := map[string]map[string]any{}
+	if len(resp.Patch) == 0 {
+		return patches, decision
+	}
+	var operations []map[string]any
+	if err := json.Unmarshal(resp.Patch, &operations); err != nil {
+		t.Fatalf("unmarshal patch: %v", err)
+	}
+	for _, operation := range operations {
+		patches[operation["path"].(string)] = operation
+	}
+	if len(patches) != decision.patches {
+		t.Fatalf("decision counts %d patches, got %v", decision.patches, patches)
+	}
+	return patches, decision
+}
+
+func containerNames(spec corev1.PodSpec) []string {
+	var names []string
+	for _, container := range spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+func TestPrepareRules(t *testing.T) {
+	sidecar := &corev1.Container{Image: "prysm:1"}
+	tests := []struct {
+		name  string
+		rules []InjectionRule
+		err   string
+	}{
+		{"valid", []InjectionRule{{Name: "rgw", Namespaces: []string{"rook-*"}, Sidecar: sidecar}}, ""},
+		{"no name", []InjectionRule{{Namespaces: []string{"rook"}, Sidecar: sidecar}}, "has no name"},
+		{"duplicate", []InjectionRule{{Name: "rgw", Namespaces: []string{"a"}, Sidecar: sidecar}, {Name: "rgw", Namespaces: []string{"b"}, Sidecar: sidecar}}, "duplicate rule"},
+		{"selects everything", []InjectionRule{{Name: "rgw", Sidecar: sidecar}}, "selects every deployment"},
+		{"injects nothing", []InjectionRule{{Name: "rgw", Namespaces: []string{"rook"}}}, "neither a sidecar nor env"},
+		{"bad pattern", []InjectionRule{{Name: "rgw", Namespaces: []string{"rook-["}, Sidecar: sidecar}}, "invalid namespace pattern"},
+		{"no image", []InjectionRule{{Name: "rgw", Namespaces: []string{"rook"}, Sidecar: &corev1.Container{}}}, "no sidecar image"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := prepareRules(test.rules)
+			if test.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestPrepareRules_CompletesSidecar(t *testing.T) {
+	rules := []InjectionRule{{Name: "rgw", Namespaces: []string{"rook"}, Sidecar: &corev1.Container{Image: "prysm:1"}}}
+	if err := prepareRules(rules); err != nil {
+		t.Fatalf("prepare rules: %v", err)
+	}
+	sidecar := rules[0].Sidecar
+	if sidecar.Name != sidecarContainer.Name || sidecar.Image != "prysm:1" {
+		t.Fatalf("unexpected sidecar %s %s", sidecar.Name, sidecar.Image)
+	}
+	if len(sidecar.Args) != len(sidecarContainer.Args) || len(sidecar.VolumeMounts) != len(sidecarContainer.VolumeMounts) {
+		t.Fatalf("sidecar not completed from the default: %+v", sidecar)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	content := `{"rules": [{"name": "rgw", "namespaces": ["rook"], "sidecar": {"image": "prysm:1"}}]}`
+	if err := os.WriteFile(rulesPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	rules, err := loadRules(rulesPath)
+	if err != nil || len(rules) != 1 || rules[0].Name != "rgw" {
+		t.Fatalf("unexpected rules %+v: %v", rules, err)
+	}
+
+	if err := os.WriteFile(rulesPath, []byte(`{"rules": [{"name": "rgw"}]}`), 0o600); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	if _, err := loadRules(rulesPath); err == nil {
+		t.Fatalf("expected the invalid rules to be rejected")
+	}
+
+	image := sidecarContainer.Image
+	sidecarContainer.Image = "prysm:default"
+	t.Cleanup(func() { sidecarContainer.Image = image })
+	rules, err = loadRules("")
+	if err != nil || len(rules) != 1 || rules[0].Name != "radosgw" || rules[0].Sidecar.Image != "prysm:default" {
+		t.Fatalf("expected the default rule, got %+v: %v", rules, err)
+	}
+}
+
+func TestMatchRule(t *testing.T) {
+	useRules(t, []InjectionRule{
+		{Name: "selected", Namespaces: []string{"rook-*"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "rgw"}}, Env: []corev1.EnvVar{{Name: "A", Value: "1"}}},
+		{Name: "namespace", Namespaces: []string{"rook-ceph"}, Env: []corev1.EnvVar{{Name: "B", Value: "2"}}},
+		{Name: "labels", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "storage"}}, Env: []corev1.EnvVar{{Name: "C", Value: "3"}}},
+	})
+
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		rule      string
+	}{
+		{"rook-ceph", map[string]string{"app": "rgw"}, "selected"},
+		{"rook-ceph", map[string]string{"app": "mon"}, "namespace"},
+		{"rook-other", map[string]string{"app": "rgw"}, "selected"},
+		{"rook-other", nil, ""},
+		{"shop", map[string]string{"team": "storage"}, "labels"},
+		{"shop", map[string]string{"app": "rgw"}, ""},
+	}
+	for _, test := range tests {
+		rule := matchRule(test.namespace, test.labels)
+		name := ""
+		if rule != nil {
+			name = rule.Name
+		}
+		if name != test.rule {
+			t.Errorf("%s %v: expected rule %q, got %q", test.namespace, test.labels, test.rule, name)
+		}
+	}
+}
+
+func TestMutateDeployment_InjectsSidecarAndEnv(t *testing.T) {
+	useRules(t, []InjectionRule{{
+		Name:       "logging",
+		Namespaces: []string{"logging"},
+		Sidecar:    &corev1.Container{Name: "fluent-bit", Image: "fluent-bit:3"},
+		Env:        []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+		Containers: []string{"app"},
+	}})
+
+	deployment := deploymentWith("logging", map[string]string{sidecarEnvSecretAnnotation: "creds"}, "app", "proxy")
+	deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}
+	patches, decision := admit(t, deployment)
+	if decision.result != resultMutated || decision.rule != "logging" {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+
+	var containers []corev1.Container
+	value, _ := json.Marshal(patches["/spec/template/spec/containers"]["value"])
+	if err := json.Unmarshal(value, &containers); err != nil {
+		t.Fatalf("unmarshal containers: %v", err)
+	}
+	if names := containerNames(corev1.PodSpec{Containers: containers}); strings.Join(names, ",") != "app,proxy,fluent-bit" {
+		t.Fatalf("unexpected containers %v", names)
+	}
+	if env := containers[0].Env; len(env) != 1 || env[0].Value != "debug" {
+		t.Fatalf("expected the env of the rule to replace the variable: %v", env)
+	}
+	if len(containers[1].Env) != 0 {
+		t.Fatalf("env injected into a container not named by the rule: %v", containers[1].Env)
+	}
+	if envFrom := containers[2].EnvFrom; len(envFrom) != 1 || envFrom[0].SecretRef == nil || envFrom[0].SecretRef.Name != "creds" {
+		t.Fatalf("expected the annotated Secret in the sidecar envFrom: %+v", envFrom)
+	}
+	annotations := patches["/spec/template/metadata/annotations"]["value"].(map[string]any)
+	if annotations[injectedSidecarAnnotation] != "fluent-bit" {
+		t.Fatalf("expected the injected sidecar to be recorded: %v", annotations)
+	}
+}
+
+func TestMutateDeployment_UnrelatedDeploymentKeepsContainers(t *testing.T) {
+	useRules(t, []InjectionRule{{
+		Name:       "logging",
+		Namespaces: []string{"logging"},
+		Sidecar:    &corev1.Container{Name: "fluent-bit", Image: "fluent-bit:3"},
+	}})
+
+	// A container named after the sidecar of a rule that does not match
+	unrelated := deploymentWith("shop", nil, "app", "fluent-bit")
+	unrelated.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: opsLogSocketVolume}}
+	if patches, decision := admit(t, unrelated); len(patches) > 0 || decision.result != resultSkipped {
+		t.Fatalf("unrelated deployment mutated: %+v %v", decision, patches)
+	}
+	spec := injectPodSpec(nil, unrelated.Spec.Template, injectedSidecar(unrelated))
+	if names := containerNames(spec); len(names) != 2 || names[1] != "fluent-bit" {
+		t.Fatalf("containers of the unrelated deployment changed: %v", names)
+	}
+	if len(spec.Volumes) != 1 {
+		t.Fatalf("volumes of the unrelated deployment changed: %v", spec.Volumes)
+	}
+}
+
+func TestMutateDeployment_RemovesOnlyInjectedSidecar(t *testing.T) {
+	useRules(t, []InjectionRule{{
+		Name:       "logging",
+		Namespaces: []string{"logging"},
+		Sidecar:    &corev1.Container{Name: "fluent-bit", Image: "fluent-bit:3"},
+	}})
+
+	matched := deploymentWith("logging", map[string]string{"team": "infra"}, "app")
+	if _, decision := admit(t, matched); decision.result != resultMutated || decision.patches != 2 {
+		t.Fatalf("expected the sidecar and its annotation to be added: %+v", decision)
+	}
+	annotations := injectedAnnotations(matchRule("logging", nil), matched.Spec.Template.Annotations)
+	if annotations[injectedSidecarAnnotation] != "fluent-bit" || annotations["team"] != "infra" {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+
+	// The rule no longer matches the deployment mutated before
+	mutated := deploymentWith("moved", annotations, "app", "fluent-bit")
+	if _, decision := admit(t, mutated); decision.result != resultMutated || decision.reason != "sidecar removed" {
+		t.Fatalf("expected the injected sidecar to be removed: %+v", decision)
+	}
+	if names := containerNames(injectPodSpec(nil, mutated.Spec.Template, injectedSidecar(mutated))); len(names) != 1 || names[0] != "app" {
+		t.Fatalf("unexpected containers %v", names)
+	}
+	if annotations := injectedAnnotations(nil, mutated.Spec.Template.Annotations); len(annotations) != 1 || annotations["team"] != "infra" {
+		t.Fatalf("expected only the record of the sidecar to be removed: %v", annotations)
+	}
+}
+
+func TestInjectedSidecar_RookRGWBeforeAnnotation(t *testing.T) {
+	deployment := deploymentWith("rook-ceph", nil, "rgw", sidecarContainer.Name)
+	deployment.Labels = map[string]string{}
+	for key, value := range rookRGWLabels {
+		deployment.Labels[key] = value
+	}
+	if name := injectedSidecar(deployment); name != sidecarContainer.Name {
+		t.Fatalf("expected the default sidecar of an RGW deployment, got %q", name)
+	}
+
+	delete(deployment.Labels, "app")
+	if name := injectedSidecar(deployment); name != "" {
+		t.Fatalf("expected no injected sidecar, got %q", name)
+	}
+}
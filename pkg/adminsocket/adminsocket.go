@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package adminsocket runs commands on the admin sockets of Ceph daemons
+package adminsocket
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Largest admin socket response read, perf dump of an OSD is some 100 KiB
+const maxResponseSize = 64 << 20
+
+// Command runs a command on a Ceph admin socket, like ceph daemon does:
+// the JSON request is terminated by a NUL byte, the response is prefixed
+// with its length as a big-endian uint32
+func Command(ctx context.Context, path, prefix string) ([]byte, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	request, err := json.Marshal(map[string]string{"prefix": prefix, "format": "json"})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(request, 0)); err != nil {
+		return nil, fmt.Errorf("failed to send %q: %w", prefix, err)
+	}
+
+	var length uint32
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("failed to read the response to %q: %w", prefix, err)
+	}
+	if length > maxResponseSize {
+		return nil, fmt.Errorf("response to %q of %d bytes is too large", prefix, length)
+	}
+	response := make([]byte, length)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, fmt.Errorf("failed to read the response to %q: %w", prefix, err)
+	}
+	return response, nil
+}
@@ -0,0 +1,433 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package aggregate merges the ops-log metrics the sidecars of many nodes
+// publish to NATS into one set of Prometheus counters, for installations
+// too small for a Thanos or a federation of Prometheus servers.
+package aggregate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSubject is the subject the ops-log sidecars publish their metrics
+// on; with a log file, they publish on DefaultSubject.metrics
+const DefaultSubject = "rgw.s3.ops.aggregated.metrics"
+
+// DefaultStaleAfter is the time a sidecar is counted as reporting after its
+// last metrics
+const DefaultStaleAfter = 5 * time.Minute
+
+// Parts of the keys of the ops-log metrics that are not a label as is
+const (
+	partUser   = "user$tenant"  // "user$tenant", the user and tenant labels
+	partOwner  = "owner$tenant" // "user$tenant", the tenant label only
+	partUnused = "-"            // Summed up
+)
+
+// family is a metric of the sidecars, with the labels of its keys but the
+// pod, summed over all sidecars
+type family struct {
+	name   string
+	help   string
+	parts  []string // Of the "|" separated keys
+	values func(m *opslog.AggregatedMetrics) map[string]uint64
+	gauge  bool    // Served as a gauge of the total, like by the sidecars
+	scale  float64 // Of the values, 1 if 0
+
+	desc   *prometheus.Desc
+	labels []string
+}
+
+var families = []*family{
+	{name: "radosgw_total_requests", help: "Number of requests by user, bucket, method and status",
+		parts: []string{partUser, "bucket", "method", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsDetailed }},
+	{name: "radosgw_total_requests_per_user", help: "Number of requests by user, method and status",
+		parts: []string{partUser, partUnused, "method", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByUser }},
+	{name: "radosgw_total_requests_per_bucket", help: "Number of requests by bucket, method and status",
+		parts: []string{"bucket", partOwner, "method", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByBucket }},
+	{name: "radosgw_total_requests_per_tenant", help: "Number of requests by tenant, method and status",
+		parts: []string{"tenant", "method", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByTenant }},
+
+	{name: "radosgw_requests_by_method", help: "Number of requests by user, bucket and method",
+		parts: []string{partUser, "bucket", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByMethodDetailed }},
+	{name: "radosgw_requests_by_method_per_user", help: "Number of requests by user and method",
+		parts: []string{partUser, "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByMethodPerUser }},
+	{name: "radosgw_requests_by_method_per_bucket", help: "Number of requests by bucket and method",
+		parts: []string{"tenant", "bucket", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByMethodPerBucket }},
+	{name: "radosgw_requests_by_method_per_tenant", help: "Number of requests by tenant and method",
+		parts: []string{"tenant", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByMethodPerTenant }},
+	{name: "radosgw_requests_by_method_global", help: "Number of requests by method",
+		parts: []string{"method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByMethodGlobal }},
+
+	{name: "radosgw_requests_by_operation", help: "Number of requests by user, bucket, operation and method",
+		parts: []string{partUser, "bucket", "operation", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByOperationDetailed }},
+	{name: "radosgw_requests_by_operation_per_user", help: "Number of requests by user, operation and method",
+		parts: []string{partUser, "operation", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByOperationPerUser }},
+	{name: "radosgw_requests_by_operation_per_bucket", help: "Number of requests by bucket, operation and method",
+		parts: []string{"tenant", "bucket", "operation", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByOperationPerBucket }},
+	{name: "radosgw_requests_by_operation_per_tenant", help: "Number of requests by tenant, operation and method",
+		parts: []string{"tenant", "operation", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByOperationPerTenant }},
+	{name: "radosgw_requests_by_operation_global", help: "Number of requests by operation and method",
+		parts: []string{"operation", "method"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByOperationGlobal }},
+
+	{name: "radosgw_requests_by_status_detailed", help: "Number of requests by user, bucket and status",
+		parts: []string{partUser, "bucket", "status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByStatusDetailed }},
+	{name: "radosgw_requests_by_status_per_user", help: "Number of requests by user and status",
+		parts: []string{partUser, "status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByStatusPerUser }},
+	{name: "radosgw_requests_by_status_per_bucket", help: "Number of requests by bucket and status",
+		parts: []string{"tenant", "bucket", "status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByStatusPerBucket }},
+	{name: "radosgw_requests_by_status_per_tenant", help: "Number of requests by tenant and status",
+		parts: []string{"tenant", "status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByStatusPerTenant }},
+	{name: "radosgw_requests_per_status", help: "Number of requests by status",
+		parts: []string{"http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsPerStatusCode }},
+
+	{name: "radosgw_requests_by_ip", help: "Total number of requests per IP and user",
+		parts: []string{partUser, "ip"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByIPDetailed }, gauge: true},
+	{name: "radosgw_requests_by_ip_bucket_method_tenant", help: "Total requests grouped by IP, bucket, method, and tenant",
+		parts: []string{"ip", "bucket", "method", "tenant"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByIPBucketMethodTenant }, gauge: true},
+	{name: "radosgw_bytes_sent_by_ip", help: "Total bytes sent per IP and user",
+		parts: []string{partUser, "ip"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentByIPDetailed }, gauge: true},
+	{name: "radosgw_bytes_received_by_ip", help: "Total bytes received per IP and user",
+		parts: []string{partUser, "ip"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedByIPDetailed }, gauge: true},
+
+	{name: "radosgw_bytes_sent", help: "Bytes sent by user and bucket",
+		parts: []string{partUser, "bucket"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentDetailed }},
+	{name: "radosgw_bytes_received", help: "Bytes received by user and bucket",
+		parts: []string{partUser, "bucket"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedDetailed }},
+	{name: "radosgw_bytes_sent_per_user", help: "Bytes sent by user",
+		parts: []string{partUser}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentPerUser }},
+	{name: "radosgw_bytes_received_per_user", help: "Bytes received by user",
+		parts: []string{partUser}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedPerUser }},
+	{name: "radosgw_bytes_sent_per_bucket", help: "Bytes sent by bucket",
+		parts: []string{"tenant", "bucket"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentPerBucket }},
+	{name: "radosgw_bytes_received_per_bucket", help: "Bytes received by bucket",
+		parts: []string{"tenant", "bucket"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedPerBucket }},
+	{name: "radosgw_bytes_sent_per_tenant", help: "Bytes sent by tenant",
+		parts: []string{"tenant"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentPerTenant }},
+	{name: "radosgw_bytes_received_per_tenant", help: "Bytes received by tenant",
+		parts: []string{"tenant"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedPerTenant }},
+
+	{name: "radosgw_errors_detailed", help: "Number of errors by user, bucket and status",
+		parts: []string{partUser, "bucket", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsDetailed }},
+	{name: "radosgw_errors_per_user", help: "Number of errors by user and status",
+		parts: []string{partUser, "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsPerUser }},
+	{name: "radosgw_errors_per_bucket", help: "Number of errors by bucket and status",
+		parts: []string{"tenant", "bucket", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsPerBucket }},
+	{name: "radosgw_errors_per_tenant", help: "Number of errors by tenant and status",
+		parts: []string{"tenant", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsPerTenant }},
+	{name: "radosgw_errors_per_status", help: "Number of errors by status",
+		parts: []string{"http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsPerStatus }},
+	{name: "radosgw_errors_per_ip", help: "Number of errors by IP, tenant and status",
+		parts: []string{"ip", "tenant", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsPerIP }},
+	{name: "radosgw_timeout_errors", help: "Number of timeout errors by user, bucket and timeout type",
+		parts: []string{partUser, "bucket", "timeout_type"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.TimeoutErrors }},
+	{name: "radosgw_errors_by_category", help: "Number of errors by bucket, category and status",
+		parts: []string{"tenant", "bucket", "error_category", "http_status"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.ErrorsByCategory }},
+
+	// Published by the sidecars with --track-api-categories only, not served by them
+	{name: "radosgw_requests_by_category_per_user", help: "Number of requests by user and API category",
+		parts: []string{partUser, "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByCategoryPerUser }},
+	{name: "radosgw_request_time_seconds_by_category_per_user", help: "Time spent serving the requests by user and API category",
+		parts: []string{partUser, "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestTimeMsByCategoryPerUser }, scale: 0.001},
+	{name: "radosgw_bytes_sent_by_category_per_user", help: "Bytes sent by user and API category",
+		parts: []string{partUser, "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentByCategoryPerUser }},
+	{name: "radosgw_bytes_received_by_category_per_user", help: "Bytes received by user and API category",
+		parts: []string{partUser, "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedByCategoryPerUser }},
+	{name: "radosgw_requests_by_category_per_bucket", help: "Number of requests by bucket and API category",
+		parts: []string{"tenant", "bucket", "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByCategoryPerBucket }},
+	{name: "radosgw_request_time_seconds_by_category_per_bucket", help: "Time spent serving the requests by bucket and API category",
+		parts: []string{"tenant", "bucket", "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestTimeMsByCategoryPerBucket }, scale: 0.001},
+	{name: "radosgw_bytes_sent_by_category_per_bucket", help: "Bytes sent by bucket and API category",
+		parts: []string{"tenant", "bucket", "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentByCategoryPerBucket }},
+	{name: "radosgw_bytes_received_by_category_per_bucket", help: "Bytes received by bucket and API category",
+		parts: []string{"tenant", "bucket", "category"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedByCategoryPerBucket }},
+}
+
+// bucketTagFamilies are the metrics by bucket owner and tags. The labels of
+// the tags are added per selection of tags of the sidecars.
+var bucketTagFamilies = []*family{
+	{name: "radosgw_requests_by_bucket_tags", help: "Total requests aggregated per bucket owner and bucket tags",
+		parts: []string{"owner"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.RequestsByBucketTags }},
+	{name: "radosgw_bytes_sent_by_bucket_tags", help: "Total bytes sent aggregated per bucket owner and bucket tags",
+		parts: []string{"owner"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesSentByBucketTags }},
+	{name: "radosgw_bytes_received_by_bucket_tags", help: "Total bytes received aggregated per bucket owner and bucket tags",
+		parts: []string{"owner"}, values: func(m *opslog.AggregatedMetrics) map[string]uint64 { return m.BytesReceivedByBucketTags }},
+}
+
+func init() {
+	for _, f := range append(families, bucketTagFamilies...) {
+		f.init()
+	}
+}
+
+// init derives the labels and the description of the family from its parts
+func (f *family) init() {
+	f.labels = nil
+	for _, part := range f.parts {
+		switch part {
+		case partUser:
+			f.labels = append(f.labels, "user", "tenant")
+		case partOwner:
+			f.labels = append(f.labels, "tenant")
+		case partUnused:
+		default:
+			f.labels = append(f.labels, part)
+		}
+	}
+	f.desc = prometheus.NewDesc(f.name, f.help+", summed over all nodes", f.labels, nil)
+}
+
+// withTags returns the family by bucket tags with a label per tag, e.g.
+// tag_cost_center, as served by the sidecars selecting the tags
+func (f *family) withTags(tags []string) *family {
+	tagged := &family{name: f.name, help: f.help, parts: slices.Clone(f.parts), values: f.values}
+	for _, tag := range tags {
+		tagged.parts = append(tagged.parts, opslog.TagLabel(tag))
+	}
+	tagged.init()
+	return tagged
+}
+
+// value is the sample of a total of the family
+func (f *family) value(total uint64) float64 {
+	if f.scale != 0 {
+		return float64(total) * f.scale
+	}
+	return float64(total)
+}
+
+func (f *family) valueType() prometheus.ValueType {
+	if f.gauge {
+		return prometheus.GaugeValue
+	}
+	return prometheus.CounterValue
+}
+
+// labelValues returns the label values of a key of the family, false if the
+// key does not have the parts of the family
+func (f *family) labelValues(key string) ([]string, bool) {
+	parts := strings.Split(key, "|")
+	if len(parts) != len(f.parts) {
+		return nil, false
+	}
+	values := make([]string, 0, len(f.labels))
+	for i, part := range f.parts {
+		switch part {
+		case partUser, partOwner:
+			user, tenant, ok := strings.Cut(parts[i], "$")
+			if !ok {
+				tenant = "none"
+			}
+			if part == partUser {
+				values = append(values, user)
+			}
+			values = append(values, tenant)
+		case partUnused:
+		default:
+			values = append(values, parts[i])
+		}
+	}
+	return values, true
+}
+
+// node is a sidecar publishing metrics
+type node struct {
+	host       string
+	windowEnd  time.Time // Of the last metrics merged
+	lastUpdate time.Time
+}
+
+// Aggregator sums the metrics of the windows of all sidecars. It is a
+// prometheus.Collector of the sums and of its own metrics.
+type Aggregator struct {
+	staleAfter time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	totals  map[*family]map[string]uint64 // By the keys of the sidecars
+	tagged  map[string]*family            // Families by bucket tags, by name and tags
+	nodes   map[string]*node              // By instance ID
+	merged  uint64
+	skipped uint64 // Windows merged before, e.g. redelivered
+
+	nodesDesc   *prometheus.Desc
+	windowsDesc *prometheus.Desc
+	lastDesc    *prometheus.Desc
+}
+
+// New returns an empty aggregator counting a sidecar as reporting until
+// staleAfter after its last metrics
+func New(staleAfter time.Duration) *Aggregator {
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	return &Aggregator{
+		staleAfter: staleAfter,
+		now:        time.Now,
+		totals:     make(map[*family]map[string]uint64, len(families)),
+		tagged:     make(map[string]*family),
+		nodes:      make(map[string]*node),
+		nodesDesc: prometheus.NewDesc("prysm_aggregate_nodes",
+			"Ops-log sidecars whose metrics were merged within the stale time", nil, nil),
+		windowsDesc: prometheus.NewDesc("prysm_aggregate_windows_total",
+			"Metric windows of the sidecars received, by result (merged, duplicate)", []string{"result"}, nil),
+		lastDesc: prometheus.NewDesc("prysm_aggregate_last_window_timestamp_seconds",
+			"End of the last metric window merged of a sidecar", []string{"instance_id", "host"}, nil),
+	}
+}
+
+// Add merges the metrics of a window, summing them into the totals. A window
+// of a sidecar that does not end after the last one merged is skipped, so
+// redelivered metrics are not counted twice. Metrics without instance ID
+// are always merged.
+func (a *Aggregator) Add(metrics opslog.AggregatedMetrics) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if metrics.InstanceID != "" {
+		n, ok := a.nodes[metrics.InstanceID]
+		if !ok {
+			n = &node{}
+			a.nodes[metrics.InstanceID] = n
+		}
+		if !metrics.WindowEnd.IsZero() && !metrics.WindowEnd.After(n.windowEnd) {
+			a.skipped++
+			return false
+		}
+		n.host = metrics.Host
+		n.windowEnd = metrics.WindowEnd
+		n.lastUpdate = a.now()
+	}
+
+	for _, f := range families {
+		a.merge(f, f.values(&metrics))
+	}
+	for _, f := range bucketTagFamilies {
+		if values := f.values(&metrics); len(values) > 0 {
+			a.merge(a.taggedFamily(f, metrics.BucketTagKeys), values)
+		}
+	}
+	a.merged++
+	return true
+}
+
+// merge sums the values of a window into the totals of the family
+func (a *Aggregator) merge(f *family, values map[string]uint64) {
+	if len(values) == 0 {
+		return
+	}
+	totals, ok := a.totals[f]
+	if !ok {
+		totals = make(map[string]uint64, len(values))
+		a.totals[f] = totals
+	}
+	for key, value := range values {
+		totals[key] += value
+	}
+}
+
+// taggedFamily returns the family by bucket tags with the labels of the
+// tags, so sidecars selecting other tags are served apart
+func (a *Aggregator) taggedFamily(f *family, tags []string) *family {
+	id := f.name + "|" + strings.Join(tags, "|")
+	tagged, ok := a.tagged[id]
+	if !ok {
+		tagged = f.withTags(tags)
+		a.tagged[id] = tagged
+	}
+	return tagged
+}
+
+// Subscribe merges the metrics published on the subjects
+func (a *Aggregator) Subscribe(nc *nats.Conn, subjects ...string) ([]*nats.Subscription, error) {
+	handler := func(msg *nats.Msg) {
+		var metrics opslog.AggregatedMetrics
+		if err := schema.Unmarshal(msg, schema.OpsMetrics, &metrics); err != nil {
+			log.Warn().Err(err).Str("subject", msg.Subject).Msg("Failed to decode ops log metrics")
+			return
+		}
+		if !a.Add(metrics) {
+			log.Debug().Str("instance_id", metrics.InstanceID).Time("window_end", metrics.WindowEnd).Msg("Metrics window merged before, skipped")
+		}
+	}
+
+	subs := make([]*nats.Subscription, 0, len(subjects))
+	for _, subject := range subjects {
+		sub, err := nc.Subscribe(subject, handler)
+		if err != nil {
+			for _, s := range subs {
+				_ = s.Unsubscribe()
+			}
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		subs = append(subs, sub)
+	}
+	log.Info().Strs("subjects", subjects).Msg("Aggregating the ops log metrics of the sidecars")
+	return subs, nil
+}
+
+// Describe implements prometheus.Collector
+func (a *Aggregator) Describe(ch chan<- *prometheus.Desc) {
+	for _, f := range append(families, bucketTagFamilies...) {
+		ch <- f.desc
+	}
+	ch <- a.nodesDesc
+	ch <- a.windowsDesc
+	ch <- a.lastDesc
+}
+
+// Collect implements prometheus.Collector. Keys of the sidecars that only
+// differ in a part that is not a label are summed up. The families by bucket
+// tags share the description of their name whatever the tags, so they pass
+// the checks of the registry.
+func (a *Aggregator) Collect(ch chan<- prometheus.Metric) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for f, totals := range a.totals {
+		sums := make(map[string]uint64)
+		labels := make(map[string][]string)
+		for key, value := range totals {
+			values, ok := f.labelValues(key)
+			if !ok {
+				continue
+			}
+			id := strings.Join(values, "\xff")
+			sums[id] += value
+			labels[id] = values
+		}
+		for id, sum := range sums {
+			metric, err := prometheus.NewConstMetric(f.desc, f.valueType(), f.value(sum), labels[id]...)
+			if err != nil {
+				continue // A label that is not UTF-8
+			}
+			ch <- metric
+		}
+	}
+
+	reporting := 0
+	now := a.now()
+	for instanceID, n := range a.nodes {
+		if now.Sub(n.lastUpdate) <= a.staleAfter {
+			reporting++
+		}
+		if !n.windowEnd.IsZero() {
+			ch <- prometheus.MustNewConstMetric(a.lastDesc, prometheus.GaugeValue, float64(n.windowEnd.Unix()), instanceID, n.host)
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(a.nodesDesc, prometheus.GaugeValue, float64(reporting))
+	ch <- prometheus.MustNewConstMetric(a.windowsDesc, prometheus.CounterValue, float64(a.merged), "merged")
+	ch <- prometheus.MustNewConstMetric(a.windowsDesc, prometheus.CounterValue, float64(a.skipped), "duplicate")
+}
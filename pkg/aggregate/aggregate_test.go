@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func window(instanceID string, end time.Time, requests map[string]uint64) opslog.AggregatedMetrics {
+	return opslog.AggregatedMetrics{
+		InstanceID:       instanceID,
+		Host:             "node-" + instanceID,
+		WindowStart:      end.Add(-time.Minute),
+		WindowEnd:        end,
+		RequestsByBucket: requests,
+		BytesSentPerTenant: map[string]uint64{
+			"acme": 100,
+		},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	a := New(5 * time.Minute)
+	a.now = func() time.Time { return start }
+
+	assert.True(t, a.Add(window("rgw-a", start, map[string]uint64{
+		"photos|alice$acme|GET|200": 10,
+		"photos|bob$acme|GET|200":   5, // The same labels as alice's, summed up
+	})))
+	assert.True(t, a.Add(window("rgw-b", start, map[string]uint64{"photos|alice$acme|GET|200": 7})))
+	assert.True(t, a.Add(window("rgw-a", start.Add(time.Minute), map[string]uint64{"logs|carol|PUT|201": 1})))
+	assert.False(t, a.Add(window("rgw-a", start.Add(time.Minute), map[string]uint64{"logs|carol|PUT|201": 1})), "merged before")
+
+	expected := `
+# HELP radosgw_bytes_sent_per_tenant Bytes sent by tenant, summed over all nodes
+# TYPE radosgw_bytes_sent_per_tenant counter
+radosgw_bytes_sent_per_tenant{tenant="acme"} 300
+# HELP radosgw_total_requests_per_bucket Number of requests by bucket, method and status, summed over all nodes
+# TYPE radosgw_total_requests_per_bucket counter
+radosgw_total_requests_per_bucket{bucket="logs",http_status="201",method="PUT",tenant="none"} 1
+radosgw_total_requests_per_bucket{bucket="photos",http_status="200",method="GET",tenant="acme"} 22
+# HELP prysm_aggregate_nodes Ops-log sidecars whose metrics were merged within the stale time
+# TYPE prysm_aggregate_nodes gauge
+prysm_aggregate_nodes 2
+# HELP prysm_aggregate_windows_total Metric windows of the sidecars received, by result (merged, duplicate)
+# TYPE prysm_aggregate_windows_total counter
+prysm_aggregate_windows_total{result="duplicate"} 1
+prysm_aggregate_windows_total{result="merged"} 3
+`
+	require.NoError(t, testutil.CollectAndCompare(a, strings.NewReader(expected),
+		"radosgw_bytes_sent_per_tenant", "radosgw_total_requests_per_bucket", "prysm_aggregate_nodes", "prysm_aggregate_windows_total"))
+
+	// No sidecar reported within the stale time
+	a.now = func() time.Time { return start.Add(6 * time.Minute) }
+	require.NoError(t, testutil.CollectAndCompare(a, strings.NewReader(`
+# HELP prysm_aggregate_nodes Ops-log sidecars whose metrics were merged within the stale time
+# TYPE prysm_aggregate_nodes gauge
+prysm_aggregate_nodes 0
+`), "prysm_aggregate_nodes"))
+}
+
+func TestFamilyLabelValues(t *testing.T) {
+	byUser := families[1] // radosgw_total_requests_per_user
+	require.Equal(t, []string{"user", "tenant", "method", "http_status"}, byUser.labels)
+
+	values, ok := byUser.labelValues("alice$acme|photos|GET|200")
+	require.True(t, ok)
+	assert.Equal(t, []string{"alice", "acme", "GET", "200"}, values)
+	_, ok = byUser.labelValues("alice|GET|200")
+	assert.False(t, ok, "missing part")
+}
+
+// Every map of the metrics of the sidecars is served
+func TestFamilies_CoverAggregatedMetrics(t *testing.T) {
+	all := append(families, bucketTagFamilies...)
+	metricsType := reflect.TypeOf(opslog.AggregatedMetrics{})
+	for i := range metricsType.NumField() {
+		field := metricsType.Field(i)
+		if field.Type != reflect.TypeOf(map[string]uint64{}) {
+			continue
+		}
+		var metrics opslog.AggregatedMetrics
+		reflect.ValueOf(&metrics).Elem().Field(i).Set(reflect.ValueOf(map[string]uint64{"key": 1}))
+
+		covered := 0
+		for _, f := range all {
+			if len(f.values(&metrics)) > 0 {
+				covered++
+			}
+		}
+		assert.Equal(t, 1, covered, "families of %s", field.Name)
+	}
+}
+
+// sidecarWindow is the metrics window of a sidecar counting the entries,
+// with every --track-* option enabled
+func sidecarWindow(instanceID string, end time.Time, entries ...opslog.S3OperationLog) opslog.AggregatedMetrics {
+	cfg := &opslog.MetricsConfig{BucketTags: opslog.NewBucketTagCache([]string{"cost-center"})}
+	options := reflect.ValueOf(cfg).Elem()
+	for i := range options.NumField() {
+		if strings.HasPrefix(options.Type().Field(i).Name, "Track") && options.Field(i).Kind() == reflect.Bool {
+			options.Field(i).SetBool(true)
+		}
+	}
+	m := opslog.NewMetrics()
+	for _, entry := range entries {
+		m.Update(entry, cfg)
+	}
+	metrics := m.Aggregate(cfg)
+	metrics.InstanceID, metrics.WindowEnd = instanceID, end
+	return metrics
+}
+
+func TestAggregator_SidecarMetrics(t *testing.T) {
+	end := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	get := opslog.S3OperationLog{User: "alice$acme", Bucket: "photos", RemoteAddr: "10.0.0.1",
+		URI: "GET /photos/cat.jpg HTTP/1.1", Operation: "get_obj", HTTPStatus: "200", BytesSent: 1024, TotalTime: 250}
+	timeout := opslog.S3OperationLog{User: "alice$acme", Bucket: "photos", RemoteAddr: "10.0.0.1",
+		URI: "PUT /photos/dog.jpg HTTP/1.1", Operation: "put_obj", HTTPStatus: "504", BytesReceived: 2048, TotalTime: 30000}
+	windows := []opslog.AggregatedMetrics{
+		sidecarWindow("rgw-a", end, get, timeout),
+		sidecarWindow("rgw-b", end, get),
+	}
+
+	a := New(time.Minute)
+	for _, metrics := range windows {
+		require.True(t, a.Add(metrics))
+	}
+
+	// The keys of all maps of the sidecars have the parts of their family
+	served := make(map[string]bool)
+	for f, totals := range a.totals {
+		require.NotEmpty(t, totals, f.name)
+		for key := range totals {
+			_, ok := f.labelValues(key)
+			assert.True(t, ok, "key %q of %s", key, f.name)
+		}
+		served[f.name] = true
+	}
+	for _, f := range append(families, bucketTagFamilies...) {
+		assert.True(t, served[f.name], "%s not served", f.name)
+	}
+
+	expected := `
+# HELP radosgw_request_time_seconds_by_category_per_bucket Time spent serving the requests by bucket and API category, summed over all nodes
+# TYPE radosgw_request_time_seconds_by_category_per_bucket counter
+radosgw_request_time_seconds_by_category_per_bucket{bucket="photos",category="read",tenant="acme"} 0.5
+radosgw_request_time_seconds_by_category_per_bucket{bucket="photos",category="write",tenant="acme"} 30
+# HELP radosgw_requests_by_bucket_tags Total requests aggregated per bucket owner and bucket tags, summed over all nodes
+# TYPE radosgw_requests_by_bucket_tags counter
+radosgw_requests_by_bucket_tags{owner="",tag_cost_center=""} 3
+# HELP radosgw_requests_by_ip Total number of requests per IP and user, summed over all nodes
+# TYPE radosgw_requests_by_ip gauge
+radosgw_requests_by_ip{ip="10.0.0.1",tenant="acme",user="alice"} 3
+# HELP radosgw_timeout_errors Number of timeout errors by user, bucket and timeout type, summed over all nodes
+# TYPE radosgw_timeout_errors counter
+radosgw_timeout_errors{bucket="photos",tenant="acme",timeout_type="gateway_timeout",user="alice"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(a, strings.NewReader(expected),
+		"radosgw_request_time_seconds_by_category_per_bucket", "radosgw_requests_by_bucket_tags",
+		"radosgw_requests_by_ip", "radosgw_timeout_errors"))
+}
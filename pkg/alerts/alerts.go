@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alerts generates the Prometheus alerting rules of the metrics the
+// producers export with a configuration, with the thresholds the producers
+// use for their own events, so alerts never query metrics that are not
+// enabled.
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+)
+
+// Producers with alerting rules
+const (
+	ProducerOpsLog            = "ops-log"
+	ProducerRadosGWUsage      = "radosgw-usage"
+	ProducerDiskHealthMetrics = "disk-health-metrics"
+)
+
+// Producers lists the producers in the order their rule groups are generated
+var Producers = []string{ProducerOpsLog, ProducerRadosGWUsage, ProducerDiskHealthMetrics}
+
+// Defaults of the thresholds that are not options of a producer
+const (
+	DefaultAvailabilityObjective = 0.999
+	DefaultQuotaWarningRatio     = 0.9
+	DefaultSyncStalledCycles     = 3
+)
+
+// Severities of the rules
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Config holds the configuration of every producer rules are generated for,
+// as the producer would be started with, and the alerting thresholds
+type Config struct {
+	Producers    []string // all if empty
+	OpsLog       opslog.OpsLogConfig
+	RadosGWUsage radosgwusage.RadosGWUsageConfig
+	DiskHealth   diskhealthmetrics.DiskHealthMetricsConfig
+	// Metrics names the metrics as the producers serve them, with
+	// --metrics-prefix
+	Metrics metricnames.Config
+
+	// AvailabilityObjective is the ratio of bucket GET/LIST requests that
+	// must not fail with a 5xx, whose error budget the SLO burn rules watch
+	AvailabilityObjective float64
+	// QuotaWarningRatio is the ratio of a user or bucket quota whose use
+	// raises a quota alert
+	QuotaWarningRatio float64
+	// SyncStalledCycles is the number of collection cycles of radosgw-usage
+	// without one completing after which its sync is stalled
+	SyncStalledCycles int
+}
+
+// RuleFile is a Prometheus rule file
+type RuleFile struct {
+	Groups []Group `yaml:"groups"`
+}
+
+// Group is a rule group, one per producer
+type Group struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is an alerting rule
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Generate returns the rule groups of the selected producers. Producers
+// without an enabled rule get no group.
+func Generate(cfg Config) (RuleFile, error) {
+	if cfg.AvailabilityObjective <= 0 || cfg.AvailabilityObjective >= 1 {
+		return RuleFile{}, fmt.Errorf("availability objective %v must be between 0 and 1", cfg.AvailabilityObjective)
+	}
+	if cfg.QuotaWarningRatio <= 0 || cfg.QuotaWarningRatio > 1 {
+		return RuleFile{}, fmt.Errorf("quota warning ratio %v must be above 0 and at most 1", cfg.QuotaWarningRatio)
+	}
+	if cfg.SyncStalledCycles < 1 {
+		return RuleFile{}, fmt.Errorf("sync stalled cycles %d must be at least 1", cfg.SyncStalledCycles)
+	}
+
+	producers := cfg.Producers
+	if len(producers) == 0 {
+		producers = Producers
+	}
+
+	file := RuleFile{Groups: []Group{}}
+	for _, producer := range producers {
+		var rules []Rule
+		switch producer {
+		case ProducerOpsLog:
+			opsLog := cfg.OpsLog
+			opsLog.MetricsConfig.ApplyShortcuts()
+			rules = opsLogRules(opsLog, cfg)
+		case ProducerRadosGWUsage:
+			rules = radosGWUsageRules(cfg.RadosGWUsage, cfg)
+		case ProducerDiskHealthMetrics:
+			rules = diskHealthRules(cfg.DiskHealth)
+		default:
+			return RuleFile{}, fmt.Errorf("unknown producer %q, expected one of %v", producer, Producers)
+		}
+		for i := range rules {
+			rules[i].Expr = cfg.Metrics.Query(rules[i].Expr)
+		}
+		if len(rules) > 0 {
+			file.Groups = append(file.Groups, Group{Name: "prysm-" + producer, Rules: rules})
+		}
+	}
+	return file, nil
+}
+
+// rule returns an alerting rule of the severity
+func rule(alert, expr, forDuration, severity, summary, description string) Rule {
+	return Rule{
+		Alert:       alert,
+		Expr:        expr,
+		For:         forDuration,
+		Labels:      map[string]string{"severity": severity},
+		Annotations: map[string]string{"summary": summary, "description": description},
+	}
+}
+
+// number formats a threshold for PromQL without floating point noise
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
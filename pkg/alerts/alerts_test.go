@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alerts
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultConfig() Config {
+	return Config{
+		RadosGWUsage: radosgwusage.RadosGWUsageConfig{CooldownInterval: 120},
+		DiskHealth: diskhealthmetrics.DiskHealthMetricsConfig{
+			GrownDefectsThreshold:       10,
+			PendingSectorsThreshold:     3,
+			ReallocatedSectorsThreshold: 10,
+			LifetimeUsedThreshold:       80,
+			RiskWarningThreshold:        40,
+			RiskCriticalThreshold:       70,
+		},
+		AvailabilityObjective: DefaultAvailabilityObjective,
+		QuotaWarningRatio:     DefaultQuotaWarningRatio,
+		SyncStalledCycles:     DefaultSyncStalledCycles,
+	}
+}
+
+// rules returns the rules of the group by alert name
+func rules(t *testing.T, file RuleFile, group string) map[string]Rule {
+	t.Helper()
+	for _, g := range file.Groups {
+		if g.Name == group {
+			byName := map[string]Rule{}
+			for _, r := range g.Rules {
+				byName[r.Alert] = r
+			}
+			return byName
+		}
+	}
+	return nil
+}
+
+func TestGenerate_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{"objective of 1", func(c *Config) { c.AvailabilityObjective = 1 }, "availability objective"},
+		{"objective in percent", func(c *Config) { c.AvailabilityObjective = 99.9 }, "availability objective"},
+		{"no quota ratio", func(c *Config) { c.QuotaWarningRatio = 0 }, "quota warning ratio"},
+		{"no stalled cycles", func(c *Config) { c.SyncStalledCycles = 0 }, "sync stalled cycles"},
+		{"unknown producer", func(c *Config) { c.Producers = []string{"kernel-metrics"} }, `unknown producer "kernel-metrics"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			tt.modify(&cfg)
+			_, err := Generate(cfg)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestGenerate_Defaults(t *testing.T) {
+	file, err := Generate(defaultConfig())
+	require.NoError(t, err)
+
+	assert.Nil(t, rules(t, file, "prysm-ops-log"), "no SLI metrics without TRACK_BUCKET_SLO")
+	require.NotNil(t, rules(t, file, "prysm-radosgw-usage"))
+	require.NotNil(t, rules(t, file, "prysm-disk-health-metrics"))
+
+	seen := map[string]bool{}
+	for _, g := range file.Groups {
+		for _, r := range g.Rules {
+			assert.False(t, seen[r.Alert], "duplicate alert %s", r.Alert)
+			seen[r.Alert] = true
+			assert.Contains(t, []string{SeverityWarning, SeverityCritical}, r.Labels["severity"])
+			assert.NotEmpty(t, r.Annotations["summary"])
+		}
+	}
+}
+
+// unprefixedMetric matches the names of the producers' metrics without the
+// prefix they are served under
+var unprefixedMetric = regexp.MustCompile(`(^|[^\w:])(radosgw|disk|ssd)_\w+`)
+
+func TestGenerate_MetricPrefix(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.OpsLog = opslog.OpsLogConfig{MetricsConfig: opslog.MetricsConfig{TrackBucketSLO: true}}
+	cfg.DiskHealth.SelfTest = true
+	plain, err := Generate(cfg)
+	require.NoError(t, err)
+
+	cfg.Metrics = metricnames.Config{Prefix: metricnames.DefaultPrefix}
+	prefixed, err := Generate(cfg)
+	require.NoError(t, err)
+
+	require.Len(t, prefixed.Groups, 3)
+	for i, group := range prefixed.Groups {
+		for j, r := range group.Rules {
+			assert.NotRegexp(t, unprefixedMetric, r.Expr, r.Alert)
+			assert.Equal(t, cfg.Metrics.Query(plain.Groups[i].Rules[j].Expr), r.Expr, r.Alert)
+		}
+	}
+	assert.Equal(t, "time() - prysm_radosgw_usage_last_sync_timestamp_seconds > 360",
+		rules(t, prefixed, "prysm-radosgw-usage")["PrysmUsageSyncStalled"].Expr)
+	assert.Equal(t, "prysm_target_up == 0", rules(t, prefixed, "prysm-radosgw-usage")["PrysmAdminAPIDown"].Expr)
+}
+
+func TestGenerate_SLOBurn(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Producers = []string{ProducerOpsLog}
+	cfg.OpsLog = opslog.OpsLogConfig{MetricsConfig: opslog.MetricsConfig{TrackEverything: true}}
+
+	file, err := Generate(cfg)
+	require.NoError(t, err)
+	opsLog := rules(t, file, "prysm-ops-log")
+	require.Len(t, opsLog, 2)
+
+	fast := opsLog["PrysmBucketAvailabilityFastBurn"]
+	assert.Contains(t, fast.Expr, `radosgw_bucket_sli_requests_total{status_class="5xx"}[1h]`)
+	assert.Contains(t, fast.Expr, "> 0.0144 and")
+	assert.Equal(t, SeverityCritical, fast.Labels["severity"])
+	assert.Contains(t, opsLog["PrysmBucketAvailabilitySlowBurn"].Expr, "> 0.006 and")
+
+	cfg.AvailabilityObjective = 0.99
+	file, err = Generate(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, rules(t, file, "prysm-ops-log")["PrysmBucketAvailabilityFastBurn"].Expr, "> 0.144 and")
+}
+
+func TestGenerate_RadosGWUsage(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.QuotaWarningRatio = 0.8
+	cfg.SyncStalledCycles = 5
+
+	file, err := Generate(cfg)
+	require.NoError(t, err)
+	usage := rules(t, file, "prysm-radosgw-usage")
+
+	assert.Equal(t,
+		"(radosgw_usage_bucket_size / radosgw_usage_bucket_quota_size > 0.8) and (radosgw_usage_bucket_quota_enabled == 1)",
+		usage["PrysmBucketQuotaNearing"].Expr)
+	assert.Equal(t, "time() - radosgw_usage_last_sync_timestamp_seconds > 600", usage["PrysmUsageSyncStalled"].Expr)
+}
+
+func TestGenerate_DiskHealth(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Producers = []string{ProducerDiskHealthMetrics}
+	cfg.DiskHealth.RiskCriticalThreshold = 85.5
+
+	file, err := Generate(cfg)
+	require.NoError(t, err)
+	disk := rules(t, file, "prysm-disk-health-metrics")
+
+	assert.Equal(t, "disk_failure_risk_score >= 85.5", disk["PrysmDiskFailureRiskCritical"].Expr)
+	assert.Equal(t, "disk_failure_risk_score >= 40 < 85.5", disk["PrysmDiskFailureRiskWarning"].Expr)
+	assert.Equal(t, "disk_pending_sectors > 3", disk["PrysmDiskPendingSectors"].Expr)
+	assert.NotContains(t, disk, "PrysmDiskSelfTestFailed")
+
+	cfg.DiskHealth.SelfTest = true
+	file, err = Generate(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, rules(t, file, "prysm-disk-health-metrics"), "PrysmDiskSelfTestFailed")
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alerts
+
+import (
+	"fmt"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+)
+
+// Burn rates of the multiwindow alerts of the SRE workbook: the fast burn
+// spends 2% of a 30 day error budget in an hour, the slow burn 5% in 6 hours
+const (
+	fastBurnRate = 14.4
+	slowBurnRate = 6
+)
+
+// errorRatio is the ratio of bucket GET/LIST requests failing with a 5xx by
+// operation over the window
+func errorRatio(window string) string {
+	return fmt.Sprintf(`sum by (operation) (rate(radosgw_bucket_sli_requests_total{status_class="5xx"}[%s])) / sum by (operation) (rate(radosgw_bucket_sli_requests_total[%s]))`, window, window)
+}
+
+// burn is true while the error ratio of both windows exceeds the burn rate
+// of the error budget
+func burn(long, short string, rate, budget float64) string {
+	threshold := number(rate * budget)
+	return fmt.Sprintf("(%s) > %s and (%s) > %s", errorRatio(long), threshold, errorRatio(short), threshold)
+}
+
+// opsLogRules watches the error budget of the bucket SLIs
+func opsLogRules(c opslog.OpsLogConfig, cfg Config) []Rule {
+	if !c.MetricsConfig.TrackBucketSLO {
+		return nil
+	}
+	budget := 1 - cfg.AvailabilityObjective
+	objective := number(cfg.AvailabilityObjective * 100)
+	return []Rule{
+		rule("PrysmBucketAvailabilityFastBurn",
+			burn("1h", "5m", fastBurnRate, budget), "2m", SeverityCritical,
+			"Bucket {{ $labels.operation }} requests burn the error budget fast",
+			"{{ $labels.operation }} requests fail with 5xx at "+number(fastBurnRate)+" times the rate the "+objective+"% availability objective allows."),
+		rule("PrysmBucketAvailabilitySlowBurn",
+			burn("6h", "30m", slowBurnRate, budget), "15m", SeverityWarning,
+			"Bucket {{ $labels.operation }} requests burn the error budget",
+			"{{ $labels.operation }} requests fail with 5xx at "+number(slowBurnRate)+" times the rate the "+objective+"% availability objective allows."),
+	}
+}
+
+// radosGWUsageRules watches quotas nearing their limit and the sync of the
+// usage data
+func radosGWUsageRules(c radosgwusage.RadosGWUsageConfig, cfg Config) []Rule {
+	ratio := number(cfg.QuotaWarningRatio)
+	quota := func(used, limit, enabled string) string {
+		return fmt.Sprintf("(%s / %s > %s) and (%s == 1)", used, limit, ratio, enabled)
+	}
+	stalled := cfg.SyncStalledCycles * max(c.CooldownInterval, 1)
+
+	return []Rule{
+		rule("PrysmUserQuotaNearing",
+			quota("radosgw_user_data_size_bytes", "radosgw_usage_user_quota_size", "radosgw_usage_user_quota_enabled"), "15m", SeverityWarning,
+			"User {{ $labels.user }} nears its size quota",
+			"User {{ $labels.user }} uses {{ $value | humanizePercentage }} of its size quota."),
+		rule("PrysmUserObjectQuotaNearing",
+			quota("radosgw_user_objects_total", "radosgw_usage_user_quota_size_objects", "radosgw_usage_user_quota_enabled"), "15m", SeverityWarning,
+			"User {{ $labels.user }} nears its object quota",
+			"User {{ $labels.user }} uses {{ $value | humanizePercentage }} of its object quota."),
+		rule("PrysmBucketQuotaNearing",
+			quota("radosgw_usage_bucket_size", "radosgw_usage_bucket_quota_size", "radosgw_usage_bucket_quota_enabled"), "15m", SeverityWarning,
+			"Bucket {{ $labels.bucket }} nears its size quota",
+			"Bucket {{ $labels.bucket }} of {{ $labels.owner }} uses {{ $value | humanizePercentage }} of its size quota."),
+		rule("PrysmBucketObjectQuotaNearing",
+			quota("radosgw_usage_bucket_objects", "radosgw_usage_bucket_quota_size_objects", "radosgw_usage_bucket_quota_enabled"), "15m", SeverityWarning,
+			"Bucket {{ $labels.bucket }} nears its object quota",
+			"Bucket {{ $labels.bucket }} of {{ $labels.owner }} uses {{ $value | humanizePercentage }} of its object quota."),
+		rule("PrysmUsageSyncStalled",
+			fmt.Sprintf("time() - radosgw_usage_last_sync_timestamp_seconds > %d", stalled), "5m", SeverityWarning,
+			"radosgw-usage on {{ $labels.node }} stopped syncing",
+			fmt.Sprintf("No collection cycle completed for %d cycles of %ds, the usage metrics are stale.", cfg.SyncStalledCycles, c.CooldownInterval)),
+		rule("PrysmAdminAPIDown",
+			"prysm_target_up == 0", "10m", SeverityWarning,
+			"radosgw-usage cannot reach the RadosGW admin API",
+			"The admin API of {{ $labels.instance }} is unreachable, the usage data is not synced."),
+	}
+}
+
+// diskHealthRules watches disks failing, with the thresholds of the events
+// of disk-health-metrics
+func diskHealthRules(c diskhealthmetrics.DiskHealthMetricsConfig) []Rule {
+	rules := []Rule{
+		rule("PrysmDiskFailureRiskCritical",
+			fmt.Sprintf("disk_failure_risk_score >= %s", number(c.RiskCriticalThreshold)), "30m", SeverityCritical,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} is likely to fail",
+			"The failure risk score of {{ $labels.disk }} (OSD {{ $labels.osd_id }}) is {{ $value }}, replace the disk."),
+		rule("PrysmDiskFailureRiskWarning",
+			fmt.Sprintf("disk_failure_risk_score >= %s < %s", number(c.RiskWarningThreshold), number(c.RiskCriticalThreshold)), "1h", SeverityWarning,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} shows signs of failing",
+			"The failure risk score of {{ $labels.disk }} (OSD {{ $labels.osd_id }}) is {{ $value }}."),
+		rule("PrysmDiskTemperatureCritical",
+			"disk_temperature_alert_level == 2", "10m", SeverityCritical,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} is overheating",
+			"The {{ $labels.media_type }} disk {{ $labels.disk }} is above its critical temperature."),
+		rule("PrysmDiskPendingSectors",
+			fmt.Sprintf("disk_pending_sectors > %d", c.PendingSectorsThreshold), "30m", SeverityWarning,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} has pending sectors",
+			"{{ $labels.disk }} has {{ $value }} sectors waiting to be reallocated."),
+		rule("PrysmDiskReallocatedSectors",
+			fmt.Sprintf("disk_reallocated_sectors > %d", c.ReallocatedSectorsThreshold), "30m", SeverityWarning,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} reallocated sectors",
+			"{{ $labels.disk }} reallocated {{ $value }} sectors."),
+		rule("PrysmDiskGrownDefects",
+			fmt.Sprintf("disk_scsi_grown_defects > %d", c.GrownDefectsThreshold), "30m", SeverityWarning,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} has grown defects",
+			"{{ $labels.disk }} has {{ $value }} grown defects."),
+		rule("PrysmSSDWearOut",
+			fmt.Sprintf("ssd_life_used_percentage > %d", c.LifetimeUsedThreshold), "1h", SeverityWarning,
+			"SSD {{ $labels.disk }} on {{ $labels.node }} is wearing out",
+			"{{ $labels.disk }} used {{ $value }}% of its rated life."),
+	}
+	if c.SelfTest {
+		rules = append(rules, rule("PrysmDiskSelfTestFailed",
+			"disk_self_test_last_passed == 0", "", SeverityWarning,
+			"Disk {{ $labels.disk }} on {{ $labels.node }} failed a self-test",
+			"The last {{ $labels.test_type }} self-test of {{ $labels.disk }} failed."))
+	}
+	return rules
+}
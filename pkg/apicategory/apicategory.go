@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apicategory maps the RGW operations of the ops log and the
+// categories of the usage log, e.g. get_obj or list_bucket, to the API
+// categories shared by the producers, so the requests of the ops-log and
+// the usage of radosgw-usage add up the same way on the dashboards.
+package apicategory
+
+import "strings"
+
+// API categories of the requests
+const (
+	Read   = "read"   // Reading objects and metadata
+	List   = "list"   // Listing buckets, objects and uploads
+	Write  = "write"  // Creating and changing objects and buckets
+	Delete = "delete" // Deleting objects, buckets and uploads
+	Other  = "other"  // Anything else, e.g. OPTIONS
+)
+
+// All are the API categories in the order of the dashboards
+var All = []string{Read, List, Write, Delete, Other}
+
+// Normalize returns an RGW operation or usage category in the form of the
+// ops log, e.g. get_obj for " Get-Obj"
+func Normalize(operation string) string {
+	operation = strings.ToLower(strings.TrimSpace(operation))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(operation)
+}
+
+// Of returns the API category of a request by its RGW operation, e.g.
+// list_bucket, or by its HTTP method if the operation is not known
+func Of(operation, method string) string {
+	switch operation = Normalize(operation); {
+	case strings.HasPrefix(operation, "list_"):
+		return List
+	case strings.HasPrefix(operation, "get_"), strings.HasPrefix(operation, "head_"), strings.HasPrefix(operation, "stat_"):
+		return Read
+	case strings.HasPrefix(operation, "delete_"), operation == "multi_object_delete", operation == "abort_multipart":
+		return Delete
+	case strings.HasPrefix(operation, "put_"), strings.HasPrefix(operation, "post_"), strings.HasPrefix(operation, "copy_"),
+		strings.HasPrefix(operation, "create_"), operation == "init_multipart", operation == "complete_multipart":
+		return Write
+	}
+
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD":
+		return Read
+	case "PUT", "POST", "PATCH":
+		return Write
+	case "DELETE":
+		return Delete
+	default:
+		return Other
+	}
+}
+
+// OfUsage returns the API category of a category of the usage log, which
+// names the RGW operation without the HTTP method
+func OfUsage(category string) string {
+	return Of(category, "")
+}
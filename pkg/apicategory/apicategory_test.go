@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apicategory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOf(t *testing.T) {
+	for _, tc := range []struct {
+		operation, method, category string
+	}{
+		{"get_obj", "GET", Read},
+		{"head_bucket", "HEAD", Read},
+		{"list_bucket", "GET", List},
+		{"list_buckets", "GET", List},
+		{"put_obj", "PUT", Write},
+		{"complete_multipart", "POST", Write},
+		{"delete_obj", "DELETE", Delete},
+		{"multi_object_delete", "POST", Delete},
+		{"", "GET", Read},
+		{"", "get", Read},
+		{"options_cors", "OPTIONS", Other},
+		{" Put-Obj ", "", Write},
+	} {
+		assert.Equal(t, tc.category, Of(tc.operation, tc.method), tc.operation)
+	}
+}
+
+func TestOfUsage(t *testing.T) {
+	for category, expected := range map[string]string{
+		"get_obj":             Read,
+		"stat_bucket":         Read,
+		"list_bucket":         List,
+		"create_bucket":       Write,
+		"init_multipart":      Write,
+		"delete_bucket":       Delete,
+		"multi_object_delete": Delete,
+		"options_cors":        Other,
+	} {
+		assert.Equal(t, expected, OfUsage(category), category)
+	}
+}
@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/debugserver"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	agentProducerNames []string
+	agentNatsURL       string
+	agentPromPort      int
+	agentNodeName      string
+	agentInstanceID    string
+	agentRemoteWrite   remoteWriteFlags
+)
+
+// agentSettings are shared by the producers of the agent, they take
+// precedence over the settings of the producers
+type agentSettings struct {
+	NatsURL        string
+	PrometheusPort int
+	NodeName       string
+	InstanceID     string
+}
+
+// agentProducer is a producer prysm agent runs. The agent takes over the
+// flags of cmd prefixed with the name of the producer, e.g.
+// --osd-perf.interval, and reads its environment variables prefixed with the
+// name in upper case, e.g. OSD_PERF_INTERVAL. configure returns the
+// producer, configured with the shared settings, ready to run.
+type agentProducer struct {
+	cmd       *cobra.Command
+	configure func(shared agentSettings) func()
+}
+
+// Flags of the producers the agent sets for all of them
+var agentSharedFlags = map[string]bool{
+	"nats-url":        true,
+	"prometheus":      true,
+	"prometheus-port": true,
+	"node-name":       true,
+	"instance-id":     true,
+	"probe-port":      true,
+}
+
+var agentProducers = []agentProducer{
+	{
+		cmd: opsLogCmd,
+		configure: func(shared agentSettings) func() {
+			config := opsLogConfig()
+			if shared.NatsURL != "" {
+				config.NatsURL = shared.NatsURL
+			}
+			config.Prometheus, config.PrometheusPort = true, shared.PrometheusPort
+			config.RemoteWrite = remotewrite.Config{}
+			return func() { runOpsLog(config) }
+		},
+	},
+	{
+		cmd: diskHealthMetricsCmd,
+		configure: func(shared agentSettings) func() {
+			config := diskHealthMetricsConfig()
+			if shared.NatsURL != "" {
+				config.NatsURL = shared.NatsURL
+			}
+			if shared.NodeName != "" {
+				config.NodeName = shared.NodeName
+			}
+			if shared.InstanceID != "" {
+				config.InstanceID = shared.InstanceID
+			}
+			config.Prometheus, config.PrometheusPort = true, shared.PrometheusPort
+			config.ProbePort = 0
+			config.RemoteWrite = remotewrite.Config{}
+			return func() { runDiskHealthMetrics(config) }
+		},
+	},
+	{
+		cmd: osdPerfCmd,
+		configure: func(shared agentSettings) func() {
+			config := osdPerfConfig()
+			if shared.NatsURL != "" {
+				config.NatsURL = shared.NatsURL
+			}
+			if shared.NodeName != "" {
+				config.NodeName = shared.NodeName
+			}
+			if shared.InstanceID != "" {
+				config.InstanceID = shared.InstanceID
+			}
+			config.Prometheus, config.PrometheusPort = true, shared.PrometheusPort
+			return func() { runOSDPerf(config) }
+		},
+	},
+	{
+		cmd: nodeInventoryCmd,
+		configure: func(shared agentSettings) func() {
+			config := nodeInventoryConfig()
+			if shared.NatsURL != "" {
+				config.NatsURL = shared.NatsURL
+			}
+			if shared.NodeName != "" {
+				config.NodeName = shared.NodeName
+			}
+			if shared.InstanceID != "" {
+				config.InstanceID = shared.InstanceID
+			}
+			config.Prometheus, config.PrometheusPort = true, shared.PrometheusPort
+			return func() { runNodeInventory(config) }
+		},
+	},
+}
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run several local producers in one process",
+	Long: `Run several local producers in one process, sharing one Prometheus
+metrics server with the /healthz and /readyz checks of all producers, one
+NATS connection and one remote_write pusher, to reduce the footprint per node.
+
+The options of a producer are its flags prefixed with its name, e.g.
+--osd-perf.interval, or its environment variables prefixed with the name in
+upper case, e.g. OSD_PERF_INTERVAL. The NATS URL, metrics port, node name and
+instance ID are set for all producers.
+
+The agent exits when one of its producers stops.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, shared := agentConfig()
+		producers, err := selectAgentProducers(names)
+		if err != nil {
+			return err
+		}
+
+		remoteWrite := remoteWriteConfig(agentRemoteWrite)
+		if !validateRemoteWriteConfig(remoteWrite, true) {
+			os.Exit(1)
+		}
+
+		event := log.Info()
+		event.Strs("producers", names)
+		event.Bool("use_nats", shared.NatsURL != "")
+		if shared.NatsURL != "" {
+			event.Str("nats_url", shared.NatsURL)
+		}
+		event.Int("prometheus_port", shared.PrometheusPort)
+		event.Str("node_name", shared.NodeName)
+		event.Str("instance_id", shared.InstanceID)
+		logRemoteWriteConfig(event, remoteWrite)
+		event.Msg("configuration_loaded")
+
+		// The producers are configured one after the other, the prefix of
+		// their environment variables is global
+		runs := make([]func(), len(producers))
+		for i, producer := range producers {
+			telemetry.SetEnvPrefix(agentEnvPrefix(producer.cmd.Name()))
+			runs[i] = producer.configure(shared)
+		}
+		telemetry.SetEnvPrefix("")
+
+		natsutil.Share()
+		telemetry.StartMetricsServer(shared.PrometheusPort)
+		if remoteWrite.URL != "" {
+			remotewrite.Start(remoteWrite)
+		}
+
+		stopped := make(chan string)
+		for i, producer := range producers {
+			go func() {
+				runs[i]()
+				stopped <- producer.cmd.Name()
+			}()
+		}
+		return fmt.Errorf("producer %s stopped", <-stopped)
+	},
+}
+
+// agentConfig returns the names of the producers to run and the shared
+// settings of the flags and environment variables
+func agentConfig() ([]string, agentSettings) {
+	names := agentProducerNames
+	if producers := telemetry.GetEnv("PRODUCERS", ""); producers != "" {
+		names = strings.Split(producers, ",")
+	}
+	return names, agentSettings{
+		NatsURL:        telemetry.GetEnv("NATS_URL", agentNatsURL),
+		PrometheusPort: telemetry.GetEnvInt("PROMETHEUS_PORT", agentPromPort),
+		NodeName:       telemetry.GetEnv("NODE_NAME", agentNodeName),
+		InstanceID:     telemetry.GetEnv("INSTANCE_ID", agentInstanceID),
+	}
+}
+
+// selectAgentProducers returns the producers of names, failing on unknown
+// and repeated ones
+func selectAgentProducers(names []string) ([]agentProducer, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no producers given, expected some of %s", strings.Join(agentProducerList(), ", "))
+	}
+	selected := map[string]bool{}
+	var producers []agentProducer
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if selected[name] {
+			return nil, fmt.Errorf("producer %s given twice", name)
+		}
+		selected[name] = true
+
+		found := false
+		for _, producer := range agentProducers {
+			if producer.cmd.Name() == name {
+				producers = append(producers, producer)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown producer %q, expected some of %s", name, strings.Join(agentProducerList(), ", "))
+		}
+	}
+	return producers, nil
+}
+
+func agentProducerList() []string {
+	names := make([]string, len(agentProducers))
+	for i, producer := range agentProducers {
+		names[i] = producer.cmd.Name()
+	}
+	return names
+}
+
+// agentEnvPrefix is the prefix of the environment variables of a producer,
+// e.g. DISK_HEALTH_METRICS_ of disk-health-metrics
+func agentEnvPrefix(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+}
+
+// addAgentProducerFlags takes over the flags of the producers, prefixed with
+// their names. It runs once all commands registered their flags.
+func addAgentProducerFlags() {
+	for _, producer := range agentProducers {
+		name := producer.cmd.Name()
+		producer.cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			if agentSharedFlags[flag.Name] || strings.HasPrefix(flag.Name, "remote-write-") || flag.Deprecated != "" {
+				return
+			}
+			agentCmd.Flags().AddFlag(&pflag.Flag{
+				Name:        name + "." + flag.Name,
+				Usage:       flag.Usage,
+				Value:       flag.Value,
+				DefValue:    flag.DefValue,
+				NoOptDefVal: flag.NoOptDefVal,
+				Hidden:      flag.Hidden,
+			})
+		})
+	}
+}
+
+func init() {
+	agentCmd.Flags().StringSliceVar(&agentProducerNames, "producers", nil, "Producers to run, some of "+strings.Join(agentProducerList(), ", "))
+	agentCmd.Flags().StringVar(&agentNatsURL, "nats-url", "", "NATS server URL shared by the producers")
+	agentCmd.Flags().IntVar(&agentPromPort, "prometheus-port", 8080, "Port of the shared Prometheus metrics and health endpoint")
+	agentCmd.Flags().StringVar(&agentNodeName, "node-name", "", "Name of the node")
+	agentCmd.Flags().StringVar(&agentInstanceID, "instance-id", "", "Instance ID")
+	agentCmd.Flags().IntVar(&debugPort, "debug-port", 0, "Port for the pprof/runtime debug server (0 disables)")
+	agentCmd.Flags().StringVar(&debugAddress, "debug-address", debugserver.DefaultAddress, "Address the debug server listens on (all interfaces if empty)")
+	registerPublishConfigFlags(agentCmd.Flags())
+	agentRemoteWrite.register(agentCmd)
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/aggregate"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aggregateNatsURL        string
+	aggregateSubject        string
+	aggregatePrometheusPort int
+	aggregateStaleAfter     time.Duration
+)
+
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "Serve the ops-log metrics of all nodes on one Prometheus endpoint",
+	Long: `Serve the ops-log metrics of all nodes on one Prometheus endpoint. The
+ops-log sidecars publish the metrics of every window to NATS with
+--nats-metrics-subject; aggregate subscribes to them, sums the windows of all
+sidecars and serves the sums as the counters of the sidecars, without their
+pod label, so small installations need neither Thanos nor a scrape of every
+sidecar.
+
+The metrics of the requests, methods, operations, statuses, bytes and errors
+are merged. A window of a sidecar is merged once; prysm_aggregate_nodes
+counts the sidecars that reported within --stale-after.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		natsURL, subject, port, staleAfter := aggregateConfig()
+		if natsURL == "" {
+			return fmt.Errorf("--nats-url is required")
+		}
+		if subject == "" {
+			return fmt.Errorf("--nats-metrics-subject must not be empty")
+		}
+		if staleAfter <= 0 {
+			return fmt.Errorf("--stale-after must be positive")
+		}
+
+		log.Info().
+			Str("nats_url", natsURL).
+			Str("subject", subject).
+			Int("prometheus_port", port).
+			Dur("stale_after", staleAfter).
+			Msg("configuration_loaded")
+
+		nc, err := natsutil.Connect(natsURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to NATS: %w", err)
+		}
+		defer nc.Close()
+
+		aggregator := aggregate.New(staleAfter)
+		prometheus.MustRegister(aggregator)
+		// The sidecars reading a log file publish on <subject>.metrics
+		if _, err := aggregator.Subscribe(nc, subject, subject+".metrics"); err != nil {
+			return err
+		}
+		telemetry.StartMetricsServer(port)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+		<-sigChan
+		log.Info().Msg("Termination signal received. Exiting...")
+		return nil
+	},
+}
+
+// aggregateConfig returns the settings of the flags and environment
+// variables of aggregate
+func aggregateConfig() (natsURL, subject string, port int, staleAfter time.Duration) {
+	return telemetry.GetEnv("NATS_URL", aggregateNatsURL),
+		telemetry.GetEnv("NATS_METRICS_SUBJECT", aggregateSubject),
+		telemetry.GetEnvInt("PROMETHEUS_PORT", aggregatePrometheusPort),
+		telemetry.GetEnvDuration("STALE_AFTER", aggregateStaleAfter)
+}
+
+func init() {
+	aggregateCmd.Flags().StringVar(&aggregateNatsURL, "nats-url", "", "NATS server URL")
+	aggregateCmd.Flags().StringVar(&aggregateSubject, "nats-metrics-subject", aggregate.DefaultSubject, "NATS subject the ops-log sidecars publish their metrics on, the --nats-metrics-subject of ops-log")
+	aggregateCmd.Flags().IntVar(&aggregatePrometheusPort, "prometheus-port", 8080, "Prometheus metrics port")
+	aggregateCmd.Flags().DurationVar(&aggregateStaleAfter, "stale-after", aggregate.DefaultStaleAfter, "Time a sidecar is counted in prysm_aggregate_nodes after its last metrics")
+}
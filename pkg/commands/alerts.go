@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/alerts"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	alertsConfig alerts.Config
+	alertsOutput string
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Prometheus alerting rules of the producer metrics",
+}
+
+var alertsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the Prometheus alerting rules of the enabled metrics",
+	Long: `Generate a Prometheus rule file with a group of alerting rules per producer:
+error budget burn of the bucket SLIs of ops-log, quotas nearing their limit
+and a stalled sync of radosgw-usage, and failing disks of disk-health-metrics.
+
+The configuration is read from the same environment variables as the
+producers, e.g. TRACK_BUCKET_SLO or RISK_CRITICAL_THRESHOLD, so the rules of
+a deployment only query the metrics it enables and alert at the thresholds
+of its events.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := mergeAlertsConfigWithEnv(alertsConfig)
+		cfg.OpsLog = opsLogConfig()
+		cfg.RadosGWUsage = radosGWUsageConfig()
+		cfg.DiskHealth = diskHealthMetricsConfig()
+		cfg.Metrics = metricnames.Current()
+
+		rules, err := alerts.Generate(cfg)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(rules); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+
+		if alertsOutput == "-" {
+			_, err := cmd.OutOrStdout().Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(alertsOutput, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write rules: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %d groups\n", alertsOutput, len(rules.Groups))
+		return nil
+	},
+}
+
+func mergeAlertsConfigWithEnv(cfg alerts.Config) alerts.Config {
+	cfg.AvailabilityObjective = telemetry.GetEnvFloat("AVAILABILITY_OBJECTIVE", cfg.AvailabilityObjective)
+	cfg.QuotaWarningRatio = telemetry.GetEnvFloat("QUOTA_WARNING_RATIO", cfg.QuotaWarningRatio)
+	cfg.SyncStalledCycles = telemetry.GetEnvInt("SYNC_STALLED_CYCLES", cfg.SyncStalledCycles)
+	return cfg
+}
+
+func init() {
+	alertsGenerateCmd.Flags().StringSliceVar(&alertsConfig.Producers, "producer", nil, "Producers to generate rules for ("+strings.Join(alerts.Producers, ", ")+"), all if not set")
+	alertsGenerateCmd.Flags().Float64Var(&alertsConfig.AvailabilityObjective, "availability-objective", alerts.DefaultAvailabilityObjective, "Ratio of bucket GET/LIST requests that must not fail with a 5xx")
+	alertsGenerateCmd.Flags().Float64Var(&alertsConfig.QuotaWarningRatio, "quota-warning-ratio", alerts.DefaultQuotaWarningRatio, "Used ratio of a user or bucket quota to alert at")
+	alertsGenerateCmd.Flags().IntVar(&alertsConfig.SyncStalledCycles, "sync-stalled-cycles", alerts.DefaultSyncStalledCycles, "Collection cycles of radosgw-usage without one completing to alert at")
+	alertsGenerateCmd.Flags().StringVarP(&alertsOutput, "output", "o", "-", "Rule file to write, - for stdout")
+
+	alertsCmd.AddCommand(alertsGenerateCmd)
+}
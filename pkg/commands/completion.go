@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish",
+	Short: "Generate the shell completion script of prysm",
+	Long: `Generate the completion script of the subcommands and flags of prysm for a
+shell, written to stdout.
+
+  # bash, in the current shell or for all shells
+  source <(prysm completion bash)
+  prysm completion bash > /etc/bash_completion.d/prysm
+
+  # zsh, with compinit enabled
+  prysm completion zsh > "${fpath[1]}/_prysm"
+
+  # fish
+  prysm completion fish > ~/.config/fish/completions/prysm.fish`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		default:
+			return rootCmd.GenBashCompletionV2(out, true)
+		}
+	},
+}
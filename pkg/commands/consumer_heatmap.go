@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/consumer/heatmapconsumer"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var heatmapConsumerConfig heatmapconsumer.HeatmapConsumerConfig
+
+var heatmapConsumerCmd = &cobra.Command{
+	Use:   "ops-heatmap",
+	Short: "Consumer aggregating the ops log into hourly bucket and operation heatmaps",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := mergeHeatmapConsumerConfigWithEnv(heatmapConsumerConfig)
+
+		event := log.Info()
+		event.Str("nats_url", config.NatsURL)
+		event.Str("nats_subject", config.NatsSubject)
+		event.Str("queue_group", config.QueueGroup)
+		event.Str("kv_bucket", config.KVBucket)
+		event.Int("retention_days", config.RetentionDays)
+		event.Int("flush_interval", config.FlushInterval)
+		event.Int("http_port", config.HTTPPort)
+
+		event.Bool("prometheus_enabled", config.Prometheus)
+		if config.Prometheus {
+			event.Int("prometheus_port", config.PrometheusPort)
+		}
+
+		// Finalize the log message with the main message
+		event.Msg("configuration_loaded")
+
+		validateHeatmapConsumerConfig(config)
+
+		heatmapconsumer.StartHeatmapConsumer(config)
+	},
+}
+
+func mergeHeatmapConsumerConfigWithEnv(cfg heatmapconsumer.HeatmapConsumerConfig) heatmapconsumer.HeatmapConsumerConfig {
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.QueueGroup = telemetry.GetEnv("QUEUE_GROUP", cfg.QueueGroup)
+	cfg.KVBucket = telemetry.GetEnv("KV_BUCKET", cfg.KVBucket)
+	cfg.RetentionDays = telemetry.GetEnvInt("RETENTION_DAYS", cfg.RetentionDays)
+	cfg.FlushInterval = telemetry.GetEnvInt("FLUSH_INTERVAL", cfg.FlushInterval)
+	cfg.HTTPPort = telemetry.GetEnvInt("HTTP_PORT", cfg.HTTPPort)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+
+	return cfg
+}
+
+func init() {
+	heatmapConsumerCmd.Flags().StringVar(&heatmapConsumerConfig.NatsURL, "nats-url", "", "NATS server URL")
+	heatmapConsumerCmd.Flags().StringVar(&heatmapConsumerConfig.NatsSubject, "nats-subject", "rgw.s3.ops", "NATS subject of the ops log entries")
+	heatmapConsumerCmd.Flags().StringVar(&heatmapConsumerConfig.QueueGroup, "queue-group", "", "NATS queue group, consumers in the same group share the entries")
+	heatmapConsumerCmd.Flags().StringVar(&heatmapConsumerConfig.KVBucket, "kv-bucket", "prysm_ops_heatmap", "NATS key-value bucket storing the heatmaps")
+	heatmapConsumerCmd.Flags().IntVar(&heatmapConsumerConfig.RetentionDays, "retention-days", 30, "Days the hourly heatmaps are kept")
+	heatmapConsumerCmd.Flags().IntVar(&heatmapConsumerConfig.FlushInterval, "flush-interval", 10, "Seconds after which the counts are added to the stored heatmaps")
+	heatmapConsumerCmd.Flags().IntVar(&heatmapConsumerConfig.HTTPPort, "http-port", 8081, "Port serving the heatmaps as JSON on /heatmap")
+	heatmapConsumerCmd.Flags().BoolVar(&heatmapConsumerConfig.Prometheus, "prometheus", false, "Enable Prometheus metrics")
+	heatmapConsumerCmd.Flags().IntVar(&heatmapConsumerConfig.PrometheusPort, "prometheus-port", 8080, "Prometheus metrics port")
+}
+
+func validateHeatmapConsumerConfig(config heatmapconsumer.HeatmapConsumerConfig) {
+	missingParams := false
+
+	if config.NatsURL == "" {
+		fmt.Println("Warning: --nats-url or NATS_URL must be set")
+		missingParams = true
+	}
+	if config.NatsSubject == "" {
+		fmt.Println("Warning: --nats-subject or NATS_SUBJECT must be set")
+		missingParams = true
+	}
+	if config.KVBucket == "" {
+		fmt.Println("Warning: --kv-bucket or KV_BUCKET must be set")
+		missingParams = true
+	}
+	if config.RetentionDays <= 0 {
+		fmt.Println("Warning: --retention-days or RETENTION_DAYS must be greater than 0")
+		missingParams = true
+	}
+	if config.FlushInterval <= 0 {
+		fmt.Println("Warning: --flush-interval or FLUSH_INTERVAL must be greater than 0")
+		missingParams = true
+	}
+	if config.HTTPPort <= 0 {
+		fmt.Println("Warning: --http-port or HTTP_PORT must be greater than 0")
+		missingParams = true
+	}
+	if config.Prometheus && (config.PrometheusPort <= 0 || config.PrometheusPort == config.HTTPPort) {
+		fmt.Println("Warning: --prometheus-port or PROMETHEUS_PORT must be greater than 0 and differ from --http-port")
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
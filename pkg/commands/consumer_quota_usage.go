@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/cobaltcore-dev/prysm/pkg/consumer/quotausageconsumer"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -62,12 +63,12 @@ var quotaUsageConsumerCmd = &cobra.Command{
 }
 
 func mergeQuotaUsageConsumerConfigWithEnv(cfg quotausageconsumer.QuotaUsageConsumerConfig) quotausageconsumer.QuotaUsageConsumerConfig {
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
-	cfg.QuotaUsagePercent = getEnvFloat("QUOTA_USAGE_PERCENT", cfg.QuotaUsagePercent)
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+	cfg.QuotaUsagePercent = telemetry.GetEnvFloat("QUOTA_USAGE_PERCENT", cfg.QuotaUsagePercent)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
 
 	return cfg
 }
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/consumer/sinkconsumer"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// ClickHouse table, optionally qualified by its database
+var clickHouseTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var (
+	opsLogConsumerCmd       = newSinkConsumerCmd(sinkconsumer.SourceOpsLog, "rgw.s3.ops", "prysm_ops_log")
+	radosGWUsageConsumerCmd = newSinkConsumerCmd(sinkconsumer.SourceRadosGWUsage, "notifications", "prysm_radosgw_events")
+	eventsConsumerCmd       = newSinkConsumerCmd(sinkconsumer.SourceEvents, "osd.disk.health,ceph.health.events,notifications,user.quotas.usage", "prysm_alerts")
+)
+
+// newSinkConsumerCmd creates the consumer command of a source, forwarding
+// what the producer publishes on NATS to the configured sinks
+func newSinkConsumerCmd(source, defaultSubject, defaultTable string) *cobra.Command {
+	config := sinkconsumer.SinkConsumerConfig{Source: source}
+
+	cmd := &cobra.Command{
+		Use:   source,
+		Short: fmt.Sprintf("Consumer forwarding %s messages from NATS to sinks", source),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := mergeSinkConsumerConfigWithEnv(config)
+
+			event := log.Info()
+			event.Str("source", cfg.Source)
+			event.Str("nats_url", cfg.NatsURL)
+			event.Str("nats_subject", cfg.NatsSubject)
+			event.Str("queue_group", cfg.QueueGroup)
+			event.Int("batch_size", cfg.BatchSize)
+			event.Int("flush_interval", cfg.FlushInterval)
+
+			event.Bool("prometheus_enabled", cfg.Prometheus)
+			if cfg.Prometheus {
+				event.Int("prometheus_port", cfg.PrometheusPort)
+			}
+
+			event.Str("remote_write_url", cfg.RemoteWriteURL)
+			event.Str("loki_url", cfg.LokiURL)
+			event.Str("clickhouse_url", cfg.ClickHouseURL)
+			if cfg.ClickHouseURL != "" {
+				event.Str("clickhouse_table", cfg.ClickHouseTable)
+			}
+			event.Str("s3_bucket", cfg.S3Bucket)
+			if cfg.S3Bucket != "" {
+				event.Str("s3_endpoint", cfg.S3Endpoint)
+				event.Str("s3_prefix", cfg.S3Prefix)
+			}
+			event.Str("webhook_url", cfg.WebhookURL)
+			if cfg.WebhookURL != "" {
+				event.Str("webhook_template", cfg.WebhookTemplate)
+				event.Bool("webhook_signed", cfg.WebhookSecret != "")
+				event.Dur("webhook_repeat_interval", cfg.WebhookRepeatInterval)
+			}
+
+			// Finalize the log message with the main message
+			event.Msg("configuration_loaded")
+
+			validateSinkConsumerConfig(cfg)
+
+			sinkconsumer.StartSinkConsumer(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&config.NatsURL, "nats-url", "", "NATS server URL")
+	cmd.Flags().StringVar(&config.NatsSubject, "nats-subject", defaultSubject, "NATS subject to subscribe to")
+	cmd.Flags().StringVar(&config.QueueGroup, "queue-group", "", "NATS queue group, consumers in the same group share the messages")
+	cmd.Flags().IntVar(&config.BatchSize, "batch-size", 500, "Maximum number of records written to the sinks at once")
+	cmd.Flags().IntVar(&config.FlushInterval, "flush-interval", 10, "Seconds after which a partial batch is written")
+	cmd.Flags().BoolVar(&config.Prometheus, "prometheus", false, "Enable Prometheus metrics")
+	cmd.Flags().IntVar(&config.PrometheusPort, "prometheus-port", 8080, "Prometheus metrics port")
+
+	cmd.Flags().StringVar(&config.RemoteWriteURL, "remote-write-url", "", "Prometheus remote_write endpoint, e.g. http://prometheus:9090/api/v1/write")
+	cmd.Flags().StringVar(&config.LokiURL, "loki-url", "", "Loki base URL, e.g. http://loki:3100")
+	cmd.Flags().StringVar(&config.LokiTenant, "loki-tenant", "", "Loki tenant (X-Scope-OrgID)")
+	cmd.Flags().StringVar(&config.ClickHouseURL, "clickhouse-url", "", "ClickHouse HTTP interface URL, e.g. http://clickhouse:8123")
+	cmd.Flags().StringVar(&config.ClickHouseTable, "clickhouse-table", defaultTable, "ClickHouse table to insert into")
+	cmd.Flags().StringVar(&config.ClickHouseUser, "clickhouse-user", "", "ClickHouse user")
+	cmd.Flags().StringVar(&config.ClickHousePassword, "clickhouse-password", "", "ClickHouse password")
+	cmd.Flags().StringVar(&config.S3Endpoint, "s3-endpoint", "", "S3 endpoint of the archive, AWS if empty")
+	cmd.Flags().StringVar(&config.S3Bucket, "s3-bucket", "", "S3 bucket to archive the records in")
+	cmd.Flags().StringVar(&config.S3Prefix, "s3-prefix", "prysm", "Key prefix of the archived objects")
+	cmd.Flags().StringVar(&config.S3Region, "s3-region", "us-east-1", "S3 region")
+	cmd.Flags().StringVar(&config.S3AccessKey, "s3-access-key", "", "S3 access key")
+	cmd.Flags().StringVar(&config.S3SecretKey, "s3-secret-key", "", "S3 secret key")
+	cmd.Flags().StringVar(&config.WebhookURL, "webhook-url", "", "URL the alerts are posted to one by one, e.g. of a ticketing system (events only)")
+	cmd.Flags().StringVar(&config.WebhookHeaders, "webhook-headers", "", "Comma-separated name=value HTTP headers of the webhook requests, e.g. \"Authorization=Bearer token\"")
+	cmd.Flags().StringVar(&config.WebhookTemplate, "webhook-template", "", "File of the Go template of the webhook payload, the alert as JSON if not set")
+	cmd.Flags().StringVar(&config.WebhookSecret, "webhook-secret", "", "Key of the HMAC-SHA256 signature of the webhook payloads in X-Prysm-Signature-256")
+	cmd.Flags().DurationVar(&config.WebhookRepeatInterval, "webhook-repeat-interval", 4*time.Hour, "Repeats of an alert within are not posted again (0 posts all)")
+
+	return cmd
+}
+
+func mergeSinkConsumerConfigWithEnv(cfg sinkconsumer.SinkConsumerConfig) sinkconsumer.SinkConsumerConfig {
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.QueueGroup = telemetry.GetEnv("QUEUE_GROUP", cfg.QueueGroup)
+	cfg.BatchSize = telemetry.GetEnvInt("BATCH_SIZE", cfg.BatchSize)
+	cfg.FlushInterval = telemetry.GetEnvInt("FLUSH_INTERVAL", cfg.FlushInterval)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+
+	cfg.RemoteWriteURL = telemetry.GetEnv("REMOTE_WRITE_URL", cfg.RemoteWriteURL)
+	cfg.LokiURL = telemetry.GetEnv("LOKI_URL", cfg.LokiURL)
+	cfg.LokiTenant = telemetry.GetEnv("LOKI_TENANT", cfg.LokiTenant)
+	cfg.ClickHouseURL = telemetry.GetEnv("CLICKHOUSE_URL", cfg.ClickHouseURL)
+	cfg.ClickHouseTable = telemetry.GetEnv("CLICKHOUSE_TABLE", cfg.ClickHouseTable)
+	cfg.ClickHouseUser = telemetry.GetEnv("CLICKHOUSE_USER", cfg.ClickHouseUser)
+	cfg.ClickHousePassword = telemetry.GetEnv("CLICKHOUSE_PASSWORD", cfg.ClickHousePassword)
+	cfg.S3Endpoint = telemetry.GetEnv("S3_ENDPOINT", cfg.S3Endpoint)
+	cfg.S3Bucket = telemetry.GetEnv("S3_BUCKET", cfg.S3Bucket)
+	cfg.S3Prefix = telemetry.GetEnv("S3_PREFIX", cfg.S3Prefix)
+	cfg.S3Region = telemetry.GetEnv("S3_REGION", cfg.S3Region)
+	cfg.S3AccessKey = telemetry.GetEnv("S3_ACCESS_KEY", cfg.S3AccessKey)
+	cfg.S3SecretKey = telemetry.GetEnv("S3_SECRET_KEY", cfg.S3SecretKey)
+	cfg.WebhookURL = telemetry.GetEnv("WEBHOOK_URL", cfg.WebhookURL)
+	cfg.WebhookHeaders = telemetry.GetEnv("WEBHOOK_HEADERS", cfg.WebhookHeaders)
+	cfg.WebhookTemplate = telemetry.GetEnv("WEBHOOK_TEMPLATE", cfg.WebhookTemplate)
+	cfg.WebhookSecret = telemetry.GetEnv("WEBHOOK_SECRET", cfg.WebhookSecret)
+	cfg.WebhookRepeatInterval = telemetry.GetEnvDuration("WEBHOOK_REPEAT_INTERVAL", cfg.WebhookRepeatInterval)
+
+	return cfg
+}
+
+func validateSinkConsumerConfig(config sinkconsumer.SinkConsumerConfig) {
+	missingParams := false
+
+	if config.NatsURL == "" {
+		fmt.Println("Warning: --nats-url or NATS_URL must be set")
+		missingParams = true
+	}
+	if config.NatsSubject == "" {
+		fmt.Println("Warning: --nats-subject or NATS_SUBJECT must be set")
+		missingParams = true
+	}
+	if config.BatchSize <= 0 {
+		fmt.Println("Warning: --batch-size or BATCH_SIZE must be greater than 0")
+		missingParams = true
+	}
+	if config.FlushInterval <= 0 {
+		fmt.Println("Warning: --flush-interval or FLUSH_INTERVAL must be greater than 0")
+		missingParams = true
+	}
+	if config.Prometheus && config.PrometheusPort <= 0 {
+		fmt.Println("Warning: --prometheus-port or PROMETHEUS_PORT must be greater than 0")
+		missingParams = true
+	}
+	if config.RemoteWriteURL == "" && config.LokiURL == "" && config.ClickHouseURL == "" && config.S3Bucket == "" && config.WebhookURL == "" {
+		fmt.Println("Warning: at least one sink must be set: --remote-write-url, --loki-url, --clickhouse-url, --s3-bucket or --webhook-url")
+		missingParams = true
+	}
+	if config.WebhookURL != "" {
+		if config.Source != sinkconsumer.SourceEvents {
+			fmt.Println("Warning: --webhook-url or WEBHOOK_URL requires prysm consumer events, only events carry alerts")
+			missingParams = true
+		}
+		if _, err := remotewrite.ParseLabels(config.WebhookHeaders); err != nil {
+			fmt.Printf("Warning: --webhook-headers or WEBHOOK_HEADERS: %v\n", err)
+			missingParams = true
+		}
+		if config.WebhookRepeatInterval < 0 {
+			fmt.Println("Warning: --webhook-repeat-interval or WEBHOOK_REPEAT_INTERVAL must not be negative")
+			missingParams = true
+		}
+	}
+	if config.ClickHouseURL != "" && !clickHouseTablePattern.MatchString(config.ClickHouseTable) {
+		fmt.Println("Warning: --clickhouse-table or CLICKHOUSE_TABLE must be a table name, optionally prefixed by the database")
+		missingParams = true
+	}
+	if (config.S3AccessKey == "") != (config.S3SecretKey == "") {
+		fmt.Println("Warning: --s3-access-key and --s3-secret-key must be set together")
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
@@ -15,4 +15,8 @@ var consumerCmd = &cobra.Command{
 
 func init() {
 	consumerCmd.AddCommand(quotaUsageConsumerCmd)
+	consumerCmd.AddCommand(opsLogConsumerCmd)
+	consumerCmd.AddCommand(radosGWUsageConsumerCmd)
+	consumerCmd.AddCommand(eventsConsumerCmd)
+	consumerCmd.AddCommand(heatmapConsumerCmd)
 }
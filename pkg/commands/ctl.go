@@ -5,38 +5,134 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/debugserver"
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/cobaltcore-dev/prysm/pkg/selflimit"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
+	"github.com/cobaltcore-dev/prysm/pkg/vault"
+	"github.com/cobaltcore-dev/prysm/pkg/version"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 var (
-	v            string
-	runningInPod bool
+	logLevel       string
+	logFormat      string
+	logDedupWindow time.Duration
+	metricsTLSCert string
+	metricsTLSKey  string
+	metricsUser    string
+	metricsPass    string
+	metricsToken   string
+	metricsRate    float64
+	metricsBurst   int
+	metricsPrefix  string
+	metricsLegacy  bool
+	natsCredsFile  string
+	natsTLSCA      string
+	natsTLSCert    string
+	natsTLSKey     string
+	natsEncoding   string
+	natsKeysFile   string
+	natsAllowPlain bool
+	natsSpoolDir   string
+	natsSpoolSize  int64
+	natsSpoolAge   time.Duration
+	tracingURL     string
+	tracingRatio   float64
+	vaultAddr      string
+	vaultTokenFile string
+	vaultRole      string
+	vaultMount     string
+	vaultNamespace string
+	vaultCACert    string
+	selfLimit      bool
+	cpuLimit       float64
+	memoryLimit    int64
+	memoryPercent  int
+	debugPort      int
+	debugAddress   string
+	readOnly       bool
+	runningInPod   bool
 	// responseBackToOperator bool
 )
 
+// Flushes the spans not exported yet, set up with the global flags
+var shutdownTracing = func(context.Context) error { return nil }
+
 var rootCmd = &cobra.Command{
 	Use:   "prysm",
 	Short: "CLI for Ceph & RadosGW observability",
 	Long:  "A CLI tool to manage Ceph & RadosGW observability, including logging and metrics collection.",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		if err := setUpLogs(v); err != nil {
+		if err := setUpLogs(telemetry.GetEnv("LOG_LEVEL", logLevel), telemetry.GetEnv("LOG_FORMAT", logFormat), telemetry.GetEnvDuration("LOG_DEDUP_WINDOW", logDedupWindow)); err != nil {
+			return err
+		}
+		setUpIdentity(cmd)
+		if err := setUpTelemetry(cmd); err != nil {
 			return err
 		}
+		// --debug-port is only registered on producer commands
+		if cmd.Flags().Lookup("debug-port") != nil {
+			debugserver.Start(telemetry.GetEnv("DEBUG_ADDRESS", debugAddress), telemetry.GetEnvInt("DEBUG_PORT", debugPort))
+		}
 		return nil
 	},
 }
 
 func init() {
 	runningInPod = checkIfRunningInPod()
+	rootCmd.Version = version.Get().Version
 
-	rootCmd.PersistentFlags().StringVarP(&v, "verbosity", "v", zerolog.WarnLevel.String(), "Log level (debug, info, warn, error, fatal, panic")
+	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "v", zerolog.WarnLevel.String(), "Log level ("+strings.Join(telemetry.LogLevels, ", ")+")")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "verbosity", zerolog.WarnLevel.String(), "Log level")
+	_ = rootCmd.PersistentFlags().MarkDeprecated("verbosity", "use --log-level instead")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", telemetry.LogFormatJSON, "Log format (json, console)")
+	rootCmd.PersistentFlags().DurationVar(&logDedupWindow, "log-dedup-window", telemetry.DefaultLogDedupWindow, "Window repeats of a warning or error are collapsed into a \"message repeated N times\" summary in (disabled if 0)")
+	rootCmd.PersistentFlags().StringVar(&metricsTLSCert, "metrics-tls-cert", "", "Certificate file to serve the Prometheus metrics over HTTPS")
+	rootCmd.PersistentFlags().StringVar(&metricsTLSKey, "metrics-tls-key", "", "Key file of --metrics-tls-cert")
+	rootCmd.PersistentFlags().StringVar(&metricsUser, "metrics-basic-auth-username", "", "Username the Prometheus metrics are scraped with (open if empty)")
+	rootCmd.PersistentFlags().StringVar(&metricsPass, "metrics-basic-auth-password", "", "Password of --metrics-basic-auth-username, better set by METRICS_BASIC_AUTH_PASSWORD")
+	rootCmd.PersistentFlags().StringVar(&metricsToken, "metrics-bearer-token", "", "Bearer token the Prometheus metrics are scraped with (open if empty), better set by METRICS_BEARER_TOKEN")
+	rootCmd.PersistentFlags().Float64Var(&metricsRate, "metrics-rate-limit", 0, "Requests a second a client address may send to the metrics server, answered with 429 above (unlimited if 0)")
+	rootCmd.PersistentFlags().IntVar(&metricsBurst, "metrics-rate-burst", telemetry.DefaultMetricsRateBurst, "Requests a client address may send to the metrics server at once with --metrics-rate-limit")
+	rootCmd.PersistentFlags().StringVar(&metricsPrefix, "metrics-prefix", metricnames.DefaultPrefix, "Prefix of the names of the Prometheus metrics, ending in an underscore (names kept if empty)")
+	rootCmd.PersistentFlags().BoolVar(&metricsLegacy, "metrics-legacy-names", false, "Serve the Prometheus metrics with their names without --metrics-prefix too, while dashboards are migrated")
+	rootCmd.PersistentFlags().StringVar(&natsCredsFile, "nats-creds", "", "NATS user credentials file")
+	rootCmd.PersistentFlags().StringVar(&natsTLSCA, "nats-tls-ca", "", "CA file verifying the NATS server")
+	rootCmd.PersistentFlags().StringVar(&natsTLSCert, "nats-tls-cert", "", "Client certificate file for NATS mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&natsTLSKey, "nats-tls-key", "", "Key file of --nats-tls-cert")
+	rootCmd.PersistentFlags().StringVar(&natsKeysFile, "nats-encryption-keys", "", "File of the keys encrypting and decrypting the NATS payloads, one base64 encoded 32 byte key per line (disabled if empty)")
+	rootCmd.PersistentFlags().BoolVar(&natsAllowPlain, "nats-allow-plaintext", false, "Accept plain text NATS payloads although --nats-encryption-keys is set, while producers are switched to encryption")
+	rootCmd.PersistentFlags().StringVar(&natsSpoolDir, "nats-spool-dir", "", "Directory the NATS messages are spooled to while the connection is down, forwarded once it is back (disabled if empty)")
+	rootCmd.PersistentFlags().Int64Var(&natsSpoolSize, "nats-spool-max-size", 1024, "Maximum size of the NATS spool in MB, the oldest messages are dropped above it (unlimited if 0)")
+	rootCmd.PersistentFlags().DurationVar(&natsSpoolAge, "nats-spool-max-age", 72*time.Hour, "Age above which spooled NATS messages are dropped (kept if 0)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Start in read-only maintenance mode, pausing the NATS publishing and KV updates (switched at runtime on the debug server)")
+	rootCmd.PersistentFlags().StringVar(&natsEncoding, "nats-encoding", string(schema.JSON), "Encoding of the published NATS payloads (json, msgpack)")
+	rootCmd.PersistentFlags().StringVar(&tracingURL, "tracing-endpoint", "", "OTLP/HTTP endpoint receiving the traces of the pipelines, e.g. http://tempo:4318 (disabled if empty)")
+	rootCmd.PersistentFlags().Float64Var(&tracingRatio, "tracing-sample-ratio", 1, "Share of the traces exported, from 0 to 1")
+	rootCmd.PersistentFlags().BoolVar(&selfLimit, "self-limit", false, "Derive GOMAXPROCS and a soft memory limit from the cgroup CPU and memory limits of the process")
+	rootCmd.PersistentFlags().Float64Var(&cpuLimit, "cpu-limit", 0, "CPU cores GOMAXPROCS is rounded up from, instead of the cgroup limit (derived or kept if 0)")
+	rootCmd.PersistentFlags().Int64Var(&memoryLimit, "memory-limit", 0, "Soft memory limit in MB the garbage collector keeps the process below, instead of the cgroup limit (derived or kept if 0)")
+	rootCmd.PersistentFlags().IntVar(&memoryPercent, "memory-limit-percent", selflimit.DefaultMemoryPercent, "Share of the cgroup memory limit the soft memory limit is set to with --self-limit")
+	rootCmd.PersistentFlags().StringVar(&vaultAddr, "vault-addr", "", "Vault server the secrets referenced as vault:<path>#<field> are read from, e.g. https://vault:8200 (disabled if empty)")
+	rootCmd.PersistentFlags().StringVar(&vaultTokenFile, "vault-token-file", "", "File of the Vault token, e.g. written by the Vault agent, if VAULT_TOKEN is not set")
+	rootCmd.PersistentFlags().StringVar(&vaultRole, "vault-kubernetes-role", "", "Vault role logged in to with the service account token of the pod, instead of a token")
+	rootCmd.PersistentFlags().StringVar(&vaultMount, "vault-kubernetes-mount", vault.DefaultKubernetesMount, "Mount of the Kubernetes auth method in Vault")
+	rootCmd.PersistentFlags().StringVar(&vaultNamespace, "vault-namespace", "", "Vault Enterprise namespace")
+	rootCmd.PersistentFlags().StringVar(&vaultCACert, "vault-cacert", "", "CA file verifying the Vault server")
 
 	if runningInPod {
 		log.Info().Msg("running in pod")
@@ -47,95 +143,199 @@ func init() {
 	rootCmd.AddCommand(consumerCmd)
 	rootCmd.AddCommand(localProducerCmd)
 	rootCmd.AddCommand(remoteProducerCmd)
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(dashboardsCmd)
+	rootCmd.AddCommand(alertsCmd)
+	rootCmd.AddCommand(fleetCmd)
+	rootCmd.AddCommand(aggregateCmd)
+	rootCmd.AddCommand(diskCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(docsCmd)
+	// Replaced by completionCmd
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	addAgentProducerFlags()
+	err := rootCmd.Execute()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(ctx); err != nil {
+		log.Warn().Err(err).Msg("failed to export the remaining traces")
+	}
+	cancel()
+
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your CLI '%s'\n", err)
 		os.Exit(1)
 	}
 }
 
-// setUpLogs sets the log output and the log level
-func setUpLogs(level string) error {
-	zerolog.SetGlobalLevel(zerolog.WarnLevel) // Default level
-	lvl, err := zerolog.ParseLevel(level)
-	if err != nil {
-		return err
-	}
-	zerolog.SetGlobalLevel(lvl)
-	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger() // Default to JSON output
-	// log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout})
-	return nil
+// setUpLogs sets the log output, the log level and the collapsing of
+// repeated lines
+func setUpLogs(level, format string, dedupWindow time.Duration) error {
+	return telemetry.SetupLogging(level, format, dedupWindow)
 }
 
-// checkIfRunningInPod checks if the application is running in a Kubernetes pod
-func checkIfRunningInPod() bool {
-	if _, err := os.Stat("/run/secrets/kubernetes.io/serviceaccount/ca.crt"); err == nil {
-		if _, err := os.Stat("/run/secrets/kubernetes.io/serviceaccount/token"); err == nil {
-			if _, ok := os.LookupEnv("KUBERNETES_SERVICE_HOST"); ok {
-				if _, ok := os.LookupEnv("KUBERNETES_SERVICE_PORT"); ok {
-					return true
-				}
-			}
+// setUpLogsOn moves the logs to out, set up again from the global flags
+func setUpLogsOn(out io.Writer) error {
+	return telemetry.SetupLoggingOn(out, telemetry.GetEnv("LOG_LEVEL", logLevel), telemetry.GetEnv("LOG_FORMAT", logFormat), telemetry.GetEnvDuration("LOG_DEDUP_WINDOW", logDedupWindow))
+}
+
+// setUpIdentity resolves the identity of the exporter stamped on its NATS
+// messages and metrics, from --instance-id and --node-name of the producer
+// commands and the environment
+func setUpIdentity(cmd *cobra.Command) {
+	flag := func(name string) string {
+		if f := cmd.Flags().Lookup(name); f != nil {
+			return f.Value.String()
 		}
+		return ""
 	}
-	return false
+	identity.Configure(identity.New(
+		telemetry.GetEnv("INSTANCE_ID", flag("instance-id")),
+		telemetry.GetEnv("POD_NAME", ""),
+		telemetry.GetEnv("NODE_NAME", flag("node-name")),
+	))
 }
 
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// setUpTelemetry configures the metrics server, the NATS connections and the
+// tracing of every subcommand from the global flags and environment variables
+func setUpTelemetry(cmd *cobra.Command) error {
+	selfLimits := selflimit.Config{
+		FromCgroup:    telemetry.GetEnvBool("SELF_LIMIT", selfLimit),
+		CPUs:          telemetry.GetEnvFloat("CPU_LIMIT", cpuLimit),
+		MemoryLimit:   telemetry.GetEnvInt64("MEMORY_LIMIT", memoryLimit) * 1024 * 1024,
+		MemoryPercent: telemetry.GetEnvInt("MEMORY_LIMIT_PERCENT", memoryPercent),
 	}
-	return fallback
-}
+	if err := selfLimits.Validate(); err != nil {
+		return err
+	}
+	selflimit.Apply(selfLimits)
 
-func getEnvInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+	vaultConfig := vault.Config{
+		Address:         telemetry.GetEnv("VAULT_ADDR", vaultAddr),
+		Token:           telemetry.GetEnv("VAULT_TOKEN", ""),
+		TokenFile:       telemetry.GetEnv("VAULT_TOKEN_FILE", vaultTokenFile),
+		KubernetesRole:  telemetry.GetEnv("VAULT_KUBERNETES_ROLE", vaultRole),
+		KubernetesMount: telemetry.GetEnv("VAULT_KUBERNETES_MOUNT", vaultMount),
+		Namespace:       telemetry.GetEnv("VAULT_NAMESPACE", vaultNamespace),
+		CACert:          telemetry.GetEnv("VAULT_CACERT", vaultCACert),
+	}
+	if err := vaultConfig.Validate(); err != nil {
+		return err
+	}
+	if err := vault.Configure(vaultConfig); err != nil {
+		return fmt.Errorf("failed to authenticate with vault: %w", err)
 	}
-	return defaultValue
-}
 
-func getEnvInt64(key string, defaultValue int64) int64 {
-	valueStr := os.Getenv(key)
-	if value, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
-		return value
+	metricsTLS := telemetry.MetricsTLS{
+		CertFile: telemetry.GetEnv("METRICS_TLS_CERT", metricsTLSCert),
+		KeyFile:  telemetry.GetEnv("METRICS_TLS_KEY", metricsTLSKey),
 	}
-	return defaultValue
-}
+	if err := metricsTLS.Validate(); err != nil {
+		return err
+	}
+	telemetry.ConfigureMetricsTLS(metricsTLS)
 
-func getEnvFloat(key string, defaultValue float64) float64 {
-	if value, exists := os.LookupEnv(key); exists {
-		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-			return floatValue
-		}
+	metricsAccess := telemetry.MetricsAccess{
+		Username:    telemetry.GetEnv("METRICS_BASIC_AUTH_USERNAME", metricsUser),
+		Password:    getEnvSecret("METRICS_BASIC_AUTH_PASSWORD", metricsPass),
+		BearerToken: getEnvSecret("METRICS_BEARER_TOKEN", metricsToken),
+		RateLimit:   telemetry.GetEnvFloat("METRICS_RATE_LIMIT", metricsRate),
+		RateBurst:   telemetry.GetEnvInt("METRICS_RATE_BURST", metricsBurst),
 	}
-	return defaultValue
-}
+	if err := metricsAccess.Validate(); err != nil {
+		return err
+	}
+	telemetry.ConfigureMetricsAccess(metricsAccess)
+
+	metricNames := metricnames.Config{
+		Prefix: telemetry.GetEnv("METRICS_PREFIX", metricsPrefix),
+		Legacy: telemetry.GetEnvBool("METRICS_LEGACY_NAMES", metricsLegacy),
+	}
+	if err := metricNames.Validate(); err != nil {
+		return err
+	}
+	metricnames.Configure(metricNames)
+
+	natsConfig := natsutil.Config{
+		Name: "prysm-" + cmd.Name(),
+		TLS: natsutil.TLSConfig{
+			CAFile:   telemetry.GetEnv("NATS_TLS_CA", natsTLSCA),
+			CertFile: telemetry.GetEnv("NATS_TLS_CERT", natsTLSCert),
+			KeyFile:  telemetry.GetEnv("NATS_TLS_KEY", natsTLSKey),
+		},
+		Spool: natsutil.SpoolConfig{
+			Dir:     telemetry.GetEnv("NATS_SPOOL_DIR", natsSpoolDir),
+			MaxSize: telemetry.GetEnvInt64("NATS_SPOOL_MAX_SIZE", natsSpoolSize) * 1024 * 1024,
+			MaxAge:  telemetry.GetEnvDuration("NATS_SPOOL_MAX_AGE", natsSpoolAge),
+		},
+	}
+	// NATS_CREDS is a file, or the content of one in Vault
+	if creds := telemetry.GetEnv("NATS_CREDS", natsCredsFile); vault.IsRef(creds) {
+		natsConfig.Creds = getEnvSecret("NATS_CREDS", natsCredsFile)
+	} else {
+		natsConfig.CredsFile = creds
+	}
+	if err := natsConfig.Validate(); err != nil {
+		return err
+	}
+	natsutil.Configure(natsConfig)
+	natsutil.SetReadOnly(telemetry.GetEnvBool("READ_ONLY", readOnly))
 
-func getEnvInt64Slice(key string, defaultValue []int64) []int64 {
-	valueStr := os.Getenv(key)
-	if valueStr == "" {
-		return defaultValue
+	encoding, err := schema.ParseEncoding(telemetry.GetEnv("NATS_ENCODING", natsEncoding))
+	if err != nil {
+		return err
 	}
-	values := strings.Split(valueStr, ",")
-	result := make([]int64, len(values))
-	for i, v := range values {
-		value, err := strconv.ParseInt(v, 10, 64)
+	schema.Configure(encoding)
+	if keysFile := telemetry.GetEnv("NATS_ENCRYPTION_KEYS", natsKeysFile); keysFile != "" {
+		keyring, err := schema.LoadKeyring(keysFile)
 		if err != nil {
-			return defaultValue
+			return err
 		}
-		result[i] = value
+		schema.ConfigureEncryption(keyring, telemetry.GetEnvBool("NATS_ALLOW_PLAINTEXT", natsAllowPlain))
+	}
+
+	tracingConfig := tracing.Config{
+		Endpoint:    telemetry.GetEnv("TRACING_ENDPOINT", tracingURL),
+		SampleRatio: telemetry.GetEnvFloat("TRACING_SAMPLE_RATIO", tracingRatio),
+		ServiceName: "prysm-" + cmd.Name(),
+	}
+	if err := tracingConfig.Validate(); err != nil {
+		return err
+	}
+	shutdown, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		return err
 	}
-	return result
+	shutdownTracing = shutdown
+	return nil
 }
 
-func getEnvBool(key string, defaultValue bool) bool {
-	valueStr := os.Getenv(key)
-	if value, err := strconv.ParseBool(valueStr); err == nil {
-		return value
+// getEnvSecret returns the value of the environment variable or the flag
+// like telemetry.GetEnv, with the secret of a vault:<path>#<field> reference
+// read from Vault. Failing to read it is fatal.
+func getEnvSecret(key, value string) string {
+	secret, err := vault.Resolve(telemetry.GetEnv(key, value))
+	if err != nil {
+		log.Fatal().Err(err).Str("key", key).Msg("failed to read the secret from vault")
+	}
+	return secret
+}
+
+// checkIfRunningInPod checks if the application is running in a Kubernetes pod
+func checkIfRunningInPod() bool {
+	if _, err := os.Stat("/run/secrets/kubernetes.io/serviceaccount/ca.crt"); err == nil {
+		if _, err := os.Stat("/run/secrets/kubernetes.io/serviceaccount/token"); err == nil {
+			if _, ok := os.LookupEnv("KUBERNETES_SERVICE_HOST"); ok {
+				if _, ok := os.LookupEnv("KUBERNETES_SERVICE_PORT"); ok {
+					return true
+				}
+			}
+		}
 	}
-	return defaultValue
+	return false
 }
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/dashboards"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dashboardsProducers []string
+	dashboardsOutputDir string
+)
+
+var dashboardsCmd = &cobra.Command{
+	Use:   "dashboards",
+	Short: "Grafana dashboards of the producer metrics",
+}
+
+var dashboardsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the Grafana dashboards of the enabled metrics",
+	Long: `Generate a Grafana dashboard per producer with panels of the metrics the
+producer exports with its configuration. The configuration is read from the
+same environment variables as the producers, e.g. TRACK_EVERYTHING or
+KERNEL_IO, so the dashboards of a deployment are generated from its
+environment or ConfigMap.
+
+The dashboards are written to <output-dir>/prysm-<producer>.json, or to
+stdout with --output-dir=- for a single producer. They ask for the
+Prometheus datasource when imported.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dashboardsOutputDir == "-" && len(dashboardsProducers) != 1 {
+			return fmt.Errorf("--output-dir=- needs exactly one --producer")
+		}
+
+		generated, err := dashboards.Generate(dashboards.Config{
+			Producers:    dashboardsProducers,
+			OpsLog:       opsLogConfig(),
+			RadosGWUsage: radosGWUsageConfig(),
+			DiskHealth:   diskHealthMetricsConfig(),
+			Metrics:      metricnames.Current(),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dashboard := range generated {
+			data, err := json.MarshalIndent(dashboard, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if dashboardsOutputDir == "-" {
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				continue
+			}
+
+			path := filepath.Join(dashboardsOutputDir, dashboard.UID+".json")
+			if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+				return fmt.Errorf("failed to write dashboard: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %d panels\n", path, len(dashboard.Panels))
+		}
+		return nil
+	},
+}
+
+func init() {
+	dashboardsGenerateCmd.Flags().StringSliceVar(&dashboardsProducers, "producer", nil, "Producers to generate dashboards for ("+strings.Join(dashboards.Producers, ", ")+"), all if not set")
+	dashboardsGenerateCmd.Flags().StringVar(&dashboardsOutputDir, "output-dir", ".", "Directory the dashboards are written to, - for stdout")
+
+	dashboardsCmd.AddCommand(dashboardsGenerateCmd)
+}
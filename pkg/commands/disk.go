@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diskNatsURL       string
+	diskLocateSubject string
+	diskLocateOff     bool
+	diskLocateTimeout time.Duration
+	diskOutput        string
+)
+
+var diskCmd = &cobra.Command{
+	Use:   "disk",
+	Short: "Commands about the disks watched by the disk-health-metrics producers",
+}
+
+var diskLocateCmd = &cobra.Command{
+	Use:   "locate <serial>",
+	Short: "Blink the slot LED of the drive with a serial number",
+	Long: `Blink the identification LED of the slot of the drive with a serial
+number, e.g. the one of a drive an alert fired for, so it is found in the
+data center. The request is sent over NATS to the disk-health-metrics
+producers started with --locate-subject; the producer of the node with the
+drive switches the LED with ceph orch device light or, for drives behind
+MegaRAID controllers, storcli or perccli. --off turns the LED off again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		natsURL, subject := diskLocateConfig()
+		if natsURL == "" {
+			return fmt.Errorf("--nats-url is required")
+		}
+		if diskOutput != "text" && diskOutput != "json" {
+			return fmt.Errorf("unknown output %q, expected text or json", diskOutput)
+		}
+		nc, err := natsutil.Connect(natsURL, nats.Name("prysm-disk-locate"))
+		if err != nil {
+			return fmt.Errorf("failed to connect to NATS: %w", err)
+		}
+		defer nc.Close()
+
+		data, err := json.Marshal(diskhealthmetrics.LocateRequest{Serial: args[0], Off: diskLocateOff})
+		if err != nil {
+			return err
+		}
+		msg, err := nc.Request(subject, data, diskLocateTimeout)
+		if errors.Is(err, nats.ErrNoResponders) || errors.Is(err, nats.ErrTimeout) {
+			return fmt.Errorf("no producer answered for a drive with serial %s on %s, is it started with --locate-subject?", args[0], subject)
+		}
+		if err != nil {
+			return err
+		}
+
+		var resp diskhealthmetrics.LocateResponse
+		if err := json.Unmarshal(msg.Data, &resp); err != nil {
+			return fmt.Errorf("invalid locate response: %w", err)
+		}
+		if diskOutput == "json" {
+			data, err := json.MarshalIndent(resp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		} else {
+			printLocateResponse(cmd.OutOrStdout(), resp)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("failed to switch the LED: %s", resp.Error)
+		}
+		return nil
+	},
+}
+
+// printLocateResponse prints the drive found and the state of its LED
+func printLocateResponse(w io.Writer, resp diskhealthmetrics.LocateResponse) {
+	fmt.Fprintf(w, "%s (%s", resp.Device, resp.Serial)
+	if resp.Model != "" {
+		fmt.Fprintf(w, ", %s", resp.Model)
+	}
+	if resp.OSDID != "" {
+		fmt.Fprintf(w, ", osd.%s", resp.OSDID)
+	}
+	fmt.Fprintf(w, ") on node %s\n", resp.NodeName)
+	if resp.Zone != "" || resp.Rack != "" {
+		fmt.Fprintf(w, "  zone %s, rack %s\n", valueOr(resp.Zone, "-"), valueOr(resp.Rack, "-"))
+	}
+	if location := resp.Location; location != nil {
+		fmt.Fprintf(w, "  chassis %s, enclosure %s, slot %s\n", valueOr(location.Chassis, "-"), location.Enclosure, location.Slot)
+	}
+	if resp.Error == "" {
+		state := "off"
+		if resp.LightOn {
+			state = "on"
+		}
+		fmt.Fprintf(w, "  LED %s (%s)\n", state, resp.Method)
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// diskLocateConfig returns the NATS URL and the subject of the flags and
+// environment variables of disk locate
+func diskLocateConfig() (natsURL, subject string) {
+	return telemetry.GetEnv("NATS_URL", diskNatsURL), telemetry.GetEnv("LOCATE_SUBJECT", diskLocateSubject)
+}
+
+func init() {
+	diskLocateCmd.Flags().StringVar(&diskNatsURL, "nats-url", "", "NATS server URL")
+	diskLocateCmd.Flags().StringVar(&diskLocateSubject, "subject", diskhealthmetrics.DefaultLocateSubject, "NATS subject the producers answer locate requests on (--locate-subject)")
+	diskLocateCmd.Flags().BoolVar(&diskLocateOff, "off", false, "Turn the LED off again")
+	diskLocateCmd.Flags().DurationVar(&diskLocateTimeout, "timeout", time.Minute, "Time to wait for the producer with the drive to switch the LED")
+	diskLocateCmd.Flags().StringVarP(&diskOutput, "output", "o", "text", "Output format (text, json)")
+
+	diskCmd.AddCommand(diskLocateCmd)
+}
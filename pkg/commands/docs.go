@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/consumer/quotausageconsumer"
+	"github.com/cobaltcore-dev/prysm/pkg/consumer/sinkconsumer"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/bucketnotify"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/cephhealth"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/kernelmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/quotausagemonitor"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/resourceusage"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var docsFormat string
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Describe all subcommands, flags and environment variables",
+	Long: `Describe all subcommands of prysm with their flags and the environment
+variables they read, e.g. to generate runbooks or Helm values.
+
+The environment variables of a command are found by reading its
+configuration, so they are the ones the command reads when it runs. A flag
+names its environment variable if it is the name of the flag in upper case,
+e.g. NATS_URL of --nats-url; the others, e.g. LOG_FILE_PATH of --log-file,
+are only in the list of the command.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if docsFormat != "json" {
+			return fmt.Errorf("unknown format %q, expected json", docsFormat)
+		}
+		data, err := json.MarshalIndent(describeCLI(rootCmd), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	},
+}
+
+// cliDoc is the machine-readable description of prysm
+type cliDoc struct {
+	Version  string       `json:"version"`
+	Commands []commandDoc `json:"commands"`
+}
+
+// commandDoc describes a subcommand
+type commandDoc struct {
+	Path           string    `json:"path"` // e.g. "prysm local-producer ops-log"
+	Short          string    `json:"short,omitempty"`
+	Long           string    `json:"long,omitempty"`
+	Runnable       bool      `json:"runnable"` // false for groups of subcommands
+	Flags          []flagDoc `json:"flags,omitempty"`
+	InheritedFlags []flagDoc `json:"inherited_flags,omitempty"`
+	Env            []string  `json:"env,omitempty"` // Read by the command, including the ones of the inherited flags
+}
+
+// flagDoc describes a flag
+type flagDoc struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+	Env       string `json:"env,omitempty"`
+}
+
+// envReaders read the configuration of the commands from the environment
+// like the commands do when they run; the environment variables of a
+// command are the ones of its reader and the readers of its parents
+var envReaders map[*cobra.Command]func(cmd *cobra.Command)
+
+func init() {
+	envReaders = map[*cobra.Command]func(cmd *cobra.Command){
+		// The global settings are set up again from the flags
+		rootCmd:              func(cmd *cobra.Command) { _ = rootCmd.PersistentPreRunE(cmd, nil) },
+		localProducerCmd:     func(*cobra.Command) { publishConfigSettings() },
+		remoteProducerCmd:    func(*cobra.Command) { publishConfigSettings() },
+		opsLogCmd:            func(*cobra.Command) { opsLogConfig() },
+		diskHealthMetricsCmd: func(*cobra.Command) { diskHealthMetricsConfig() },
+		osdPerfCmd:           func(*cobra.Command) { osdPerfConfig() },
+		nodeInventoryCmd:     func(*cobra.Command) { nodeInventoryConfig() },
+		radosGWUsageCmd:      func(*cobra.Command) { radosGWUsageConfig() },
+		bucketNotifyCmd: func(*cobra.Command) {
+			mergeBucketNotifyConfigWithEnv(bucketnotify.BucketNotifyConfig{})
+		},
+		cephHealthCmd: func(*cobra.Command) { mergeCephHealthConfigWithEnv(cephhealth.CephHealthConfig{}) },
+		kernelMetricsCmd: func(*cobra.Command) {
+			mergeKernelMetricsConfigWithEnv(kernelmetrics.KernelMetricsConfig{})
+		},
+		resourceUsageCmd: func(*cobra.Command) {
+			mergeResourceUsageConfigWithEnv(resourceusage.ResourceUsageConfig{})
+		},
+		quotaUsageMonitorCmd: func(*cobra.Command) {
+			mergeQuotaUsageMonitorConfigWithEnv(quotausagemonitor.QuotaUsageMonitorConfig{})
+		},
+		heatmapConsumerCmd: func(*cobra.Command) { mergeHeatmapConsumerConfigWithEnv(heatmapConsumerConfig) },
+		quotaUsageConsumerCmd: func(*cobra.Command) {
+			mergeQuotaUsageConsumerConfigWithEnv(quotausageconsumer.QuotaUsageConsumerConfig{})
+		},
+		opsLogConsumerCmd:       func(*cobra.Command) { mergeSinkConsumerConfigWithEnv(sinkconsumer.SinkConsumerConfig{}) },
+		radosGWUsageConsumerCmd: func(*cobra.Command) { mergeSinkConsumerConfigWithEnv(sinkconsumer.SinkConsumerConfig{}) },
+		eventsConsumerCmd:       func(*cobra.Command) { mergeSinkConsumerConfigWithEnv(sinkconsumer.SinkConsumerConfig{}) },
+		doctorCmd:               func(*cobra.Command) { mergeDoctorConfigWithEnv(doctorConfig) },
+		alertsGenerateCmd: func(*cobra.Command) {
+			mergeAlertsConfigWithEnv(alertsConfig)
+			opsLogConfig()
+			radosGWUsageConfig()
+			diskHealthMetricsConfig()
+		},
+		dashboardsGenerateCmd: func(*cobra.Command) {
+			opsLogConfig()
+			radosGWUsageConfig()
+			diskHealthMetricsConfig()
+		},
+		configDiffCmd: func(*cobra.Command) { configDiffConfig() },
+		aggregateCmd:  func(*cobra.Command) { aggregateConfig() },
+		diskLocateCmd: func(*cobra.Command) { diskLocateConfig() },
+		agentCmd: func(*cobra.Command) {
+			agentConfig()
+			remoteWriteConfig(agentRemoteWrite)
+			publishConfigSettings()
+			for _, producer := range agentProducers {
+				telemetry.SetEnvPrefix(agentEnvPrefix(producer.cmd.Name()))
+				producer.configure(agentSettings{})
+			}
+			telemetry.SetEnvPrefix("")
+		},
+	}
+}
+
+// describeCLI describes root and all its available subcommands. A command
+// added to several parents, e.g. bucket-notify, is described once.
+func describeCLI(root *cobra.Command) cliDoc {
+	doc := cliDoc{Version: version.Get().Version}
+	described := map[*cobra.Command]bool{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if described[cmd] {
+			return
+		}
+		described[cmd] = true
+		doc.Commands = append(doc.Commands, describeCommand(cmd))
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				walk(sub)
+			}
+		}
+	}
+	walk(root)
+	return doc
+}
+
+func describeCommand(cmd *cobra.Command) commandDoc {
+	env := telemetry.TraceEnv(func() {
+		for parent := cmd; parent != nil; parent = parent.Parent() {
+			if read, ok := envReaders[parent]; ok {
+				read(cmd)
+			}
+		}
+	})
+	read := make(map[string]bool, len(env))
+	for _, key := range env {
+		read[key] = true
+	}
+
+	return commandDoc{
+		Path:           cmd.CommandPath(),
+		Short:          cmd.Short,
+		Long:           cmd.Long,
+		Runnable:       cmd.Runnable(),
+		Flags:          describeFlags(cmd.LocalFlags(), read),
+		InheritedFlags: describeFlags(cmd.InheritedFlags(), read),
+		Env:            env,
+	}
+}
+
+// describeFlags describes the visible flags; env are the environment
+// variables read by the command
+func describeFlags(flags *pflag.FlagSet, env map[string]bool) []flagDoc {
+	var docs []flagDoc
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || flag.Deprecated != "" {
+			return
+		}
+		doc := flagDoc{
+			Name:      flag.Name,
+			Shorthand: flag.Shorthand,
+			Type:      flag.Value.Type(),
+			Default:   flag.DefValue,
+			Usage:     flag.Usage,
+		}
+		if key := flagEnv(flag.Name); env[key] {
+			doc.Env = key
+		}
+		docs = append(docs, doc)
+	})
+	return docs
+}
+
+// flagEnv is the environment variable named like a flag, e.g. NATS_URL of
+// nats-url or OSD_PERF_INTERVAL of the agent flag osd-perf.interval
+func flagEnv(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+func init() {
+	docsCmd.Flags().StringVar(&docsFormat, "format", "json", "Output format (json)")
+}
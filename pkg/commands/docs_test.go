@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeCLI(t *testing.T) {
+	// The environment is not read, the docs do not depend on it
+	t.Setenv("NATS_URL", "nats://nats:4222")
+
+	commands := map[string]commandDoc{}
+	for _, command := range describeCLI(rootCmd).Commands {
+		_, repeated := commands[command.Path]
+		assert.False(t, repeated, "%s described twice", command.Path)
+		commands[command.Path] = command
+	}
+
+	opsLog, ok := commands["prysm local-producer ops-log"]
+	require.True(t, ok)
+	assert.True(t, opsLog.Runnable)
+	assert.Contains(t, opsLog.Flags, flagDoc{Name: "nats-url", Type: "string", Usage: "NATS server URL", Env: "NATS_URL"})
+	assert.Contains(t, opsLog.Env, "LOG_FILE_PATH")
+	assert.Contains(t, opsLog.Env, "PUBLISH_CONFIG")
+	assert.Contains(t, opsLog.InheritedFlags, findFlag(t, commands["prysm"].Flags, "log-level"))
+	assert.Equal(t, "LOG_LEVEL", findFlag(t, opsLog.InheritedFlags, "log-level").Env)
+
+	// The flags of the producers are added to the agent by Execute, their
+	// environment variables are prefixed with the producer
+	agent := commands["prysm agent"]
+	assert.Contains(t, agent.Env, "OSD_PERF_INTERVAL")
+	assert.Contains(t, agent.Env, "OPS_LOG_LOG_FILE_PATH")
+	assert.Contains(t, agent.Env, "PRODUCERS")
+
+	assert.False(t, commands["prysm local-producer"].Runnable)
+	assert.Contains(t, commands, "prysm completion")
+}
+
+func findFlag(t *testing.T, flags []flagDoc, name string) flagDoc {
+	t.Helper()
+	for _, flag := range flags {
+		if flag.Name == name {
+			return flag
+		}
+	}
+	t.Fatalf("flag %s not described", name)
+	return flagDoc{}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/doctor"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorConfig doctor.Config
+	doctorOutput string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the prerequisites of the producers",
+	Long: `Check the prerequisites of the producers on this host: the RGW ops log
+configuration, smartctl and nvme-cli, disk access privileges, NATS and the
+RadosGW admin API. Every problem found comes with the steps to fix it.
+
+Options take the same environment variables as the producers, e.g. NATS_URL
+or ADMIN_URL; checks of options that are not set are skipped. Exits with 1
+if a check failed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := mergeDoctorConfigWithEnv(doctorConfig)
+
+		results, err := doctor.Run(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		switch doctorOutput {
+		case "text":
+			doctor.Print(cmd.OutOrStdout(), results)
+		case "json":
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		default:
+			return fmt.Errorf("unknown output %q, expected text or json", doctorOutput)
+		}
+
+		if failed := doctor.Failed(results); failed > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "\n%d checks failed\n", failed)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func mergeDoctorConfigWithEnv(cfg doctor.Config) doctor.Config {
+	cfg.LogFilePath = telemetry.GetEnv("LOG_FILE_PATH", cfg.LogFilePath)
+	cfg.SocketPath = telemetry.GetEnv("SOCKET_PATH", cfg.SocketPath)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.AdminURL = telemetry.GetEnv("ADMIN_URL", cfg.AdminURL)
+	cfg.AccessKey = getEnvSecret("ACCESS_KEY", cfg.AccessKey)
+	cfg.SecretKey = getEnvSecret("SECRET_KEY", cfg.SecretKey)
+	cfg.CephEntity = telemetry.GetEnv("CEPH_ENTITY", cfg.CephEntity)
+	return cfg
+}
+
+func init() {
+	doctorCmd.Flags().StringSliceVar(&doctorConfig.Producers, "producer", nil, "Producers to check ("+strings.Join(doctor.Producers, ", ")+"), all if not set")
+	doctorCmd.Flags().StringVar(&doctorConfig.CephBinary, "ceph", "ceph", "Path of the ceph CLI")
+	doctorCmd.Flags().StringVar(&doctorConfig.CephEntity, "ceph-entity", "client.rgw", "Config section of the RGW daemons, e.g. client.rgw.my.store.a")
+	doctorCmd.Flags().StringVar(&doctorConfig.LogFilePath, "log-file", "", "Ops log file the ops-log producer reads")
+	doctorCmd.Flags().StringVar(&doctorConfig.SocketPath, "socket-path", "", "Unix socket the ops-log producer listens on")
+	doctorCmd.Flags().StringVar(&doctorConfig.NatsURL, "nats-url", "", "NATS server URL")
+	doctorCmd.Flags().StringVar(&doctorConfig.AdminURL, "admin-url", "", "RadosGW admin URL")
+	doctorCmd.Flags().StringVar(&doctorConfig.AccessKey, "access-key", "", "Access key of the RadosGW admin user")
+	doctorCmd.Flags().StringVar(&doctorConfig.SecretKey, "secret-key", "", "Secret key of the RadosGW admin user")
+	doctorCmd.Flags().DurationVar(&doctorConfig.Timeout, "timeout", doctor.DefaultTimeout, "Timeout of every check")
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "text", "Output format (text, json)")
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/fleet"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// Set on the producer commands
+	publishConfig bool
+	configBucket  string
+
+	fleetNatsURL   string
+	fleetBucket    string
+	fleetProducers []string
+	fleetReference string
+	fleetIgnore    []string
+	fleetOutput    string
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Commands about all producers of the fleet",
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "config-diff",
+	Short: "Report the producers whose configuration deviates from a reference",
+	Long: `Report the producers whose configuration deviates from a reference. The
+producers publish their effective configuration to a NATS key-value bucket
+with --publish-config. For each producer the configurations are compared with
+the one of the node given with --reference, or with the most common one.
+
+Secrets are not published. The node name and instance ID are not compared,
+other fields differing by design, e.g. Disks, are left out with --ignore.
+Exits with 1 if a configuration deviates.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		natsURL, bucket := configDiffConfig()
+		if natsURL == "" {
+			return fmt.Errorf("--nats-url is required")
+		}
+		nc, err := natsutil.Connect(natsURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to NATS: %w", err)
+		}
+		defer nc.Close()
+
+		records, err := fleet.Load(nc, bucket)
+		if err != nil {
+			return err
+		}
+		if len(fleetProducers) > 0 {
+			selected := records[:0]
+			for _, record := range records {
+				for _, producer := range fleetProducers {
+					if record.Producer == producer {
+						selected = append(selected, record)
+					}
+				}
+			}
+			records = selected
+		}
+
+		reports, err := fleet.Compare(records, fleetReference, fleetIgnore)
+		if err != nil {
+			return err
+		}
+
+		switch fleetOutput {
+		case "text":
+			printConfigDiff(cmd.OutOrStdout(), reports)
+		case "json":
+			data, err := json.MarshalIndent(reports, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		default:
+			return fmt.Errorf("unknown output %q, expected text or json", fleetOutput)
+		}
+
+		for _, report := range reports {
+			if len(report.Deviations) > 0 {
+				os.Exit(1)
+			}
+		}
+		return nil
+	},
+}
+
+// printConfigDiff prints the deviating fields of every producer
+func printConfigDiff(w io.Writer, reports []fleet.Report) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "no configurations published")
+		return
+	}
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s: %d of %d match %s\n", report.Producer, report.Matching, report.Records, report.Reference)
+		for _, deviation := range report.Deviations {
+			fmt.Fprintf(w, "  %s (%s)\n", deviation.Name, deviation.Version)
+			for _, d := range deviation.Differences {
+				fmt.Fprintf(w, "    %s: %s, reference %s\n", d.Field, configValue(d.Value), configValue(d.Reference))
+			}
+		}
+	}
+}
+
+func configValue(v any) string {
+	if v == nil {
+		return "unset"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// configDiffConfig returns the NATS URL and the config bucket of the flags
+// and environment variables of config-diff
+func configDiffConfig() (natsURL, bucket string) {
+	return telemetry.GetEnv("NATS_URL", fleetNatsURL), telemetry.GetEnv("CONFIG_BUCKET", fleetBucket)
+}
+
+// publishConfigSettings returns whether the producers publish their
+// configuration and the bucket they publish it to
+func publishConfigSettings() (bool, string) {
+	return telemetry.GetEnvBool("PUBLISH_CONFIG", publishConfig), telemetry.GetEnv("CONFIG_BUCKET", configBucket)
+}
+
+// publishProducerConfig publishes the effective configuration of a producer
+// to the config bucket with --publish-config. Failures are logged, the
+// producer runs anyway.
+func publishProducerConfig(producer, natsURL, nodeName, instanceID string, config any) {
+	publish, bucket := publishConfigSettings()
+	if !publish {
+		return
+	}
+	if natsURL == "" {
+		log.Warn().Str("producer", producer).Msg("not publishing the configuration without a NATS URL")
+		return
+	}
+	if nodeName == "" {
+		nodeName, _ = os.Hostname()
+	}
+
+	record, err := fleet.NewRecord(producer, nodeName, instanceID, version.Get().Version, config)
+	if err != nil {
+		log.Warn().Err(err).Str("producer", producer).Msg("failed to publish the configuration")
+		return
+	}
+	// A connection of its own, closing it leaves the shared one of the agent
+	nc, err := natsutil.Connect(natsURL, nats.Name("prysm-"+producer+"-config"))
+	if err != nil {
+		log.Warn().Err(err).Str("producer", producer).Msg("failed to publish the configuration")
+		return
+	}
+	defer nc.Close()
+
+	if err := fleet.Publish(nc, bucket, record); err != nil {
+		log.Warn().Err(err).Str("producer", producer).Msg("failed to publish the configuration")
+		return
+	}
+	log.Info().Str("producer", producer).Str("bucket", bucket).Str("key", record.Key()).Str("hash", record.Hash).Msg("configuration published")
+}
+
+// registerPublishConfigFlags registers the flags publishing the configuration
+// of the producers
+func registerPublishConfigFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&publishConfig, "publish-config", false, "Publish the effective configuration to NATS for prysm fleet config-diff")
+	flags.StringVar(&configBucket, "config-bucket", fleet.DefaultBucket, "Key-value bucket the configuration is published to")
+}
+
+func init() {
+	configDiffCmd.Flags().StringVar(&fleetNatsURL, "nats-url", "", "NATS server URL")
+	configDiffCmd.Flags().StringVar(&fleetBucket, "bucket", fleet.DefaultBucket, "Key-value bucket the producers publish their configuration to")
+	configDiffCmd.Flags().StringSliceVar(&fleetProducers, "producer", nil, "Producers to compare, all if not set")
+	configDiffCmd.Flags().StringVar(&fleetReference, "reference", "", "Node whose configuration is the reference, the most common configuration if not set")
+	configDiffCmd.Flags().StringSliceVar(&fleetIgnore, "ignore", nil, "Fields not compared, e.g. Disks or MetricsConfig.TrackLatencyDetailed")
+	configDiffCmd.Flags().StringVarP(&fleetOutput, "output", "o", "text", "Output format (text, json)")
+
+	fleetCmd.AddCommand(configDiffCmd)
+}
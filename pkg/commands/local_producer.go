@@ -5,10 +5,11 @@
 package commands
 
 import (
-	"log"
 	"sync"
 
+	"github.com/cobaltcore-dev/prysm/pkg/debugserver"
 	"github.com/cobaltcore-dev/prysm/pkg/producers/config"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -25,7 +26,7 @@ var useConfigCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.LoadConfig(configFilePath)
 		if err != nil {
-			log.Fatalf("Failed to load config: %v", err)
+			log.Fatal().Err(err).Msg("failed to load config")
 		}
 
 		var wg sync.WaitGroup
@@ -40,6 +41,9 @@ var useConfigCmd = &cobra.Command{
 }
 
 func init() {
+	localProducerCmd.PersistentFlags().IntVar(&debugPort, "debug-port", 0, "Port for the pprof/runtime debug server (0 disables)")
+	localProducerCmd.PersistentFlags().StringVar(&debugAddress, "debug-address", debugserver.DefaultAddress, "Address the debug server listens on (all interfaces if empty)")
+	registerPublishConfigFlags(localProducerCmd.PersistentFlags())
 	useConfigCmd.Flags().StringVar(&configFilePath, "config", "", "Path to configuration file")
 	useConfigCmd.MarkFlagRequired("config")
 	localProducerCmd.AddCommand(useConfigCmd)
@@ -48,5 +52,8 @@ func init() {
 	localProducerCmd.AddCommand(bucketNotifyCmd)
 	localProducerCmd.AddCommand(diskHealthMetricsCmd)
 	localProducerCmd.AddCommand(kernelMetricsCmd)
+	localProducerCmd.AddCommand(cephHealthCmd)
+	localProducerCmd.AddCommand(osdPerfCmd)
+	localProducerCmd.AddCommand(nodeInventoryCmd)
 	localProducerCmd.AddCommand(resourceUsageCmd)
 }
@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/bucketnotify"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -50,15 +51,16 @@ Rook: https://rook.io/docs/rook/latest-release/Storage-Configuration/Object-Stor
 		event.Msg("configuration_loaded")
 
 		validateBucketNotifyConfig(config)
+		publishProducerConfig("bucket-notify", config.NatsURL, "", "", config)
 
 		bucketnotify.StartBucketNotifyServer(config)
 	},
 }
 
 func mergeBucketNotifyConfigWithEnv(cfg bucketnotify.BucketNotifyConfig) bucketnotify.BucketNotifyConfig {
-	cfg.EndpointPort = getEnvInt("BUCKET_NOTIFY_ENDPOINT_PORT", cfg.EndpointPort)
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.EndpointPort = telemetry.GetEnvInt("BUCKET_NOTIFY_ENDPOINT_PORT", cfg.EndpointPort)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
 
 	return cfg
 }
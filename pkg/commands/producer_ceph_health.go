@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/cephhealth"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	chSource        string
+	chCephBinary    string
+	chCephConf      string
+	chCephName      string
+	chCephKeyring   string
+	chMgrURL        string
+	chMgrUser       string
+	chMgrKey        string
+	chMgrInsecure   bool
+	chInterval      int
+	chTimeout       int
+	chNatsURL       string
+	chNatsSubject   string
+	chEventsSubject string
+	chPromEnabled   bool
+	chPromPort      int
+	chNodeName      string
+	chInstanceID    string
+)
+
+var cephHealthCmd = &cobra.Command{
+	Use:   "ceph-health",
+	Short: "Ceph cluster health, placement group and recovery metrics from ceph status",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := cephhealth.CephHealthConfig{
+			Source:         chSource,
+			CephBinary:     chCephBinary,
+			CephConf:       chCephConf,
+			CephName:       chCephName,
+			CephKeyring:    chCephKeyring,
+			MgrURL:         chMgrURL,
+			MgrUser:        chMgrUser,
+			MgrKey:         chMgrKey,
+			MgrInsecure:    chMgrInsecure,
+			Interval:       chInterval,
+			Timeout:        chTimeout,
+			NatsURL:        chNatsURL,
+			NatsSubject:    chNatsSubject,
+			EventsSubject:  chEventsSubject,
+			Prometheus:     chPromEnabled,
+			PrometheusPort: chPromPort,
+			NodeName:       chNodeName,
+			InstanceID:     chInstanceID,
+		}
+
+		config = mergeCephHealthConfigWithEnv(config)
+		config.UseNats = config.NatsURL != ""
+
+		event := log.Info()
+		event.Str("source", config.Source)
+		if config.Source == cephhealth.SourceMgr {
+			event.Str("mgr_url", config.MgrURL)
+			event.Str("mgr_user", config.MgrUser)
+		} else {
+			event.Str("ceph_name", config.CephName)
+		}
+
+		event.Bool("use_nats", config.UseNats)
+		if config.UseNats {
+			event.Str("nats_url", config.NatsURL)
+			event.Str("nats_subject", config.NatsSubject)
+			event.Str("events_subject", config.EventsSubject)
+		}
+
+		event.Bool("prometheus_enabled", config.Prometheus)
+		if config.Prometheus {
+			event.Int("prometheus_port", config.PrometheusPort)
+		}
+
+		event.Str("node_name", config.NodeName)
+		event.Str("instance_id", config.InstanceID)
+		event.Int("interval_seconds", config.Interval)
+
+		event.Msg("configuration_loaded")
+
+		validateCephHealthConfig(config)
+		publishProducerConfig("ceph-health", config.NatsURL, config.NodeName, config.InstanceID, config)
+
+		cephhealth.StartMonitoring(config)
+	},
+}
+
+func mergeCephHealthConfigWithEnv(cfg cephhealth.CephHealthConfig) cephhealth.CephHealthConfig {
+	cfg.Source = telemetry.GetEnv("CEPH_STATUS_SOURCE", cfg.Source)
+	cfg.CephBinary = telemetry.GetEnv("CEPH_BINARY", cfg.CephBinary)
+	cfg.CephConf = telemetry.GetEnv("CEPH_CONF", cfg.CephConf)
+	cfg.CephName = telemetry.GetEnv("CEPH_NAME", cfg.CephName)
+	cfg.CephKeyring = telemetry.GetEnv("CEPH_KEYRING", cfg.CephKeyring)
+	cfg.MgrURL = telemetry.GetEnv("MGR_URL", cfg.MgrURL)
+	cfg.MgrUser = telemetry.GetEnv("MGR_USER", cfg.MgrUser)
+	cfg.MgrKey = telemetry.GetEnv("MGR_KEY", cfg.MgrKey)
+	cfg.MgrInsecure = telemetry.GetEnvBool("MGR_INSECURE", cfg.MgrInsecure)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.Timeout = telemetry.GetEnvInt("TIMEOUT", cfg.Timeout)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.EventsSubject = telemetry.GetEnv("EVENTS_SUBJECT", cfg.EventsSubject)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+
+	return cfg
+}
+
+func init() {
+	cephHealthCmd.Flags().StringVar(&chSource, "source", cephhealth.SourceCLI, "Where to read the cluster status: cli (ceph status) or mgr (restful mgr module)")
+	cephHealthCmd.Flags().StringVar(&chCephBinary, "ceph", "ceph", "Path of the ceph CLI")
+	cephHealthCmd.Flags().StringVar(&chCephConf, "ceph-conf", "", "Ceph configuration file, the ceph CLI default if not set")
+	cephHealthCmd.Flags().StringVar(&chCephName, "ceph-name", "", "Ceph user, e.g. client.prysm")
+	cephHealthCmd.Flags().StringVar(&chCephKeyring, "ceph-keyring", "", "Keyring of the Ceph user")
+	cephHealthCmd.Flags().StringVar(&chMgrURL, "mgr-url", "", "URL of the restful mgr module, e.g. https://rook-ceph-mgr:8003")
+	cephHealthCmd.Flags().StringVar(&chMgrUser, "mgr-user", "", "User of the restful mgr module")
+	cephHealthCmd.Flags().StringVar(&chMgrKey, "mgr-key", "", "Key of the user, from ceph restful create-key")
+	cephHealthCmd.Flags().BoolVar(&chMgrInsecure, "mgr-insecure", false, "Skip verifying the certificate of the mgr, it is self-signed by default")
+	cephHealthCmd.Flags().IntVar(&chInterval, "interval", 30, "Interval in seconds between status polls")
+	cephHealthCmd.Flags().IntVar(&chTimeout, "timeout", 10, "Timeout in seconds of a status poll")
+	cephHealthCmd.Flags().StringVar(&chNatsURL, "nats-url", "", "NATS server URL")
+	cephHealthCmd.Flags().StringVar(&chNatsSubject, "nats-subject", "ceph.status", "NATS subject to publish status snapshots")
+	cephHealthCmd.Flags().StringVar(&chEventsSubject, "events-subject", "ceph.health.events", "NATS subject to publish health changes")
+	cephHealthCmd.Flags().BoolVar(&chPromEnabled, "prometheus", false, "Enable Prometheus metrics")
+	cephHealthCmd.Flags().IntVar(&chPromPort, "prometheus-port", 8080, "Prometheus metrics port")
+	cephHealthCmd.Flags().StringVar(&chNodeName, "node-name", "", "Name of the node")
+	cephHealthCmd.Flags().StringVar(&chInstanceID, "instance-id", "", "Instance ID")
+}
+
+func validateCephHealthConfig(config cephhealth.CephHealthConfig) {
+	missingParams := false
+
+	switch config.Source {
+	case cephhealth.SourceCLI:
+	case cephhealth.SourceMgr:
+		if config.MgrURL == "" || config.MgrUser == "" || config.MgrKey == "" {
+			fmt.Println("Warning: --mgr-url, --mgr-user and --mgr-key (or MGR_URL, MGR_USER, MGR_KEY) must be set with --source=mgr")
+			missingParams = true
+		}
+	default:
+		fmt.Printf("Warning: unknown --source %q, expected cli or mgr\n", config.Source)
+		missingParams = true
+	}
+	if config.Interval <= 0 || config.Timeout <= 0 {
+		fmt.Println("Warning: --interval and --timeout must be positive")
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -20,17 +21,59 @@ var (
 	dhmUseNats                     bool
 	dhmPromEnabled                 bool
 	dhmPromPort                    int
+	dhmRemoteWrite                 remoteWriteFlags
 	dhmAllAttributes               bool
 	dhmDisksFlag                   string
 	dhmNodeName                    string
 	dhmInstanceID                  string
+	dhmKubernetes                  bool
+	dhmZone                        string
+	dhmRack                        string
+	dhmRackLabel                   string
+	dhmProbePort                   int
 	dhmIncludeZeroValues           bool
+	dhmExportAttributes            string
+	dhmExcludeAttributes           string
 	dhmInterval                    int
+	dhmScanSchedules               string
 	dhmGrownDefectsThreshold       int64
 	dhmPendingSectorsThreshold     int64
 	dhmReallocatedSectorsThreshold int64
 	dhmLifetimeUsedThreshold       int64
+	dhmRiskWarningThreshold        float64
+	dhmRiskCriticalThreshold       float64
+	dhmEnduranceWarrantyYears      int
+	dhmTemperatureThresholds       string
+	dhmTemperatureHysteresis       int64
 	dhmCephOSDBasePath             string
+	dhmCephCluster                 string
+	dhmChangeEventsSubject         string
+	dhmPassthroughDevicesPath      string
+	dhmDiscoverRAID                bool
+	dhmScanConcurrency             int
+	dhmDeviceTimeout               int
+	dhmHotplug                     bool
+	dhmDeviceDBPath                string
+	dhmHistoryPath                 string
+	dhmHistoryKVBucket             string
+	dhmSnapshotPath                string
+	dhmSnapshotSubject             string
+	dhmFirmwareReportPath          string
+	dhmFirmwareReportSubject       string
+	dhmReplacementSubject          string
+	dhmLocateSubject               string
+	dhmNVMeTelemetry               bool
+	dhmKernelIO                    bool
+	dhmEnclosureSlots              bool
+	dhmChassis                     string
+	dhmSelfTest                    bool
+	dhmSelfTestShortInterval       int
+	dhmSelfTestLongInterval        int
+	dhmSelfTestWindow              string
+	dhmSelfTestStagger             int
+	dhmSmartdStateDir              string
+	dhmSmartdLogPath               string
+	dhmMockSmartctlDir             string
 	dhmTestMode                    bool
 	dhmTestDataPath                string
 	dhmTestScenario                string
@@ -41,88 +84,269 @@ var diskHealthMetricsCmd = &cobra.Command{
 	Use:   "disk-health-metrics",
 	Short: "Disk health metrics collector and media error logger",
 	Run: func(cmd *cobra.Command, args []string) {
-		config := diskhealthmetrics.DiskHealthMetricsConfig{
-			NatsURL:                     dhmNatsURL,
-			NatsSubject:                 dhmNatsSubject,
-			UseNats:                     dhmUseNats,
-			Prometheus:                  dhmPromEnabled,
-			PrometheusPort:              dhmPromPort,
-			AllAttributes:               dhmAllAttributes,
-			Disks:                       strings.Split(dhmDisksFlag, ","),
-			NodeName:                    dhmNodeName,
-			InstanceID:                  dhmInstanceID,
-			IncludeZeroValues:           dhmIncludeZeroValues,
-			Interval:                    dhmInterval,
-			GrownDefectsThreshold:       dhmGrownDefectsThreshold,
-			PendingSectorsThreshold:     dhmPendingSectorsThreshold,
-			ReallocatedSectorsThreshold: dhmReallocatedSectorsThreshold,
-			LifetimeUsedThreshold:       dhmLifetimeUsedThreshold,
-			CephOSDBasePath:             dhmCephOSDBasePath,
-			TestMode:                    dhmTestMode,
-			TestDataPath:                dhmTestDataPath,
-			TestScenario:                dhmTestScenario,
-		}
+		runDiskHealthMetrics(diskHealthMetricsConfig())
+	},
+}
 
-		// Parse test devices if provided
-		if dhmTestDevices != "" {
-			config.TestDevices = strings.Split(dhmTestDevices, ",")
-		}
+// diskHealthMetricsConfig returns the configuration of the flags and
+// environment variables
+func diskHealthMetricsConfig() diskhealthmetrics.DiskHealthMetricsConfig {
+	config := diskhealthmetrics.DiskHealthMetricsConfig{
+		NatsURL:                     dhmNatsURL,
+		NatsSubject:                 dhmNatsSubject,
+		ChangeEventsSubject:         dhmChangeEventsSubject,
+		UseNats:                     dhmUseNats,
+		Prometheus:                  dhmPromEnabled,
+		PrometheusPort:              dhmPromPort,
+		AllAttributes:               dhmAllAttributes,
+		Disks:                       strings.Split(dhmDisksFlag, ","),
+		PassthroughDevicesPath:      dhmPassthroughDevicesPath,
+		DiscoverRAID:                dhmDiscoverRAID,
+		ScanConcurrency:             dhmScanConcurrency,
+		DeviceTimeout:               dhmDeviceTimeout,
+		Hotplug:                     dhmHotplug,
+		NodeName:                    dhmNodeName,
+		InstanceID:                  dhmInstanceID,
+		Kubernetes:                  dhmKubernetes,
+		Zone:                        dhmZone,
+		Rack:                        dhmRack,
+		RackLabel:                   dhmRackLabel,
+		ProbePort:                   dhmProbePort,
+		IncludeZeroValues:           dhmIncludeZeroValues,
+		Interval:                    dhmInterval,
+		GrownDefectsThreshold:       dhmGrownDefectsThreshold,
+		PendingSectorsThreshold:     dhmPendingSectorsThreshold,
+		ReallocatedSectorsThreshold: dhmReallocatedSectorsThreshold,
+		LifetimeUsedThreshold:       dhmLifetimeUsedThreshold,
+		RiskWarningThreshold:        dhmRiskWarningThreshold,
+		RiskCriticalThreshold:       dhmRiskCriticalThreshold,
+		EnduranceWarrantyYears:      dhmEnduranceWarrantyYears,
+		TemperatureHysteresis:       dhmTemperatureHysteresis,
+		CephOSDBasePath:             dhmCephOSDBasePath,
+		CephCluster:                 dhmCephCluster,
+		DeviceDBPath:                dhmDeviceDBPath,
+		HistoryPath:                 dhmHistoryPath,
+		HistoryKVBucket:             dhmHistoryKVBucket,
+		SnapshotPath:                dhmSnapshotPath,
+		SnapshotSubject:             dhmSnapshotSubject,
+		FirmwareReportPath:          dhmFirmwareReportPath,
+		FirmwareReportSubject:       dhmFirmwareReportSubject,
+		ReplacementSubject:          dhmReplacementSubject,
+		LocateSubject:               dhmLocateSubject,
+		NVMeTelemetry:               dhmNVMeTelemetry,
+		KernelIO:                    dhmKernelIO,
+		EnclosureSlots:              dhmEnclosureSlots,
+		Chassis:                     dhmChassis,
+		SelfTest:                    dhmSelfTest,
+		SelfTestShortIntervalHours:  dhmSelfTestShortInterval,
+		SelfTestLongIntervalHours:   dhmSelfTestLongInterval,
+		SelfTestWindow:              dhmSelfTestWindow,
+		SelfTestStaggerMinutes:      dhmSelfTestStagger,
+		SmartdStateDir:              dhmSmartdStateDir,
+		SmartdLogPath:               dhmSmartdLogPath,
+		MockSmartctlDir:             dhmMockSmartctlDir,
+		TestMode:                    dhmTestMode,
+		TestDataPath:                dhmTestDataPath,
+		TestScenario:                dhmTestScenario,
+	}
 
-		config = mergeDiskHealthMetricsConfigWithEnv(config)
+	if dhmExportAttributes != "" {
+		config.ExportAttributes = strings.Split(dhmExportAttributes, ",")
+	}
+	if dhmExcludeAttributes != "" {
+		config.ExcludeAttributes = strings.Split(dhmExcludeAttributes, ",")
+	}
 
-		config.UseNats = config.NatsURL != ""
+	// Parse test devices if provided
+	if dhmTestDevices != "" {
+		config.TestDevices = strings.Split(dhmTestDevices, ",")
+	}
 
-		event := log.Info()
-		event.Bool("use_nats", config.UseNats)
-		if config.UseNats {
-			event.Str("nats_url", config.NatsURL)
-			event.Str("nats_subject", config.NatsSubject)
-		}
+	config = mergeDiskHealthMetricsConfigWithEnv(config)
+	config.RemoteWrite = remoteWriteConfig(dhmRemoteWrite)
 
-		event.Bool("prometheus_enabled", config.Prometheus)
-		if config.Prometheus {
-			event.Int("prometheus_port", config.PrometheusPort)
-		}
+	temperatureThresholds := telemetry.GetEnv("TEMPERATURE_THRESHOLDS", dhmTemperatureThresholds)
+	thresholds, err := diskhealthmetrics.ParseTemperatureThresholds(temperatureThresholds)
+	if err != nil {
+		fmt.Printf("Warning: invalid --temperature-thresholds: %v\n", err)
+		os.Exit(1)
+	}
+	config.TemperatureThresholds = thresholds
 
-		event.Bool("all_attributes", config.AllAttributes).
-			Str("disks", fmt.Sprintf("%v", config.Disks)).
-			Str("node_name", config.NodeName).
-			Str("instance_id", config.InstanceID).
-			Int("interval_seconds", config.Interval).
-			Str("ceph_osd_base_path", config.CephOSDBasePath)
-		event.Msg("configuration_loaded")
+	scanSchedules, err := diskhealthmetrics.ParseScanSchedules(telemetry.GetEnv("SCAN_SCHEDULES", dhmScanSchedules))
+	if err != nil {
+		fmt.Printf("Warning: invalid --scan-schedules: %v\n", err)
+		os.Exit(1)
+	}
+	config.ScanSchedules = scanSchedules
 
-		validateDiskHealthMetricsConfig(config)
+	return config
+}
 
-		diskhealthmetrics.StartMonitoring(config)
-	},
+func runDiskHealthMetrics(config diskhealthmetrics.DiskHealthMetricsConfig) {
+	config.UseNats = config.NatsURL != ""
+
+	event := log.Info()
+	event.Bool("use_nats", config.UseNats)
+	if config.UseNats {
+		event.Str("nats_url", config.NatsURL)
+		event.Str("nats_subject", config.NatsSubject)
+		event.Str("change_events_subject", config.ChangeEventsSubject)
+	}
+
+	event.Bool("prometheus_enabled", config.Prometheus)
+	if config.Prometheus {
+		event.Int("prometheus_port", config.PrometheusPort)
+	}
+	logRemoteWriteConfig(event, config.RemoteWrite)
+
+	event.Bool("all_attributes", config.AllAttributes).
+		Str("disks", fmt.Sprintf("%v", config.Disks)).
+		Str("node_name", config.NodeName).
+		Str("instance_id", config.InstanceID).
+		Strs("export_attributes", config.ExportAttributes).
+		Strs("exclude_attributes", config.ExcludeAttributes).
+		Bool("kubernetes", config.Kubernetes).
+		Str("zone", config.Zone).
+		Str("rack", config.Rack).
+		Int("probe_port", config.ProbePort).
+		Int("interval_seconds", config.Interval).
+		Int("scan_concurrency", config.ScanConcurrency).
+		Int("device_timeout_seconds", config.DeviceTimeout).
+		Bool("hotplug", config.Hotplug).
+		Str("ceph_osd_base_path", config.CephOSDBasePath).
+		Str("ceph_cluster", config.CephCluster).
+		Float64("risk_warning_threshold", config.RiskWarningThreshold).
+		Float64("risk_critical_threshold", config.RiskCriticalThreshold).
+		Int("endurance_warranty_years", config.EnduranceWarrantyYears).
+		Interface("temperature_thresholds", config.TemperatureThresholds).
+		Int64("temperature_hysteresis", config.TemperatureHysteresis)
+	if config.DeviceDBPath != "" {
+		event.Str("device_db", config.DeviceDBPath)
+	}
+	if config.HistoryPath != "" {
+		event.Str("history_path", config.HistoryPath)
+	} else if config.HistoryKVBucket != "" {
+		event.Str("history_kv_bucket", config.HistoryKVBucket)
+	}
+	if config.SnapshotPath != "" {
+		event.Str("snapshot_path", config.SnapshotPath)
+	}
+	if config.SnapshotSubject != "" {
+		event.Str("snapshot_subject", config.SnapshotSubject)
+	}
+	if config.FirmwareReportPath != "" {
+		event.Str("firmware_report_path", config.FirmwareReportPath)
+	}
+	if config.FirmwareReportSubject != "" {
+		event.Str("firmware_report_subject", config.FirmwareReportSubject)
+	}
+	if config.ReplacementSubject != "" {
+		event.Str("replacement_subject", config.ReplacementSubject)
+	}
+	if config.LocateSubject != "" {
+		event.Str("locate_subject", config.LocateSubject)
+	}
+	event.Bool("nvme_telemetry", config.NVMeTelemetry)
+	event.Bool("kernel_io", config.KernelIO)
+	event.Bool("enclosure_slots", config.EnclosureSlots)
+	if config.Chassis != "" {
+		event.Str("chassis", config.Chassis)
+	}
+	event.Bool("discover_raid", config.DiscoverRAID)
+	if config.PassthroughDevicesPath != "" {
+		event.Str("passthrough_devices", config.PassthroughDevicesPath)
+	}
+	if config.SmartdStateDir != "" {
+		event.Str("smartd_state_dir", config.SmartdStateDir).
+			Str("smartd_log", config.SmartdLogPath)
+	}
+	if config.MockSmartctlDir != "" {
+		event.Str("mock_smartctl_dir", config.MockSmartctlDir)
+	}
+	event.Bool("self_test", config.SelfTest)
+	if config.SelfTest {
+		event.Int("self_test_short_interval_hours", config.SelfTestShortIntervalHours).
+			Int("self_test_long_interval_hours", config.SelfTestLongIntervalHours).
+			Str("self_test_window", config.SelfTestWindow).
+			Int("self_test_stagger_minutes", config.SelfTestStaggerMinutes)
+	}
+	event.Msg("configuration_loaded")
+
+	validateDiskHealthMetricsConfig(config)
+	publishProducerConfig("disk-health-metrics", config.NatsURL, config.NodeName, config.InstanceID, config)
+
+	diskhealthmetrics.StartMonitoring(config)
 }
 
 func mergeDiskHealthMetricsConfigWithEnv(cfg diskhealthmetrics.DiskHealthMetricsConfig) diskhealthmetrics.DiskHealthMetricsConfig {
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
-	cfg.AllAttributes = getEnvBool("ALL_ATTR", cfg.AllAttributes)
-	disksEnv := getEnv("DISKS", "")
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.ChangeEventsSubject = telemetry.GetEnv("CHANGE_EVENTS_SUBJECT", cfg.ChangeEventsSubject)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+	cfg.AllAttributes = telemetry.GetEnvBool("ALL_ATTR", cfg.AllAttributes)
+	disksEnv := telemetry.GetEnv("DISKS", "")
 	if disksEnv != "" {
 		cfg.Disks = strings.Split(disksEnv, ",")
 	}
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
-	cfg.IncludeZeroValues = getEnvBool("INCLUDE_ZERO_VALUES", cfg.IncludeZeroValues)
-	cfg.Interval = getEnvInt("INTERVAL", cfg.Interval)
-	cfg.GrownDefectsThreshold = getEnvInt64("GROWN_DEFECTS_THRESHOLD", cfg.GrownDefectsThreshold)
-	cfg.PendingSectorsThreshold = getEnvInt64("PENDING_SECTORS_THRESHOLD", cfg.PendingSectorsThreshold)
-	cfg.ReallocatedSectorsThreshold = getEnvInt64("REALLOCATED_SECTORS_THRESHOLD", cfg.ReallocatedSectorsThreshold)
-	cfg.LifetimeUsedThreshold = getEnvInt64("LIFETIME_USED_THRESHOLD", cfg.LifetimeUsedThreshold)
-	cfg.CephOSDBasePath = getEnv("CEPH_OSD_BASE_PATH", cfg.CephOSDBasePath)
-	
+	cfg.PassthroughDevicesPath = telemetry.GetEnv("PASSTHROUGH_DEVICES", cfg.PassthroughDevicesPath)
+	cfg.DiscoverRAID = telemetry.GetEnvBool("DISCOVER_RAID", cfg.DiscoverRAID)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Kubernetes = telemetry.GetEnvBool("KUBERNETES_MODE", cfg.Kubernetes)
+	cfg.Zone = telemetry.GetEnv("NODE_ZONE", cfg.Zone)
+	cfg.Rack = telemetry.GetEnv("NODE_RACK", cfg.Rack)
+	cfg.RackLabel = telemetry.GetEnv("RACK_LABEL", cfg.RackLabel)
+	cfg.ProbePort = telemetry.GetEnvInt("PROBE_PORT", cfg.ProbePort)
+	cfg.IncludeZeroValues = telemetry.GetEnvBool("INCLUDE_ZERO_VALUES", cfg.IncludeZeroValues)
+	if exportAttributesEnv := telemetry.GetEnv("EXPORT_ATTRIBUTES", ""); exportAttributesEnv != "" {
+		cfg.ExportAttributes = strings.Split(exportAttributesEnv, ",")
+	}
+	if excludeAttributesEnv := telemetry.GetEnv("EXCLUDE_ATTRIBUTES", ""); excludeAttributesEnv != "" {
+		cfg.ExcludeAttributes = strings.Split(excludeAttributesEnv, ",")
+	}
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.ScanConcurrency = telemetry.GetEnvInt("SCAN_CONCURRENCY", cfg.ScanConcurrency)
+	cfg.DeviceTimeout = telemetry.GetEnvInt("DEVICE_TIMEOUT", cfg.DeviceTimeout)
+	cfg.Hotplug = telemetry.GetEnvBool("HOTPLUG", cfg.Hotplug)
+	cfg.GrownDefectsThreshold = telemetry.GetEnvInt64("GROWN_DEFECTS_THRESHOLD", cfg.GrownDefectsThreshold)
+	cfg.PendingSectorsThreshold = telemetry.GetEnvInt64("PENDING_SECTORS_THRESHOLD", cfg.PendingSectorsThreshold)
+	cfg.ReallocatedSectorsThreshold = telemetry.GetEnvInt64("REALLOCATED_SECTORS_THRESHOLD", cfg.ReallocatedSectorsThreshold)
+	cfg.LifetimeUsedThreshold = telemetry.GetEnvInt64("LIFETIME_USED_THRESHOLD", cfg.LifetimeUsedThreshold)
+	cfg.RiskWarningThreshold = telemetry.GetEnvFloat("RISK_WARNING_THRESHOLD", cfg.RiskWarningThreshold)
+	cfg.RiskCriticalThreshold = telemetry.GetEnvFloat("RISK_CRITICAL_THRESHOLD", cfg.RiskCriticalThreshold)
+	cfg.EnduranceWarrantyYears = telemetry.GetEnvInt("ENDURANCE_WARRANTY_YEARS", cfg.EnduranceWarrantyYears)
+	cfg.TemperatureHysteresis = telemetry.GetEnvInt64("TEMPERATURE_HYSTERESIS", cfg.TemperatureHysteresis)
+	cfg.CephOSDBasePath = telemetry.GetEnv("CEPH_OSD_BASE_PATH", cfg.CephOSDBasePath)
+	cfg.CephCluster = telemetry.GetEnv("CEPH_CLUSTER", cfg.CephCluster)
+	cfg.HistoryPath = telemetry.GetEnv("HISTORY_PATH", cfg.HistoryPath)
+	cfg.HistoryKVBucket = telemetry.GetEnv("HISTORY_KV_BUCKET", cfg.HistoryKVBucket)
+	cfg.SnapshotPath = telemetry.GetEnv("SNAPSHOT_PATH", cfg.SnapshotPath)
+	cfg.SnapshotSubject = telemetry.GetEnv("SNAPSHOT_SUBJECT", cfg.SnapshotSubject)
+	cfg.FirmwareReportPath = telemetry.GetEnv("FIRMWARE_REPORT_PATH", cfg.FirmwareReportPath)
+	cfg.FirmwareReportSubject = telemetry.GetEnv("FIRMWARE_REPORT_SUBJECT", cfg.FirmwareReportSubject)
+	cfg.ReplacementSubject = telemetry.GetEnv("REPLACEMENT_SUBJECT", cfg.ReplacementSubject)
+	cfg.LocateSubject = telemetry.GetEnv("LOCATE_SUBJECT", cfg.LocateSubject)
+	cfg.DeviceDBPath = telemetry.GetEnv("DEVICE_DB", cfg.DeviceDBPath)
+	cfg.NVMeTelemetry = telemetry.GetEnvBool("NVME_TELEMETRY", cfg.NVMeTelemetry)
+	cfg.KernelIO = telemetry.GetEnvBool("KERNEL_IO", cfg.KernelIO)
+	cfg.EnclosureSlots = telemetry.GetEnvBool("ENCLOSURE_SLOTS", cfg.EnclosureSlots)
+	cfg.Chassis = telemetry.GetEnv("CHASSIS", cfg.Chassis)
+	cfg.SelfTest = telemetry.GetEnvBool("SELF_TEST", cfg.SelfTest)
+	cfg.SelfTestShortIntervalHours = telemetry.GetEnvInt("SELF_TEST_SHORT_INTERVAL", cfg.SelfTestShortIntervalHours)
+	cfg.SelfTestLongIntervalHours = telemetry.GetEnvInt("SELF_TEST_LONG_INTERVAL", cfg.SelfTestLongIntervalHours)
+	cfg.SelfTestWindow = telemetry.GetEnv("SELF_TEST_WINDOW", cfg.SelfTestWindow)
+	cfg.SelfTestStaggerMinutes = telemetry.GetEnvInt("SELF_TEST_STAGGER", cfg.SelfTestStaggerMinutes)
+	cfg.SmartdStateDir = telemetry.GetEnv("SMARTD_STATE_DIR", cfg.SmartdStateDir)
+	cfg.SmartdLogPath = telemetry.GetEnv("SMARTD_LOG", cfg.SmartdLogPath)
+
 	// Test mode environment variables
-	cfg.TestMode = getEnvBool("TEST_MODE", cfg.TestMode)
-	cfg.TestDataPath = getEnv("TEST_DATA_PATH", cfg.TestDataPath)
-	cfg.TestScenario = getEnv("TEST_SCENARIO", cfg.TestScenario)
-	
-	testDevicesEnv := getEnv("TEST_DEVICES", "")
+	cfg.MockSmartctlDir = telemetry.GetEnv("MOCK_SMARTCTL_DIR", cfg.MockSmartctlDir)
+	cfg.TestMode = telemetry.GetEnvBool("TEST_MODE", cfg.TestMode)
+	cfg.TestDataPath = telemetry.GetEnv("TEST_DATA_PATH", cfg.TestDataPath)
+	cfg.TestScenario = telemetry.GetEnv("TEST_SCENARIO", cfg.TestScenario)
+
+	testDevicesEnv := telemetry.GetEnv("TEST_DEVICES", "")
 	if testDevicesEnv != "" {
 		cfg.TestDevices = strings.Split(testDevicesEnv, ",")
 	}
@@ -133,19 +357,61 @@ func mergeDiskHealthMetricsConfigWithEnv(cfg diskhealthmetrics.DiskHealthMetrics
 func init() {
 	diskHealthMetricsCmd.Flags().StringVar(&dhmNatsURL, "nats-url", "", "NATS server URL")
 	diskHealthMetricsCmd.Flags().StringVar(&dhmNatsSubject, "nats-subject", "osd.disk.health", "NATS subject to publish metrics")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmChangeEventsSubject, "change-events-subject", "", "NATS subject for SMART attribute and status change events (empty disables)")
 	diskHealthMetricsCmd.Flags().BoolVar(&dhmPromEnabled, "prometheus", false, "Enable Prometheus metrics")
 	diskHealthMetricsCmd.Flags().IntVar(&dhmPromPort, "prometheus-port", 8080, "Prometheus metrics port")
+	dhmRemoteWrite.register(diskHealthMetricsCmd)
 	// diskHealthMetricsCmd.Flags().BoolVar(&dhmAllAttributes, "all-attr", false, "Monitor all SMART attributes")
 	diskHealthMetricsCmd.Flags().StringVar(&dhmDisksFlag, "disks", "/dev/sda,/dev/sdb", "Comma-separated list of disks to monitor, e.g., \"/dev/sda,/dev/sdb\". Use \"*\" to monitor all available disks.")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmPassthroughDevicesPath, "passthrough-devices", "", "Path to a JSON file listing devices that need a smartctl device type, e.g. drives behind RAID controllers")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmDiscoverRAID, "discover-raid", false, "Discover drives behind MegaRAID/PERC controllers using storcli or perccli")
 	// diskHealthMetricsCmd.Flags().BoolVar(&dhmIncludeZeroValues, "include-zero-values", false, "Include attributes with zero values")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmExportAttributes, "export-attributes", "", "Comma-separated SMART attribute IDs or keys exported as Prometheus metrics, e.g. \"5,197,temperature_celsius\" (default: all)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmExcludeAttributes, "exclude-attributes", "", "Comma-separated SMART attribute IDs or keys never exported as Prometheus metrics (NATS events keep all attributes)")
 	diskHealthMetricsCmd.Flags().IntVar(&dhmInterval, "interval", 10, "Interval in seconds between metric collections")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmScanSchedules, "scan-schedules", "", "Scan intervals per media type or device glob overriding --interval, e.g. \"nvme=1m,hdd=30m,/dev/sdz=6h\"")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmScanConcurrency, "scan-concurrency", 8, "Number of disks queried with smartctl in parallel")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmDeviceTimeout, "device-timeout", 60, "Seconds after which a disk that does not answer is skipped for the current scan (0 disables)")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmHotplug, "hotplug", false, "Watch kernel uevents and scan added or removed disks immediately")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmKubernetes, "kubernetes", false, "Detect node name, instance, zone and rack from the downward API and node labels")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmZone, "zone", "", "Zone label attached to all metrics and events (detected from node labels in Kubernetes mode)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmRack, "rack", "", "Rack label attached to all metrics and events (detected from node labels in Kubernetes mode)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmRackLabel, "rack-label", diskhealthmetrics.DefaultRackLabel, "Node label the rack is read from in Kubernetes mode")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmProbePort, "probe-port", 0, "Port serving /healthz and /readyz (0 serves them on the Prometheus port only)")
 	diskHealthMetricsCmd.Flags().Int64Var(&dhmGrownDefectsThreshold, "grown-defects-threshold", 10, "Threshold for grown defects to trigger a warning")
 	diskHealthMetricsCmd.Flags().Int64Var(&dhmPendingSectorsThreshold, "pending-sectors-threshold", 3, "Threshold for pending sectors to trigger a warning")
 	diskHealthMetricsCmd.Flags().Int64Var(&dhmReallocatedSectorsThreshold, "reallocated-sectors-threshold", 10, "Threshold for reallocated sectors to trigger a warning")
 	diskHealthMetricsCmd.Flags().Int64Var(&dhmLifetimeUsedThreshold, "lifetime-used-threshold", 80, "Threshold for SSD lifetime used percentage to trigger a critical alert")
-	diskHealthMetricsCmd.Flags().StringVar(&dhmCephOSDBasePath, "ceph-osd-base-path", "/var/lib/rook/rook-ceph/", "Base path for mapping devices to Ceph OSD numbers")
-	
+	diskHealthMetricsCmd.Flags().Float64Var(&dhmRiskWarningThreshold, "risk-warning-threshold", 40, "Failure-risk score (0-100) at which a warning event is emitted")
+	diskHealthMetricsCmd.Flags().Float64Var(&dhmRiskCriticalThreshold, "risk-critical-threshold", 70, "Failure-risk score (0-100) at which a critical event is emitted")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmEnduranceWarrantyYears, "endurance-warranty-years", 5, "Warranty period in years the DWPD rating of SSDs applies to, used for the remaining-life projection")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmTemperatureThresholds, "temperature-thresholds", diskhealthmetrics.DefaultTemperatureThresholds, "Warning and critical temperatures in Celsius per media type, e.g. \"hdd=50:60,ssd=60:70,nvme=70:80\"")
+	diskHealthMetricsCmd.Flags().Int64Var(&dhmTemperatureHysteresis, "temperature-hysteresis", 3, "Degrees Celsius a disk must cool below a threshold before its temperature level is lowered")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmCephOSDBasePath, "ceph-osd-base-path", "/var/lib/rook/rook-ceph/", "Base path for mapping devices to Ceph OSD numbers (Rook OSD directories or /var/lib/ceph/osd)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmCephCluster, "ceph-cluster", "", "Value for the ceph_cluster label (default: cluster fsid discovered from the OSD)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmDeviceDBPath, "device-db", "", "Path to a JSON device database that extends or overrides the built-in model normalization (reloaded on change)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmHistoryPath, "history-path", "", "Local JSON file to persist SMART history for rate-of-change metrics (default: in memory)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmHistoryKVBucket, "history-kv-bucket", "", "NATS KV bucket to persist SMART history for rate-of-change metrics (requires --nats-url)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmSnapshotPath, "snapshot-path", "", "File to write a fleet snapshot of all devices to after every scan, CSV if it ends in .csv, JSON otherwise")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmSnapshotSubject, "snapshot-subject", "", "NATS subject to publish a fleet snapshot of all devices to after every scan (empty disables)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmFirmwareReportPath, "firmware-report-path", "", "JSON file to write the devices running firmware not approved by the device database to after every scan")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmFirmwareReportSubject, "firmware-report-subject", "", "NATS subject to publish the devices running firmware not approved by the device database to after every scan (empty disables)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmReplacementSubject, "replacement-subject", "", "NATS subject to answer requests for devices recommended for replacement on (empty disables)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmLocateSubject, "locate-subject", "", "NATS subject to answer requests to blink the slot LED of a drive on, e.g. "+diskhealthmetrics.DefaultLocateSubject+" for prysm disk locate (empty disables)")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmNVMeTelemetry, "nvme-telemetry", false, "Collect NVMe endurance group, self-test and vendor log pages via nvme-cli")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmKernelIO, "kernel-io", false, "Join kernel I/O latencies (/sys/block) and I/O errors from the kernel log (/dev/kmsg) with SMART data")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmEnclosureSlots, "enclosure-slots", false, "Map drives to their chassis, enclosure and slot via the kernel ses driver (/sys/class/enclosure) or sg_ses")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmChassis, "chassis", "", "Chassis label of the drive locations (default: the DMI chassis serial number)")
+	diskHealthMetricsCmd.Flags().BoolVar(&dhmSelfTest, "self-test", false, "Export SMART self-test status and run scheduled self-tests")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmSelfTestShortInterval, "self-test-short-interval", 24, "Hours between short self-tests per device (0 disables)")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmSelfTestLongInterval, "self-test-long-interval", 168, "Hours between long self-tests per device (0 disables)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmSelfTestWindow, "self-test-window", "", "Daily local time window for starting self-tests, e.g. \"01:00-05:00\" (default: any time)")
+	diskHealthMetricsCmd.Flags().IntVar(&dhmSelfTestStagger, "self-test-stagger", 15, "Minimum minutes between self-test starts on this node")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmSmartdStateDir, "smartd-state-dir", "", "Read ATA SMART attributes from smartd state files in this directory instead of polling the devices (smartd --savestates)")
+	diskHealthMetricsCmd.Flags().StringVar(&dhmSmartdLogPath, "smartd-log", "", "Log file smartd reports failing drives to, e.g. /var/log/syslog (with --smartd-state-dir)")
+
 	// Test mode flags
+	diskHealthMetricsCmd.Flags().StringVar(&dhmMockSmartctlDir, "mock-smartctl-dir", "", "Directory with canned smartctl JSON outputs to read instead of running smartctl (see README)")
 	diskHealthMetricsCmd.Flags().BoolVar(&dhmTestMode, "test-mode", false, "Enable test mode with simulated data (no smartctl required)")
 	diskHealthMetricsCmd.Flags().StringVar(&dhmTestDataPath, "test-data-path", "", "Path to test data directory (default: pkg/producers/diskhealthmetrics/testdata)")
 	diskHealthMetricsCmd.Flags().StringVar(&dhmTestScenario, "test-scenario", "mixed", "Test scenario: healthy, failing, mixed")
@@ -161,6 +427,27 @@ func validateDiskHealthMetricsConfig(config diskhealthmetrics.DiskHealthMetricsC
 		missingParams = true
 	}
 
+	if config.ScanConcurrency < 1 {
+		fmt.Println("Warning: --scan-concurrency must be at least 1")
+		missingParams = true
+	}
+
+	if config.RiskWarningThreshold > config.RiskCriticalThreshold {
+		fmt.Println("Warning: --risk-warning-threshold must not exceed --risk-critical-threshold")
+		missingParams = true
+	}
+
+	if config.SelfTest {
+		if err := diskhealthmetrics.ValidateSelfTestWindow(config.SelfTestWindow); err != nil {
+			fmt.Printf("Warning: --self-test-window: %v\n", err)
+			missingParams = true
+		}
+	}
+
+	if !validateRemoteWriteConfig(config.RemoteWrite, config.Prometheus) {
+		missingParams = true
+	}
+
 	if missingParams {
 		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
 		os.Exit(1)
@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/kernelmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -62,18 +63,19 @@ var kernelMetricsCmd = &cobra.Command{
 		event.Msg("configuration_loaded")
 
 		validateKernelMetricsConfig(config)
+		publishProducerConfig("kernel-metrics", config.NatsURL, config.NodeName, config.InstanceID, config)
 
 		kernelmetrics.StartMonitoring(config)
 	},
 }
 
 func mergeKernelMetricsConfigWithEnv(cfg kernelmetrics.KernelMetricsConfig) kernelmetrics.KernelMetricsConfig {
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
-	cfg.Interval = getEnvInt("INTERVAL", cfg.Interval)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
 
 	return cfg
 }
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/nodeinventory"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	niHostRoot    string
+	niSocketGlob  string
+	niInterval    int
+	niTimeout     int
+	niNatsURL     string
+	niNatsSubject string
+	niPromEnabled bool
+	niPromPort    int
+	niNodeName    string
+	niInstanceID  string
+)
+
+var nodeInventoryCmd = &cobra.Command{
+	Use:   "node-inventory",
+	Short: "CPU, memory, NIC, kernel and Ceph version inventory of the node",
+	Run: func(cmd *cobra.Command, args []string) {
+		runNodeInventory(nodeInventoryConfig())
+	},
+}
+
+// nodeInventoryConfig returns the configuration of the flags and
+// environment variables
+func nodeInventoryConfig() nodeinventory.NodeInventoryConfig {
+	config := nodeinventory.NodeInventoryConfig{
+		HostRoot:       niHostRoot,
+		SocketGlob:     niSocketGlob,
+		Interval:       niInterval,
+		Timeout:        niTimeout,
+		NatsURL:        niNatsURL,
+		NatsSubject:    niNatsSubject,
+		Prometheus:     niPromEnabled,
+		PrometheusPort: niPromPort,
+		NodeName:       niNodeName,
+		InstanceID:     niInstanceID,
+	}
+
+	return mergeNodeInventoryConfigWithEnv(config)
+}
+
+func runNodeInventory(config nodeinventory.NodeInventoryConfig) {
+	config.UseNats = config.NatsURL != ""
+
+	event := log.Info()
+	event.Str("host_root", config.HostRoot)
+	event.Str("socket_glob", config.SocketGlob)
+
+	event.Bool("use_nats", config.UseNats)
+	if config.UseNats {
+		event.Str("nats_url", config.NatsURL)
+		event.Str("nats_subject", config.NatsSubject)
+	}
+
+	event.Bool("prometheus_enabled", config.Prometheus)
+	if config.Prometheus {
+		event.Int("prometheus_port", config.PrometheusPort)
+	}
+
+	event.Str("node_name", config.NodeName)
+	event.Str("instance_id", config.InstanceID)
+	event.Int("interval_seconds", config.Interval)
+
+	event.Msg("configuration_loaded")
+
+	validateNodeInventoryConfig(config)
+	publishProducerConfig("node-inventory", config.NatsURL, config.NodeName, config.InstanceID, config)
+
+	nodeinventory.StartMonitoring(config)
+}
+
+func mergeNodeInventoryConfigWithEnv(cfg nodeinventory.NodeInventoryConfig) nodeinventory.NodeInventoryConfig {
+	cfg.HostRoot = telemetry.GetEnv("HOST_ROOT", cfg.HostRoot)
+	cfg.SocketGlob = telemetry.GetEnv("SOCKET_GLOB", cfg.SocketGlob)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.Timeout = telemetry.GetEnvInt("TIMEOUT", cfg.Timeout)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+
+	return cfg
+}
+
+func init() {
+	nodeInventoryCmd.Flags().StringVar(&niHostRoot, "host-root", "/", "Root of the host filesystem, e.g. /host with the host mounted there")
+	nodeInventoryCmd.Flags().StringVar(&niSocketGlob, "socket-glob", "/var/run/ceph/*.asok", "Admin sockets of the Ceph daemons on the node, empty to skip the Ceph versions")
+	nodeInventoryCmd.Flags().IntVar(&niInterval, "interval", 300, "Interval in seconds between collections")
+	nodeInventoryCmd.Flags().IntVar(&niTimeout, "timeout", 5, "Timeout in seconds of an admin socket command")
+	nodeInventoryCmd.Flags().StringVar(&niNatsURL, "nats-url", "", "NATS server URL")
+	nodeInventoryCmd.Flags().StringVar(&niNatsSubject, "nats-subject", "node.inventory", "NATS subject to publish the node inventory")
+	nodeInventoryCmd.Flags().BoolVar(&niPromEnabled, "prometheus", false, "Enable Prometheus metrics")
+	nodeInventoryCmd.Flags().IntVar(&niPromPort, "prometheus-port", 8080, "Prometheus metrics port")
+	nodeInventoryCmd.Flags().StringVar(&niNodeName, "node-name", "", "Name of the node, the same as of disk-health-metrics")
+	nodeInventoryCmd.Flags().StringVar(&niInstanceID, "instance-id", "", "Instance ID")
+}
+
+func validateNodeInventoryConfig(config nodeinventory.NodeInventoryConfig) {
+	missingParams := false
+
+	if config.HostRoot == "" {
+		fmt.Println("Warning: --host-root (or HOST_ROOT) must be set")
+		missingParams = true
+	}
+	if config.Interval <= 0 || config.Timeout <= 0 {
+		fmt.Println("Warning: --interval and --timeout must be positive")
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
@@ -7,8 +7,11 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
 	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -18,9 +21,15 @@ var (
 	opsLogFilePath             string
 	opsTruncateLogOnStart      bool
 	opsSocketPath              string
+	opsJournaldUnit            string
+	opsJournaldCursorFile      string
 	opsNatsURL                 string
 	opsNatsSubject             string
 	opsNatsMetricsSubject      string
+	opsNatsTenantSubjects      bool
+	opsNatsRoutes              string
+	opsNatsSecurityEvents      bool
+	opsNatsSecuritySubject     string
 	opsLogToStdout             bool
 	opsLogPrettyPrint          bool
 	opsLogRetentionDays        int
@@ -29,6 +38,16 @@ var (
 	opsPromPort                int
 	opsIgnoreAnonymousRequests bool
 	opsPromIntervalSeconds     int
+	opsIPInternalCIDRs         string
+	opsIPCrossRegionCIDRs      string
+	opsReplicationUsers        string
+	opsReplicationCIDRs        string
+	opsCostPrices              string
+	opsBucketTagsKV            string
+	opsBucketTags              string
+	opsInstanceID              string
+	opsNodeName                string
+	opsRemoteWrite             remoteWriteFlags
 
 	// Audit flags
 	opsAuditEnabled           bool
@@ -46,9 +65,41 @@ var (
 	opsAuditAllowDomains      string
 	opsAuditDenyDomains       string
 
+	// Loki sink flags
+	opsLokiURL       string
+	opsLokiTenant    string
+	opsLokiLabels    string
+	opsLokiBatchSize int
+	opsLokiBatchWait int
+	opsLokiQueueSize int
+
+	// Backpressure flags
+	opsBackpressureQueueSize             int
+	opsBackpressureHealthCheckUserAgents string
+	opsBackpressureHealthCheckCIDRs      string
+
+	// Slow request capture flags
+	opsSlowRequestThreshold        time.Duration
+	opsSlowRequestBucketThresholds string
+	opsSlowRequestsBufferSize      int
+	opsNatsSlowRequests            bool
+	opsNatsSlowRequestsSubject     string
+
+	// Keystone project resolution flags
+	opsKeystoneURL                         string
+	opsKeystoneApplicationCredentialID     string
+	opsKeystoneApplicationCredentialSecret string
+	opsKeystoneCacheTTL                    time.Duration
+
 	// Shortcut config
-	opsTrackEverything bool
-	opsTrackBucketSLO  bool
+	opsTrackEverything        bool
+	opsTrackBucketSLO         bool
+	opsTrackSecurity          bool
+	opsTrackBucketConcurrency bool
+	opsTrackAuthMethods       bool
+	opsTrackHealthChecks      bool
+	opsHealthCheckMinPerMin   int
+	opsHealthCheckTop         int
 
 	// Request metrics flags
 	opsTrackRequestsDetailed  bool
@@ -118,6 +169,9 @@ var (
 	opsTrackLatencyPerTenant          bool
 	opsTrackLatencyPerMethod          bool
 	opsTrackLatencyPerBucketAndMethod bool
+
+	// API category metrics
+	opsTrackAPICategories bool
 )
 
 var opsLogCmd = &cobra.Command{
@@ -140,165 +194,286 @@ Then restart all RadosGW daemons:
 
 Following this configuration change, the RadosGW will log operations to the file /var/log/ceph/ceph-rgw-ops.json.log.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		config := opslog.OpsLogConfig{
-			LogFilePath:               opsLogFilePath,
-			TruncateLogOnStart:        opsTruncateLogOnStart,
-			SocketPath:                opsSocketPath,
-			NatsURL:                   opsNatsURL,
-			NatsSubject:               opsNatsSubject,
-			NatsMetricsSubject:        opsNatsMetricsSubject,
-			LogToStdout:               opsLogToStdout,
-			LogPrettyPrint:            opsLogPrettyPrint,
-			LogRetentionDays:          opsLogRetentionDays,
-			MaxLogFileSize:            opsMaxLogFileSize,
-			Prometheus:                opsPromEnabled,
-			PrometheusPort:            opsPromPort,
-			IgnoreAnonymousRequests:   opsIgnoreAnonymousRequests,
-			PrometheusIntervalSeconds: opsPromIntervalSeconds,
-			MetricsConfig: opslog.MetricsConfig{
-				// Shortcut config
-				TrackEverything: opsTrackEverything,
-				TrackBucketSLO:  opsTrackBucketSLO,
-
-				// Request metrics
-				TrackRequestsDetailed:  opsTrackRequestsDetailed,
-				TrackRequestsPerUser:   opsTrackRequestsPerUser,
-				TrackRequestsPerBucket: opsTrackRequestsPerBucket,
-				TrackRequestsPerTenant: opsTrackRequestsPerTenant,
-
-				// Method-based requests
-				TrackRequestsByMethodDetailed:  opsTrackRequestsByMethodDetailed,
-				TrackRequestsByMethodPerUser:   opsTrackRequestsByMethodPerUser,
-				TrackRequestsByMethodPerBucket: opsTrackRequestsByMethodPerBucket,
-				TrackRequestsByMethodPerTenant: opsTrackRequestsByMethodPerTenant,
-				TrackRequestsByMethodGlobal:    opsTrackRequestsByMethodGlobal,
-
-				// Operation-based requests
-				TrackRequestsByOperationDetailed:  opsTrackRequestsByOperationDetailed,
-				TrackRequestsByOperationPerUser:   opsTrackRequestsByOperationPerUser,
-				TrackRequestsByOperationPerBucket: opsTrackRequestsByOperationPerBucket,
-				TrackRequestsByOperationPerTenant: opsTrackRequestsByOperationPerTenant,
-				TrackRequestsByOperationGlobal:    opsTrackRequestsByOperationGlobal,
-
-				// Status-based requests
-				TrackRequestsByStatusDetailed:  opsTrackRequestsByStatusDetailed,
-				TrackRequestsByStatusPerUser:   opsTrackRequestsByStatusPerUser,
-				TrackRequestsByStatusPerBucket: opsTrackRequestsByStatusPerBucket,
-				TrackRequestsByStatusPerTenant: opsTrackRequestsByStatusPerTenant,
-
-				// Bytes metrics
-				TrackBytesSentDetailed:  opsTrackBytesSentDetailed,
-				TrackBytesSentPerUser:   opsTrackBytesSentPerUser,
-				TrackBytesSentPerBucket: opsTrackBytesSentPerBucket,
-				TrackBytesSentPerTenant: opsTrackBytesSentPerTenant,
-
-				TrackBytesReceivedDetailed:  opsTrackBytesReceivedDetailed,
-				TrackBytesReceivedPerUser:   opsTrackBytesReceivedPerUser,
-				TrackBytesReceivedPerBucket: opsTrackBytesReceivedPerBucket,
-				TrackBytesReceivedPerTenant: opsTrackBytesReceivedPerTenant,
-
-				// Error metrics
-				TrackErrorsDetailed:   opsTrackErrorsDetailed,
-				TrackErrorsPerUser:    opsTrackErrorsPerUser,
-				TrackErrorsPerBucket:  opsTrackErrorsPerBucket,
-				TrackErrorsPerTenant:  opsTrackErrorsPerTenant,
-				TrackErrorsPerStatus:  opsTrackErrorsPerStatus,
-				TrackTimeoutErrors:    opsTrackTimeoutErrors,
-				TrackErrorsByCategory: opsTrackErrorsByCategory,
-
-				// IP-based metrics
-				TrackRequestsByIPDetailed:           opsTrackRequestsByIPDetailed,
-				TrackRequestsByIPPerTenant:          opsTrackRequestsByIPPerTenant,
-				TrackRequestsByIPBucketMethodTenant: opsTrackRequestsByIPBucketMethodTenant,
-				TrackRequestsByIPGlobalPerTenant:    opsTrackRequestsByIPGlobalPerTenant,
-
-				TrackBytesSentByIPDetailed:        opsTrackBytesSentByIPDetailed,
-				TrackBytesSentByIPPerTenant:       opsTrackBytesSentByIPPerTenant,
-				TrackBytesSentByIPGlobalPerTenant: opsTrackBytesSentByIPGlobalPerTenant,
-
-				TrackBytesReceivedByIPDetailed:        opsTrackBytesReceivedByIPDetailed,
-				TrackBytesReceivedByIPPerTenant:       opsTrackBytesReceivedByIPPerTenant,
-				TrackBytesReceivedByIPGlobalPerTenant: opsTrackBytesReceivedByIPGlobalPerTenant,
-
-				TrackErrorsByIP: opsTrackErrorsByIP,
-
-				// Latency metrics
-				TrackLatencyDetailed:           opsTrackLatencyDetailed,
-				TrackLatencyPerUser:            opsTrackLatencyPerUser,
-				TrackLatencyPerBucket:          opsTrackLatencyPerBucket,
-				TrackLatencyPerTenant:          opsTrackLatencyPerTenant,
-				TrackLatencyPerMethod:          opsTrackLatencyPerMethod,
-				TrackLatencyPerBucketAndMethod: opsTrackLatencyPerBucketAndMethod,
-			},
-			AuditSink: opslog.AuditSinkConfig{
-				Enabled:           opsAuditEnabled,
-				RabbitMQURL:       opsAuditRabbitMQURL,
-				RabbitMQUsername:  opsAuditRabbitMQUsername,
-				RabbitMQPassword:  opsAuditRabbitMQPassword,
-				QueueName:         opsAuditQueueName,
-				InternalQueueSize: opsAuditInternalQueueSize,
-				Debug:             opsAuditDebug,
-				RequireTenant:     opsAuditRequireTenant,
-				Region:            opsAuditRegion,
-				ObserverName:      opsAuditObserverName,
-				IncludeReads:      opsAuditIncludeReads,
-				SkipBuckets:       opsAuditSkipBuckets,
-				AllowDomains:      opsAuditAllowDomains,
-				DenyDomains:       opsAuditDenyDomains,
-			},
+		runOpsLog(opsLogConfig())
+	},
+}
+
+// opsLogConfig returns the configuration of the flags and environment
+// variables
+func opsLogConfig() opslog.OpsLogConfig {
+	config := opslog.OpsLogConfig{
+		LogFilePath:               opsLogFilePath,
+		TruncateLogOnStart:        opsTruncateLogOnStart,
+		SocketPath:                opsSocketPath,
+		JournaldUnit:              opsJournaldUnit,
+		JournaldCursorFile:        opsJournaldCursorFile,
+		NatsURL:                   opsNatsURL,
+		NatsSubject:               opsNatsSubject,
+		NatsMetricsSubject:        opsNatsMetricsSubject,
+		NatsTenantSubjects:        opsNatsTenantSubjects,
+		NatsRoutes:                opsNatsRoutes,
+		NatsSecurityEvents:        opsNatsSecurityEvents,
+		NatsSecuritySubject:       opsNatsSecuritySubject,
+		LogToStdout:               opsLogToStdout,
+		LogPrettyPrint:            opsLogPrettyPrint,
+		LogRetentionDays:          opsLogRetentionDays,
+		MaxLogFileSize:            opsMaxLogFileSize,
+		Prometheus:                opsPromEnabled,
+		PrometheusPort:            opsPromPort,
+		IgnoreAnonymousRequests:   opsIgnoreAnonymousRequests,
+		PrometheusIntervalSeconds: opsPromIntervalSeconds,
+		IPInternalCIDRs:           opsIPInternalCIDRs,
+		IPCrossRegionCIDRs:        opsIPCrossRegionCIDRs,
+		ReplicationUsers:          opsReplicationUsers,
+		ReplicationCIDRs:          opsReplicationCIDRs,
+		CostPrices:                opsCostPrices,
+		BucketTagsKV:              opsBucketTagsKV,
+		BucketTags:                opsBucketTags,
+		Identity:                  identity.Current(), // Resolved from --instance-id and --node-name
+		MetricsConfig: opslog.MetricsConfig{
+			// Shortcut config
+			TrackEverything:        opsTrackEverything,
+			TrackBucketSLO:         opsTrackBucketSLO,
+			TrackSecurity:          opsTrackSecurity,
+			TrackBucketConcurrency: opsTrackBucketConcurrency,
+			TrackAuthMethods:       opsTrackAuthMethods,
+
+			// Health check detection
+			TrackHealthChecks:       opsTrackHealthChecks,
+			HealthCheckMinPerMinute: opsHealthCheckMinPerMin,
+			HealthCheckTop:          opsHealthCheckTop,
+
+			// Request metrics
+			TrackRequestsDetailed:  opsTrackRequestsDetailed,
+			TrackRequestsPerUser:   opsTrackRequestsPerUser,
+			TrackRequestsPerBucket: opsTrackRequestsPerBucket,
+			TrackRequestsPerTenant: opsTrackRequestsPerTenant,
+
+			// Method-based requests
+			TrackRequestsByMethodDetailed:  opsTrackRequestsByMethodDetailed,
+			TrackRequestsByMethodPerUser:   opsTrackRequestsByMethodPerUser,
+			TrackRequestsByMethodPerBucket: opsTrackRequestsByMethodPerBucket,
+			TrackRequestsByMethodPerTenant: opsTrackRequestsByMethodPerTenant,
+			TrackRequestsByMethodGlobal:    opsTrackRequestsByMethodGlobal,
+
+			// Operation-based requests
+			TrackRequestsByOperationDetailed:  opsTrackRequestsByOperationDetailed,
+			TrackRequestsByOperationPerUser:   opsTrackRequestsByOperationPerUser,
+			TrackRequestsByOperationPerBucket: opsTrackRequestsByOperationPerBucket,
+			TrackRequestsByOperationPerTenant: opsTrackRequestsByOperationPerTenant,
+			TrackRequestsByOperationGlobal:    opsTrackRequestsByOperationGlobal,
+
+			// Status-based requests
+			TrackRequestsByStatusDetailed:  opsTrackRequestsByStatusDetailed,
+			TrackRequestsByStatusPerUser:   opsTrackRequestsByStatusPerUser,
+			TrackRequestsByStatusPerBucket: opsTrackRequestsByStatusPerBucket,
+			TrackRequestsByStatusPerTenant: opsTrackRequestsByStatusPerTenant,
+
+			// Bytes metrics
+			TrackBytesSentDetailed:  opsTrackBytesSentDetailed,
+			TrackBytesSentPerUser:   opsTrackBytesSentPerUser,
+			TrackBytesSentPerBucket: opsTrackBytesSentPerBucket,
+			TrackBytesSentPerTenant: opsTrackBytesSentPerTenant,
+
+			TrackBytesReceivedDetailed:  opsTrackBytesReceivedDetailed,
+			TrackBytesReceivedPerUser:   opsTrackBytesReceivedPerUser,
+			TrackBytesReceivedPerBucket: opsTrackBytesReceivedPerBucket,
+			TrackBytesReceivedPerTenant: opsTrackBytesReceivedPerTenant,
+
+			// Error metrics
+			TrackErrorsDetailed:   opsTrackErrorsDetailed,
+			TrackErrorsPerUser:    opsTrackErrorsPerUser,
+			TrackErrorsPerBucket:  opsTrackErrorsPerBucket,
+			TrackErrorsPerTenant:  opsTrackErrorsPerTenant,
+			TrackErrorsPerStatus:  opsTrackErrorsPerStatus,
+			TrackTimeoutErrors:    opsTrackTimeoutErrors,
+			TrackErrorsByCategory: opsTrackErrorsByCategory,
+
+			// IP-based metrics
+			TrackRequestsByIPDetailed:           opsTrackRequestsByIPDetailed,
+			TrackRequestsByIPPerTenant:          opsTrackRequestsByIPPerTenant,
+			TrackRequestsByIPBucketMethodTenant: opsTrackRequestsByIPBucketMethodTenant,
+			TrackRequestsByIPGlobalPerTenant:    opsTrackRequestsByIPGlobalPerTenant,
+
+			TrackBytesSentByIPDetailed:        opsTrackBytesSentByIPDetailed,
+			TrackBytesSentByIPPerTenant:       opsTrackBytesSentByIPPerTenant,
+			TrackBytesSentByIPGlobalPerTenant: opsTrackBytesSentByIPGlobalPerTenant,
+
+			TrackBytesReceivedByIPDetailed:        opsTrackBytesReceivedByIPDetailed,
+			TrackBytesReceivedByIPPerTenant:       opsTrackBytesReceivedByIPPerTenant,
+			TrackBytesReceivedByIPGlobalPerTenant: opsTrackBytesReceivedByIPGlobalPerTenant,
+
+			TrackErrorsByIP: opsTrackErrorsByIP,
+
+			// Latency metrics
+			TrackLatencyDetailed:           opsTrackLatencyDetailed,
+			TrackLatencyPerUser:            opsTrackLatencyPerUser,
+			TrackLatencyPerBucket:          opsTrackLatencyPerBucket,
+			TrackLatencyPerTenant:          opsTrackLatencyPerTenant,
+			TrackLatencyPerMethod:          opsTrackLatencyPerMethod,
+			TrackLatencyPerBucketAndMethod: opsTrackLatencyPerBucketAndMethod,
+			TrackAPICategories:             opsTrackAPICategories,
+		},
+		AuditSink: opslog.AuditSinkConfig{
+			Enabled:           opsAuditEnabled,
+			RabbitMQURL:       opsAuditRabbitMQURL,
+			RabbitMQUsername:  opsAuditRabbitMQUsername,
+			RabbitMQPassword:  opsAuditRabbitMQPassword,
+			QueueName:         opsAuditQueueName,
+			InternalQueueSize: opsAuditInternalQueueSize,
+			Debug:             opsAuditDebug,
+			RequireTenant:     opsAuditRequireTenant,
+			Region:            opsAuditRegion,
+			ObserverName:      opsAuditObserverName,
+			IncludeReads:      opsAuditIncludeReads,
+			SkipBuckets:       opsAuditSkipBuckets,
+			AllowDomains:      opsAuditAllowDomains,
+			DenyDomains:       opsAuditDenyDomains,
+		},
+		LokiSink: opslog.LokiSinkConfig{
+			URL:       opsLokiURL,
+			Tenant:    opsLokiTenant,
+			Labels:    opsLokiLabels,
+			BatchSize: opsLokiBatchSize,
+			BatchWait: time.Duration(opsLokiBatchWait) * time.Second,
+			QueueSize: opsLokiQueueSize,
+		},
+		Backpressure: opslog.BackpressureConfig{
+			QueueSize:             opsBackpressureQueueSize,
+			HealthCheckUserAgents: opsBackpressureHealthCheckUserAgents,
+			HealthCheckCIDRs:      opsBackpressureHealthCheckCIDRs,
+		},
+		SlowRequests: opslog.SlowRequestsConfig{
+			Threshold:        opsSlowRequestThreshold,
+			BucketThresholds: opsSlowRequestBucketThresholds,
+			BufferSize:       opsSlowRequestsBufferSize,
+		},
+		NatsSlowRequests:        opsNatsSlowRequests,
+		NatsSlowRequestsSubject: opsNatsSlowRequestsSubject,
+		Keystone: opslog.KeystoneConfig{
+			URL:                         opsKeystoneURL,
+			ApplicationCredentialID:     opsKeystoneApplicationCredentialID,
+			ApplicationCredentialSecret: opsKeystoneApplicationCredentialSecret,
+			CacheTTL:                    opsKeystoneCacheTTL,
+		},
+	}
+
+	config = mergeOpsLogConfigWithEnv(config)
+	config.RemoteWrite = remoteWriteConfig(opsRemoteWrite)
+
+	return config
+}
+
+func runOpsLog(config opslog.OpsLogConfig) {
+	config.UseNats = config.NatsURL != ""
+
+	event := log.Info()
+	event.Bool("use_nats", config.UseNats)
+	if config.UseNats {
+		event.Str("nats_url", config.NatsURL)
+		event.Str("nats_subject", config.NatsSubject)
+		event.Str("nats_metrics_subject", config.NatsMetricsSubject)
+		event.Bool("nats_tenant_subjects", config.NatsTenantSubjects)
+		if config.NatsRoutes != "" {
+			event.Str("nats_routes", config.NatsRoutes)
 		}
+		event.Bool("nats_security_events", config.NatsSecurityEvents)
+		if config.NatsSecurityEvents {
+			event.Str("nats_security_subject", config.NatsSecuritySubject)
+		}
+	}
 
-		config = mergeOpsLogConfigWithEnv(config)
+	if config.LogFilePath != "" {
+		event.Str("log_file_path", config.LogFilePath)
+	}
 
-		config.UseNats = config.NatsURL != ""
+	if config.SocketPath != "" {
+		event.Str("socket_path", config.SocketPath)
+	}
 
-		event := log.Info()
-		event.Bool("use_nats", config.UseNats)
-		if config.UseNats {
-			event.Str("nats_url", config.NatsURL)
-			event.Str("nats_subject", config.NatsSubject)
-			event.Str("nats_metrics_subject", config.NatsMetricsSubject)
-		}
+	if config.JournaldUnit != "" {
+		event.Str("journald_unit", config.JournaldUnit)
+		event.Str("journald_cursor_file", config.JournaldCursorFile)
+	}
 
-		if config.LogFilePath != "" {
-			event.Str("log_file_path", config.LogFilePath)
-		}
+	if config.LogToStdout {
+		event.Bool("log_to_stdout", config.LogToStdout)
+	}
 
-		if config.SocketPath != "" {
-			event.Str("socket_path", config.SocketPath)
-		}
+	if config.LogPrettyPrint {
+		event.Bool("log_pretty_print", config.LogPrettyPrint)
+	}
 
-		if config.LogToStdout {
-			event.Bool("log_to_stdout", config.LogToStdout)
-		}
+	event.Int("log_retention_days", config.LogRetentionDays)
+	event.Int64("max_log_file_size", config.MaxLogFileSize)
 
-		if config.LogPrettyPrint {
-			event.Bool("log_pretty_print", config.LogPrettyPrint)
-		}
+	event.Bool("prometheus_enabled", config.Prometheus)
+	if config.Prometheus {
+		event.Int("prometheus_port", config.PrometheusPort)
+	}
+	logRemoteWriteConfig(event, config.RemoteWrite)
 
-		event.Int("log_retention_days", config.LogRetentionDays)
-		event.Int64("max_log_file_size", config.MaxLogFileSize)
+	if config.IPInternalCIDRs != "" || config.IPCrossRegionCIDRs != "" {
+		event.Str("ip_internal_cidrs", config.IPInternalCIDRs)
+		event.Str("ip_cross_region_cidrs", config.IPCrossRegionCIDRs)
+	}
+	if config.ReplicationUsers != "" || config.ReplicationCIDRs != "" {
+		event.Str("replication_users", config.ReplicationUsers)
+		event.Str("replication_cidrs", config.ReplicationCIDRs)
+	}
+	if config.CostPrices != "" {
+		event.Str("cost_prices", config.CostPrices)
+	}
 
-		event.Bool("prometheus_enabled", config.Prometheus)
-		if config.Prometheus {
-			event.Int("prometheus_port", config.PrometheusPort)
-		}
+	event.Str("instance_id", config.InstanceID)
+	event.Str("node_name", config.NodeName)
+	if config.PodName != "" {
+		event.Str("pod_name", config.PodName)
+	}
+
+	if config.BucketTagsKV != "" {
+		event.Str("bucket_tags_kv", config.BucketTagsKV)
+		event.Str("bucket_tags", config.BucketTags)
+	}
 
-		// Enhanced debugging for tracking options
-		debugTrackingConfig(event, config.MetricsConfig)
+	if config.LokiSink.URL != "" {
+		event.Str("loki_url", config.LokiSink.URL)
+		event.Str("loki_labels", config.LokiSink.Labels)
+	}
 
-		event.Msg("OpsLog configuration initialized")
+	if config.Keystone.URL != "" {
+		event.Str("keystone_url", config.Keystone.URL)
+		event.Dur("keystone_cache_ttl", config.Keystone.CacheTTL)
+	}
 
-		event.Msg("OpsLog configuration initialized")
+	if config.Backpressure.QueueSize > 0 {
+		event.Int("backpressure_queue_size", config.Backpressure.QueueSize)
+		event.Str("backpressure_health_check_user_agents", config.Backpressure.HealthCheckUserAgents)
+		event.Str("backpressure_health_check_cidrs", config.Backpressure.HealthCheckCIDRs)
+	}
 
-		validateOpsLogConfig(config)
+	if config.SlowRequests.Threshold > 0 || config.SlowRequests.BucketThresholds != "" {
+		event.Dur("slow_request_threshold", config.SlowRequests.Threshold)
+		event.Str("slow_request_bucket_thresholds", config.SlowRequests.BucketThresholds)
+		event.Int("slow_requests_buffer_size", config.SlowRequests.BufferSize)
+		event.Bool("nats_slow_requests", config.NatsSlowRequests)
+	}
 
-		if config.SocketPath != "" {
-			opslog.StartSocketOpsLogger(config)
-		} else {
-			opslog.StartFileOpsLogger(config)
-		}
-	},
+	// Enhanced debugging for tracking options
+	debugTrackingConfig(event, config.MetricsConfig)
+
+	event.Msg("OpsLog configuration initialized")
+
+	event.Msg("OpsLog configuration initialized")
+
+	validateOpsLogConfig(config)
+	publishProducerConfig("ops-log", config.NatsURL, "", "", config)
+
+	if config.SocketPath != "" {
+		opslog.StartSocketOpsLogger(config)
+	} else {
+		opslog.StartFileOpsLogger(config)
+	}
 }
 
 // debugTrackingConfig adds comprehensive metrics configuration to the zerolog event
@@ -318,6 +493,26 @@ func debugTrackingConfig(event *zerolog.Event, config opslog.MetricsConfig) {
 		totalEnabled++
 	}
 
+	if config.TrackSecurity {
+		event.Bool("track_security", true)
+		totalEnabled++
+	}
+
+	if config.TrackBucketConcurrency {
+		event.Bool("track_bucket_concurrency", true)
+		totalEnabled++
+	}
+
+	if config.TrackAuthMethods {
+		event.Bool("track_auth_methods", true)
+		totalEnabled++
+	}
+
+	if config.TrackHealthChecks {
+		event.Bool("track_health_checks", true)
+		totalEnabled++
+	}
+
 	// Request tracking
 	requestMetrics := []string{}
 	if config.TrackRequestsDetailed {
@@ -558,6 +753,11 @@ func debugTrackingConfig(event *zerolog.Event, config opslog.MetricsConfig) {
 		event.Strs("latency_tracking", latencyMetrics)
 	}
 
+	if config.TrackAPICategories {
+		event.Bool("api_category_tracking", true)
+		totalEnabled++
+	}
+
 	// Summary information
 	event.Int("total_enabled_metrics", totalEnabled)
 
@@ -572,111 +772,156 @@ func debugTrackingConfig(event *zerolog.Event, config opslog.MetricsConfig) {
 }
 
 func mergeOpsLogConfigWithEnv(cfg opslog.OpsLogConfig) opslog.OpsLogConfig {
-	cfg.LogFilePath = getEnv("LOG_FILE_PATH", cfg.LogFilePath)
-	cfg.TruncateLogOnStart = getEnvBool("TRUNCATE_LOG_ON_START", cfg.TruncateLogOnStart)
-	cfg.SocketPath = getEnv("SOCKET_PATH", cfg.SocketPath)
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.NatsMetricsSubject = getEnv("NATS_METRICS_SUBJECT", cfg.NatsMetricsSubject)
-	cfg.LogToStdout = getEnvBool("LOG_TO_STDOUT", cfg.LogToStdout)
-	cfg.LogPrettyPrint = getEnvBool("LOG_PRETTY_PRINT", cfg.LogPrettyPrint)
-	cfg.LogRetentionDays = getEnvInt("LOG_RETENTION_DAYS", cfg.LogRetentionDays)
-	cfg.MaxLogFileSize = getEnvInt64("MAX_LOG_FILE_SIZE", cfg.MaxLogFileSize)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
-	cfg.PodName = getEnv("POD_NAME", cfg.PodName)
-	cfg.IgnoreAnonymousRequests = getEnvBool("IGNORE_ANONYMOUS_REQUESTS", cfg.IgnoreAnonymousRequests)
-	cfg.PrometheusIntervalSeconds = getEnvInt("PROMETHEUS_INTERVAL", cfg.PrometheusIntervalSeconds)
+	cfg.LogFilePath = telemetry.GetEnv("LOG_FILE_PATH", cfg.LogFilePath)
+	cfg.TruncateLogOnStart = telemetry.GetEnvBool("TRUNCATE_LOG_ON_START", cfg.TruncateLogOnStart)
+	cfg.SocketPath = telemetry.GetEnv("SOCKET_PATH", cfg.SocketPath)
+	cfg.JournaldUnit = telemetry.GetEnv("JOURNALD_UNIT", cfg.JournaldUnit)
+	cfg.JournaldCursorFile = telemetry.GetEnv("JOURNALD_CURSOR_FILE", cfg.JournaldCursorFile)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.NatsMetricsSubject = telemetry.GetEnv("NATS_METRICS_SUBJECT", cfg.NatsMetricsSubject)
+	cfg.NatsTenantSubjects = telemetry.GetEnvBool("NATS_TENANT_SUBJECTS", cfg.NatsTenantSubjects)
+	cfg.NatsRoutes = telemetry.GetEnv("NATS_ROUTES", cfg.NatsRoutes)
+	cfg.NatsSecurityEvents = telemetry.GetEnvBool("NATS_SECURITY_EVENTS", cfg.NatsSecurityEvents)
+	cfg.NatsSecuritySubject = telemetry.GetEnv("NATS_SECURITY_SUBJECT", cfg.NatsSecuritySubject)
+	cfg.LogToStdout = telemetry.GetEnvBool("LOG_TO_STDOUT", cfg.LogToStdout)
+	cfg.LogPrettyPrint = telemetry.GetEnvBool("LOG_PRETTY_PRINT", cfg.LogPrettyPrint)
+	cfg.LogRetentionDays = telemetry.GetEnvInt("LOG_RETENTION_DAYS", cfg.LogRetentionDays)
+	cfg.MaxLogFileSize = telemetry.GetEnvInt64("MAX_LOG_FILE_SIZE", cfg.MaxLogFileSize)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+	cfg.IgnoreAnonymousRequests = telemetry.GetEnvBool("IGNORE_ANONYMOUS_REQUESTS", cfg.IgnoreAnonymousRequests)
+	cfg.PrometheusIntervalSeconds = telemetry.GetEnvInt("PROMETHEUS_INTERVAL", cfg.PrometheusIntervalSeconds)
+	cfg.IPInternalCIDRs = telemetry.GetEnv("IP_INTERNAL_CIDRS", cfg.IPInternalCIDRs)
+	cfg.IPCrossRegionCIDRs = telemetry.GetEnv("IP_CROSS_REGION_CIDRS", cfg.IPCrossRegionCIDRs)
+	cfg.ReplicationUsers = telemetry.GetEnv("REPLICATION_USERS", cfg.ReplicationUsers)
+	cfg.ReplicationCIDRs = telemetry.GetEnv("REPLICATION_CIDRS", cfg.ReplicationCIDRs)
+	cfg.CostPrices = telemetry.GetEnv("COST_PRICES", cfg.CostPrices)
+	cfg.BucketTagsKV = telemetry.GetEnv("BUCKET_TAGS_KV", cfg.BucketTagsKV)
+	cfg.BucketTags = telemetry.GetEnv("BUCKET_TAGS", cfg.BucketTags)
 
 	// Shortcut config
-	cfg.MetricsConfig.TrackEverything = getEnvBool("TRACK_EVERYTHING", cfg.MetricsConfig.TrackEverything)
-	cfg.MetricsConfig.TrackBucketSLO = getEnvBool("TRACK_BUCKET_SLO", cfg.MetricsConfig.TrackBucketSLO)
+	cfg.MetricsConfig.TrackEverything = telemetry.GetEnvBool("TRACK_EVERYTHING", cfg.MetricsConfig.TrackEverything)
+	cfg.MetricsConfig.TrackBucketSLO = telemetry.GetEnvBool("TRACK_BUCKET_SLO", cfg.MetricsConfig.TrackBucketSLO)
+	cfg.MetricsConfig.TrackSecurity = telemetry.GetEnvBool("TRACK_SECURITY", cfg.MetricsConfig.TrackSecurity)
+	cfg.MetricsConfig.TrackBucketConcurrency = telemetry.GetEnvBool("TRACK_BUCKET_CONCURRENCY", cfg.MetricsConfig.TrackBucketConcurrency)
+	cfg.MetricsConfig.TrackAuthMethods = telemetry.GetEnvBool("TRACK_AUTH_METHODS", cfg.MetricsConfig.TrackAuthMethods)
+	cfg.MetricsConfig.TrackHealthChecks = telemetry.GetEnvBool("TRACK_HEALTH_CHECKS", cfg.MetricsConfig.TrackHealthChecks)
+	cfg.MetricsConfig.HealthCheckMinPerMinute = telemetry.GetEnvInt("HEALTH_CHECK_MIN_PER_MINUTE", cfg.MetricsConfig.HealthCheckMinPerMinute)
+	cfg.MetricsConfig.HealthCheckTop = telemetry.GetEnvInt("HEALTH_CHECK_TOP", cfg.MetricsConfig.HealthCheckTop)
 
 	// Request metrics environment variables
-	cfg.MetricsConfig.TrackRequestsDetailed = getEnvBool("TRACK_REQUESTS_DETAILED", cfg.MetricsConfig.TrackRequestsDetailed)
-	cfg.MetricsConfig.TrackRequestsPerUser = getEnvBool("TRACK_REQUESTS_PER_USER", cfg.MetricsConfig.TrackRequestsPerUser)
-	cfg.MetricsConfig.TrackRequestsPerBucket = getEnvBool("TRACK_REQUESTS_PER_BUCKET", cfg.MetricsConfig.TrackRequestsPerBucket)
-	cfg.MetricsConfig.TrackRequestsPerTenant = getEnvBool("TRACK_REQUESTS_PER_TENANT", cfg.MetricsConfig.TrackRequestsPerTenant)
+	cfg.MetricsConfig.TrackRequestsDetailed = telemetry.GetEnvBool("TRACK_REQUESTS_DETAILED", cfg.MetricsConfig.TrackRequestsDetailed)
+	cfg.MetricsConfig.TrackRequestsPerUser = telemetry.GetEnvBool("TRACK_REQUESTS_PER_USER", cfg.MetricsConfig.TrackRequestsPerUser)
+	cfg.MetricsConfig.TrackRequestsPerBucket = telemetry.GetEnvBool("TRACK_REQUESTS_PER_BUCKET", cfg.MetricsConfig.TrackRequestsPerBucket)
+	cfg.MetricsConfig.TrackRequestsPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_PER_TENANT", cfg.MetricsConfig.TrackRequestsPerTenant)
 
 	// Method-based requests
-	cfg.MetricsConfig.TrackRequestsByMethodDetailed = getEnvBool("TRACK_REQUESTS_BY_METHOD_DETAILED", cfg.MetricsConfig.TrackRequestsByMethodDetailed)
-	cfg.MetricsConfig.TrackRequestsByMethodPerUser = getEnvBool("TRACK_REQUESTS_BY_METHOD_PER_USER", cfg.MetricsConfig.TrackRequestsByMethodPerUser)
-	cfg.MetricsConfig.TrackRequestsByMethodPerBucket = getEnvBool("TRACK_REQUESTS_BY_METHOD_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByMethodPerBucket)
-	cfg.MetricsConfig.TrackRequestsByMethodPerTenant = getEnvBool("TRACK_REQUESTS_BY_METHOD_PER_TENANT", cfg.MetricsConfig.TrackRequestsByMethodPerTenant)
-	cfg.MetricsConfig.TrackRequestsByMethodGlobal = getEnvBool("TRACK_REQUESTS_BY_METHOD_GLOBAL", cfg.MetricsConfig.TrackRequestsByMethodGlobal)
+	cfg.MetricsConfig.TrackRequestsByMethodDetailed = telemetry.GetEnvBool("TRACK_REQUESTS_BY_METHOD_DETAILED", cfg.MetricsConfig.TrackRequestsByMethodDetailed)
+	cfg.MetricsConfig.TrackRequestsByMethodPerUser = telemetry.GetEnvBool("TRACK_REQUESTS_BY_METHOD_PER_USER", cfg.MetricsConfig.TrackRequestsByMethodPerUser)
+	cfg.MetricsConfig.TrackRequestsByMethodPerBucket = telemetry.GetEnvBool("TRACK_REQUESTS_BY_METHOD_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByMethodPerBucket)
+	cfg.MetricsConfig.TrackRequestsByMethodPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_METHOD_PER_TENANT", cfg.MetricsConfig.TrackRequestsByMethodPerTenant)
+	cfg.MetricsConfig.TrackRequestsByMethodGlobal = telemetry.GetEnvBool("TRACK_REQUESTS_BY_METHOD_GLOBAL", cfg.MetricsConfig.TrackRequestsByMethodGlobal)
 
 	// Operation-based requests
-	cfg.MetricsConfig.TrackRequestsByOperationDetailed = getEnvBool("TRACK_REQUESTS_BY_OPERATION_DETAILED", cfg.MetricsConfig.TrackRequestsByOperationDetailed)
-	cfg.MetricsConfig.TrackRequestsByOperationPerUser = getEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_USER", cfg.MetricsConfig.TrackRequestsByOperationPerUser)
-	cfg.MetricsConfig.TrackRequestsByOperationPerBucket = getEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByOperationPerBucket)
-	cfg.MetricsConfig.TrackRequestsByOperationPerTenant = getEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_TENANT", cfg.MetricsConfig.TrackRequestsByOperationPerTenant)
-	cfg.MetricsConfig.TrackRequestsByOperationGlobal = getEnvBool("TRACK_REQUESTS_BY_OPERATION_GLOBAL", cfg.MetricsConfig.TrackRequestsByOperationGlobal)
+	cfg.MetricsConfig.TrackRequestsByOperationDetailed = telemetry.GetEnvBool("TRACK_REQUESTS_BY_OPERATION_DETAILED", cfg.MetricsConfig.TrackRequestsByOperationDetailed)
+	cfg.MetricsConfig.TrackRequestsByOperationPerUser = telemetry.GetEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_USER", cfg.MetricsConfig.TrackRequestsByOperationPerUser)
+	cfg.MetricsConfig.TrackRequestsByOperationPerBucket = telemetry.GetEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByOperationPerBucket)
+	cfg.MetricsConfig.TrackRequestsByOperationPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_OPERATION_PER_TENANT", cfg.MetricsConfig.TrackRequestsByOperationPerTenant)
+	cfg.MetricsConfig.TrackRequestsByOperationGlobal = telemetry.GetEnvBool("TRACK_REQUESTS_BY_OPERATION_GLOBAL", cfg.MetricsConfig.TrackRequestsByOperationGlobal)
 
 	// Status-based requests
-	cfg.MetricsConfig.TrackRequestsByStatusDetailed = getEnvBool("TRACK_REQUESTS_BY_STATUS_DETAILED", cfg.MetricsConfig.TrackRequestsByStatusDetailed)
-	cfg.MetricsConfig.TrackRequestsByStatusPerUser = getEnvBool("TRACK_REQUESTS_BY_STATUS_PER_USER", cfg.MetricsConfig.TrackRequestsByStatusPerUser)
-	cfg.MetricsConfig.TrackRequestsByStatusPerBucket = getEnvBool("TRACK_REQUESTS_BY_STATUS_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByStatusPerBucket)
-	cfg.MetricsConfig.TrackRequestsByStatusPerTenant = getEnvBool("TRACK_REQUESTS_BY_STATUS_PER_TENANT", cfg.MetricsConfig.TrackRequestsByStatusPerTenant)
+	cfg.MetricsConfig.TrackRequestsByStatusDetailed = telemetry.GetEnvBool("TRACK_REQUESTS_BY_STATUS_DETAILED", cfg.MetricsConfig.TrackRequestsByStatusDetailed)
+	cfg.MetricsConfig.TrackRequestsByStatusPerUser = telemetry.GetEnvBool("TRACK_REQUESTS_BY_STATUS_PER_USER", cfg.MetricsConfig.TrackRequestsByStatusPerUser)
+	cfg.MetricsConfig.TrackRequestsByStatusPerBucket = telemetry.GetEnvBool("TRACK_REQUESTS_BY_STATUS_PER_BUCKET", cfg.MetricsConfig.TrackRequestsByStatusPerBucket)
+	cfg.MetricsConfig.TrackRequestsByStatusPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_STATUS_PER_TENANT", cfg.MetricsConfig.TrackRequestsByStatusPerTenant)
 
 	// Bytes metrics
-	cfg.MetricsConfig.TrackBytesSentDetailed = getEnvBool("TRACK_BYTES_SENT_DETAILED", cfg.MetricsConfig.TrackBytesSentDetailed)
-	cfg.MetricsConfig.TrackBytesSentPerUser = getEnvBool("TRACK_BYTES_SENT_PER_USER", cfg.MetricsConfig.TrackBytesSentPerUser)
-	cfg.MetricsConfig.TrackBytesSentPerBucket = getEnvBool("TRACK_BYTES_SENT_PER_BUCKET", cfg.MetricsConfig.TrackBytesSentPerBucket)
-	cfg.MetricsConfig.TrackBytesSentPerTenant = getEnvBool("TRACK_BYTES_SENT_PER_TENANT", cfg.MetricsConfig.TrackBytesSentPerTenant)
+	cfg.MetricsConfig.TrackBytesSentDetailed = telemetry.GetEnvBool("TRACK_BYTES_SENT_DETAILED", cfg.MetricsConfig.TrackBytesSentDetailed)
+	cfg.MetricsConfig.TrackBytesSentPerUser = telemetry.GetEnvBool("TRACK_BYTES_SENT_PER_USER", cfg.MetricsConfig.TrackBytesSentPerUser)
+	cfg.MetricsConfig.TrackBytesSentPerBucket = telemetry.GetEnvBool("TRACK_BYTES_SENT_PER_BUCKET", cfg.MetricsConfig.TrackBytesSentPerBucket)
+	cfg.MetricsConfig.TrackBytesSentPerTenant = telemetry.GetEnvBool("TRACK_BYTES_SENT_PER_TENANT", cfg.MetricsConfig.TrackBytesSentPerTenant)
 
-	cfg.MetricsConfig.TrackBytesReceivedDetailed = getEnvBool("TRACK_BYTES_RECEIVED_DETAILED", cfg.MetricsConfig.TrackBytesReceivedDetailed)
-	cfg.MetricsConfig.TrackBytesReceivedPerUser = getEnvBool("TRACK_BYTES_RECEIVED_PER_USER", cfg.MetricsConfig.TrackBytesReceivedPerUser)
-	cfg.MetricsConfig.TrackBytesReceivedPerBucket = getEnvBool("TRACK_BYTES_RECEIVED_PER_BUCKET", cfg.MetricsConfig.TrackBytesReceivedPerBucket)
-	cfg.MetricsConfig.TrackBytesReceivedPerTenant = getEnvBool("TRACK_BYTES_RECEIVED_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedPerTenant)
+	cfg.MetricsConfig.TrackBytesReceivedDetailed = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_DETAILED", cfg.MetricsConfig.TrackBytesReceivedDetailed)
+	cfg.MetricsConfig.TrackBytesReceivedPerUser = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_PER_USER", cfg.MetricsConfig.TrackBytesReceivedPerUser)
+	cfg.MetricsConfig.TrackBytesReceivedPerBucket = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_PER_BUCKET", cfg.MetricsConfig.TrackBytesReceivedPerBucket)
+	cfg.MetricsConfig.TrackBytesReceivedPerTenant = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedPerTenant)
 
 	// Error metrics
-	cfg.MetricsConfig.TrackErrorsDetailed = getEnvBool("TRACK_ERRORS_DETAILED", cfg.MetricsConfig.TrackErrorsDetailed)
-	cfg.MetricsConfig.TrackErrorsPerUser = getEnvBool("TRACK_ERRORS_PER_USER", cfg.MetricsConfig.TrackErrorsPerUser)
-	cfg.MetricsConfig.TrackErrorsPerBucket = getEnvBool("TRACK_ERRORS_PER_BUCKET", cfg.MetricsConfig.TrackErrorsPerBucket)
-	cfg.MetricsConfig.TrackErrorsPerTenant = getEnvBool("TRACK_ERRORS_PER_TENANT", cfg.MetricsConfig.TrackErrorsPerTenant)
-	cfg.MetricsConfig.TrackErrorsPerStatus = getEnvBool("TRACK_ERRORS_PER_STATUS", cfg.MetricsConfig.TrackErrorsPerStatus)
-	cfg.MetricsConfig.TrackErrorsByIP = getEnvBool("TRACK_ERRORS_BY_IP", cfg.MetricsConfig.TrackErrorsByIP)
-	cfg.MetricsConfig.TrackTimeoutErrors = getEnvBool("TRACK_TIMEOUT_ERRORS", cfg.MetricsConfig.TrackTimeoutErrors)
-	cfg.MetricsConfig.TrackErrorsByCategory = getEnvBool("TRACK_ERRORS_BY_CATEGORY", cfg.MetricsConfig.TrackErrorsByCategory)
+	cfg.MetricsConfig.TrackErrorsDetailed = telemetry.GetEnvBool("TRACK_ERRORS_DETAILED", cfg.MetricsConfig.TrackErrorsDetailed)
+	cfg.MetricsConfig.TrackErrorsPerUser = telemetry.GetEnvBool("TRACK_ERRORS_PER_USER", cfg.MetricsConfig.TrackErrorsPerUser)
+	cfg.MetricsConfig.TrackErrorsPerBucket = telemetry.GetEnvBool("TRACK_ERRORS_PER_BUCKET", cfg.MetricsConfig.TrackErrorsPerBucket)
+	cfg.MetricsConfig.TrackErrorsPerTenant = telemetry.GetEnvBool("TRACK_ERRORS_PER_TENANT", cfg.MetricsConfig.TrackErrorsPerTenant)
+	cfg.MetricsConfig.TrackErrorsPerStatus = telemetry.GetEnvBool("TRACK_ERRORS_PER_STATUS", cfg.MetricsConfig.TrackErrorsPerStatus)
+	cfg.MetricsConfig.TrackErrorsByIP = telemetry.GetEnvBool("TRACK_ERRORS_BY_IP", cfg.MetricsConfig.TrackErrorsByIP)
+	cfg.MetricsConfig.TrackTimeoutErrors = telemetry.GetEnvBool("TRACK_TIMEOUT_ERRORS", cfg.MetricsConfig.TrackTimeoutErrors)
+	cfg.MetricsConfig.TrackErrorsByCategory = telemetry.GetEnvBool("TRACK_ERRORS_BY_CATEGORY", cfg.MetricsConfig.TrackErrorsByCategory)
 
 	// IP-based metrics
-	cfg.MetricsConfig.TrackRequestsByIPDetailed = getEnvBool("TRACK_REQUESTS_BY_IP_DETAILED", cfg.MetricsConfig.TrackRequestsByIPDetailed)
-	cfg.MetricsConfig.TrackRequestsByIPPerTenant = getEnvBool("TRACK_REQUESTS_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackRequestsByIPPerTenant)
-	cfg.MetricsConfig.TrackRequestsByIPBucketMethodTenant = getEnvBool("TRACK_REQUESTS_BY_IP_BUCKET_METHOD_TENANT", cfg.MetricsConfig.TrackRequestsByIPBucketMethodTenant)
-	cfg.MetricsConfig.TrackRequestsByIPGlobalPerTenant = getEnvBool("TRACK_REQUESTS_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackRequestsByIPGlobalPerTenant)
+	cfg.MetricsConfig.TrackRequestsByIPDetailed = telemetry.GetEnvBool("TRACK_REQUESTS_BY_IP_DETAILED", cfg.MetricsConfig.TrackRequestsByIPDetailed)
+	cfg.MetricsConfig.TrackRequestsByIPPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackRequestsByIPPerTenant)
+	cfg.MetricsConfig.TrackRequestsByIPBucketMethodTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_IP_BUCKET_METHOD_TENANT", cfg.MetricsConfig.TrackRequestsByIPBucketMethodTenant)
+	cfg.MetricsConfig.TrackRequestsByIPGlobalPerTenant = telemetry.GetEnvBool("TRACK_REQUESTS_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackRequestsByIPGlobalPerTenant)
 
-	cfg.MetricsConfig.TrackBytesSentByIPDetailed = getEnvBool("TRACK_BYTES_SENT_BY_IP_DETAILED", cfg.MetricsConfig.TrackBytesSentByIPDetailed)
-	cfg.MetricsConfig.TrackBytesSentByIPPerTenant = getEnvBool("TRACK_BYTES_SENT_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackBytesSentByIPPerTenant)
-	cfg.MetricsConfig.TrackBytesSentByIPGlobalPerTenant = getEnvBool("TRACK_BYTES_SENT_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackBytesSentByIPGlobalPerTenant)
+	cfg.MetricsConfig.TrackBytesSentByIPDetailed = telemetry.GetEnvBool("TRACK_BYTES_SENT_BY_IP_DETAILED", cfg.MetricsConfig.TrackBytesSentByIPDetailed)
+	cfg.MetricsConfig.TrackBytesSentByIPPerTenant = telemetry.GetEnvBool("TRACK_BYTES_SENT_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackBytesSentByIPPerTenant)
+	cfg.MetricsConfig.TrackBytesSentByIPGlobalPerTenant = telemetry.GetEnvBool("TRACK_BYTES_SENT_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackBytesSentByIPGlobalPerTenant)
 
-	cfg.MetricsConfig.TrackBytesReceivedByIPDetailed = getEnvBool("TRACK_BYTES_RECEIVED_BY_IP_DETAILED", cfg.MetricsConfig.TrackBytesReceivedByIPDetailed)
-	cfg.MetricsConfig.TrackBytesReceivedByIPPerTenant = getEnvBool("TRACK_BYTES_RECEIVED_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedByIPPerTenant)
-	cfg.MetricsConfig.TrackBytesReceivedByIPGlobalPerTenant = getEnvBool("TRACK_BYTES_RECEIVED_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedByIPGlobalPerTenant)
+	cfg.MetricsConfig.TrackBytesReceivedByIPDetailed = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_BY_IP_DETAILED", cfg.MetricsConfig.TrackBytesReceivedByIPDetailed)
+	cfg.MetricsConfig.TrackBytesReceivedByIPPerTenant = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_BY_IP_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedByIPPerTenant)
+	cfg.MetricsConfig.TrackBytesReceivedByIPGlobalPerTenant = telemetry.GetEnvBool("TRACK_BYTES_RECEIVED_BY_IP_GLOBAL_PER_TENANT", cfg.MetricsConfig.TrackBytesReceivedByIPGlobalPerTenant)
 
 	// Latency metrics
-	cfg.MetricsConfig.TrackLatencyDetailed = getEnvBool("TRACK_LATENCY_DETAILED", cfg.MetricsConfig.TrackLatencyDetailed)
-	cfg.MetricsConfig.TrackLatencyPerUser = getEnvBool("TRACK_LATENCY_PER_USER", cfg.MetricsConfig.TrackLatencyPerUser)
-	cfg.MetricsConfig.TrackLatencyPerBucket = getEnvBool("TRACK_LATENCY_PER_BUCKET", cfg.MetricsConfig.TrackLatencyPerBucket)
-	cfg.MetricsConfig.TrackLatencyPerTenant = getEnvBool("TRACK_LATENCY_PER_TENANT", cfg.MetricsConfig.TrackLatencyPerTenant)
-	cfg.MetricsConfig.TrackLatencyPerMethod = getEnvBool("TRACK_LATENCY_PER_METHOD", cfg.MetricsConfig.TrackLatencyPerMethod)
-	cfg.MetricsConfig.TrackLatencyPerBucketAndMethod = getEnvBool("TRACK_LATENCY_PER_BUCKET_AND_METHOD", cfg.MetricsConfig.TrackLatencyPerBucketAndMethod)
+	cfg.MetricsConfig.TrackLatencyDetailed = telemetry.GetEnvBool("TRACK_LATENCY_DETAILED", cfg.MetricsConfig.TrackLatencyDetailed)
+	cfg.MetricsConfig.TrackLatencyPerUser = telemetry.GetEnvBool("TRACK_LATENCY_PER_USER", cfg.MetricsConfig.TrackLatencyPerUser)
+	cfg.MetricsConfig.TrackLatencyPerBucket = telemetry.GetEnvBool("TRACK_LATENCY_PER_BUCKET", cfg.MetricsConfig.TrackLatencyPerBucket)
+	cfg.MetricsConfig.TrackLatencyPerTenant = telemetry.GetEnvBool("TRACK_LATENCY_PER_TENANT", cfg.MetricsConfig.TrackLatencyPerTenant)
+	cfg.MetricsConfig.TrackLatencyPerMethod = telemetry.GetEnvBool("TRACK_LATENCY_PER_METHOD", cfg.MetricsConfig.TrackLatencyPerMethod)
+	cfg.MetricsConfig.TrackLatencyPerBucketAndMethod = telemetry.GetEnvBool("TRACK_LATENCY_PER_BUCKET_AND_METHOD", cfg.MetricsConfig.TrackLatencyPerBucketAndMethod)
+	cfg.MetricsConfig.TrackAPICategories = telemetry.GetEnvBool("TRACK_API_CATEGORIES", cfg.MetricsConfig.TrackAPICategories)
 
 	// Audit sink (RabbitMQ) configuration. These mirror the --audit-* flags so
 	// the sink can be enabled via env vars injected by the mutating webhook
 	// (Secret/ConfigMap) without editing the sidecar command line.
-	cfg.AuditSink.Enabled = getEnvBool("AUDIT_ENABLED", cfg.AuditSink.Enabled)
-	cfg.AuditSink.RabbitMQURL = getEnv("AUDIT_RABBITMQ_URL", cfg.AuditSink.RabbitMQURL)
-	cfg.AuditSink.RabbitMQUsername = getEnv("AUDIT_RABBITMQ_USERNAME", cfg.AuditSink.RabbitMQUsername)
-	cfg.AuditSink.RabbitMQPassword = getEnv("AUDIT_RABBITMQ_PASSWORD", cfg.AuditSink.RabbitMQPassword)
-	cfg.AuditSink.QueueName = getEnv("AUDIT_QUEUE_NAME", cfg.AuditSink.QueueName)
-	cfg.AuditSink.RequireTenant = getEnvBool("AUDIT_REQUIRE_TENANT", cfg.AuditSink.RequireTenant)
-	cfg.AuditSink.Region = getEnv("AUDIT_REGION", cfg.AuditSink.Region)
-	cfg.AuditSink.ObserverName = getEnv("AUDIT_OBSERVER_NAME", cfg.AuditSink.ObserverName)
-	cfg.AuditSink.IncludeReads = getEnvBool("AUDIT_INCLUDE_READS", cfg.AuditSink.IncludeReads)
-	cfg.AuditSink.SkipBuckets = getEnv("AUDIT_SKIP_BUCKETS", cfg.AuditSink.SkipBuckets)
-	cfg.AuditSink.AllowDomains = getEnv("AUDIT_ALLOW_DOMAINS", cfg.AuditSink.AllowDomains)
-	cfg.AuditSink.DenyDomains = getEnv("AUDIT_DENY_DOMAINS", cfg.AuditSink.DenyDomains)
-	cfg.AuditSink.InternalQueueSize = getEnvInt("AUDIT_QUEUE_SIZE", cfg.AuditSink.InternalQueueSize)
-	cfg.AuditSink.Debug = getEnvBool("AUDIT_DEBUG", cfg.AuditSink.Debug)
+	cfg.AuditSink.Enabled = telemetry.GetEnvBool("AUDIT_ENABLED", cfg.AuditSink.Enabled)
+	cfg.AuditSink.RabbitMQURL = telemetry.GetEnv("AUDIT_RABBITMQ_URL", cfg.AuditSink.RabbitMQURL)
+	cfg.AuditSink.RabbitMQUsername = getEnvSecret("AUDIT_RABBITMQ_USERNAME", cfg.AuditSink.RabbitMQUsername)
+	cfg.AuditSink.RabbitMQPassword = getEnvSecret("AUDIT_RABBITMQ_PASSWORD", cfg.AuditSink.RabbitMQPassword)
+	cfg.AuditSink.QueueName = telemetry.GetEnv("AUDIT_QUEUE_NAME", cfg.AuditSink.QueueName)
+	cfg.AuditSink.RequireTenant = telemetry.GetEnvBool("AUDIT_REQUIRE_TENANT", cfg.AuditSink.RequireTenant)
+	cfg.AuditSink.Region = telemetry.GetEnv("AUDIT_REGION", cfg.AuditSink.Region)
+	cfg.AuditSink.ObserverName = telemetry.GetEnv("AUDIT_OBSERVER_NAME", cfg.AuditSink.ObserverName)
+	cfg.AuditSink.IncludeReads = telemetry.GetEnvBool("AUDIT_INCLUDE_READS", cfg.AuditSink.IncludeReads)
+	cfg.AuditSink.SkipBuckets = telemetry.GetEnv("AUDIT_SKIP_BUCKETS", cfg.AuditSink.SkipBuckets)
+	cfg.AuditSink.AllowDomains = telemetry.GetEnv("AUDIT_ALLOW_DOMAINS", cfg.AuditSink.AllowDomains)
+	cfg.AuditSink.DenyDomains = telemetry.GetEnv("AUDIT_DENY_DOMAINS", cfg.AuditSink.DenyDomains)
+	cfg.AuditSink.InternalQueueSize = telemetry.GetEnvInt("AUDIT_QUEUE_SIZE", cfg.AuditSink.InternalQueueSize)
+	cfg.AuditSink.Debug = telemetry.GetEnvBool("AUDIT_DEBUG", cfg.AuditSink.Debug)
+
+	// Loki sink of the raw entries
+	cfg.LokiSink.URL = telemetry.GetEnv("LOKI_URL", cfg.LokiSink.URL)
+	cfg.LokiSink.Tenant = telemetry.GetEnv("LOKI_TENANT", cfg.LokiSink.Tenant)
+	cfg.LokiSink.Labels = telemetry.GetEnv("LOKI_LABELS", cfg.LokiSink.Labels)
+	cfg.LokiSink.BatchSize = telemetry.GetEnvInt("LOKI_BATCH_SIZE", cfg.LokiSink.BatchSize)
+	cfg.LokiSink.BatchWait = time.Duration(telemetry.GetEnvInt("LOKI_BATCH_WAIT", int(cfg.LokiSink.BatchWait/time.Second))) * time.Second
+	cfg.LokiSink.QueueSize = telemetry.GetEnvInt("LOKI_QUEUE_SIZE", cfg.LokiSink.QueueSize)
+
+	// Backpressure queue of the published entries
+	cfg.Backpressure.QueueSize = telemetry.GetEnvInt("BACKPRESSURE_QUEUE_SIZE", cfg.Backpressure.QueueSize)
+	cfg.Backpressure.HealthCheckUserAgents = telemetry.GetEnv("BACKPRESSURE_HEALTH_CHECK_USER_AGENTS", cfg.Backpressure.HealthCheckUserAgents)
+	cfg.Backpressure.HealthCheckCIDRs = telemetry.GetEnv("BACKPRESSURE_HEALTH_CHECK_CIDRS", cfg.Backpressure.HealthCheckCIDRs)
+
+	// Capture of the slow requests
+	cfg.SlowRequests.Threshold = telemetry.GetEnvDuration("SLOW_REQUEST_THRESHOLD", cfg.SlowRequests.Threshold)
+	cfg.SlowRequests.BucketThresholds = telemetry.GetEnv("SLOW_REQUEST_BUCKET_THRESHOLDS", cfg.SlowRequests.BucketThresholds)
+	cfg.SlowRequests.BufferSize = telemetry.GetEnvInt("SLOW_REQUESTS_BUFFER_SIZE", cfg.SlowRequests.BufferSize)
+	cfg.NatsSlowRequests = telemetry.GetEnvBool("NATS_SLOW_REQUESTS", cfg.NatsSlowRequests)
+	cfg.NatsSlowRequestsSubject = telemetry.GetEnv("NATS_SLOW_REQUESTS_SUBJECT", cfg.NatsSlowRequestsSubject)
+
+	// Keystone project resolution
+	cfg.Keystone.URL = telemetry.GetEnv("KEYSTONE_URL", cfg.Keystone.URL)
+	cfg.Keystone.ApplicationCredentialID = telemetry.GetEnv("KEYSTONE_APPLICATION_CREDENTIAL_ID", cfg.Keystone.ApplicationCredentialID)
+	cfg.Keystone.ApplicationCredentialSecret = getEnvSecret("KEYSTONE_APPLICATION_CREDENTIAL_SECRET", cfg.Keystone.ApplicationCredentialSecret)
+	cfg.Keystone.CacheTTL = telemetry.GetEnvDuration("KEYSTONE_CACHE_TTL", cfg.Keystone.CacheTTL)
 
 	return cfg
 }
@@ -685,9 +930,15 @@ func init() {
 	opsLogCmd.Flags().StringVar(&opsLogFilePath, "log-file", "/var/log/ceph/ceph-rgw-ops.json.log", "Path to the S3 operations log file")
 	opsLogCmd.Flags().BoolVar(&opsTruncateLogOnStart, "truncate-log-on-start", true, "Truncate ops log file at startup to avoid duplicate processing")
 	opsLogCmd.Flags().StringVar(&opsSocketPath, "socket-path", "", "Path to the Unix domain socket")
+	opsLogCmd.Flags().StringVar(&opsJournaldUnit, "journald-unit", "", "Read the ops log from the journal of this systemd unit instead of --log-file")
+	opsLogCmd.Flags().StringVar(&opsJournaldCursorFile, "journald-cursor-file", "/var/lib/prysm/ops-log-journald.cursor", "Checkpoint of the last journal entry read, resumed after a restart")
 	opsLogCmd.Flags().StringVar(&opsNatsURL, "nats-url", "", "NATS server URL")
 	opsLogCmd.Flags().StringVar(&opsNatsSubject, "nats-subject", "rgw.s3.ops", "NATS subject to publish results")
 	opsLogCmd.Flags().StringVar(&opsNatsMetricsSubject, "nats-metrics-subject", "rgw.s3.ops.aggregated.metrics", "NATS subject to publish aggregated metrics")
+	opsLogCmd.Flags().BoolVar(&opsNatsTenantSubjects, "nats-tenant-subjects", false, "Publish each entry to <nats-subject>.<tenant> instead of --nats-subject, for consumers authorized per tenant")
+	opsLogCmd.Flags().StringVar(&opsNatsRoutes, "nats-routes", "", "Comma-separated <class>=<subject> routes publishing the entries of a class (health_check, read, write, error or all) instead of to --nats-subject, e.g. error=rgw.s3.errors,write=rgw.s3.writes,all=rgw.s3.ops")
+	opsLogCmd.Flags().BoolVar(&opsNatsSecurityEvents, "nats-security-events", false, "Publish denied (401/403) and anonymous requests as security events")
+	opsLogCmd.Flags().StringVar(&opsNatsSecuritySubject, "nats-security-subject", "rgw.s3.security", "NATS subject of the security events")
 	opsLogCmd.Flags().BoolVar(&opsLogToStdout, "log-to-stdout", false, "Log operations to stdout instead of a file")
 	opsLogCmd.Flags().BoolVar(&opsLogPrettyPrint, "log-pretty-print", false, "Enable pretty printing for log output")
 	opsLogCmd.Flags().IntVar(&opsLogRetentionDays, "log-retention-days", 1, "Number of days to retain old log files")
@@ -696,6 +947,16 @@ func init() {
 	opsLogCmd.Flags().IntVar(&opsPromPort, "prometheus-port", 8080, "Prometheus metrics port")
 	opsLogCmd.Flags().BoolVar(&opsIgnoreAnonymousRequests, "ignore-anonymous-requests", true, "Ignore anonymous requests (must remain enabled when --track-bucket-slo is used to prevent tenant='none' from polluting SLI metrics)")
 	opsLogCmd.Flags().IntVar(&opsPromIntervalSeconds, "prometheus-interval", 60, "Prometheus metrics update interval in seconds")
+	opsLogCmd.Flags().StringVar(&opsIPInternalCIDRs, "ip-internal-cidrs", "", "Comma-separated CIDRs of internal clients; with --ip-cross-region-cidrs, the ip labels of the metrics become network classes (internal, cross-region, public, unknown)")
+	opsLogCmd.Flags().StringVar(&opsIPCrossRegionCIDRs, "ip-cross-region-cidrs", "", "Comma-separated CIDRs of clients in other regions, labeled cross-region")
+	opsLogCmd.Flags().StringVar(&opsReplicationUsers, "replication-users", "", "Comma-separated multisite sync users (user or user$tenant) whose requests are counted by the radosgw_replication_* metrics instead of the request metrics")
+	opsLogCmd.Flags().StringVar(&opsReplicationCIDRs, "replication-cidrs", "", "Comma-separated CIDRs of the peer zone endpoints whose requests are counted as replication")
+	opsLogCmd.Flags().StringVar(&opsCostPrices, "cost-prices", "", "Comma-separated prices of the cost estimates by tenant and bucket: egress_gb per GB sent and read, list, write, delete or other per 1,000 requests, e.g. egress_gb=0.09,read=0.0004,write=0.005")
+	opsLogCmd.Flags().StringVar(&opsBucketTagsKV, "bucket-tags-kv", "", "Bucket data KV of the radosgwusage producer (e.g. sync_bucket_data) to count the requests and bytes by bucket owner and tags")
+	opsLogCmd.Flags().StringVar(&opsBucketTags, "bucket-tags", "cost-center,environment", "Comma-separated bucket tags counted by with --bucket-tags-kv")
+	opsLogCmd.Flags().StringVar(&opsInstanceID, "instance-id", "", "Instance ID of the exporter, POD_NAME if empty")
+	opsLogCmd.Flags().StringVar(&opsNodeName, "node-name", "", "Node of the exporter, the hostname if empty")
+	opsRemoteWrite.register(opsLogCmd)
 
 	// Audit flags
 	opsLogCmd.Flags().BoolVar(&opsAuditEnabled, "audit-enabled", false, "Enable audit event publishing to RabbitMQ")
@@ -713,15 +974,59 @@ func init() {
 	opsLogCmd.Flags().StringVar(&opsAuditAllowDomains, "audit-allow-domains", "", "Comma-separated Keystone domains (ID or name) to audit; if set, only these domains are published. Empty = all domains")
 	opsLogCmd.Flags().StringVar(&opsAuditDenyDomains, "audit-deny-domains", "", "Comma-separated Keystone domains (ID or name) excluded from audit; takes precedence over --audit-allow-domains")
 
+	// Loki sink flags
+	opsLogCmd.Flags().StringVar(&opsLokiURL, "loki-url", "", "Loki base URL (e.g. http://loki:3100) to push the raw ops log entries to; empty disables the sink")
+	opsLogCmd.Flags().StringVar(&opsLokiTenant, "loki-tenant", "", "Tenant sent as X-Scope-OrgID to multi-tenant Loki")
+	opsLogCmd.Flags().StringVar(&opsLokiLabels, "loki-labels", "tenant,bucket,status_class", "Comma-separated entry fields mapped to Loki labels (tenant, bucket, status_class, operation, method)")
+	opsLogCmd.Flags().IntVar(&opsLokiBatchSize, "loki-batch-size", 1000, "Maximum entries per Loki push")
+	opsLogCmd.Flags().IntVar(&opsLokiBatchWait, "loki-batch-wait", 5, "Maximum seconds an entry waits for its Loki batch")
+	opsLogCmd.Flags().IntVar(&opsLokiQueueSize, "loki-queue-size", 10000, "Entries buffered for Loki; entries beyond are dropped and counted")
+
+	// Backpressure flags
+	opsLogCmd.Flags().IntVar(&opsBackpressureQueueSize, "backpressure-queue-size", 0, "Entries waiting to be published to NATS and stdout; when full, health checks are dropped before reads, reads before writes and writes before errors. 0 publishes synchronously")
+	opsLogCmd.Flags().StringVar(&opsBackpressureHealthCheckUserAgents, "backpressure-health-check-user-agents", "", "Comma-separated, case-insensitive user agent prefixes of health checkers, whose successful reads are dropped first")
+	opsLogCmd.Flags().StringVar(&opsBackpressureHealthCheckCIDRs, "backpressure-health-check-cidrs", "", "Comma-separated CIDRs of health checkers, whose successful reads are dropped first")
+
+	// Slow request capture flags
+	opsLogCmd.Flags().DurationVar(&opsSlowRequestThreshold, "slow-request-threshold", 0, "Latency above which the raw entry of a request is captured, served on --debug-port at /debug/slow-requests (disabled if 0)")
+	opsLogCmd.Flags().StringVar(&opsSlowRequestBucketThresholds, "slow-request-bucket-thresholds", "", "Comma-separated <bucket>=<duration> thresholds overriding --slow-request-threshold, e.g. backups=30s,web=500ms")
+	opsLogCmd.Flags().IntVar(&opsSlowRequestsBufferSize, "slow-requests-buffer-size", opslog.DefaultSlowRequestsBufferSize, "Slow requests kept, the oldest are overwritten")
+	opsLogCmd.Flags().BoolVar(&opsNatsSlowRequests, "nats-slow-requests", false, "Publish the captured slow requests to --nats-slow-requests-subject")
+	opsLogCmd.Flags().StringVar(&opsNatsSlowRequestsSubject, "nats-slow-requests-subject", "rgw.s3.slow", "NATS subject of the slow requests")
+
+	// Keystone project resolution flags
+	opsLogCmd.Flags().StringVar(&opsKeystoneURL, "keystone-url", "", "Keystone identity API v3 (e.g. https://keystone:5000/v3) the project names of the tenants are looked up at; empty disables the resolution")
+	opsLogCmd.Flags().StringVar(&opsKeystoneApplicationCredentialID, "keystone-application-credential-id", "", "ID of the Keystone application credential reading the projects")
+	opsLogCmd.Flags().StringVar(&opsKeystoneApplicationCredentialSecret, "keystone-application-credential-secret", "", "Secret of the Keystone application credential")
+	opsLogCmd.Flags().DurationVar(&opsKeystoneCacheTTL, "keystone-cache-ttl", time.Hour, "How long a project name is used before it is looked up again")
+
 	// Shortcut flag
 	opsLogCmd.Flags().BoolVar(&opsTrackEverything, "track-everything", false, "Enable detailed tracking for all metric types (efficient mode)")
 	opsLogCmd.Flags().BoolVar(&opsTrackBucketSLO, "track-bucket-slo", false, "Track low-cardinality bucket GET/LIST SLI metrics for Prometheus SLOs")
+	opsLogCmd.Flags().BoolVar(&opsTrackSecurity, "track-security", false, "Track denied requests by user, IP and bucket, and anonymous requests, also when --ignore-anonymous-requests is set")
+	opsLogCmd.Flags().BoolVar(&opsTrackBucketConcurrency, "track-bucket-concurrency", false, "Track the estimated requests in flight per bucket, the maximum and average of every interval, e.g. to size the RGW thread pools")
+	opsLogCmd.Flags().BoolVar(&opsTrackAuthMethods, "track-auth-methods", false, "Track the requests per user by authentication type and AWS signature version, e.g. to find the clients still signing with version 2")
+	opsLogCmd.Flags().BoolVar(&opsTrackHealthChecks, "track-health-checks", false, "Detect health-check-like traffic, small reads of the same operation on the same key at a high frequency, and report the top patterns, e.g. to find aggressive HEAD bucket checks")
+	opsLogCmd.Flags().IntVar(&opsHealthCheckMinPerMin, "health-check-min-per-minute", opslog.DefaultHealthCheckMinPerMinute, "Requests a minute of the same operation on the same key, at least, to be health-check-like")
+	opsLogCmd.Flags().IntVar(&opsHealthCheckTop, "health-check-top", opslog.DefaultHealthCheckTop, "Most frequent health-check-like patterns reported with their bucket and object")
 
 	existingOpsLogPreRunE := opsLogCmd.PreRunE
 	opsLogCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if opsTrackBucketSLO && !opsPromEnabled {
 			return fmt.Errorf("--track-bucket-slo requires --prometheus")
 		}
+		if opsTrackSecurity && !opsPromEnabled {
+			return fmt.Errorf("--track-security requires --prometheus")
+		}
+		if opsTrackBucketConcurrency && !opsPromEnabled {
+			return fmt.Errorf("--track-bucket-concurrency requires --prometheus")
+		}
+		if opsTrackAuthMethods && !opsPromEnabled {
+			return fmt.Errorf("--track-auth-methods requires --prometheus")
+		}
+		if opsTrackHealthChecks && !opsPromEnabled {
+			return fmt.Errorf("--track-health-checks requires --prometheus")
+		}
 		if existingOpsLogPreRunE != nil {
 			return existingOpsLogPreRunE(cmd, args)
 		}
@@ -797,6 +1102,9 @@ func init() {
 	opsLogCmd.Flags().BoolVar(&opsTrackLatencyPerTenant, "track-latency-per-tenant", false, "Track latency per tenant")
 	opsLogCmd.Flags().BoolVar(&opsTrackLatencyPerMethod, "track-latency-per-method", false, "Track latency per method")
 	opsLogCmd.Flags().BoolVar(&opsTrackLatencyPerBucketAndMethod, "track-latency-per-bucket-and-method", false, "Track latency per bucket and method")
+
+	// API category metrics
+	opsLogCmd.Flags().BoolVar(&opsTrackAPICategories, "track-api-categories", false, "Publish the requests, bytes and latency per user and bucket by API category to NATS")
 }
 
 func validateOpsLogConfig(config opslog.OpsLogConfig) {
@@ -807,6 +1115,78 @@ func validateOpsLogConfig(config opslog.OpsLogConfig) {
 		missingParams = true
 	}
 
+	if config.JournaldUnit != "" && config.SocketPath != "" {
+		fmt.Println("Warning: --journald-unit or JOURNALD_UNIT and --socket-path or SOCKET_PATH are mutually exclusive")
+		missingParams = true
+	}
+
+	if config.JournaldUnit != "" && config.JournaldCursorFile == "" {
+		fmt.Println("Warning: --journald-unit or JOURNALD_UNIT requires --journald-cursor-file or JOURNALD_CURSOR_FILE")
+		missingParams = true
+	}
+
+	if !validateRemoteWriteConfig(config.RemoteWrite, config.Prometheus) {
+		missingParams = true
+	}
+
+	if _, err := opslog.NewIPClassifier(config.IPInternalCIDRs, config.IPCrossRegionCIDRs); err != nil {
+		fmt.Printf("Warning: --ip-internal-cidrs or --ip-cross-region-cidrs: %v\n", err)
+		missingParams = true
+	}
+
+	if _, err := opslog.NewReplicationMatcher(config.ReplicationUsers, config.ReplicationCIDRs); err != nil {
+		fmt.Printf("Warning: --replication-cidrs or REPLICATION_CIDRS: %v\n", err)
+		missingParams = true
+	}
+
+	if _, err := opslog.NewCostModel(config.CostPrices); err != nil {
+		fmt.Printf("Warning: --cost-prices or COST_PRICES: %v\n", err)
+		missingParams = true
+	}
+	if config.CostPrices != "" && !config.Prometheus {
+		fmt.Println("Warning: --cost-prices or COST_PRICES requires --prometheus")
+		missingParams = true
+	}
+
+	if _, err := opslog.NewProjectResolver(config.Keystone); err != nil {
+		fmt.Println("Warning: --keystone-url or KEYSTONE_URL requires --keystone-application-credential-id and --keystone-application-credential-secret")
+		missingParams = true
+	}
+
+	if err := config.Backpressure.Validate(); err != nil {
+		fmt.Printf("Warning: --backpressure-queue-size or --backpressure-health-check-cidrs: %v\n", err)
+		missingParams = true
+	}
+
+	if err := config.SlowRequests.Validate(); err != nil {
+		fmt.Printf("Warning: --slow-request-threshold or --slow-request-bucket-thresholds: %v\n", err)
+		missingParams = true
+	}
+	if config.NatsSlowRequests && config.NatsURL == "" {
+		fmt.Println("Warning: --nats-slow-requests or NATS_SLOW_REQUESTS requires --nats-url or NATS_URL")
+		missingParams = true
+	}
+
+	if _, err := opslog.ParseNatsRoutes(config.NatsRoutes); err != nil {
+		fmt.Printf("Warning: --nats-routes or NATS_ROUTES: %v\n", err)
+		missingParams = true
+	}
+
+	if config.NatsSecurityEvents && config.NatsURL == "" {
+		fmt.Println("Warning: --nats-security-events or NATS_SECURITY_EVENTS requires --nats-url or NATS_URL")
+		missingParams = true
+	}
+
+	if config.MetricsConfig.TrackAPICategories && config.NatsURL == "" {
+		fmt.Println("Warning: --track-api-categories or TRACK_API_CATEGORIES requires --nats-url or NATS_URL")
+		missingParams = true
+	}
+
+	if config.BucketTagsKV != "" && config.NatsURL == "" {
+		fmt.Println("Warning: --bucket-tags-kv or BUCKET_TAGS_KV requires --nats-url or NATS_URL")
+		missingParams = true
+	}
+
 	if missingParams {
 		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
 		os.Exit(1)
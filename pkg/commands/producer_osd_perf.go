@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/osdperf"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	opSocketGlob  string
+	opInterval    int
+	opTimeout     int
+	opNatsURL     string
+	opNatsSubject string
+	opPromEnabled bool
+	opPromPort    int
+	opNodeName    string
+	opInstanceID  string
+)
+
+var osdPerfCmd = &cobra.Command{
+	Use:   "osd-perf",
+	Short: "OSD commit/apply latency and op queue metrics from the admin sockets on the node",
+	Run: func(cmd *cobra.Command, args []string) {
+		runOSDPerf(osdPerfConfig())
+	},
+}
+
+// osdPerfConfig returns the configuration of the flags and environment
+// variables
+func osdPerfConfig() osdperf.OSDPerfConfig {
+	config := osdperf.OSDPerfConfig{
+		SocketGlob:     opSocketGlob,
+		Interval:       opInterval,
+		Timeout:        opTimeout,
+		NatsURL:        opNatsURL,
+		NatsSubject:    opNatsSubject,
+		Prometheus:     opPromEnabled,
+		PrometheusPort: opPromPort,
+		NodeName:       opNodeName,
+		InstanceID:     opInstanceID,
+	}
+
+	return mergeOSDPerfConfigWithEnv(config)
+}
+
+func runOSDPerf(config osdperf.OSDPerfConfig) {
+	config.UseNats = config.NatsURL != ""
+
+	event := log.Info()
+	event.Str("socket_glob", config.SocketGlob)
+
+	event.Bool("use_nats", config.UseNats)
+	if config.UseNats {
+		event.Str("nats_url", config.NatsURL)
+		event.Str("nats_subject", config.NatsSubject)
+	}
+
+	event.Bool("prometheus_enabled", config.Prometheus)
+	if config.Prometheus {
+		event.Int("prometheus_port", config.PrometheusPort)
+	}
+
+	event.Str("node_name", config.NodeName)
+	event.Str("instance_id", config.InstanceID)
+	event.Int("interval_seconds", config.Interval)
+
+	event.Msg("configuration_loaded")
+
+	validateOSDPerfConfig(config)
+	publishProducerConfig("osd-perf", config.NatsURL, config.NodeName, config.InstanceID, config)
+
+	osdperf.StartMonitoring(config)
+}
+
+func mergeOSDPerfConfigWithEnv(cfg osdperf.OSDPerfConfig) osdperf.OSDPerfConfig {
+	cfg.SocketGlob = telemetry.GetEnv("SOCKET_GLOB", cfg.SocketGlob)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.Timeout = telemetry.GetEnvInt("TIMEOUT", cfg.Timeout)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+
+	return cfg
+}
+
+func init() {
+	osdPerfCmd.Flags().StringVar(&opSocketGlob, "socket-glob", "/var/run/ceph/ceph-osd.*.asok", "Admin sockets of the OSDs on the node")
+	osdPerfCmd.Flags().IntVar(&opInterval, "interval", 15, "Interval in seconds between collections")
+	osdPerfCmd.Flags().IntVar(&opTimeout, "timeout", 5, "Timeout in seconds of an admin socket command")
+	osdPerfCmd.Flags().StringVar(&opNatsURL, "nats-url", "", "NATS server URL")
+	osdPerfCmd.Flags().StringVar(&opNatsSubject, "nats-subject", "osd.perf", "NATS subject to publish OSD perf counters")
+	osdPerfCmd.Flags().BoolVar(&opPromEnabled, "prometheus", false, "Enable Prometheus metrics")
+	osdPerfCmd.Flags().IntVar(&opPromPort, "prometheus-port", 8080, "Prometheus metrics port")
+	osdPerfCmd.Flags().StringVar(&opNodeName, "node-name", "", "Name of the node, the same as of disk-health-metrics")
+	osdPerfCmd.Flags().StringVar(&opInstanceID, "instance-id", "", "Instance ID")
+}
+
+func validateOSDPerfConfig(config osdperf.OSDPerfConfig) {
+	missingParams := false
+
+	if config.SocketGlob == "" {
+		fmt.Println("Warning: --socket-glob (or SOCKET_GLOB) must be set")
+		missingParams = true
+	}
+	if config.Interval <= 0 || config.Timeout <= 0 {
+		fmt.Println("Warning: --interval and --timeout must be positive")
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
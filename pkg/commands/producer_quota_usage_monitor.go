@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/quotausagemonitor"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -62,21 +63,22 @@ var quotaUsageMonitorCmd = &cobra.Command{
 		event.Msg("configuration_loaded")
 
 		validateQuotaUsageMonitorConfig(config)
+		publishProducerConfig("quota-usage-monitor", config.NatsURL, config.NodeName, config.InstanceID, config)
 
 		quotausagemonitor.StartMonitoring(config)
 	},
 }
 
 func mergeQuotaUsageMonitorConfigWithEnv(cfg quotausagemonitor.QuotaUsageMonitorConfig) quotausagemonitor.QuotaUsageMonitorConfig {
-	cfg.AdminURL = getEnv("ADMIN_URL", cfg.AdminURL)
-	cfg.AccessKey = getEnv("ACCESS_KEY", cfg.AccessKey)
-	cfg.SecretKey = getEnv("SECRET_KEY", cfg.SecretKey)
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
-	cfg.Interval = getEnvInt("INTERVAL", cfg.Interval)
-	cfg.QuotaUsagePercent = getEnvFloat("QUOTA_USAGE_PERCENT", cfg.QuotaUsagePercent)
+	cfg.AdminURL = telemetry.GetEnv("ADMIN_URL", cfg.AdminURL)
+	cfg.AccessKey = getEnvSecret("ACCESS_KEY", cfg.AccessKey)
+	cfg.SecretKey = getEnvSecret("SECRET_KEY", cfg.SecretKey)
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
+	cfg.QuotaUsagePercent = telemetry.GetEnvFloat("QUOTA_USAGE_PERCENT", cfg.QuotaUsagePercent)
 
 	return cfg
 }
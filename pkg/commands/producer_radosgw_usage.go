@@ -7,18 +7,26 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 var (
+	rgwuSource                  string
 	rgwuAdminURL                string
 	rgwuAccessKey               string
 	rgwuSecretKey               string
+	rgwuRadosGWAdminBinary      string
+	rgwuCephConf                string
+	rgwuCephName                string
+	rgwuCephKeyring             string
 	rgwuPrometheus              bool
 	rgwuPrometheusPort          int
+	rgwuRemoteWrite             remoteWriteFlags
 	rgwuNodeName                string
 	rgwuInstanceID              string
 	rgwuCooldownInterval        int
@@ -27,36 +35,61 @@ var (
 	rgwuSyncExternalNats        bool
 	rgwuSyncControlURL          string
 	rgwuSyncControlBucketPrefix string
+	rgwuReshardObjectsPerShard  int
+	rgwuReshardNotify           bool
+	rgwuLargeOmapKeysPerShard   int
+	rgwuAuditBucketAccess       bool
+	rgwuPublicBucketNotify      bool
+	rgwuOpsMetricsJoin          bool
+	rgwuOpsMetricsSubject       string
+	rgwuAdminAPIFaults          string
+	rgwuDryRun                  bool
+	rgwuProbeTargets            string
+
+	rgwuTenantAnomalyNotify          bool
+	rgwuTenantAnomalyHistory         int
+	rgwuTenantAnomalyGrowthFactor    int
+	rgwuTenantAnomalyDeletionPercent int
+	rgwuCompletenessPercent          int
+	rgwuTenantAnomalyMinGiB          int
+
+	rgwuKVTTLs            string
+	rgwuKVCompactAge      time.Duration
+	rgwuKVCompactInterval time.Duration
+	rgwuKVWriteWorkers    int
+
+	rgwuRookDiscovery         bool
+	rgwuRookNamespace         string
+	rgwuRookDiscoveryInterval time.Duration
 )
 
 var radosGWUsageCmd = &cobra.Command{
 	Use:   "radosgw-usage",
 	Short: "RadosGW usage exporter",
 	Run: func(cmd *cobra.Command, args []string) {
-		config := radosgwusage.RadosGWUsageConfig{
-			AdminURL:                rgwuAdminURL,
-			AccessKey:               rgwuAccessKey,
-			SecretKey:               rgwuSecretKey,
-			Prometheus:              rgwuPrometheus,
-			PrometheusPort:          rgwuPrometheusPort,
-			NodeName:                rgwuNodeName,
-			InstanceID:              rgwuInstanceID,
-			CooldownInterval:        rgwuCooldownInterval,
-			ClusterID:               rgwuClusterID,
-			SyncControlNats:         rgwuSyncControlNats,
-			SyncExternalNats:        rgwuSyncExternalNats,
-			SyncControlURL:          rgwuSyncControlURL,
-			SyncControlBucketPrefix: rgwuSyncControlBucketPrefix,
-		}
-
-		config = mergeRadosGWUsageConfigWithEnv(config)
+		config := radosGWUsageConfig()
+		if config.DryRun {
+			// The metrics of the dry run are printed on stdout
+			if err := setUpLogsOn(os.Stderr); err != nil {
+				log.Fatal().Err(err).Msg("Failed to move the logs to stderr")
+			}
+		}
 
 		event := log.Info()
 
+		event.Str("source", config.Source)
+		if config.Source == radosgwusage.SourceCLI {
+			event.Str("radosgw_admin", config.RadosGWAdminBinary)
+			event.Str("ceph_conf", config.CephConf)
+			event.Str("ceph_name", config.CephName)
+		} else {
+			event.Str("admin_url", config.AdminURL)
+		}
 		event.Bool("prometheus_enabled", config.Prometheus)
 		if config.Prometheus {
 			event.Int("prometheus_port", config.PrometheusPort)
 		}
+		logRemoteWriteConfig(event, config.RemoteWrite)
 
 		event.Str("node_name", config.NodeName)
 		event.Str("instance_id", config.InstanceID)
@@ -70,67 +103,263 @@ var radosGWUsageCmd = &cobra.Command{
 				event.Str("sync_control_url", config.SyncControlURL)
 			}
 			event.Str("sync_control_bucket_prefix", config.SyncControlBucketPrefix)
+			if config.KVTTLs != "" {
+				event.Str("kv_ttl", config.KVTTLs)
+			}
+			event.Dur("kv_compact_age", config.KVCompactAge)
+			if config.KVCompactAge > 0 {
+				event.Dur("kv_compact_interval", config.KVCompactInterval)
+			}
+			event.Int("kv_write_workers", config.KVWriteWorkers)
+		}
+
+		event.Int("reshard_objects_per_shard", config.ReshardObjectsPerShard)
+		event.Bool("reshard_notify_enabled", config.ReshardNotify)
+		event.Int("large_omap_keys_per_shard", config.LargeOmapKeysPerShard)
+
+		event.Bool("audit_bucket_access_enabled", config.AuditBucketAccess)
+		if config.AuditBucketAccess {
+			event.Bool("public_bucket_notify_enabled", config.PublicBucketNotify)
+		}
+
+		event.Bool("tenant_anomaly_notify_enabled", config.TenantAnomalyNotify)
+		if config.TenantAnomalyNotify {
+			event.Int("tenant_anomaly_history", config.TenantAnomalyHistory)
+			event.Int("tenant_anomaly_growth_factor", config.TenantAnomalyGrowthFactor)
+			event.Int("tenant_anomaly_deletion_percent", config.TenantAnomalyDeletionPercent)
+			event.Int("tenant_anomaly_min_gib", config.TenantAnomalyMinGiB)
+		}
+
+		event.Int("completeness_percent", config.CompletenessPercent)
+
+		event.Bool("ops_metrics_join_enabled", config.OpsMetricsJoin)
+		if config.OpsMetricsJoin {
+			event.Str("ops_metrics_subject", config.OpsMetricsSubject)
+		}
+
+		if config.AdminAPIFaults != "" {
+			event.Str("admin_api_faults", config.AdminAPIFaults)
+		}
+		event.Bool("dry_run", config.DryRun)
+		if config.ProbeTargets != "" {
+			event.Str("probe_targets", config.ProbeTargets)
+		}
+		event.Bool("rook_discovery_enabled", config.RookDiscovery)
+		if config.RookDiscovery {
+			event.Str("rook_namespace", config.RookNamespace)
+			event.Dur("rook_discovery_interval", config.RookDiscoveryInterval)
 		}
 
 		// Finalize the log message with the main message
 		event.Msg("configuration_loaded")
 
+		if config.ProbeTargets != "" || config.RookDiscovery {
+			radosgwusage.StartProbeExporter(config, loadProbeTargets(config))
+			return
+		}
+
 		validateRadosGWUsageConfig(config)
+		if config.DryRun {
+			if err := radosgwusage.RunDryRun(config, os.Stdout); err != nil {
+				log.Fatal().Err(err).Msg("Dry run failed")
+			}
+			return
+		}
+		if config.SyncExternalNats {
+			publishProducerConfig("radosgw-usage", config.SyncControlURL, config.NodeName, config.InstanceID, config)
+		}
 
 		radosgwusage.StartRadosGWUsageExporter(config)
 	},
 }
 
+// radosGWUsageConfig returns the configuration of the flags and environment
+// variables
+func radosGWUsageConfig() radosgwusage.RadosGWUsageConfig {
+	config := radosgwusage.RadosGWUsageConfig{
+		Source:                  rgwuSource,
+		AdminURL:                rgwuAdminURL,
+		AccessKey:               rgwuAccessKey,
+		SecretKey:               rgwuSecretKey,
+		RadosGWAdminBinary:      rgwuRadosGWAdminBinary,
+		CephConf:                rgwuCephConf,
+		CephName:                rgwuCephName,
+		CephKeyring:             rgwuCephKeyring,
+		Prometheus:              rgwuPrometheus,
+		PrometheusPort:          rgwuPrometheusPort,
+		NodeName:                rgwuNodeName,
+		InstanceID:              rgwuInstanceID,
+		CooldownInterval:        rgwuCooldownInterval,
+		ClusterID:               rgwuClusterID,
+		SyncControlNats:         rgwuSyncControlNats,
+		SyncExternalNats:        rgwuSyncExternalNats,
+		SyncControlURL:          rgwuSyncControlURL,
+		SyncControlBucketPrefix: rgwuSyncControlBucketPrefix,
+		ReshardObjectsPerShard:  rgwuReshardObjectsPerShard,
+		ReshardNotify:           rgwuReshardNotify,
+		LargeOmapKeysPerShard:   rgwuLargeOmapKeysPerShard,
+		AuditBucketAccess:       rgwuAuditBucketAccess,
+		PublicBucketNotify:      rgwuPublicBucketNotify,
+		OpsMetricsJoin:          rgwuOpsMetricsJoin,
+		OpsMetricsSubject:       rgwuOpsMetricsSubject,
+		AdminAPIFaults:          rgwuAdminAPIFaults,
+		DryRun:                  rgwuDryRun,
+		ProbeTargets:            rgwuProbeTargets,
+
+		TenantAnomalyNotify:          rgwuTenantAnomalyNotify,
+		TenantAnomalyHistory:         rgwuTenantAnomalyHistory,
+		TenantAnomalyGrowthFactor:    rgwuTenantAnomalyGrowthFactor,
+		TenantAnomalyDeletionPercent: rgwuTenantAnomalyDeletionPercent,
+		TenantAnomalyMinGiB:          rgwuTenantAnomalyMinGiB,
+
+		CompletenessPercent: rgwuCompletenessPercent,
+
+		KVTTLs:            rgwuKVTTLs,
+		KVCompactAge:      rgwuKVCompactAge,
+		KVCompactInterval: rgwuKVCompactInterval,
+		KVWriteWorkers:    rgwuKVWriteWorkers,
+
+		RookDiscovery:         rgwuRookDiscovery,
+		RookNamespace:         rgwuRookNamespace,
+		RookDiscoveryInterval: rgwuRookDiscoveryInterval,
+	}
+
+	config = mergeRadosGWUsageConfigWithEnv(config)
+	config.RemoteWrite = remoteWriteConfig(rgwuRemoteWrite)
+	return config
+}
+
 func mergeRadosGWUsageConfigWithEnv(cfg radosgwusage.RadosGWUsageConfig) radosgwusage.RadosGWUsageConfig {
-	cfg.AdminURL = getEnv("ADMIN_URL", cfg.AdminURL)
-	cfg.AccessKey = getEnv("ACCESS_KEY", cfg.AccessKey)
-	cfg.SecretKey = getEnv("SECRET_KEY", cfg.SecretKey)
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
-	cfg.Prometheus = getEnvBool("PROMETHEUS_ENABLED", cfg.Prometheus)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
-	cfg.CooldownInterval = getEnvInt("COOLDOWN_INTERVAL", cfg.CooldownInterval)
-	cfg.ClusterID = getEnv("RGW_CLUSTER_ID", cfg.ClusterID)
+	cfg.Source = telemetry.GetEnv("RGW_USAGE_SOURCE", cfg.Source)
+	cfg.AdminURL = telemetry.GetEnv("ADMIN_URL", cfg.AdminURL)
+	cfg.AccessKey = getEnvSecret("ACCESS_KEY", cfg.AccessKey)
+	cfg.SecretKey = getEnvSecret("SECRET_KEY", cfg.SecretKey)
+	cfg.RadosGWAdminBinary = telemetry.GetEnv("RADOSGW_ADMIN_BINARY", cfg.RadosGWAdminBinary)
+	cfg.CephConf = telemetry.GetEnv("CEPH_CONF", cfg.CephConf)
+	cfg.CephName = telemetry.GetEnv("CEPH_NAME", cfg.CephName)
+	cfg.CephKeyring = telemetry.GetEnv("CEPH_KEYRING", cfg.CephKeyring)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+	cfg.CooldownInterval = telemetry.GetEnvInt("COOLDOWN_INTERVAL", cfg.CooldownInterval)
+	cfg.ClusterID = telemetry.GetEnv("RGW_CLUSTER_ID", cfg.ClusterID)
 	// Sync control related parameters
-	cfg.SyncControlNats = getEnvBool("SYNC_CONTROL_NATS", cfg.SyncControlNats)
-	cfg.SyncExternalNats = getEnvBool("SYNC_EXTERNAL_NATS", cfg.SyncExternalNats)
-	cfg.SyncControlURL = getEnv("SYNC_CONTROL_URL", cfg.SyncControlURL)
-	cfg.SyncControlBucketPrefix = getEnv("SYNC_CONTROL_BUCKET_PREFIX", cfg.SyncControlBucketPrefix)
+	cfg.SyncControlNats = telemetry.GetEnvBool("SYNC_CONTROL_NATS", cfg.SyncControlNats)
+	cfg.SyncExternalNats = telemetry.GetEnvBool("SYNC_EXTERNAL_NATS", cfg.SyncExternalNats)
+	cfg.SyncControlURL = telemetry.GetEnv("SYNC_CONTROL_URL", cfg.SyncControlURL)
+	cfg.SyncControlBucketPrefix = telemetry.GetEnv("SYNC_CONTROL_BUCKET_PREFIX", cfg.SyncControlBucketPrefix)
+	cfg.KVTTLs = telemetry.GetEnv("KV_TTL", cfg.KVTTLs)
+	cfg.KVCompactAge = telemetry.GetEnvDuration("KV_COMPACT_AGE", cfg.KVCompactAge)
+	cfg.KVCompactInterval = telemetry.GetEnvDuration("KV_COMPACT_INTERVAL", cfg.KVCompactInterval)
+	cfg.KVWriteWorkers = telemetry.GetEnvInt("KV_WRITE_WORKERS", cfg.KVWriteWorkers)
+	// Resharding recommendation parameters
+	cfg.ReshardObjectsPerShard = telemetry.GetEnvInt("RESHARD_OBJECTS_PER_SHARD", cfg.ReshardObjectsPerShard)
+	cfg.ReshardNotify = telemetry.GetEnvBool("RESHARD_NOTIFY", cfg.ReshardNotify)
+	cfg.LargeOmapKeysPerShard = telemetry.GetEnvInt("LARGE_OMAP_KEYS_PER_SHARD", cfg.LargeOmapKeysPerShard)
+	// Bucket access audit parameters
+	cfg.AuditBucketAccess = telemetry.GetEnvBool("AUDIT_BUCKET_ACCESS", cfg.AuditBucketAccess)
+	cfg.PublicBucketNotify = telemetry.GetEnvBool("PUBLIC_BUCKET_NOTIFY", cfg.PublicBucketNotify)
+	// Tenant anomaly parameters
+	cfg.TenantAnomalyNotify = telemetry.GetEnvBool("TENANT_ANOMALY_NOTIFY", cfg.TenantAnomalyNotify)
+	cfg.TenantAnomalyHistory = telemetry.GetEnvInt("TENANT_ANOMALY_HISTORY", cfg.TenantAnomalyHistory)
+	cfg.TenantAnomalyGrowthFactor = telemetry.GetEnvInt("TENANT_ANOMALY_GROWTH_FACTOR", cfg.TenantAnomalyGrowthFactor)
+	cfg.TenantAnomalyDeletionPercent = telemetry.GetEnvInt("TENANT_ANOMALY_DELETION_PERCENT", cfg.TenantAnomalyDeletionPercent)
+	cfg.TenantAnomalyMinGiB = telemetry.GetEnvInt("TENANT_ANOMALY_MIN_GIB", cfg.TenantAnomalyMinGiB)
+	cfg.CompletenessPercent = telemetry.GetEnvInt("COMPLETENESS_PERCENT", cfg.CompletenessPercent)
+	// Ops log join parameters
+	cfg.OpsMetricsJoin = telemetry.GetEnvBool("OPS_METRICS_JOIN", cfg.OpsMetricsJoin)
+	cfg.OpsMetricsSubject = telemetry.GetEnv("OPS_METRICS_SUBJECT", cfg.OpsMetricsSubject)
+	// Fault injection parameters
+	cfg.AdminAPIFaults = telemetry.GetEnv("ADMIN_API_FAULTS", cfg.AdminAPIFaults)
+	cfg.DryRun = telemetry.GetEnvBool("DRY_RUN", cfg.DryRun)
+	cfg.ProbeTargets = telemetry.GetEnv("PROBE_TARGETS", cfg.ProbeTargets)
+	cfg.RookDiscovery = telemetry.GetEnvBool("ROOK_DISCOVERY", cfg.RookDiscovery)
+	cfg.RookNamespace = telemetry.GetEnv("ROOK_NAMESPACE", cfg.RookNamespace)
+	cfg.RookDiscoveryInterval = telemetry.GetEnvDuration("ROOK_DISCOVERY_INTERVAL", cfg.RookDiscoveryInterval)
 
 	return cfg
 }
 
 func init() {
+	radosGWUsageCmd.Flags().StringVar(&rgwuSource, "source", radosgwusage.SourceAdminAPI, "Where to read the users, buckets and usage: admin-api (RGW admin REST API) or cli (radosgw-admin)")
 	radosGWUsageCmd.Flags().StringVar(&rgwuAdminURL, "admin-url", "", "Admin URL for the RadosGW instance")
 	radosGWUsageCmd.Flags().StringVar(&rgwuAccessKey, "access-key", "", "Access key for the RadosGW admin")
 	radosGWUsageCmd.Flags().StringVar(&rgwuSecretKey, "secret-key", "", "Secret key for the RadosGW admin")
+	radosGWUsageCmd.Flags().StringVar(&rgwuRadosGWAdminBinary, "radosgw-admin", "radosgw-admin", "Path of the radosgw-admin CLI of --source=cli")
+	radosGWUsageCmd.Flags().StringVar(&rgwuCephConf, "ceph-conf", "", "Ceph configuration file of radosgw-admin, its default if not set")
+	radosGWUsageCmd.Flags().StringVar(&rgwuCephName, "ceph-name", "", "Ceph user of radosgw-admin, e.g. client.admin")
+	radosGWUsageCmd.Flags().StringVar(&rgwuCephKeyring, "ceph-keyring", "", "Keyring of the Ceph user of radosgw-admin")
 	radosGWUsageCmd.Flags().StringVar(&rgwuClusterID, "rgw-cluster-id", "", "RGW Cluster ID added to metrics")
 	radosGWUsageCmd.Flags().StringVar(&rgwuNodeName, "node-name", "", "Name of the node")
 	radosGWUsageCmd.Flags().StringVar(&rgwuInstanceID, "instance-id", "", "Instance ID")
 	radosGWUsageCmd.Flags().BoolVar(&rgwuPrometheus, "prometheus", false, "Enable Prometheus metrics")
 	radosGWUsageCmd.Flags().IntVar(&rgwuPrometheusPort, "prometheus-port", 8080, "Prometheus metrics port")
+	rgwuRemoteWrite.register(radosGWUsageCmd)
 	radosGWUsageCmd.Flags().IntVar(&rgwuCooldownInterval, "cooldown-interval", 120, "Cooldown interval in seconds")
 	// Sync control related flags
 	radosGWUsageCmd.Flags().BoolVar(&rgwuSyncControlNats, "sync-control-nats", true, "Enable sync control using NATS")
 	radosGWUsageCmd.Flags().BoolVar(&rgwuSyncExternalNats, "sync-external-nats", false, "Use external NATS server for sync control")
 	radosGWUsageCmd.Flags().StringVar(&rgwuSyncControlURL, "sync-control-url", "", "URL of the external NATS server for sync control")
 	radosGWUsageCmd.Flags().StringVar(&rgwuSyncControlBucketPrefix, "sync-control-bucket-prefix", "sync", "NATS KV bucket prefix for sync control")
-
+	radosGWUsageCmd.Flags().StringVar(&rgwuKVTTLs, "kv-ttl", "", "TTLs of the NATS KV buckets, without the prefix, e.g. user_usage_data=72h,bucket_data=72h (keys are kept forever if not set)")
+	radosGWUsageCmd.Flags().DurationVar(&rgwuKVCompactAge, "kv-compact-age", 0, "Purge the keys of the NATS KV buckets not written for longer, e.g. 24h (0 disables)")
+	radosGWUsageCmd.Flags().DurationVar(&rgwuKVCompactInterval, "kv-compact-interval", time.Hour, "Interval of the compaction of the NATS KV buckets")
+	radosGWUsageCmd.Flags().IntVar(&rgwuKVWriteWorkers, "kv-write-workers", 10, "Parallel writes to the NATS KV buckets")
+	// Resharding recommendation flags
+	radosGWUsageCmd.Flags().IntVar(&rgwuReshardObjectsPerShard, "reshard-objects-per-shard", 100000, "Objects per bucket index shard above which resharding is recommended (0 disables)")
+	radosGWUsageCmd.Flags().BoolVar(&rgwuReshardNotify, "reshard-notify", false, "Publish a NATS event listing buckets that need resharding")
+	radosGWUsageCmd.Flags().IntVar(&rgwuLargeOmapKeysPerShard, "large-omap-keys-per-shard", 200000, "OMAP keys per bucket index shard above which Ceph warns of large OMAP objects, osd_deep_scrub_large_omap_object_key_threshold (0 disables)")
+	// Bucket access audit flags
+	radosGWUsageCmd.Flags().BoolVar(&rgwuAuditBucketAccess, "audit-bucket-access", false, "Evaluate bucket ACLs and policies and export public and authenticated access")
+	radosGWUsageCmd.Flags().BoolVar(&rgwuPublicBucketNotify, "public-bucket-notify", false, "Publish a NATS event when a bucket becomes public (requires --audit-bucket-access)")
+	// Tenant anomaly flags
+	radosGWUsageCmd.Flags().BoolVar(&rgwuTenantAnomalyNotify, "tenant-anomaly-notify", false, "Publish a NATS event when the storage of a tenant grows or shrinks anomalously between two cycles")
+	radosGWUsageCmd.Flags().IntVar(&rgwuTenantAnomalyHistory, "tenant-anomaly-history", 12, "Cycles the growth of a tenant is compared with")
+	radosGWUsageCmd.Flags().IntVar(&rgwuTenantAnomalyGrowthFactor, "tenant-anomaly-growth-factor", 10, "Growth above this many times the average growth of the recent cycles is anomalous")
+	radosGWUsageCmd.Flags().IntVar(&rgwuTenantAnomalyDeletionPercent, "tenant-anomaly-deletion-percent", 50, "Loss of this percentage of the bytes or objects of a tenant in one cycle is anomalous")
+	radosGWUsageCmd.Flags().IntVar(&rgwuTenantAnomalyMinGiB, "tenant-anomaly-min-gib", 1, "Growth or loss in GiB below which a tenant is never anomalous")
+	radosGWUsageCmd.Flags().IntVar(&rgwuCompletenessPercent, "completeness-percent", 100, "Percent of the users and buckets a cycle must collect, below it the metrics in the KV and the NATS events derived from them are marked partial (0 never marks)")
+	// Ops log join flags
+	radosGWUsageCmd.Flags().BoolVar(&rgwuOpsMetricsJoin, "ops-metrics-join", false, "Join the traffic and latency by API category of the ops-log metrics into the user and bucket metrics (requires --sync-external-nats)")
+	radosGWUsageCmd.Flags().StringVar(&rgwuOpsMetricsSubject, "ops-metrics-subject", "rgw.s3.ops.aggregated.metrics", "NATS subject of the ops-log metrics")
+	// Fault injection flags
+	radosGWUsageCmd.Flags().StringVar(&rgwuAdminAPIFaults, "admin-api-faults", "", "For testing: probabilities of faults injected into the admin API requests, e.g. timeout=0.1,error=0.05,partial=0.2")
+	// Multi-target flags
+	radosGWUsageCmd.Flags().StringVar(&rgwuProbeTargets, "probe-targets", "", "JSON file of RGW clusters scraped on /probe?cluster=<cluster>, one collection cycle per scrape, instead of the collection loop (requires --prometheus)")
+	radosGWUsageCmd.Flags().BoolVar(&rgwuRookDiscovery, "rook-discovery", false, "Probe the Rook CephObjectStores of the Kubernetes cluster on /probe?cluster=<store>, with the admin ops user Rook created (requires --prometheus)")
+	radosGWUsageCmd.Flags().StringVar(&rgwuRookNamespace, "rook-namespace", "", "Namespace of the CephObjectStores of --rook-discovery, all namespaces if empty")
+	radosGWUsageCmd.Flags().DurationVar(&rgwuRookDiscoveryInterval, "rook-discovery-interval", radosgwusage.DefaultRookDiscoveryInterval, "Interval the CephObjectStores are listed again to add and remove probe targets")
+	radosGWUsageCmd.Flags().BoolVar(&rgwuDryRun, "dry-run", false, "Run one collection cycle, print the user, bucket and cluster metrics as JSON on stdout and exit, without NATS KV and Prometheus")
 }
 
 func validateRadosGWUsageConfig(config radosgwusage.RadosGWUsageConfig) {
 	missingParams := false
 
-	if config.AdminURL == "" {
-		fmt.Println("Warning: --admin-url or ADMIN_URL must be set")
-		missingParams = true
-	}
-	if config.AccessKey == "" {
-		fmt.Println("Warning: --access-key or ACCESS_KEY must be set")
-		missingParams = true
-	}
-	if config.SecretKey == "" {
-		fmt.Println("Warning: --secret-key or SECRET_KEY must be set")
+	switch config.Source {
+	case radosgwusage.SourceAdminAPI:
+		if config.AdminURL == "" {
+			fmt.Println("Warning: --admin-url or ADMIN_URL must be set")
+			missingParams = true
+		}
+		if config.AccessKey == "" {
+			fmt.Println("Warning: --access-key or ACCESS_KEY must be set")
+			missingParams = true
+		}
+		if config.SecretKey == "" {
+			fmt.Println("Warning: --secret-key or SECRET_KEY must be set")
+			missingParams = true
+		}
+	case radosgwusage.SourceCLI:
+		if config.RadosGWAdminBinary == "" {
+			fmt.Println("Warning: --radosgw-admin or RADOSGW_ADMIN_BINARY must be set with --source=cli")
+			missingParams = true
+		}
+		if config.AdminAPIFaults != "" {
+			fmt.Println("Warning: --admin-api-faults or ADMIN_API_FAULTS requires --source=admin-api")
+			missingParams = true
+		}
+	default:
+		fmt.Printf("Warning: --source or RGW_USAGE_SOURCE must be %s or %s\n", radosgwusage.SourceAdminAPI, radosgwusage.SourceCLI)
 		missingParams = true
 	}
 	if config.CooldownInterval <= 0 {
@@ -143,6 +372,54 @@ func validateRadosGWUsageConfig(config radosgwusage.RadosGWUsageConfig) {
 		missingParams = true
 	}
 
+	if config.ReshardObjectsPerShard < 0 {
+		fmt.Println("Warning: --reshard-objects-per-shard or RESHARD_OBJECTS_PER_SHARD must not be negative")
+		missingParams = true
+	}
+
+	if config.LargeOmapKeysPerShard < 0 {
+		fmt.Println("Warning: --large-omap-keys-per-shard or LARGE_OMAP_KEYS_PER_SHARD must not be negative")
+		missingParams = true
+	}
+
+	if config.PublicBucketNotify && !config.AuditBucketAccess {
+		fmt.Println("Warning: --public-bucket-notify or PUBLIC_BUCKET_NOTIFY requires --audit-bucket-access")
+		missingParams = true
+	}
+
+	if config.TenantAnomalyNotify {
+		if config.TenantAnomalyHistory <= 0 || config.TenantAnomalyGrowthFactor <= 0 {
+			fmt.Println("Warning: --tenant-anomaly-history and --tenant-anomaly-growth-factor (TENANT_ANOMALY_HISTORY, TENANT_ANOMALY_GROWTH_FACTOR) must be positive")
+			missingParams = true
+		}
+		if config.TenantAnomalyDeletionPercent <= 0 || config.TenantAnomalyDeletionPercent > 100 {
+			fmt.Println("Warning: --tenant-anomaly-deletion-percent or TENANT_ANOMALY_DELETION_PERCENT must be between 1 and 100")
+			missingParams = true
+		}
+		if config.TenantAnomalyMinGiB < 0 {
+			fmt.Println("Warning: --tenant-anomaly-min-gib or TENANT_ANOMALY_MIN_GIB must not be negative")
+			missingParams = true
+		}
+	}
+
+	if config.CompletenessPercent < 0 || config.CompletenessPercent > 100 {
+		fmt.Println("Warning: --completeness-percent or COMPLETENESS_PERCENT must be between 0 and 100")
+		missingParams = true
+	}
+
+	if config.OpsMetricsJoin && !config.SyncExternalNats {
+		fmt.Println("Warning: --ops-metrics-join or OPS_METRICS_JOIN requires --sync-external-nats, the NATS server of the ops-log sidecars")
+		missingParams = true
+	}
+	if _, err := radosgwusage.ParseAdminAPIFaults(config.AdminAPIFaults); err != nil {
+		fmt.Printf("Warning: --admin-api-faults or ADMIN_API_FAULTS: %v\n", err)
+		missingParams = true
+	}
+	if config.OpsMetricsJoin && config.OpsMetricsSubject == "" {
+		fmt.Println("Warning: --ops-metrics-subject or OPS_METRICS_SUBJECT must be set")
+		missingParams = true
+	}
+
 	// Validate sync control configuration
 	if !config.SyncControlNats {
 		fmt.Println("Warning: --sync-control-nats=false is not supported by radosgw-usage yet")
@@ -158,8 +435,76 @@ func validateRadosGWUsageConfig(config radosgwusage.RadosGWUsageConfig) {
 		}
 	}
 
+	// Keys expiring or purged between two cycles would be missing from the metrics
+	cooldown := time.Duration(config.CooldownInterval) * time.Second
+	ttls, err := radosgwusage.ParseKVTTLs(config.KVTTLs)
+	if err != nil {
+		fmt.Printf("Warning: --kv-ttl or KV_TTL: %v\n", err)
+		missingParams = true
+	}
+	for name, ttl := range ttls {
+		if ttl > 0 && ttl <= cooldown {
+			fmt.Printf("Warning: --kv-ttl or KV_TTL of %s must be longer than --cooldown-interval\n", name)
+			missingParams = true
+		}
+	}
+	if config.KVCompactAge < 0 || (config.KVCompactAge > 0 && config.KVCompactAge <= cooldown) {
+		fmt.Println("Warning: --kv-compact-age or KV_COMPACT_AGE must be 0 or longer than --cooldown-interval")
+		missingParams = true
+	}
+	if config.KVCompactAge > 0 && config.KVCompactInterval <= 0 {
+		fmt.Println("Warning: --kv-compact-interval or KV_COMPACT_INTERVAL must be a positive duration")
+		missingParams = true
+	}
+	if config.KVWriteWorkers < 1 {
+		fmt.Println("Warning: --kv-write-workers or KV_WRITE_WORKERS must be positive")
+		missingParams = true
+	}
+
+	if !validateRemoteWriteConfig(config.RemoteWrite, config.Prometheus) {
+		missingParams = true
+	}
+
+	if missingParams {
+		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
+		os.Exit(1)
+	}
+}
+
+// loadProbeTargets validates the multi-target mode and loads its static
+// targets, the source and credentials of the exporter are the defaults of
+// the targets
+func loadProbeTargets(config radosgwusage.RadosGWUsageConfig) map[string]radosgwusage.ProbeTarget {
+	missingParams := false
+	if !config.Prometheus {
+		fmt.Println("Warning: --probe-targets, PROBE_TARGETS, --rook-discovery or ROOK_DISCOVERY requires --prometheus")
+		missingParams = true
+	}
+	if config.DryRun {
+		fmt.Println("Warning: --probe-targets, PROBE_TARGETS, --rook-discovery or ROOK_DISCOVERY cannot be combined with --dry-run")
+		missingParams = true
+	}
+	var targets map[string]radosgwusage.ProbeTarget
+	if config.ProbeTargets != "" {
+		var err error
+		targets, err = radosgwusage.LoadProbeTargets(config.ProbeTargets)
+		if err != nil {
+			fmt.Printf("Warning: --probe-targets or PROBE_TARGETS: %v\n", err)
+			missingParams = true
+		}
+	}
+	if config.RookDiscovery && config.RookDiscoveryInterval <= 0 {
+		fmt.Println("Warning: --rook-discovery-interval or ROOK_DISCOVERY_INTERVAL must be positive")
+		missingParams = true
+	}
+	if _, err := radosgwusage.ParseAdminAPIFaults(config.AdminAPIFaults); err != nil {
+		fmt.Printf("Warning: --admin-api-faults or ADMIN_API_FAULTS: %v\n", err)
+		missingParams = true
+	}
+
 	if missingParams {
 		fmt.Println("One or more required parameters are missing. Please provide them through flags or environment variables.")
 		os.Exit(1)
 	}
+	return targets
 }
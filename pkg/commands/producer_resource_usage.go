@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/resourceusage"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -67,22 +68,23 @@ var resourceUsageCmd = &cobra.Command{
 		event.Msg("configuration_loaded")
 
 		validateResourceUsageConfig(config)
+		publishProducerConfig("resource-usage", config.NatsURL, config.NodeName, config.InstanceID, config)
 
 		resourceusage.StartMonitoring(config)
 	},
 }
 
 func mergeResourceUsageConfigWithEnv(cfg resourceusage.ResourceUsageConfig) resourceusage.ResourceUsageConfig {
-	cfg.NatsURL = getEnv("NATS_URL", cfg.NatsURL)
-	cfg.NatsSubject = getEnv("NATS_SUBJECT", cfg.NatsSubject)
-	cfg.PrometheusPort = getEnvInt("PROMETHEUS_PORT", cfg.PrometheusPort)
-	disksEnv := getEnv("DISKS", "")
+	cfg.NatsURL = telemetry.GetEnv("NATS_URL", cfg.NatsURL)
+	cfg.NatsSubject = telemetry.GetEnv("NATS_SUBJECT", cfg.NatsSubject)
+	cfg.Prometheus, cfg.PrometheusPort = telemetry.MergeMetricsEnv(cfg.Prometheus, cfg.PrometheusPort)
+	disksEnv := telemetry.GetEnv("DISKS", "")
 	if disksEnv != "" {
 		cfg.Disks = strings.Split(disksEnv, ",")
 	}
-	cfg.NodeName = getEnv("NODE_NAME", cfg.NodeName)
-	cfg.InstanceID = getEnv("INSTANCE_ID", cfg.InstanceID)
-	cfg.Interval = getEnvInt("INTERVAL", cfg.Interval)
+	cfg.NodeName = telemetry.GetEnv("NODE_NAME", cfg.NodeName)
+	cfg.InstanceID = telemetry.GetEnv("INSTANCE_ID", cfg.InstanceID)
+	cfg.Interval = telemetry.GetEnvInt("INTERVAL", cfg.Interval)
 
 	return cfg
 }
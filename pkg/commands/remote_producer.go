@@ -5,6 +5,7 @@
 package commands
 
 import (
+	"github.com/cobaltcore-dev/prysm/pkg/debugserver"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,9 @@ var remoteProducerCmd = &cobra.Command{
 }
 
 func init() {
+	remoteProducerCmd.PersistentFlags().IntVar(&debugPort, "debug-port", 0, "Port for the pprof/runtime debug server (0 disables)")
+	remoteProducerCmd.PersistentFlags().StringVar(&debugAddress, "debug-address", debugserver.DefaultAddress, "Address the debug server listens on (all interfaces if empty)")
+	registerPublishConfigFlags(remoteProducerCmd.PersistentFlags())
 	remoteProducerCmd.AddCommand(bucketNotifyCmd)
 	// remoteProducerCmd.AddCommand(metricsCmd)
 	remoteProducerCmd.AddCommand(quotaUsageMonitorCmd)
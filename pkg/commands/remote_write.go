@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+// remoteWriteFlags holds the Prometheus remote_write flags shared by the
+// producers exporting Prometheus metrics
+type remoteWriteFlags struct {
+	url            string
+	interval       int // in seconds
+	externalLabels string
+	headers        string
+}
+
+func (f *remoteWriteFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.url, "remote-write-url", "", "Prometheus remote_write endpoint to push the metrics to, e.g. \"http://mimir:8080/api/v1/push\" (requires --prometheus)")
+	cmd.Flags().IntVar(&f.interval, "remote-write-interval", 30, "Interval in seconds between remote_write pushes")
+	cmd.Flags().StringVar(&f.externalLabels, "remote-write-external-labels", "", "Comma-separated name=value labels added to every pushed series, e.g. \"cluster=edge-1\"")
+	cmd.Flags().StringVar(&f.headers, "remote-write-headers", "", "Comma-separated name=value HTTP headers of the remote_write requests, e.g. \"X-Scope-OrgID=tenant\"")
+}
+
+// remoteWriteConfig merges the flags with the REMOTE_WRITE_* environment
+// variables; invalid labels or headers are reported and exit
+func remoteWriteConfig(f remoteWriteFlags) remotewrite.Config {
+	cfg := remotewrite.Config{
+		URL:        telemetry.GetEnv("REMOTE_WRITE_URL", f.url),
+		Interval:   time.Duration(telemetry.GetEnvInt("REMOTE_WRITE_INTERVAL", f.interval)) * time.Second,
+		MaxRetries: remotewrite.DefaultMaxRetries,
+	}
+
+	var err error
+	if cfg.ExternalLabels, err = remotewrite.ParseLabels(telemetry.GetEnv("REMOTE_WRITE_EXTERNAL_LABELS", f.externalLabels)); err != nil {
+		fmt.Printf("Warning: --remote-write-external-labels or REMOTE_WRITE_EXTERNAL_LABELS: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Headers, err = remotewrite.ParseLabels(telemetry.GetEnv("REMOTE_WRITE_HEADERS", f.headers)); err != nil {
+		fmt.Printf("Warning: --remote-write-headers or REMOTE_WRITE_HEADERS: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// validateRemoteWriteConfig prints what is wrong with the remote_write
+// configuration and tells whether it is valid. Only the metrics collected
+// for the Prometheus endpoint are pushed, it must be enabled.
+func validateRemoteWriteConfig(cfg remotewrite.Config, prometheus bool) bool {
+	if cfg.URL == "" {
+		return true
+	}
+	valid := true
+	if !prometheus {
+		fmt.Println("Warning: --remote-write-url or REMOTE_WRITE_URL requires --prometheus")
+		valid = false
+	}
+	if cfg.Interval <= 0 {
+		fmt.Println("Warning: --remote-write-interval or REMOTE_WRITE_INTERVAL must be positive")
+		valid = false
+	}
+	return valid
+}
+
+// logRemoteWriteConfig adds the remote_write configuration to the
+// configuration_loaded log event, without the headers that may hold secrets
+func logRemoteWriteConfig(event *zerolog.Event, cfg remotewrite.Config) {
+	event.Bool("remote_write_enabled", cfg.URL != "")
+	if cfg.URL != "" {
+		event.Dur("remote_write_interval", cfg.Interval)
+		event.Interface("remote_write_external_labels", cfg.ExternalLabels)
+	}
+}
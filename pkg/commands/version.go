@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+var versionOutput string
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version, commit, build date and features of prysm",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := version.Get()
+		switch versionOutput {
+		case "text":
+			fmt.Fprint(cmd.OutOrStdout(), info)
+		case "json":
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		default:
+			return fmt.Errorf("unknown output %q, expected text or json", versionOutput)
+		}
+		return nil
+	},
+}
+
+func init() {
+	versionCmd.Flags().StringVarP(&versionOutput, "output", "o", "text", "Output format (text, json)")
+}
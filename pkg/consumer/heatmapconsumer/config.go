@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+type HeatmapConsumerConfig struct {
+	NatsURL        string
+	NatsSubject    string
+	QueueGroup     string
+	KVBucket       string
+	RetentionDays  int
+	FlushInterval  int // seconds
+	HTTPPort       int
+	Prometheus     bool
+	PrometheusPort int
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+)
+
+// Timestamp format of RGW ops log entries
+const opsLogTimeLayout = "2006-01-02T15:04:05.999999Z"
+
+// Heatmap counts the operations of an hour per bucket and operation
+type Heatmap struct {
+	Hour  time.Time `json:"hour"`
+	Cells []Cell    `json:"cells"`
+}
+
+// Cell of a heatmap, the operations on a bucket of one kind
+type Cell struct {
+	Bucket        string `json:"bucket"`
+	Operation     string `json:"operation"`
+	Requests      uint64 `json:"requests"`
+	Errors        uint64 `json:"errors"` // responses with status 4xx or 5xx
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+}
+
+type cellKey struct {
+	bucket    string
+	operation string
+}
+
+// cells of one hour
+type cells map[cellKey]*Cell
+
+func (c cells) add(cell Cell) {
+	key := cellKey{cell.Bucket, cell.Operation}
+	current, ok := c[key]
+	if !ok {
+		current = &Cell{Bucket: cell.Bucket, Operation: cell.Operation}
+		c[key] = current
+	}
+	current.Requests += cell.Requests
+	current.Errors += cell.Errors
+	current.BytesSent += cell.BytesSent
+	current.BytesReceived += cell.BytesReceived
+}
+
+// merge adds the cells of c to h, keeping the cells sorted by bucket and
+// operation
+func (c cells) merge(h *Heatmap) {
+	all := cells{}
+	for _, cell := range h.Cells {
+		all.add(cell)
+	}
+	for _, cell := range c {
+		all.add(*cell)
+	}
+
+	h.Cells = make([]Cell, 0, len(all))
+	for _, cell := range all {
+		h.Cells = append(h.Cells, *cell)
+	}
+	sort.Slice(h.Cells, func(i, j int) bool {
+		if h.Cells[i].Bucket != h.Cells[j].Bucket {
+			return h.Cells[i].Bucket < h.Cells[j].Bucket
+		}
+		return h.Cells[i].Operation < h.Cells[j].Operation
+	})
+}
+
+// aggregator counts the ops log entries received since the last flush, per
+// hour
+type aggregator struct {
+	mu      sync.Mutex
+	pending map[time.Time]cells
+}
+
+func newAggregator() *aggregator {
+	return &aggregator{pending: map[time.Time]cells{}}
+}
+
+// add counts entry in the hour of its time, or of received if it has none
+func (a *aggregator) add(entry opslog.S3OperationLog, received time.Time) {
+	eventTime := received
+	if parsed, err := time.Parse(opsLogTimeLayout, entry.Time); err == nil {
+		eventTime = parsed
+	}
+	cell := Cell{Bucket: entry.Bucket, Operation: entry.Operation, Requests: 1}
+	if status, err := strconv.Atoi(entry.HTTPStatus); err == nil && status >= 400 {
+		cell.Errors = 1
+	}
+	if entry.BytesSent > 0 {
+		cell.BytesSent = uint64(entry.BytesSent)
+	}
+	if entry.BytesReceived > 0 {
+		cell.BytesReceived = uint64(entry.BytesReceived)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hour(eventTime).add(cell)
+}
+
+// hour returns the pending cells of the hour of t. The lock must be held.
+func (a *aggregator) hour(t time.Time) cells {
+	hour := t.UTC().Truncate(time.Hour)
+	c, ok := a.pending[hour]
+	if !ok {
+		c = cells{}
+		a.pending[hour] = c
+	}
+	return c
+}
+
+// take returns the pending cells and starts counting anew
+func (a *aggregator) take() map[time.Time]cells {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pending := a.pending
+	a.pending = map[time.Time]cells{}
+	return pending
+}
+
+// restore adds cells that could not be stored back to the pending ones
+func (a *aggregator) restore(hour time.Time, c cells) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pending := a.hour(hour)
+	for _, cell := range c {
+		pending.add(*cell)
+	}
+}
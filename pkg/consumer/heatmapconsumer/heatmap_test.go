@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore keeps the values in memory with their revisions, like a
+// key-value bucket
+type testStore struct {
+	values    map[string][]byte
+	revisions map[string]uint64
+	revision  uint64
+	// Called before every update, to change the value concurrently
+	beforeUpdate func()
+	fail         bool
+}
+
+func newTestStore() *testStore {
+	return &testStore{values: map[string][]byte{}, revisions: map[string]uint64{}}
+}
+
+type testEntry struct {
+	key      string
+	value    []byte
+	revision uint64
+}
+
+func (e testEntry) Bucket() string             { return "test" }
+func (e testEntry) Key() string                { return e.key }
+func (e testEntry) Value() []byte              { return e.value }
+func (e testEntry) Revision() uint64           { return e.revision }
+func (e testEntry) Created() time.Time         { return time.Time{} }
+func (e testEntry) Delta() uint64              { return 0 }
+func (e testEntry) Operation() nats.KeyValueOp { return nats.KeyValuePut }
+
+func (s *testStore) Get(key string) (nats.KeyValueEntry, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, nats.ErrKeyNotFound
+	}
+	return testEntry{key: key, value: value, revision: s.revisions[key]}, nil
+}
+
+func (s *testStore) put(key string, value []byte) uint64 {
+	s.revision++
+	s.values[key], s.revisions[key] = value, s.revision
+	return s.revision
+}
+
+func (s *testStore) Create(key string, value []byte) (uint64, error) {
+	if s.fail {
+		return 0, errors.New("unavailable")
+	}
+	if _, ok := s.values[key]; ok {
+		return 0, nats.ErrKeyExists
+	}
+	return s.put(key, value), nil
+}
+
+func (s *testStore) Update(key string, value []byte, last uint64) (uint64, error) {
+	if s.beforeUpdate != nil {
+		s.beforeUpdate()
+	}
+	if s.revisions[key] != last {
+		return 0, errors.New("wrong last sequence")
+	}
+	return s.put(key, value), nil
+}
+
+var hour = time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+
+func entry(bucket, operation, status string, sent int) opslog.S3OperationLog {
+	return opslog.S3OperationLog{
+		Bucket:     bucket,
+		Operation:  operation,
+		HTTPStatus: status,
+		BytesSent:  sent,
+		Time:       "2025-01-02T03:04:05.123456Z",
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	a := newAggregator()
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	a.add(entry("photos", "get_obj", "404", 10), time.Now())
+	a.add(entry("photos", "put_obj", "200", 0), time.Now())
+	// Without a time, the hour it was received
+	a.add(opslog.S3OperationLog{Bucket: "logs", Operation: "get_obj"}, hour.Add(time.Hour+time.Minute))
+
+	pending := a.take()
+	require.Len(t, pending, 2)
+	assert.Equal(t, Cell{Bucket: "photos", Operation: "get_obj", Requests: 2, Errors: 1, BytesSent: 110}, *pending[hour][cellKey{"photos", "get_obj"}])
+	assert.Equal(t, uint64(1), pending[hour][cellKey{"photos", "put_obj"}].Requests)
+	assert.Equal(t, uint64(1), pending[hour.Add(time.Hour)][cellKey{"logs", "get_obj"}].Requests)
+	assert.Empty(t, a.take())
+}
+
+func TestFlush(t *testing.T) {
+	s := newTestStore()
+	a := newAggregator()
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	flush(s, a)
+	a.add(entry("photos", "get_obj", "200", 50), time.Now())
+	a.add(entry("backups", "put_obj", "500", 0), time.Now())
+	flush(s, a)
+
+	heatmap, _, err := loadHeatmap(s, hour)
+	require.NoError(t, err)
+	assert.Equal(t, Heatmap{Hour: hour, Cells: []Cell{
+		{Bucket: "backups", Operation: "put_obj", Requests: 1, Errors: 1},
+		{Bucket: "photos", Operation: "get_obj", Requests: 2, BytesSent: 150},
+	}}, heatmap)
+}
+
+func TestFlushConcurrentUpdate(t *testing.T) {
+	s := newTestStore()
+	a := newAggregator()
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	flush(s, a)
+
+	// Another replica stores its counts between the read and the update
+	other := cells{}
+	other.add(Cell{Bucket: "photos", Operation: "get_obj", Requests: 5})
+	s.beforeUpdate = func() {
+		s.beforeUpdate = nil
+		require.NoError(t, storeCells(s, hour, other))
+	}
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	flush(s, a)
+
+	heatmap, _, err := loadHeatmap(s, hour)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), heatmap.Cells[0].Requests)
+}
+
+func TestFlushKeepsFailedCells(t *testing.T) {
+	s := newTestStore()
+	s.fail = true
+	a := newAggregator()
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	flush(s, a)
+	require.Empty(t, s.values)
+
+	s.fail = false
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	flush(s, a)
+	heatmap, _, err := loadHeatmap(s, hour)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), heatmap.Cells[0].Requests)
+}
+
+func TestHeatmapHandler(t *testing.T) {
+	s := newTestStore()
+	a := newAggregator()
+	a.add(entry("photos", "get_obj", "200", 100), time.Now())
+	a.add(entry("photos", "put_obj", "200", 0), time.Now())
+	a.add(entry("logs", "get_obj", "200", 0), time.Now())
+	flush(s, a)
+	handler := &heatmapHandler{store: s, now: func() time.Time { return hour.Add(90 * time.Minute) }}
+
+	get := func(query string) (*httptest.ResponseRecorder, []Heatmap) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/heatmap"+query, nil))
+		var heatmaps []Heatmap
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &heatmaps))
+		}
+		return rec, heatmaps
+	}
+
+	rec, heatmaps := get("")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Len(t, heatmaps, 1)
+	assert.Len(t, heatmaps[0].Cells, 3)
+
+	_, heatmaps = get("?bucket=photos&operation=get_obj")
+	require.Len(t, heatmaps, 1)
+	assert.Equal(t, []Cell{{Bucket: "photos", Operation: "get_obj", Requests: 1, BytesSent: 100}}, heatmaps[0].Cells)
+
+	_, heatmaps = get("?bucket=missing")
+	assert.Empty(t, heatmaps)
+	_, heatmaps = get("?to=2025-01-01T00:00:00Z")
+	assert.Empty(t, heatmaps)
+
+	for _, query := range []string{"?from=yesterday", "?from=2025-01-03T00:00:00Z&to=2025-01-02T00:00:00Z", "?from=2024-01-01T00:00:00Z"} {
+		rec, _ := get(query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+func StartHeatmapConsumer(cfg HeatmapConsumerConfig) {
+	if cfg.Prometheus {
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+	}
+
+	nc, err := natsutil.Connect(cfg.NatsURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error connecting to nats")
+	}
+	defer nc.Close()
+
+	kv, err := openStore(nc, cfg.KVBucket, time.Duration(cfg.RetentionDays)*24*time.Hour)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error opening heatmap store")
+	}
+	startHTTPServer(cfg.HTTPPort, kv)
+
+	a := newAggregator()
+	sub, err := subscribe(nc, cfg, a)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error subscribing to nats subject")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(time.Duration(cfg.FlushInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			flush(kv, a)
+		case <-ctx.Done():
+			// Stop receiving, then store what was received
+			if err := sub.Drain(); err != nil {
+				log.Error().Err(err).Msg("error draining nats subscription")
+			}
+			for sub.IsValid() {
+				time.Sleep(100 * time.Millisecond)
+			}
+			flush(kv, a)
+			log.Info().Msg("consumer stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultRange = 24 * time.Hour
+	maxRange     = 31 * 24 * time.Hour
+)
+
+// heatmapHandler serves the heatmaps of the hours from the from to the to
+// parameter (RFC 3339, the last 24 hours by default) as a JSON array,
+// optionally only the cells of the bucket and operation parameters. Hours
+// without operations are left out.
+type heatmapHandler struct {
+	store store
+	now   func() time.Time
+}
+
+func (h *heatmapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	to := h.now()
+	if value := query.Get("to"); value != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "invalid to, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.Add(-defaultRange)
+	if value := query.Get("from"); value != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "invalid from, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+	}
+	if from.After(to) || to.Sub(from) > maxRange {
+		http.Error(w, fmt.Sprintf("from must be before to, at most %s", maxRange), http.StatusBadRequest)
+		return
+	}
+
+	bucket, operation := query.Get("bucket"), query.Get("operation")
+	heatmaps := []Heatmap{}
+	for hour := from.UTC().Truncate(time.Hour); !hour.After(to); hour = hour.Add(time.Hour) {
+		heatmap, _, err := loadHeatmap(h.store, hour)
+		if errors.Is(err, nats.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Str("hour", hourKey(hour)).Msg("error loading heatmap")
+			http.Error(w, "error loading heatmaps", http.StatusInternalServerError)
+			return
+		}
+		heatmap.Cells = filterCells(heatmap.Cells, bucket, operation)
+		if len(heatmap.Cells) > 0 {
+			heatmaps = append(heatmaps, heatmap)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(heatmaps)
+}
+
+// filterCells returns the cells of bucket and operation, all if empty
+func filterCells(all []Cell, bucket, operation string) []Cell {
+	var filtered []Cell
+	for _, cell := range all {
+		if (bucket == "" || cell.Bucket == bucket) && (operation == "" || cell.Operation == operation) {
+			filtered = append(filtered, cell)
+		}
+	}
+	return filtered
+}
+
+// startHTTPServer serves the heatmaps on /heatmap
+func startHTTPServer(port int, s store) {
+	mux := http.NewServeMux()
+	mux.Handle("/heatmap", &heatmapHandler{store: s, now: time.Now})
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Msgf("starting heatmap server on :%d", port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("error starting heatmap server")
+		}
+	}()
+}
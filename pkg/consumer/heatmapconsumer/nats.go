@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// subscribe counts the ops log entries on the configured subject. With a
+// queue group, consumers of the same group share the entries.
+func subscribe(nc *nats.Conn, cfg HeatmapConsumerConfig, a *aggregator) (*nats.Subscription, error) {
+	handler := func(m *nats.Msg) {
+		var entry opslog.S3OperationLog
+		if err := schema.Unmarshal(m, schema.OpsEvent, &entry); err != nil {
+			eventsReceived.WithLabelValues("invalid").Inc()
+			log.Error().Err(err).Str("subject", m.Subject).Msg("error decoding ops log entry")
+			return
+		}
+		eventsReceived.WithLabelValues("decoded").Inc()
+		a.add(entry, time.Now())
+	}
+
+	if cfg.QueueGroup != "" {
+		return nc.QueueSubscribe(cfg.NatsSubject, cfg.QueueGroup, handler)
+	}
+	return nc.Subscribe(cfg.NatsSubject, handler)
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	eventsReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prysm_consumer_heatmap_events_total",
+			Help: "Ops log entries received from NATS, by whether they could be decoded",
+		},
+		[]string{"result"},
+	)
+	flushErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prysm_consumer_heatmap_flush_errors_total",
+			Help: "Hourly heatmaps that could not be stored, their counts are retried on the next flush",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(eventsReceived, flushErrors)
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heatmapconsumer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// Attempts to update the heatmap of an hour that other replicas of the
+// consumer update at the same time
+const maxUpdateAttempts = 5
+
+// store is the part of nats.KeyValue the heatmaps are stored with, one key
+// per hour
+type store interface {
+	Get(key string) (nats.KeyValueEntry, error)
+	Create(key string, value []byte) (uint64, error)
+	Update(key string, value []byte, last uint64) (uint64, error)
+}
+
+func openStore(nc *nats.Conn, bucket string, retention time.Duration) (nats.KeyValue, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize JetStream: %w", err)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "Hourly S3 access heatmaps per bucket and operation",
+			TTL:         retention,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+	return kv, nil
+}
+
+// hourKey is the key of the heatmap of an hour, e.g. 2025-01-02T15
+func hourKey(hour time.Time) string {
+	return hour.UTC().Format("2006-01-02T15")
+}
+
+// loadHeatmap returns the heatmap of hour, ErrKeyNotFound if nothing was
+// stored for it
+func loadHeatmap(s store, hour time.Time) (Heatmap, uint64, error) {
+	entry, err := s.Get(hourKey(hour))
+	if err != nil {
+		return Heatmap{}, 0, err
+	}
+	var heatmap Heatmap
+	if err := json.Unmarshal(entry.Value(), &heatmap); err != nil {
+		return Heatmap{}, 0, fmt.Errorf("invalid heatmap %s: %w", entry.Key(), err)
+	}
+	return heatmap, entry.Revision(), nil
+}
+
+// storeCells adds the cells of hour to its stored heatmap. Replicas in the
+// same queue group update the same hours, so the update only succeeds on the
+// revision it read and is retried on conflicts.
+func storeCells(s store, hour time.Time, c cells) error {
+	var err error
+	for range maxUpdateAttempts {
+		heatmap, revision, loadErr := loadHeatmap(s, hour)
+		if loadErr != nil && !errors.Is(loadErr, nats.ErrKeyNotFound) {
+			return loadErr
+		}
+		heatmap.Hour = hour
+		c.merge(&heatmap)
+
+		var data []byte
+		if data, err = json.Marshal(heatmap); err != nil {
+			return err
+		}
+		if errors.Is(loadErr, nats.ErrKeyNotFound) {
+			_, err = s.Create(hourKey(hour), data)
+		} else {
+			_, err = s.Update(hourKey(hour), data, revision)
+		}
+		if err == nil {
+			return nil
+		}
+		log.Debug().Err(err).Str("hour", hourKey(hour)).Msg("heatmap changed concurrently, retrying")
+	}
+	return fmt.Errorf("failed to update heatmap %s: %w", hourKey(hour), err)
+}
+
+// flush stores the cells counted since the last flush. Cells of hours that
+// could not be stored are kept for the next flush.
+func flush(s store, a *aggregator) {
+	for hour, c := range a.take() {
+		if err := storeCells(s, hour, c); err != nil {
+			flushErrors.Inc()
+			log.Error().Err(err).Msg("error storing heatmap")
+			a.restore(hour, c)
+		}
+	}
+}
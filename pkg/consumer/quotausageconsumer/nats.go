@@ -5,14 +5,14 @@
 package quotausageconsumer
 
 import (
-	"encoding/json"
-
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
 
 func StartNatsConsumer(cfg QuotaUsageConsumerConfig) {
-	nc, err := nats.Connect(cfg.NatsURL)
+	nc, err := natsutil.Connect(cfg.NatsURL)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error connecting to nats")
 	}
@@ -20,7 +20,7 @@ func StartNatsConsumer(cfg QuotaUsageConsumerConfig) {
 
 	_, err = nc.Subscribe(cfg.NatsSubject, func(m *nats.Msg) {
 		var quotas []QuotaUsage
-		err := json.Unmarshal(m.Data, &quotas)
+		err := schema.Unmarshal(m, schema.QuotaUsage, &quotas)
 		if err != nil {
 			log.Error().Err(err).Msg("error unmarshalling quotas")
 			return
@@ -4,15 +4,7 @@
 
 package quotausageconsumer
 
-import (
-	"fmt"
-	"net/http"
-
-	"github.com/rs/zerolog/log"
-
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-)
+import "github.com/prometheus/client_golang/prometheus"
 
 var (
 	quotaUsageGaugeVec = prometheus.NewGaugeVec(
@@ -46,14 +38,3 @@ func PublishToPrometheus(quotas []QuotaUsage, cfg QuotaUsageConsumerConfig) {
 		}
 	}
 }
-
-func StartPrometheusServer(port int) {
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
-		}
-	}()
-}
@@ -4,6 +4,8 @@
 
 package quotausageconsumer
 
+import "github.com/cobaltcore-dev/prysm/pkg/telemetry"
+
 type QuotaUsage struct {
 	UserID         string `json:"user_id"`
 	TotalQuota     uint64 `json:"total_quota"`
@@ -16,7 +18,7 @@ type QuotaUsage struct {
 func StartQuotaUsageConsumer(cfg QuotaUsageConsumerConfig) {
 
 	if cfg.Prometheus {
-		StartPrometheusServer(cfg.PrometheusPort)
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
 	}
 
 	StartNatsConsumer(cfg)
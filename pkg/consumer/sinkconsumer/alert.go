@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/cephhealth"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/quotausagemonitor"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+// Severities of the alerts
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// errNotAlert is returned for the events of the events source that need no
+// action, e.g. a disk back to healthy or a completed sync
+var errNotAlert = errors.New("not an alert")
+
+// Alert is an event of a producer that needs action, e.g. a failing disk or
+// a user over quota, in the same shape whatever the producer
+type Alert struct {
+	Key        string            `json:"key"`      // Same for the repeats of the alert, e.g. to deduplicate tickets
+	Producer   string            `json:"producer"` // e.g. disk-health-metrics
+	Event      string            `json:"event"`    // e.g. failure_risk, check_raised, quota_exceeded
+	Severity   string            `json:"severity"` // warning or critical
+	Summary    string            `json:"summary"`
+	Node       string            `json:"node,omitempty"`
+	InstanceID string            `json:"instance_id,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"` // What the alert is about, e.g. device or user
+	Time       time.Time         `json:"time"`
+}
+
+// newAlert fills the key of the alert from its producer, event, node and
+// labels
+func newAlert(alert Alert) Alert {
+	parts := []string{alert.Producer, alert.Event, alert.Node}
+	for _, name := range []string{"device", "check", "user", "ids"} {
+		if value, ok := alert.Labels[name]; ok {
+			parts = append(parts, value)
+		}
+	}
+	alert.Key = strings.Join(parts, "/")
+	return alert
+}
+
+// decodeAlerts decodes the alerts of a message of the events source by the
+// schema named in its header
+func decodeAlerts(msg *nats.Msg, received time.Time) ([]Alert, error) {
+	header := msg.Header.Get(schema.Header)
+	name, _, _ := strings.Cut(header, "/v")
+
+	switch name {
+	case schema.DiskEvent.Name:
+		var event diskhealthmetrics.NatsEvent
+		if err := schema.Unmarshal(msg, schema.DiskEvent, &event); err != nil {
+			return nil, fmt.Errorf("invalid disk event: %w", err)
+		}
+		if event.Severity != SeverityWarning && event.Severity != SeverityCritical {
+			return nil, errNotAlert
+		}
+		labels := map[string]string{"device": event.Device}
+		for name, value := range map[string]string{"zone": event.Zone, "rack": event.Rack, "enclosure": event.Enclosure, "slot": event.Slot} {
+			if value != "" {
+				labels[name] = value
+			}
+		}
+		return []Alert{newAlert(Alert{
+			Producer: "disk-health-metrics", Event: event.EventType, Severity: event.Severity, Summary: event.Message,
+			Node: event.NodeName, InstanceID: event.InstanceID, Labels: labels, Time: received,
+		})}, nil
+
+	case schema.DiskChangeEvent.Name:
+		var event diskhealthmetrics.DiskChangeEvent
+		if err := schema.Unmarshal(msg, schema.DiskChangeEvent, &event); err != nil {
+			return nil, fmt.Errorf("invalid disk change event: %w", err)
+		}
+		if event.Severity != SeverityWarning && event.Severity != SeverityCritical {
+			return nil, errNotAlert
+		}
+		labels := map[string]string{"device": event.Device}
+		if event.Attribute != "" {
+			labels["attribute"] = event.Attribute
+		}
+		if event.OSDID != "" {
+			labels["osd_id"] = event.OSDID
+		}
+		return []Alert{newAlert(Alert{
+			Producer: "disk-health-metrics", Event: event.EventType, Severity: event.Severity, Summary: event.Message,
+			Node: event.NodeName, InstanceID: event.InstanceID, Labels: labels, Time: event.Timestamp,
+		})}, nil
+
+	case schema.CephHealthEvent.Name:
+		var event cephhealth.HealthEvent
+		if err := schema.Unmarshal(msg, schema.CephHealthEvent, &event); err != nil {
+			return nil, fmt.Errorf("invalid ceph health event: %w", err)
+		}
+		var severity string
+		switch event.Severity {
+		case cephhealth.HealthWarn:
+			severity = SeverityWarning
+		case cephhealth.HealthErr:
+			severity = SeverityCritical
+		default:
+			return nil, errNotAlert
+		}
+		labels := map[string]string{"fsid": event.FSID}
+		if event.Check != "" {
+			labels["check"] = event.Check
+		}
+		return []Alert{newAlert(Alert{
+			Producer: "ceph-health", Event: event.EventType, Severity: severity, Summary: event.Message,
+			Node: event.NodeName, InstanceID: event.InstanceID, Labels: labels, Time: event.Timestamp,
+		})}, nil
+
+	case schema.RadosGWUsageEvent.Name:
+		var event radosgwusage.Event
+		if err := schema.Unmarshal(msg, schema.RadosGWUsageEvent, &event); err != nil {
+			return nil, fmt.Errorf("invalid radosgw-usage event: %w", err)
+		}
+		// Detected findings and failures need action, the progress of the syncs not
+		if event.Status != "detected" && event.Status != "failed" {
+			return nil, errNotAlert
+		}
+		labels := map[string]string{"ids": strings.Join(event.IDs, ",")}
+		for name, value := range event.Metadata {
+			labels[name] = value
+		}
+		return []Alert{newAlert(Alert{
+			Producer: "radosgw-usage", Event: event.Event, Severity: SeverityWarning,
+			Summary: fmt.Sprintf("%s %s: %s", event.Event, event.Status, labels["ids"]), Labels: labels, Time: received,
+		})}, nil
+
+	case schema.QuotaUsage.Name:
+		var quotas []quotausagemonitor.QuotaUsage
+		if err := schema.Unmarshal(msg, schema.QuotaUsage, &quotas); err != nil {
+			return nil, fmt.Errorf("invalid quota usage: %w", err)
+		}
+		// The monitor only publishes the users above its usage threshold
+		var alerts []Alert
+		for _, quota := range quotas {
+			event, severity := "quota_warning", SeverityWarning
+			if quota.RemainingQuota == 0 {
+				event, severity = "quota_exceeded", SeverityCritical
+			}
+			alerts = append(alerts, newAlert(Alert{
+				Producer: "quota-usage-monitor", Event: event, Severity: severity,
+				Summary: fmt.Sprintf("user %s uses %d of %d bytes of its quota", quota.UserID, quota.UsedQuota, quota.TotalQuota),
+				Node:    quota.NodeName, InstanceID: quota.InstanceID,
+				Labels: map[string]string{"user": quota.UserID, "used_bytes": strconv.FormatUint(quota.UsedQuota, 10), "quota_bytes": strconv.FormatUint(quota.TotalQuota, 10)},
+				Time:   received,
+			}))
+		}
+		if len(alerts) == 0 {
+			return nil, errNotAlert
+		}
+		return alerts, nil
+
+	case "":
+		return nil, fmt.Errorf("event without %s header", schema.Header)
+	default:
+		return nil, fmt.Errorf("%w: %s is not an event", schema.ErrIncompatible, header)
+	}
+}
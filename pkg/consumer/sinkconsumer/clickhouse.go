@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Time format of the event_time column, parsed by ClickHouse as DateTime64
+const clickHouseTimeLayout = "2006-01-02 15:04:05.000"
+
+// clickHouseSink inserts the records into a ClickHouse table over the HTTP
+// interface. Each row has the fields of the message and source, subject
+// and event_time; fields without a column are skipped.
+type clickHouseSink struct {
+	url      string
+	user     string
+	password string
+}
+
+func newClickHouseSink(baseURL, table, user, password string) *clickHouseSink {
+	query := url.Values{}
+	query.Set("query", "INSERT INTO "+table+" FORMAT JSONEachRow")
+	query.Set("input_format_skip_unknown_fields", "1")
+	return &clickHouseSink{
+		url:      strings.TrimSuffix(baseURL, "/") + "/?" + query.Encode(),
+		user:     user,
+		password: password,
+	}
+}
+
+func (s *clickHouseSink) Name() string {
+	return "clickhouse"
+}
+
+func (s *clickHouseSink) Write(ctx context.Context, records []Record) error {
+	var body bytes.Buffer
+	for _, record := range records {
+		row, err := clickHouseRow(record)
+		if err != nil {
+			return err
+		}
+		body.Write(row)
+		body.WriteByte('\n')
+	}
+
+	headers := map[string]string{"Content-Type": "application/x-ndjson"}
+	if s.user != "" {
+		headers["X-ClickHouse-User"] = s.user
+		headers["X-ClickHouse-Key"] = s.password
+	}
+	return post(ctx, s.url, headers, body.Bytes())
+}
+
+// clickHouseRow flattens a record into a JSONEachRow row. Messages that
+// are not JSON objects are stored in the data column.
+func clickHouseRow(record Record) ([]byte, error) {
+	row := map[string]any{}
+	decoder := json.NewDecoder(bytes.NewReader(record.Data))
+	decoder.UseNumber() // keep large integers exact
+	if err := decoder.Decode(&row); err != nil || row == nil {
+		row = map[string]any{"data": string(record.Data)}
+	}
+	row["source"] = record.Source
+	row["subject"] = record.Subject
+	row["event_time"] = record.Time.UTC().Format(clickHouseTimeLayout)
+	return json.Marshal(row)
+}
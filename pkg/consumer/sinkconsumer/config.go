@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import "time"
+
+// Sources the consumer subscribes to, named after the producers publishing them
+const (
+	SourceOpsLog       = "ops-log"
+	SourceRadosGWUsage = "radosgw-usage"
+	SourceEvents       = "events" // the alert-type events of all producers
+)
+
+type SinkConsumerConfig struct {
+	Source         string
+	NatsURL        string
+	NatsSubject    string // comma-separated subjects
+	QueueGroup     string
+	BatchSize      int
+	FlushInterval  int // seconds
+	Prometheus     bool
+	PrometheusPort int
+
+	// Sinks, each enabled by its URL or bucket
+	RemoteWriteURL     string
+	LokiURL            string
+	LokiTenant         string
+	ClickHouseURL      string
+	ClickHouseTable    string
+	ClickHouseUser     string
+	ClickHousePassword string
+	S3Endpoint         string
+	S3Bucket           string
+	S3Prefix           string
+	S3Region           string
+	S3AccessKey        string
+	S3SecretKey        string
+
+	// Webhook of the alerts of the events source, e.g. a ticketing system
+	WebhookURL            string
+	WebhookHeaders        string        // Comma-separated name=value headers, e.g. of the authorization
+	WebhookTemplate       string        // File of the text/template of the payload, the alert as JSON if empty
+	WebhookSecret         string        // Key of the HMAC-SHA256 signature of the payloads, unsigned if empty
+	WebhookRepeatInterval time.Duration // Repeats of an alert within are not posted again (0 posts all)
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lokiSink pushes the records as log lines to Loki, one stream per source
+// and subject. Labels stay few, the fields of the message are in the line.
+type lokiSink struct {
+	url    string
+	tenant string
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func newLokiSink(baseURL, tenant string) *lokiSink {
+	return &lokiSink{url: strings.TrimSuffix(baseURL, "/") + "/loki/api/v1/push", tenant: tenant}
+}
+
+func (s *lokiSink) Name() string {
+	return "loki"
+}
+
+func (s *lokiSink) Write(ctx context.Context, records []Record) error {
+	sorted := append([]Record(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	streams := map[string]*lokiStream{}
+	var keys []string
+	for _, record := range sorted {
+		key := record.Source + "\xff" + record.Subject
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{"source": record.Source, "subject": record.Subject}}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(record.Time.UnixNano(), 10),
+			string(record.Data),
+		})
+	}
+
+	push := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range keys {
+		push.Streams = append(push.Streams, streams[key])
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if s.tenant != "" {
+		headers["X-Scope-OrgID"] = s.tenant
+	}
+	return post(ctx, s.url, headers, body)
+}
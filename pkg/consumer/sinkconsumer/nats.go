@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// subscribe decodes the messages on the configured subjects into records.
+// With a queue group, consumers of the same group share the messages.
+func subscribe(nc *nats.Conn, cfg SinkConsumerConfig, records chan<- Record) ([]*nats.Subscription, error) {
+	handler := func(m *nats.Msg) {
+		record, err := decodeRecord(cfg.Source, m, time.Now())
+		if errors.Is(err, errNotAlert) {
+			recordsReceived.WithLabelValues(cfg.Source, "skipped").Inc()
+			return
+		}
+		if err != nil {
+			recordsReceived.WithLabelValues(cfg.Source, "invalid").Inc()
+			log.Error().Err(err).Str("subject", m.Subject).Msg("error decoding message")
+			return
+		}
+		recordsReceived.WithLabelValues(cfg.Source, "decoded").Inc()
+		records <- record
+	}
+
+	var subs []*nats.Subscription
+	for _, subject := range strings.Split(cfg.NatsSubject, ",") {
+		var sub *nats.Subscription
+		var err error
+		if cfg.QueueGroup != "" {
+			sub, err = nc.QueueSubscribe(strings.TrimSpace(subject), cfg.QueueGroup, handler)
+		} else {
+			sub, err = nc.Subscribe(strings.TrimSpace(subject), handler)
+		}
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
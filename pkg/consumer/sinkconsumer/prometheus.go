@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	recordsReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prysm_consumer_records_received_total",
+			Help: "Messages received from NATS, by source and whether they were decoded, invalid or skipped as no alert",
+		},
+		[]string{"source", "result"},
+	)
+	sinkRecordsWritten = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prysm_consumer_sink_records_written_total",
+			Help: "Records written to a sink",
+		},
+		[]string{"sink"},
+	)
+	sinkRecordsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prysm_consumer_sink_records_dropped_total",
+			Help: "Records dropped because the sink failed to write their batch",
+		},
+		[]string{"sink"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(recordsReceived, sinkRecordsWritten, sinkRecordsDropped)
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+// Timestamp format of RGW ops log entries
+const opsLogTimeLayout = "2006-01-02T15:04:05.999999Z"
+
+// Record is a message received from NATS, decoded according to its source
+type Record struct {
+	Source  string
+	Subject string
+	Time    time.Time
+	Data    json.RawMessage // the message as published, as JSON
+	Samples []Sample        // counter increments derived from the message
+	Alerts  []Alert         // alerts of the message, of the events source only
+}
+
+// Sample increments the counter Name with Labels by Value
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// decodeRecord decodes a message of the given source with the schema of the
+// source. The record time is the time of the event if the message has one,
+// otherwise received.
+func decodeRecord(source string, msg *nats.Msg, received time.Time) (Record, error) {
+	data, err := schema.ToJSON(msg)
+	if err != nil {
+		return Record{}, err
+	}
+	record := Record{Source: source, Subject: msg.Subject, Time: received, Data: data}
+
+	switch source {
+	case SourceOpsLog:
+		var entry opslog.S3OperationLog
+		if err := schema.Unmarshal(msg, schema.OpsEvent, &entry); err != nil {
+			return Record{}, fmt.Errorf("invalid ops log entry: %w", err)
+		}
+		if eventTime, err := time.Parse(opsLogTimeLayout, entry.Time); err == nil {
+			record.Time = eventTime
+		}
+		labels := map[string]string{
+			"user":        entry.User,
+			"bucket":      entry.Bucket,
+			"operation":   entry.Operation,
+			"http_status": entry.HTTPStatus,
+		}
+		record.Samples = []Sample{
+			{Name: "prysm_consumer_ops_requests_total", Labels: labels, Value: 1},
+			{Name: "prysm_consumer_ops_bytes_sent_total", Labels: labels, Value: float64(entry.BytesSent)},
+			{Name: "prysm_consumer_ops_bytes_received_total", Labels: labels, Value: float64(entry.BytesReceived)},
+		}
+
+	case SourceRadosGWUsage:
+		var event radosgwusage.Event
+		if err := schema.Unmarshal(msg, schema.RadosGWUsageEvent, &event); err != nil {
+			return Record{}, fmt.Errorf("invalid radosgw-usage event: %w", err)
+		}
+		record.Samples = []Sample{{
+			Name:   "prysm_consumer_radosgw_events_total",
+			Labels: map[string]string{"event": event.Event, "status": event.Status},
+			Value:  1,
+		}}
+
+	case SourceEvents:
+		alerts, err := decodeAlerts(msg, received)
+		if err != nil {
+			return Record{}, err
+		}
+		record.Alerts = alerts
+		for _, alert := range alerts {
+			record.Samples = append(record.Samples, Sample{
+				Name:   "prysm_consumer_alerts_total",
+				Labels: map[string]string{"producer": alert.Producer, "event": alert.Event, "severity": alert.Severity},
+				Value:  1,
+			})
+		}
+
+	default:
+		return Record{}, fmt.Errorf("unknown source %q", source)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRecordOpsLog(t *testing.T) {
+	received := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := []byte(`{"bucket":"photos","time":"2025-01-02T03:04:00.123456Z","user":"alice$tenant","operation":"get_obj","http_status":"200","bytes_sent":1024,"bytes_received":16}`)
+
+	record, err := decodeRecord(SourceOpsLog, &nats.Msg{Subject: "rgw.s3.ops", Data: data}, received)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 0, 123456000, time.UTC), record.Time)
+	assert.JSONEq(t, string(data), string(record.Data))
+	require.Len(t, record.Samples, 3)
+	labels := map[string]string{"user": "alice$tenant", "bucket": "photos", "operation": "get_obj", "http_status": "200"}
+	assert.Equal(t, Sample{Name: "prysm_consumer_ops_requests_total", Labels: labels, Value: 1}, record.Samples[0])
+	assert.Equal(t, 1024.0, record.Samples[1].Value)
+	assert.Equal(t, 16.0, record.Samples[2].Value)
+}
+
+func TestDecodeRecordOpsLogWithoutTime(t *testing.T) {
+	received := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	record, err := decodeRecord(SourceOpsLog, &nats.Msg{Subject: "rgw.s3.ops", Data: []byte(`{"bucket":"photos"}`)}, received)
+	require.NoError(t, err)
+	assert.Equal(t, received, record.Time)
+}
+
+func TestDecodeRecordRadosGWUsage(t *testing.T) {
+	data := []byte(`{"event":"reshard_recommended","status":"detected","ids":["photos"]}`)
+
+	record, err := decodeRecord(SourceRadosGWUsage, &nats.Msg{Subject: "notifications", Data: data}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []Sample{{
+		Name:   "prysm_consumer_radosgw_events_total",
+		Labels: map[string]string{"event": "reshard_recommended", "status": "detected"},
+		Value:  1,
+	}}, record.Samples)
+}
+
+func TestDecodeRecordMsgPack(t *testing.T) {
+	schema.Configure(schema.MsgPack)
+	t.Cleanup(func() { schema.Configure(schema.JSON) })
+	msg, err := schema.NewMsg("rgw.s3.ops", schema.OpsEvent, map[string]any{"bucket": "photos", "bytes_sent": 1024})
+	require.NoError(t, err)
+
+	record, err := decodeRecord(SourceOpsLog, msg, time.Now())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bucket":"photos","bytes_sent":1024}`, string(record.Data))
+	require.Len(t, record.Samples, 3)
+	assert.Equal(t, 1024.0, record.Samples[1].Value)
+}
+
+func TestDecodeRecordInvalid(t *testing.T) {
+	_, err := decodeRecord(SourceOpsLog, &nats.Msg{Subject: "rgw.s3.ops", Data: []byte(`not json`)}, time.Now())
+	assert.Error(t, err)
+
+	metrics := &nats.Msg{Subject: "rgw.s3.ops", Header: nats.Header{}, Data: []byte(`{}`)}
+	metrics.Header.Set(schema.Header, schema.OpsMetrics.String())
+	_, err = decodeRecord(SourceOpsLog, metrics, time.Now())
+	assert.ErrorIs(t, err, schema.ErrIncompatible)
+
+	_, err = decodeRecord("unknown", &nats.Msg{Subject: "subject", Data: []byte(`{}`)}, time.Now())
+	assert.Error(t, err)
+}
+
+// eventMsg is a message of an event with the schema header
+func eventMsg(t *testing.T, subject string, s schema.Schema, v any) *nats.Msg {
+	msg, err := schema.NewMsg(subject, s, v)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestDecodeRecordEvents(t *testing.T) {
+	received := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := eventMsg(t, "osd.disk.health", schema.DiskEvent, map[string]any{
+		"node_name": "node-1", "device": "/dev/sda", "event_type": "failure_risk", "severity": "critical", "message": "failure risk critical", "rack": "r1",
+	})
+
+	record, err := decodeRecord(SourceEvents, msg, received)
+	require.NoError(t, err)
+	require.Len(t, record.Alerts, 1)
+	assert.Equal(t, Alert{
+		Key: "disk-health-metrics/failure_risk/node-1//dev/sda", Producer: "disk-health-metrics", Event: "failure_risk",
+		Severity: SeverityCritical, Summary: "failure risk critical", Node: "node-1",
+		Labels: map[string]string{"device": "/dev/sda", "rack": "r1"}, Time: received,
+	}, record.Alerts[0])
+	assert.Equal(t, []Sample{{
+		Name:   "prysm_consumer_alerts_total",
+		Labels: map[string]string{"producer": "disk-health-metrics", "event": "failure_risk", "severity": SeverityCritical},
+		Value:  1,
+	}}, record.Samples)
+}
+
+func TestDecodeAlerts(t *testing.T) {
+	now := time.Now()
+
+	alerts, err := decodeAlerts(eventMsg(t, "ceph.health.events", schema.CephHealthEvent, map[string]any{
+		"fsid": "f1", "event_type": "check_raised", "check": "OSD_DOWN", "severity": "HEALTH_WARN", "message": "1 osds down",
+	}), now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "ceph-health/check_raised//OSD_DOWN", alerts[0].Key)
+	assert.Equal(t, SeverityWarning, alerts[0].Severity)
+
+	alerts, err = decodeAlerts(eventMsg(t, "user.quotas.usage", schema.QuotaUsage, []map[string]any{
+		{"user_id": "alice", "total_quota": 100, "used_quota": 95, "remaining_quota": 5},
+		{"user_id": "bob", "total_quota": 100, "used_quota": 120, "remaining_quota": 0},
+	}), now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "quota_warning", alerts[0].Event)
+	assert.Equal(t, "quota_exceeded", alerts[1].Event)
+	assert.Equal(t, SeverityCritical, alerts[1].Severity)
+
+	alerts, err = decodeAlerts(eventMsg(t, "notifications", schema.RadosGWUsageEvent, map[string]any{
+		"event": "bucket_public", "status": "detected", "ids": []string{"alice/photos"},
+	}), now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "radosgw-usage/bucket_public//alice/photos", alerts[0].Key)
+
+	for name, msg := range map[string]*nats.Msg{
+		"healthy disk":     eventMsg(t, "osd.disk.health", schema.DiskEvent, map[string]any{"device": "/dev/sda", "severity": "info"}),
+		"cleared check":    eventMsg(t, "ceph.health.events", schema.CephHealthEvent, map[string]any{"event_type": "check_cleared", "severity": "HEALTH_OK"}),
+		"sync completed":   eventMsg(t, "notifications", schema.RadosGWUsageEvent, map[string]any{"event": "sync_users", "status": "completed"}),
+		"no user on quota": eventMsg(t, "user.quotas.usage", schema.QuotaUsage, []map[string]any{}),
+	} {
+		_, err := decodeAlerts(msg, now)
+		assert.ErrorIs(t, err, errNotAlert, name)
+	}
+
+	_, err = decodeAlerts(eventMsg(t, "osd.perf", schema.OSDPerf, map[string]any{}), now)
+	assert.ErrorIs(t, err, schema.ErrIncompatible)
+	_, err = decodeAlerts(&nats.Msg{Subject: "notifications", Data: []byte(`{}`)}, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+)
+
+// remoteWriteSink turns the samples of the records into counters and sends
+// them to a Prometheus remote_write endpoint. Counters are cumulative since
+// the consumer started, every batch sends all of them.
+type remoteWriteSink struct {
+	client *remotewrite.Client
+	series map[string]*remotewrite.Series // by labels, __name__ included
+}
+
+func newRemoteWriteSink(url string) *remoteWriteSink {
+	return &remoteWriteSink{
+		client: remotewrite.NewClient(remotewrite.Config{URL: url, MaxRetries: remotewrite.DefaultMaxRetries}),
+		series: map[string]*remotewrite.Series{},
+	}
+}
+
+func (s *remoteWriteSink) Name() string {
+	return "remote-write"
+}
+
+func (s *remoteWriteSink) Write(ctx context.Context, records []Record) error {
+	for _, record := range records {
+		for _, sample := range record.Samples {
+			s.add(sample)
+		}
+	}
+	if len(s.series) == 0 {
+		return nil
+	}
+	return s.client.Write(ctx, s.snapshot(time.Now().UnixMilli()))
+}
+
+func (s *remoteWriteSink) add(sample Sample) {
+	labels := []remotewrite.Label{{Name: "__name__", Value: sample.Name}}
+	for name, value := range sample.Labels {
+		labels = append(labels, remotewrite.Label{Name: name, Value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+
+	var key strings.Builder
+	for _, label := range labels {
+		key.WriteString(label.Name + "\xff" + label.Value + "\xff")
+	}
+	series, ok := s.series[key.String()]
+	if !ok {
+		series = &remotewrite.Series{Labels: labels}
+		s.series[key.String()] = series
+	}
+	series.Value += sample.Value
+}
+
+// snapshot returns the counters in a stable order, one sample per series at
+// timestamp (milliseconds)
+func (s *remoteWriteSink) snapshot(timestamp int64) []remotewrite.Series {
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	series := make([]remotewrite.Series, 0, len(keys))
+	for _, key := range keys {
+		series = append(series, remotewrite.Series{Labels: s.series[key].Labels, Value: s.series[key].Value, Timestamp: timestamp})
+	}
+	return series
+}
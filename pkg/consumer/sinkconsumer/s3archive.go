@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// s3ArchiveSink archives every batch as a gzipped JSON lines object,
+// partitioned by source and hour:
+// <prefix>/<source>/2006/01/02/15/<unix nanoseconds>-<hostname>.ndjson.gz
+type s3ArchiveSink struct {
+	client   *s3.S3
+	bucket   string
+	prefix   string
+	source   string
+	hostname string
+}
+
+func newS3ArchiveSink(cfg SinkConsumerConfig) (*s3ArchiveSink, error) {
+	awsConfig := aws.NewConfig().WithRegion(cfg.S3Region).WithS3ForcePathStyle(true)
+	if cfg.S3Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(cfg.S3Endpoint)
+	}
+	// Without keys the default credential chain applies
+	if cfg.S3AccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(cfg.S3AccessKey, cfg.S3SecretKey, ""))
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &s3ArchiveSink{
+		client:   s3.New(sess),
+		bucket:   cfg.S3Bucket,
+		prefix:   cfg.S3Prefix,
+		source:   cfg.Source,
+		hostname: hostname,
+	}, nil
+}
+
+func (s *s3ArchiveSink) Name() string {
+	return "s3-archive"
+}
+
+func (s *s3ArchiveSink) Write(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	for _, record := range records {
+		zw.Write(record.Data)
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	key := path.Join(s.prefix, s.source, now.Format("2006/01/02/15"),
+		fmt.Sprintf("%d-%s.ndjson.gz", now.UnixNano(), s.hostname))
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(body.Bytes()),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return err
+}
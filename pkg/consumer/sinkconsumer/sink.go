@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Sink receives the records in batches. A batch the sink fails to write is
+// counted and dropped, the consumer doesn't retry it.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, records []Record) error
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// newSinks creates the sinks enabled in the configuration
+func newSinks(cfg SinkConsumerConfig) ([]Sink, error) {
+	var sinks []Sink
+	if cfg.RemoteWriteURL != "" {
+		sinks = append(sinks, newRemoteWriteSink(cfg.RemoteWriteURL))
+	}
+	if cfg.LokiURL != "" {
+		sinks = append(sinks, newLokiSink(cfg.LokiURL, cfg.LokiTenant))
+	}
+	if cfg.ClickHouseURL != "" {
+		sinks = append(sinks, newClickHouseSink(cfg.ClickHouseURL, cfg.ClickHouseTable, cfg.ClickHouseUser, cfg.ClickHousePassword))
+	}
+	if cfg.S3Bucket != "" {
+		sink, err := newS3ArchiveSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.WebhookURL != "" {
+		sink, err := newWebhookSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// post sends body to url and fails unless the response status is 2xx
+func post(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// capture serves one request and keeps it for the test
+func capture(t *testing.T, status int) (*httptest.Server, *http.Request, *[]byte) {
+	t.Helper()
+	var req http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = *r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &req, &body
+}
+
+func testRecords() []Record {
+	return []Record{
+		{
+			Source:  SourceOpsLog,
+			Subject: "rgw.s3.ops",
+			Time:    time.Unix(20, 0),
+			Data:    json.RawMessage(`{"bucket":"b","bytes_sent":12345678901234567}`),
+			Samples: []Sample{{Name: "requests_total", Labels: map[string]string{"bucket": "b"}, Value: 1}},
+		},
+		{
+			Source:  SourceOpsLog,
+			Subject: "rgw.s3.ops",
+			Time:    time.Unix(10, 0),
+			Data:    json.RawMessage(`{"bucket":"b"}`),
+			Samples: []Sample{{Name: "requests_total", Labels: map[string]string{"bucket": "b"}, Value: 1}},
+		},
+	}
+}
+
+func TestLokiSink(t *testing.T) {
+	server, req, body := capture(t, http.StatusNoContent)
+
+	sink := newLokiSink(server.URL+"/", "team-a")
+	require.NoError(t, sink.Write(context.Background(), testRecords()))
+
+	assert.Equal(t, "/loki/api/v1/push", req.URL.Path)
+	assert.Equal(t, "team-a", req.Header.Get("X-Scope-OrgID"))
+	assert.JSONEq(t, `{"streams":[{
+		"stream":{"source":"ops-log","subject":"rgw.s3.ops"},
+		"values":[["10000000000","{\"bucket\":\"b\"}"],["20000000000","{\"bucket\":\"b\",\"bytes_sent\":12345678901234567}"]]
+	}]}`, string(*body))
+}
+
+func TestClickHouseSink(t *testing.T) {
+	server, req, body := capture(t, http.StatusOK)
+
+	sink := newClickHouseSink(server.URL, "prysm.ops_log", "default", "secret")
+	require.NoError(t, sink.Write(context.Background(), testRecords()))
+
+	assert.Equal(t, "INSERT INTO prysm.ops_log FORMAT JSONEachRow", req.URL.Query().Get("query"))
+	assert.Equal(t, "1", req.URL.Query().Get("input_format_skip_unknown_fields"))
+	assert.Equal(t, "default", req.Header.Get("X-ClickHouse-User"))
+	assert.Equal(t, "secret", req.Header.Get("X-ClickHouse-Key"))
+
+	rows := strings.Split(strings.TrimSpace(string(*body)), "\n")
+	require.Len(t, rows, 2)
+	assert.JSONEq(t, `{"bucket":"b","bytes_sent":12345678901234567,"source":"ops-log","subject":"rgw.s3.ops","event_time":"1970-01-01 00:00:20.000"}`, rows[0])
+}
+
+func TestClickHouseRowNotAnObject(t *testing.T) {
+	row, err := clickHouseRow(Record{Source: SourceOpsLog, Time: time.Unix(0, 0), Data: json.RawMessage(`[1,2]`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":"[1,2]","source":"ops-log","subject":"","event_time":"1970-01-01 00:00:00.000"}`, string(row))
+}
+
+func TestRemoteWriteSink(t *testing.T) {
+	server, req, body := capture(t, http.StatusNoContent)
+
+	sink := newRemoteWriteSink(server.URL)
+	require.NoError(t, sink.Write(context.Background(), testRecords()))
+	assert.Equal(t, "snappy", req.Header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+
+	decoded, err := snappy.Decode(nil, *body)
+	require.NoError(t, err)
+	series := decodeWriteRequest(t, decoded)
+	assert.Equal(t, []decodedSeries{{labels: []string{"__name__=requests_total", "bucket=b"}, value: 2}}, series)
+
+	// Counters accumulate over batches
+	require.NoError(t, sink.Write(context.Background(), testRecords()[:1]))
+	decoded, err = snappy.Decode(nil, *body)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, decodeWriteRequest(t, decoded)[0].value)
+}
+
+func TestSinkErrorStatus(t *testing.T) {
+	server, _, _ := capture(t, http.StatusBadRequest)
+
+	err := newLokiSink(server.URL, "").Write(context.Background(), testRecords())
+	assert.ErrorContains(t, err, "400")
+}
+
+type decodedSeries struct {
+	labels []string
+	value  float64
+}
+
+// decodeWriteRequest decodes the fields of a WriteRequest the sink sets
+func decodeWriteRequest(t *testing.T, data []byte) []decodedSeries {
+	t.Helper()
+	var result []decodedSeries
+	forEachField(t, data, func(num protowire.Number, value []byte) {
+		require.Equal(t, protowire.Number(1), num)
+		series := decodedSeries{}
+		forEachField(t, value, func(num protowire.Number, value []byte) {
+			switch num {
+			case 1:
+				var label [2]string
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					label[num-1] = string(value)
+				})
+				series.labels = append(series.labels, label[0]+"="+label[1])
+			case 2:
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					if num == 1 {
+						bits, _ := protowire.ConsumeFixed64(value)
+						series.value = math.Float64frombits(bits)
+					}
+				})
+			}
+		})
+		result = append(result, series)
+	})
+	return result
+}
+
+// forEachField calls fn with the raw value of every field in data; values
+// of fixed64 and varint fields are passed in their wire encoding
+func forEachField(t *testing.T, data []byte, fn func(protowire.Number, []byte)) {
+	t.Helper()
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		require.GreaterOrEqual(t, n, 0)
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		require.GreaterOrEqual(t, n, 0)
+		value := data[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(data)
+		}
+		fn(num, value)
+		data = data[n:]
+	}
+}
+
+type recordingSink struct {
+	batches [][]Record
+	err     error
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(_ context.Context, records []Record) error {
+	s.batches = append(s.batches, records)
+	return s.err
+}
+
+func TestRunBatches(t *testing.T) {
+	records := make(chan Record, 5)
+	for i := 0; i < 5; i++ {
+		records <- Record{Source: SourceOpsLog}
+	}
+	close(records)
+
+	ok, failing := &recordingSink{}, &recordingSink{err: errors.New("unavailable")}
+	runBatches(records, []Sink{ok, failing}, 2, time.Hour)
+
+	require.Len(t, ok.batches, 3)
+	assert.Len(t, ok.batches[0], 2)
+	assert.Len(t, ok.batches[2], 1)
+	assert.Len(t, failing.batches, 3)
+}
+
+func testAlertRecords(keys ...string) []Record {
+	var records []Record
+	for _, key := range keys {
+		records = append(records, Record{Source: SourceEvents, Alerts: []Alert{{Key: key, Producer: "disk-health-metrics", Event: "failure_risk", Severity: SeverityCritical, Summary: "disk \"sda\" failing"}}})
+	}
+	return records
+}
+
+func TestWebhookSink(t *testing.T) {
+	server, req, body := capture(t, http.StatusCreated)
+	template := filepath.Join(t.TempDir(), "ticket.tmpl")
+	require.NoError(t, os.WriteFile(template, []byte(`{"short_description":{{json .Summary}},"urgency":"{{upper .Severity}}","correlation_id":{{json .Key}}}`), 0o600))
+
+	sink, err := newWebhookSink(SinkConsumerConfig{
+		WebhookURL: server.URL, WebhookTemplate: template, WebhookSecret: "s3cret", WebhookHeaders: "Authorization=Bearer token",
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testAlertRecords("node-1/sda")))
+
+	assert.JSONEq(t, `{"short_description":"disk \"sda\" failing","urgency":"CRITICAL","correlation_id":"node-1/sda"}`, string(*body))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	assert.Equal(t, "sha256="+Sign([]byte("s3cret"), *body), req.Header.Get(SignatureHeader))
+}
+
+func TestWebhookSinkRepeatInterval(t *testing.T) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		_ = json.NewDecoder(r.Body).Decode(&alert)
+		posted = append(posted, alert.Key)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Unix(1000, 0)
+	sink, err := newWebhookSink(SinkConsumerConfig{WebhookURL: server.URL, WebhookRepeatInterval: time.Hour})
+	require.NoError(t, err)
+	sink.now = func() time.Time { return now }
+
+	require.NoError(t, sink.Write(context.Background(), testAlertRecords("a", "a", "b")))
+	assert.Equal(t, []string{"a", "b"}, posted)
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, sink.Write(context.Background(), testAlertRecords("a")))
+	assert.Len(t, posted, 2)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, sink.Write(context.Background(), testAlertRecords("a")))
+	assert.Equal(t, []string{"a", "b", "a"}, posted)
+}
+
+func TestWebhookSinkRejected(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	sink, err := newWebhookSink(SinkConsumerConfig{WebhookURL: server.URL, WebhookRepeatInterval: time.Hour})
+	require.NoError(t, err)
+	err = sink.Write(context.Background(), testAlertRecords("a"))
+	assert.ErrorContains(t, err, "invalid payload")
+	assert.Equal(t, 1, requests, "a rejected payload is not retried")
+	assert.Empty(t, sink.posted, "a failed alert is posted again")
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/selflimit"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+func StartSinkConsumer(cfg SinkConsumerConfig) {
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error creating sinks")
+	}
+	for _, sink := range sinks {
+		log.Info().Str("sink", sink.Name()).Msg("sink enabled")
+	}
+
+	if cfg.Prometheus {
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+	}
+
+	nc, err := natsutil.Connect(cfg.NatsURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error connecting to nats")
+	}
+	defer nc.Close()
+
+	records := make(chan Record, cfg.BatchSize)
+	subs, err := subscribe(nc, cfg, records)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error subscribing to nats subject")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Stop receiving on shutdown, then write what was received
+	go func() {
+		<-ctx.Done()
+		for _, sub := range subs {
+			if err := sub.Drain(); err != nil {
+				log.Error().Err(err).Msg("error draining nats subscription")
+			}
+		}
+		for _, sub := range subs {
+			for sub.IsValid() {
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+		close(records)
+	}()
+
+	runBatches(records, sinks, cfg.BatchSize, time.Duration(cfg.FlushInterval)*time.Second)
+	log.Info().Msg("consumer stopped")
+}
+
+// runBatches writes the records to every sink in batches of up to batchSize
+// records, fewer under memory pressure, and at least every flushInterval,
+// until records is closed
+func runBatches(records <-chan Record, sinks []Sink, batchSize int, flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, batchSize)
+	size := selflimit.BatchSize(batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		writeBatch(sinks, batch)
+		batch = make([]Record, 0, batchSize)
+		size = selflimit.BatchSize(batchSize)
+	}
+
+	for {
+		select {
+		case record, ok := <-records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func writeBatch(sinks []Sink, batch []Record) {
+	for _, sink := range sinks {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := sink.Write(ctx, batch)
+		cancel()
+		if err != nil {
+			sinkRecordsDropped.WithLabelValues(sink.Name()).Add(float64(len(batch)))
+			log.Error().Err(err).Str("sink", sink.Name()).Int("records", len(batch)).Msg("error writing batch")
+			continue
+		}
+		sinkRecordsWritten.WithLabelValues(sink.Name()).Add(float64(len(batch)))
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sinkconsumer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+)
+
+// SignatureHeader holds the HMAC-SHA256 of the payload, as sha256=<hex>
+const SignatureHeader = "X-Prysm-Signature-256"
+
+var webhookRetry = retry.Policy{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second, Jitter: 0.2}
+
+// webhookSink posts every alert of the records, one request per alert, to
+// an HTTP endpoint such as the API of a ticketing system. The payload is the
+// alert as JSON or rendered by a template.
+type webhookSink struct {
+	url      string
+	headers  map[string]string
+	template *template.Template // nil posts the alert as JSON
+	secret   []byte
+	repeat   time.Duration
+	posted   map[string]time.Time // when the alerts were last posted, by key
+	now      func() time.Time
+}
+
+// webhookFuncs are the functions of the payload templates
+var webhookFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. to quote a string in a JSON payload
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+}
+
+func newWebhookSink(cfg SinkConsumerConfig) (*webhookSink, error) {
+	headers, err := remotewrite.ParseLabels(cfg.WebhookHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook headers: %w", err)
+	}
+	sink := &webhookSink{
+		url:     cfg.WebhookURL,
+		headers: headers,
+		secret:  []byte(cfg.WebhookSecret),
+		repeat:  cfg.WebhookRepeatInterval,
+		posted:  map[string]time.Time{},
+		now:     time.Now,
+	}
+	if cfg.WebhookTemplate != "" {
+		text, err := os.ReadFile(cfg.WebhookTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook template %s: %w", cfg.WebhookTemplate, err)
+		}
+		sink.template, err = template.New(filepath.Base(cfg.WebhookTemplate)).Funcs(webhookFuncs).Option("missingkey=zero").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse webhook template %s: %w", cfg.WebhookTemplate, err)
+		}
+	}
+	return sink, nil
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+// Write posts the alerts of the records that were not posted within the
+// repeat interval. An alert failing is retried, then the others still go.
+func (s *webhookSink) Write(ctx context.Context, records []Record) error {
+	var errs []error
+	for _, record := range records {
+		for _, alert := range record.Alerts {
+			now := s.now()
+			if last, ok := s.posted[alert.Key]; ok && s.repeat > 0 && now.Sub(last) < s.repeat {
+				continue
+			}
+			if err := s.post(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("alert %s: %w", alert.Key, err))
+				continue
+			}
+			s.posted[alert.Key] = now
+		}
+	}
+	s.forget(s.now())
+	return errors.Join(errs...)
+}
+
+// forget drops the alerts posted before the repeat interval, they are
+// posted again anyway
+func (s *webhookSink) forget(now time.Time) {
+	for key, last := range s.posted {
+		if now.Sub(last) >= s.repeat {
+			delete(s.posted, key)
+		}
+	}
+}
+
+// payload renders the alert with the template, as JSON without one
+func (s *webhookSink) payload(alert Alert) ([]byte, error) {
+	if s.template == nil {
+		return json.Marshal(alert)
+	}
+	var buf bytes.Buffer
+	if err := s.template.Execute(&buf, alert); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends the alert, retrying on failed requests, rate limits and server
+// errors
+func (s *webhookSink) post(ctx context.Context, alert Alert) error {
+	body, err := s.payload(alert)
+	if err != nil {
+		return fmt.Errorf("rendering the payload: %w", err)
+	}
+
+	return retry.Do(ctx, "webhook", webhookRetry, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range s.headers {
+			req.Header.Set(name, value)
+		}
+		if len(s.secret) > 0 {
+			req.Header.Set(SignatureHeader, "sha256="+Sign(s.secret, body))
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 == 2 {
+			return nil
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err = fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+}
+
+// Sign returns the hex HMAC-SHA256 of body with secret, for receivers to
+// verify the SignatureHeader of the payloads
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dashboards generates the Grafana dashboards of the metrics the
+// producers export with a configuration, so a dashboard never shows panels
+// of metrics that are not enabled.
+package dashboards
+
+import (
+	"fmt"
+
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+)
+
+// Producers with dashboards
+const (
+	ProducerOpsLog            = "ops-log"
+	ProducerRadosGWUsage      = "radosgw-usage"
+	ProducerDiskHealthMetrics = "disk-health-metrics"
+)
+
+// Producers lists the producers in the order their dashboards are generated
+var Producers = []string{ProducerOpsLog, ProducerRadosGWUsage, ProducerDiskHealthMetrics}
+
+// Config holds the configuration of every producer a dashboard is generated
+// for, as the producer would be started with
+type Config struct {
+	Producers    []string // all if empty
+	OpsLog       opslog.OpsLogConfig
+	RadosGWUsage radosgwusage.RadosGWUsageConfig
+	DiskHealth   diskhealthmetrics.DiskHealthMetricsConfig
+	// Metrics names the metrics as the producers serve them, with
+	// --metrics-prefix
+	Metrics metricnames.Config
+}
+
+// Dashboard is the JSON model of a Grafana dashboard, ready for import
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+// Panel is a row or a time series panel of a dashboard
+type Panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     gridPos      `json:"gridPos"`
+	Datasource  *datasource  `json:"datasource,omitempty"`
+	Targets     []target     `json:"targets,omitempty"`
+	FieldConfig *fieldConfig `json:"fieldConfig,omitempty"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+// The Prometheus datasource is picked when the dashboard is opened
+var prometheusDatasource = &datasource{Type: "prometheus", UID: "${datasource}"}
+
+const (
+	schemaVersion = 39 // Grafana 10.4
+	panelWidth    = 12 // Two panels side by side
+	panelHeight   = 8
+)
+
+// panel is a panel of the catalog of a producer, shown if its metric is
+// enabled by the configuration C
+type panel[C any] struct {
+	title       string
+	description string
+	metric      string // The metric shown, queried by expr
+	expr        string
+	legend      string
+	unit        string
+	enabled     func(C) bool // nil if the metric is always exported
+}
+
+// section is a row of panels
+type section[C any] struct {
+	title  string
+	panels []panel[C]
+}
+
+// Generate returns the dashboards of the selected producers
+func Generate(cfg Config) ([]Dashboard, error) {
+	producers := cfg.Producers
+	if len(producers) == 0 {
+		producers = Producers
+	}
+
+	var dashboards []Dashboard
+	for _, producer := range producers {
+		switch producer {
+		case ProducerOpsLog:
+			opsLog := cfg.OpsLog
+			opsLog.MetricsConfig.ApplyShortcuts()
+			dashboards = append(dashboards, build("prysm-ops-log", "Prysm / RGW Operations", opsLog, opsLogSections, cfg.Metrics))
+		case ProducerRadosGWUsage:
+			dashboards = append(dashboards, build("prysm-radosgw-usage", "Prysm / RGW Usage", cfg.RadosGWUsage, radosGWUsageSections, cfg.Metrics))
+		case ProducerDiskHealthMetrics:
+			dashboards = append(dashboards, build("prysm-disk-health-metrics", "Prysm / Disk Health", cfg.DiskHealth, diskHealthSections, cfg.Metrics))
+		default:
+			return nil, fmt.Errorf("unknown producer %q, expected one of %v", producer, Producers)
+		}
+	}
+	return dashboards, nil
+}
+
+// build lays out the enabled panels of the sections, two per line. Sections
+// without an enabled panel are left out. The queries use the names the
+// metrics are served under.
+func build[C any](uid, title string, cfg C, sections []section[C], names metricnames.Config) Dashboard {
+	dashboard := Dashboard{
+		UID:           uid,
+		Title:         title,
+		Tags:          []string{"prysm"},
+		Timezone:      "browser",
+		Editable:      true,
+		SchemaVersion: schemaVersion,
+		Refresh:       "1m",
+		Time:          timeRange{From: "now-6h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Datasource", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: []Panel{},
+	}
+
+	id, y := 1, 0
+	for _, s := range sections {
+		var enabled []panel[C]
+		for _, p := range s.panels {
+			if p.enabled == nil || p.enabled(cfg) {
+				enabled = append(enabled, p)
+			}
+		}
+		if len(enabled) == 0 {
+			continue
+		}
+
+		dashboard.Panels = append(dashboard.Panels, Panel{
+			ID:      id,
+			Type:    "row",
+			Title:   s.title,
+			GridPos: gridPos{H: 1, W: 24, X: 0, Y: y},
+		})
+		id++
+		y++
+
+		for i, p := range enabled {
+			dashboard.Panels = append(dashboard.Panels, Panel{
+				ID:          id,
+				Type:        "timeseries",
+				Title:       p.title,
+				Description: p.description,
+				GridPos:     gridPos{H: panelHeight, W: panelWidth, X: (i % 2) * panelWidth, Y: y + (i/2)*panelHeight},
+				Datasource:  prometheusDatasource,
+				Targets:     []target{{RefID: "A", Expr: names.Query(p.expr), LegendFormat: p.legend}},
+				FieldConfig: &fieldConfig{Defaults: fieldDefaults{Unit: p.unit}},
+			})
+			id++
+		}
+		y += (len(enabled) + 1) / 2 * panelHeight
+	}
+	return dashboard
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboards
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// titles returns the titles of the panels of a dashboard that are not rows
+func titles(d Dashboard) []string {
+	var out []string
+	for _, p := range d.Panels {
+		if p.Type != "row" {
+			out = append(out, p.Title)
+		}
+	}
+	return out
+}
+
+func checkExprs[C any](t *testing.T, sections []section[C]) {
+	t.Helper()
+	for _, s := range sections {
+		for _, p := range s.panels {
+			assert.Contains(t, p.expr, p.metric, "panel %q", p.title)
+			assert.NotEmpty(t, p.legend, "panel %q", p.title)
+		}
+	}
+}
+
+func TestCatalogs_QueryTheirMetric(t *testing.T) {
+	checkExprs(t, opsLogSections)
+	checkExprs(t, radosGWUsageSections)
+	checkExprs(t, diskHealthSections)
+}
+
+// checkServedNames checks that the queries of the panels name their metric
+// as it is served, and no metric without the prefix
+func checkServedNames[C any](t *testing.T, sections []section[C], names metricnames.Config) {
+	t.Helper()
+	for _, s := range sections {
+		for _, p := range s.panels {
+			query := names.Query(p.expr)
+			assert.Contains(t, query, names.Name(p.metric), "panel %q", p.title)
+			if names.Name(p.metric) != p.metric {
+				assert.NotRegexp(t, `(^|[^\w:])`+regexp.QuoteMeta(p.metric), query, "panel %q", p.title)
+			}
+		}
+	}
+}
+
+func TestCatalogs_QueryServedNames(t *testing.T) {
+	names := metricnames.Config{Prefix: metricnames.DefaultPrefix}
+	checkServedNames(t, opsLogSections, names)
+	checkServedNames(t, radosGWUsageSections, names)
+	checkServedNames(t, diskHealthSections, names)
+
+	generated, err := Generate(Config{Producers: []string{ProducerDiskHealthMetrics}, Metrics: names})
+	require.NoError(t, err)
+	require.NotEmpty(t, generated[0].Panels)
+	for _, p := range generated[0].Panels {
+		for _, target := range p.Targets {
+			assert.NotRegexp(t, `(^|[^\w:])(disk|ssd)_`, target.Expr, "panel %q", p.Title)
+		}
+	}
+}
+
+func TestGenerate_AllProducers(t *testing.T) {
+	generated, err := Generate(Config{})
+	require.NoError(t, err)
+	require.Len(t, generated, len(Producers))
+
+	assert.Equal(t, "prysm-ops-log", generated[0].UID)
+	assert.Equal(t, "prysm-radosgw-usage", generated[1].UID)
+	assert.Equal(t, "prysm-disk-health-metrics", generated[2].UID)
+}
+
+func TestGenerate_UnknownProducer(t *testing.T) {
+	_, err := Generate(Config{Producers: []string{"ops-log", "kernel-metrics"}})
+	assert.ErrorContains(t, err, `unknown producer "kernel-metrics"`)
+}
+
+func TestGenerate_OpsLog(t *testing.T) {
+	generated, err := Generate(Config{Producers: []string{ProducerOpsLog}})
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	assert.Empty(t, generated[0].Panels, "no metric is enabled by default")
+
+	generated, err = Generate(Config{
+		Producers: []string{ProducerOpsLog},
+		OpsLog: opslog.OpsLogConfig{MetricsConfig: opslog.MetricsConfig{
+			TrackRequestsPerUser: true,
+			TrackLatencyDetailed: true,
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Requests of the top users", "p99 latency by method"}, titles(generated[0]))
+
+	generated, err = Generate(Config{
+		Producers: []string{ProducerOpsLog},
+		OpsLog:    opslog.OpsLogConfig{MetricsConfig: opslog.MetricsConfig{TrackEverything: true}},
+	})
+	require.NoError(t, err)
+	got := titles(generated[0])
+	assert.Contains(t, got, "Requests by status")
+	assert.Contains(t, got, "Availability by operation")
+	assert.Contains(t, got, "Denied requests of the top users")
+	assert.NotContains(t, got, "Requests of the top users", "aggregations are left to Prometheus")
+	assert.NotContains(t, got, "Loki entries")
+	assert.NotContains(t, got, "Requests by bucket tags")
+
+	generated, err = Generate(Config{
+		Producers: []string{ProducerOpsLog},
+		OpsLog:    opslog.OpsLogConfig{BucketTagsKV: "sync_bucket_data"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Requests by bucket tags", "Bytes sent by bucket tags", "Bytes received by bucket tags"}, titles(generated[0]))
+}
+
+func TestGenerate_RadosGWUsage(t *testing.T) {
+	generated, err := Generate(Config{Producers: []string{ProducerRadosGWUsage}})
+	require.NoError(t, err)
+	got := titles(generated[0])
+	assert.Contains(t, got, "Size of the top buckets")
+	assert.NotContains(t, got, "Public buckets")
+	assert.NotContains(t, got, "Slowest users")
+
+	generated, err = Generate(Config{
+		Producers:    []string{ProducerRadosGWUsage},
+		RadosGWUsage: radosgwusage.RadosGWUsageConfig{AuditBucketAccess: true, OpsMetricsJoin: true},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, titles(generated[0]), "Public buckets")
+	assert.Contains(t, titles(generated[0]), "Slowest users")
+}
+
+func TestGenerate_DiskHealth(t *testing.T) {
+	generated, err := Generate(Config{Producers: []string{ProducerDiskHealthMetrics}})
+	require.NoError(t, err)
+	got := titles(generated[0])
+	assert.Contains(t, got, "Hottest disks")
+	assert.Contains(t, got, "Scan duration")
+	for _, title := range []string{"Slowest disks", "Self-tests running", "Endurance group life used", "Hotplug events"} {
+		assert.NotContains(t, got, title)
+	}
+
+	generated, err = Generate(Config{
+		Producers:  []string{ProducerDiskHealthMetrics},
+		DiskHealth: diskhealthmetrics.DiskHealthMetricsConfig{KernelIO: true, Hotplug: true},
+	})
+	require.NoError(t, err)
+	got = titles(generated[0])
+	assert.Contains(t, got, "Slowest disks")
+	assert.Contains(t, got, "Hotplug events")
+	assert.NotContains(t, got, "Self-tests running")
+}
+
+func TestGenerate_Layout(t *testing.T) {
+	generated, err := Generate(Config{
+		OpsLog:       opslog.OpsLogConfig{MetricsConfig: opslog.MetricsConfig{TrackEverything: true}},
+		RadosGWUsage: radosgwusage.RadosGWUsageConfig{AuditBucketAccess: true, OpsMetricsJoin: true},
+		DiskHealth:   diskhealthmetrics.DiskHealthMetricsConfig{KernelIO: true, SelfTest: true, NVMeTelemetry: true, Hotplug: true},
+	})
+	require.NoError(t, err)
+
+	for _, d := range generated {
+		ids := map[int]bool{}
+		cells := map[[2]int]string{}
+		for _, p := range d.Panels {
+			assert.False(t, ids[p.ID], "%s: duplicate panel id %d", d.UID, p.ID)
+			ids[p.ID] = true
+
+			if p.Type == "row" {
+				continue
+			}
+			cell := [2]int{p.GridPos.X, p.GridPos.Y}
+			assert.Empty(t, cells[cell], "%s: %q overlaps %q", d.UID, p.Title, cells[cell])
+			cells[cell] = p.Title
+			require.Len(t, p.Targets, 1)
+			assert.Equal(t, prometheusDatasource, p.Datasource)
+		}
+
+		data, err := json.Marshal(d)
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(string(data), `"uid":"${datasource}"`))
+	}
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboards
+
+import "github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+
+type diskHealthPanel = panel[diskhealthmetrics.DiskHealthMetricsConfig]
+
+// diskHealthSections are the panels of the disk-health-metrics metrics. The
+// kernel I/O, self-test, NVMe telemetry and hotplug metrics are only
+// exported with their options.
+var diskHealthSections = []section[diskhealthmetrics.DiskHealthMetricsConfig]{
+	{
+		title: "Health",
+		panels: []diskHealthPanel{
+			{
+				title:  "Failure risk of the top disks",
+				metric: "disk_failure_risk_score",
+				expr:   topGauge("disk_failure_risk_score", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+			{
+				title:  "Failure risk trend of the top disks",
+				metric: "disk_failure_risk_trend",
+				expr:   topGauge("disk_failure_risk_trend", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+			{
+				title:  "Reallocated sectors",
+				metric: "disk_reallocated_sectors",
+				expr:   topGauge("disk_reallocated_sectors", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+			{
+				title:  "Pending sectors",
+				metric: "disk_pending_sectors",
+				expr:   topGauge("disk_pending_sectors", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+			{
+				title:  "Grown defects of SCSI disks",
+				metric: "disk_scsi_grown_defects",
+				expr:   topGauge("disk_scsi_grown_defects", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+			{
+				title:  "Disks with unapproved firmware",
+				metric: "disk_firmware_compliant",
+				expr:   "count by (node) (disk_firmware_compliant == 0)",
+				legend: "{{node}}",
+				unit:   "short",
+			},
+		},
+	},
+	{
+		title: "Temperature and Wear",
+		panels: []diskHealthPanel{
+			{
+				title:  "Hottest disks",
+				metric: "disk_temperature_celsius",
+				expr:   topGauge("disk_temperature_celsius", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "celsius",
+			},
+			{
+				title:  "Disks above a temperature threshold",
+				metric: "disk_temperature_alert_level",
+				expr:   "count by (media_type) (disk_temperature_alert_level > 0)",
+				legend: "{{media_type}}",
+				unit:   "short",
+			},
+			{
+				title:  "SSD life used",
+				metric: "ssd_life_used_percentage",
+				expr:   topGauge("ssd_life_used_percentage", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "percent",
+			},
+			{
+				title:  "Shortest remaining SSD life",
+				metric: "disk_remaining_life_days",
+				expr:   bottomGauge("disk_remaining_life_days", "node, disk"),
+				legend: "{{node}} {{disk}}",
+				unit:   "d",
+			},
+		},
+	},
+	{
+		title: "Errors",
+		panels: []diskHealthPanel{
+			{
+				title:  "I/O errors by source",
+				metric: "disk_io_errors",
+				expr:   gauge("disk_io_errors", "source, error_type"),
+				legend: "{{source}} {{error_type}}",
+				unit:   "short",
+			},
+			{
+				title:  "SCSI errors",
+				metric: "disk_scsi_errors_total",
+				expr:   rate("disk_scsi_errors_total", "op, error_type"),
+				legend: "{{op}} {{error_type}}",
+				unit:   "ops",
+			},
+			{
+				title:  "Failed collections",
+				metric: "disk_collection_errors_total",
+				expr:   rate("disk_collection_errors_total", "node, reason"),
+				legend: "{{node}} {{reason}}",
+				unit:   "ops",
+			},
+			{
+				title:  "Paths down",
+				metric: "disk_path_up",
+				expr:   "count by (node, disk) (disk_path_up == 0)",
+				legend: "{{node}} {{disk}}",
+				unit:   "short",
+			},
+		},
+	},
+	{
+		title: "Kernel I/O",
+		panels: []diskHealthPanel{
+			{
+				title:   "Slowest disks",
+				metric:  "disk_io_latency_ms",
+				expr:    topGauge("disk_io_latency_ms", "node, disk, op"),
+				legend:  "{{node}} {{disk}} {{op}}",
+				unit:    "ms",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.KernelIO },
+			},
+			{
+				title:   "I/O in flight",
+				metric:  "disk_io_in_flight",
+				expr:    topGauge("disk_io_in_flight", "node, disk"),
+				legend:  "{{node}} {{disk}}",
+				unit:    "short",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.KernelIO },
+			},
+		},
+	},
+	{
+		title: "Self-Tests",
+		panels: []diskHealthPanel{
+			{
+				title:   "Self-tests running",
+				metric:  "disk_self_test_in_progress",
+				expr:    gauge("disk_self_test_in_progress", "node"),
+				legend:  "{{node}}",
+				unit:    "short",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.SelfTest },
+			},
+			{
+				title:   "Disks failing their last self-test",
+				metric:  "disk_self_test_last_passed",
+				expr:    "count by (test_type) (disk_self_test_last_passed == 0)",
+				legend:  "{{test_type}}",
+				unit:    "short",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.SelfTest },
+			},
+		},
+	},
+	{
+		title: "NVMe",
+		panels: []diskHealthPanel{
+			{
+				title:   "Endurance group life used",
+				metric:  "disk_nvme_endurance_group_percentage_used",
+				expr:    topGauge("disk_nvme_endurance_group_percentage_used", "node, disk"),
+				legend:  "{{node}} {{disk}}",
+				unit:    "percent",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.NVMeTelemetry },
+			},
+			{
+				title:   "Endurance group spare",
+				metric:  "disk_nvme_endurance_group_available_spare",
+				expr:    bottomGauge("disk_nvme_endurance_group_available_spare", "node, disk"),
+				legend:  "{{node}} {{disk}}",
+				unit:    "percent",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.NVMeTelemetry },
+			},
+			{
+				title:   "Failed NVMe self-tests",
+				metric:  "disk_nvme_self_test_failed_count",
+				expr:    topGauge("disk_nvme_self_test_failed_count", "node, disk"),
+				legend:  "{{node}} {{disk}}",
+				unit:    "short",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.NVMeTelemetry },
+			},
+		},
+	},
+	{
+		title: "Scans",
+		panels: []diskHealthPanel{
+			{
+				title:  "Scan duration",
+				metric: "disk_scan_duration_seconds",
+				expr:   gauge("disk_scan_duration_seconds", "node"),
+				legend: "{{node}}",
+				unit:   "s",
+			},
+			{
+				title:   "Hotplug events",
+				metric:  "disk_hotplug_events_total",
+				expr:    rate("disk_hotplug_events_total", "node, action"),
+				legend:  "{{node}} {{action}}",
+				unit:    "ops",
+				enabled: func(c diskhealthmetrics.DiskHealthMetricsConfig) bool { return c.Hotplug },
+			},
+		},
+	},
+}
@@ -0,0 +1,587 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboards
+
+import "github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+
+type opsLogPanel = panel[opslog.OpsLogConfig]
+
+// opsLogSections are the panels of the ops-log metrics, each shown when the
+// tracking option registering its metric is set. The IP gauges hold running
+// totals, so they are rated like counters.
+var opsLogSections = []section[opslog.OpsLogConfig]{
+	{
+		title: "Requests",
+		panels: []opsLogPanel{
+			{
+				title:   "Requests by status",
+				metric:  "radosgw_total_requests",
+				expr:    rate("radosgw_total_requests", "http_status"),
+				legend:  "{{http_status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsDetailed },
+			},
+			{
+				title:   "Requests of the top users",
+				metric:  "radosgw_total_requests_per_user",
+				expr:    topRate("radosgw_total_requests_per_user", "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsPerUser },
+			},
+			{
+				title:   "Requests of the top buckets",
+				metric:  "radosgw_total_requests_per_bucket",
+				expr:    topRate("radosgw_total_requests_per_bucket", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsPerBucket },
+			},
+			{
+				title:   "Requests by tenant",
+				metric:  "radosgw_total_requests_per_tenant",
+				expr:    rate("radosgw_total_requests_per_tenant", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsPerTenant },
+			},
+		},
+	},
+	{
+		title: "Methods and Operations",
+		panels: []opsLogPanel{
+			{
+				title:   "Requests by method",
+				metric:  "radosgw_requests_by_method",
+				expr:    rate("radosgw_requests_by_method", "method"),
+				legend:  "{{method}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByMethodDetailed },
+			},
+			{
+				title:   "Methods of the top users",
+				metric:  "radosgw_requests_by_method_per_user",
+				expr:    topRate("radosgw_requests_by_method_per_user", "tenant, user, method"),
+				legend:  "{{tenant}}/{{user}} {{method}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByMethodPerUser },
+			},
+			{
+				title:   "Methods of the top buckets",
+				metric:  "radosgw_requests_by_method_per_bucket",
+				expr:    topRate("radosgw_requests_by_method_per_bucket", "tenant, bucket, method"),
+				legend:  "{{tenant}}/{{bucket}} {{method}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByMethodPerBucket },
+			},
+			{
+				title:   "Methods by tenant",
+				metric:  "radosgw_requests_by_method_per_tenant",
+				expr:    rate("radosgw_requests_by_method_per_tenant", "tenant, method"),
+				legend:  "{{tenant}} {{method}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByMethodPerTenant },
+			},
+			{
+				title:   "Requests by method, all tenants",
+				metric:  "radosgw_requests_by_method_global",
+				expr:    rate("radosgw_requests_by_method_global", "method"),
+				legend:  "{{method}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByMethodGlobal },
+			},
+			{
+				title:   "Requests by operation",
+				metric:  "radosgw_requests_by_operation",
+				expr:    rate("radosgw_requests_by_operation", "operation"),
+				legend:  "{{operation}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByOperationDetailed },
+			},
+			{
+				title:   "Operations of the top users",
+				metric:  "radosgw_requests_by_operation_per_user",
+				expr:    topRate("radosgw_requests_by_operation_per_user", "tenant, user, operation"),
+				legend:  "{{tenant}}/{{user}} {{operation}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByOperationPerUser },
+			},
+			{
+				title:   "Operations of the top buckets",
+				metric:  "radosgw_requests_by_operation_per_bucket",
+				expr:    topRate("radosgw_requests_by_operation_per_bucket", "tenant, bucket, operation"),
+				legend:  "{{tenant}}/{{bucket}} {{operation}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByOperationPerBucket },
+			},
+			{
+				title:   "Operations by tenant",
+				metric:  "radosgw_requests_by_operation_per_tenant",
+				expr:    rate("radosgw_requests_by_operation_per_tenant", "tenant, operation"),
+				legend:  "{{tenant}} {{operation}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByOperationPerTenant },
+			},
+			{
+				title:   "Requests by operation, all tenants",
+				metric:  "radosgw_requests_by_operation_global",
+				expr:    rate("radosgw_requests_by_operation_global", "operation"),
+				legend:  "{{operation}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByOperationGlobal },
+			},
+		},
+	},
+	{
+		title: "Status Codes",
+		panels: []opsLogPanel{
+			{
+				title:   "Responses by status",
+				metric:  "radosgw_requests_by_status_detailed",
+				expr:    rate("radosgw_requests_by_status_detailed", "status"),
+				legend:  "{{status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByStatusDetailed },
+			},
+			{
+				title:   "Responses of the top users",
+				metric:  "radosgw_requests_by_status_per_user",
+				expr:    topRate("radosgw_requests_by_status_per_user", "tenant, user, status"),
+				legend:  "{{tenant}}/{{user}} {{status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByStatusPerUser },
+			},
+			{
+				title:   "Responses of the top buckets",
+				metric:  "radosgw_requests_by_status_per_bucket",
+				expr:    topRate("radosgw_requests_by_status_per_bucket", "tenant, bucket, status"),
+				legend:  "{{tenant}}/{{bucket}} {{status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByStatusPerBucket },
+			},
+			{
+				title:   "Responses by tenant",
+				metric:  "radosgw_requests_by_status_per_tenant",
+				expr:    rate("radosgw_requests_by_status_per_tenant", "tenant, status"),
+				legend:  "{{tenant}} {{status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByStatusPerTenant },
+			},
+		},
+	},
+	{
+		title: "Throughput",
+		panels: []opsLogPanel{
+			{
+				title:   "Bytes sent by the top buckets",
+				metric:  "radosgw_bytes_sent",
+				expr:    topRate("radosgw_bytes_sent", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentDetailed },
+			},
+			{
+				title:   "Bytes received by the top buckets",
+				metric:  "radosgw_bytes_received",
+				expr:    topRate("radosgw_bytes_received", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedDetailed },
+			},
+			{
+				title:   "Bytes sent to the top users",
+				metric:  "radosgw_bytes_sent_per_user",
+				expr:    topRate("radosgw_bytes_sent_per_user", "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentPerUser },
+			},
+			{
+				title:   "Bytes received from the top users",
+				metric:  "radosgw_bytes_received_per_user",
+				expr:    topRate("radosgw_bytes_received_per_user", "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedPerUser },
+			},
+			{
+				title:   "Bytes sent per bucket",
+				metric:  "radosgw_bytes_sent_per_bucket",
+				expr:    topRate("radosgw_bytes_sent_per_bucket", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentPerBucket },
+			},
+			{
+				title:   "Bytes received per bucket",
+				metric:  "radosgw_bytes_received_per_bucket",
+				expr:    topRate("radosgw_bytes_received_per_bucket", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedPerBucket },
+			},
+			{
+				title:   "Bytes sent by tenant",
+				metric:  "radosgw_bytes_sent_per_tenant",
+				expr:    rate("radosgw_bytes_sent_per_tenant", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentPerTenant },
+			},
+			{
+				title:   "Bytes received by tenant",
+				metric:  "radosgw_bytes_received_per_tenant",
+				expr:    rate("radosgw_bytes_received_per_tenant", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedPerTenant },
+			},
+		},
+	},
+	{
+		title: "Errors",
+		panels: []opsLogPanel{
+			{
+				title:   "Errors by status",
+				metric:  "radosgw_errors_detailed",
+				expr:    rate("radosgw_errors_detailed", "http_status"),
+				legend:  "{{http_status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsDetailed },
+			},
+			{
+				title:   "Errors of the top users",
+				metric:  "radosgw_errors_per_user",
+				expr:    topRate("radosgw_errors_per_user", "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsPerUser },
+			},
+			{
+				title:   "Errors of the top buckets",
+				metric:  "radosgw_errors_per_bucket",
+				expr:    topRate("radosgw_errors_per_bucket", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsPerBucket },
+			},
+			{
+				title:   "Errors by tenant",
+				metric:  "radosgw_errors_per_tenant",
+				expr:    rate("radosgw_errors_per_tenant", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsPerTenant },
+			},
+			{
+				title:   "Errors by status, all tenants",
+				metric:  "radosgw_errors_per_status",
+				expr:    rate("radosgw_errors_per_status", "http_status"),
+				legend:  "{{http_status}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsPerStatus },
+			},
+			{
+				title:   "Errors of the top clients",
+				metric:  "radosgw_errors_per_ip",
+				expr:    topRate("radosgw_errors_per_ip", "ip"),
+				legend:  "{{ip}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsByIP },
+			},
+			{
+				title:       "Timeouts by type",
+				description: "408, 504, 598 and 499 responses, a sign of slow OSDs",
+				metric:      "radosgw_timeout_errors",
+				expr:        rate("radosgw_timeout_errors", "timeout_type"),
+				legend:      "{{timeout_type}}",
+				unit:        "reqps",
+				enabled:     func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackTimeoutErrors },
+			},
+			{
+				title:   "Errors by category",
+				metric:  "radosgw_errors_by_category",
+				expr:    rate("radosgw_errors_by_category", "error_category"),
+				legend:  "{{error_category}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackErrorsByCategory },
+			},
+		},
+	},
+	{
+		title: "Clients",
+		panels: []opsLogPanel{
+			{
+				title:   "Requests of the top clients",
+				metric:  "radosgw_requests_by_ip",
+				expr:    topRate("radosgw_requests_by_ip", "ip"),
+				legend:  "{{ip}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByIPDetailed },
+			},
+			{
+				title:   "Requests of the top clients by tenant",
+				metric:  "radosgw_requests_per_ip",
+				expr:    topRate("radosgw_requests_per_ip", "tenant, ip"),
+				legend:  "{{tenant}} {{ip}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByIPPerTenant },
+			},
+			{
+				title:   "Requests of the top clients by bucket",
+				metric:  "radosgw_requests_by_ip_bucket_method_tenant",
+				expr:    topRate("radosgw_requests_by_ip_bucket_method_tenant", "tenant, bucket, ip"),
+				legend:  "{{tenant}}/{{bucket}} {{ip}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByIPBucketMethodTenant },
+			},
+			{
+				title:   "Client requests by tenant",
+				metric:  "radosgw_requests_per_tenant_from_ip",
+				expr:    rate("radosgw_requests_per_tenant_from_ip", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackRequestsByIPGlobalPerTenant },
+			},
+			{
+				title:   "Bytes sent to the top clients",
+				metric:  "radosgw_bytes_sent_by_ip",
+				expr:    topRate("radosgw_bytes_sent_by_ip", "ip"),
+				legend:  "{{ip}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentByIPDetailed },
+			},
+			{
+				title:   "Bytes received from the top clients",
+				metric:  "radosgw_bytes_received_by_ip",
+				expr:    topRate("radosgw_bytes_received_by_ip", "ip"),
+				legend:  "{{ip}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedByIPDetailed },
+			},
+			{
+				title:   "Bytes sent to the top clients by tenant",
+				metric:  "radosgw_bytes_sent_per_ip",
+				expr:    topRate("radosgw_bytes_sent_per_ip", "tenant, ip"),
+				legend:  "{{tenant}} {{ip}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentByIPPerTenant },
+			},
+			{
+				title:   "Bytes received from the top clients by tenant",
+				metric:  "radosgw_bytes_received_per_ip",
+				expr:    topRate("radosgw_bytes_received_per_ip", "tenant, ip"),
+				legend:  "{{tenant}} {{ip}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedByIPPerTenant },
+			},
+			{
+				title:   "Client bytes sent by tenant",
+				metric:  "radosgw_bytes_sent_per_tenant_from_ip",
+				expr:    rate("radosgw_bytes_sent_per_tenant_from_ip", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesSentByIPGlobalPerTenant },
+			},
+			{
+				title:   "Client bytes received by tenant",
+				metric:  "radosgw_bytes_received_per_tenant_from_ip",
+				expr:    rate("radosgw_bytes_received_per_tenant_from_ip", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "Bps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBytesReceivedByIPGlobalPerTenant },
+			},
+		},
+	},
+	{
+		title: "Latency",
+		panels: []opsLogPanel{
+			{
+				title:   "p99 latency by method",
+				metric:  "radosgw_requests_duration",
+				expr:    p99("radosgw_requests_duration", "method"),
+				legend:  "{{method}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyDetailed },
+			},
+			{
+				title:   "p99 latency of the slowest users",
+				metric:  "radosgw_requests_duration_per_user",
+				expr:    topP99("radosgw_requests_duration_per_user", "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyPerUser },
+			},
+			{
+				title:   "p99 latency of the slowest buckets",
+				metric:  "radosgw_requests_duration_per_bucket",
+				expr:    topP99("radosgw_requests_duration_per_bucket", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyPerBucket },
+			},
+			{
+				title:   "p99 latency by tenant",
+				metric:  "radosgw_requests_duration_per_tenant",
+				expr:    p99("radosgw_requests_duration_per_tenant", "tenant"),
+				legend:  "{{tenant}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyPerTenant },
+			},
+			{
+				title:   "p99 latency by method, all tenants",
+				metric:  "radosgw_requests_duration_per_method",
+				expr:    p99("radosgw_requests_duration_per_method", "method"),
+				legend:  "{{method}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyPerMethod },
+			},
+			{
+				title:   "p99 latency of the slowest buckets by method",
+				metric:  "radosgw_requests_duration_per_bucket_and_method",
+				expr:    topP99("radosgw_requests_duration_per_bucket_and_method", "tenant, bucket, method"),
+				legend:  "{{tenant}}/{{bucket}} {{method}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackLatencyPerBucketAndMethod },
+			},
+			{
+				title:   "Requests in flight of the busiest buckets",
+				metric:  "radosgw_bucket_requests_in_flight_max",
+				expr:    topGauge("radosgw_bucket_requests_in_flight_max", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "short",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBucketConcurrency },
+			},
+		},
+	},
+	{
+		title: "Bucket SLIs",
+		panels: []opsLogPanel{
+			{
+				title:  "Availability by operation",
+				metric: "radosgw_bucket_sli_requests_total",
+				expr: `sum by (operation) (rate(radosgw_bucket_sli_requests_total{status_class!="5xx"}[$__rate_interval]))` +
+					" / " + rate("radosgw_bucket_sli_requests_total", "operation"),
+				legend:  "{{operation}}",
+				unit:    "percentunit",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBucketSLO },
+			},
+			{
+				title:   "p99 latency by operation",
+				metric:  "radosgw_bucket_sli_request_duration_seconds",
+				expr:    p99("radosgw_bucket_sli_request_duration_seconds", "operation"),
+				legend:  "{{operation}}",
+				unit:    "s",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackBucketSLO },
+			},
+		},
+	},
+	{
+		title: "Bucket Tags",
+		panels: []opsLogPanel{
+			{
+				title:       "Requests by bucket tags",
+				description: "Requests by bucket owner and the tag_<tag> labels of --bucket-tags",
+				metric:      "radosgw_requests_by_bucket_tags",
+				expr:        topRateWithout("radosgw_requests_by_bucket_tags", "pod, instance, job"),
+				legend:      "__auto",
+				unit:        "reqps",
+				enabled:     func(c opslog.OpsLogConfig) bool { return c.BucketTagsKV != "" },
+			},
+			{
+				title:       "Bytes sent by bucket tags",
+				description: "Bytes sent by bucket owner and the tag_<tag> labels of --bucket-tags",
+				metric:      "radosgw_bytes_sent_by_bucket_tags",
+				expr:        topRateWithout("radosgw_bytes_sent_by_bucket_tags", "pod, instance, job"),
+				legend:      "__auto",
+				unit:        "Bps",
+				enabled:     func(c opslog.OpsLogConfig) bool { return c.BucketTagsKV != "" },
+			},
+			{
+				title:       "Bytes received by bucket tags",
+				description: "Bytes received by bucket owner and the tag_<tag> labels of --bucket-tags",
+				metric:      "radosgw_bytes_received_by_bucket_tags",
+				expr:        topRateWithout("radosgw_bytes_received_by_bucket_tags", "pod, instance, job"),
+				legend:      "__auto",
+				unit:        "Bps",
+				enabled:     func(c opslog.OpsLogConfig) bool { return c.BucketTagsKV != "" },
+			},
+		},
+	},
+	{
+		title: "Security",
+		panels: []opsLogPanel{
+			{
+				title:   "Denied requests of the top users",
+				metric:  "radosgw_security_denied_requests_by_user_total",
+				expr:    topRate("radosgw_security_denied_requests_by_user_total", "tenant, user, error_code"),
+				legend:  "{{tenant}}/{{user}} {{error_code}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackSecurity },
+			},
+			{
+				title:   "Denied requests of the top clients",
+				metric:  "radosgw_security_denied_requests_by_ip_total",
+				expr:    topRate("radosgw_security_denied_requests_by_ip_total", "ip"),
+				legend:  "{{ip}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackSecurity },
+			},
+			{
+				title:   "Denied requests of the top buckets",
+				metric:  "radosgw_security_denied_requests_by_bucket_total",
+				expr:    topRate("radosgw_security_denied_requests_by_bucket_total", "tenant, bucket"),
+				legend:  "{{tenant}}/{{bucket}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackSecurity },
+			},
+			{
+				title:   "Anonymous requests of the top buckets",
+				metric:  "radosgw_security_anonymous_requests_total",
+				expr:    topRate("radosgw_security_anonymous_requests_total", "tenant, bucket, status_class"),
+				legend:  "{{tenant}}/{{bucket}} {{status_class}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackSecurity },
+			},
+			{
+				title:   "Requests by auth method",
+				metric:  "radosgw_requests_by_auth_method_total",
+				expr:    rate("radosgw_requests_by_auth_method_total", "auth_type, signature"),
+				legend:  "{{auth_type}} {{signature}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackAuthMethods },
+			},
+			{
+				title:   "Users signing with version 2",
+				metric:  "radosgw_requests_by_auth_method_total",
+				expr:    topRate(`radosgw_requests_by_auth_method_total{signature="v2"}`, "tenant, user"),
+				legend:  "{{tenant}}/{{user}}",
+				unit:    "reqps",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.MetricsConfig.TrackAuthMethods },
+			},
+		},
+	},
+	{
+		title: "Sinks",
+		panels: []opsLogPanel{
+			{
+				title:   "Dropped audit events",
+				metric:  "prysm_audit_events_dropped_total",
+				expr:    rate("prysm_audit_events_dropped_total", "reason"),
+				legend:  "{{reason}}",
+				unit:    "ops",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.AuditSink.Enabled },
+			},
+			{
+				title:   "Loki entries",
+				metric:  "prysm_loki_entries_total",
+				expr:    rate("prysm_loki_entries_total", "result"),
+				legend:  "{{result}}",
+				unit:    "ops",
+				enabled: func(c opslog.OpsLogConfig) bool { return c.LokiSink.URL != "" },
+			},
+		},
+	},
+}
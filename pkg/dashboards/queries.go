@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboards
+
+import "fmt"
+
+// topSeries is the number of series of panels that would otherwise show one
+// per user, bucket or client
+const topSeries = 10
+
+// rate sums the per-second rate of a counter by the labels
+func rate(metric, by string) string {
+	return fmt.Sprintf("sum by (%s) (rate(%s[$__rate_interval]))", by, metric)
+}
+
+// topRate is rate limited to the series with the highest rates
+func topRate(metric, by string) string {
+	return fmt.Sprintf("topk(%d, %s)", topSeries, rate(metric, by))
+}
+
+// topRateWithout is topRate summed by all labels but the given ones, for
+// counters whose labels depend on the configuration
+func topRateWithout(metric, without string) string {
+	return fmt.Sprintf("topk(%d, sum without (%s) (rate(%s[$__rate_interval])))", topSeries, without, metric)
+}
+
+// p99 is the 99th percentile of a histogram by the labels
+func p99(metric, by string) string {
+	return fmt.Sprintf("histogram_quantile(0.99, sum by (le, %s) (rate(%s_bucket[$__rate_interval])))", by, metric)
+}
+
+// topP99 is p99 limited to the slowest series
+func topP99(metric, by string) string {
+	return fmt.Sprintf("topk(%d, %s)", topSeries, p99(metric, by))
+}
+
+// gauge sums a gauge by the labels
+func gauge(metric, by string) string {
+	return fmt.Sprintf("sum by (%s) (%s)", by, metric)
+}
+
+// topGauge is gauge limited to the highest series
+func topGauge(metric, by string) string {
+	return fmt.Sprintf("topk(%d, %s)", topSeries, gauge(metric, by))
+}
+
+// bottomGauge is gauge limited to the lowest series
+func bottomGauge(metric, by string) string {
+	return fmt.Sprintf("bottomk(%d, %s)", topSeries, gauge(metric, by))
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboards
+
+import "github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+
+type radosGWUsagePanel = panel[radosgwusage.RadosGWUsageConfig]
+
+// radosGWUsageSections are the panels of the radosgw-usage metrics. All but
+// the access and API traffic metrics are always exported.
+var radosGWUsageSections = []section[radosgwusage.RadosGWUsageConfig]{
+	{
+		title: "Exporter",
+		panels: []radosGWUsagePanel{
+			{
+				title:  "Admin API reachable",
+				metric: "prysm_target_up",
+				expr:   gauge("prysm_target_up", "instance"),
+				legend: "{{instance}}",
+			},
+			{
+				title:  "Scrape errors",
+				metric: "exporter_scrape_errors_total",
+				expr:   rate("exporter_scrape_errors_total", "instance"),
+				legend: "{{instance}}",
+				unit:   "ops",
+			},
+			{
+				title:  "Time since the last sync",
+				metric: "radosgw_usage_last_sync_timestamp_seconds",
+				expr:   "time() - max by (instance_id) (radosgw_usage_last_sync_timestamp_seconds)",
+				legend: "{{instance_id}}",
+				unit:   "s",
+			},
+		},
+	},
+	{
+		title: "Users",
+		panels: []radosGWUsagePanel{
+			{
+				title:  "Data of the top users",
+				metric: "radosgw_user_data_size_bytes",
+				expr:   topGauge("radosgw_user_data_size_bytes", "user"),
+				legend: "{{user}}",
+				unit:   "bytes",
+			},
+			{
+				title:  "Objects of the top users",
+				metric: "radosgw_user_objects_total",
+				expr:   topGauge("radosgw_user_objects_total", "user"),
+				legend: "{{user}}",
+				unit:   "short",
+			},
+			{
+				title:  "Buckets of the top users",
+				metric: "radosgw_user_buckets_total",
+				expr:   topGauge("radosgw_user_buckets_total", "user"),
+				legend: "{{user}}",
+				unit:   "short",
+			},
+			{
+				title:  "Users with quota",
+				metric: "radosgw_usage_user_quota_enabled",
+				expr:   gauge("radosgw_usage_user_quota_enabled", "rgw_cluster_id"),
+				legend: "{{rgw_cluster_id}}",
+				unit:   "short",
+			},
+		},
+	},
+	{
+		title: "Buckets",
+		panels: []radosGWUsagePanel{
+			{
+				title:  "Size of the top buckets",
+				metric: "radosgw_usage_bucket_size",
+				expr:   topGauge("radosgw_usage_bucket_size", "owner, bucket"),
+				legend: "{{owner}}/{{bucket}}",
+				unit:   "bytes",
+			},
+			{
+				title:  "Objects of the top buckets",
+				metric: "radosgw_usage_bucket_objects",
+				expr:   topGauge("radosgw_usage_bucket_objects", "owner, bucket"),
+				legend: "{{owner}}/{{bucket}}",
+				unit:   "short",
+			},
+			{
+				title:  "Objects per shard of the top buckets",
+				metric: "radosgw_usage_bucket_objects_per_shard",
+				expr:   topGauge("radosgw_usage_bucket_objects_per_shard", "owner, bucket"),
+				legend: "{{owner}}/{{bucket}}",
+				unit:   "short",
+			},
+			{
+				title:  "Buckets to reshard",
+				metric: "radosgw_usage_bucket_reshard_recommended",
+				expr:   gauge("radosgw_usage_bucket_reshard_recommended", "rgw_cluster_id"),
+				legend: "{{rgw_cluster_id}}",
+				unit:   "short",
+			},
+			{
+				title:  "Buckets with quota",
+				metric: "radosgw_usage_bucket_quota_enabled",
+				expr:   gauge("radosgw_usage_bucket_quota_enabled", "rgw_cluster_id"),
+				legend: "{{rgw_cluster_id}}",
+				unit:   "short",
+			},
+		},
+	},
+	{
+		title: "Bucket Access",
+		panels: []radosGWUsagePanel{
+			{
+				title:   "Buckets by access",
+				metric:  "radosgw_usage_buckets_with_access",
+				expr:    gauge("radosgw_usage_buckets_with_access", "access"),
+				legend:  "{{access}}",
+				unit:    "short",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.AuditBucketAccess },
+			},
+			{
+				title:   "Public buckets",
+				metric:  "radosgw_usage_bucket_access",
+				expr:    `sum by (owner, bucket, access) (radosgw_usage_bucket_access{access=~"public_read|public_write"}) > 0`,
+				legend:  "{{owner}}/{{bucket}} {{access}}",
+				unit:    "short",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.AuditBucketAccess },
+			},
+		},
+	},
+	{
+		title: "API Traffic",
+		panels: []radosGWUsagePanel{
+			{
+				title:   "Requests of the top users",
+				metric:  "radosgw_usage_user_api_requests",
+				expr:    topGauge("radosgw_usage_user_api_requests", "user, category"),
+				legend:  "{{user}} {{category}}",
+				unit:    "short",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.OpsMetricsJoin },
+			},
+			{
+				title:   "Slowest users",
+				metric:  "radosgw_usage_user_api_latency_seconds",
+				expr:    topGauge("radosgw_usage_user_api_latency_seconds", "user, category"),
+				legend:  "{{user}} {{category}}",
+				unit:    "s",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.OpsMetricsJoin },
+			},
+			{
+				title:   "Requests of the top buckets",
+				metric:  "radosgw_usage_bucket_api_requests",
+				expr:    topGauge("radosgw_usage_bucket_api_requests", "owner, bucket, category"),
+				legend:  "{{owner}}/{{bucket}} {{category}}",
+				unit:    "short",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.OpsMetricsJoin },
+			},
+			{
+				title:   "Slowest buckets",
+				metric:  "radosgw_usage_bucket_api_latency_seconds",
+				expr:    topGauge("radosgw_usage_bucket_api_latency_seconds", "owner, bucket, category"),
+				legend:  "{{owner}}/{{bucket}} {{category}}",
+				unit:    "s",
+				enabled: func(c radosgwusage.RadosGWUsageConfig) bool { return c.OpsMetricsJoin },
+			},
+		},
+	},
+}
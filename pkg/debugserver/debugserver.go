@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package debugserver exposes net/http/pprof profiles and Go runtime
+// statistics on a dedicated, opt-in HTTP port so memory and CPU issues in a
+// running producer can be profiled live, e.g.:
+//
+//	go tool pprof http://<pod>:<debug-port>/debug/pprof/heap
+//
+// It also switches the read-only maintenance mode of the producer:
+//
+//	curl -X PUT 'http://<pod>:<debug-port>/maintenance/read-only?enabled=true'
+//
+// and serves the endpoints producers register with Handle, e.g. the slow
+// requests captured by ops-log. All endpoints are served with the access
+// control of the metrics server.
+package debugserver
+
+import (
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+// Endpoints of the producers, registered with Handle
+var handlers = http.NewServeMux()
+
+// Handle registers the handler of a producer for the pattern, served by the
+// debug server besides the built-in endpoints. It may be called after Start.
+func Handle(pattern string, handler http.Handler) {
+	handlers.Handle(pattern, handler)
+}
+
+// NewMux returns a ServeMux serving the pprof endpoints under /debug/pprof/,
+// the expvar runtime statistics (memstats, cmdline) under /debug/vars and
+// the read-only maintenance mode on /maintenance/read-only, and the endpoints
+// of producers, the ones registered with Handle for the debug server. A
+// dedicated mux keeps these handlers independent of the metrics port.
+func NewMux(producers *http.ServeMux) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("/maintenance/read-only", natsutil.ReadOnlyHandler())
+	mux.Handle("/", producers)
+	return mux
+}
+
+// DefaultAddress is the address the debug server listens on, reachable with
+// kubectl port-forward but not from other pods, as the profiles and the
+// command line may reveal secrets
+const DefaultAddress = "127.0.0.1"
+
+// handler is NewMux of the endpoints registered with Handle behind the access
+// control of the metrics server, so the maintenance mode is switched with the
+// credentials of --metrics-bearer-token or --metrics-basic-auth-username
+func handler() http.Handler {
+	return telemetry.GuardMetricsAccess(NewMux(handlers))
+}
+
+// Start serves handler on the given address and port in the background, all
+// interfaces if the address is empty. A port of zero or less leaves the
+// debug server disabled.
+func Start(address string, port int) {
+	if port <= 0 {
+		return
+	}
+
+	addr := net.JoinHostPort(address, strconv.Itoa(port))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Msgf("starting debug server on %s", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("error starting debug server")
+		}
+	}()
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package debugserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMux(t *testing.T) {
+	// A mux of its own, registering a pattern twice panics with -count=2
+	producers := http.NewServeMux()
+	producers.Handle("/debug/test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("registered"))
+	}))
+	mux := NewMux(producers)
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder
+	}
+
+	response := serve(http.MethodGet, "/debug/pprof/")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "heap")
+
+	response = serve(http.MethodGet, "/debug/vars")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"memstats"`)
+
+	response = serve(http.MethodGet, "/maintenance/read-only")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"read_only":false}`, response.Body.String())
+
+	response = serve(http.MethodGet, "/debug/test")
+	assert.Equal(t, "registered", response.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/unknown").Code)
+}
+
+func TestHandler_MetricsAccess(t *testing.T) {
+	telemetry.ConfigureMetricsAccess(telemetry.MetricsAccess{BearerToken: "t0ken"})
+	defer telemetry.ConfigureMetricsAccess(telemetry.MetricsAccess{})
+	defer natsutil.SetReadOnly(false)
+	h := handler()
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/maintenance/read-only?enabled=true", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.False(t, natsutil.ReadOnly())
+
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	request := httptest.NewRequest(http.MethodPut, "/maintenance/read-only?enabled=true", nil)
+	request.Header.Set("Authorization", "Bearer t0ken")
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, natsutil.ReadOnly())
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package doctor
+
+import "os"
+
+// readable checks that path can be opened for reading, there is no access(2)
+// on this platform
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writable checks that path can be opened for writing, for a directory that
+// a file can be created in it
+func writable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		f, err := os.CreateTemp(path, ".prysm-doctor-*")
+		if err != nil {
+			return err
+		}
+		f.Close()
+		return os.Remove(f.Name())
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writableDir checks that a file can be created in dir
+func writableDir(dir string) error {
+	return writable(dir)
+}
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package doctor
+
+import "golang.org/x/sys/unix"
+
+// readable checks that the user of the process may read path
+func readable(path string) error {
+	return unix.Access(path, unix.R_OK)
+}
+
+// writable checks that the user of the process may write path, for a
+// directory to create files in it
+func writable(path string) error {
+	return unix.Access(path, unix.W_OK)
+}
+
+// writableDir checks that the user of the process may create files in dir
+// and open them
+func writableDir(dir string) error {
+	return unix.Access(dir, unix.W_OK|unix.X_OK)
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+)
+
+// checkAdminAPI calls the admin API endpoints radosgw-usage reads, to find
+// wrong credentials and missing capabilities
+func checkAdminAPI(ctx context.Context, cfg Config) Result {
+	if cfg.AdminURL == "" {
+		return skip("no admin URL configured")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return fail("access key or secret key not set",
+			"set --access-key and --secret-key, or ACCESS_KEY and SECRET_KEY, to the keys of the admin user")
+	}
+
+	api, err := rgwadmin.New(cfg.AdminURL, cfg.AccessKey, cfg.SecretKey, &http.Client{Timeout: cfg.Timeout})
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	noEntries := false
+	probes := []struct {
+		capability string
+		call       func() error
+	}{
+		{"metadata=read", func() error { _, err := api.GetUsers(ctx); return err }},
+		{"buckets=read", func() error { _, err := api.ListBuckets(ctx); return err }},
+		{"usage=read", func() error {
+			_, err := api.GetUsage(ctx, rgwadmin.Usage{ShowEntries: &noEntries, ShowSummary: &noEntries})
+			return err
+		}},
+	}
+	var missing []string
+	for _, probe := range probes {
+		err := probe.call()
+		switch {
+		case err == nil:
+		case errors.Is(err, rgwadmin.ErrInvalidAccessKeyID), errors.Is(err, rgwadmin.ErrSignatureDoesNotMatch):
+			return fail(fmt.Sprintf("%s rejects the credentials: %v", cfg.AdminURL, err),
+				"check the access and secret key: radosgw-admin user info --uid=<admin user>")
+		case errors.Is(err, rgwadmin.ErrAccessDenied):
+			missing = append(missing, probe.capability)
+		default:
+			return fail(fmt.Sprintf("cannot call the admin API at %s: %v", cfg.AdminURL, err),
+				"check the URL, it points to an RGW endpoint, e.g. http://rook-ceph-rgw-my-store.rook-ceph.svc",
+				"check that the endpoint is reachable from here")
+		}
+	}
+	if len(missing) > 0 {
+		caps := strings.Join(missing, ";")
+		return fail("the admin user lacks "+strings.Join(missing, ", "),
+			fmt.Sprintf("radosgw-admin caps add --uid=<admin user> --caps=%q", caps),
+			"with Rook, add them to spec.capabilities of the CephObjectStoreUser")
+	}
+	return ok("%s accepts the credentials, the admin user can read users, buckets and usage", cfg.AdminURL)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// smartctl writes JSON since 7.0
+const minSmartctlMajor = 7
+
+// Capabilities smartctl and nvme-cli need without root
+const (
+	capSysRawIO = 17
+	capSysAdmin = 21
+)
+
+var smartctlVersion = regexp.MustCompile(`^smartctl (\d+)\.(\d+)`)
+
+// checkSmartctl checks the smartctl version and that it finds devices
+func checkSmartctl(ctx context.Context, _ Config) Result {
+	if _, err := lookPath("smartctl"); err != nil {
+		return fail("smartctl not found in PATH",
+			"install smartmontools 7.0 or later, the prysm image contains it")
+	}
+
+	out, err := commandOutput(ctx, "smartctl", "--version")
+	match := smartctlVersion.FindSubmatch(out)
+	if err != nil || match == nil {
+		return fail(fmt.Sprintf("cannot read the smartctl version: %v", err),
+			"check that 'smartctl --version' runs")
+	}
+	version := string(match[1]) + "." + string(match[2])
+	if major, _ := strconv.Atoi(string(match[1])); major < minSmartctlMajor {
+		return fail("smartctl "+version+" cannot write JSON",
+			"install smartmontools 7.0 or later")
+	}
+
+	// --scan-open exits non-zero if a device cannot be opened, the JSON
+	// output still lists the others
+	out, _ = commandOutput(ctx, "smartctl", "--scan-open", "-j")
+	scan := struct {
+		Devices []json.RawMessage `json:"devices"`
+	}{}
+	if err := json.Unmarshal(out, &scan); err != nil {
+		return warn(fmt.Sprintf("smartctl %s, but its device scan failed: %v", version, err),
+			"run 'smartctl --scan-open -j' to see why")
+	}
+	if len(scan.Devices) == 0 {
+		return warn("smartctl "+version+" finds no devices",
+			"run the container privileged with the host /dev mounted",
+			"or list the devices with --disks")
+	}
+	return ok("smartctl %s finds %d devices", version, len(scan.Devices))
+}
+
+// checkNvmeCLI checks for nvme-cli, optional for NVMe telemetry
+func checkNvmeCLI(ctx context.Context, _ Config) Result {
+	if _, err := lookPath("nvme"); err != nil {
+		return warn("nvme not found in PATH, NVMe telemetry and vendor logs are not collected",
+			"install nvme-cli, the prysm image contains it")
+	}
+	out, err := commandOutput(ctx, "nvme", "version")
+	if err != nil {
+		return warn(fmt.Sprintf("cannot run nvme: %v", err), "check that 'nvme version' runs")
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return ok("%s", line)
+}
+
+// checkPrivileges checks that the process may send commands to disks
+func checkPrivileges(_ context.Context, _ Config) Result {
+	if os.Geteuid() == 0 {
+		return ok("running as root")
+	}
+	caps, err := effectiveCapabilities("/proc/self/status")
+	if err != nil {
+		return warn(fmt.Sprintf("not running as root and cannot read capabilities: %v", err),
+			"run the disk-health-metrics producer as root")
+	}
+	var missing []string
+	if caps&(1<<capSysRawIO) == 0 {
+		missing = append(missing, "CAP_SYS_RAWIO")
+	}
+	if caps&(1<<capSysAdmin) == 0 {
+		missing = append(missing, "CAP_SYS_ADMIN")
+	}
+	if len(missing) > 0 {
+		return fail(fmt.Sprintf("running as uid %d without %s, SMART data cannot be read", os.Geteuid(), strings.Join(missing, " and ")),
+			"run the container privileged, or as root",
+			"or add the capabilities: securityContext.capabilities.add: [SYS_RAWIO, SYS_ADMIN]")
+	}
+	return ok("running as uid %d with CAP_SYS_RAWIO and CAP_SYS_ADMIN", os.Geteuid())
+}
+
+// effectiveCapabilities reads the CapEff mask from a /proc status file
+func effectiveCapabilities(path string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), "CapEff:"); found {
+			return strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no CapEff in %s", path)
+}
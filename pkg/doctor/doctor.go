@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package doctor checks the prerequisites of the producers on the host it
+// runs on and explains how to fix what is missing.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Producers with prerequisites to check
+const (
+	ProducerOpsLog            = "ops-log"
+	ProducerDiskHealthMetrics = "disk-health-metrics"
+	ProducerRadosGWUsage      = "radosgw-usage"
+)
+
+// Producers lists the producers in the order they are checked
+var Producers = []string{ProducerOpsLog, ProducerDiskHealthMetrics, ProducerRadosGWUsage}
+
+// Checks of what all producers use, run with any selection
+const common = "common"
+
+const DefaultTimeout = 5 * time.Second
+
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn" // works, but not as well as it could
+	StatusFail Status = "fail" // the producer will not work
+	StatusSkip Status = "skip" // nothing configured to check
+)
+
+// Config selects the producers to check and what they would be started
+// with; checks of unset options are skipped
+type Config struct {
+	Producers   []string // all if empty
+	CephBinary  string   // ceph CLI, "ceph" if empty
+	CephEntity  string   // RGW config section, "client.rgw" if empty
+	LogFilePath string
+	SocketPath  string
+	NatsURL     string
+	AdminURL    string
+	AccessKey   string
+	SecretKey   string
+	Timeout     time.Duration // of every check, DefaultTimeout if zero
+}
+
+// Result of a check
+type Result struct {
+	Producer    string   `json:"producer"`
+	Check       string   `json:"check"`
+	Status      Status   `json:"status"`
+	Message     string   `json:"message"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// A check runs with the configuration and reports its result
+type check struct {
+	producer string
+	name     string
+	run      func(ctx context.Context, cfg Config) Result
+}
+
+// commandOutput runs a command and returns its combined output, replaced
+// in tests
+var commandOutput = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// lookPath finds a command in PATH, replaced in tests
+var lookPath = exec.LookPath
+
+func checks() []check {
+	return []check{
+		{ProducerOpsLog, "ceph ops log", checkCephOpsLog},
+		{ProducerOpsLog, "ops log file", checkLogFile},
+		{ProducerOpsLog, "ops log socket", checkSocket},
+		{ProducerDiskHealthMetrics, "smartctl", checkSmartctl},
+		{ProducerDiskHealthMetrics, "nvme-cli", checkNvmeCLI},
+		{ProducerDiskHealthMetrics, "privileges", checkPrivileges},
+		{ProducerRadosGWUsage, "admin API", checkAdminAPI},
+		{common, "nats", checkNats},
+	}
+}
+
+// Run runs the checks of the selected producers
+func Run(ctx context.Context, cfg Config) ([]Result, error) {
+	for _, producer := range cfg.Producers {
+		if !slices.Contains(Producers, producer) {
+			return nil, fmt.Errorf("unknown producer %q, expected one of %s", producer, strings.Join(Producers, ", "))
+		}
+	}
+	if cfg.CephBinary == "" {
+		cfg.CephBinary = "ceph"
+	}
+	if cfg.CephEntity == "" {
+		cfg.CephEntity = "client.rgw"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	results := []Result{}
+	for _, c := range checks() {
+		if c.producer != common && len(cfg.Producers) > 0 && !slices.Contains(cfg.Producers, c.producer) {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		result := c.run(checkCtx, cfg)
+		cancel()
+		result.Producer, result.Check = c.producer, c.name
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Failed counts the results that stop a producer from working
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status == StatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Print writes the results grouped by producer, with the remediation
+// steps below every check that did not pass
+func Print(w io.Writer, results []Result) {
+	producer := ""
+	for _, result := range results {
+		if result.Producer != producer {
+			if producer != "" {
+				fmt.Fprintln(w)
+			}
+			producer = result.Producer
+			fmt.Fprintln(w, producer)
+		}
+		fmt.Fprintf(w, "  [%-4s] %s: %s\n", strings.ToUpper(string(result.Status)), result.Check, result.Message)
+		for _, step := range result.Remediation {
+			fmt.Fprintf(w, "         - %s\n", step)
+		}
+	}
+}
+
+func ok(format string, args ...any) Result {
+	return Result{Status: StatusOK, Message: fmt.Sprintf(format, args...)}
+}
+
+func skip(format string, args ...any) Result {
+	return Result{Status: StatusSkip, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(message string, remediation ...string) Result {
+	return Result{Status: StatusWarn, Message: message, Remediation: remediation}
+}
+
+func fail(message string, remediation ...string) Result {
+	return Result{Status: StatusFail, Message: message, Remediation: remediation}
+}
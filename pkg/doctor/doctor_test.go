@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommands answers commands from outputs keyed by the command line;
+// commands without an output are not found
+func fakeCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	origOutput, origLookPath := commandOutput, lookPath
+	t.Cleanup(func() { commandOutput, lookPath = origOutput, origLookPath })
+
+	commandOutput = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		out, found := outputs[strings.Join(append([]string{name}, args...), " ")]
+		if !found {
+			return []byte("unknown option"), errors.New("exit status 1")
+		}
+		return []byte(out), nil
+	}
+	lookPath = func(name string) (string, error) {
+		for command := range outputs {
+			if strings.HasPrefix(command, name+" ") {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestCheckCephOpsLog(t *testing.T) {
+	cfg := Config{CephBinary: "ceph", CephEntity: "client.rgw", SocketPath: "/var/run/prysm/ops-log.sock"}
+
+	fakeCommands(t, map[string]string{
+		"ceph config get client.rgw rgw_enable_ops_log":      "true\n",
+		"ceph config get client.rgw rgw_ops_log_socket_path": "/var/run/prysm/ops-log.sock\n",
+	})
+	assert.Equal(t, StatusOK, checkCephOpsLog(context.Background(), cfg).Status)
+
+	fakeCommands(t, map[string]string{
+		"ceph config get client.rgw rgw_enable_ops_log":      "true\n",
+		"ceph config get client.rgw rgw_ops_log_socket_path": "\n",
+	})
+	result := checkCephOpsLog(context.Background(), cfg)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Contains(t, result.Remediation, "ceph config set client.rgw rgw_ops_log_socket_path /var/run/prysm/ops-log.sock")
+
+	fakeCommands(t, map[string]string{"ceph config get client.rgw rgw_enable_ops_log": "false\n"})
+	result = checkCephOpsLog(context.Background(), cfg)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, []string{"ceph config set client.rgw rgw_enable_ops_log true"}, result.Remediation)
+
+	fakeCommands(t, map[string]string{})
+	assert.Equal(t, StatusWarn, checkCephOpsLog(context.Background(), cfg).Status)
+}
+
+func TestCheckSmartctl(t *testing.T) {
+	fakeCommands(t, map[string]string{
+		"smartctl --version":      "smartctl 7.3 2022-02-28 r5338 [x86_64-linux-6.1.0] (local build)\n",
+		"smartctl --scan-open -j": `{"devices":[{"name":"/dev/sda"},{"name":"/dev/nvme0"}]}`,
+	})
+	result := checkSmartctl(context.Background(), Config{})
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Equal(t, "smartctl 7.3 finds 2 devices", result.Message)
+
+	fakeCommands(t, map[string]string{
+		"smartctl --version":      "smartctl 7.3 2022-02-28 r5338\n",
+		"smartctl --scan-open -j": `{"devices":[]}`,
+	})
+	assert.Equal(t, StatusWarn, checkSmartctl(context.Background(), Config{}).Status)
+
+	fakeCommands(t, map[string]string{"smartctl --version": "smartctl 6.6 2017-11-05 r4594\n"})
+	assert.Equal(t, StatusFail, checkSmartctl(context.Background(), Config{}).Status)
+
+	fakeCommands(t, map[string]string{})
+	assert.Equal(t, StatusFail, checkSmartctl(context.Background(), Config{}).Status)
+	assert.Equal(t, StatusWarn, checkNvmeCLI(context.Background(), Config{}).Status)
+}
+
+func TestEffectiveCapabilities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	require.NoError(t, os.WriteFile(path, []byte("Name:\tprysm\nCapPrm:\t0000000000000000\nCapEff:\t0000000000220000\n"), 0o644))
+
+	caps, err := effectiveCapabilities(path)
+	require.NoError(t, err)
+	assert.NotZero(t, caps&(1<<capSysRawIO))
+	assert.NotZero(t, caps&(1<<capSysAdmin))
+}
+
+func TestCheckLogFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{CephEntity: "client.rgw", LogFilePath: filepath.Join(dir, "ops-log.log")}
+
+	assert.Equal(t, StatusFail, checkLogFile(context.Background(), cfg).Status)
+
+	require.NoError(t, os.WriteFile(cfg.LogFilePath, []byte("{}\n"), 0o644))
+	assert.Equal(t, StatusOK, checkLogFile(context.Background(), cfg).Status)
+
+	assert.Equal(t, StatusSkip, checkLogFile(context.Background(), Config{}).Status)
+}
+
+func TestCheckSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "doctor")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	cfg := Config{SocketPath: filepath.Join(dir, "ops-log.sock")}
+	result := checkSocket(context.Background(), cfg)
+	assert.Contains(t, []Status{StatusOK, StatusWarn}, result.Status, result.Message)
+
+	// A socket in use is fine, the producer replaces it
+	listener, err := net.Listen("unix", cfg.SocketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.NotEqual(t, StatusFail, checkSocket(context.Background(), cfg).Status)
+
+	cfg.SocketPath = filepath.Join(dir, "ops-log.log")
+	require.NoError(t, os.WriteFile(cfg.SocketPath, nil, 0o644))
+	assert.Equal(t, StatusFail, checkSocket(context.Background(), cfg).Status)
+
+	cfg.SocketPath = filepath.Join(dir, "missing", "ops-log.sock")
+	assert.Equal(t, StatusFail, checkSocket(context.Background(), cfg).Status)
+
+	cfg.SocketPath = "/" + strings.Repeat("a", maxSocketPath)
+	assert.Equal(t, StatusFail, checkSocket(context.Background(), cfg).Status)
+}
+
+func TestCheckNats(t *testing.T) {
+	assert.Equal(t, StatusSkip, checkNats(context.Background(), Config{}).Status)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	result := checkNats(context.Background(), Config{NatsURL: "nats://" + addr, Timeout: DefaultTimeout})
+	assert.Equal(t, StatusFail, result.Status)
+	assert.NotEmpty(t, result.Remediation)
+}
+
+func TestRun(t *testing.T) {
+	fakeCommands(t, map[string]string{})
+
+	results, err := Run(context.Background(), Config{Producers: []string{ProducerOpsLog}})
+	require.NoError(t, err)
+	var checks []string
+	for _, result := range results {
+		checks = append(checks, result.Producer+"/"+result.Check)
+	}
+	assert.Equal(t, []string{"ops-log/ceph ops log", "ops-log/ops log file", "ops-log/ops log socket", "common/nats"}, checks)
+	assert.Zero(t, Failed(results))
+
+	_, err = Run(context.Background(), Config{Producers: []string{"kernel-metrics"}})
+	assert.ErrorContains(t, err, "unknown producer")
+}
+
+func TestPrint(t *testing.T) {
+	var out bytes.Buffer
+	Print(&out, []Result{
+		{Producer: ProducerOpsLog, Check: "ceph ops log", Status: StatusFail, Message: "rgw_enable_ops_log is \"false\" for client.rgw", Remediation: []string{"ceph config set client.rgw rgw_enable_ops_log true"}},
+		{Producer: ProducerOpsLog, Check: "ops log file", Status: StatusSkip, Message: "no ops log file configured"},
+		{Producer: common, Check: "nats", Status: StatusOK, Message: "connected"},
+	})
+	assert.Equal(t, `ops-log
+  [FAIL] ceph ops log: rgw_enable_ops_log is "false" for client.rgw
+         - ceph config set client.rgw rgw_enable_ops_log true
+  [SKIP] ops log file: no ops log file configured
+
+common
+  [OK  ] nats: connected
+`, out.String())
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+)
+
+// checkNats connects to NATS with the configured credentials and TLS, and
+// checks for JetStream, which the key-value buckets and streams need
+func checkNats(ctx context.Context, cfg Config) Result {
+	if cfg.NatsURL == "" {
+		return skip("no NATS URL configured")
+	}
+
+	timeout := cfg.Timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	nc, err := natsutil.Connect(cfg.NatsURL, nats.Timeout(timeout), nats.NoReconnect())
+	if err != nil {
+		return fail(fmt.Sprintf("cannot connect to %s: %v", cfg.NatsURL, err),
+			"check the URL and that the NATS port, 4222 by default, is reachable from here",
+			"if the server requires authentication or TLS, set --nats-creds and --nats-tls-ca, --nats-tls-cert, --nats-tls-key")
+	}
+	defer nc.Close()
+
+	js, err := nc.JetStream(nats.Context(ctx))
+	if err == nil {
+		_, err = js.AccountInfo(nats.Context(ctx))
+	}
+	if err != nil {
+		return warn(fmt.Sprintf("connected to %s, but JetStream is not available: %v", nc.ConnectedUrlRedacted(), err),
+			"enable JetStream on the NATS server (jetstream: enabled), the radosgw-usage sync, the disk health history and the ops heatmaps use it")
+	}
+	return ok("connected to %s, JetStream is available", nc.ConnectedUrlRedacted())
+}
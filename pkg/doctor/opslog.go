@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSocketPath is the longest path of a Unix socket on Linux, without the
+// terminating NUL
+const maxSocketPath = 107
+
+// checkCephOpsLog reads the ops log options of RGW with the ceph CLI
+func checkCephOpsLog(ctx context.Context, cfg Config) Result {
+	if _, err := lookPath(cfg.CephBinary); err != nil {
+		return warn(fmt.Sprintf("%s not found, cannot read the RGW configuration", cfg.CephBinary),
+			"run prysm doctor where the ceph CLI and a keyring are available, e.g. in the rook-ceph-tools pod",
+			"or check by hand: ceph config get "+cfg.CephEntity+" rgw_enable_ops_log")
+	}
+
+	enabled, err := cephConfigGet(ctx, cfg, "rgw_enable_ops_log")
+	if err != nil {
+		return warn(fmt.Sprintf("cannot read the RGW configuration: %v", err),
+			"check that /etc/ceph/ceph.conf and a keyring allowing 'ceph config get' are available",
+			"choose the config section of the RGW daemons with --ceph-entity, e.g. client.rgw.my.store.a")
+	}
+	if enabled != "true" {
+		return fail(fmt.Sprintf("rgw_enable_ops_log is %q for %s", enabled, cfg.CephEntity),
+			fmt.Sprintf("ceph config set %s rgw_enable_ops_log true", cfg.CephEntity))
+	}
+
+	// RGW writes the ops log where the producer reads it
+	option, want := "rgw_ops_log_file_path", cfg.LogFilePath
+	if cfg.SocketPath != "" {
+		option, want = "rgw_ops_log_socket_path", cfg.SocketPath
+	}
+	if want == "" {
+		return ok("rgw_enable_ops_log is true for %s", cfg.CephEntity)
+	}
+	got, err := cephConfigGet(ctx, cfg, option)
+	if err != nil {
+		return warn(fmt.Sprintf("rgw_enable_ops_log is true, but %s cannot be read: %v", option, err),
+			fmt.Sprintf("check that RGW writes the ops log to %s", want))
+	}
+	if got != want {
+		return fail(fmt.Sprintf("%s is %q for %s, the producer reads %s", option, got, cfg.CephEntity, want),
+			fmt.Sprintf("ceph config set %s %s %s", cfg.CephEntity, option, want),
+			"restart the RGW daemons to apply it")
+	}
+	return ok("rgw_enable_ops_log is true and %s is %s for %s", option, got, cfg.CephEntity)
+}
+
+func cephConfigGet(ctx context.Context, cfg Config, option string) (string, error) {
+	out, err := commandOutput(ctx, cfg.CephBinary, "config", "get", cfg.CephEntity, option)
+	value := strings.TrimSpace(string(out))
+	if err != nil {
+		if value != "" {
+			return "", fmt.Errorf("%w: %s", err, value)
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+// checkLogFile checks that the ops log file can be read and rotated
+func checkLogFile(_ context.Context, cfg Config) Result {
+	if cfg.LogFilePath == "" || cfg.SocketPath != "" {
+		return skip("no ops log file configured")
+	}
+
+	info, err := os.Stat(cfg.LogFilePath)
+	switch {
+	case os.IsNotExist(err):
+		return fail(cfg.LogFilePath+" does not exist",
+			"mount the RGW log directory, /var/log/ceph with Rook, into the prysm container",
+			"check that RGW logs operations to this file: ceph config get "+cfg.CephEntity+" rgw_ops_log_file_path")
+	case err != nil:
+		return fail(err.Error())
+	case info.IsDir():
+		return fail(cfg.LogFilePath+" is a directory", "set --log-file to the ops log file inside it")
+	}
+
+	if err := readable(cfg.LogFilePath); err != nil {
+		return fail(fmt.Sprintf("%s is not readable: %v", cfg.LogFilePath, err),
+			"run prysm as the user RGW writes the log as, or make the file readable for it")
+	}
+	dir := filepath.Dir(cfg.LogFilePath)
+	if writable(cfg.LogFilePath) != nil || writable(dir) != nil {
+		return warn(fmt.Sprintf("%s is readable, but it or %s is not writable, the log cannot be rotated", cfg.LogFilePath, dir),
+			"mount the log directory read-write, or rotate the ops log outside of prysm")
+	}
+	return ok("%s is readable and can be rotated (%d bytes)", cfg.LogFilePath, info.Size())
+}
+
+// checkSocket checks that the producer can listen on the ops log socket
+func checkSocket(_ context.Context, cfg Config) Result {
+	if cfg.SocketPath == "" {
+		return skip("no ops log socket configured")
+	}
+	if len(cfg.SocketPath) > maxSocketPath {
+		return fail(fmt.Sprintf("%s is longer than %d bytes, the limit of Unix socket paths", cfg.SocketPath, maxSocketPath),
+			"choose a shorter --socket-path, e.g. /var/run/prysm/ops-log.sock")
+	}
+
+	dir := filepath.Dir(cfg.SocketPath)
+	dirInfo, err := os.Stat(dir)
+	if err != nil || !dirInfo.IsDir() {
+		return fail(dir+" does not exist",
+			"mount a volume shared with the RGW container at "+dir+", e.g. an emptyDir")
+	}
+	if err := writableDir(dir); err != nil {
+		return fail(fmt.Sprintf("%s is not writable: %v", dir, err),
+			"make "+dir+" writable for the prysm user, e.g. with fsGroup in the pod security context")
+	}
+	if info, err := os.Lstat(cfg.SocketPath); err == nil && info.Mode()&os.ModeSocket == 0 {
+		return fail(cfg.SocketPath+" exists and is not a socket, the producer would delete it on start",
+			"choose another --socket-path, or remove the file")
+	}
+
+	// Listen on a socket next to it, the one in use may belong to a running producer
+	probe := filepath.Join(dir, fmt.Sprintf(".prysm-doctor-%d.sock", os.Getpid()))
+	if len(probe) > maxSocketPath {
+		probe = cfg.SocketPath + "~"
+	}
+	listener, err := net.Listen("unix", probe)
+	if err != nil {
+		return fail(fmt.Sprintf("cannot listen on a socket in %s: %v", dir, err),
+			"use a local, writable filesystem for the socket, e.g. an emptyDir")
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(probe)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if info.Mode().Perm()&0o022 == 0 {
+		return warn(fmt.Sprintf("sockets in %s are created with mode %v, RGW can only connect if it runs as uid %d", dir, info.Mode().Perm(), os.Geteuid()),
+			"run prysm and RGW as the same user, e.g. runAsUser: 167 (ceph) for the sidecar")
+	}
+	return ok("%s can be created, sockets get mode %v", cfg.SocketPath, info.Mode().Perm())
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Difference is a field whose value deviates from the reference; nil is a
+// field the configuration does not have
+type Difference struct {
+	Field     string `json:"field"`
+	Reference any    `json:"reference"`
+	Value     any    `json:"value"`
+}
+
+// Deviation is a record whose configuration deviates from the reference
+type Deviation struct {
+	Name        string       `json:"name"`
+	Version     string       `json:"version"`
+	Differences []Difference `json:"differences"`
+}
+
+// Report compares the records of a producer with its reference
+type Report struct {
+	Producer   string      `json:"producer"`
+	Reference  string      `json:"reference"` // Name of the reference record
+	Records    int         `json:"records"`
+	Matching   int         `json:"matching"` // Records without differences, the reference included
+	Deviations []Deviation `json:"deviations"`
+}
+
+// Compare compares the records of every producer with a reference: the
+// record of the node named reference, or the most common configuration if
+// it is empty. Fields of ignore, e.g. Disks or MetricsConfig, are not
+// compared.
+func Compare(records []Record, reference string, ignore []string) ([]Report, error) {
+	byProducer := map[string][]Record{}
+	var producers []string
+	for _, record := range records {
+		if _, found := byProducer[record.Producer]; !found {
+			producers = append(producers, record.Producer)
+		}
+		byProducer[record.Producer] = append(byProducer[record.Producer], record)
+	}
+	sort.Strings(producers)
+
+	var reports []Report
+	for _, producer := range producers {
+		group := byProducer[producer]
+		ref, found := pickReference(group, reference, ignore)
+		if !found {
+			return nil, fmt.Errorf("no configuration of %s on %s", producer, reference)
+		}
+
+		report := Report{Producer: producer, Reference: ref.Name(), Records: len(group), Deviations: []Deviation{}}
+		for _, record := range group {
+			differences := Diff(ref.Config, record.Config, ignore)
+			if len(differences) == 0 {
+				report.Matching++
+				continue
+			}
+			report.Deviations = append(report.Deviations, Deviation{
+				Name:        record.Name(),
+				Version:     record.Version,
+				Differences: differences,
+			})
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// pickReference returns the record of the node named reference, or the
+// first record of the most common configuration
+func pickReference(group []Record, reference string, ignore []string) (Record, bool) {
+	if reference != "" {
+		for _, record := range group {
+			if record.Name() == reference || record.Node == reference {
+				return record, true
+			}
+		}
+		return Record{}, false
+	}
+
+	counts := map[string]int{}
+	fingerprints := make([]string, len(group))
+	for i, record := range group {
+		fingerprints[i] = fingerprint(record.Config, ignore)
+		counts[fingerprints[i]]++
+	}
+	best := 0
+	for i := range group {
+		if counts[fingerprints[i]] > counts[fingerprints[best]] {
+			best = i
+		}
+	}
+	return group[best], true
+}
+
+// fingerprint identifies the compared fields of a configuration
+func fingerprint(config map[string]any, ignore []string) string {
+	fields := map[string]any{}
+	for field, value := range flatten(config, "") {
+		if !ignored(field, ignore) {
+			fields[field] = value
+		}
+	}
+	data, _ := json.Marshal(fields)
+	return string(data)
+}
+
+// Diff returns the fields of value that deviate from reference, ordered by
+// field
+func Diff(reference, value map[string]any, ignore []string) []Difference {
+	ref, val := flatten(reference, ""), flatten(value, "")
+
+	var fields []string
+	for field := range ref {
+		fields = append(fields, field)
+	}
+	for field := range val {
+		if _, found := ref[field]; !found {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var differences []Difference
+	for _, field := range fields {
+		if ignored(field, ignore) || reflect.DeepEqual(ref[field], val[field]) {
+			continue
+		}
+		differences = append(differences, Difference{Field: field, Reference: ref[field], Value: val[field]})
+	}
+	return differences
+}
+
+// flatten returns the fields of nested objects by their dotted path, e.g.
+// MetricsConfig.TrackSecurity
+func flatten(config map[string]any, prefix string) map[string]any {
+	fields := map[string]any{}
+	for name, value := range config {
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			for field, v := range flatten(nested, prefix+name+".") {
+				fields[field] = v
+			}
+			continue
+		}
+		fields[prefix+name] = value
+	}
+	return fields
+}
+
+// ignored is true for the fields of ignore and the fields nested in them
+func ignored(field string, ignore []string) bool {
+	for _, i := range ignore {
+		if field == i || strings.HasPrefix(field, i+".") {
+			return true
+		}
+	}
+	return false
+}
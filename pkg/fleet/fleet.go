@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fleet keeps the effective configuration of every producer of the
+// fleet in a NATS key-value bucket, so the nodes whose configuration drifted
+// from the others can be found.
+package fleet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBucket is the key-value bucket the configurations are published to
+const DefaultBucket = "prysm_config"
+
+// identityFields differ between the nodes by design. They are recorded as
+// the node and instance of a record, not as part of its configuration.
+var identityFields = []string{"NodeName", "InstanceID"}
+
+// secretFields are the parts of field names whose values are never
+// published
+var secretFields = []string{"password", "secret", "token", "accesskey", "headers"}
+
+// Redacted replaces the published value of a secret
+const Redacted = "<redacted>"
+
+// Record is the effective configuration of a producer on a node
+type Record struct {
+	Producer  string         `json:"producer"`
+	Node      string         `json:"node"`
+	Instance  string         `json:"instance,omitempty"`
+	Version   string         `json:"version"`
+	Hash      string         `json:"hash"` // SHA-256 of Config
+	Config    map[string]any `json:"config"`
+	Published time.Time      `json:"published"`
+}
+
+// NewRecord returns the record of the configuration, a struct encoding to a
+// JSON object. Secrets and the credentials of URLs are redacted.
+func NewRecord(producer, node, instance, version string, config any) (Record, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Record{}, fmt.Errorf("configuration is not an object: %w", err)
+	}
+	for _, field := range identityFields {
+		delete(fields, field)
+	}
+	redact(fields)
+
+	// Maps encode with sorted keys, equal configurations hash the same
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return Record{}, err
+	}
+	sum := sha256.Sum256(canonical)
+
+	return Record{
+		Producer:  producer,
+		Node:      node,
+		Instance:  instance,
+		Version:   version,
+		Hash:      hex.EncodeToString(sum[:]),
+		Config:    fields,
+		Published: time.Now().UTC(),
+	}, nil
+}
+
+// Key of the record in the bucket, e.g. ops-log.node-1
+func (r Record) Key() string {
+	key := r.Producer + "." + keyToken(r.Node)
+	if r.Instance != "" {
+		key += "." + keyToken(r.Instance)
+	}
+	return key
+}
+
+// Name of the node and instance of the record
+func (r Record) Name() string {
+	if r.Instance == "" {
+		return r.Node
+	}
+	return r.Node + "/" + r.Instance
+}
+
+// keyToken replaces the characters not allowed in a key
+func keyToken(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '=':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// redact removes the secrets of the fields and their nested objects
+func redact(fields map[string]any) {
+	for name, value := range fields {
+		if isSecret(name) {
+			if !isEmpty(value) {
+				fields[name] = Redacted
+			}
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			redact(v)
+		case string:
+			fields[name] = redactURL(v)
+		}
+	}
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretFields {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// redactURL hides the password of a URL with credentials
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") || !strings.Contains(s, "@") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
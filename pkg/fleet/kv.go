@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fleet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// openBucket opens the key-value bucket, creating it if it does not exist
+func openBucket(nc *nats.Conn, bucket string, create bool) (nats.KeyValue, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize JetStream: %w", err)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) && create {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "Effective configuration of the prysm producers",
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+	return kv, nil
+}
+
+// Publish stores the record in the bucket, replacing the previous record of
+// the producer on the node. Nothing is stored in read-only maintenance mode.
+func Publish(nc *nats.Conn, bucket string, record Record) error {
+	if natsutil.SkipKVWrite() {
+		return nil
+	}
+	kv, err := openBucket(nc, bucket, true)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := kv.Put(record.Key(), data); err != nil {
+		return fmt.Errorf("failed to store the configuration of %s: %w", record.Key(), err)
+	}
+	return nil
+}
+
+// Load returns the records of the bucket ordered by producer and node
+func Load(nc *nats.Conn, bucket string) ([]Record, error) {
+	kv, err := openBucket(nc, bucket, false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := kv.Keys()
+	if errors.Is(err, nats.ErrNoKeysFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configurations: %w", err)
+	}
+
+	var records []Record
+	for _, key := range keys {
+		entry, err := kv.Get(key)
+		if err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("failed to read configuration")
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(entry.Value(), &record); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("failed to parse configuration")
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Producer != records[j].Producer {
+			return records[i].Producer < records[j].Producer
+		}
+		return records[i].Name() < records[j].Name()
+	})
+	return records, nil
+}
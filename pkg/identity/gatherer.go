@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Gatherer returns the metrics of g with the labels of the current identity.
+// A metric keeps the identity labels it has a value for, e.g. the node of
+// the producers that label their metrics themselves, and gets the others.
+func Gatherer(g prometheus.Gatherer) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		addLabels(families, Current().Labels())
+		return families, err
+	})
+}
+
+// addLabels sets the labels on the metrics missing them or with an empty
+// value, keeping the labels sorted by name as gathered
+func addLabels(families []*dto.MetricFamily, labels map[string]string) {
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			missing := make(map[string]string, len(labels))
+			for name, value := range labels {
+				missing[name] = value
+			}
+			for _, pair := range metric.GetLabel() {
+				value, ok := missing[pair.GetName()]
+				if !ok {
+					continue
+				}
+				if pair.GetValue() == "" {
+					pair.Value = &value
+				}
+				delete(missing, pair.GetName())
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			for name, value := range missing {
+				metric.Label = append(metric.Label, &dto.LabelPair{Name: &name, Value: &value})
+			}
+			sort.Slice(metric.Label, func(i, j int) bool {
+				return metric.Label[i].GetName() < metric.Label[j].GetName()
+			})
+		}
+	}
+}
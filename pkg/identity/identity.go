@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package identity names the running exporter: its instance ID, pod, node
+// and version. It is resolved once on startup from --instance-id,
+// --node-name, INSTANCE_ID, NODE_NAME and POD_NAME, then stamped on every
+// NATS message as Prysm-* headers and on every Prometheus metric as labels,
+// so the data of all producers tells which exporter it came from the same
+// way.
+package identity
+
+import (
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/nats-io/nats.go"
+)
+
+// Headers of the NATS messages naming the exporter that published them
+const (
+	InstanceIDHeader = "Prysm-Instance-ID"
+	PodHeader        = "Prysm-Pod"
+	NodeHeader       = "Prysm-Node"
+	VersionHeader    = "Prysm-Version"
+)
+
+// Labels of the Prometheus metrics naming the exporter. The version is a
+// label of prysm_build_info only, a label on every series would replace all
+// of them on every upgrade.
+const (
+	InstanceIDLabel = "instance_id"
+	PodLabel        = "pod"
+	NodeLabel       = "node"
+)
+
+// Identity of an exporter
+type Identity struct {
+	InstanceID string `json:"instance_id"`        // Unique among the exporters, the pod name or node name if not set
+	PodName    string `json:"pod_name,omitempty"` // Empty outside Kubernetes
+	NodeName   string `json:"node_name"`          // The hostname if not set
+	Version    string `json:"version"`
+}
+
+// New resolves the identity of an exporter: the node name defaults to the
+// hostname, the instance ID to the pod name, then the node name
+func New(instanceID, podName, nodeName string) Identity {
+	id := Identity{InstanceID: instanceID, PodName: podName, NodeName: nodeName, Version: version.Version}
+	if id.NodeName == "" {
+		id.NodeName, _ = os.Hostname()
+	}
+	if id.InstanceID == "" {
+		id.InstanceID = id.PodName
+	}
+	if id.InstanceID == "" {
+		id.InstanceID = id.NodeName
+	}
+	return id
+}
+
+// Identity of this exporter, set once on startup by Configure
+var current = New("", os.Getenv("POD_NAME"), "")
+
+func Configure(id Identity) {
+	current = id
+}
+
+// Current returns the identity of this exporter
+func Current() Identity {
+	return current
+}
+
+// SetHeaders names the exporter in the headers of a NATS message, empty
+// fields are left out
+func (id Identity) SetHeaders(header nats.Header) {
+	for name, value := range map[string]string{
+		InstanceIDHeader: id.InstanceID,
+		PodHeader:        id.PodName,
+		NodeHeader:       id.NodeName,
+		VersionHeader:    id.Version,
+	} {
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+}
+
+// FromHeaders returns the identity of the exporter that published a NATS
+// message, empty for messages of exporters older than the headers
+func FromHeaders(header nats.Header) Identity {
+	return Identity{
+		InstanceID: header.Get(InstanceIDHeader),
+		PodName:    header.Get(PodHeader),
+		NodeName:   header.Get(NodeHeader),
+		Version:    header.Get(VersionHeader),
+	}
+}
+
+// Labels returns the metric labels naming the exporter, empty fields are
+// left out
+func (id Identity) Labels() map[string]string {
+	labels := map[string]string{}
+	for name, value := range map[string]string{
+		InstanceIDLabel: id.InstanceID,
+		PodLabel:        id.PodName,
+		NodeLabel:       id.NodeName,
+	} {
+		if value != "" {
+			labels[name] = value
+		}
+	}
+	return labels
+}
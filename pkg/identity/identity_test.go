@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	id := New("", "rgw-0", "")
+	assert.Equal(t, "rgw-0", id.InstanceID, "the pod name outside of a set instance ID")
+	assert.Equal(t, hostname, id.NodeName)
+	assert.NotEmpty(t, id.Version)
+
+	id = New("", "", "node-1")
+	assert.Equal(t, "node-1", id.InstanceID, "the node name outside of Kubernetes")
+
+	id = New("rgw-a", "rgw-0", "node-1")
+	assert.Equal(t, Identity{InstanceID: "rgw-a", PodName: "rgw-0", NodeName: "node-1", Version: id.Version}, id)
+}
+
+func TestHeaders(t *testing.T) {
+	id := Identity{InstanceID: "rgw-a", NodeName: "node-1", Version: "v1.2.3"}
+	header := nats.Header{}
+	id.SetHeaders(header)
+
+	assert.Equal(t, "rgw-a", header.Get(InstanceIDHeader))
+	assert.Equal(t, "node-1", header.Get(NodeHeader))
+	assert.Equal(t, "v1.2.3", header.Get(VersionHeader))
+	assert.NotContains(t, header, PodHeader, "empty fields are left out")
+	assert.Equal(t, id, FromHeaders(header))
+}
+
+func TestGatherer(t *testing.T) {
+	defer Configure(Current())
+	Configure(Identity{InstanceID: "rgw-a", PodName: "rgw-0", NodeName: "node-1"})
+
+	registry := prometheus.NewRegistry()
+	plain := prometheus.NewGauge(prometheus.GaugeOpts{Name: "plain"})
+	labeled := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "labeled"}, []string{"node", "pod", "zone"})
+	registry.MustRegister(plain, labeled)
+	labeled.WithLabelValues("node-2", "", "a").Set(1)
+
+	families, err := Gatherer(registry).Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 2)
+
+	labels := func(metric *dto.Metric) map[string]string {
+		pairs := map[string]string{}
+		var names []string
+		for _, pair := range metric.GetLabel() {
+			pairs[pair.GetName()] = pair.GetValue()
+			names = append(names, pair.GetName())
+		}
+		assert.IsIncreasing(t, names, "labels are sorted by name")
+		return pairs
+	}
+	// The own node label is kept, the empty pod label is filled in
+	assert.Equal(t, map[string]string{"instance_id": "rgw-a", "node": "node-2", "pod": "rgw-0", "zone": "a"}, labels(families[0].GetMetric()[0]))
+	assert.Equal(t, map[string]string{"instance_id": "rgw-a", "node": "node-1", "pod": "rgw-0"}, labels(families[1].GetMetric()[0]))
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubeclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Get(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, `{"reason":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/nodes/storage-01":
+			_, _ = w.Write([]byte(`{"kind":"Node","metadata":{"name":"storage-01"}}`))
+		case "/api/v1/nodes/broken":
+			_, _ = w.Write([]byte(`{"kind":`))
+		default:
+			http.Error(w, `{"reason":"NotFound"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("test-token\n"), 0o600))
+	client := New(server.Client(), server.URL, tokenFile)
+
+	var node struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, client.Get(context.Background(), "/api/v1/nodes/storage-01", &node))
+	assert.Equal(t, "storage-01", node.Metadata.Name)
+
+	assert.ErrorContains(t, client.Get(context.Background(), "/api/v1/nodes/storage-02", &node), "404")
+	assert.ErrorContains(t, client.Get(context.Background(), "/api/v1/nodes/broken", &node), "failed to decode")
+
+	// The token is read on every request, a rotated one is used right away
+	require.NoError(t, os.WriteFile(tokenFile, []byte("rotated-token"), 0o600))
+	assert.ErrorContains(t, client.Get(context.Background(), "/api/v1/nodes/storage-01", &node), "401")
+
+	client = New(server.Client(), server.URL, filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, client.Get(context.Background(), "/api/v1/nodes/storage-01", &node), "service account token")
+}
+
+func TestInCluster_OutsideOfPod(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := InCluster()
+	assert.ErrorContains(t, err, "not running in a Kubernetes pod")
+}
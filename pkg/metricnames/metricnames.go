@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metricnames puts the metrics of all producers under one namespace.
+// The producers name their metrics radosgw_, disk_ or without a prefix at
+// all; the prefix, prysm_ by default, is prepended when the metrics are
+// served and pushed, so one selector matches the metrics of every producer.
+// The old names can be served along for the dashboards not migrated yet.
+package metricnames
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultPrefix is the namespace of the metrics unless configured otherwise
+const DefaultPrefix = "prysm_"
+
+// standardPrefixes are the metrics of the Go client library and of the
+// multi-target probes, kept as they are for the dashboards shared with other
+// exporters
+var standardPrefixes = []string{"go_", "process_", "promhttp_", "probe_"}
+
+// A prefix without colons, which are reserved for recording rules, and ending
+// in an underscore, so relabeling can strip it again
+var prefixPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*_$`)
+
+// Config of the metric names
+type Config struct {
+	Prefix string // Prepended to the names without it, e.g. prysm_ (names kept if empty)
+	Legacy bool   // Serve the names without the prefix along
+}
+
+// Validate fails on a prefix that is not the start of a metric name ending in
+// an underscore
+func (c Config) Validate() error {
+	if c.Prefix != "" && !prefixPattern.MatchString(c.Prefix) {
+		return fmt.Errorf("metric prefix %q must be letters, digits and underscores ending in an underscore, e.g. %s", c.Prefix, DefaultPrefix)
+	}
+	return nil
+}
+
+// Names of this exporter, set once on startup by Configure
+var current Config
+
+func Configure(c Config) {
+	current = c
+}
+
+// renames tells whether the prefix is prepended to a name
+func (c Config) renames(name string) bool {
+	if c.Prefix == "" || strings.HasPrefix(name, c.Prefix) {
+		return false
+	}
+	for _, prefix := range standardPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// Gatherer returns the metrics of g named with the current prefix, and with
+// their old names too if legacy names are configured
+func Gatherer(g prometheus.Gatherer) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		return rename(families, current), err
+	})
+}
+
+// rename prefixes the names of the families, keeping the families sorted by
+// name as gathered. A family whose prefixed name is taken keeps its name.
+func rename(families []*dto.MetricFamily, cfg Config) []*dto.MetricFamily {
+	if cfg.Prefix == "" {
+		return families
+	}
+	taken := make(map[string]bool, len(families))
+	for _, family := range families {
+		taken[family.GetName()] = true
+	}
+
+	renamed := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		name := family.GetName()
+		prefixed := cfg.Prefix + name
+		if !cfg.renames(name) || taken[prefixed] {
+			renamed = append(renamed, family)
+			continue
+		}
+		if cfg.Legacy {
+			// The metrics are shared, the labels added later are on both
+			help := fmt.Sprintf("Deprecated, use %s: %s", prefixed, family.GetHelp())
+			renamed = append(renamed, &dto.MetricFamily{Name: &name, Help: &help, Type: family.Type, Unit: family.Unit, Metric: family.Metric})
+		}
+		family.Name = &prefixed
+		renamed = append(renamed, family)
+	}
+	sort.Slice(renamed, func(i, j int) bool {
+		return renamed[i].GetName() < renamed[j].GetName()
+	})
+	return renamed
+}
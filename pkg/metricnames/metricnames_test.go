@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metricnames
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatherNames(t *testing.T, cfg Config) map[string]string {
+	defer Configure(current)
+	Configure(cfg)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "radosgw_usage_kv_entries", Help: "Entries"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "disk_temperature_celsius", Help: "Temperature"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "prysm_build_info", Help: "Build"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "go_goroutines", Help: "Goroutines"}),
+	)
+	families, err := Gatherer(registry).Gather()
+	require.NoError(t, err)
+
+	names := map[string]string{}
+	var order []string
+	for _, family := range families {
+		names[family.GetName()] = family.GetHelp()
+		order = append(order, family.GetName())
+		assert.Len(t, family.GetMetric(), 1)
+	}
+	assert.IsIncreasing(t, order, "sorted by name")
+	return names
+}
+
+func TestGatherer(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"prysm_radosgw_usage_kv_entries": "Entries",
+		"prysm_disk_temperature_celsius": "Temperature",
+		"prysm_build_info":               "Build",
+		"go_goroutines":                  "Goroutines",
+	}, gatherNames(t, Config{Prefix: DefaultPrefix}))
+
+	assert.Len(t, gatherNames(t, Config{}), 4)
+	assert.Contains(t, gatherNames(t, Config{}), "radosgw_usage_kv_entries", "no prefix keeps the names")
+}
+
+func TestGatherer_Legacy(t *testing.T) {
+	names := gatherNames(t, Config{Prefix: "ceph_", Legacy: true})
+	assert.Equal(t, map[string]string{
+		"ceph_radosgw_usage_kv_entries": "Entries",
+		"radosgw_usage_kv_entries":      "Deprecated, use ceph_radosgw_usage_kv_entries: Entries",
+		"ceph_disk_temperature_celsius": "Temperature",
+		"disk_temperature_celsius":      "Deprecated, use ceph_disk_temperature_celsius: Temperature",
+		"ceph_prysm_build_info":         "Build",
+		"prysm_build_info":              "Deprecated, use ceph_prysm_build_info: Build",
+		"go_goroutines":                 "Goroutines",
+	}, names)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, prefix := range []string{"", "prysm_", "ceph_prysm_"} {
+		assert.NoError(t, Config{Prefix: prefix}.Validate(), prefix)
+	}
+	for _, prefix := range []string{"prysm", "prysm:", "1prysm_", "prysm-rgw_", "_"} {
+		assert.Error(t, Config{Prefix: prefix}.Validate(), prefix)
+	}
+}
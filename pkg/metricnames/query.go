@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metricnames
+
+import (
+	"strings"
+)
+
+// Current returns the names of this exporter as configured on startup, for
+// the rules and dashboards querying them
+func Current() Config {
+	return current
+}
+
+// Name is the name a metric is served under
+func (c Config) Name(name string) string {
+	if c.renames(name) {
+		return c.Prefix + name
+	}
+	return name
+}
+
+// Keywords and aggregation operators of PromQL, which are no metric names
+// even where no parenthesis follows, e.g. in sum by (le) (...)
+var promqlKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "offset": true, "bool": true,
+	"atan2": true, "inf": true, "nan": true,
+	"sum": true, "avg": true, "count": true, "min": true, "max": true,
+	"group": true, "stddev": true, "stdvar": true, "topk": true,
+	"bottomk": true, "quantile": true, "count_values": true,
+	"limitk": true, "limit_ratio": true,
+}
+
+// Keywords of PromQL followed by a list of labels
+var labelListKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true,
+}
+
+// closing are the brackets whose content holds no metric names: label
+// matchers, ranges and label lists
+var closing = map[byte]byte{'{': '}', '[': ']', '(': ')'}
+
+// Query names the metrics of a PromQL expression as they are served, so the
+// generated alerting rules and dashboards query the renamed metrics.
+// Functions, keywords, labels, strings, numbers and the names of recording
+// rules are kept.
+func (c Config) Query(expr string) string {
+	if c.Prefix == "" {
+		return expr
+	}
+
+	var out strings.Builder
+	labelList := false // The next parentheses list labels
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		switch {
+		case ch == '"' || ch == '\'' || ch == '`':
+			end := stringEnd(expr, i)
+			out.WriteString(expr[i:end])
+			i = end
+		case ch == '{' || ch == '[' || (ch == '(' && labelList):
+			end := bracketEnd(expr, i, closing[ch])
+			out.WriteString(expr[i:end])
+			i = end
+			labelList = false
+		case isNameStart(ch):
+			end := i
+			for end < len(expr) && isNameChar(expr[end]) {
+				end++
+			}
+			word := expr[i:end]
+			next := strings.TrimLeft(expr[end:], " \t\n")
+			switch {
+			case labelListKeywords[word]:
+				labelList = strings.HasPrefix(next, "(")
+				out.WriteString(word)
+			case promqlKeywords[strings.ToLower(word)], strings.HasPrefix(next, "("), strings.Contains(word, ":"):
+				out.WriteString(word)
+			default:
+				out.WriteString(c.Name(word))
+			}
+			i = end
+		case ch == '$' || ch == '.' || (ch >= '0' && ch <= '9'):
+			// Numbers, durations and Grafana variables
+			end := i + 1
+			for end < len(expr) && (isNameChar(expr[end]) || expr[end] == '.' || expr[end] == '{' || expr[end] == '}') {
+				end++
+			}
+			out.WriteString(expr[i:end])
+			i = end
+		default:
+			out.WriteByte(ch)
+			i++
+		}
+	}
+	return out.String()
+}
+
+func isNameStart(ch byte) bool {
+	return ch == '_' || ch == ':' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isNameChar(ch byte) bool {
+	return isNameStart(ch) || (ch >= '0' && ch <= '9')
+}
+
+// stringEnd is the index after the string literal starting at start
+func stringEnd(expr string, start int) int {
+	quote := expr[start]
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(expr)
+}
+
+// bracketEnd is the index after the bracket closing the one at start,
+// skipping the strings in between
+func bracketEnd(expr string, start int, closer byte) int {
+	for i := start + 1; i < len(expr); {
+		switch expr[i] {
+		case '"', '\'', '`':
+			i = stringEnd(expr, i)
+		case closer:
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(expr)
+}
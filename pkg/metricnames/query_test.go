@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metricnames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Query(t *testing.T) {
+	cfg := Config{Prefix: DefaultPrefix}
+	for _, tc := range []struct{ expr, want string }{
+		{
+			`sum by (operation) (rate(radosgw_bucket_sli_requests_total{status_class="5xx"}[5m]))`,
+			`sum by (operation) (rate(prysm_radosgw_bucket_sli_requests_total{status_class="5xx"}[5m]))`,
+		},
+		{
+			`histogram_quantile(0.99, sum by (le, method) (rate(radosgw_request_duration_seconds_bucket[$__rate_interval])))`,
+			`histogram_quantile(0.99, sum by (le, method) (rate(prysm_radosgw_request_duration_seconds_bucket[$__rate_interval])))`,
+		},
+		{
+			`(radosgw_usage_bucket_size / radosgw_usage_bucket_quota_size > 0.9) and (radosgw_usage_bucket_quota_enabled == 1)`,
+			`(prysm_radosgw_usage_bucket_size / prysm_radosgw_usage_bucket_quota_size > 0.9) and (prysm_radosgw_usage_bucket_quota_enabled == 1)`,
+		},
+		{
+			`time() - radosgw_usage_last_sync_timestamp_seconds > 180`,
+			`time() - prysm_radosgw_usage_last_sync_timestamp_seconds > 180`,
+		},
+		{
+			`disk_temperature_celsius * on (node, disk) group_left (model) disk_info offset 1h`,
+			`prysm_disk_temperature_celsius * on (node, disk) group_left (model) prysm_disk_info offset 1h`,
+		},
+		{
+			`topk(10, sum(disk_io_errors) by (node))`,
+			`topk(10, sum(prysm_disk_io_errors) by (node))`,
+		},
+		{
+			`prysm_target_up == 0 or go_goroutines > Inf`,
+			`prysm_target_up == 0 or go_goroutines > Inf`,
+		},
+		{
+			`{__name__="disk_pending_sectors", node=~"$node"}`,
+			`{__name__="disk_pending_sectors", node=~"$node"}`,
+		},
+		{
+			`node:disk_temperature:max > 60`,
+			`node:disk_temperature:max > 60`,
+		},
+	} {
+		assert.Equal(t, tc.want, cfg.Query(tc.expr), tc.expr)
+	}
+
+	expr := `sum(rate(radosgw_total_requests[5m]))`
+	assert.Equal(t, expr, Config{}.Query(expr), "no prefix keeps the names")
+}
+
+// The names queried are the names served
+func TestConfig_QueryServedNames(t *testing.T) {
+	for _, cfg := range []Config{{Prefix: DefaultPrefix}, {Prefix: "ceph_", Legacy: true}, {}} {
+		served := gatherNames(t, cfg)
+		for _, name := range []string{"radosgw_usage_kv_entries", "disk_temperature_celsius", "prysm_build_info", "go_goroutines"} {
+			queried := cfg.Query("max(" + name + ")")
+			assert.Contains(t, served, queried[len("max("):len(queried)-1], "%s with prefix %q", name, cfg.Prefix)
+		}
+	}
+}
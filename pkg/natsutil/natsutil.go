@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package natsutil connects the prysm subcommands to NATS the same way: with
+// the client name, TLS and credentials configured on the command line, and
+// reconnecting for as long as the process runs.
+package natsutil
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// Config shared by all connections of the process
+type Config struct {
+	Name      string // Client name shown in the NATS server monitoring
+	CredsFile string // NATS user credentials (JWT and NKey seed)
+	Creds     string // Content of a credentials file, e.g. read from Vault; takes precedence over CredsFile
+	TLS       TLSConfig
+	Spool     SpoolConfig
+}
+
+// TLSConfig of the connections: CAFile verifies the server, CertFile and
+// KeyFile authenticate the client with mutual TLS
+type TLSConfig struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// Validate fails if only one of the client certificate and key is set, or
+// on credentials without a JWT or seed
+func (c Config) Validate() error {
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return errors.New("NATS TLS requires both a client certificate and a key")
+	}
+	if c.Creds != "" {
+		if _, _, err := parseCreds(c.Creds); err != nil {
+			return err
+		}
+	}
+	return c.Spool.Validate()
+}
+
+// Configuration of the connections, set once on startup by Configure
+var config Config
+
+func Configure(cfg Config) {
+	config = cfg
+}
+
+// Options returns the connection options of the configuration
+func (c Config) Options() []nats.Option {
+	opts := []nats.Option{
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			if err != nil {
+				log.Warn().Err(err).Str("nats_url", nc.ConnectedUrlRedacted()).Msg("disconnected from nats")
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Info().Str("nats_url", nc.ConnectedUrlRedacted()).Msg("reconnected to nats")
+			forwardSpool(nc)
+		}),
+	}
+	if c.Name != "" {
+		opts = append(opts, nats.Name(c.Name))
+	}
+	if jwt, seed, err := parseCreds(c.Creds); err == nil {
+		opts = append(opts, nats.UserJWTAndSeed(jwt, seed))
+	} else if c.CredsFile != "" {
+		opts = append(opts, nats.UserCredentials(c.CredsFile))
+	}
+	if c.TLS.CAFile != "" {
+		opts = append(opts, nats.RootCAs(c.TLS.CAFile))
+	}
+	if c.TLS.CertFile != "" {
+		opts = append(opts, nats.ClientCert(c.TLS.CertFile, c.TLS.KeyFile))
+	}
+	return opts
+}
+
+// parseCreds returns the JWT and the NKey seed of the content of a
+// credentials file, in the blocks following their BEGIN lines
+func parseCreds(creds string) (jwt, seed string, err error) {
+	lines := strings.Split(creds, "\n")
+	for i, line := range lines[:max(len(lines)-1, 0)] {
+		switch {
+		case strings.Contains(line, "BEGIN NATS USER JWT"):
+			jwt = strings.TrimSpace(lines[i+1])
+		case strings.Contains(line, "BEGIN USER NKEY SEED"):
+			seed = strings.TrimSpace(lines[i+1])
+		}
+	}
+	if jwt == "" || seed == "" {
+		return "", "", errors.New("NATS credentials require a user JWT and an NKey seed")
+	}
+	return jwt, seed, nil
+}
+
+// Connections by URL, shared by the callers of Connect once Share is called
+var shared = struct {
+	sync.Mutex
+	enabled bool
+	conns   map[string]*nats.Conn
+}{conns: map[string]*nats.Conn{}}
+
+// Share makes Connect return one connection per URL to all its callers, so
+// producers running together in prysm agent share it. Closing a shared
+// connection closes it for all of them.
+func Share() {
+	shared.Lock()
+	defer shared.Unlock()
+	shared.enabled = true
+}
+
+func sharing() bool {
+	shared.Lock()
+	defer shared.Unlock()
+	return shared.enabled
+}
+
+// Connect connects to url with the configured options; opts are applied
+// after them. Connections with opts are never shared and never spooled to.
+func Connect(url string, opts ...nats.Option) (*nats.Conn, error) {
+	if len(opts) > 0 {
+		return nats.Connect(url, append(config.Options(), opts...)...)
+	}
+	if !sharing() {
+		return connect(url)
+	}
+
+	shared.Lock()
+	defer shared.Unlock()
+	if nc, found := shared.conns[url]; found && !nc.IsClosed() {
+		return nc, nil
+	}
+	nc, err := connect(url)
+	if err != nil {
+		return nil, err
+	}
+	shared.conns[url] = nc
+	return nc, nil
+}
+
+// connect connects a producer or consumer. With a spool, it returns before
+// the server is reachable and forwards the spool once connected.
+func connect(url string) (*nats.Conn, error) {
+	nc, err := nats.Connect(url, append(config.Options(), config.Spool.options()...)...)
+	if err != nil {
+		return nil, err
+	}
+	forwardSpool(nc)
+	return nc, nil
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{TLS: TLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}}.Validate())
+	assert.Error(t, Config{TLS: TLSConfig{KeyFile: "tls.key"}}.Validate())
+}
+
+func TestOptions(t *testing.T) {
+	opts := nats.GetDefaultOptions()
+	for _, opt := range (Config{Name: "prysm-test"}).Options() {
+		require.NoError(t, opt(&opts))
+	}
+	assert.Equal(t, "prysm-test", opts.Name)
+	assert.Equal(t, -1, opts.MaxReconnect)
+	assert.NotNil(t, opts.ReconnectedCB)
+}
+
+func TestOptionsMissingCA(t *testing.T) {
+	opts := nats.GetDefaultOptions()
+	var err error
+	for _, opt := range (Config{TLS: TLSConfig{CAFile: "/nonexistent/ca.crt"}}).Options() {
+		if err = opt(&opts); err != nil {
+			break
+		}
+	}
+	assert.Error(t, err)
+}
+
+func TestSubjectToken(t *testing.T) {
+	for s, want := range map[string]string{
+		"":             "none",
+		"proj-1_a":     "proj-1_a",
+		"a.b":          "a%2Eb",
+		"a_b":          "a_b",
+		"*":            "%2A",
+		"ops.>":        "ops%2E%3E",
+		"with space":   "with%20space",
+		"100%":         "100%25",
+		"prüfung":      "pr%C3%BCfung",
+		"d1f3e0c2b9a4": "d1f3e0c2b9a4",
+	} {
+		assert.Equal(t, want, SubjectToken(s), s)
+	}
+}
+
+const testCreds = `-----BEGIN NATS USER JWT-----
+eyJ0eXAiOiJKV1QiLCJhbGciOiJlZDI1NTE5LW5rZXkifQ.eyJzdWIiOiJVQVRFU1QifQ.c2ln
+------END NATS USER JWT------
+
+************************* IMPORTANT *************************
+NKEY Seed printed below can be used to sign and prove identity.
+
+-----BEGIN USER NKEY SEED-----
+SUAMLK2ZNL35WSMW37E7UD4VZ7ELPKW7DHC3BWBSD2GCZ7IUQQXZIORRBU
+------END USER NKEY SEED------
+`
+
+func TestParseCreds(t *testing.T) {
+	jwt, seed, err := parseCreds(testCreds)
+	require.NoError(t, err)
+	assert.Equal(t, "eyJ0eXAiOiJKV1QiLCJhbGciOiJlZDI1NTE5LW5rZXkifQ.eyJzdWIiOiJVQVRFU1QifQ.c2ln", jwt)
+	assert.Equal(t, "SUAMLK2ZNL35WSMW37E7UD4VZ7ELPKW7DHC3BWBSD2GCZ7IUQQXZIORRBU", seed)
+
+	_, _, err = parseCreds("-----BEGIN NATS USER JWT-----\neyJ0\n")
+	assert.Error(t, err, "no seed")
+	assert.Error(t, Config{Creds: "not creds"}.Validate())
+	assert.NoError(t, Config{Creds: testCreds}.Validate())
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// Read-only maintenance mode, set on startup by --read-only and at runtime
+// on the debug server. It pauses the writes to NATS, the published messages
+// and the KV updates, during maintenance windows of the NATS servers, while
+// the producers keep collecting and exporting to Prometheus.
+var readOnly atomic.Bool
+
+var (
+	readOnlyGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "prysm_read_only",
+		Help: "Read-only maintenance mode pausing the writes to NATS (1 = on, 0 = off)",
+	}, func() float64 {
+		if ReadOnly() {
+			return 1
+		}
+		return 0
+	})
+	droppedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prysm_read_only_dropped_messages_total",
+		Help: "NATS messages not published in read-only maintenance mode",
+	})
+	skippedKVWrites = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prysm_read_only_skipped_kv_writes_total",
+		Help: "NATS KV writes skipped in read-only maintenance mode",
+	})
+)
+
+// Collectors returns the metrics of the read-only mode, registered with the
+// metrics server
+func Collectors() []prometheus.Collector {
+	return append([]prometheus.Collector{readOnlyGauge, droppedMessages, skippedKVWrites}, spoolCollectors()...)
+}
+
+// SetReadOnly turns the read-only maintenance mode on or off
+func SetReadOnly(on bool) {
+	if readOnly.Swap(on) == on {
+		return
+	}
+	if on {
+		log.Warn().Msg("read-only maintenance mode on, pausing the writes to NATS")
+	} else {
+		log.Info().Msg("read-only maintenance mode off, resuming the writes to NATS")
+	}
+}
+
+// ReadOnly tells if the writes to NATS are paused
+func ReadOnly() bool {
+	return readOnly.Load()
+}
+
+// SkipKVWrite tells if a KV write is to be skipped, counting it if so.
+// Writers call it before updating a KV bucket of the NATS servers.
+func SkipKVWrite() bool {
+	if !ReadOnly() {
+		return false
+	}
+	skippedKVWrites.Inc()
+	return true
+}
+
+// ReadOnlyHandler serves the read-only maintenance mode: GET returns it,
+// PUT sets it from ?enabled=true or false
+func ReadOnlyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+			if err != nil {
+				http.Error(w, "expected ?enabled=true or false", http.StatusBadRequest)
+				return
+			}
+			SetReadOnly(enabled)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]bool{"read_only": ReadOnly()})
+	})
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly_PausesWrites(t *testing.T) {
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	// Dropped before the connection is used
+	dropped := testutil.ToFloat64(droppedMessages)
+	require.NoError(t, PublishMsg(nil, nats.NewMsg("prysm.test")))
+	assert.Equal(t, dropped+1, testutil.ToFloat64(droppedMessages))
+
+	assert.True(t, SkipKVWrite())
+	SetReadOnly(false)
+	assert.False(t, SkipKVWrite())
+}
+
+func TestReadOnlyHandler(t *testing.T) {
+	defer SetReadOnly(false)
+	handler := ReadOnlyHandler()
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder
+	}
+
+	response := serve(http.MethodPut, "/maintenance/read-only?enabled=true")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"read_only":true}`, response.Body.String())
+	assert.True(t, ReadOnly())
+	assert.Equal(t, 1.0, testutil.ToFloat64(readOnlyGauge))
+
+	response = serve(http.MethodGet, "/maintenance/read-only")
+	assert.JSONEq(t, `{"read_only":true}`, response.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/maintenance/read-only?enabled=maybe").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, "/maintenance/read-only?enabled=false").Code)
+	assert.True(t, ReadOnly())
+
+	serve(http.MethodPut, "/maintenance/read-only?enabled=false")
+	assert.False(t, ReadOnly())
+}
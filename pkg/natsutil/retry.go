@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+	"github.com/nats-io/nats.go"
+)
+
+// Retries of the publishes and KV operations, short enough not to hold up a
+// producer for long while NATS is away
+var (
+	PublishRetry = retry.Policy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.2}
+	KVRetry      = retry.Policy{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second, Jitter: 0.2}
+)
+
+// PublishMsg publishes msg, retrying while the connection reconnects and
+// its outbound buffer is full. Other failures, e.g. a closed connection or
+// a payload over the limit of the server, are not retried. In read-only
+// maintenance mode msg is dropped. With a spool, msg is spooled to disk
+// while the connection is down.
+func PublishMsg(nc *nats.Conn, msg *nats.Msg) error {
+	if ReadOnly() {
+		droppedMessages.Inc()
+		return nil
+	}
+	if s := spoolOf(nc); s != nil {
+		return s.publish(nc, msg)
+	}
+	return publishRetry(nc, msg)
+}
+
+func publishRetry(nc *nats.Conn, msg *nats.Msg) error {
+	return retry.Do(context.Background(), "nats-publish", PublishRetry, func(context.Context) error {
+		err := nc.PublishMsg(msg)
+		if err != nil && !errors.Is(err, nats.ErrReconnectBufExceeded) && !errors.Is(err, nats.ErrConnectionReconnecting) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+}
+
+// RetryKV returns kv retrying the reads, writes and deletes of keys that
+// time out or find no JetStream responder, e.g. while a stream leader is
+// elected. Missing keys and the other failures are not retried.
+func RetryKV(kv nats.KeyValue) nats.KeyValue {
+	return retryKV{KeyValue: kv}
+}
+
+type retryKV struct {
+	nats.KeyValue
+}
+
+// kvRetryable tells if a KV operation failed for a moment
+func kvRetryable(err error) bool {
+	return errors.Is(err, nats.ErrTimeout) || errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrNoStreamResponse) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// doKV runs a KV operation by KVRetry
+func doKV[T any](fn func() (T, error)) (T, error) {
+	return retry.DoValue(context.Background(), "nats-kv", KVRetry, func(context.Context) (T, error) {
+		value, err := fn()
+		if err != nil && !kvRetryable(err) {
+			return value, retry.Permanent(err)
+		}
+		return value, err
+	})
+}
+
+func (kv retryKV) Get(key string) (nats.KeyValueEntry, error) {
+	return doKV(func() (nats.KeyValueEntry, error) { return kv.KeyValue.Get(key) })
+}
+
+func (kv retryKV) Put(key string, value []byte) (uint64, error) {
+	return doKV(func() (uint64, error) { return kv.KeyValue.Put(key, value) })
+}
+
+func (kv retryKV) Delete(key string, opts ...nats.DeleteOpt) error {
+	_, err := doKV(func() (struct{}, error) { return struct{}{}, kv.KeyValue.Delete(key, opts...) })
+	return err
+}
+
+func (kv retryKV) Purge(key string, opts ...nats.DeleteOpt) error {
+	_, err := doKV(func() (struct{}, error) { return struct{}{}, kv.KeyValue.Purge(key, opts...) })
+	return err
+}
+
+func (kv retryKV) Keys(opts ...nats.WatchOpt) ([]string, error) {
+	return doKV(func() ([]string, error) { return kv.KeyValue.Keys(opts...) })
+}
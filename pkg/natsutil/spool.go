@@ -0,0 +1,536 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// spoolSegmentSize is the size of the spool files above which a new one
+	// is started; the files are forwarded and deleted one by one
+	spoolSegmentSize = 4 << 20
+	// spoolFlushTimeout bounds the wait for the server to receive the
+	// messages of a forwarded file before it is deleted
+	spoolFlushTimeout = 10 * time.Second
+)
+
+// SpoolConfig of the disk spool of the published messages, for edge sites
+// losing their NATS connection for hours. Messages published while the
+// connection is down are written to Dir and forwarded, oldest first, once
+// it is back.
+type SpoolConfig struct {
+	Dir     string        // Directory of the spool, disabled if empty
+	MaxSize int64         // Bytes above which the oldest messages are dropped (unlimited if 0)
+	MaxAge  time.Duration // Age above which spooled messages are dropped (kept if 0)
+}
+
+// Validate fails on negative limits
+func (c SpoolConfig) Validate() error {
+	if c.MaxSize < 0 || c.MaxAge < 0 {
+		return errors.New("NATS spool limits must not be negative")
+	}
+	return nil
+}
+
+// options keep the producers running without a connection on startup, and
+// forward the spool when they connect
+func (c SpoolConfig) options() []nats.Option {
+	if c.Dir == "" {
+		return nil
+	}
+	return []nats.Option{
+		nats.RetryOnFailedConnect(true),
+		nats.ConnectHandler(func(nc *nats.Conn) {
+			log.Info().Str("nats_url", nc.ConnectedUrlRedacted()).Msg("connected to nats")
+			forwardSpool(nc)
+		}),
+	}
+}
+
+var (
+	spooledMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prysm_nats_spool_spooled_messages_total",
+		Help: "NATS messages written to the disk spool while the connection was down",
+	})
+	forwardedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prysm_nats_spool_forwarded_messages_total",
+		Help: "Spooled NATS messages forwarded once the connection was back",
+	})
+	spoolDroppedMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prysm_nats_spool_dropped_messages_total",
+		Help: "Spooled NATS messages dropped by reason: size, age or corrupt",
+	}, []string{"reason"})
+	spoolMessagesGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "prysm_nats_spool_messages",
+		Help: "NATS messages in the disk spool, not forwarded yet",
+	}, func() float64 {
+		return sumSpools(func(s *spool) float64 { return float64(s.messages) })
+	})
+	spoolBytesGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "prysm_nats_spool_bytes",
+		Help: "Bytes of the disk spool",
+	}, func() float64 {
+		return sumSpools(func(s *spool) float64 { return float64(s.size) })
+	})
+	spoolLagGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "prysm_nats_spool_forwarding_lag_seconds",
+		Help: "Age of the oldest spooled NATS message not forwarded yet, 0 if the spool is empty",
+	}, func() float64 {
+		var lag float64
+		sumSpools(func(s *spool) float64 {
+			if len(s.segments) > 0 {
+				lag = max(lag, s.now().Sub(s.segments[0].oldest).Seconds())
+			}
+			return 0
+		})
+		return lag
+	})
+)
+
+func spoolCollectors() []prometheus.Collector {
+	return []prometheus.Collector{spooledMessages, forwardedMessages, spoolDroppedMessages, spoolMessagesGauge, spoolBytesGauge, spoolLagGauge}
+}
+
+// spooledMsg is a line of a spool file
+type spooledMsg struct {
+	Time    time.Time   `json:"time"`
+	Subject string      `json:"subject"`
+	Header  nats.Header `json:"header,omitempty"`
+	Data    []byte      `json:"data"`
+}
+
+// spoolSegment is a file of the spool, a spooledMsg per line
+type spoolSegment struct {
+	path     string
+	size     int64
+	messages int
+	oldest   time.Time
+	newest   time.Time
+}
+
+// spool of the messages of a connection. Messages are appended to the
+// newest file; the forwarder publishes the oldest file and deletes it once
+// the server received its messages, so a message may be delivered twice if
+// the connection drops during a forward, but is not lost.
+type spool struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+	now     func() time.Time
+
+	mu         sync.Mutex
+	segments   []*spoolSegment // Oldest first, the last one open for writing if current is set
+	current    *os.File
+	size       int64
+	messages   int
+	last       int64         // Name of the newest file, Unix nanoseconds
+	forwarding bool          // A forwarder runs
+	busy       *spoolSegment // The file being forwarded, never dropped
+}
+
+// Spools of the connections by URL, opened on the first publish
+var spools = struct {
+	sync.Mutex
+	byURL map[string]*spool
+}{byURL: map[string]*spool{}}
+
+// spoolOf returns the spool of the connection, nil if spooling is disabled
+// or the spool cannot be opened
+func spoolOf(nc *nats.Conn) *spool {
+	if config.Spool.Dir == "" || nc == nil {
+		return nil
+	}
+	spools.Lock()
+	defer spools.Unlock()
+	if s, found := spools.byURL[nc.Opts.Url]; found {
+		return s
+	}
+	dir := filepath.Join(config.Spool.Dir, spoolName(config.Name, nc.Opts.Url))
+	s, err := openSpool(dir, config.Spool.MaxSize, config.Spool.MaxAge)
+	if err != nil {
+		log.Error().Err(err).Str("dir", dir).Msg("failed to open the nats spool, publishing without it")
+	}
+	spools.byURL[nc.Opts.Url] = s
+	return s
+}
+
+// spoolName is the directory of the spool of a client and URL; the URL is
+// hashed, it may hold credentials
+func spoolName(client, url string) string {
+	h := fnv.New64a()
+	h.Write([]byte(url))
+	if client == "" {
+		client = "prysm"
+	}
+	return fmt.Sprintf("%s-%016x", client, h.Sum64())
+}
+
+func sumSpools(fn func(s *spool) float64) float64 {
+	spools.Lock()
+	defer spools.Unlock()
+	var sum float64
+	for _, s := range spools.byURL {
+		if s == nil {
+			continue
+		}
+		s.mu.Lock()
+		sum += fn(s)
+		s.mu.Unlock()
+	}
+	return sum
+}
+
+// forwardSpool starts forwarding the spool of the connection, if any
+func forwardSpool(nc *nats.Conn) {
+	if s := spoolOf(nc); s != nil && nc.IsConnected() {
+		s.kick(nc)
+	}
+}
+
+// openSpool opens the spool in dir with the files left by a previous run
+func openSpool(dir string, maxSize int64, maxAge time.Duration) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.spool"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	s := &spool{dir: dir, maxSize: maxSize, maxAge: maxAge, now: time.Now}
+	for _, path := range paths {
+		seg, err := loadSegment(path)
+		if err != nil {
+			return nil, err
+		}
+		if seg.messages == 0 {
+			_ = os.Remove(path)
+			continue
+		}
+		s.segments = append(s.segments, seg)
+		s.size += seg.size
+		s.messages += seg.messages
+		if name, err := strconv.ParseInt(strings.TrimSuffix(filepath.Base(path), ".spool"), 10, 64); err == nil {
+			s.last = max(s.last, name)
+		}
+	}
+	if s.messages > 0 {
+		log.Info().Int("messages", s.messages).Str("dir", dir).Msg("nats spool holds messages of a previous run")
+	}
+	return s, nil
+}
+
+// loadSegment reads the size, messages and times of a spool file
+func loadSegment(path string) (*spoolSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seg := &spoolSegment{path: path, size: int64(len(data))}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var msg spooledMsg
+		if len(line) == 0 || json.Unmarshal(line, &msg) != nil {
+			continue
+		}
+		if seg.messages == 0 {
+			seg.oldest = msg.Time
+		}
+		seg.newest = msg.Time
+		seg.messages++
+	}
+	return seg, nil
+}
+
+// publish publishes msg right away while the connection is up and nothing
+// is spooled, and spools it otherwise, keeping the order of the messages
+func (s *spool) publish(nc *nats.Conn, msg *nats.Msg) error {
+	if s.empty() && nc.IsConnected() {
+		err := publishRetry(nc, msg)
+		if err == nil || !spoolable(err) {
+			return err
+		}
+	}
+	if err := s.append(msg); err != nil {
+		return fmt.Errorf("failed to spool the message: %w", err)
+	}
+	if nc.IsConnected() {
+		s.kick(nc)
+	}
+	return nil
+}
+
+// spoolable tells if a publish failed for the connection being down
+func spoolable(err error) bool {
+	return errors.Is(err, nats.ErrReconnectBufExceeded) || errors.Is(err, nats.ErrConnectionReconnecting) ||
+		errors.Is(err, nats.ErrConnectionClosed)
+}
+
+func (s *spool) empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.segments) == 0
+}
+
+// append writes msg to the newest file, then drops the oldest files above
+// the size limit
+func (s *spool) append(msg *nats.Msg) error {
+	now := s.now()
+	line, err := json.Marshal(spooledMsg{Time: now, Subject: msg.Subject, Header: msg.Header, Data: msg.Data})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == nil {
+		if err := s.create(now); err != nil {
+			return err
+		}
+	}
+	if _, err := s.current.Write(line); err != nil {
+		return err
+	}
+	seg := s.segments[len(s.segments)-1]
+	if seg.messages == 0 {
+		seg.oldest = now
+	}
+	seg.newest = now
+	seg.messages++
+	seg.size += int64(len(line))
+	s.messages++
+	s.size += int64(len(line))
+	spooledMessages.Inc()
+
+	if seg.size >= spoolSegmentSize {
+		s.seal()
+	}
+	s.enforceSize()
+	return nil
+}
+
+// create starts a new file, named by its creation time
+func (s *spool) create(now time.Time) error {
+	name := max(now.UnixNano(), s.last+1)
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d.spool", name))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	s.last = name
+	s.current = f
+	s.segments = append(s.segments, &spoolSegment{path: path})
+	return nil
+}
+
+// seal closes the newest file, the next message starts a new one
+func (s *spool) seal() {
+	if s.current == nil {
+		return
+	}
+	if err := s.current.Sync(); err != nil {
+		log.Warn().Err(err).Msg("failed to sync the nats spool")
+	}
+	_ = s.current.Close()
+	s.current = nil
+}
+
+// enforceSize drops the oldest files until the spool fits its size
+func (s *spool) enforceSize() {
+	for s.maxSize > 0 && s.size > s.maxSize {
+		seg := s.oldestIdle()
+		if seg == nil {
+			return
+		}
+		s.drop(seg, "size")
+	}
+}
+
+// expire drops the files whose newest message is older than the age limit
+func (s *spool) expire(now time.Time) {
+	if s.maxAge <= 0 {
+		return
+	}
+	for _, seg := range append([]*spoolSegment(nil), s.segments...) {
+		if seg != s.busy && now.Sub(seg.newest) > s.maxAge {
+			s.drop(seg, "age")
+		}
+	}
+}
+
+// oldestIdle returns the oldest file not being forwarded
+func (s *spool) oldestIdle() *spoolSegment {
+	for _, seg := range s.segments {
+		if seg != s.busy {
+			return seg
+		}
+	}
+	return nil
+}
+
+// drop deletes a file with its messages
+func (s *spool) drop(seg *spoolSegment, reason string) {
+	if seg == s.segments[len(s.segments)-1] {
+		s.seal()
+	}
+	if err := os.Remove(seg.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Str("path", seg.path).Msg("failed to delete a nats spool file")
+	}
+	s.remove(seg)
+	spoolDroppedMessages.WithLabelValues(reason).Add(float64(seg.messages))
+	log.Warn().Int("messages", seg.messages).Str("reason", reason).Msg("dropped spooled nats messages")
+}
+
+func (s *spool) remove(seg *spoolSegment) {
+	for i, candidate := range s.segments {
+		if candidate == seg {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			s.size -= seg.size
+			s.messages -= seg.messages
+			return
+		}
+	}
+}
+
+// kick starts a forwarder on the connection unless one runs
+func (s *spool) kick(nc *nats.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.forwarding {
+		return
+	}
+	s.forwarding = true
+	go func() {
+		publish := func(msg *nats.Msg) error {
+			if !nc.IsConnected() {
+				return nats.ErrConnectionReconnecting
+			}
+			return nc.PublishMsg(msg)
+		}
+		flush := func() error {
+			return nc.FlushTimeout(spoolFlushTimeout)
+		}
+		if err := s.forward(publish, flush); err != nil {
+			log.Warn().Err(err).Msg("forwarding the nats spool paused until the next reconnect")
+		}
+	}()
+}
+
+// forward publishes the files oldest first until the spool is empty or a
+// publish fails
+func (s *spool) forward(publish func(*nats.Msg) error, flush func() error) error {
+	defer func() {
+		s.mu.Lock()
+		s.forwarding = false
+		s.busy = nil
+		s.mu.Unlock()
+	}()
+
+	for {
+		s.mu.Lock()
+		s.expire(s.now())
+		if len(s.segments) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		seg := s.segments[0]
+		if len(s.segments) == 1 {
+			s.seal()
+		}
+		s.busy = seg
+		s.mu.Unlock()
+
+		forwarded, rest, err := s.forwardSegment(seg, publish, flush)
+
+		s.mu.Lock()
+		forwardedMessages.Add(float64(forwarded))
+		if err == nil {
+			if removeErr := os.Remove(seg.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+				log.Warn().Err(removeErr).Str("path", seg.path).Msg("failed to delete a forwarded nats spool file")
+			}
+			s.remove(seg)
+		} else if rest != nil {
+			s.rewrite(seg, rest)
+		}
+		s.busy = nil
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// forwardSegment publishes the messages of a file. On a failed publish, it
+// returns the lines not published yet.
+func (s *spool) forwardSegment(seg *spoolSegment, publish func(*nats.Msg) error, flush func() error) (int, [][]byte, error) {
+	data, err := os.ReadFile(seg.path)
+	if err != nil {
+		return 0, nil, err
+	}
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+
+	forwarded := 0
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		var msg spooledMsg
+		if err := json.Unmarshal(line, &msg); err != nil {
+			spoolDroppedMessages.WithLabelValues("corrupt").Inc()
+			continue
+		}
+		if s.maxAge > 0 && s.now().Sub(msg.Time) > s.maxAge {
+			spoolDroppedMessages.WithLabelValues("age").Inc()
+			continue
+		}
+		if err := publish(&nats.Msg{Subject: msg.Subject, Header: msg.Header, Data: msg.Data}); err != nil {
+			return forwarded, lines[i:], err
+		}
+		forwarded++
+	}
+	// Deleted only once the server has them, forwarded again otherwise
+	if err := flush(); err != nil {
+		return 0, nil, err
+	}
+	return forwarded, nil, nil
+}
+
+// rewrite replaces a file by its lines not forwarded yet
+func (s *spool) rewrite(seg *spoolSegment, rest [][]byte) {
+	data := append(bytes.Join(rest, []byte("\n")), '\n')
+	tmp := seg.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Warn().Err(err).Str("path", seg.path).Msg("failed to rewrite a nats spool file, its messages are forwarded again")
+		return
+	}
+	if err := os.Rename(tmp, seg.path); err != nil {
+		log.Warn().Err(err).Str("path", seg.path).Msg("failed to rewrite a nats spool file, its messages are forwarded again")
+		return
+	}
+	rewritten, err := loadSegment(seg.path)
+	if err != nil {
+		return
+	}
+	s.size += rewritten.size - seg.size
+	s.messages += rewritten.messages - seg.messages
+	*seg = *rewritten
+}
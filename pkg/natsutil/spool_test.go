@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spoolMsg(subject, data string) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	msg.Header.Set("Prysm-Schema", "test/v1")
+	msg.Data = []byte(data)
+	return msg
+}
+
+func TestSpool_ForwardsInOrder(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	for i, data := range []string{"a", "b", "c"} {
+		require.NoError(t, s.append(spoolMsg("prysm.test", data)))
+		if i == 1 {
+			s.seal() // Two files
+		}
+	}
+	assert.Len(t, s.segments, 2)
+	assert.Equal(t, 3, s.messages)
+
+	var published []string
+	forwarded := testutil.ToFloat64(forwardedMessages)
+	err = s.forward(func(msg *nats.Msg) error {
+		assert.Equal(t, "test/v1", msg.Header.Get("Prysm-Schema"))
+		published = append(published, string(msg.Data))
+		return nil
+	}, func() error { return nil })
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c"}, published)
+	assert.Equal(t, forwarded+3, testutil.ToFloat64(forwardedMessages))
+	assert.Empty(t, s.segments)
+	assert.Zero(t, s.size)
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*"))
+	assert.Empty(t, files, "forwarded files are deleted")
+}
+
+func TestSpool_KeepsUnforwardedMessages(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 0, 0)
+	require.NoError(t, err)
+	for _, data := range []string{"a", "b", "c"} {
+		require.NoError(t, s.append(spoolMsg("prysm.test", data)))
+	}
+
+	// The connection drops after the first message
+	calls := 0
+	err = s.forward(func(*nats.Msg) error {
+		calls++
+		if calls > 1 {
+			return nats.ErrConnectionReconnecting
+		}
+		return nil
+	}, func() error { return nil })
+	require.ErrorIs(t, err, nats.ErrConnectionReconnecting)
+	assert.Equal(t, 2, s.messages)
+
+	// A restart finds the rest
+	s, err = openSpool(dir, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.messages)
+	var published []string
+	require.NoError(t, s.forward(func(msg *nats.Msg) error {
+		published = append(published, string(msg.Data))
+		return nil
+	}, func() error { return nil }))
+	assert.Equal(t, []string{"b", "c"}, published)
+
+	// A failed flush forwards the file again
+	require.NoError(t, s.append(spoolMsg("prysm.test", "d")))
+	err = s.forward(func(*nats.Msg) error { return nil }, func() error { return errors.New("flush timeout") })
+	require.Error(t, err)
+	assert.Equal(t, 1, s.messages)
+}
+
+func TestSpool_Limits(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s, err := openSpool(t.TempDir(), 0, time.Hour)
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.append(spoolMsg("prysm.test", "old")))
+	s.seal()
+	now = now.Add(90 * time.Minute)
+	require.NoError(t, s.append(spoolMsg("prysm.test", "new")))
+
+	droppedByAge := testutil.ToFloat64(spoolDroppedMessages.WithLabelValues("age"))
+	var published []string
+	require.NoError(t, s.forward(func(msg *nats.Msg) error {
+		published = append(published, string(msg.Data))
+		return nil
+	}, func() error { return nil }))
+	assert.Equal(t, []string{"new"}, published)
+	assert.Equal(t, droppedByAge+1, testutil.ToFloat64(spoolDroppedMessages.WithLabelValues("age")))
+
+	// Above the size, the oldest file goes
+	require.NoError(t, s.append(spoolMsg("prysm.test", "first")))
+	s.seal()
+	s.maxSize = s.size + 10
+	droppedBySize := testutil.ToFloat64(spoolDroppedMessages.WithLabelValues("size"))
+	require.NoError(t, s.append(spoolMsg("prysm.test", "second")))
+	assert.Equal(t, 1, s.messages)
+	assert.Equal(t, droppedBySize+1, testutil.ToFloat64(spoolDroppedMessages.WithLabelValues("size")))
+}
+
+func TestSpoolConfig_Validate(t *testing.T) {
+	assert.NoError(t, SpoolConfig{Dir: "/var/spool/prysm", MaxSize: 1 << 30, MaxAge: time.Hour}.Validate())
+	assert.Error(t, SpoolConfig{MaxSize: -1}.Validate())
+	assert.Error(t, Config{Spool: SpoolConfig{MaxAge: -time.Second}}.Validate())
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package natsutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubjectToken turns s into a single subject token. Letters, digits, - and _
+// are kept, every other byte is escaped as %XX, so the dots and wildcards of
+// NATS never split or widen a subject, and different values never share a
+// token, which matters when the token is granted in NATS permissions. An
+// empty s becomes "none".
+func SubjectToken(s string) string {
+	if s == "" {
+		return "none"
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
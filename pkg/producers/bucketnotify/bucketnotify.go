@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
 	"github.com/rs/zerolog/log"
 
 	"github.com/nats-io/nats.go"
@@ -57,7 +58,7 @@ func StartBucketNotifyServer(cfg BucketNotifyConfig) {
 	var nc *nats.Conn
 	var err error
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Error().Err(err).Msg("error connecting to nats server")
 			return
@@ -80,7 +81,7 @@ func StartBucketNotifyServer(cfg BucketNotifyConfig) {
 		}
 
 		if cfg.UseNats {
-			if err := nc.Publish(cfg.NatsSubject, body); err != nil {
+			if err := natsutil.PublishMsg(nc, &nats.Msg{Subject: cfg.NatsSubject, Data: body}); err != nil {
 				http.Error(w, "error publishing to nats", http.StatusInternalServerError)
 				return
 			}
@@ -7,6 +7,7 @@ package bucketnotify
 import (
 	"encoding/json"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
 	"github.com/nats-io/nats.go"
 )
 
@@ -16,5 +17,5 @@ func PublishToNATS(nc *nats.Conn, msg interface{}, cfg BucketNotifyConfig) error
 		return err
 	}
 
-	return nc.Publish(cfg.NatsSubject, data)
+	return natsutil.PublishMsg(nc, &nats.Msg{Subject: cfg.NatsSubject, Data: data})
 }
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// poll reads and parses the cluster status
+func poll(ctx context.Context, source statusSource, cfg CephHealthConfig) (ClusterStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+
+	data, err := source.status(ctx)
+	if err != nil {
+		return ClusterStatus{}, err
+	}
+	status, err := parseStatus(data)
+	if err != nil {
+		return ClusterStatus{}, err
+	}
+	status.NodeName = cfg.NodeName
+	status.InstanceID = cfg.InstanceID
+	status.Timestamp = time.Now().UTC()
+	return status, nil
+}
+
+func StartMonitoring(cfg CephHealthConfig) {
+	source, err := newStatusSource(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid ceph status source")
+	}
+
+	var nc *nats.Conn
+	if cfg.UseNats {
+		nc, err = natsutil.Connect(cfg.NatsURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error connecting to NATS")
+		}
+		defer nc.Close()
+	}
+
+	if cfg.Prometheus {
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+	}
+
+	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+	defer ticker.Stop()
+
+	var previous *ClusterStatus
+	for ; true; <-ticker.C {
+		start := time.Now()
+		status, err := poll(context.Background(), source, cfg)
+		statusDurationGauge.WithLabelValues(cfg.InstanceID).Set(time.Since(start).Seconds())
+		if err != nil {
+			statusErrorsCounter.WithLabelValues(cfg.InstanceID).Inc()
+			log.Error().Err(err).Msg("error reading ceph status")
+			continue
+		}
+
+		events := detectHealthEvents(previous, status)
+		previous = &status
+		for _, event := range events {
+			log.Info().Str("event_type", event.EventType).Str("check", event.Check).Str("severity", event.Severity).Msg(event.Message)
+		}
+
+		if cfg.Prometheus {
+			PublishToPrometheus(status, cfg)
+		}
+
+		if cfg.UseNats {
+			if err := PublishToNATS(nc, status, cfg); err != nil {
+				log.Error().Err(err).Msg("error publishing ceph status to NATS")
+			}
+			if err := PublishEventsToNATS(nc, events, cfg); err != nil {
+				log.Error().Err(err).Msg("error publishing health events to NATS")
+			}
+		} else if !cfg.Prometheus {
+			statusJSON, err := json.Marshal(status)
+			if err != nil {
+				log.Error().Err(err).Msg("error marshalling ceph status to JSON")
+				continue
+			}
+			fmt.Println(string(statusJSON))
+		}
+	}
+}
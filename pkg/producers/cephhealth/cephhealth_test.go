@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readStatus(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/status.json")
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseStatus(t *testing.T) {
+	status, err := parseStatus(readStatus(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "3b1f0d1e-5c8a-4f0b-9a55-2c1d7e8f9a01", status.FSID)
+	assert.Equal(t, HealthWarn, status.Health)
+	assert.Equal(t, []HealthCheck{
+		{Name: "OSD_DOWN", Severity: HealthWarn, Message: "1 osds down", Count: 1},
+		{Name: "PG_DEGRADED", Severity: HealthWarn, Message: "Degraded data redundancy: 1204/90312 objects degraded (1.333%), 12 pgs degraded", Count: 12},
+	}, status.Checks)
+	assert.Equal(t, 3, status.Mons)
+	assert.Equal(t, 3, status.MonsInQuorum)
+	assert.Equal(t, 6, status.OSDs)
+	assert.Equal(t, 5, status.OSDsUp)
+	assert.True(t, status.MgrAvailable)
+	assert.Equal(t, 93, status.PGs)
+	assert.Equal(t, int64(1204), status.Objects.Degraded)
+	assert.Equal(t, 176160768.0, status.Recovery.BytesPerSec)
+	assert.Equal(t, 340.0, status.Client.WriteOpsPerSec)
+
+	states := status.PGStateCounts()
+	assert.Equal(t, 93, states["active"])
+	assert.Equal(t, 81, states["clean"])
+	assert.Equal(t, 12, states["degraded"])
+	assert.Equal(t, 4, states["backfilling"])
+}
+
+func TestParseStatusNestedOSDMap(t *testing.T) {
+	status, err := parseStatus([]byte(`{"health":{"status":"HEALTH_OK"},"osdmap":{"osdmap":{"num_osds":3,"num_up_osds":3,"num_in_osds":2}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, status.OSDsUp)
+	assert.Equal(t, 2, status.OSDsIn)
+	assert.Empty(t, status.Checks)
+}
+
+func TestParseStatusInvalid(t *testing.T) {
+	_, err := parseStatus([]byte(`not json`))
+	assert.Error(t, err)
+	_, err = parseStatus([]byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestDetectHealthEvents(t *testing.T) {
+	now := time.Unix(100, 0)
+	previous := ClusterStatus{FSID: "f", Health: HealthWarn, Checks: []HealthCheck{
+		{Name: "OSD_DOWN", Severity: HealthWarn, Message: "1 osds down"},
+		{Name: "PG_AVAILABILITY", Severity: HealthWarn, Message: "Reduced data availability"},
+	}}
+	current := ClusterStatus{FSID: "f", Health: HealthErr, Timestamp: now, Checks: []HealthCheck{
+		{Name: "MON_DOWN", Severity: HealthWarn, Message: "1/3 mons down"},
+		{Name: "PG_AVAILABILITY", Severity: HealthErr, Message: "Reduced data availability"},
+	}}
+
+	assert.Empty(t, detectHealthEvents(nil, current))
+
+	events := detectHealthEvents(&previous, current)
+	require.Len(t, events, 4)
+	assert.Equal(t, HealthEvent{FSID: "f", EventType: EventHealthChanged, Severity: HealthErr, Previous: HealthWarn, Current: HealthErr,
+		Message: "cluster health changed from HEALTH_WARN to HEALTH_ERR", Timestamp: now}, events[0])
+	assert.Equal(t, EventCheckRaised, events[1].EventType)
+	assert.Equal(t, "MON_DOWN", events[1].Check)
+	assert.Equal(t, EventCheckChanged, events[2].EventType)
+	assert.Equal(t, HealthErr, events[2].Current)
+	assert.Equal(t, EventCheckCleared, events[3].EventType)
+	assert.Equal(t, "OSD_DOWN", events[3].Check)
+
+	assert.Empty(t, detectHealthEvents(&current, current))
+}
+
+func TestMgrSource(t *testing.T) {
+	statusJSON := readStatus(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, key, _ := r.BasicAuth()
+		if user != "prysm" || key != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/request", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("wait"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"prefix":"status","format":"json"}`, string(body))
+		json.NewEncoder(w).Encode(map[string]any{
+			"has_failed": false,
+			"finished":   []map[string]string{{"outb": string(statusJSON), "outs": ""}},
+		})
+	}))
+	defer server.Close()
+
+	cfg := CephHealthConfig{Source: SourceMgr, MgrURL: server.URL + "/", MgrUser: "prysm", MgrKey: "secret", Timeout: 5}
+	source, err := newStatusSource(cfg)
+	require.NoError(t, err)
+	status, err := poll(context.Background(), source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, HealthWarn, status.Health)
+
+	cfg.MgrKey = "wrong"
+	source, err = newStatusSource(cfg)
+	require.NoError(t, err)
+	_, err = source.status(context.Background())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestNewStatusSource(t *testing.T) {
+	_, err := newStatusSource(CephHealthConfig{Source: SourceMgr})
+	assert.Error(t, err)
+	_, err = newStatusSource(CephHealthConfig{Source: "dashboard"})
+	assert.Error(t, err)
+}
+
+func TestPublishToPrometheus(t *testing.T) {
+	status, err := parseStatus(readStatus(t))
+	require.NoError(t, err)
+	cfg := CephHealthConfig{InstanceID: "test"}
+
+	PublishToPrometheus(status, cfg)
+	assert.Equal(t, 1.0, testutil.ToFloat64(healthStatusGauge.WithLabelValues(status.FSID, "test")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(pgStateGauge.WithLabelValues(status.FSID, "test", "backfilling")))
+	assert.Equal(t, 2, testutil.CollectAndCount(healthCheckGauge))
+
+	// Cleared checks disappear
+	status.Health, status.Checks = HealthOK, nil
+	PublishToPrometheus(status, cfg)
+	assert.Equal(t, 0.0, testutil.ToFloat64(healthStatusGauge.WithLabelValues(status.FSID, "test")))
+	assert.Equal(t, 0, testutil.CollectAndCount(healthCheckGauge))
+}
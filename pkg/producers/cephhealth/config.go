@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+// Sources of the cluster status
+const (
+	SourceCLI = "cli" // ceph status, needs ceph.conf and a keyring
+	SourceMgr = "mgr" // the REST API of the mgr restful module
+)
+
+type CephHealthConfig struct {
+	Source         string // SourceCLI or SourceMgr
+	CephBinary     string
+	CephConf       string // --conf of the ceph CLI, its default if empty
+	CephName       string // --name of the ceph CLI, e.g. client.prysm
+	CephKeyring    string // --keyring of the ceph CLI
+	MgrURL         string // e.g. https://rook-ceph-mgr:8003
+	MgrUser        string
+	MgrKey         string // created with ceph restful create-key
+	MgrInsecure    bool   // skip verifying the self-signed certificate of the mgr
+	Interval       int    // in seconds
+	Timeout        int    // of a status request, in seconds
+	NatsURL        string
+	NatsSubject    string // status snapshots
+	EventsSubject  string // health changes
+	UseNats        bool
+	NodeName       string
+	InstanceID     string
+	Prometheus     bool
+	PrometheusPort int
+}
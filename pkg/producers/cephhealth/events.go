@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event types
+const (
+	EventHealthChanged = "health_changed" // the overall health changed
+	EventCheckRaised   = "check_raised"   // a health check appeared
+	EventCheckCleared  = "check_cleared"  // a health check went away
+	EventCheckChanged  = "check_changed"  // a health check changed its severity
+)
+
+// HealthEvent describes a change of the cluster health between two polls
+type HealthEvent struct {
+	FSID       string    `json:"fsid"`
+	NodeName   string    `json:"node_name"`
+	InstanceID string    `json:"instance_id"`
+	EventType  string    `json:"event_type"`
+	Check      string    `json:"check,omitempty"`
+	Severity   string    `json:"severity"` // of the check, or the current health
+	Previous   string    `json:"previous,omitempty"`
+	Current    string    `json:"current,omitempty"`
+	Message    string    `json:"message"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// detectHealthEvents compares the status with the one of the previous
+// poll, the first poll only records a baseline
+func detectHealthEvents(previous *ClusterStatus, current ClusterStatus) []HealthEvent {
+	if previous == nil {
+		return nil
+	}
+
+	event := func(eventType, check, severity, message string) HealthEvent {
+		return HealthEvent{
+			FSID:       current.FSID,
+			NodeName:   current.NodeName,
+			InstanceID: current.InstanceID,
+			EventType:  eventType,
+			Check:      check,
+			Severity:   severity,
+			Message:    message,
+			Timestamp:  current.Timestamp,
+		}
+	}
+
+	var events []HealthEvent
+	if previous.Health != current.Health {
+		e := event(EventHealthChanged, "", current.Health, fmt.Sprintf("cluster health changed from %s to %s", previous.Health, current.Health))
+		e.Previous, e.Current = previous.Health, current.Health
+		events = append(events, e)
+	}
+
+	before := map[string]HealthCheck{}
+	for _, check := range previous.Checks {
+		before[check.Name] = check
+	}
+	for _, check := range current.Checks {
+		old, found := before[check.Name]
+		delete(before, check.Name)
+		switch {
+		case !found:
+			events = append(events, event(EventCheckRaised, check.Name, check.Severity, check.Message))
+		case old.Severity != check.Severity:
+			e := event(EventCheckChanged, check.Name, check.Severity, check.Message)
+			e.Previous, e.Current = old.Severity, check.Severity
+			events = append(events, e)
+		}
+	}
+	// Checks are sorted, keep the cleared ones in that order too
+	for _, check := range previous.Checks {
+		if _, cleared := before[check.Name]; cleared {
+			events = append(events, event(EventCheckCleared, check.Name, HealthOK, check.Message))
+		}
+	}
+	return events
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+func PublishToNATS(nc *nats.Conn, status ClusterStatus, cfg CephHealthConfig) error {
+	return schema.Publish(nc, cfg.NatsSubject, schema.CephHealth, status)
+}
+
+func PublishEventsToNATS(nc *nats.Conn, events []HealthEvent, cfg CephHealthConfig) error {
+	for _, event := range events {
+		if err := schema.Publish(nc, cfg.EventsSubject, schema.CephHealthEvent, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var clusterLabels = []string{"fsid", "instance"}
+
+func newClusterGauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, append(clusterLabels, labels...))
+}
+
+var (
+	healthStatusGauge   = newClusterGauge("ceph_cluster_health_status", "Cluster health: 0 ok, 1 warning, 2 error")
+	healthCheckGauge    = newClusterGauge("ceph_cluster_health_check", "Active health checks, with the number of affected items in the value", "check", "severity", "muted")
+	monsGauge           = newClusterGauge("ceph_cluster_mons", "Number of monitors")
+	monsQuorumGauge     = newClusterGauge("ceph_cluster_mons_in_quorum", "Number of monitors in quorum")
+	osdsGauge           = newClusterGauge("ceph_cluster_osds", "Number of OSDs")
+	osdsUpGauge         = newClusterGauge("ceph_cluster_osds_up", "Number of OSDs up")
+	osdsInGauge         = newClusterGauge("ceph_cluster_osds_in", "Number of OSDs in")
+	mgrAvailableGauge   = newClusterGauge("ceph_cluster_mgr_available", "1 if an active mgr is available")
+	pgsGauge            = newClusterGauge("ceph_cluster_pgs", "Number of placement groups")
+	pgStateGauge        = newClusterGauge("ceph_cluster_pg_state", "Number of placement groups in a state, e.g. active, degraded or backfilling", "state")
+	objectsGauge        = newClusterGauge("ceph_cluster_objects", "Number of objects")
+	objectsDegraded     = newClusterGauge("ceph_cluster_objects_degraded", "Number of degraded object copies")
+	objectsMisplaced    = newClusterGauge("ceph_cluster_objects_misplaced", "Number of misplaced object copies")
+	objectsUnfound      = newClusterGauge("ceph_cluster_objects_unfound", "Number of unfound objects")
+	degradedRatioGauge  = newClusterGauge("ceph_cluster_objects_degraded_ratio", "Ratio of degraded object copies")
+	misplacedRatioGauge = newClusterGauge("ceph_cluster_objects_misplaced_ratio", "Ratio of misplaced object copies")
+	recoveryGauge       = newClusterGauge("ceph_cluster_recovery_bytes_per_second", "Recovery and backfill throughput in bytes")
+	recoveryObjects     = newClusterGauge("ceph_cluster_recovery_objects_per_second", "Recovery and backfill throughput in objects")
+	recoveryKeys        = newClusterGauge("ceph_cluster_recovery_keys_per_second", "Recovery and backfill throughput in omap keys")
+	clientBytesGauge    = newClusterGauge("ceph_cluster_client_bytes_per_second", "Client throughput", "direction")
+	clientOpsGauge      = newClusterGauge("ceph_cluster_client_ops_per_second", "Client operations", "direction")
+	bytesUsedGauge      = newClusterGauge("ceph_cluster_bytes_used", "Raw capacity used")
+	bytesAvailGauge     = newClusterGauge("ceph_cluster_bytes_available", "Raw capacity available")
+	bytesTotalGauge     = newClusterGauge("ceph_cluster_bytes_total", "Raw capacity")
+
+	statusErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ceph_cluster_status_errors_total",
+			Help: "Number of failed ceph status polls",
+		},
+		[]string{"instance"},
+	)
+	statusDurationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ceph_cluster_status_duration_seconds",
+			Help: "Duration of the last ceph status poll",
+		},
+		[]string{"instance"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		healthStatusGauge, healthCheckGauge,
+		monsGauge, monsQuorumGauge, osdsGauge, osdsUpGauge, osdsInGauge, mgrAvailableGauge,
+		pgsGauge, pgStateGauge,
+		objectsGauge, objectsDegraded, objectsMisplaced, objectsUnfound, degradedRatioGauge, misplacedRatioGauge,
+		recoveryGauge, recoveryObjects, recoveryKeys, clientBytesGauge, clientOpsGauge,
+		bytesUsedGauge, bytesAvailGauge, bytesTotalGauge,
+		statusErrorsCounter, statusDurationGauge,
+	)
+}
+
+func PublishToPrometheus(status ClusterStatus, cfg CephHealthConfig) {
+	labels := prometheus.Labels{"fsid": status.FSID, "instance": cfg.InstanceID}
+	set := func(gauge *prometheus.GaugeVec, value float64) {
+		gauge.With(labels).Set(value)
+	}
+
+	set(healthStatusGauge, healthValue(status.Health))
+	// Cleared checks and states no PG is in anymore disappear
+	healthCheckGauge.Reset()
+	for _, check := range status.Checks {
+		healthCheckGauge.WithLabelValues(status.FSID, cfg.InstanceID, check.Name, check.Severity, strconv.FormatBool(check.Muted)).Set(float64(check.Count))
+	}
+
+	set(monsGauge, float64(status.Mons))
+	set(monsQuorumGauge, float64(status.MonsInQuorum))
+	set(osdsGauge, float64(status.OSDs))
+	set(osdsUpGauge, float64(status.OSDsUp))
+	set(osdsInGauge, float64(status.OSDsIn))
+	set(mgrAvailableGauge, boolValue(status.MgrAvailable))
+
+	set(pgsGauge, float64(status.PGs))
+	pgStateGauge.Reset()
+	for state, count := range status.PGStateCounts() {
+		pgStateGauge.WithLabelValues(status.FSID, cfg.InstanceID, state).Set(float64(count))
+	}
+
+	set(objectsGauge, float64(status.Objects.Total))
+	set(objectsDegraded, float64(status.Objects.Degraded))
+	set(objectsMisplaced, float64(status.Objects.Misplaced))
+	set(objectsUnfound, float64(status.Objects.Unfound))
+	set(degradedRatioGauge, status.Objects.DegradedRatio)
+	set(misplacedRatioGauge, status.Objects.MisplacedRatio)
+
+	set(recoveryGauge, status.Recovery.BytesPerSec)
+	set(recoveryObjects, status.Recovery.ObjectsPerSec)
+	set(recoveryKeys, status.Recovery.KeysPerSec)
+	clientBytesGauge.WithLabelValues(status.FSID, cfg.InstanceID, "read").Set(status.Client.ReadBytesPerSec)
+	clientBytesGauge.WithLabelValues(status.FSID, cfg.InstanceID, "write").Set(status.Client.WriteBytesPerSec)
+	clientOpsGauge.WithLabelValues(status.FSID, cfg.InstanceID, "read").Set(status.Client.ReadOpsPerSec)
+	clientOpsGauge.WithLabelValues(status.FSID, cfg.InstanceID, "write").Set(status.Client.WriteOpsPerSec)
+
+	set(bytesUsedGauge, float64(status.BytesUsed))
+	set(bytesAvailGauge, float64(status.BytesAvail))
+	set(bytesTotalGauge, float64(status.BytesTotal))
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+// statusSource returns the JSON output of ceph status
+type statusSource interface {
+	status(ctx context.Context) ([]byte, error)
+}
+
+func newStatusSource(cfg CephHealthConfig) (statusSource, error) {
+	switch cfg.Source {
+	case SourceCLI, "":
+		return &cliSource{cfg: cfg}, nil
+	case SourceMgr:
+		if cfg.MgrURL == "" {
+			return nil, errors.New("the mgr source needs a mgr URL")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.MgrInsecure {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		return &mgrSource{cfg: cfg, client: &http.Client{Transport: transport}}, nil
+	default:
+		return nil, fmt.Errorf("unknown source %q, expected %s or %s", cfg.Source, SourceCLI, SourceMgr)
+	}
+}
+
+// cliSource runs ceph status
+type cliSource struct {
+	cfg CephHealthConfig
+}
+
+func (s *cliSource) status(ctx context.Context) ([]byte, error) {
+	args := []string{"status", "--format", "json"}
+	if s.cfg.CephConf != "" {
+		args = append(args, "--conf", s.cfg.CephConf)
+	}
+	if s.cfg.CephName != "" {
+		args = append(args, "--name", s.cfg.CephName)
+	}
+	if s.cfg.CephKeyring != "" {
+		args = append(args, "--keyring", s.cfg.CephKeyring)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.cfg.CephBinary, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s status failed: %w: %s", s.cfg.CephBinary, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// mgrSource runs the status command through the restful mgr module
+type mgrSource struct {
+	cfg    CephHealthConfig
+	client *http.Client
+}
+
+// The request the restful module returns once it finished
+type mgrRequest struct {
+	HasFailed bool `json:"has_failed"`
+	Finished  []struct {
+		Outb string `json:"outb"`
+		Outs string `json:"outs"`
+	} `json:"finished"`
+	Failed []struct {
+		Outs string `json:"outs"`
+	} `json:"failed"`
+}
+
+func (s *mgrSource) status(ctx context.Context) ([]byte, error) {
+	body, _ := json.Marshal(map[string]string{"prefix": "status", "format": "json"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.cfg.MgrURL, "/")+"/request?wait=1", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(s.cfg.MgrUser, s.cfg.MgrKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("mgr returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result mgrRequest
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse mgr response: %w", err)
+	}
+	if result.HasFailed {
+		message := "unknown error"
+		if len(result.Failed) > 0 {
+			message = result.Failed[0].Outs
+		}
+		return nil, fmt.Errorf("mgr status command failed: %s", message)
+	}
+	if len(result.Finished) == 0 {
+		return nil, errors.New("mgr status command did not finish")
+	}
+	return []byte(result.Finished[0].Outb), nil
+}
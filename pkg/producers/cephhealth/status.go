@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cephhealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Health states of the cluster and of health checks
+const (
+	HealthOK   = "HEALTH_OK"
+	HealthWarn = "HEALTH_WARN"
+	HealthErr  = "HEALTH_ERR"
+)
+
+// ClusterStatus is the part of ceph status prysm exports, published on
+// NATS on every poll
+type ClusterStatus struct {
+	FSID         string         `json:"fsid"`
+	Health       string         `json:"health"`
+	Checks       []HealthCheck  `json:"checks"`
+	Mons         int            `json:"mons"`
+	MonsInQuorum int            `json:"mons_in_quorum"`
+	OSDs         int            `json:"osds"`
+	OSDsUp       int            `json:"osds_up"`
+	OSDsIn       int            `json:"osds_in"`
+	MgrAvailable bool           `json:"mgr_available"`
+	PGs          int            `json:"pgs"`
+	PGStates     map[string]int `json:"pg_states"` // PG count by combined state, e.g. active+clean
+	Objects      ObjectCounts   `json:"objects"`
+	Recovery     Throughput     `json:"recovery"` // recovery and backfill
+	Client       ClientIO       `json:"client"`
+	BytesUsed    uint64         `json:"bytes_used"`
+	BytesAvail   uint64         `json:"bytes_avail"`
+	BytesTotal   uint64         `json:"bytes_total"`
+	NodeName     string         `json:"node_name"`
+	InstanceID   string         `json:"instance_id"`
+	Timestamp    time.Time      `json:"timestamp"`
+}
+
+type HealthCheck struct {
+	Name     string `json:"name"` // e.g. OSD_DOWN
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Count    int    `json:"count"`
+	Muted    bool   `json:"muted"`
+}
+
+type ObjectCounts struct {
+	Total          int64   `json:"total"`
+	Degraded       int64   `json:"degraded"`
+	DegradedRatio  float64 `json:"degraded_ratio"`
+	Misplaced      int64   `json:"misplaced"`
+	MisplacedRatio float64 `json:"misplaced_ratio"`
+	Unfound        int64   `json:"unfound"`
+}
+
+type Throughput struct {
+	BytesPerSec   float64 `json:"bytes_per_sec"`
+	ObjectsPerSec float64 `json:"objects_per_sec"`
+	KeysPerSec    float64 `json:"keys_per_sec"`
+}
+
+type ClientIO struct {
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+}
+
+// PGStateCounts returns the PG count by single state, e.g. active, clean
+// or backfilling; a PG in active+clean counts for both
+func (s ClusterStatus) PGStateCounts() map[string]int {
+	counts := map[string]int{}
+	for combined, count := range s.PGStates {
+		for _, state := range strings.Split(combined, "+") {
+			counts[state] += count
+		}
+	}
+	return counts
+}
+
+// The JSON output of ceph status, fields prysm does not use are left out.
+// The rates only appear while there is I/O.
+type cephStatus struct {
+	FSID   string `json:"fsid"`
+	Health struct {
+		Status string `json:"status"`
+		Checks map[string]struct {
+			Severity string `json:"severity"`
+			Summary  struct {
+				Message string `json:"message"`
+				Count   int    `json:"count"`
+			} `json:"summary"`
+			Muted bool `json:"muted"`
+		} `json:"checks"`
+	} `json:"health"`
+	QuorumNames []string `json:"quorum_names"`
+	MonMap      struct {
+		NumMons int `json:"num_mons"`
+	} `json:"monmap"`
+	OSDMap struct {
+		osdCounts
+		OSDMap *osdCounts `json:"osdmap"` // before Quincy the counts were nested
+	} `json:"osdmap"`
+	MgrMap struct {
+		Available bool `json:"available"`
+	} `json:"mgrmap"`
+	PGMap struct {
+		PGsByState []struct {
+			StateName string `json:"state_name"`
+			Count     int    `json:"count"`
+		} `json:"pgs_by_state"`
+		NumPGs                  int     `json:"num_pgs"`
+		NumObjects              int64   `json:"num_objects"`
+		BytesUsed               uint64  `json:"bytes_used"`
+		BytesAvail              uint64  `json:"bytes_avail"`
+		BytesTotal              uint64  `json:"bytes_total"`
+		DegradedObjects         int64   `json:"degraded_objects"`
+		DegradedRatio           float64 `json:"degraded_ratio"`
+		MisplacedObjects        int64   `json:"misplaced_objects"`
+		MisplacedRatio          float64 `json:"misplaced_ratio"`
+		UnfoundObjects          int64   `json:"unfound_objects"`
+		RecoveringBytesPerSec   float64 `json:"recovering_bytes_per_sec"`
+		RecoveringObjectsPerSec float64 `json:"recovering_objects_per_sec"`
+		RecoveringKeysPerSec    float64 `json:"recovering_keys_per_sec"`
+		ReadBytesSec            float64 `json:"read_bytes_sec"`
+		WriteBytesSec           float64 `json:"write_bytes_sec"`
+		ReadOpPerSec            float64 `json:"read_op_per_sec"`
+		WriteOpPerSec           float64 `json:"write_op_per_sec"`
+	} `json:"pgmap"`
+}
+
+type osdCounts struct {
+	NumOSDs   int `json:"num_osds"`
+	NumUpOSDs int `json:"num_up_osds"`
+	NumInOSDs int `json:"num_in_osds"`
+}
+
+// parseStatus converts the JSON output of ceph status
+func parseStatus(data []byte) (ClusterStatus, error) {
+	var raw cephStatus
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ClusterStatus{}, fmt.Errorf("failed to parse ceph status: %w", err)
+	}
+	if raw.Health.Status == "" {
+		return ClusterStatus{}, fmt.Errorf("ceph status has no health")
+	}
+
+	osds := raw.OSDMap.osdCounts
+	if raw.OSDMap.OSDMap != nil {
+		osds = *raw.OSDMap.OSDMap
+	}
+	pgmap := raw.PGMap
+	status := ClusterStatus{
+		FSID:         raw.FSID,
+		Health:       raw.Health.Status,
+		Checks:       []HealthCheck{},
+		Mons:         raw.MonMap.NumMons,
+		MonsInQuorum: len(raw.QuorumNames),
+		OSDs:         osds.NumOSDs,
+		OSDsUp:       osds.NumUpOSDs,
+		OSDsIn:       osds.NumInOSDs,
+		MgrAvailable: raw.MgrMap.Available,
+		PGs:          pgmap.NumPGs,
+		PGStates:     map[string]int{},
+		Objects: ObjectCounts{
+			Total:          pgmap.NumObjects,
+			Degraded:       pgmap.DegradedObjects,
+			DegradedRatio:  pgmap.DegradedRatio,
+			Misplaced:      pgmap.MisplacedObjects,
+			MisplacedRatio: pgmap.MisplacedRatio,
+			Unfound:        pgmap.UnfoundObjects,
+		},
+		Recovery: Throughput{
+			BytesPerSec:   pgmap.RecoveringBytesPerSec,
+			ObjectsPerSec: pgmap.RecoveringObjectsPerSec,
+			KeysPerSec:    pgmap.RecoveringKeysPerSec,
+		},
+		Client: ClientIO{
+			ReadBytesPerSec:  pgmap.ReadBytesSec,
+			WriteBytesPerSec: pgmap.WriteBytesSec,
+			ReadOpsPerSec:    pgmap.ReadOpPerSec,
+			WriteOpsPerSec:   pgmap.WriteOpPerSec,
+		},
+		BytesUsed:  pgmap.BytesUsed,
+		BytesAvail: pgmap.BytesAvail,
+		BytesTotal: pgmap.BytesTotal,
+	}
+	for _, state := range pgmap.PGsByState {
+		status.PGStates[state.StateName] += state.Count
+	}
+	for name, check := range raw.Health.Checks {
+		status.Checks = append(status.Checks, HealthCheck{
+			Name:     name,
+			Severity: check.Severity,
+			Message:  check.Summary.Message,
+			Count:    check.Summary.Count,
+			Muted:    check.Muted,
+		})
+	}
+	sort.Slice(status.Checks, func(i, j int) bool { return status.Checks[i].Name < status.Checks[j].Name })
+	return status, nil
+}
+
+// healthValue maps a health state to 0 (ok), 1 (warn) or 2 (error)
+func healthValue(health string) float64 {
+	switch health {
+	case HealthOK:
+		return 0
+	case HealthWarn:
+		return 1
+	default:
+		return 2
+	}
+}
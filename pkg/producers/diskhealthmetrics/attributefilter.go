@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// attributeFilter selects the SMART attributes exported as the
+// smart_attributes metric. Events published to NATS always carry all
+// attributes. A nil filter exports every attribute.
+type attributeFilter struct {
+	include map[string]bool // exported attributes, all if empty
+	exclude map[string]bool // attributes never exported, wins over include
+}
+
+// prometheusAttributeFilter is set from the configuration by StartMonitoring.
+var prometheusAttributeFilter *attributeFilter
+
+// newAttributeFilter builds a filter from lists of ATA attribute IDs (e.g.
+// "5") and attribute keys (e.g. "reallocated_sector_ct" or
+// "Reallocated_Sector_Ct"). It returns nil if both lists are empty.
+func newAttributeFilter(include, exclude []string) (*attributeFilter, error) {
+	includeKeys, err := attributeKeys(include)
+	if err != nil {
+		return nil, err
+	}
+	excludeKeys, err := attributeKeys(exclude)
+	if err != nil {
+		return nil, err
+	}
+	if len(includeKeys) == 0 && len(excludeKeys) == 0 {
+		return nil, nil
+	}
+	return &attributeFilter{include: includeKeys, exclude: excludeKeys}, nil
+}
+
+// exports reports whether the attribute with the normalized name is exported.
+func (f *attributeFilter) exports(name string) bool {
+	if f == nil {
+		return true
+	}
+	key := attributeKey(name)
+	if f.exclude[key] {
+		return false
+	}
+	return len(f.include) == 0 || f.include[key]
+}
+
+// attributeKeys resolves attribute IDs and keys to normalized attribute names.
+func attributeKeys(entries []string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, err := strconv.Atoi(entry)
+		if err != nil {
+			keys[attributeKey(entry)] = true
+			continue
+		}
+		key, found := attributeKeyByID(id)
+		if !found {
+			return nil, fmt.Errorf("unknown SMART attribute ID %d", id)
+		}
+		keys[key] = true
+	}
+	return keys, nil
+}
+
+// attributeKeyByID returns the normalized name of a standard ATA attribute.
+func attributeKeyByID(id int) (string, bool) {
+	attr, found := lookupSMARTAttribute(id)
+	if !found {
+		return "", false
+	}
+	return attributeKey(attr.Key), true
+}
+
+// attributeKey normalizes a smartctl attribute name the way collected
+// attributes are named, resolving aliases.
+func attributeKey(name string) string {
+	key := strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	if resolved, found := aliasMap[key]; found {
+		return resolved
+	}
+	return key
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeFilter(t *testing.T) {
+	filter, err := newAttributeFilter(nil, []string{""})
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.exports("reallocated_sector_ct"), "a nil filter exports everything")
+
+	filter, err = newAttributeFilter([]string{"5", " 197", "Temperature_Celsius", "current_drive_temperature"}, nil)
+	require.NoError(t, err)
+	assert.True(t, filter.exports("reallocated_sector_ct"))
+	assert.True(t, filter.exports("current_pending_sector"))
+	assert.True(t, filter.exports("temperature_celsius"))
+	assert.False(t, filter.exports("power_on_hours"))
+
+	filter, err = newAttributeFilter(nil, []string{"G-Sense_Error_Rate", "9"})
+	require.NoError(t, err)
+	assert.False(t, filter.exports("g_sense_error_rate"))
+	assert.False(t, filter.exports("power_on_hours"))
+	assert.True(t, filter.exports("reallocated_sector_ct"))
+
+	filter, err = newAttributeFilter([]string{"5", "9"}, []string{"power_on_hours"})
+	require.NoError(t, err)
+	assert.True(t, filter.exports("reallocated_sector_ct"))
+	assert.False(t, filter.exports("power_on_hours"), "exclusion wins over inclusion")
+
+	_, err = newAttributeFilter([]string{"999"}, nil)
+	assert.Error(t, err)
+}
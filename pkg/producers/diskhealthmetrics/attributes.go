@@ -104,3 +104,13 @@ var SMARTAttributes = []SMARTAttribute{
 	// {ID: 252, Key: "Newly_Added_Bad_Flash_Block", Name: "Newly Added Bad Flash Block", Critical: false, Description: "Number of newly added bad flash blocks", PromName: "disk_newly_added_bad_flash_block", PromHelp: "Number of newly added bad flash blocks"},
 	// {ID: 254, Key: "Free_Fall_Protection", Name: "Free Fall Protection", Critical: false, Description: "Free fall protection enabled", PromName: "disk_free_fall_protection", PromHelp: "Free fall protection enabled"},
 }
+
+// lookupSMARTAttribute returns the known ATA attribute with the given ID.
+func lookupSMARTAttribute(id int) (SMARTAttribute, bool) {
+	for _, attr := range SMARTAttributes {
+		if attr.ID == id {
+			return attr, true
+		}
+	}
+	return SMARTAttribute{}, false
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// cephVolumeLVMEntry is a single logical volume from `ceph-volume lvm list --format json`.
+type cephVolumeLVMEntry struct {
+	Devices []string          `json:"devices"`
+	Type    string            `json:"type"`
+	Tags    map[string]string `json:"tags"`
+}
+
+// cephVolumeRawEntry is a single OSD from `ceph-volume raw list --format json`.
+type cephVolumeRawEntry struct {
+	CephFSID string `json:"ceph_fsid"`
+	Device   string `json:"device"`
+	OSDID    int64  `json:"osd_id"`
+}
+
+// mapCephVolumeOSDs adds the OSDs known to ceph-volume to the mapping cache
+// and returns the number of mapped devices. It is a no-op when ceph-volume is
+// not installed.
+func mapCephVolumeOSDs() int {
+	if _, err := exec.LookPath("ceph-volume"); err != nil {
+		return 0
+	}
+
+	mappingCount := 0
+
+	if out, err := exec.Command("ceph-volume", "lvm", "list", "--format", "json").Output(); err != nil {
+		log.Warn().Err(err).Msg("failed to run ceph-volume lvm list")
+	} else if mappings, err := parseCephVolumeLVMList(out); err != nil {
+		log.Warn().Err(err).Msg("failed to parse ceph-volume lvm list output")
+	} else {
+		mappingCount += addOSDMappings(mappings)
+	}
+
+	if out, err := exec.Command("ceph-volume", "raw", "list", "--format", "json").Output(); err != nil {
+		log.Debug().Err(err).Msg("failed to run ceph-volume raw list")
+	} else if mappings, err := parseCephVolumeRawList(out); err != nil {
+		log.Warn().Err(err).Msg("failed to parse ceph-volume raw list output")
+	} else {
+		mappingCount += addOSDMappings(mappings)
+	}
+
+	log.Info().Int("mappings", mappingCount).Msg("ceph-volume OSD mapping loaded")
+	return mappingCount
+}
+
+// parseCephVolumeLVMList maps the physical devices of every block/data LV to
+// its OSD. WAL and DB volumes are skipped so a shared DB device does not get
+// attributed to a single OSD.
+func parseCephVolumeLVMList(out []byte) (map[string]cephOSD, error) {
+	var list map[string][]cephVolumeLVMEntry
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("error parsing ceph-volume lvm list JSON: %v", err)
+	}
+
+	mappings := make(map[string]cephOSD)
+	for osdID, volumes := range list {
+		for _, volume := range volumes {
+			if volume.Type != "block" && volume.Type != "data" {
+				continue
+			}
+			osd := cephOSD{ID: osdID, ClusterFSID: volume.Tags["ceph.cluster_fsid"]}
+			for _, device := range volume.Devices {
+				mappings[device] = osd
+			}
+		}
+	}
+	return mappings, nil
+}
+
+// parseCephVolumeRawList maps raw-mode OSD devices to their OSD.
+func parseCephVolumeRawList(out []byte) (map[string]cephOSD, error) {
+	var list map[string]cephVolumeRawEntry
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("error parsing ceph-volume raw list JSON: %v", err)
+	}
+
+	mappings := make(map[string]cephOSD)
+	for _, entry := range list {
+		if entry.Device == "" {
+			continue
+		}
+		mappings[entry.Device] = cephOSD{ID: strconv.FormatInt(entry.OSDID, 10), ClusterFSID: entry.CephFSID}
+	}
+	return mappings, nil
+}
+
+// addOSDMappings stores the mappings under both the reported and the
+// normalized device path.
+func addOSDMappings(mappings map[string]cephOSD) int {
+	for device, osd := range mappings {
+		physicalDeviceToOSDCache[device] = osd
+		if normalizedDevice := normalizeDevicePath(device); normalizedDevice != device {
+			physicalDeviceToOSDCache[normalizedDevice] = osd
+		}
+	}
+	return len(mappings)
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCephVolumeLVMList(t *testing.T) {
+	out := []byte(`{
+		"0": [
+			{"devices": ["/dev/sdb"], "type": "block", "tags": {"ceph.cluster_fsid": "fsid-a", "ceph.osd_id": "0"}},
+			{"devices": ["/dev/nvme0n1"], "type": "db", "tags": {"ceph.cluster_fsid": "fsid-a", "ceph.osd_id": "0"}}
+		],
+		"7": [
+			{"devices": ["/dev/sdc"], "type": "block", "tags": {"ceph.cluster_fsid": "fsid-a", "ceph.osd_id": "7"}}
+		]
+	}`)
+
+	mappings, err := parseCephVolumeLVMList(out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cephOSD{
+		"/dev/sdb": {ID: "0", ClusterFSID: "fsid-a"},
+		"/dev/sdc": {ID: "7", ClusterFSID: "fsid-a"},
+	}, mappings)
+}
+
+func TestParseCephVolumeRawList(t *testing.T) {
+	out := []byte(`{
+		"2b1f7c5e": {"ceph_fsid": "fsid-b", "device": "/dev/sdd", "osd_id": 12, "type": "bluestore"}
+	}`)
+
+	mappings, err := parseCephVolumeRawList(out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cephOSD{"/dev/sdd": {ID: "12", ClusterFSID: "fsid-b"}}, mappings)
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiskChangeEvent describes a change of a device's health between two
+// collections. It carries the full normalized DeviceInfo so consumers can act
+// (open tickets, drain OSDs) without looking the device up.
+type DiskChangeEvent struct {
+	NodeName    string      `json:"node_name"`
+	InstanceID  string      `json:"instance_id"`
+	Zone        string      `json:"zone,omitempty"`
+	Rack        string      `json:"rack,omitempty"`
+	Chassis     string      `json:"chassis,omitempty"`
+	Enclosure   string      `json:"enclosure,omitempty"`
+	Slot        string      `json:"slot,omitempty"`
+	Device      string      `json:"device"`
+	OSDID       string      `json:"osd_id,omitempty"`
+	CephCluster string      `json:"ceph_cluster,omitempty"`
+	EventType   string      `json:"event_type"` // attribute_changed or smart_status_changed
+	Severity    string      `json:"severity"`   // info, warning or critical
+	Attribute   string      `json:"attribute,omitempty"`
+	Previous    int64       `json:"previous"`
+	Current     int64       `json:"current"`
+	Delta       int64       `json:"delta"`
+	Message     string      `json:"message"`
+	DeviceInfo  *DeviceInfo `json:"device_info"`
+	Timestamp   time.Time   `json:"timestamp"`
+}
+
+// watchedAttribute defines which change of a SMART attribute is reported.
+type watchedAttribute struct {
+	increaseIsBad bool   // report increases (counters), otherwise decreases (remaining spare)
+	severity      string // severity of a change in the bad direction
+}
+
+var watchedAttributes = map[string]watchedAttribute{
+	"reallocated_sector_ct":           {increaseIsBad: true, severity: "warning"},
+	"reallocated_event_count":         {increaseIsBad: true, severity: "warning"},
+	"current_pending_sector":          {increaseIsBad: true, severity: "warning"},
+	"offline_uncorrectable":           {increaseIsBad: true, severity: "critical"},
+	"reported_uncorrect":              {increaseIsBad: true, severity: "critical"},
+	"grown_defects_count":             {increaseIsBad: true, severity: "warning"},
+	"udma_crc_error_count":            {increaseIsBad: true, severity: "info"},
+	"total_uncorrected_read_errors":   {increaseIsBad: true, severity: "critical"},
+	"total_uncorrected_write_errors":  {increaseIsBad: true, severity: "critical"},
+	"nvme_media_errors":               {increaseIsBad: true, severity: "critical"},
+	"media_and_data_integrity_errors": {increaseIsBad: true, severity: "critical"},
+	"critical_warning":                {increaseIsBad: true, severity: "critical"},
+	"percentage_used":                 {increaseIsBad: true, severity: "info"},
+	"available_spare":                 {increaseIsBad: false, severity: "warning"},
+}
+
+type deviceSnapshot struct {
+	attributes map[string]int64
+	healthy    bool
+}
+
+var (
+	deviceSnapshots      = make(map[string]deviceSnapshot)
+	deviceSnapshotsMutex sync.Mutex
+)
+
+// detectChangeEvents compares the collected metrics with the previous
+// collection and returns one event per changed watched attribute and per
+// SMART status flip. The first collection of a device only records a baseline.
+func detectChangeEvents(metrics []NormalizedSmartData, now time.Time) []DiskChangeEvent {
+	deviceSnapshotsMutex.Lock()
+	defer deviceSnapshotsMutex.Unlock()
+
+	var events []DiskChangeEvent
+	for _, metric := range metrics {
+		if metric.DeviceInfo == nil {
+			continue
+		}
+
+		current := deviceSnapshot{
+			attributes: make(map[string]int64),
+			healthy:    metric.DeviceInfo.HealthStatus,
+		}
+		for name := range watchedAttributes {
+			if attr, ok := metric.Attributes[name]; ok {
+				current.attributes[name] = attr.RawValue
+			}
+		}
+
+		previous, seen := deviceSnapshots[metric.Device]
+		deviceSnapshots[metric.Device] = current
+		if !seen {
+			continue
+		}
+
+		location := locationOf(metric)
+		newEvent := func(eventType, severity, message string) DiskChangeEvent {
+			return DiskChangeEvent{
+				NodeName:    metric.NodeName,
+				InstanceID:  metric.InstanceID,
+				Zone:        metric.Zone,
+				Rack:        metric.Rack,
+				Chassis:     location.Chassis,
+				Enclosure:   location.Enclosure,
+				Slot:        location.Slot,
+				Device:      metric.Device,
+				OSDID:       metric.OSDID,
+				CephCluster: metric.CephCluster,
+				EventType:   eventType,
+				Severity:    severity,
+				Message:     message,
+				DeviceInfo:  metric.DeviceInfo,
+				Timestamp:   now,
+			}
+		}
+
+		if previous.healthy != current.healthy {
+			if current.healthy {
+				events = append(events, newEvent("smart_status_changed", "info", "SMART overall-health self-assessment changed to passed."))
+			} else {
+				events = append(events, newEvent("smart_status_changed", "critical", "SMART overall-health self-assessment changed to failed."))
+			}
+		}
+
+		names := make([]string, 0, len(current.attributes))
+		for name := range current.attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			before, ok := previous.attributes[name]
+			after := current.attributes[name]
+			if !ok || before == after {
+				continue
+			}
+
+			watch := watchedAttributes[name]
+			delta := after - before
+			severity := "info"
+			if (delta > 0) == watch.increaseIsBad {
+				severity = watch.severity
+			}
+
+			direction := "increased"
+			if delta < 0 {
+				direction = "decreased"
+			}
+
+			event := newEvent("attribute_changed", severity, fmt.Sprintf("%s %s by %d (%d -> %d).", name, direction, max(delta, -delta), before, after))
+			event.Attribute = name
+			event.Previous = before
+			event.Current = after
+			event.Delta = delta
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
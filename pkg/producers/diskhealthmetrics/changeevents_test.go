@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectChangeEvents(t *testing.T) {
+	device := "/dev/test-changes"
+	t.Cleanup(func() {
+		deviceSnapshotsMutex.Lock()
+		delete(deviceSnapshots, device)
+		deviceSnapshotsMutex.Unlock()
+	})
+
+	sample := func(healthy bool, reallocated, spare, powerOn int64) []NormalizedSmartData {
+		return []NormalizedSmartData{{
+			Device:     device,
+			OSDID:      "3",
+			DeviceInfo: &DeviceInfo{SerialNumber: "S1", HealthStatus: healthy},
+			Attributes: map[string]SmartAttribute{
+				"reallocated_sector_ct": {RawValue: reallocated},
+				"available_spare":       {RawValue: spare},
+				"power_on_hours":        {RawValue: powerOn},
+			},
+		}}
+	}
+
+	now := time.Now()
+	assert.Empty(t, detectChangeEvents(sample(true, 8, 100, 1000), now), "first collection is a baseline")
+	assert.Empty(t, detectChangeEvents(sample(true, 8, 100, 1001), now), "unwatched attributes are ignored")
+
+	events := detectChangeEvents(sample(false, 24, 90, 1002), now)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "smart_status_changed", events[0].EventType)
+	assert.Equal(t, "critical", events[0].Severity)
+
+	assert.Equal(t, "available_spare", events[1].Attribute)
+	assert.Equal(t, int64(-10), events[1].Delta)
+	assert.Equal(t, "warning", events[1].Severity)
+
+	assert.Equal(t, "reallocated_sector_ct", events[2].Attribute)
+	assert.Equal(t, int64(16), events[2].Delta)
+	assert.Equal(t, "warning", events[2].Severity)
+	assert.Equal(t, "3", events[2].OSDID)
+	assert.Equal(t, "S1", events[2].DeviceInfo.SerialNumber)
+
+	recovered := detectChangeEvents(sample(true, 24, 95, 1003), now)
+	require.Len(t, recovered, 2)
+	assert.Equal(t, "info", recovered[0].Severity)
+	assert.Equal(t, "info", recovered[1].Severity)
+}
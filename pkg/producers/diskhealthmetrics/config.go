@@ -4,12 +4,15 @@
 
 package diskhealthmetrics
 
+import "github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+
 type DiskHealthMetricsConfig struct {
 	NatsURL           string
 	NatsSubject       string
 	UseNats           bool
 	Prometheus        bool
 	PrometheusPort    int
+	RemoteWrite       remotewrite.Config // Pushes the Prometheus metrics when URL is set
 	AllAttributes     bool
 	Disks             []string
 	IncludeZeroValues bool
@@ -17,13 +20,133 @@ type DiskHealthMetricsConfig struct {
 	NodeName          string
 	InstanceID        string
 
+	// ExportAttributes and ExcludeAttributes select the SMART attributes
+	// exported as Prometheus metrics by ATA attribute ID or attribute key;
+	// NATS events always carry all attributes. Empty exports all.
+	ExportAttributes  []string
+	ExcludeAttributes []string
+
+	// Kubernetes mode completes the node identity from the downward API and
+	// reads Zone and Rack from the node labels unless they are set. Zone and
+	// Rack are attached to every metric, event and snapshot.
+	Kubernetes bool
+	Zone       string
+	Rack       string
+	RackLabel  string // Node label holding the rack, DefaultRackLabel by default
+
+	// ProbePort serves the /healthz and /readyz probes; they are also served
+	// on the Prometheus port. 0 disables the separate probe server.
+	ProbePort int
+
+	// PassthroughDevices are drives addressed with an explicit smartctl
+	// device type (e.g. behind RAID controllers); filled from --disks "*",
+	// PassthroughDevicesPath and DiscoverRAID.
+	PassthroughDevices     []PassthroughDevice
+	PassthroughDevicesPath string // JSON file listing passthrough devices
+	DiscoverRAID           bool   // Discover drives behind MegaRAID controllers via storcli/perccli
+
+	// Devices are collected by ScanConcurrency workers; a device that does
+	// not answer within DeviceTimeout seconds is skipped for this scan.
+	ScanConcurrency int
+	DeviceTimeout   int // in seconds, 0 disables the timeout
+
+	// ScanSchedules scan devices at their own interval instead of Interval
+	// by media type or device glob, e.g. NVMe drives more often than HDDs.
+	// The scan runs at the shortest interval and only collects the devices
+	// that are due, the others are reported as of their last scan.
+	ScanSchedules []ScanSchedule
+
+	// Hotplug watches kernel uevents (devd on FreeBSD) for disks being added or removed and
+	// rescans right away; discovered disks (--disks "*") follow the events.
+	Hotplug bool
+
+	// ChangeEventsSubject receives an event whenever a watched SMART
+	// attribute or the SMART status changes; empty disables change events.
+	ChangeEventsSubject string
+
 	// NATS event thresholds
 	GrownDefectsThreshold       int64
 	PendingSectorsThreshold     int64
 	ReallocatedSectorsThreshold int64
 	LifetimeUsedThreshold       int64 // percentage
 
+	// Temperature thresholds per media type (hdd, ssd, nvme); a level is
+	// left only after cooling TemperatureHysteresis degrees below it.
+	TemperatureThresholds map[string]TemperatureThreshold
+	TemperatureHysteresis int64
+
+	// Failure-risk score thresholds (0-100)
+	RiskWarningThreshold  float64
+	RiskCriticalThreshold float64
+
+	// EnduranceWarrantyYears is the period the DWPD rating of SSDs applies to,
+	// used to derive their rated endurance for the remaining-life projection.
+	EnduranceWarrantyYears int
+
+	// SMART history for rate-of-change metrics. HistoryPath takes precedence
+	// over HistoryKVBucket; without either the history is kept in memory.
+	HistoryPath     string // Local JSON file the history is persisted to
+	HistoryKVBucket string // NATS KV bucket the history is persisted to, requires UseNats
+
+	// Fleet snapshot written or published after every scan.
+	SnapshotPath    string // File the snapshot is written to, CSV if it ends in .csv, JSON otherwise
+	SnapshotSubject string // NATS subject the snapshot is published to, requires UseNats
+
+	// Firmware compliance report written or published after every scan,
+	// listing devices whose firmware is not approved by the device database.
+	FirmwareReportPath    string // JSON file the report is written to
+	FirmwareReportSubject string // NATS subject the report is published to, requires UseNats
+
+	// ReplacementSubject answers requests for the devices recommended for
+	// replacement (request-reply, requires UseNats); they are also served on
+	// /replacements of the Prometheus and probe ports.
+	ReplacementSubject string
+
+	// LocateSubject switches the identification LED of the slot of a drive
+	// on requests naming its serial number (request-reply, requires UseNats),
+	// with ceph orch device light or storcli.
+	LocateSubject string
+
 	CephOSDBasePath string
+	CephCluster     string // Overrides the ceph_cluster label, defaults to the OSD's cluster fsid
+
+	// DeviceDBPath points to a JSON device database merged on top of the
+	// embedded one; the file is watched and reloaded on change.
+	DeviceDBPath string
+
+	// SMART self-test orchestration
+	SelfTest                   bool   // Collect self-test status and run scheduled self-tests
+	SelfTestShortIntervalHours int    // Hours between short self-tests per device, 0 disables
+	SelfTestLongIntervalHours  int    // Hours between long self-tests per device, 0 disables
+	SelfTestWindow             string // Daily "HH:MM-HH:MM" window for starting tests, empty for any time
+	SelfTestStaggerMinutes     int    // Minimum minutes between two test starts on this node
+
+	// KernelIO joins /sys/block/<dev>/stat latencies and I/O errors from the
+	// kernel log (/dev/kmsg) with the SMART data of each device.
+	KernelIO bool
+
+	// EnclosureSlots maps every drive to the chassis, enclosure and slot it
+	// sits in, read from the kernel ses driver or sg_ses. Chassis overrides
+	// the chassis serial number read from DMI.
+	EnclosureSlots bool
+	Chassis        string
+
+	// NVMeTelemetry enables collection of endurance group, self-test and
+	// vendor log pages via nvme-cli for NVMe devices.
+	NVMeTelemetry bool
+
+	// SmartdStateDir reads ATA attributes from the state files smartd saves
+	// there (smartd --savestates) instead of polling the drives; smartctl
+	// only identifies each device once. SmartdLogPath is the log smartd
+	// reports failing drives to, the SMART status is passed without it.
+	// Self-tests are left to smartd.
+	SmartdStateDir string
+	SmartdLogPath  string
+
+	// MockSmartctlDir answers smartctl from canned JSON outputs in this
+	// directory instead of running it; unlike test mode, devices go through
+	// the regular scan. nvme-cli is not used and self-tests are not started.
+	MockSmartctlDir string
 
 	// Test mode configuration
 	TestMode     bool     // Enable test mode with simulated data
@@ -0,0 +1,414 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// embeddedDeviceDB is the built-in device normalization database. Entries can
+// be overridden or extended at runtime with --device-db.
+//
+//go:embed devicedb.json
+var embeddedDeviceDB []byte
+
+// DeviceDBEntry maps a reported device model to normalized device attributes.
+// Model may contain shell-style wildcards (e.g. "*SSDSC2KG480G8R"); every
+// other field is optional and only overwrites DeviceInfo when set.
+type DeviceDBEntry struct {
+	Model       string   `json:"model"`
+	DeviceModel string   `json:"device_model,omitempty"`
+	Product     string   `json:"product,omitempty"`
+	CapacityGB  *float64 `json:"capacity_gb,omitempty"`
+	Vendor      string   `json:"vendor,omitempty"`
+	Media       string   `json:"media,omitempty"`
+	FormFactor  string   `json:"form_factor,omitempty"`
+	DWPD        *float64 `json:"dwpd,omitempty"`
+	RPM         *int64   `json:"rpm,omitempty"`
+}
+
+// Sources of the value of a reinterpreted ATA SMART attribute.
+const (
+	AttributeSourceRaw        = "raw"          // the raw value (default)
+	AttributeSourceNormalized = "normalized"   // the normalized 1-253 value, e.g. life left in percent
+	AttributeSourceRawLowByte = "raw_low_byte" // the lowest raw byte, e.g. temperatures packed with min/max
+)
+
+// AttributeInterpretation maps a vendor-specific ATA SMART attribute ID to
+// a canonical attribute.
+type AttributeInterpretation struct {
+	Name   string `json:"name"`             // canonical attribute, e.g. percent_life_remaining
+	Source string `json:"source,omitempty"` // AttributeSource*, raw if empty
+}
+
+// AttributeProfile reinterprets ATA SMART attributes by ID for the devices
+// it matches, as the same ID means different things on different vendors
+// (231 is the temperature on some drives and the SSD life left on others).
+// Vendor, Models (may contain wildcards, one must match) and Media are
+// optional; all set fields must match the normalized DeviceInfo.
+type AttributeProfile struct {
+	Vendor     string                            `json:"vendor,omitempty"`
+	Models     []string                          `json:"models,omitempty"`
+	Media      string                            `json:"media,omitempty"`
+	Attributes map[int64]AttributeInterpretation `json:"attributes"` // keyed by attribute ID
+}
+
+// FirmwarePolicy lists the approved firmware versions of the devices it
+// matches, selected like an AttributeProfile. Approved versions may contain
+// wildcards, e.g. "XCV1*".
+type FirmwarePolicy struct {
+	Vendor   string   `json:"vendor,omitempty"`
+	Models   []string `json:"models,omitempty"`
+	Media    string   `json:"media,omitempty"`
+	Approved []string `json:"approved"`
+}
+
+type deviceDBFile struct {
+	Devices           []DeviceDBEntry    `json:"devices"`
+	AttributeProfiles []AttributeProfile `json:"attribute_profiles,omitempty"`
+	FirmwarePolicies  []FirmwarePolicy   `json:"firmware_policies,omitempty"`
+}
+
+// DeviceDB resolves device models to normalization entries. Exact model
+// matches win over wildcard patterns; patterns are tried in file order.
+type DeviceDB struct {
+	exact    map[string]DeviceDBEntry
+	patterns []DeviceDBEntry
+	profiles []AttributeProfile // override profiles first, then base profiles
+	policies []FirmwarePolicy   // override policies first, then base policies
+}
+
+var (
+	deviceDBMu     sync.RWMutex
+	activeDeviceDB *DeviceDB
+
+	defaultDeviceDB = sync.OnceValues(func() (*DeviceDB, error) {
+		return newDeviceDB(embeddedDeviceDB, nil)
+	})
+)
+
+// newDeviceDB builds a DeviceDB from the base database and an optional
+// override database. Override entries replace base entries with the same
+// model and their patterns take precedence over base patterns.
+func newDeviceDB(base, override []byte) (*DeviceDB, error) {
+	var baseFile, overrideFile deviceDBFile
+	if err := json.Unmarshal(base, &baseFile); err != nil {
+		return nil, fmt.Errorf("failed to parse base device database: %w", err)
+	}
+	if override != nil {
+		if err := json.Unmarshal(override, &overrideFile); err != nil {
+			return nil, fmt.Errorf("failed to parse device database override: %w", err)
+		}
+	}
+
+	db := &DeviceDB{exact: make(map[string]DeviceDBEntry)}
+	var overridePatterns, basePatterns []DeviceDBEntry
+	for _, entry := range baseFile.Devices {
+		if isDeviceModelPattern(entry.Model) {
+			basePatterns = append(basePatterns, entry)
+			continue
+		}
+		db.exact[entry.Model] = entry
+	}
+	for _, entry := range overrideFile.Devices {
+		if entry.Model == "" {
+			return nil, fmt.Errorf("device database override contains an entry without a model")
+		}
+		if isDeviceModelPattern(entry.Model) {
+			if _, err := path.Match(entry.Model, ""); err != nil {
+				return nil, fmt.Errorf("invalid model pattern %q: %w", entry.Model, err)
+			}
+			overridePatterns = append(overridePatterns, entry)
+			continue
+		}
+		db.exact[entry.Model] = entry
+	}
+	db.patterns = append(overridePatterns, basePatterns...)
+
+	for _, profile := range overrideFile.AttributeProfiles {
+		if err := profile.validate(); err != nil {
+			return nil, fmt.Errorf("device database override: %w", err)
+		}
+	}
+	db.profiles = append(overrideFile.AttributeProfiles, baseFile.AttributeProfiles...)
+
+	for _, policy := range overrideFile.FirmwarePolicies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("device database override: %w", err)
+		}
+	}
+	db.policies = append(overrideFile.FirmwarePolicies, baseFile.FirmwarePolicies...)
+
+	return db, nil
+}
+
+func (p AttributeProfile) validate() error {
+	if err := validateModelPatterns(p.Models); err != nil {
+		return fmt.Errorf("attribute profile: %w", err)
+	}
+	known := GetSmartAttributes()
+	for id, interpretation := range p.Attributes {
+		if _, ok := known[interpretation.Name]; !ok {
+			return fmt.Errorf("attribute %d is mapped to unknown attribute %q", id, interpretation.Name)
+		}
+		switch interpretation.Source {
+		case "", AttributeSourceRaw, AttributeSourceNormalized, AttributeSourceRawLowByte:
+		default:
+			return fmt.Errorf("attribute %d has unknown source %q", id, interpretation.Source)
+		}
+	}
+	return nil
+}
+
+func (p FirmwarePolicy) validate() error {
+	if err := validateModelPatterns(p.Models); err != nil {
+		return fmt.Errorf("firmware policy: %w", err)
+	}
+	if len(p.Approved) == 0 {
+		return fmt.Errorf("firmware policy for %v approves no firmware version", p.Models)
+	}
+	for _, version := range p.Approved {
+		if _, err := path.Match(version, ""); err != nil {
+			return fmt.Errorf("invalid firmware version pattern %q in firmware policy: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func validateModelPatterns(models []string) error {
+	for _, model := range models {
+		if _, err := path.Match(model, ""); err != nil {
+			return fmt.Errorf("invalid model pattern %q: %w", model, err)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the profile applies to the device.
+func (p AttributeProfile) matches(deviceInfo *DeviceInfo) bool {
+	return deviceMatches(p.Vendor, p.Models, p.Media, deviceInfo)
+}
+
+// matches reports whether the policy applies to the device.
+func (p FirmwarePolicy) matches(deviceInfo *DeviceInfo) bool {
+	return deviceMatches(p.Vendor, p.Models, p.Media, deviceInfo)
+}
+
+// approves reports whether the firmware version is approved by the policy.
+func (p FirmwarePolicy) approves(version string) bool {
+	for _, approved := range p.Approved {
+		if matched, _ := path.Match(approved, version); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceMatches reports whether the set vendor, model patterns and media
+// match the normalized device information.
+func deviceMatches(vendor string, models []string, media string, deviceInfo *DeviceInfo) bool {
+	if vendor != "" && !strings.EqualFold(vendor, deviceInfo.Vendor) {
+		return false
+	}
+	if media != "" && media != deviceInfo.Media {
+		return false
+	}
+	if len(models) == 0 {
+		return true
+	}
+	for _, model := range models {
+		if matched, _ := path.Match(model, deviceInfo.DeviceModel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributeInterpretations returns the interpretation of every attribute ID
+// reinterpreted for the device. Profiles are tried in file order, override
+// profiles first, and the first profile mapping an ID wins.
+func (db *DeviceDB) AttributeInterpretations(deviceInfo *DeviceInfo) map[int64]AttributeInterpretation {
+	interpretations := make(map[int64]AttributeInterpretation)
+	for _, profile := range db.profiles {
+		if !profile.matches(deviceInfo) {
+			continue
+		}
+		for id, interpretation := range profile.Attributes {
+			if _, ok := interpretations[id]; !ok {
+				interpretations[id] = interpretation
+			}
+		}
+	}
+	return interpretations
+}
+
+// FirmwarePolicy returns the first firmware policy matching the device,
+// override policies first.
+func (db *DeviceDB) FirmwarePolicy(deviceInfo *DeviceInfo) (FirmwarePolicy, bool) {
+	for _, policy := range db.policies {
+		if policy.matches(deviceInfo) {
+			return policy, true
+		}
+	}
+	return FirmwarePolicy{}, false
+}
+
+// value returns the attribute value selected by the interpretation's source.
+func (i AttributeInterpretation) value(entry SmartCtlATASMARTEntry) int64 {
+	switch i.Source {
+	case AttributeSourceNormalized:
+		return entry.Value
+	case AttributeSourceRawLowByte:
+		return entry.Raw.Value & 0xff
+	default:
+		return entry.Raw.Value
+	}
+}
+
+func isDeviceModelPattern(model string) bool {
+	for _, c := range model {
+		if c == '*' || c == '?' || c == '[' {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the normalization entry for the given device model.
+func (db *DeviceDB) Lookup(model string) (DeviceDBEntry, bool) {
+	if entry, ok := db.exact[model]; ok {
+		return entry, true
+	}
+	for _, entry := range db.patterns {
+		if matched, _ := path.Match(entry.Model, model); matched {
+			return entry, true
+		}
+	}
+	return DeviceDBEntry{}, false
+}
+
+// Apply copies all fields set in the entry onto deviceInfo.
+func (e DeviceDBEntry) Apply(deviceInfo *DeviceInfo) {
+	if e.DeviceModel != "" {
+		deviceInfo.DeviceModel = e.DeviceModel
+	}
+	if e.Product != "" {
+		deviceInfo.Product = e.Product
+	}
+	if e.CapacityGB != nil {
+		deviceInfo.Capacity = *e.CapacityGB
+	}
+	if e.Vendor != "" {
+		deviceInfo.Vendor = e.Vendor
+	}
+	if e.Media != "" {
+		deviceInfo.Media = e.Media
+	}
+	if e.FormFactor != "" {
+		deviceInfo.FormFactor = e.FormFactor
+	}
+	if e.DWPD != nil {
+		deviceInfo.DWPD = *e.DWPD
+	}
+	if e.RPM != nil {
+		deviceInfo.RPM = *e.RPM
+	}
+}
+
+// currentDeviceDB returns the database loaded via LoadDeviceDB, falling back
+// to the embedded database.
+func currentDeviceDB() *DeviceDB {
+	deviceDBMu.RLock()
+	db := activeDeviceDB
+	deviceDBMu.RUnlock()
+	if db != nil {
+		return db
+	}
+
+	db, err := defaultDeviceDB()
+	if err != nil {
+		log.Error().Err(err).Msg("embedded device database is invalid")
+		return &DeviceDB{exact: map[string]DeviceDBEntry{}}
+	}
+	return db
+}
+
+// LoadDeviceDB merges the device database at overridePath on top of the
+// embedded database and makes the result active for NormalizeDeviceInfo.
+func LoadDeviceDB(overridePath string) error {
+	override, err := os.ReadFile(overridePath)
+	if err != nil {
+		return fmt.Errorf("failed to read device database %s: %w", overridePath, err)
+	}
+
+	db, err := newDeviceDB(embeddedDeviceDB, override)
+	if err != nil {
+		return err
+	}
+
+	deviceDBMu.Lock()
+	activeDeviceDB = db
+	deviceDBMu.Unlock()
+
+	log.Info().
+		Str("path", overridePath).
+		Int("exact_models", len(db.exact)).
+		Int("model_patterns", len(db.patterns)).
+		Int("attribute_profiles", len(db.profiles)).
+		Int("firmware_policies", len(db.policies)).
+		Msg("device database loaded")
+	return nil
+}
+
+// WatchDeviceDB reloads the device database whenever the file at
+// overridePath changes. The parent directory is watched so that atomic
+// replacements (e.g. Kubernetes ConfigMap updates) are picked up. A database
+// that fails to load is logged and the previous one stays active.
+func WatchDeviceDB(overridePath string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create device database watcher")
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(overridePath)); err != nil {
+		log.Error().Err(err).Str("path", overridePath).Msg("failed to watch device database")
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if _, err := os.Stat(overridePath); err != nil {
+				continue
+			}
+			if err := LoadDeviceDB(overridePath); err != nil {
+				log.Error().Err(err).Msg("failed to reload device database, keeping previous version")
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Msg("device database watcher error")
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceDB_EmbeddedExactAndWildcard(t *testing.T) {
+	db, err := defaultDeviceDB()
+	require.NoError(t, err)
+
+	info := &DeviceInfo{DeviceModel: "INTEL SSDSC2BX200G4R"}
+	entry, ok := db.Lookup(info.DeviceModel)
+	require.True(t, ok)
+	entry.Apply(info)
+	assert.Equal(t, "SSDSC2BX200G4R", info.DeviceModel)
+	assert.Equal(t, "S3610", info.Product)
+	assert.Equal(t, 200.0, info.Capacity)
+	assert.Equal(t, 3.0, info.DWPD)
+
+	info = &DeviceInfo{DeviceModel: "INTEL SSDSC2KG480G8R", RPM: 42}
+	entry, ok = db.Lookup(info.DeviceModel)
+	require.True(t, ok, "wildcard entry should match vendor-prefixed model")
+	entry.Apply(info)
+	assert.Equal(t, "INTEL SSDSC2KG480G8R", info.DeviceModel, "entries without device_model keep the reported model")
+	assert.Equal(t, "S4610", info.Product)
+	assert.Equal(t, int64(42), info.RPM, "unset fields must not be overwritten")
+
+	_, ok = db.Lookup("UNKNOWN MODEL")
+	assert.False(t, ok)
+}
+
+func TestDeviceDB_OverridePrecedence(t *testing.T) {
+	base := []byte(`{"devices": [
+		{"model": "MODEL-A", "product": "base-a"},
+		{"model": "MODEL-*", "product": "base-wildcard"}
+	]}`)
+	override := []byte(`{"devices": [
+		{"model": "MODEL-A", "product": "override-a"},
+		{"model": "MODEL-B*", "product": "override-wildcard"}
+	]}`)
+
+	db, err := newDeviceDB(base, override)
+	require.NoError(t, err)
+
+	entry, _ := db.Lookup("MODEL-A")
+	assert.Equal(t, "override-a", entry.Product)
+	entry, _ = db.Lookup("MODEL-B1")
+	assert.Equal(t, "override-wildcard", entry.Product)
+	entry, _ = db.Lookup("MODEL-C")
+	assert.Equal(t, "base-wildcard", entry.Product)
+
+	_, err = newDeviceDB(base, []byte(`{"devices": [{"product": "no-model"}]}`))
+	assert.Error(t, err)
+	_, err = newDeviceDB(base, []byte(`{"devices": [{"model": "[bad"}]}`))
+	assert.Error(t, err)
+}
+
+func TestDeviceDB_AttributeProfiles(t *testing.T) {
+	base := []byte(`{"devices": [], "attribute_profiles": [
+		{"models": ["VENDOR-SSD*"], "attributes": {"231": {"name": "percent_life_remaining", "source": "normalized"}}},
+		{"attributes": {"194": {"name": "temperature_celsius", "source": "raw_low_byte"}}}
+	]}`)
+	override := []byte(`{"devices": [], "attribute_profiles": [
+		{"vendor": "intel", "media": "ssd", "attributes": {"231": {"name": "temperature_celsius"}}}
+	]}`)
+
+	db, err := newDeviceDB(base, override)
+	require.NoError(t, err)
+
+	interpretations := db.AttributeInterpretations(&DeviceInfo{DeviceModel: "VENDOR-SSD-960", Media: "ssd"})
+	assert.Equal(t, AttributeInterpretation{Name: "percent_life_remaining", Source: AttributeSourceNormalized}, interpretations[231])
+	assert.Equal(t, "temperature_celsius", interpretations[194].Name, "profiles without filters match every device")
+
+	interpretations = db.AttributeInterpretations(&DeviceInfo{DeviceModel: "VENDOR-SSD-960", Vendor: "Intel", Media: "ssd"})
+	assert.Equal(t, AttributeInterpretation{Name: "temperature_celsius"}, interpretations[231], "override profiles take precedence")
+
+	interpretations = db.AttributeInterpretations(&DeviceInfo{DeviceModel: "OTHER", Vendor: "Intel", Media: "hdd"})
+	assert.NotContains(t, interpretations, int64(231))
+
+	_, err = newDeviceDB(base, []byte(`{"attribute_profiles": [{"attributes": {"231": {"name": "no_such_attribute"}}}]}`))
+	assert.ErrorContains(t, err, "unknown attribute")
+	_, err = newDeviceDB(base, []byte(`{"attribute_profiles": [{"attributes": {"231": {"name": "temperature_celsius", "source": "high_byte"}}}]}`))
+	assert.ErrorContains(t, err, "unknown source")
+	_, err = newDeviceDB(base, []byte(`{"attribute_profiles": [{"models": ["[bad"], "attributes": {}}]}`))
+	assert.Error(t, err)
+}
+
+func TestProcessATASmartAttributes_VendorInterpretation(t *testing.T) {
+	db, err := defaultDeviceDB()
+	require.NoError(t, err)
+
+	// Attribute 231 and 241 as a Kingston DC500M reports them.
+	output := &SmartCtlOutput{ATASMARTAttributes: &SmartCtlATASMARTAttributes{Table: []SmartCtlATASMARTEntry{
+		{ID: 194, Name: "Temperature_Celsius", Value: 68, Raw: SmartCtlATASMARTRaw{Value: 0x00370014001f, String: "31 (Min/Max 20/55)"}},
+		{ID: 231, Name: "Temperature_Celsius", Value: 97, Raw: SmartCtlATASMARTRaw{Value: 3}},
+		{ID: 241, Name: "Total_LBAs_Written", Value: 100, Raw: SmartCtlATASMARTRaw{Value: 5120}},
+	}}}
+
+	smartAttrs := GetSmartAttributes()
+	ProcessAndUpdateATASmartAttributes(smartAttrs, output, db.AttributeInterpretations(&DeviceInfo{DeviceModel: "KINGSTON SEDC500M960G", Media: "ssd"}))
+	assert.Equal(t, int64(3), smartAttrs["percent_life_remaining"].Value, "231 is the normalized life left, reported as percent used")
+	assert.Equal(t, int64(5120), smartAttrs["host_writes_gib"].RawValue)
+	assert.Equal(t, int64(31), smartAttrs["temperature_celsius"].RawValue, "temperature is the low byte of the raw value")
+
+	// Without a matching profile 231 keeps the name smartctl reports.
+	smartAttrs = GetSmartAttributes()
+	ProcessAndUpdateATASmartAttributes(smartAttrs, output, nil)
+	assert.Equal(t, int64(3), smartAttrs["temperature_celsius"].RawValue)
+	assert.Equal(t, int64(-1), smartAttrs["percent_life_remaining"].Value)
+}
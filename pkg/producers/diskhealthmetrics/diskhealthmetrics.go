@@ -5,17 +5,24 @@
 package diskhealthmetrics
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"syscall"
 	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
 
-func collectDiskHealthMetrics(cfg DiskHealthMetricsConfig) []NormalizedSmartData {
+func collectDiskHealthMetrics(ctx context.Context, cfg DiskHealthMetricsConfig, schedule *scanSchedule) []NormalizedSmartData {
 	var allMetrics []NormalizedSmartData
 
 	// Check for test mode
@@ -23,61 +30,186 @@ func collectDiskHealthMetrics(cfg DiskHealthMetricsConfig) []NormalizedSmartData
 		return collectTestDiskHealthMetrics(cfg)
 	}
 
-	// Check if nvme-cli is available for enhanced NVMe support
-	nvmeCliAvailable := checkNVMeCliInstalled()
+	// Check if nvme-cli is available for enhanced NVMe support, canned
+	// smartctl outputs have no devices for it to read
+	nvmeCliAvailable := cfg.MockSmartctlDir == "" && checkNVMeCliInstalled()
 	if nvmeCliAvailable {
 		log.Info().Msg("nvme-cli detected, enhanced NVMe metrics will be available")
 	}
 
-	for _, disk := range cfg.Disks {
-		//FIXME rawData, err := collectSmartData(fmt.Sprintf("/dev/%s", disk))
-		rawData, err := collectSmartData(disk)
-		// rawData, err := collectSmartDataFromFile("../mat/devicehealth/nvme0.json")
-		// rawData, err := collectSmartDataFromFile("../mat/devicehealth/sdl.json")
-		if err != nil {
-			log.Error().Err(err).Str("disk", disk).Msg("error running smartctl")
+	// Drives reachable through several paths (dm-multipath, dual-ported SAS
+	// behind expanders) are reported once, with their paths attached.
+	topology := discoverMultipathTopology(sysBlockPath)
+	seenDevices := make(map[string]int) // device identity -> index in allMetrics
+
+	var enclosures *enclosureMap
+	if cfg.EnclosureSlots {
+		enclosures = discoverEnclosures(ctx, sysEnclosurePath, sysSCSIGenericPath, cfg.Chassis)
+	}
+
+	// With scan schedules only the devices that are due are collected, the
+	// others are reported as of their last scan.
+	targets := collectionTargets(cfg)
+	scanned := targets
+	start := time.Now()
+	if schedule != nil {
+		scanned = schedule.due(targets, start)
+	}
+	results := scanTargets(ctx, scanned, cfg.ScanConcurrency, time.Duration(cfg.DeviceTimeout)*time.Second,
+		func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error) {
+			return collectTarget(ctx, cfg, target, topology, enclosures, nvmeCliAvailable)
+		})
+	scanDuration := time.Since(start)
+	log.Debug().Int("devices", len(scanned)).Int("skipped", len(targets)-len(scanned)).Dur("duration", scanDuration).Msg("disk scan completed")
+
+	if cfg.Prometheus {
+		publishScanResults(scanned, results, scanDuration, cfg)
+	}
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Error().Err(result.err).Str("disk", scanned[i].path).Str("device_type", scanned[i].deviceType).Str("reason", result.reason).Msg("error collecting disk health metrics")
+		}
+	}
+	if schedule != nil {
+		schedule.record(scanned, results, start)
+		results = schedule.latest(targets)
+	}
+
+	// Results are in target order, so the first path to a device wins as
+	// with a sequential scan.
+	for i, result := range results {
+		disk := targets[i].path
+		if result.err != nil || result.metric == nil {
+			continue // failed, or not started because the scan was canceled
+		}
+
+		if idx, seen := seenDevices[result.identity]; result.identity != "" && seen {
+			addDevicePath(&allMetrics[idx], disk)
+			log.Debug().Str("disk", disk).Str("device", allMetrics[idx].Device).Msg("skipping additional path to an already collected device")
 			continue
 		}
 
-		// Enhance NVMe devices with nvme-cli data if available
-		var nvmeController *NVMeIDControllerOutput
-		var nvmeErrors *NVMeErrorLogOutput
+		if result.identity != "" {
+			seenDevices[result.identity] = len(allMetrics)
+		}
+		allMetrics = append(allMetrics, *result.metric)
+	}
 
-		if nvmeCliAvailable && rawData.Device.Protocol == "NVMe" {
-			nvmeController, err = collectNVMeControllerData(disk)
-			if err != nil {
-				log.Warn().Err(err).Str("disk", disk).Msg("failed to collect NVMe controller data, continuing with smartctl only")
-			}
+	return allMetrics
+}
 
-			nvmeErrors, err = collectNVMeErrorLog(disk)
-			if err != nil {
-				log.Warn().Err(err).Str("disk", disk).Msg("failed to collect NVMe error log, continuing without error log data")
-			}
+// collectTarget runs smartctl (and nvme-cli) for one device and returns its
+// normalized data and identity. It is called concurrently for all targets.
+func collectTarget(ctx context.Context, cfg DiskHealthMetricsConfig, target diskTarget, topology *multipathTopology, enclosures *enclosureMap, nvmeCliAvailable bool) (*NormalizedSmartData, string, error) {
+	disk := target.path
+	devicePath := disk
+	if target.deviceType == "" {
+		devicePath = topology.resolve(disk, sysBlockPath)
+	}
 
-			// Enhance the smartctl data with nvme-cli information
-			enhanceNVMeData(rawData, nvmeController, nvmeErrors)
+	rawData, err := collectSmartData(ctx, devicePath, target.deviceType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Drives behind a RAID controller share the controller's device node,
+	// smartctl's info name (e.g. "/dev/bus/0 [megaraid_disk_03]") is unique.
+	if target.deviceType != "" && rawData.Device.InfoName != "" {
+		rawData.Device.Name = rawData.Device.InfoName
+	}
+
+	// Enhance NVMe devices with nvme-cli data if available
+	var nvmeController *NVMeIDControllerOutput
+	var nvmeErrors *NVMeErrorLogOutput
+
+	if nvmeCliAvailable && target.deviceType == "" && rawData.Device.Protocol == "NVMe" {
+		nvmeController, err = collectNVMeControllerData(ctx, disk)
+		if err != nil {
+			log.Warn().Err(err).Str("disk", disk).Msg("failed to collect NVMe controller data, continuing with smartctl only")
 		}
 
-		deviceInfo := &DeviceInfo{}
-		FillDeviceInfoFromSmartData(deviceInfo, rawData)
-		NormalizeVendor(deviceInfo)
-		NormalizeDeviceInfo(deviceInfo)
+		nvmeErrors, err = collectNVMeErrorLog(ctx, disk)
+		if err != nil {
+			log.Warn().Err(err).Str("disk", disk).Msg("failed to collect NVMe error log, continuing without error log data")
+		}
 
-		smartAttrs := GetSmartAttributes()
-		ProcessAndUpdateSmartAttributes(smartAttrs, rawData)
+		// Enhance the smartctl data with nvme-cli information
+		enhanceNVMeData(rawData, nvmeController, nvmeErrors)
+	}
+
+	deviceInfo := &DeviceInfo{}
+	FillDeviceInfoFromSmartData(deviceInfo, rawData)
+	NormalizeVendor(deviceInfo)
+	NormalizeDeviceInfo(deviceInfo)
+
+	if cfg.NVMeTelemetry && nvmeCliAvailable && target.deviceType == "" && rawData.Device.Protocol == "NVMe" {
+		deviceInfo.NVMeTelemetry = collectNVMeTelemetry(ctx, disk, nvmeController)
+	}
+
+	smartAttrs := GetSmartAttributes()
+	ProcessAndUpdateSmartAttributes(smartAttrs, rawData, deviceInfo)
+
+	// Process NVMe-specific attributes if we have nvme-cli data
+	if nvmeController != nil || nvmeErrors != nil {
+		processNVMeSpecificAttributes(smartAttrs, nvmeController, nvmeErrors)
+	}
+
+	CleanupSmartAttributes(smartAttrs)
+
+	normalizedData := normalizeSmartData(rawData, deviceInfo, smartAttrs, cfg.NodeName, cfg.InstanceID, cfg.CephOSDBasePath)
+
+	if paths := topology.devicePaths(disk, sysBlockPath); paths != nil {
+		normalizedData.MultipathDevice = "/dev/mapper/" + topology.mapNames[topology.mapForDevice(disk)]
+		normalizedData.Paths = paths
+	}
+	if target.deviceType == "" {
+		normalizedData.Location = enclosures.locate(devicePath, sysBlockPath)
+	}
 
-		// Process NVMe-specific attributes if we have nvme-cli data
-		if nvmeController != nil || nvmeErrors != nil {
-			processNVMeSpecificAttributes(smartAttrs, nvmeController, nvmeErrors)
+	if cfg.SelfTest {
+		normalizedData.SelfTest, err = collectSelfTestStatus(ctx, devicePath, target.deviceType)
+		if err != nil {
+			log.Warn().Err(err).Str("disk", disk).Msg("failed to collect self-test status")
 		}
+	}
 
-		CleanupSmartAttributes(smartAttrs)
+	return &normalizedData, deviceIdentity(rawData), nil
+}
 
-		normalizedData := normalizeSmartData(rawData, deviceInfo, smartAttrs, cfg.NodeName, cfg.InstanceID, cfg.CephOSDBasePath)
-		allMetrics = append(allMetrics, normalizedData)
+// diskTarget is a device to collect, with the smartctl device type for
+// drives that are not directly addressable.
+type diskTarget struct {
+	path       string
+	deviceType string
+}
+
+// collectionTargets returns the configured disks followed by the
+// passthrough devices.
+func collectionTargets(cfg DiskHealthMetricsConfig) []diskTarget {
+	targets := make([]diskTarget, 0, len(cfg.Disks)+len(cfg.PassthroughDevices))
+	for _, disk := range cfg.Disks {
+		targets = append(targets, diskTarget{path: disk})
+	}
+	for _, device := range cfg.PassthroughDevices {
+		targets = append(targets, diskTarget{path: device.Device, deviceType: device.Type})
 	}
+	return targets
+}
 
-	return allMetrics
+// addDevicePath records disk as an additional path of an already collected
+// device that is not managed by dm-multipath.
+func addDevicePath(metric *NormalizedSmartData, disk string) {
+	if len(metric.Paths) == 0 {
+		metric.Paths = append(metric.Paths, readDevicePath(sysBlockPath, kernelName(metric.Device)))
+	}
+	name := "/dev/" + kernelName(disk)
+	for _, path := range metric.Paths {
+		if path.Name == name {
+			return
+		}
+	}
+	metric.Paths = append(metric.Paths, readDevicePath(sysBlockPath, kernelName(disk)))
 }
 
 // collectTestDiskHealthMetrics collects metrics from test data files
@@ -114,7 +246,7 @@ func collectTestDiskHealthMetrics(cfg DiskHealthMetricsConfig) []NormalizedSmart
 		NormalizeDeviceInfo(deviceInfo)
 		
 		smartAttrs := GetSmartAttributes()
-		ProcessAndUpdateSmartAttributes(smartAttrs, rawData)
+		ProcessAndUpdateSmartAttributes(smartAttrs, rawData, deviceInfo)
 		CleanupSmartAttributes(smartAttrs)
 		
 		normalizedData := normalizeSmartData(rawData, deviceInfo, smartAttrs, 
@@ -176,9 +308,12 @@ func normalizeSmartData(smartData *SmartCtlOutput, deviceInfo *DeviceInfo, attri
 
 	enhanceDeviceInfo(deviceInfo)
 
-	osdID, _ := getOSDIDForDisk(smartData.Device.Name, basePath) // Ignore error as it's handled within the function
+	osd, _ := getCephOSDForDisk(smartData.Device.Name, basePath) // Ignore error as it's handled within the function
+
+	healthStatus := deviceInfo.HealthStatus
 
 	return NormalizedSmartData{
+		HealthStatus:       &healthStatus,
 		NodeName:           nodeName,
 		InstanceID:         instanceID,
 		Device:             smartData.Device.Name,
@@ -193,7 +328,10 @@ func normalizeSmartData(smartData *SmartCtlOutput, deviceInfo *DeviceInfo, attri
 			"UDMA_CRC_Error_Count": udmaCrcErrorCount,
 		},
 		Attributes: attributes,
-		OSDID:      osdID, // This may be an empty string if OSD ID is not applicable or retrievable
+		Thresholds: attributeThresholds(smartData),
+		OSDID:       osd.ID, // This may be an empty string if OSD ID is not applicable or retrievable
+		CephCluster: osd.ClusterFSID,
+		SCSIErrors:  scsiErrorCounters(smartData),
 	}
 }
 
@@ -207,11 +345,51 @@ func findSmartAttributeByID(attributes []SmartCtlATASMARTEntry, id int64) *Smart
 }
 
 func StartMonitoring(cfg DiskHealthMetricsConfig) {
-	// Skip smartctl check in test mode
-	if !cfg.TestMode && !checkSmartctlInstalled() {
+	// Skip smartctl check in test and mock mode
+	if cfg.MockSmartctlDir != "" {
+		if err := useMockSmartctl(cfg.MockSmartctlDir); err != nil {
+			log.Fatal().Err(err).Msg("error setting up mock smartctl")
+		}
+		log.Info().Str("mock_smartctl_dir", cfg.MockSmartctlDir).Msg("reading canned smartctl outputs instead of running smartctl")
+	} else if !cfg.TestMode && !checkSmartctlInstalled() {
 		log.Fatal().Msg("smartctl is not installed. please install smartmontools package.")
 	}
 
+	if cfg.SmartdStateDir != "" && !cfg.TestMode {
+		if err := useSmartdState(cfg.SmartdStateDir, cfg.SmartdLogPath); err != nil {
+			log.Fatal().Err(err).Msg("error setting up smartd input")
+		}
+		log.Info().
+			Str("smartd_state_dir", cfg.SmartdStateDir).
+			Str("smartd_log", cfg.SmartdLogPath).
+			Msg("reading SMART attributes from smartd state files instead of polling the devices")
+		if cfg.SelfTest {
+			log.Warn().Msg("self-tests are left to smartd when reading its state files, disabling --self-test")
+			cfg.SelfTest = false
+		}
+	}
+
+	filter, err := newAttributeFilter(cfg.ExportAttributes, cfg.ExcludeAttributes)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error parsing the exported SMART attributes")
+	}
+	prometheusAttributeFilter = filter
+
+	if cfg.Kubernetes {
+		if err := resolveKubernetesNode(&cfg); err != nil {
+			log.Fatal().Err(err).Msg("error detecting the Kubernetes node")
+		}
+		log.Info().
+			Str("node", cfg.NodeName).
+			Str("instance", cfg.InstanceID).
+			Str("zone", cfg.Zone).
+			Str("rack", cfg.Rack).
+			Msg("running in Kubernetes mode")
+	}
+
+	// Discovered disks follow hot-plug events, configured ones are kept.
+	discovered := false
+
 	// Handle test mode setup
 	if cfg.TestMode {
 		// Use default test devices if none specified
@@ -234,55 +412,240 @@ func StartMonitoring(cfg DiskHealthMetricsConfig) {
 	} else {
 		// Discover devices if wildcard (*) is used in the configuration.
 		if len(cfg.Disks) == 1 && cfg.Disks[0] == "*" {
+			discovered = true
 			devices, err := discoverDevices()
 			if err != nil {
 				log.Fatal().Err(err).Msg("Error discovering devices")
 			}
 
-			cfg.Disks = make([]string, len(devices.Devices))
-			for i, device := range devices.Devices {
-				cfg.Disks[i] = device.Name
+			// Drives behind RAID controllers are reported with the
+			// controller's device node and a device type like megaraid,N.
+			cfg.Disks = nil
+			for _, device := range devices.Devices {
+				if needsDeviceType(device.Type) {
+					cfg.PassthroughDevices = append(cfg.PassthroughDevices, PassthroughDevice{Device: device.Name, Type: device.Type})
+					continue
+				}
+				cfg.Disks = append(cfg.Disks, device.Name)
+			}
+		}
+
+		if cfg.PassthroughDevicesPath != "" {
+			devices, err := loadPassthroughDevices(cfg.PassthroughDevicesPath)
+			if err != nil {
+				log.Fatal().Err(err).Msg("error loading passthrough devices")
 			}
+			cfg.PassthroughDevices = append(cfg.PassthroughDevices, devices...)
 		}
+
+		if cfg.DiscoverRAID {
+			devices, err := discoverRAIDDevices()
+			if err != nil {
+				log.Error().Err(err).Msg("error discovering drives behind RAID controllers")
+			}
+			cfg.PassthroughDevices = append(cfg.PassthroughDevices, devices...)
+		}
+		cfg.PassthroughDevices = slices.Compact(slices.SortedFunc(slices.Values(cfg.PassthroughDevices), comparePassthroughDevices))
 	}
 
 	// Ensure that at least one device is found, log a fatal error otherwise.
-	if len(cfg.Disks) == 0 {
+	// With hot-plug discovery the node may still be waiting for its drives.
+	if len(cfg.Disks) == 0 && len(cfg.PassthroughDevices) == 0 && !(cfg.Hotplug && discovered) {
 		log.Fatal().Msg("No devices found for monitoring.")
 	}
 
 	// Log the list of devices to be monitored.
 	log.Info().Strs("Devices", cfg.Disks).Msg("Devices for monitoring")
+	for _, device := range cfg.PassthroughDevices {
+		log.Info().Str("device", device.Device).Str("type", device.Type).Msg("Passthrough device for monitoring")
+	}
+
+	if cfg.DeviceDBPath != "" {
+		if err := LoadDeviceDB(cfg.DeviceDBPath); err != nil {
+			log.Fatal().Err(err).Msg("error loading device database")
+		}
+		go telemetry.RunWorker(context.Background(), "disk-health.device-db", func(context.Context) {
+			WatchDeviceDB(cfg.DeviceDBPath)
+		})
+	}
 
 	var nc *nats.Conn
-	var err error
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Fatal().Err(err).Msg("error connecting to nats")
 		}
 		defer nc.Close()
 	}
 
+	var backend historyBackend
+	switch {
+	case cfg.HistoryPath != "":
+		backend = &fileHistoryBackend{path: cfg.HistoryPath}
+	case cfg.HistoryKVBucket != "" && cfg.UseNats:
+		backend, err = newKVHistoryBackend(nc, cfg.HistoryKVBucket)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error opening SMART history bucket")
+		}
+	case cfg.HistoryKVBucket != "":
+		log.Warn().Str("bucket", cfg.HistoryKVBucket).Msg("SMART history bucket requires NATS, keeping history in memory")
+	}
+	history := newSmartHistory(backend)
+
+	var kernelIO *kernelIOCollector
+	switch {
+	case cfg.KernelIO && !hostPlatform.kernelIO():
+		log.Warn().Str("platform", hostPlatform.name()).Msg("kernel I/O statistics are not available on this platform, disabling --kernel-io")
+	case cfg.KernelIO && !cfg.TestMode:
+		kernelIO = newKernelIOCollector(sysBlockPath, kmsgPath)
+	}
+
+	probe := newScanProbe(cfg)
+	advisor := newReplacementAdvisor(cfg)
+	locator := newDriveLocator(cfg)
 	if cfg.Prometheus {
-		StartPrometheusServer(cfg.PrometheusPort)
+		StartPrometheusServer(cfg.PrometheusPort, &cfg, probe, advisor)
+		if cfg.RemoteWrite.URL != "" {
+			remotewrite.Start(cfg.RemoteWrite)
+		}
+	}
+	if cfg.ProbePort > 0 && !(cfg.Prometheus && cfg.ProbePort == cfg.PrometheusPort) {
+		startProbeServer(cfg.ProbePort, probe, advisor)
 	}
 
-	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+	switch {
+	case cfg.ReplacementSubject != "" && cfg.UseNats:
+		if _, err := SubscribeReplacementRequests(nc, cfg.ReplacementSubject, advisor); err != nil {
+			log.Fatal().Err(err).Msg("error subscribing to replacement requests")
+		}
+	case cfg.ReplacementSubject != "":
+		log.Warn().Str("subject", cfg.ReplacementSubject).Msg("replacement requests require NATS, serving them over HTTP only")
+	}
+
+	switch {
+	case cfg.LocateSubject != "" && cfg.UseNats:
+		if _, err := SubscribeLocateRequests(nc, cfg.LocateSubject, locator); err != nil {
+			log.Fatal().Err(err).Msg("error subscribing to locate requests")
+		}
+	case cfg.LocateSubject != "":
+		log.Warn().Str("subject", cfg.LocateSubject).Msg("locate requests require NATS, ignoring them")
+	}
+
+	if cfg.SelfTest && !cfg.TestMode {
+		go telemetry.RunWorker(context.Background(), "disk-health.self-test", func(context.Context) {
+			RunSelfTestScheduler(cfg)
+		})
+	}
+
+	// Cancel a running scan on shutdown instead of waiting for hung devices.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Hot-plugged disks are scanned right away instead of on the next tick.
+	var hotplugEvents chan DeviceEvent
+	var rescan <-chan time.Time
+	if cfg.Hotplug && !cfg.TestMode {
+		hotplugEvents = make(chan DeviceEvent, 16)
+		go telemetry.RunWorker(ctx, "disk-health.hotplug", func(ctx context.Context) {
+			if err := hostPlatform.watchHotplug(ctx, hotplugEvents); err != nil {
+				log.Error().Err(err).Msg("error watching for hot-plugged devices, falling back to the configured devices")
+			}
+		})
+	}
+
+	interval := time.Duration(cfg.Interval) * time.Second
+	schedule := newScanSchedule(cfg)
+	if schedule != nil {
+		interval = schedule.tick
+		for _, s := range cfg.ScanSchedules {
+			log.Info().Str("match", s.Match).Dur("interval", s.Interval).Msg("scan schedule")
+		}
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		metrics := collectDiskHealthMetrics(cfg)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("termination signal received, stopping disk health monitoring")
+			return
+		case event := <-hotplugEvents:
+			log.Info().Str("device", event.Device).Str("action", event.Action).Msg("device hot-plug event")
+			if cfg.Prometheus {
+				publishHotplugEvent(event, cfg)
+			}
+			cfg.Disks = applyDeviceEvent(cfg.Disks, event, discovered)
+			if event.Action == DeviceEventRemove {
+				forgetDevice(event.Device)
+			}
+			rescan = time.After(hotplugSettleDelay)
+			continue
+		case <-rescan:
+			rescan = nil
+		case <-ticker.C:
+		}
+
+		metrics := collectDiskHealthMetrics(ctx, cfg, schedule)
+		if ctx.Err() != nil {
+			continue // scan was interrupted, do not publish partial results
+		}
+		for i := range metrics {
+			if cfg.CephCluster != "" {
+				metrics[i].CephCluster = cfg.CephCluster
+			}
+			metrics[i].Zone = cfg.Zone
+			metrics[i].Rack = cfg.Rack
+		}
+		scoreFailureRisk(metrics, cfg)
+		projectEndurance(metrics, cfg)
+		evaluateTemperatureAlerts(metrics, cfg)
+		checkFirmwareCompliance(metrics)
+		history.track(metrics, time.Now())
+		if kernelIO != nil {
+			kernelIO.collect(metrics)
+		}
+		advisor.update(metrics, time.Now())
+		locator.update(metrics)
+
+		if cfg.SnapshotPath != "" {
+			if err := writeSnapshotFile(newSnapshot(metrics, cfg, time.Now()), cfg.SnapshotPath); err != nil {
+				log.Error().Err(err).Msg("error writing snapshot")
+			}
+		}
+		if cfg.FirmwareReportPath != "" {
+			if err := writeFirmwareReport(newFirmwareReport(metrics, cfg, time.Now()), cfg.FirmwareReportPath); err != nil {
+				log.Error().Err(err).Msg("error writing firmware report")
+			}
+		}
 
 		if cfg.Prometheus {
 			PublishToPrometheus(metrics, cfg)
 		}
+		probe.scanned()
 
 		if cfg.UseNats {
 			err = PublishToNATS(metrics, nc, cfg.NatsSubject, &cfg)
 			if err != nil {
 				log.Error().Err(err).Msg("error publishing metrics to nats")
 			}
+
+			if cfg.ChangeEventsSubject != "" {
+				if err := PublishChangeEvents(metrics, nc, cfg.ChangeEventsSubject); err != nil {
+					log.Error().Err(err).Msg("error publishing change events to nats")
+				}
+			}
+
+			if cfg.SnapshotSubject != "" {
+				if err := PublishSnapshot(newSnapshot(metrics, cfg, time.Now()), nc, cfg.SnapshotSubject); err != nil {
+					log.Error().Err(err).Msg("error publishing snapshot to nats")
+				}
+			}
+
+			if cfg.FirmwareReportSubject != "" {
+				if err := PublishFirmwareReport(newFirmwareReport(metrics, cfg, time.Now()), nc, cfg.FirmwareReportSubject); err != nil {
+					log.Error().Err(err).Msg("error publishing firmware report to nats")
+				}
+			}
 		} else {
 			metricsJSON, err := json.Marshal(metrics)
 			if err != nil {
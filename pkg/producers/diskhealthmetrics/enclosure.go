@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sysEnclosurePath holds the enclosures of the kernel ses driver.
+	sysEnclosurePath = "/sys/class/enclosure"
+	// sysSCSIGenericPath holds the SCSI generic devices sg_ses talks to.
+	sysSCSIGenericPath = "/sys/class/scsi_generic"
+	// dmiChassisSerialPath is the serial number of the chassis of the node.
+	dmiChassisSerialPath = "/sys/class/dmi/id/chassis_serial"
+)
+
+// scsiTypeEnclosure is the SCSI peripheral device type of SES enclosures.
+const scsiTypeEnclosure = "13"
+
+// DriveLocation is the bay a drive sits in, so technicians know exactly
+// which one to pull.
+type DriveLocation struct {
+	Chassis   string `json:"chassis,omitempty"` // Serial number of the chassis, or --chassis
+	Enclosure string `json:"enclosure"`         // Logical ID (SAS address) of the enclosure
+	Slot      string `json:"slot"`              // Slot number, or the name of the slot if it has none
+}
+
+// enclosureMap maps the drives of the node to their enclosure slots.
+type enclosureMap struct {
+	chassis string
+	byName  map[string]DriveLocation // sdX -> location
+	bySAS   map[string]DriveLocation // SAS address of a drive port -> location
+}
+
+// discoverEnclosures reads the slots of the enclosures from the kernel ses
+// driver and falls back to sg_ses for the enclosures it does not manage.
+// chassis overrides the serial number of the chassis.
+func discoverEnclosures(ctx context.Context, sysEnclosure, sysSCSIGeneric, chassis string) *enclosureMap {
+	enclosures := &enclosureMap{
+		chassis: chassis,
+		byName:  make(map[string]DriveLocation),
+		bySAS:   make(map[string]DriveLocation),
+	}
+	if enclosures.chassis == "" {
+		if serial, err := os.ReadFile(dmiChassisSerialPath); err == nil {
+			enclosures.chassis = cleanChassisSerial(string(serial))
+		}
+	}
+
+	if enclosures.readSysfs(sysEnclosure) > 0 {
+		return enclosures
+	}
+	if _, err := exec.LookPath("sg_ses"); err != nil {
+		return enclosures
+	}
+	enclosures.readSGSES(ctx, sysSCSIGeneric)
+	return enclosures
+}
+
+// cleanChassisSerial drops the placeholders vendors leave in the DMI tables.
+func cleanChassisSerial(serial string) string {
+	serial = strings.TrimSpace(serial)
+	switch strings.ToLower(serial) {
+	case "", "0", "none", "not specified", "to be filled by o.e.m.", "default string":
+		return ""
+	}
+	return serial
+}
+
+// readSysfs maps the drives of the enclosure components the ses driver
+// linked to their SCSI devices and returns the number of mapped drives.
+func (m *enclosureMap) readSysfs(sysEnclosure string) int {
+	mapped := 0
+	enclosureDirs, _ := filepath.Glob(filepath.Join(sysEnclosure, "*"))
+	for _, enclosureDir := range enclosureDirs {
+		enclosure := filepath.Base(enclosureDir)
+		if id, err := os.ReadFile(filepath.Join(enclosureDir, "id")); err == nil && strings.TrimSpace(string(id)) != "" {
+			enclosure = strings.TrimSpace(string(id))
+		}
+
+		components, _ := filepath.Glob(filepath.Join(enclosureDir, "*", "device"))
+		for _, device := range components {
+			componentDir := filepath.Dir(device)
+			slot := filepath.Base(componentDir)
+			if number, err := os.ReadFile(filepath.Join(componentDir, "slot")); err == nil && strings.TrimSpace(string(number)) != "" {
+				slot = strings.TrimSpace(string(number))
+			}
+
+			blocks, err := os.ReadDir(filepath.Join(device, "block"))
+			if err != nil {
+				continue
+			}
+			for _, block := range blocks {
+				m.byName[block.Name()] = DriveLocation{Chassis: m.chassis, Enclosure: enclosure, Slot: slot}
+				mapped++
+			}
+		}
+	}
+	return mapped
+}
+
+// readSGSES maps the drives of every SES enclosure by the SAS addresses
+// sg_ses reports for its device slots.
+func (m *enclosureMap) readSGSES(ctx context.Context, sysSCSIGeneric string) {
+	devices, _ := filepath.Glob(filepath.Join(sysSCSIGeneric, "sg*"))
+	for _, device := range devices {
+		scsiType, err := os.ReadFile(filepath.Join(device, "device", "type"))
+		if err != nil || strings.TrimSpace(string(scsiType)) != scsiTypeEnclosure {
+			continue
+		}
+
+		sg := filepath.Base(device)
+		enclosure := sg
+		if address, err := os.ReadFile(filepath.Join(device, "device", "sas_address")); err == nil && strings.TrimSpace(string(address)) != "" {
+			enclosure = strings.TrimSpace(string(address))
+		}
+
+		out, err := exec.CommandContext(ctx, "sg_ses", "--page=aes", "/dev/"+sg).Output()
+		if err != nil {
+			log.Warn().Err(err).Str("enclosure", sg).Msg("failed to run sg_ses")
+			continue
+		}
+		for address, slot := range parseSGSESSlots(out) {
+			m.bySAS[address] = DriveLocation{Chassis: m.chassis, Enclosure: enclosure, Slot: slot}
+		}
+	}
+}
+
+var (
+	sgSESSlotPattern    = regexp.MustCompile(`device slot number:\s*(\d+)`)
+	sgSESAddressPattern = regexp.MustCompile(`^\s*SAS address:\s*(0x[0-9a-fA-F]+)`)
+)
+
+// parseSGSESSlots returns the slot of every SAS address in the additional
+// element status page of sg_ses. A dual-ported drive has an address per
+// port; the attached addresses of the expander are skipped.
+func parseSGSESSlots(out []byte) map[string]string {
+	slots := make(map[string]string)
+	slot := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := sgSESSlotPattern.FindStringSubmatch(line); match != nil {
+			slot = match[1]
+			continue
+		}
+		if match := sgSESAddressPattern.FindStringSubmatch(line); match != nil && slot != "" {
+			if address := strings.ToLower(match[1]); address != "0x0000000000000000" {
+				slots[address] = slot
+			}
+		}
+	}
+	return slots
+}
+
+// locate returns the slot of the drive disk, nil if it is not in an
+// enclosure. Drives are found by their kernel name, or by the SAS address
+// of their port for slots read with sg_ses.
+func (m *enclosureMap) locate(disk, sysBlock string) *DriveLocation {
+	if m == nil {
+		return nil
+	}
+	name := kernelName(disk)
+	if location, ok := m.byName[name]; ok {
+		return &location
+	}
+	if len(m.bySAS) == 0 {
+		return nil
+	}
+	address, err := os.ReadFile(filepath.Join(sysBlock, name, "device", "sas_address"))
+	if err != nil {
+		return nil
+	}
+	if location, ok := m.bySAS[strings.ToLower(strings.TrimSpace(string(address)))]; ok {
+		return &location
+	}
+	return nil
+}
+
+// locationOf returns the enclosure slot of a drive, empty if it was not
+// located.
+func locationOf(metric NormalizedSmartData) DriveLocation {
+	if metric.Location == nil {
+		return DriveLocation{}
+	}
+	return *metric.Location
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnclosureMap_ReadSysfs(t *testing.T) {
+	sysEnclosure := t.TempDir()
+	scsiDevices := t.TempDir()
+
+	// Drives are linked to their slots the way the ses driver does it
+	writeSysfsFile(t, filepath.Join(sysEnclosure, "0:0:12:0", "id"), "0x500304801f4b8c7f\n")
+	for slot, drive := range map[string]string{"Slot00": "sdc", "Slot01": "sdd"} {
+		scsiDevice := filepath.Join(scsiDevices, drive)
+		require.NoError(t, os.MkdirAll(filepath.Join(scsiDevice, "block", drive), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(sysEnclosure, "0:0:12:0", slot), 0o755))
+		require.NoError(t, os.Symlink(scsiDevice, filepath.Join(sysEnclosure, "0:0:12:0", slot, "device")))
+	}
+	writeSysfsFile(t, filepath.Join(sysEnclosure, "0:0:12:0", "Slot01", "slot"), "1\n")
+	// Empty slots have no device
+	require.NoError(t, os.MkdirAll(filepath.Join(sysEnclosure, "0:0:12:0", "Slot02"), 0o755))
+
+	enclosures := &enclosureMap{chassis: "CZ20420ABC", byName: map[string]DriveLocation{}, bySAS: map[string]DriveLocation{}}
+	assert.Equal(t, 2, enclosures.readSysfs(sysEnclosure))
+
+	assert.Equal(t, &DriveLocation{Chassis: "CZ20420ABC", Enclosure: "0x500304801f4b8c7f", Slot: "Slot00"}, enclosures.locate("/dev/sdc", t.TempDir()))
+	assert.Equal(t, &DriveLocation{Chassis: "CZ20420ABC", Enclosure: "0x500304801f4b8c7f", Slot: "1"}, enclosures.locate("/dev/sdd", t.TempDir()))
+	assert.Nil(t, enclosures.locate("/dev/sde", t.TempDir()))
+}
+
+func TestParseSGSESSlots(t *testing.T) {
+	out := []byte(`  LSI CORP  SAS2X28  0e12
+    Primary enclosure logical identifier (hex): 500304801f4b8c7f
+Additional element status diagnostic page:
+  generation code: 0x0
+  additional element status descriptor list
+    Element type: Array device slot, subenclosure id: 0 [ti=0]
+      element index: 0 [ei_ioe=0]
+        Transport protocol: SAS
+        number of phys: 2, not all phys: 0, device slot number: 0
+        phy index: 0
+          SAS device type: end device
+          attached SAS address: 0x500304801f4b8c40
+          SAS address: 0x5000c500a1b2c3d5
+        phy index: 1
+          SAS device type: end device
+          attached SAS address: 0x500304801f4b8c40
+          SAS address: 0x5000c500a1b2c3d6
+      element index: 1 [ei_ioe=0]
+        Transport protocol: SAS
+        number of phys: 1, not all phys: 0, device slot number: 1
+        phy index: 0
+          SAS device type: no SAS device attached
+          attached SAS address: 0x500304801f4b8c40
+          SAS address: 0x0000000000000000
+`)
+
+	assert.Equal(t, map[string]string{
+		"0x5000c500a1b2c3d5": "0",
+		"0x5000c500a1b2c3d6": "0",
+	}, parseSGSESSlots(out))
+}
+
+func TestEnclosureMap_LocateBySASAddress(t *testing.T) {
+	sysBlock := t.TempDir()
+	writeSysfsFile(t, filepath.Join(sysBlock, "sdf", "device", "sas_address"), "0x5000C500A1B2C3D6\n")
+
+	location := DriveLocation{Enclosure: "0x500304801f4b8c7f", Slot: "0"}
+	enclosures := &enclosureMap{byName: map[string]DriveLocation{}, bySAS: map[string]DriveLocation{"0x5000c500a1b2c3d6": location}}
+	assert.Equal(t, &location, enclosures.locate("/dev/sdf", sysBlock))
+	assert.Nil(t, enclosures.locate("/dev/sdg", sysBlock))
+
+	var disabled *enclosureMap
+	assert.Nil(t, disabled.locate("/dev/sdf", sysBlock))
+}
+
+func TestCleanChassisSerial(t *testing.T) {
+	assert.Equal(t, "CZ20420ABC", cleanChassisSerial("CZ20420ABC\n"))
+	assert.Equal(t, "", cleanChassisSerial("To Be Filled By O.E.M.\n"))
+	assert.Equal(t, "", cleanChassisSerial("Default string"))
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import "math"
+
+// remainingLifeBuckets are the upper bounds in days of the fleet remaining
+// life histogram, +Inf is added when publishing.
+var remainingLifeBuckets = []float64{30, 90, 180, 365, 730, 1095, 1825}
+
+// hostWriteAttributes are the host write counters in order of preference,
+// with the number of bytes per unit.
+var hostWriteAttributes = []struct {
+	name string
+	unit float64
+}{
+	{"data_units_written", 512 * 1000}, // NVMe, units of 1000 logical blocks of 512 bytes
+	{"total_lbas_written", 512},
+	{"total_host_sector_write", 512},
+	{"host_writes_32mib", 32 << 20},
+	{"host_writes_mib", 1 << 20},
+	{"host_writes_gib", 1 << 30},
+	{"write_gigabytes_processed", 1e9}, // SCSI error counter log
+}
+
+// ataWearAttributes are ATA SSD wear indicators already converted to percent
+// used by ProcessAndUpdateATASmartAttributes.
+var ataWearAttributes = []string{
+	"media_wearout_indicator",
+	"percent_life_remaining",
+	"percent_lifetime_remain",
+}
+
+// EnduranceProjection is the projected remaining write endurance of an SSD.
+type EnduranceProjection struct {
+	LifeUsedPercent  float64 `json:"life_used_percent"` // larger of the reported wear and the written share of the rated endurance
+	TerabytesWritten float64 `json:"terabytes_written"` // host writes, 0 if the drive does not report them
+	RatedTerabytes   float64 `json:"rated_terabytes"`   // DWPD * capacity * warranty period, 0 if the DWPD is unknown
+	DailyTerabytes   float64 `json:"daily_terabytes"`   // average host writes per power-on day
+	RemainingDays    float64 `json:"remaining_days"`    // projected days until the rated endurance is used up
+}
+
+// projectEndurance assigns an EnduranceProjection to every SSD and NVMe
+// device with enough data to project its remaining life.
+func projectEndurance(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
+	for i := range metrics {
+		metrics[i].Endurance = calculateEndurance(metrics[i], cfg.EnduranceWarrantyYears)
+	}
+}
+
+// calculateEndurance extrapolates the wear so far over the power-on time:
+// a drive that used 10% of its life in 100 days has 900 days left at the
+// same write rate. Wear is the larger of the drive-reported percentage used
+// and the host writes relative to the rated endurance, so a drive written
+// beyond its DWPD rating is not hidden by a lagging wear indicator.
+func calculateEndurance(data NormalizedSmartData, warrantyYears int) *EnduranceProjection {
+	if data.DeviceInfo == nil || (data.DeviceInfo.Media != "ssd" && data.DeviceInfo.Media != "nvme") {
+		return nil
+	}
+
+	powerOnHours := data.Attributes["power_on_hours"].RawValue
+	if data.PowerOnHours != nil {
+		powerOnHours = *data.PowerOnHours
+	}
+	if powerOnHours <= 0 {
+		return nil
+	}
+	powerOnDays := float64(powerOnHours) / 24
+
+	projection := &EnduranceProjection{
+		LifeUsedPercent:  float64(enduranceWearLevel(data)),
+		TerabytesWritten: hostBytesWritten(data) / 1e12,
+	}
+	projection.DailyTerabytes = projection.TerabytesWritten / powerOnDays
+
+	if data.DeviceInfo.DWPD > 0 && data.CapacityGB > 0 && warrantyYears > 0 {
+		projection.RatedTerabytes = data.DeviceInfo.DWPD * data.CapacityGB / 1000 * 365 * float64(warrantyYears)
+		projection.LifeUsedPercent = math.Max(projection.LifeUsedPercent, projection.TerabytesWritten/projection.RatedTerabytes*100)
+	}
+
+	if projection.LifeUsedPercent <= 0 {
+		return nil // no measurable wear yet, nothing to extrapolate
+	}
+	projection.RemainingDays = math.Max(0, math.Round(powerOnDays*(100-projection.LifeUsedPercent)/projection.LifeUsedPercent))
+	return projection
+}
+
+// enduranceWearLevel returns the drive-reported percentage of rated life used.
+func enduranceWearLevel(data NormalizedSmartData) int64 {
+	wear := riskWearLevel(data)
+	for _, name := range ataWearAttributes {
+		if attr, ok := data.Attributes[name]; ok && attr.Value >= 0 && attr.Value <= 100 {
+			wear = max(wear, attr.Value)
+		}
+	}
+	return wear
+}
+
+// hostBytesWritten returns the bytes written by the host, or 0 if unknown.
+func hostBytesWritten(data NormalizedSmartData) float64 {
+	for _, counter := range hostWriteAttributes {
+		if attr, ok := data.Attributes[counter.name]; ok && attr.RawValue > 0 {
+			return float64(attr.RawValue) * counter.unit
+		}
+	}
+	return 0
+}
+
+// remainingLifeHistogram counts the devices with a projected remaining life
+// of at most each bucket bound, cumulative like a Prometheus histogram.
+func remainingLifeHistogram(metrics []NormalizedSmartData) []int {
+	counts := make([]int, len(remainingLifeBuckets)+1)
+	for _, metric := range metrics {
+		if metric.Endurance == nil {
+			continue
+		}
+		for i, bound := range remainingLifeBuckets {
+			if metric.Endurance.RemainingDays <= bound {
+				counts[i]++
+			}
+		}
+		counts[len(remainingLifeBuckets)]++
+	}
+	return counts
+}
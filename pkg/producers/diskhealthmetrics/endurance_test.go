@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateEndurance_PercentageUsed(t *testing.T) {
+	lifeUsed := int64(10)
+	data := NormalizedSmartData{
+		DeviceInfo:  &DeviceInfo{Media: "nvme"},
+		SSDLifeUsed: &lifeUsed,
+		Attributes: map[string]SmartAttribute{
+			"power_on_hours":     {RawValue: 2400}, // 100 days
+			"data_units_written": {RawValue: 2_000_000_000},
+		},
+	}
+
+	projection := calculateEndurance(data, 5)
+	require.NotNil(t, projection)
+	assert.Equal(t, 10.0, projection.LifeUsedPercent)
+	assert.Equal(t, 900.0, projection.RemainingDays)
+	assert.InDelta(t, 1024.0, projection.TerabytesWritten, 0.001)
+	assert.Zero(t, projection.RatedTerabytes, "no DWPD rating")
+}
+
+func TestCalculateEndurance_DWPD(t *testing.T) {
+	powerOnHours := int64(365 * 24)
+	data := NormalizedSmartData{
+		DeviceInfo:   &DeviceInfo{Media: "ssd", DWPD: 1},
+		CapacityGB:   1000,
+		PowerOnHours: &powerOnHours,
+		Attributes: map[string]SmartAttribute{
+			// 730 TB written in one year against 1825 TB rated (1 DWPD, 5 years)
+			"total_lbas_written":      {RawValue: 730e12 / 512},
+			"media_wearout_indicator": {Value: 5, RawValue: -1},
+		},
+	}
+
+	projection := calculateEndurance(data, 5)
+	require.NotNil(t, projection)
+	assert.Equal(t, 1825.0, projection.RatedTerabytes)
+	assert.InDelta(t, 40.0, projection.LifeUsedPercent, 0.001, "writes outpace the reported wear")
+	assert.InDelta(t, 2.0, projection.DailyTerabytes, 0.001)
+	assert.Equal(t, 548.0, projection.RemainingDays)
+}
+
+func TestCalculateEndurance_NotProjected(t *testing.T) {
+	powerOnHours := int64(1000)
+	assert.Nil(t, calculateEndurance(NormalizedSmartData{
+		DeviceInfo:   &DeviceInfo{Media: "hdd"},
+		PowerOnHours: &powerOnHours,
+	}, 5))
+	assert.Nil(t, calculateEndurance(NormalizedSmartData{
+		DeviceInfo:   &DeviceInfo{Media: "ssd"},
+		PowerOnHours: &powerOnHours,
+	}, 5), "no wear yet")
+}
+
+func TestRemainingLifeHistogram(t *testing.T) {
+	metrics := []NormalizedSmartData{
+		{Endurance: &EnduranceProjection{RemainingDays: 20}},
+		{Endurance: &EnduranceProjection{RemainingDays: 400}},
+		{Endurance: &EnduranceProjection{RemainingDays: 5000}},
+		{},
+	}
+
+	assert.Equal(t, []int{1, 1, 1, 1, 2, 2, 2, 3}, remainingLifeHistogram(metrics))
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+// FirmwareCompliance is the firmware version of a device checked against the
+// firmware policy of its model in the device database.
+type FirmwareCompliance struct {
+	Version   string   `json:"version"`
+	Approved  []string `json:"approved"`
+	Compliant bool     `json:"compliant"`
+}
+
+// FirmwareReport lists the devices of a node running firmware that is not
+// approved for their model, to track firmware rollouts across the fleet.
+type FirmwareReport struct {
+	NodeName     string                 `json:"node_name"`
+	InstanceID   string                 `json:"instance_id"`
+	Zone         string                 `json:"zone,omitempty"`
+	Rack         string                 `json:"rack,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Checked      int                    `json:"checked"` // devices with a firmware policy
+	NonCompliant []NonCompliantFirmware `json:"non_compliant"`
+}
+
+// NonCompliantFirmware is a device running firmware that is not approved.
+type NonCompliantFirmware struct {
+	Device          string   `json:"device"`
+	OSDID           string   `json:"osd_id,omitempty"`
+	CephCluster     string   `json:"ceph_cluster,omitempty"`
+	Vendor          string   `json:"vendor"`
+	Model           string   `json:"model"`
+	SerialNumber    string   `json:"serial_number"`
+	FirmwareVersion string   `json:"firmware_version"`
+	Approved        []string `json:"approved"`
+}
+
+// checkFirmwareCompliance assigns a FirmwareCompliance to every device
+// matched by a firmware policy of the active device database.
+func checkFirmwareCompliance(metrics []NormalizedSmartData) {
+	db := currentDeviceDB()
+	for i := range metrics {
+		metrics[i].Firmware = firmwareCompliance(db, metrics[i].DeviceInfo)
+	}
+}
+
+// firmwareCompliance returns nil if no policy matches the device.
+func firmwareCompliance(db *DeviceDB, deviceInfo *DeviceInfo) *FirmwareCompliance {
+	if deviceInfo == nil {
+		return nil
+	}
+	policy, ok := db.FirmwarePolicy(deviceInfo)
+	if !ok {
+		return nil
+	}
+	version := strings.TrimSpace(deviceInfo.FirmwareVersion)
+	return &FirmwareCompliance{
+		Version:   version,
+		Approved:  policy.Approved,
+		Compliant: policy.approves(version),
+	}
+}
+
+// newFirmwareReport builds a FirmwareReport from the checked metrics.
+func newFirmwareReport(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig, now time.Time) FirmwareReport {
+	report := FirmwareReport{
+		NodeName:     cfg.NodeName,
+		InstanceID:   cfg.InstanceID,
+		Zone:         cfg.Zone,
+		Rack:         cfg.Rack,
+		Timestamp:    now.UTC(),
+		NonCompliant: []NonCompliantFirmware{},
+	}
+
+	for _, metric := range metrics {
+		if metric.Firmware == nil {
+			continue
+		}
+		report.Checked++
+		if metric.Firmware.Compliant {
+			continue
+		}
+		report.NonCompliant = append(report.NonCompliant, NonCompliantFirmware{
+			Device:          metric.Device,
+			OSDID:           metric.OSDID,
+			CephCluster:     metric.CephCluster,
+			Vendor:          metric.DeviceInfo.Vendor,
+			Model:           metric.DeviceInfo.DeviceModel,
+			SerialNumber:    metric.DeviceInfo.SerialNumber,
+			FirmwareVersion: metric.Firmware.Version,
+			Approved:        metric.Firmware.Approved,
+		})
+	}
+
+	return report
+}
+
+// writeFirmwareReport writes the report as JSON, replacing it on every scan.
+func writeFirmwareReport(report FirmwareReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode firmware report: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write firmware report %s: %w", path, err)
+	}
+	return nil
+}
+
+// PublishFirmwareReport publishes the report as one JSON message.
+func PublishFirmwareReport(report FirmwareReport, nc *nats.Conn, subject string) error {
+	return schema.Publish(nc, subject, schema.DiskFirmwareReport, report)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirmwareCompliance(t *testing.T) {
+	override := []byte(`{"devices": [], "firmware_policies": [
+		{"models": ["SSDSC2KG*"], "approved": ["XCV10132", "XCV1014*"]},
+		{"vendor": "seagate", "media": "hdd", "approved": ["SN04"]}
+	]}`)
+	db, err := newDeviceDB([]byte(`{"devices": []}`), override)
+	require.NoError(t, err)
+
+	compliance := firmwareCompliance(db, &DeviceInfo{DeviceModel: "SSDSC2KG480G8R", FirmwareVersion: "XCV10140 "})
+	require.NotNil(t, compliance)
+	assert.True(t, compliance.Compliant, "approved versions may contain wildcards")
+	assert.Equal(t, "XCV10140", compliance.Version)
+
+	compliance = firmwareCompliance(db, &DeviceInfo{DeviceModel: "SSDSC2KG480G8R", FirmwareVersion: "XCV10120"})
+	require.NotNil(t, compliance)
+	assert.False(t, compliance.Compliant)
+
+	compliance = firmwareCompliance(db, &DeviceInfo{DeviceModel: "ST12000NM0008", Vendor: "Seagate", Media: "hdd", FirmwareVersion: "SN02"})
+	require.NotNil(t, compliance)
+	assert.False(t, compliance.Compliant)
+
+	assert.Nil(t, firmwareCompliance(db, &DeviceInfo{DeviceModel: "OTHER", Media: "ssd"}), "devices without a policy are not checked")
+	assert.Nil(t, firmwareCompliance(db, nil))
+
+	_, err = newDeviceDB([]byte(`{"devices": []}`), []byte(`{"firmware_policies": [{"models": ["SSDSC2KG*"]}]}`))
+	assert.ErrorContains(t, err, "approves no firmware version")
+	_, err = newDeviceDB([]byte(`{"devices": []}`), []byte(`{"firmware_policies": [{"models": ["[bad"], "approved": ["1"]}]}`))
+	assert.Error(t, err)
+}
+
+func TestFirmwareReport(t *testing.T) {
+	metrics := []NormalizedSmartData{
+		{
+			Device:     "/dev/sda",
+			OSDID:      "3",
+			DeviceInfo: &DeviceInfo{Vendor: "Intel", DeviceModel: "SSDSC2KG480G8R", SerialNumber: "PHYG1234"},
+			Firmware:   &FirmwareCompliance{Version: "XCV10120", Approved: []string{"XCV10132"}},
+		},
+		{
+			Device:     "/dev/sdb",
+			DeviceInfo: &DeviceInfo{Vendor: "Intel", DeviceModel: "SSDSC2KG480G8R"},
+			Firmware:   &FirmwareCompliance{Version: "XCV10132", Approved: []string{"XCV10132"}, Compliant: true},
+		},
+		{Device: "/dev/sdc", DeviceInfo: &DeviceInfo{DeviceModel: "OTHER"}},
+	}
+	cfg := DiskHealthMetricsConfig{NodeName: "node-1", InstanceID: "i-1", Zone: "eu-1a"}
+	report := newFirmwareReport(metrics, cfg, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 2, report.Checked)
+	require.Len(t, report.NonCompliant, 1)
+	assert.Equal(t, NonCompliantFirmware{
+		Device:          "/dev/sda",
+		OSDID:           "3",
+		Vendor:          "Intel",
+		Model:           "SSDSC2KG480G8R",
+		SerialNumber:    "PHYG1234",
+		FirmwareVersion: "XCV10120",
+		Approved:        []string{"XCV10132"},
+	}, report.NonCompliant[0])
+
+	path := filepath.Join(t.TempDir(), "firmware.json")
+	require.NoError(t, writeFirmwareReport(report, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded FirmwareReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report, decoded)
+}
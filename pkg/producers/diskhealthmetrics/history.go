@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// historyResolution is the minimum spacing between stored samples.
+	historyResolution = time.Hour
+	// historyRetention is how long samples are kept; it must cover the
+	// longest trend window.
+	historyRetention = 8 * 24 * time.Hour
+)
+
+// trendWindows are the periods over which indicator increases are reported.
+var trendWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "24h", duration: 24 * time.Hour},
+	{name: "7d", duration: 7 * 24 * time.Hour},
+}
+
+// trendIndicators are the counters whose rate of change is tracked. They
+// reuse the failure-risk inputs so trends and score agree on what is counted.
+var trendIndicators = map[string]func(NormalizedSmartData) int64{
+	"grown_defects":   riskReallocatedSectors,
+	"pending_sectors": riskPendingSectors,
+	"media_errors":    riskMediaErrors,
+	"wear_level":      riskWearLevel,
+}
+
+// SmartTrend is the increase of a SMART health indicator over a window.
+type SmartTrend struct {
+	Indicator string    `json:"indicator"` // grown_defects, pending_sectors, media_errors or wear_level
+	Window    string    `json:"window"`    // 24h or 7d
+	Increase  int64     `json:"increase"`  // current value minus the oldest value inside the window
+	Since     time.Time `json:"since"`     // time of the oldest sample inside the window
+}
+
+// HistorySample is a stored reading of the trend indicators.
+type HistorySample struct {
+	At     time.Time        `json:"at"`
+	Values map[string]int64 `json:"values"`
+}
+
+// historyBackend persists SMART samples across restarts.
+type historyBackend interface {
+	load() (map[string][]HistorySample, error)
+	// store persists the changed devices; all holds every device's history.
+	store(changed []string, all map[string][]HistorySample) error
+}
+
+// smartHistory keeps per-device samples and derives trends from them.
+type smartHistory struct {
+	mu      sync.Mutex
+	samples map[string][]HistorySample
+	backend historyBackend // nil keeps the history in memory only
+}
+
+// newSmartHistory loads previously stored samples from backend.
+func newSmartHistory(backend historyBackend) *smartHistory {
+	h := &smartHistory{samples: make(map[string][]HistorySample), backend: backend}
+	if backend == nil {
+		return h
+	}
+
+	samples, err := backend.load()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to load SMART history, starting with an empty history")
+		return h
+	}
+	h.samples = samples
+	log.Info().Int("devices", len(samples)).Msg("loaded SMART history")
+	return h
+}
+
+// track records the current indicator values of every device and assigns
+// the resulting trends.
+func (h *smartHistory) track(metrics []NormalizedSmartData, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var changedKeys []string
+	for i := range metrics {
+		key := historyKey(metrics[i])
+		values := make(map[string]int64, len(trendIndicators))
+		for name, indicator := range trendIndicators {
+			values[name] = indicator(metrics[i])
+		}
+
+		samples, changed := appendHistorySample(h.samples[key], HistorySample{At: now, Values: values})
+		h.samples[key] = samples
+		metrics[i].Trends = calculateTrends(samples, values, now)
+
+		if changed {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+
+	if h.backend != nil && len(changedKeys) > 0 {
+		if err := h.backend.store(changedKeys, h.samples); err != nil {
+			log.Warn().Err(err).Msg("failed to persist SMART history")
+		}
+	}
+}
+
+// appendHistorySample adds sample at historyResolution and drops samples
+// older than historyRetention. It reports whether samples changed.
+func appendHistorySample(samples []HistorySample, sample HistorySample) ([]HistorySample, bool) {
+	changed := false
+	if n := len(samples); n == 0 || sample.At.Sub(samples[n-1].At) >= historyResolution {
+		samples = append(samples, sample)
+		changed = true
+	}
+	for len(samples) > 1 && sample.At.Sub(samples[0].At) > historyRetention {
+		samples = samples[1:]
+		changed = true
+	}
+	return samples, changed
+}
+
+// calculateTrends compares the current values with the oldest sample inside
+// each window. A decrease (counter reset, replaced drive) is reported as 0.
+func calculateTrends(samples []HistorySample, current map[string]int64, now time.Time) []SmartTrend {
+	var trends []SmartTrend
+	for _, window := range trendWindows {
+		var base *HistorySample
+		for i := range samples {
+			if now.Sub(samples[i].At) <= window.duration {
+				base = &samples[i]
+				break
+			}
+		}
+		if base == nil {
+			continue
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(trendIndicators)) {
+			previous, ok := base.Values[name]
+			if !ok {
+				continue
+			}
+			trends = append(trends, SmartTrend{
+				Indicator: name,
+				Window:    window.name,
+				Increase:  max(current[name]-previous, 0),
+				Since:     base.At,
+			})
+		}
+	}
+	return trends
+}
+
+// invalidHistoryKeyChars matches characters not allowed in NATS KV keys.
+var invalidHistoryKeyChars = regexp.MustCompile(`[^-_=.a-zA-Z0-9]`)
+
+// historyKey identifies a device across reboots and device renames. It is a
+// valid NATS KV key.
+func historyKey(metric NormalizedSmartData) string {
+	key := metric.Device
+	if metric.DeviceInfo != nil && metric.DeviceInfo.SerialNumber != "" {
+		key = metric.DeviceInfo.DeviceModel + "_" + metric.DeviceInfo.SerialNumber
+	}
+	return invalidHistoryKeyChars.ReplaceAllString(key, "_")
+}
+
+// fileHistoryBackend stores the history of all devices in one JSON file.
+type fileHistoryBackend struct {
+	path string
+}
+
+func (b *fileHistoryBackend) load() (map[string][]HistorySample, error) {
+	samples := make(map[string][]HistorySample)
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return samples, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SMART history %s: %w", b.path, err)
+	}
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("failed to parse SMART history %s: %w", b.path, err)
+	}
+	return samples, nil
+}
+
+// store rewrites the whole file.
+func (b *fileHistoryBackend) store(_ []string, all map[string][]HistorySample) error {
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(b.path, data); err != nil {
+		return fmt.Errorf("failed to write SMART history: %w", err)
+	}
+	return nil
+}
+
+// kvHistoryBackend stores the history of each device under its own key in
+// a NATS JetStream key-value bucket.
+type kvHistoryBackend struct {
+	kv nats.KeyValue
+}
+
+func newKVHistoryBackend(nc *nats.Conn, bucket string) (*kvHistoryBackend, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize JetStream: %w", err)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "SMART history for disk health trends",
+			TTL:         historyRetention,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+	return &kvHistoryBackend{kv: kv}, nil
+}
+
+func (b *kvHistoryBackend) load() (map[string][]HistorySample, error) {
+	samples := make(map[string][]HistorySample)
+	keys, err := b.kv.Keys()
+	if errors.Is(err, nats.ErrNoKeysFound) {
+		return samples, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SMART history keys: %w", err)
+	}
+
+	for _, key := range keys {
+		entry, err := b.kv.Get(key)
+		if err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("failed to read SMART history entry")
+			continue
+		}
+		var deviceSamples []HistorySample
+		if err := json.Unmarshal(entry.Value(), &deviceSamples); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("failed to parse SMART history entry")
+			continue
+		}
+		samples[key] = deviceSamples
+	}
+	return samples, nil
+}
+
+func (b *kvHistoryBackend) store(changed []string, all map[string][]HistorySample) error {
+	// Every new sample stores the whole history of the device again
+	if natsutil.SkipKVWrite() {
+		return nil
+	}
+	for _, key := range changed {
+		data, err := json.Marshal(all[key])
+		if err != nil {
+			return err
+		}
+		if _, err := b.kv.Put(key, data); err != nil {
+			return fmt.Errorf("failed to store SMART history for %s: %w", key, err)
+		}
+	}
+	return nil
+}
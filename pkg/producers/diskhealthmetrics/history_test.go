@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trendIncrease(trends []SmartTrend, indicator, window string) (int64, bool) {
+	for _, trend := range trends {
+		if trend.Indicator == indicator && trend.Window == window {
+			return trend.Increase, true
+		}
+	}
+	return 0, false
+}
+
+func TestSmartHistory_TrendWindows(t *testing.T) {
+	history := newSmartHistory(nil)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := &DeviceInfo{DeviceModel: "ST12000NM", SerialNumber: "ZJV0ABC"}
+
+	// One grown defect per day for eight days.
+	var metrics []NormalizedSmartData
+	for day := 0; day <= 8; day++ {
+		metrics = []NormalizedSmartData{{
+			Device:     "/dev/sda",
+			DeviceInfo: info,
+			Attributes: map[string]SmartAttribute{"grown_defects_count": {RawValue: int64(day)}},
+		}}
+		history.track(metrics, start.Add(time.Duration(day)*24*time.Hour))
+	}
+
+	daily, ok := trendIncrease(metrics[0].Trends, "grown_defects", "24h")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), daily)
+
+	weekly, ok := trendIncrease(metrics[0].Trends, "grown_defects", "7d")
+	require.True(t, ok)
+	assert.Equal(t, int64(7), weekly)
+
+	// Samples older than the retention are dropped.
+	assert.Len(t, history.samples[historyKey(metrics[0])], 9)
+	history.track(metrics, start.Add(9*24*time.Hour))
+	assert.Len(t, history.samples[historyKey(metrics[0])], 9)
+}
+
+func TestSmartHistory_DecreaseIsZero(t *testing.T) {
+	history := newSmartHistory(nil)
+	now := time.Now()
+	pending := int64(5)
+	metrics := []NormalizedSmartData{{Device: "/dev/sdb", PendingSectors: &pending}}
+	history.track(metrics, now)
+
+	pending = 2
+	history.track(metrics, now.Add(2*time.Hour))
+	increase, ok := trendIncrease(metrics[0].Trends, "pending_sectors", "24h")
+	require.True(t, ok)
+	assert.Equal(t, int64(0), increase)
+}
+
+func TestFileHistoryBackend_RoundTrip(t *testing.T) {
+	backend := &fileHistoryBackend{path: filepath.Join(t.TempDir(), "history.json")}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history := newSmartHistory(backend)
+	history.track([]NormalizedSmartData{{
+		Device:     "/dev/nvme0n1",
+		DeviceInfo: &DeviceInfo{DeviceModel: "SAMSUNG MZQL2", SerialNumber: "S64"},
+		Attributes: map[string]SmartAttribute{"nvme_media_errors": {RawValue: 3}},
+	}}, now)
+
+	reloaded := newSmartHistory(backend)
+	samples := reloaded.samples["SAMSUNG_MZQL2_S64"]
+	require.Len(t, samples, 1)
+	assert.Equal(t, int64(3), samples[0].Values["media_errors"])
+	assert.True(t, samples[0].At.Equal(now))
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Actions of a DeviceEvent, as reported by the kernel.
+const (
+	DeviceEventAdd    = "add"
+	DeviceEventRemove = "remove"
+)
+
+// hotplugSettleDelay is how long to wait after the last device event before
+// rescanning, so a whole shelf of drives inserted at once triggers one scan
+// and smartctl does not race the device initialization.
+const hotplugSettleDelay = 2 * time.Second
+
+// DeviceEvent is a whole-disk block device that appeared or disappeared.
+type DeviceEvent struct {
+	Action string
+	Device string // device name as reported by smartctl, e.g. /dev/sda or /dev/nvme0
+}
+
+// hotplugDiskName matches the block devices smartctl can monitor. NVMe
+// namespaces are reported as their controller (nvme0n1 -> nvme0).
+var hotplugDiskName = regexp.MustCompile(`^(?:sd[a-z]+|(nvme\d+)n\d+)$`)
+
+// parseUevent parses a kernel uevent message: an "action@devpath" header
+// followed by NUL-separated KEY=VALUE pairs. It reports false for events
+// other than whole disks being added or removed.
+func parseUevent(msg []byte) (DeviceEvent, bool) {
+	fields := bytes.Split(msg, []byte{0})
+	if len(fields) < 2 || !bytes.Contains(fields[0], []byte("@")) {
+		return DeviceEvent{}, false // not a kernel uevent (e.g. a libudev message)
+	}
+
+	env := make(map[string]string, len(fields))
+	for _, field := range fields[1:] {
+		if key, value, ok := bytes.Cut(field, []byte("=")); ok {
+			env[string(key)] = string(value)
+		}
+	}
+
+	action := env["ACTION"]
+	if env["SUBSYSTEM"] != "block" || env["DEVTYPE"] != "disk" ||
+		(action != DeviceEventAdd && action != DeviceEventRemove) {
+		return DeviceEvent{}, false
+	}
+
+	m := hotplugDiskName.FindStringSubmatch(env["DEVNAME"])
+	if m == nil {
+		return DeviceEvent{}, false // loop, dm, md, optical and other virtual devices
+	}
+	name := env["DEVNAME"]
+	if m[1] != "" {
+		name = m[1]
+	}
+	return DeviceEvent{Action: action, Device: "/dev/" + name}, true
+}
+
+// devdDiskName matches the FreeBSD disks smartctl can monitor: SATA (ada),
+// SCSI and SAS (da) and NVMe controllers (nvme). Partitions and NVMe
+// namespaces (nda, nvme0ns1) are left out.
+var devdDiskName = regexp.MustCompile(`^(?:a?da\d+|nvme\d+)$`)
+
+// parseDevdEvent parses a FreeBSD devd notification, e.g.
+// "!system=DEVFS subsystem=CDEV type=CREATE cdev=ada1". It reports false for
+// events other than disk device nodes being created or destroyed.
+func parseDevdEvent(msg []byte) (DeviceEvent, bool) {
+	line, ok := strings.CutPrefix(strings.TrimSpace(string(msg)), "!")
+	if !ok {
+		return DeviceEvent{}, false // attach, detach and nomatch events
+	}
+
+	env := make(map[string]string)
+	for _, field := range strings.Fields(line) {
+		if key, value, ok := strings.Cut(field, "="); ok {
+			env[key] = value
+		}
+	}
+	if env["system"] != "DEVFS" || env["subsystem"] != "CDEV" || !devdDiskName.MatchString(env["cdev"]) {
+		return DeviceEvent{}, false
+	}
+
+	switch env["type"] {
+	case "CREATE":
+		return DeviceEvent{Action: DeviceEventAdd, Device: "/dev/" + env["cdev"]}, true
+	case "DESTROY":
+		return DeviceEvent{Action: DeviceEventRemove, Device: "/dev/" + env["cdev"]}, true
+	default:
+		return DeviceEvent{}, false
+	}
+}
+
+// applyDeviceEvent returns the disks to monitor after event. Only
+// discovered disks follow the events, an explicitly configured list is kept.
+func applyDeviceEvent(disks []string, event DeviceEvent, discovered bool) []string {
+	if !discovered {
+		return disks
+	}
+	switch event.Action {
+	case DeviceEventAdd:
+		if !slices.Contains(disks, event.Device) {
+			disks = append(disks, event.Device)
+		}
+	case DeviceEventRemove:
+		disks = slices.DeleteFunc(disks, func(disk string) bool { return disk == event.Device })
+	}
+	return disks
+}
+
+// forgetDevice drops the metrics and per-device state of a removed disk.
+func forgetDevice(device string) {
+	deleteDeviceMetrics(device)
+
+	riskStatesMutex.Lock()
+	delete(riskStates, device)
+	riskStatesMutex.Unlock()
+
+	temperatureLevelsMutex.Lock()
+	delete(temperatureLevels, device)
+	temperatureLevelsMutex.Unlock()
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uevent(header string, env ...string) []byte {
+	return []byte(header + "\x00" + strings.Join(env, "\x00") + "\x00")
+}
+
+func TestParseUevent(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   []byte
+		event DeviceEvent
+		ok    bool
+	}{
+		{
+			name: "SATA disk added",
+			msg: uevent("add@/devices/pci0000:00/0000:00:17.0/ata3/host2/target2:0:0/2:0:0:0/block/sdc",
+				"ACTION=add", "SUBSYSTEM=block", "DEVTYPE=disk", "DEVNAME=sdc", "SEQNUM=4242"),
+			event: DeviceEvent{Action: DeviceEventAdd, Device: "/dev/sdc"},
+			ok:    true,
+		},
+		{
+			name: "NVMe namespace removed",
+			msg: uevent("remove@/devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme1/nvme1n1",
+				"ACTION=remove", "SUBSYSTEM=block", "DEVTYPE=disk", "DEVNAME=nvme1n1"),
+			event: DeviceEvent{Action: DeviceEventRemove, Device: "/dev/nvme1"},
+			ok:    true,
+		},
+		{
+			name: "partition",
+			msg: uevent("add@/devices/virtual/block/sdc/sdc1",
+				"ACTION=add", "SUBSYSTEM=block", "DEVTYPE=partition", "DEVNAME=sdc1"),
+		},
+		{
+			name: "loop device",
+			msg: uevent("add@/devices/virtual/block/loop0",
+				"ACTION=add", "SUBSYSTEM=block", "DEVTYPE=disk", "DEVNAME=loop0"),
+		},
+		{
+			name: "change event",
+			msg: uevent("change@/devices/virtual/block/sdc",
+				"ACTION=change", "SUBSYSTEM=block", "DEVTYPE=disk", "DEVNAME=sdc"),
+		},
+		{
+			name: "libudev message",
+			msg:  []byte("libudev\x00\xfe\xed\xca\xfe"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := parseUevent(tt.msg)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.event, event)
+		})
+	}
+}
+
+func TestParseDevdEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   string
+		event DeviceEvent
+		ok    bool
+	}{
+		{
+			name:  "SATA disk added",
+			msg:   "!system=DEVFS subsystem=CDEV type=CREATE cdev=ada1\n",
+			event: DeviceEvent{Action: DeviceEventAdd, Device: "/dev/ada1"},
+			ok:    true,
+		},
+		{
+			name:  "NVMe controller removed",
+			msg:   "!system=DEVFS subsystem=CDEV type=DESTROY cdev=nvme2\n",
+			event: DeviceEvent{Action: DeviceEventRemove, Device: "/dev/nvme2"},
+			ok:    true,
+		},
+		{name: "partition", msg: "!system=DEVFS subsystem=CDEV type=CREATE cdev=da0p1\n"},
+		{name: "NVMe namespace", msg: "!system=DEVFS subsystem=CDEV type=CREATE cdev=nvme2ns1\n"},
+		{name: "CAM passthrough", msg: "!system=DEVFS subsystem=CDEV type=CREATE cdev=pass3\n"},
+		{name: "attach event", msg: "+ahcich2 at slot=2 on ahci0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := parseDevdEvent([]byte(tt.msg))
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.event, event)
+		})
+	}
+}
+
+func TestApplyDeviceEvent(t *testing.T) {
+	disks := []string{"/dev/sda", "/dev/nvme0"}
+
+	disks = applyDeviceEvent(disks, DeviceEvent{Action: DeviceEventAdd, Device: "/dev/sdb"}, true)
+	disks = applyDeviceEvent(disks, DeviceEvent{Action: DeviceEventAdd, Device: "/dev/sdb"}, true)
+	assert.Equal(t, []string{"/dev/sda", "/dev/nvme0", "/dev/sdb"}, disks)
+
+	disks = applyDeviceEvent(disks, DeviceEvent{Action: DeviceEventRemove, Device: "/dev/nvme0"}, true)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, disks)
+
+	configured := applyDeviceEvent([]string{"/dev/sda"}, DeviceEvent{Action: DeviceEventRemove, Device: "/dev/sda"}, false)
+	assert.Equal(t, []string{"/dev/sda"}, configured)
+}
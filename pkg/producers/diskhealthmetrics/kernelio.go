@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// kmsgPath is the kernel log ring buffer device.
+const kmsgPath = "/dev/kmsg"
+
+// Kernel I/O error types reported in KernelIOStats.KernelErrors.
+const (
+	KernelErrorIO      = "io_error"
+	KernelErrorMedium  = "medium_error"
+	KernelErrorTimeout = "timeout"
+)
+
+// KernelIOStats are the OS-level I/O statistics of a device, joined with its
+// SMART data so media health and kernel-visible errors can be correlated.
+type KernelIOStats struct {
+	ReadIOs        uint64           `json:"read_ios"`         // completed reads from /sys/block/<dev>/stat
+	WriteIOs       uint64           `json:"write_ios"`        // completed writes
+	InFlight       uint64           `json:"in_flight"`        // requests currently in flight
+	ReadLatencyMs  float64          `json:"read_latency_ms"`  // average read latency since the previous collection
+	WriteLatencyMs float64          `json:"write_latency_ms"` // average write latency since the previous collection
+	SCSIIOErrors   int64            `json:"scsi_io_errors"`   // ioerr_cnt of the SCSI device, 0 for NVMe
+	KernelErrors   map[string]int64 `json:"kernel_errors"`    // errors logged by the kernel per error type
+}
+
+// blockStat holds the fields of /sys/block/<dev>/stat used for latency.
+type blockStat struct {
+	readIOs, readTicks   uint64
+	writeIOs, writeTicks uint64
+	inFlight             uint64
+}
+
+// parseBlockStat parses /sys/block/<dev>/stat, see
+// Documentation/block/stat.rst in the kernel tree.
+func parseBlockStat(data []byte) (blockStat, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) < 11 {
+		return blockStat{}, fmt.Errorf("unexpected block stat format: %q", data)
+	}
+
+	values := make([]uint64, 9)
+	for i := range values {
+		v, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return blockStat{}, fmt.Errorf("invalid block stat field %d: %w", i, err)
+		}
+		values[i] = v
+	}
+
+	return blockStat{
+		readIOs:    values[0],
+		readTicks:  values[3],
+		writeIOs:   values[4],
+		writeTicks: values[7],
+		inFlight:   values[8],
+	}, nil
+}
+
+// kernelIOErrorPatterns recognize per-device I/O errors in kernel messages.
+// The first submatch is the kernel device name, possibly a partition.
+var kernelIOErrorPatterns = []struct {
+	pattern   *regexp.Regexp
+	errorType string
+}{
+	// blk_update_request / blk_print_req_error
+	{regexp.MustCompile(`critical medium error, dev (\w+)`), KernelErrorMedium},
+	{regexp.MustCompile(`(?:I/O|critical target|critical space allocation) error, dev (\w+)`), KernelErrorIO},
+	{regexp.MustCompile(`Buffer I/O error on dev (\w+)`), KernelErrorIO},
+	// SCSI sense data reported by the sd driver
+	{regexp.MustCompile(`\[(sd[a-z]+)\] .*Medium Error`), KernelErrorMedium},
+	{regexp.MustCompile(`\[(sd[a-z]+)\] .*(?:timing out command|FAILED Result: hostbyte=DID_TIME_OUT)`), KernelErrorTimeout},
+	// NVMe driver
+	{regexp.MustCompile(`nvme (nvme\d+): I/O \d+ (?:\(\w+\) )?QID \d+ timeout`), KernelErrorTimeout},
+	{regexp.MustCompile(`(nvme\d+n\d+(?:p\d+)?): I/O Cmd\(`), KernelErrorIO},
+}
+
+// nvmeControllerName matches NVMe controller names as reported by smartctl.
+var nvmeControllerName = regexp.MustCompile(`^nvme\d+$`)
+
+// partitionSuffix strips partition numbers (sda1 -> sda, nvme0n1p2 -> nvme0n1).
+var partitionSuffix = regexp.MustCompile(`^(sd[a-z]+|nvme\d+n\d+p?)\d*$`)
+
+func parentDevice(name string) string {
+	if m := partitionSuffix.FindStringSubmatch(name); m != nil {
+		return strings.TrimSuffix(m[1], "p")
+	}
+	return name
+}
+
+// parseKernelIOError returns the device and error type of a kernel message,
+// or empty strings if the message is not a per-device I/O error.
+func parseKernelIOError(message string) (string, string) {
+	for _, p := range kernelIOErrorPatterns {
+		if m := p.pattern.FindStringSubmatch(message); m != nil {
+			return parentDevice(m[1]), p.errorType
+		}
+	}
+	return "", ""
+}
+
+// kernelIOCollector keeps the state needed across collections: the previous
+// block stats for latency and the kernel errors counted so far.
+type kernelIOCollector struct {
+	mu        sync.Mutex
+	sysBlock  string
+	kmsg      string
+	lastSeq   int64
+	previous  map[string]blockStat
+	errCounts map[string]map[string]int64 // kernel device -> error type -> count
+}
+
+func newKernelIOCollector(sysBlock, kmsg string) *kernelIOCollector {
+	return &kernelIOCollector{
+		sysBlock:  sysBlock,
+		kmsg:      kmsg,
+		lastSeq:   -1,
+		previous:  make(map[string]blockStat),
+		errCounts: make(map[string]map[string]int64),
+	}
+}
+
+// collect attaches the kernel I/O statistics to every collected device.
+func (c *kernelIOCollector) collect(metrics []NormalizedSmartData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.readKernelLog(); err != nil {
+		log.Warn().Err(err).Msg("failed to read kernel log, kernel I/O error counts are not updated")
+	}
+
+	for i := range metrics {
+		metrics[i].KernelIO = c.deviceStats(metrics[i].Device)
+	}
+}
+
+// blockDevices returns the kernel block devices behind a SMART device name.
+// smartctl reports NVMe controllers (/dev/nvme0), whose namespaces are the
+// block devices (nvme0n1, nvme0n2).
+func (c *kernelIOCollector) blockDevices(device string) []string {
+	if strings.HasPrefix(device, "/dev/bus/") {
+		return nil // drives behind a RAID controller have no own block device
+	}
+
+	name := kernelName(device)
+	if nvmeControllerName.MatchString(name) {
+		namespaces, _ := filepath.Glob(filepath.Join(c.sysBlock, name+"n*"))
+		devices := make([]string, 0, len(namespaces))
+		for _, ns := range namespaces {
+			devices = append(devices, filepath.Base(ns))
+		}
+		return devices
+	}
+	return []string{parentDevice(name)}
+}
+
+func (c *kernelIOCollector) deviceStats(device string) *KernelIOStats {
+	names := c.blockDevices(device)
+	if len(names) == 0 {
+		return nil
+	}
+
+	stats := &KernelIOStats{KernelErrors: make(map[string]int64)}
+	var readTicks, writeTicks, prevReadTicks, prevWriteTicks uint64
+	var prevReadIOs, prevWriteIOs uint64
+	found := false
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(c.sysBlock, name, "stat"))
+		if err != nil {
+			continue
+		}
+		current, err := parseBlockStat(data)
+		if err != nil {
+			log.Debug().Err(err).Str("device", name).Msg("failed to parse block stat")
+			continue
+		}
+		found = true
+
+		previous, ok := c.previous[name]
+		if !ok || current.readIOs < previous.readIOs || current.writeIOs < previous.writeIOs {
+			previous = current // first collection or counters wrapped
+		}
+		c.previous[name] = current
+
+		stats.ReadIOs += current.readIOs
+		stats.WriteIOs += current.writeIOs
+		stats.InFlight += current.inFlight
+		readTicks += current.readTicks
+		writeTicks += current.writeTicks
+		prevReadIOs += previous.readIOs
+		prevWriteIOs += previous.writeIOs
+		prevReadTicks += previous.readTicks
+		prevWriteTicks += previous.writeTicks
+
+		stats.SCSIIOErrors += readDevicePath(c.sysBlock, name).IOErrors
+		for errorType, count := range c.errCounts[name] {
+			stats.KernelErrors[errorType] += count
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	// NVMe timeouts are logged against the controller, not the namespace.
+	if name := kernelName(device); nvmeControllerName.MatchString(name) {
+		for errorType, count := range c.errCounts[name] {
+			stats.KernelErrors[errorType] += count
+		}
+	}
+
+	if ios := stats.ReadIOs - prevReadIOs; ios > 0 {
+		stats.ReadLatencyMs = float64(readTicks-prevReadTicks) / float64(ios)
+	}
+	if ios := stats.WriteIOs - prevWriteIOs; ios > 0 {
+		stats.WriteLatencyMs = float64(writeTicks-prevWriteTicks) / float64(ios)
+	}
+	return stats
+}
+
+func (c *kernelIOCollector) countKernelRecord(record []byte) {
+	header, message, ok := bytes.Cut(record, []byte(";"))
+	if !ok {
+		return
+	}
+	fields := strings.Split(string(header), ",")
+	if len(fields) < 2 {
+		return
+	}
+	seq, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || seq <= c.lastSeq {
+		return
+	}
+	c.lastSeq = seq
+
+	// Continuation lines (key=value dictionary) follow on indented lines.
+	line, _, _ := bytes.Cut(message, []byte("\n"))
+	device, errorType := parseKernelIOError(string(line))
+	if device == "" {
+		return
+	}
+	if c.errCounts[device] == nil {
+		c.errCounts[device] = make(map[string]int64)
+	}
+	c.errCounts[device][errorType]++
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package diskhealthmetrics
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// readKernelLog counts the I/O errors logged since the previous call. Each
+// read of /dev/kmsg returns one record "prio,seq,usec,flags;message"; the
+// sequence number skips records already counted.
+func (c *kernelIOCollector) readKernelLog() error {
+	fd, err := unix.Open(c.kmsg, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", c.kmsg, err)
+	}
+	defer unix.Close(fd)
+
+	buf := make([]byte, 8192)
+	for {
+		n, err := unix.Read(fd, buf)
+		if errors.Is(err, unix.EAGAIN) {
+			return nil
+		}
+		if errors.Is(err, unix.EPIPE) {
+			continue // record was overwritten while reading, continue with the next one
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", c.kmsg, err)
+		}
+		if n == 0 {
+			return nil
+		}
+		c.countKernelRecord(buf[:n])
+	}
+}
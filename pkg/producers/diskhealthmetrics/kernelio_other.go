@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package diskhealthmetrics
+
+import "fmt"
+
+// readKernelLog fails, the kernel log is only read on Linux.
+func (c *kernelIOCollector) readKernelLog() error {
+	return fmt.Errorf("reading %s is not supported on this platform", c.kmsg)
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKernelIOError(t *testing.T) {
+	tests := []struct {
+		message   string
+		device    string
+		errorType string
+	}{
+		{"I/O error, dev sdb, sector 1953525160 op 0x0:(READ) flags 0x80700 phys_seg 1 prio class 0", "sdb", KernelErrorIO},
+		{"blk_update_request: critical medium error, dev sdc, sector 42", "sdc", KernelErrorMedium},
+		{"Buffer I/O error on dev sdd1, logical block 0, async page read", "sdd", KernelErrorIO},
+		{"sd 0:0:2:0: [sde] tag#12 Sense Key : Medium Error [current]", "sde", KernelErrorMedium},
+		{"nvme nvme1: I/O 512 QID 3 timeout, aborting", "nvme1", KernelErrorTimeout},
+		{"nvme0n1p2: I/O Cmd(0x2) @ LBA 1234, 8 blocks, I/O Error (sct 0x2 / sc 0x81) DNR", "nvme0n1", KernelErrorIO},
+		{"EXT4-fs (dm-0): mounted filesystem with ordered data mode", "", ""},
+	}
+
+	for _, tt := range tests {
+		device, errorType := parseKernelIOError(tt.message)
+		assert.Equal(t, tt.device, device, tt.message)
+		assert.Equal(t, tt.errorType, errorType, tt.message)
+	}
+}
+
+func TestKernelIOCollector_DeviceStats(t *testing.T) {
+	sysBlock := t.TempDir()
+	statPath := filepath.Join(sysBlock, "sda", "stat")
+	writeSysfsFile(t, statPath, "100 0 800 500 50 0 400 1000 2 300 1500 0 0 0 0 0 0\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "sda", "device", "ioerr_cnt"), "0x3\n")
+
+	collector := newKernelIOCollector(sysBlock, "")
+	collector.countKernelRecord([]byte("3,100,5000,-;I/O error, dev sda, sector 8 op 0x0:(READ)\n SUBSYSTEM=block\n"))
+	collector.countKernelRecord([]byte("3,100,5000,-;I/O error, dev sda, sector 8 op 0x0:(READ)")) // already counted
+	collector.countKernelRecord([]byte("3,101,5001,-;sd 0:0:0:0: [sda] tag#3 timing out command, waited 180s"))
+
+	stats := collector.deviceStats("/dev/sda")
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(3), stats.SCSIIOErrors)
+	assert.Equal(t, int64(1), stats.KernelErrors[KernelErrorIO])
+	assert.Equal(t, int64(1), stats.KernelErrors[KernelErrorTimeout])
+	assert.Zero(t, stats.ReadLatencyMs, "no latency without a previous sample")
+
+	// 10 more reads taking 200ms, 50 more writes taking 100ms.
+	writeSysfsFile(t, statPath, "110 0 880 700 100 0 800 1100 0 400 1800 0 0 0 0 0 0\n")
+	stats = collector.deviceStats("/dev/sda")
+	require.NotNil(t, stats)
+	assert.Equal(t, 20.0, stats.ReadLatencyMs)
+	assert.Equal(t, 2.0, stats.WriteLatencyMs)
+	assert.Equal(t, uint64(110), stats.ReadIOs)
+
+	assert.Nil(t, collector.deviceStats("/dev/bus/0 [megaraid_disk_00]"))
+}
+
+func TestKernelIOCollector_NVMeNamespaces(t *testing.T) {
+	sysBlock := t.TempDir()
+	writeSysfsFile(t, filepath.Join(sysBlock, "nvme0n1", "stat"), "10 0 80 5 20 0 160 40 1 30 45 0 0 0 0 0 0\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "nvme0n2", "stat"), "5 0 40 5 0 0 0 0 0 5 5 0 0 0 0 0 0\n")
+
+	collector := newKernelIOCollector(sysBlock, "")
+	collector.countKernelRecord([]byte("4,7,100,-;nvme nvme0: I/O 12 QID 2 timeout, reset controller"))
+
+	stats := collector.deviceStats("/dev/nvme0")
+	require.NotNil(t, stats)
+	assert.Equal(t, uint64(15), stats.ReadIOs)
+	assert.Equal(t, uint64(1), stats.InFlight)
+	assert.Equal(t, int64(1), stats.KernelErrors[KernelErrorTimeout])
+}
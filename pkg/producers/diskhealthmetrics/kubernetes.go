@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/cobaltcore-dev/prysm/pkg/kubeclient"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRackLabel is the node label Rook uses for the rack failure domain.
+const DefaultRackLabel = "topology.rook.io/rack"
+
+// zoneLabels are the node labels the zone is read from, the first set wins.
+var zoneLabels = []string{
+	"topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/zone",
+}
+
+// NodeTopology is the failure domain of the node the producer runs on.
+type NodeTopology struct {
+	Zone string
+	Rack string
+}
+
+// topologyFromLabels reads the zone and rack from the labels of a node.
+func topologyFromLabels(labels map[string]string, rackLabel string) NodeTopology {
+	var topology NodeTopology
+	for _, label := range zoneLabels {
+		if zone := labels[label]; zone != "" {
+			topology.Zone = zone
+			break
+		}
+	}
+	if rackLabel != "" {
+		topology.Rack = labels[rackLabel]
+	}
+	return topology
+}
+
+// fetchNodeLabels gets the labels of a node from the Kubernetes API server.
+func fetchNodeLabels(ctx context.Context, kube *kubeclient.Client, nodeName string) (map[string]string, error) {
+	var node struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := kube.Get(ctx, "/api/v1/nodes/"+url.PathEscape(nodeName), &node); err != nil {
+		return nil, err
+	}
+	return node.Metadata.Labels, nil
+}
+
+// inClusterNodeLabels gets the labels of a node with the service account of
+// the pod. The service account needs get permission on nodes.
+func inClusterNodeLabels(nodeName string) (map[string]string, error) {
+	kube, err := kubeclient.InCluster()
+	if err != nil {
+		return nil, err
+	}
+	return fetchNodeLabels(context.Background(), kube, nodeName)
+}
+
+// resolveKubernetesNode completes the node identity in Kubernetes mode:
+// the node name comes from the downward API (NODE_NAME from spec.nodeName),
+// the instance defaults to the pod name, and zone and rack not configured
+// explicitly are read from the node labels.
+func resolveKubernetesNode(cfg *DiskHealthMetricsConfig) error {
+	if cfg.NodeName == "" {
+		return errors.New("node name is not set, expose spec.nodeName as NODE_NAME via the downward API")
+	}
+	if cfg.InstanceID == "" {
+		hostname, err := os.Hostname() // the pod name
+		if err != nil {
+			return fmt.Errorf("failed to get pod name: %w", err)
+		}
+		cfg.InstanceID = hostname
+	}
+
+	if cfg.Zone != "" && (cfg.Rack != "" || cfg.RackLabel == "") {
+		return nil
+	}
+	labels, err := inClusterNodeLabels(cfg.NodeName)
+	if err != nil {
+		log.Warn().Err(err).Str("node", cfg.NodeName).Msg("failed to read node labels, zone and rack are not detected")
+		return nil
+	}
+	topology := topologyFromLabels(labels, cfg.RackLabel)
+	if cfg.Zone == "" {
+		cfg.Zone = topology.Zone
+	}
+	if cfg.Rack == "" {
+		cfg.Rack = topology.Rack
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/kubeclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchNodeLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, `{"reason":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/nodes/storage-01" {
+			http.Error(w, `{"reason":"NotFound"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"kind":"Node","metadata":{"name":"storage-01","labels":{
+			"topology.kubernetes.io/zone":"eu-de-1a",
+			"topology.rook.io/rack":"rack-17"}}}`))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("test-token\n"), 0o600))
+	kube := kubeclient.New(server.Client(), server.URL, tokenFile)
+
+	labels, err := fetchNodeLabels(context.Background(), kube, "storage-01")
+	require.NoError(t, err)
+	assert.Equal(t, NodeTopology{Zone: "eu-de-1a", Rack: "rack-17"}, topologyFromLabels(labels, DefaultRackLabel))
+
+	_, err = fetchNodeLabels(context.Background(), kube, "storage-02")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestTopologyFromLabels(t *testing.T) {
+	labels := map[string]string{
+		"failure-domain.beta.kubernetes.io/zone": "legacy-zone",
+		"example.com/rack":                       "r2",
+	}
+	assert.Equal(t, NodeTopology{Zone: "legacy-zone", Rack: "r2"}, topologyFromLabels(labels, "example.com/rack"))
+	assert.Equal(t, NodeTopology{Zone: "legacy-zone"}, topologyFromLabels(labels, DefaultRackLabel))
+}
+
+func TestResolveKubernetesNode(t *testing.T) {
+	cfg := DiskHealthMetricsConfig{Kubernetes: true}
+	assert.Error(t, resolveKubernetesNode(&cfg), "node name is required")
+
+	// Explicit zone and rack need no API access.
+	cfg = DiskHealthMetricsConfig{Kubernetes: true, NodeName: "storage-01", Zone: "z1", Rack: "r1", RackLabel: DefaultRackLabel}
+	require.NoError(t, resolveKubernetesNode(&cfg))
+	assert.NotEmpty(t, cfg.InstanceID)
+	assert.Equal(t, "z1", cfg.Zone)
+	assert.Equal(t, "r1", cfg.Rack)
+}
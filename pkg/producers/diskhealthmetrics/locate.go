@@ -0,0 +1,302 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultLocateSubject is the subject prysm disk locate sends requests to.
+const DefaultLocateSubject = "osd.disk.locate"
+
+// Methods a drive is located with, in the order they are tried.
+const (
+	LocateMethodCephOrch = "ceph-orch" // ceph orch device light, for clusters managed by cephadm or Rook
+	LocateMethodStorcli  = "storcli"   // storcli or perccli, for drives behind MegaRAID controllers
+)
+
+// locateTimeout bounds the commands run for a request, ceph orch may take a
+// while to reach the host.
+const locateTimeout = 30 * time.Second
+
+// errNoLocateMethod is returned if neither ceph orch nor a RAID controller
+// CLI knows the drive.
+var errNoLocateMethod = errors.New("no locate method knows the drive, install the ceph CLI with an admin keyring or storcli")
+
+// LocateRequest asks the node with the drive of a serial number to turn
+// the identification LED of its slot on or off.
+type LocateRequest struct {
+	Serial string `json:"serial"`
+	Off    bool   `json:"off,omitempty"` // turn the LED off again
+}
+
+// LocateResponse is the answer of the node with the drive.
+type LocateResponse struct {
+	NodeName   string         `json:"node_name"`
+	InstanceID string         `json:"instance_id"`
+	Zone       string         `json:"zone,omitempty"`
+	Rack       string         `json:"rack,omitempty"`
+	Serial     string         `json:"serial"`
+	Device     string         `json:"device"`
+	Model      string         `json:"model,omitempty"`
+	OSDID      string         `json:"osd_id,omitempty"`
+	Location   *DriveLocation `json:"location,omitempty"` // with --enclosure-slots
+	Method     string         `json:"method,omitempty"`   // ceph-orch or storcli
+	LightOn    bool           `json:"light_on"`
+	Error      string         `json:"error,omitempty"` // set if the LED could not be switched
+}
+
+// driveLocator switches the identification LEDs of the drives of the latest
+// scan.
+type driveLocator struct {
+	cfg DiskHealthMetricsConfig
+	// run runs a command and returns its standard output, replaced in tests
+	run      func(ctx context.Context, name string, args ...string) ([]byte, error)
+	lookPath func(file string) (string, error)
+
+	mu      sync.Mutex
+	metrics []NormalizedSmartData
+}
+
+func newDriveLocator(cfg DiskHealthMetricsConfig) *driveLocator {
+	return &driveLocator{
+		cfg: cfg,
+		run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).Output()
+		},
+		lookPath: exec.LookPath,
+	}
+}
+
+// update replaces the scan the drives are looked up in.
+func (l *driveLocator) update(metrics []NormalizedSmartData) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.metrics = metrics
+}
+
+// find returns the drive with the serial number, nil if it is not in this
+// node.
+func (l *driveLocator) find(serial string) *NormalizedSmartData {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.metrics {
+		if info := l.metrics[i].DeviceInfo; info != nil && sameSerial(info.SerialNumber, serial) {
+			metric := l.metrics[i]
+			return &metric
+		}
+	}
+	return nil
+}
+
+func sameSerial(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// locate switches the LED of a drive of this node, trying ceph orch first
+// and the RAID controller CLIs second.
+func (l *driveLocator) locate(ctx context.Context, serial string, on bool) (string, error) {
+	var errs []error
+	for _, method := range []struct {
+		name   string
+		locate func(ctx context.Context, serial string, on bool) (bool, error)
+	}{
+		{LocateMethodCephOrch, l.cephOrchLocate},
+		{LocateMethodStorcli, l.storcliLocate},
+	} {
+		found, err := method.locate(ctx, serial, on)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", method.name, err))
+			continue
+		}
+		if found {
+			return method.name, nil
+		}
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return "", errNoLocateMethod
+}
+
+// cephOrchDevices is the output of ceph orch device ls --format json, one
+// entry per host.
+type cephOrchDevices []struct {
+	Name    string `json:"name"` // host name
+	Devices []struct {
+		Path     string `json:"path"`
+		DeviceID string `json:"device_id"` // <vendor>_<model>_<serial>
+		SysAPI   struct {
+			Serial string `json:"serial"`
+		} `json:"sys_api"`
+	} `json:"devices"`
+}
+
+// parseCephOrchDevice returns the Ceph device ID of the drive with the
+// serial number, empty if the orchestrator does not know it.
+func parseCephOrchDevice(out []byte, serial string) (string, error) {
+	var hosts cephOrchDevices
+	if err := json.Unmarshal(out, &hosts); err != nil {
+		return "", fmt.Errorf("error parsing ceph orch device ls JSON: %v", err)
+	}
+	for _, host := range hosts {
+		for _, device := range host.Devices {
+			if device.DeviceID == "" {
+				continue
+			}
+			if sameSerial(device.SysAPI.Serial, serial) || strings.HasSuffix(strings.ToLower(device.DeviceID), "_"+strings.ToLower(strings.TrimSpace(serial))) {
+				return device.DeviceID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// cephOrchLocate switches the ident LED with ceph orch device light. found
+// is false if the ceph CLI is missing or the orchestrator does not know the
+// drive.
+func (l *driveLocator) cephOrchLocate(ctx context.Context, serial string, on bool) (bool, error) {
+	if _, err := l.lookPath("ceph"); err != nil {
+		return false, nil
+	}
+	out, err := l.run(ctx, "ceph", "orch", "device", "ls", "--format", "json")
+	if err != nil {
+		return false, fmt.Errorf("error running ceph orch device ls: %v", err)
+	}
+	deviceID, err := parseCephOrchDevice(out, serial)
+	if err != nil || deviceID == "" {
+		return false, err
+	}
+	if _, err := l.run(ctx, "ceph", "orch", "device", "light", onOff(on), deviceID, "ident"); err != nil {
+		return false, fmt.Errorf("error turning the light of %s %s: %v", deviceID, onOff(on), err)
+	}
+	return true, nil
+}
+
+var storcliDetailPattern = regexp.MustCompile(`^Drive (/c\d+(?:/e\d+)?/s\d+) - Detailed Information$`)
+
+// parseStorcliDrive returns the storcli path of the drive with the serial
+// number, e.g. /c0/e252/s3, from the output of
+// storcli /call/eall/sall show all J. It is empty if no controller has the
+// drive.
+func parseStorcliDrive(out []byte, serial string) (string, error) {
+	var output struct {
+		Controllers []struct {
+			ResponseData map[string]json.RawMessage `json:"Response Data"`
+		} `json:"Controllers"`
+	}
+	if err := json.Unmarshal(out, &output); err != nil {
+		return "", fmt.Errorf("error parsing storcli JSON: %v", err)
+	}
+	for _, controller := range output.Controllers {
+		for key, data := range controller.ResponseData {
+			match := storcliDetailPattern.FindStringSubmatch(key)
+			if match == nil {
+				continue
+			}
+			var detail map[string]json.RawMessage
+			if err := json.Unmarshal(data, &detail); err != nil {
+				continue
+			}
+			var attributes struct {
+				SN string `json:"SN"`
+			}
+			if err := json.Unmarshal(detail["Drive "+match[1]+" Device attributes"], &attributes); err != nil {
+				continue
+			}
+			if sameSerial(attributes.SN, serial) {
+				return match[1], nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// storcliLocate blinks the slot with the first RAID controller CLI
+// installed. found is false if none is installed or no controller has the
+// drive.
+func (l *driveLocator) storcliLocate(ctx context.Context, serial string, on bool) (bool, error) {
+	for _, cli := range raidCLIs {
+		if _, err := l.lookPath(cli); err != nil {
+			continue
+		}
+		out, err := l.run(ctx, cli, "/call/eall/sall", "show", "all", "J")
+		if err != nil {
+			return false, fmt.Errorf("error running %s: %v", cli, err)
+		}
+		drive, err := parseStorcliDrive(out, serial)
+		if err != nil || drive == "" {
+			return false, err
+		}
+		action := "start"
+		if !on {
+			action = "stop"
+		}
+		if _, err := l.run(ctx, cli, drive, action, "locate"); err != nil {
+			return false, fmt.Errorf("error running %s %s %s locate: %v", cli, drive, action, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// respond answers a JSON-encoded request received over NATS, where all
+// producers share the subject: only the node with the drive answers (ok is
+// false on the others). Errors are reported in the response.
+func (l *driveLocator) respond(ctx context.Context, data []byte) (response []byte, ok bool) {
+	var req LocateRequest
+	if err := json.Unmarshal(data, &req); err != nil || strings.TrimSpace(req.Serial) == "" {
+		return nil, false
+	}
+	metric := l.find(req.Serial)
+	if metric == nil {
+		return nil, false
+	}
+
+	resp := LocateResponse{
+		NodeName:   l.cfg.NodeName,
+		InstanceID: l.cfg.InstanceID,
+		Zone:       l.cfg.Zone,
+		Rack:       l.cfg.Rack,
+		Serial:     metric.DeviceInfo.SerialNumber,
+		Device:     metric.Device,
+		Model:      metric.DeviceInfo.DeviceModel,
+		OSDID:      metric.OSDID,
+		Location:   metric.Location,
+	}
+	method, err := l.locate(ctx, resp.Serial, !req.Off)
+	resp.Method = method
+	result := "success"
+	if err != nil {
+		resp.Error = err.Error()
+		result = "error"
+		log.Warn().Err(err).Str("device", metric.Device).Str("serial", resp.Serial).Msg("failed to switch the identification LED")
+	} else {
+		resp.LightOn = !req.Off
+		log.Info().Str("device", metric.Device).Str("serial", resp.Serial).Str("method", method).Bool("light_on", resp.LightOn).Msg("identification LED switched")
+	}
+	locateRequestsCounter.WithLabelValues(l.cfg.NodeName, l.cfg.InstanceID, onOff(!req.Off), result).Inc()
+
+	response, _ = json.Marshal(resp)
+	return response, true
+}
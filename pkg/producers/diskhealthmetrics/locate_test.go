@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cephOrchDeviceLs = `[
+  {"name": "storage-01", "addr": "10.0.0.1", "devices": [
+    {"path": "/dev/sdb", "device_id": "SEAGATE_ST8000NM0055_ZA1B2C3D", "sys_api": {"model": "ST8000NM0055"}},
+    {"path": "/dev/sdc", "device_id": "", "sys_api": {}}
+  ]},
+  {"name": "storage-02", "addr": "10.0.0.2", "devices": [
+    {"path": "/dev/nvme0n1", "device_id": "Samsung_SSD_980_S64ANS0T1", "sys_api": {"serial": "S64ANS0T1-B"}}
+  ]}
+]`
+
+const storcliShowAll = `{"Controllers": [{
+  "Command Status": {"Controller": 0, "Status": "Success"},
+  "Response Data": {
+    "Drive /c0/e252/s0": [{"EID:Slt": "252:0", "DID": 8, "State": "Onln"}],
+    "Drive /c0/e252/s0 - Detailed Information": {
+      "Drive /c0/e252/s0 State": {"Media Error Count": 0},
+      "Drive /c0/e252/s0 Device attributes": {"SN": "  WD-WCC4N1234567  ", "Model Number": "WDC WD40EFRX"}
+    },
+    "Drive /c0/s3 - Detailed Information": {
+      "Drive /c0/s3 Device attributes": {"SN": "PHYS1234"}
+    }
+  }
+}]}`
+
+func TestParseCephOrchDevice(t *testing.T) {
+	id, err := parseCephOrchDevice([]byte(cephOrchDeviceLs), "za1b2c3d")
+	require.NoError(t, err)
+	assert.Equal(t, "SEAGATE_ST8000NM0055_ZA1B2C3D", id, "matched by the device ID")
+
+	id, err = parseCephOrchDevice([]byte(cephOrchDeviceLs), "S64ANS0T1-B")
+	require.NoError(t, err)
+	assert.Equal(t, "Samsung_SSD_980_S64ANS0T1", id, "matched by the serial of sys_api")
+
+	id, err = parseCephOrchDevice([]byte(cephOrchDeviceLs), "B2C3D")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	_, err = parseCephOrchDevice([]byte("not json"), "ZA1B2C3D")
+	assert.Error(t, err)
+}
+
+func TestParseStorcliDrive(t *testing.T) {
+	drive, err := parseStorcliDrive([]byte(storcliShowAll), "WD-WCC4N1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "/c0/e252/s0", drive)
+
+	drive, err = parseStorcliDrive([]byte(storcliShowAll), "PHYS1234")
+	require.NoError(t, err)
+	assert.Equal(t, "/c0/s3", drive, "drive without an enclosure")
+
+	drive, err = parseStorcliDrive([]byte(storcliShowAll), "ZA1B2C3D")
+	require.NoError(t, err)
+	assert.Empty(t, drive)
+}
+
+// fakeLocator runs the commands of tools, the others are not installed
+func fakeLocator(tools map[string]func(args []string) ([]byte, error)) (*driveLocator, *[]string) {
+	locator := newDriveLocator(DiskHealthMetricsConfig{NodeName: "storage-01", InstanceID: "prysm-x7k2p"})
+	var commands []string
+	locator.lookPath = func(file string) (string, error) {
+		if _, ok := tools[file]; ok {
+			return "/usr/bin/" + file, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	locator.run = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return tools[name](args)
+	}
+	locator.update([]NormalizedSmartData{
+		{Device: "/dev/sdb", OSDID: "12", DeviceInfo: &DeviceInfo{SerialNumber: "ZA1B2C3D", DeviceModel: "ST8000NM0055"}, Location: &DriveLocation{Enclosure: "500056b3", Slot: "4"}},
+		{Device: "/dev/bus/0", DeviceInfo: &DeviceInfo{SerialNumber: "WD-WCC4N1234567"}},
+	})
+	return locator, &commands
+}
+
+func TestDriveLocator_Respond(t *testing.T) {
+	locator, commands := fakeLocator(map[string]func([]string) ([]byte, error){
+		"ceph": func(args []string) ([]byte, error) {
+			if args[1] == "device" && args[2] == "ls" {
+				return []byte(cephOrchDeviceLs), nil
+			}
+			return nil, nil
+		},
+		"storcli64": func(args []string) ([]byte, error) {
+			if args[0] == "/call/eall/sall" {
+				return []byte(storcliShowAll), nil
+			}
+			return nil, nil
+		},
+	})
+
+	response, ok := locator.respond(context.Background(), []byte(`{"serial": "ZA1B2C3D"}`))
+	require.True(t, ok)
+	var resp LocateResponse
+	require.NoError(t, json.Unmarshal(response, &resp))
+	assert.Equal(t, LocateResponse{
+		NodeName: "storage-01", InstanceID: "prysm-x7k2p", Serial: "ZA1B2C3D", Device: "/dev/sdb", Model: "ST8000NM0055", OSDID: "12",
+		Location: &DriveLocation{Enclosure: "500056b3", Slot: "4"}, Method: LocateMethodCephOrch, LightOn: true,
+	}, resp)
+	assert.Equal(t, "ceph orch device light on SEAGATE_ST8000NM0055_ZA1B2C3D ident", (*commands)[1])
+
+	// Unknown to ceph orch, the drive is behind the RAID controller
+	response, ok = locator.respond(context.Background(), []byte(`{"serial": "WD-WCC4N1234567", "off": true}`))
+	require.True(t, ok)
+	resp = LocateResponse{}
+	require.NoError(t, json.Unmarshal(response, &resp))
+	assert.Equal(t, LocateMethodStorcli, resp.Method)
+	assert.False(t, resp.LightOn)
+	assert.Empty(t, resp.Error)
+	assert.Equal(t, "storcli64 /c0/e252/s0 stop locate", (*commands)[len(*commands)-1])
+
+	// Drives of other nodes and invalid requests are left to other producers
+	_, ok = locator.respond(context.Background(), []byte(`{"serial": "S64ANS0T1-B"}`))
+	assert.False(t, ok)
+	_, ok = locator.respond(context.Background(), []byte(`{}`))
+	assert.False(t, ok)
+}
+
+func TestDriveLocator_Failure(t *testing.T) {
+	locator, _ := fakeLocator(nil)
+	response, ok := locator.respond(context.Background(), []byte(`{"serial": "ZA1B2C3D"}`))
+	require.True(t, ok)
+	var resp LocateResponse
+	require.NoError(t, json.Unmarshal(response, &resp))
+	assert.Equal(t, errNoLocateMethod.Error(), resp.Error)
+	assert.False(t, resp.LightOn)
+
+	locator, _ = fakeLocator(map[string]func([]string) ([]byte, error){
+		"ceph": func([]string) ([]byte, error) { return nil, errors.New("no orchestrator configured") },
+	})
+	method, err := locator.locate(context.Background(), "ZA1B2C3D", true)
+	assert.Empty(t, method)
+	assert.ErrorContains(t, err, "ceph-orch: error running ceph orch device ls: no orchestrator configured")
+}
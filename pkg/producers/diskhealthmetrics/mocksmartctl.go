@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// smartctlCommand runs smartctl with args and returns its output. It is
+// replaced by a mockSmartctl in mock mode.
+var smartctlCommand = hostPlatform.runSmartctl
+
+const (
+	mockScanFile       = "scan.json"      // answers smartctl --scan-open
+	mockSelfTestSuffix = ".selftest.json" // answers the self-test log, the device file if missing
+)
+
+// mockSmartctl answers smartctl invocations with canned JSON outputs from a
+// directory, for development, demos and integration tests on machines
+// without disks or root access. A device is answered with <name>.json,
+// where name is the device path without /dev/ and with / replaced by _,
+// followed by _<device type> for drives behind a controller, e.g. sda.json
+// or bus_0_megaraid_3.json. Self-tests are never started.
+type mockSmartctl struct {
+	dir string
+}
+
+// useMockSmartctl answers all smartctl invocations from dir.
+func useMockSmartctl(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open mock smartctl directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("mock smartctl path %s is not a directory", dir)
+	}
+	smartctlCommand = mockSmartctl{dir: dir}.run
+	return nil
+}
+
+// mockFileName returns the base name of the canned output of a device.
+func mockFileName(devicePath, deviceType string) string {
+	name := strings.ReplaceAll(strings.TrimPrefix(devicePath, "/dev/"), "/", "_")
+	if deviceType != "" {
+		name += "_" + strings.ReplaceAll(deviceType, ",", "_")
+	}
+	return name
+}
+
+func (m mockSmartctl) run(ctx context.Context, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var devicePath, deviceType, testType string
+	var scan, selfTestLog bool
+	for _, arg := range args {
+		switch {
+		case arg == "--scan-open":
+			scan = true
+		case arg == "--log=selftest":
+			selfTestLog = true
+		case strings.HasPrefix(arg, "--test="):
+			testType = strings.TrimPrefix(arg, "--test=")
+		case strings.HasPrefix(arg, "--device="):
+			deviceType = strings.TrimPrefix(arg, "--device=")
+		case !strings.HasPrefix(arg, "-"):
+			devicePath = arg
+		}
+	}
+
+	switch {
+	case scan:
+		return m.scan()
+	case testType != "":
+		log.Info().Str("device", devicePath).Str("test_type", testType).Msg("mock smartctl: not starting self-test")
+		return nil, nil
+	}
+
+	name := mockFileName(devicePath, deviceType)
+	if selfTestLog {
+		if out, err := os.ReadFile(filepath.Join(m.dir, name+mockSelfTestSuffix)); err == nil {
+			return out, nil
+		}
+	}
+	out, err := os.ReadFile(filepath.Join(m.dir, name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no canned smartctl output for %s: %w", devicePath, err)
+	}
+	return out, nil
+}
+
+// scan answers smartctl --scan-open with scan.json, or lists every canned
+// device output as /dev/<name> if the directory has none.
+func (m mockSmartctl) scan() ([]byte, error) {
+	out, err := os.ReadFile(filepath.Join(m.dir, mockScanFile))
+	if err == nil {
+		return out, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(m.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var scanOutput SmartCtlScanOutput
+	for _, file := range files {
+		name := filepath.Base(file)
+		if strings.HasSuffix(name, mockSelfTestSuffix) {
+			continue
+		}
+		scanOutput.Devices = append(scanOutput.Devices, SmartCtlDevice{Name: "/dev/" + strings.TrimSuffix(name, ".json")})
+	}
+	return json.Marshal(scanOutput)
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockSmartctl(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"sda.json":              `{"device": {"name": "/dev/sda", "protocol": "ATA"}, "model_name": "MOCK SSD"}`,
+		"sda.selftest.json":     `{"ata_smart_data": {"capabilities": {"self_tests_supported": true}}}`,
+		"nvme0.json":            `{"device": {"name": "/dev/nvme0", "protocol": "NVMe"}}`,
+		"bus_0_megaraid_3.json": `{"device": {"name": "/dev/bus/0", "info_name": "/dev/bus/0 [megaraid_disk_03]", "protocol": "SCSI"}}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	original := smartctlCommand
+	t.Cleanup(func() { smartctlCommand = original })
+	require.NoError(t, useMockSmartctl(dir))
+	ctx := context.Background()
+
+	scan, err := discoverDevices()
+	require.NoError(t, err)
+	names := []string{}
+	for _, device := range scan.Devices {
+		names = append(names, device.Name)
+	}
+	assert.Equal(t, []string{"/dev/bus_0_megaraid_3", "/dev/nvme0", "/dev/sda"}, names, "self-test logs are not listed as devices")
+
+	data, err := collectSmartData(ctx, "/dev/sda", "")
+	require.NoError(t, err)
+	assert.Equal(t, "MOCK SSD", data.ModelName)
+
+	data, err = collectSmartData(ctx, "/dev/bus/0", "megaraid,3")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/bus/0 [megaraid_disk_03]", data.Device.InfoName)
+
+	_, err = collectSmartData(ctx, "/dev/sdz", "")
+	assert.ErrorContains(t, err, "no canned smartctl output")
+
+	status, err := collectSelfTestStatus(ctx, "/dev/sda", "")
+	require.NoError(t, err)
+	assert.True(t, status.Supported)
+
+	assert.NoError(t, startSelfTest("/dev/sda", "", SelfTestShort), "self-tests are only logged")
+	assert.NoError(t, startSelfTest("/dev/bus/0", "megaraid,3", SelfTestLong))
+
+	// scan.json takes precedence over the listed files.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, mockScanFile), []byte(`{"devices": [{"name": "/dev/bus/0", "type": "megaraid,3"}]}`), 0o600))
+	scan, err = discoverDevices()
+	require.NoError(t, err)
+	assert.Equal(t, []SmartCtlDevice{{Name: "/dev/bus/0", Type: "megaraid,3"}}, scan.Devices)
+
+	assert.Error(t, useMockSmartctl(filepath.Join(dir, "sda.json")))
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysBlockPath is the sysfs directory used for topology discovery.
+const sysBlockPath = "/sys/block"
+
+// DevicePath is one SCSI path to a physical device, either a multipath
+// member or a second port of a dual-ported SAS drive behind an expander.
+type DevicePath struct {
+	Name     string `json:"name"`      // e.g. /dev/sdc
+	State    string `json:"state"`     // SCSI device state, e.g. running, offline, blocked
+	IOErrors int64  `json:"io_errors"` // ioerr_cnt of the SCSI device
+}
+
+// multipathTopology maps dm-multipath devices to their member paths.
+type multipathTopology struct {
+	pathToMap map[string]string   // sdX -> dm-N
+	mapPaths  map[string][]string // dm-N -> [sdX, ...]
+	mapNames  map[string]string   // dm-N -> mpath name (as in /dev/mapper)
+}
+
+// discoverMultipathTopology reads the dm-multipath maps from sysfs. Only
+// device-mapper devices whose uuid starts with "mpath-" are considered, so
+// LVM volumes (used by Ceph OSDs) are not mistaken for multipath maps.
+func discoverMultipathTopology(sysBlock string) *multipathTopology {
+	topology := &multipathTopology{
+		pathToMap: make(map[string]string),
+		mapPaths:  make(map[string][]string),
+		mapNames:  make(map[string]string),
+	}
+
+	dms, _ := filepath.Glob(filepath.Join(sysBlock, "dm-*"))
+	for _, dmPath := range dms {
+		uuid, err := os.ReadFile(filepath.Join(dmPath, "dm", "uuid"))
+		if err != nil || !strings.HasPrefix(string(uuid), "mpath-") {
+			continue
+		}
+
+		dm := filepath.Base(dmPath)
+		if name, err := os.ReadFile(filepath.Join(dmPath, "dm", "name")); err == nil {
+			topology.mapNames[dm] = strings.TrimSpace(string(name))
+		}
+
+		slaves, err := os.ReadDir(filepath.Join(dmPath, "slaves"))
+		if err != nil {
+			continue
+		}
+		for _, slave := range slaves {
+			topology.pathToMap[slave.Name()] = dm
+			topology.mapPaths[dm] = append(topology.mapPaths[dm], slave.Name())
+		}
+		sort.Strings(topology.mapPaths[dm])
+	}
+
+	return topology
+}
+
+// resolve returns the device smartctl should query for disk. Multipath maps
+// (/dev/dm-N or /dev/mapper/<name>) cannot be queried directly, so the first
+// running member path is used instead.
+func (t *multipathTopology) resolve(disk, sysBlock string) string {
+	dm := t.mapForDevice(disk)
+	if dm == "" || t.pathToMap[kernelName(disk)] != "" {
+		return disk
+	}
+
+	paths := t.mapPaths[dm]
+	for _, path := range paths {
+		if readPathState(sysBlock, path) == "running" {
+			return "/dev/" + path
+		}
+	}
+	if len(paths) > 0 {
+		return "/dev/" + paths[0]
+	}
+	return disk
+}
+
+// mapForDevice returns the dm-N multipath map that disk belongs to, whether
+// disk is the map itself or one of its member paths.
+func (t *multipathTopology) mapForDevice(disk string) string {
+	name := kernelName(disk)
+	if dm, ok := t.pathToMap[name]; ok {
+		return dm
+	}
+	if _, ok := t.mapPaths[name]; ok {
+		return name
+	}
+	for dm, mapName := range t.mapNames {
+		if filepath.Base(disk) == mapName {
+			return dm
+		}
+	}
+	return ""
+}
+
+// kernelName resolves symlinks such as /dev/disk/by-id/... and returns the
+// kernel device name (sdc, dm-3).
+func kernelName(disk string) string {
+	return filepath.Base(normalizeDevicePath(disk))
+}
+
+// deviceIdentity returns a stable identifier for the physical drive behind a
+// path, used to recognize the same drive reached through different paths.
+func deviceIdentity(smartData *SmartCtlOutput) string {
+	switch {
+	case smartData.WWN != nil && (smartData.WWN.NAA != 0 || smartData.WWN.OUI != 0 || smartData.WWN.ID != 0):
+		return fmt.Sprintf("wwn-%x-%06x-%09x", smartData.WWN.NAA, smartData.WWN.OUI, smartData.WWN.ID)
+	case smartData.LogicalUnitID != "":
+		return "lun-" + smartData.LogicalUnitID
+	case smartData.SerialNumber != "":
+		model := smartData.ModelName
+		if model == "" {
+			model = smartData.SCSIModelName
+		}
+		return "serial-" + model + "-" + smartData.SerialNumber
+	default:
+		return ""
+	}
+}
+
+// devicePaths returns the path details for all member paths of the map disk
+// belongs to, or nil if disk is not a multipath device.
+func (t *multipathTopology) devicePaths(disk, sysBlock string) []DevicePath {
+	dm := t.mapForDevice(disk)
+	if dm == "" {
+		return nil
+	}
+
+	paths := make([]DevicePath, 0, len(t.mapPaths[dm]))
+	for _, path := range t.mapPaths[dm] {
+		paths = append(paths, readDevicePath(sysBlock, path))
+	}
+	return paths
+}
+
+func readDevicePath(sysBlock, name string) DevicePath {
+	path := DevicePath{Name: "/dev/" + name, State: readPathState(sysBlock, name)}
+	if raw, err := os.ReadFile(filepath.Join(sysBlock, name, "device", "ioerr_cnt")); err == nil {
+		if count, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(string(raw)), "0x"), 16, 64); err == nil {
+			path.IOErrors = count
+		}
+	}
+	return path
+}
+
+func readPathState(sysBlock, name string) string {
+	state, err := os.ReadFile(filepath.Join(sysBlock, name, "device", "state"))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(state))
+}
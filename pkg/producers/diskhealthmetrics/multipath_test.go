@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSysfsFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestMultipathTopology(t *testing.T) {
+	sysBlock := t.TempDir()
+
+	// dm-0 is a multipath map over sdb and sdc, dm-1 an LVM volume on sdd.
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-0", "dm", "uuid"), "mpath-3600508b400105e210000900000490000\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-0", "dm", "name"), "mpatha\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-0", "slaves", "sdb"), "")
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-0", "slaves", "sdc"), "")
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-1", "dm", "uuid"), "LVM-abc\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "dm-1", "slaves", "sdd"), "")
+
+	writeSysfsFile(t, filepath.Join(sysBlock, "sdb", "device", "state"), "offline\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "sdb", "device", "ioerr_cnt"), "0x1a\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "sdc", "device", "state"), "running\n")
+	writeSysfsFile(t, filepath.Join(sysBlock, "sdc", "device", "ioerr_cnt"), "0x0\n")
+
+	topology := discoverMultipathTopology(sysBlock)
+
+	assert.Equal(t, "dm-0", topology.mapForDevice("/dev/sdb"))
+	assert.Equal(t, "dm-0", topology.mapForDevice("/dev/mapper/mpatha"))
+	assert.Empty(t, topology.mapForDevice("/dev/sdd"), "LVM volumes are not multipath maps")
+
+	// The map itself resolves to its first running path, members stay as they are.
+	assert.Equal(t, "/dev/sdc", topology.resolve("/dev/mapper/mpatha", sysBlock))
+	assert.Equal(t, "/dev/sdb", topology.resolve("/dev/sdb", sysBlock))
+
+	assert.Equal(t, []DevicePath{
+		{Name: "/dev/sdb", State: "offline", IOErrors: 26},
+		{Name: "/dev/sdc", State: "running", IOErrors: 0},
+	}, topology.devicePaths("/dev/sdc", sysBlock))
+}
+
+func TestDeviceIdentity(t *testing.T) {
+	assert.Equal(t, "wwn-5-000c50-0a1b2c3d4", deviceIdentity(&SmartCtlOutput{WWN: &SmartCtlWWN{NAA: 5, OUI: 0xc50, ID: 0xa1b2c3d4}}))
+	assert.Equal(t, "lun-0x5000c500a1b2c3d4", deviceIdentity(&SmartCtlOutput{LogicalUnitID: "0x5000c500a1b2c3d4"}))
+	assert.Equal(t, "serial-ST4000-ZC1", deviceIdentity(&SmartCtlOutput{SCSIModelName: "ST4000", SerialNumber: "ZC1"}))
+	assert.Empty(t, deviceIdentity(&SmartCtlOutput{}))
+}
@@ -5,10 +5,14 @@
 package diskhealthmetrics
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
 )
 
 // convertToNatsEvent converts NormalizedSmartData to a NatsEvent
@@ -39,6 +43,31 @@ func convertToNatsEvent(normalizedData NormalizedSmartData, config *DiskHealthMe
 	if normalizedData.SSDLifeUsed != nil {
 		details["SSDLifeUsed"] = fmt.Sprintf("%d", *normalizedData.SSDLifeUsed)
 	}
+	if normalizedData.OSDID != "" {
+		details["OSDID"] = normalizedData.OSDID
+	}
+	if normalizedData.CephCluster != "" {
+		details["CephCluster"] = normalizedData.CephCluster
+	}
+
+	if endurance := normalizedData.Endurance; endurance != nil {
+		details["RemainingLifeDays"] = fmt.Sprintf("%.0f", endurance.RemainingDays)
+		details["LifeUsedPercent"] = fmt.Sprintf("%.1f", endurance.LifeUsedPercent)
+		details["TerabytesWritten"] = fmt.Sprintf("%.2f", endurance.TerabytesWritten)
+	}
+
+	for _, trend := range normalizedData.Trends {
+		details[fmt.Sprintf("Increase_%s_%s", trend.Indicator, trend.Window)] = fmt.Sprintf("%d", trend.Increase)
+	}
+
+	if kernelIO := normalizedData.KernelIO; kernelIO != nil {
+		details["ReadLatencyMs"] = fmt.Sprintf("%.2f", kernelIO.ReadLatencyMs)
+		details["WriteLatencyMs"] = fmt.Sprintf("%.2f", kernelIO.WriteLatencyMs)
+		details["SCSIIOErrors"] = fmt.Sprintf("%d", kernelIO.SCSIIOErrors)
+		for errorType, count := range kernelIO.KernelErrors {
+			details["KernelErrors_"+errorType] = fmt.Sprintf("%d", count)
+		}
+	}
 
 	// Handle critical SMART metrics with thresholds
 	checkAndSetThresholds(&details, normalizedData, config, &severity, &eventType)
@@ -51,9 +80,15 @@ func convertToNatsEvent(normalizedData NormalizedSmartData, config *DiskHealthMe
 		}
 	}
 
+	location := locationOf(normalizedData)
 	return NatsEvent{
 		NodeName:   normalizedData.NodeName,
 		InstanceID: normalizedData.InstanceID,
+		Zone:       normalizedData.Zone,
+		Rack:       normalizedData.Rack,
+		Chassis:    location.Chassis,
+		Enclosure:  location.Enclosure,
+		Slot:       location.Slot,
 		Device:     normalizedData.Device,
 		EventType:  eventType,
 		Severity:   severity,
@@ -114,10 +149,25 @@ func checkAndSetThresholds(details *map[string]string, normalizedData Normalized
 		*severity = "critical"
 		*eventType = "lifetime_alert"
 	}
+
+	// Attributes at or below the threshold reported by the drive, a failed
+	// prefailure attribute predicts the failure of the drive
+	if failing, prefailure := failingAttributes(normalizedData.Thresholds); len(failing) > 0 {
+		(*details)["FailingAttributes"] = strings.Join(failing, ", ")
+		*eventType = "health_alert"
+		if prefailure {
+			*severity = "critical"
+		} else if *severity == "info" {
+			*severity = "warning"
+		}
+	}
 }
 
 // generateMessage generates a summary message based on the details.
 func generateMessage(details map[string]string) string {
+	if _, found := details["FailingAttributes"]; found {
+		return "SMART attributes at or below the failure threshold of the drive."
+	}
 	if _, found := details["GrownDefects"]; found {
 		return "SMART data indicates potential drive issues (grown defects)."
 	}
@@ -133,19 +183,153 @@ func generateMessage(details map[string]string) string {
 	return "SMART data collected successfully."
 }
 
+// publishRiskEvent emits a failure_risk event when a device's risk score
+// crossed the warning or critical threshold in either direction.
+func publishRiskEvent(metric NormalizedSmartData, nc *nats.Conn, subject string) error {
+	risk := metric.FailureRisk
+	severity := "info"
+	if risk.Level != RiskLevelOK {
+		severity = risk.Level
+	}
+
+	details := map[string]string{
+		"RiskScore":     fmt.Sprintf("%.2f", risk.Score),
+		"RiskTrend":     fmt.Sprintf("%.2f", risk.Trend),
+		"RiskLevel":     risk.Level,
+		"PreviousLevel": risk.PreviousLevel,
+	}
+	for name, value := range risk.Factors {
+		details["Factor_"+name] = fmt.Sprintf("%.3f", value)
+	}
+	if metric.OSDID != "" {
+		details["OSDID"] = metric.OSDID
+	}
+	if metric.CephCluster != "" {
+		details["CephCluster"] = metric.CephCluster
+	}
+
+	location := locationOf(metric)
+	return schema.Publish(nc, subject, schema.DiskEvent, NatsEvent{
+		NodeName:   metric.NodeName,
+		InstanceID: metric.InstanceID,
+		Zone:       metric.Zone,
+		Rack:       metric.Rack,
+		Chassis:    location.Chassis,
+		Enclosure:  location.Enclosure,
+		Slot:       location.Slot,
+		Device:     metric.Device,
+		EventType:  "failure_risk",
+		Severity:   severity,
+		Message:    riskEventMessage(risk),
+		Details:    details,
+	})
+}
+
+// publishTemperatureEvent emits a temperature event when a device's
+// temperature level changed.
+func publishTemperatureEvent(metric NormalizedSmartData, nc *nats.Conn, subject string) error {
+	alert := metric.TemperatureAlert
+	severity := "info"
+	if alert.Level != TemperatureLevelOK {
+		severity = alert.Level
+	}
+
+	details := map[string]string{
+		"TemperatureCelsius": fmt.Sprintf("%d", *metric.TemperatureCelsius),
+		"TemperatureLevel":   alert.Level,
+		"PreviousLevel":      alert.PreviousLevel,
+		"WarningCelsius":     fmt.Sprintf("%d", alert.WarningCelsius),
+		"CriticalCelsius":    fmt.Sprintf("%d", alert.CriticalCelsius),
+	}
+	if metric.DeviceInfo != nil {
+		details["Media"] = metric.DeviceInfo.Media
+	}
+	if metric.OSDID != "" {
+		details["OSDID"] = metric.OSDID
+	}
+	if metric.CephCluster != "" {
+		details["CephCluster"] = metric.CephCluster
+	}
+
+	location := locationOf(metric)
+	return schema.Publish(nc, subject, schema.DiskEvent, NatsEvent{
+		NodeName:   metric.NodeName,
+		InstanceID: metric.InstanceID,
+		Zone:       metric.Zone,
+		Rack:       metric.Rack,
+		Chassis:    location.Chassis,
+		Enclosure:  location.Enclosure,
+		Slot:       location.Slot,
+		Device:     metric.Device,
+		EventType:  "temperature",
+		Severity:   severity,
+		Message:    temperatureEventMessage(alert, *metric.TemperatureCelsius),
+		Details:    details,
+	})
+}
+
+// PublishChangeEvents publishes health changes since the previous collection
+// to the change-events subject.
+func PublishChangeEvents(metrics []NormalizedSmartData, nc *nats.Conn, subject string) error {
+	for _, event := range detectChangeEvents(metrics, time.Now()) {
+		if err := schema.Publish(nc, subject, schema.DiskChangeEvent, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func PublishToNATS(metrics []NormalizedSmartData, nc *nats.Conn, subject string, cfg *DiskHealthMetricsConfig) error {
 	for _, metric := range metrics {
 		event := convertToNatsEvent(metric, cfg)
 
-		eventJSON, err := json.Marshal(event)
-		if err != nil {
+		if err := schema.Publish(nc, subject, schema.DiskEvent, event); err != nil {
 			return err
 		}
 
-		if err := nc.Publish(subject, eventJSON); err != nil {
-			return err
+		if metric.FailureRisk != nil && metric.FailureRisk.LevelChanged() {
+			if err := publishRiskEvent(metric, nc, subject); err != nil {
+				return err
+			}
+		}
+
+		if metric.TemperatureAlert != nil && metric.TemperatureAlert.LevelChanged() {
+			if err := publishTemperatureEvent(metric, nc, subject); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
+
+// SubscribeReplacementRequests answers replacement requests on subject with
+// the recommendations of this node (see ReplacementRequest).
+func SubscribeReplacementRequests(nc *nats.Conn, subject string, advisor *replacementAdvisor) (*nats.Subscription, error) {
+	return nc.Subscribe(subject, func(msg *nats.Msg) {
+		response, ok := advisor.respond(msg.Data)
+		if !ok {
+			return
+		}
+		if err := msg.Respond(response); err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("error responding to replacement request")
+		}
+	})
+}
+
+// SubscribeLocateRequests switches the identification LEDs of the drives of
+// this node on requests on subject (see LocateRequest).
+func SubscribeLocateRequests(nc *nats.Conn, subject string, locator *driveLocator) (*nats.Subscription, error) {
+	return nc.Subscribe(subject, func(msg *nats.Msg) {
+		ctx, cancel := context.WithTimeout(context.Background(), locateTimeout)
+		defer cancel()
+		response, ok := locator.respond(ctx, msg.Data)
+		if !ok {
+			return
+		}
+		if err := msg.Respond(response); err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("error responding to locate request")
+		}
+	})
+}
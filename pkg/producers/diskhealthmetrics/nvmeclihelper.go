@@ -5,6 +5,7 @@
 package diskhealthmetrics
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
@@ -20,8 +21,8 @@ func checkNVMeCliInstalled() bool {
 }
 
 // collectNVMeControllerData collects NVMe controller data using nvme id-ctrl
-func collectNVMeControllerData(devicePath string) (*NVMeIDControllerOutput, error) {
-	out, err := exec.Command("nvme", "id-ctrl", devicePath, "-o", "json").Output()
+func collectNVMeControllerData(ctx context.Context, devicePath string) (*NVMeIDControllerOutput, error) {
+	out, err := exec.CommandContext(ctx, "nvme", "id-ctrl", devicePath, "-o", "json").Output()
 	if err != nil {
 		return nil, fmt.Errorf("error running nvme id-ctrl: %v", err)
 	}
@@ -35,8 +36,8 @@ func collectNVMeControllerData(devicePath string) (*NVMeIDControllerOutput, erro
 }
 
 // collectNVMeErrorLog collects NVMe error log using nvme error-log
-func collectNVMeErrorLog(devicePath string) (*NVMeErrorLogOutput, error) {
-	out, err := exec.Command("nvme", "error-log", devicePath, "-o", "json").Output()
+func collectNVMeErrorLog(ctx context.Context, devicePath string) (*NVMeErrorLogOutput, error) {
+	out, err := exec.CommandContext(ctx, "nvme", "error-log", devicePath, "-o", "json").Output()
 	if err != nil {
 		return nil, fmt.Errorf("error running nvme error-log: %v", err)
 	}
@@ -17,6 +17,7 @@ type NVMeIDControllerOutput struct {
 	IEEE                json.Number `json:"ieee"`
 	TotalCapacity       int64  `json:"tnvmcap"`
 	UnallocatedCapacity int64  `json:"unvmcap"`
+	EnduranceGroupIDMax int64  `json:"endgidmax"`
 }
 
 // NVMeErrorLogOutput represents the JSON output from nvme error-log -o json
@@ -39,3 +40,31 @@ type NVMeErrorEntry struct {
 	CommandSpecific           int64 `json:"cs"`
 	TransportTypeSpecificInfo int64 `json:"trtype_spec_info"`
 }
+
+// NVMeEnduranceLogOutput represents the JSON output from nvme endurance-log -o json.
+// 128-bit counters are emitted as numbers or strings depending on the nvme-cli version.
+type NVMeEnduranceLogOutput struct {
+	CriticalWarning         int64       `json:"critical_warning"`
+	AvailableSpare          int64       `json:"avl_spare"`
+	AvailableSpareThreshold int64       `json:"avl_spare_threshold"`
+	PercentUsed             int64       `json:"percent_used"`
+	EnduranceEstimate       json.Number `json:"endurance_estimate"`
+	DataUnitsRead           json.Number `json:"data_units_read"`
+	DataUnitsWritten        json.Number `json:"data_units_written"`
+	MediaUnitsWritten       json.Number `json:"media_units_written"`
+	MediaDataIntegrityErr   json.Number `json:"media_data_integrity_err"`
+}
+
+// NVMeSelfTestLogOutput represents the JSON output from nvme self-test-log -o json
+type NVMeSelfTestLogOutput struct {
+	CurrentOperation  int64               `json:"Current Device Self-Test Operation"`
+	CurrentCompletion int64               `json:"Current Device Self-Test Completion"`
+	Results           []NVMeSelfTestEntry `json:"List of Valid Reports"`
+}
+
+// NVMeSelfTestEntry represents a single self-test result from the self-test log
+type NVMeSelfTestEntry struct {
+	Result       int64 `json:"Self test result"`
+	Code         int64 `json:"Self test code"`
+	PowerOnHours int64 `json:"Power on hours"`
+}
@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxEnduranceGroups caps how many endurance group logs are read per device.
+// Drives with more than one endurance group are rare outside of ZNS/FDP setups.
+const maxEnduranceGroups = 8
+
+// PCI vendor IDs whose nvme-cli plugin exposes additional SMART data.
+const (
+	pciVendorIntel    = 0x8086
+	pciVendorSolidigm = 0x025E
+)
+
+// NVMeTelemetry holds NVMe log pages that smartctl does not expose, collected
+// with nvme-cli when --nvme-telemetry is enabled.
+type NVMeTelemetry struct {
+	EnduranceGroups []NVMeEnduranceGroup `json:"endurance_groups,omitempty"`
+	SelfTest        *NVMeSelfTestSummary `json:"self_test,omitempty"`
+	Vendor          map[string]int64     `json:"vendor,omitempty"` // Vendor plugin counters, keyed by normalized name
+}
+
+// NVMeEnduranceGroup is the normalized endurance group information log.
+type NVMeEnduranceGroup struct {
+	GroupID                 int64 `json:"group_id"`
+	CriticalWarning         int64 `json:"critical_warning"`
+	AvailableSpare          int64 `json:"available_spare"`
+	AvailableSpareThreshold int64 `json:"available_spare_threshold"`
+	PercentageUsed          int64 `json:"percentage_used"`
+	EnduranceEstimate       int64 `json:"endurance_estimate"`  // in data units (1000 * 512 bytes)
+	DataUnitsRead           int64 `json:"data_units_read"`     // in data units (1000 * 512 bytes)
+	DataUnitsWritten        int64 `json:"data_units_written"`  // in data units (1000 * 512 bytes)
+	MediaUnitsWritten       int64 `json:"media_units_written"` // in data units (1000 * 512 bytes)
+	MediaIntegrityErrors    int64 `json:"media_integrity_errors"`
+}
+
+// NVMeSelfTestSummary summarizes the device self-test log.
+type NVMeSelfTestSummary struct {
+	InProgress         bool   `json:"in_progress"`
+	CompletionPercent  int64  `json:"completion_percent"`
+	LastTestType       string `json:"last_test_type,omitempty"`
+	LastResult         int64  `json:"last_result"` // NVMe self-test result code, -1 if no test was recorded
+	LastResultText     string `json:"last_result_text,omitempty"`
+	LastPowerOnHours   int64  `json:"last_power_on_hours"`
+	FailedTestsInLog   int64  `json:"failed_tests_in_log"`
+	RecordedTestsInLog int64  `json:"recorded_tests_in_log"`
+}
+
+// collectNVMeTelemetry gathers endurance group, self-test and vendor logs for
+// an NVMe device. Each log page is optional: drives that do not support a
+// page are logged at debug level and the remaining pages are still collected.
+func collectNVMeTelemetry(ctx context.Context, devicePath string, controller *NVMeIDControllerOutput) *NVMeTelemetry {
+	telemetry := &NVMeTelemetry{}
+
+	if controller != nil && controller.EnduranceGroupIDMax > 0 {
+		groups := min(controller.EnduranceGroupIDMax, maxEnduranceGroups)
+		for groupID := int64(1); groupID <= groups; groupID++ {
+			group, err := collectNVMeEnduranceLog(ctx, devicePath, groupID)
+			if err != nil {
+				log.Debug().Err(err).Str("disk", devicePath).Int64("endurance_group", groupID).Msg("endurance group log not available")
+				continue
+			}
+			telemetry.EnduranceGroups = append(telemetry.EnduranceGroups, *group)
+		}
+	}
+
+	selfTest, err := collectNVMeSelfTestLog(ctx, devicePath)
+	if err != nil {
+		log.Debug().Err(err).Str("disk", devicePath).Msg("self-test log not available")
+	} else {
+		telemetry.SelfTest = summarizeNVMeSelfTestLog(selfTest)
+	}
+
+	if controller != nil && (controller.VendorID == pciVendorIntel || controller.VendorID == pciVendorSolidigm) {
+		vendor, err := collectNVMeIntelSmartLogAdd(ctx, devicePath)
+		if err != nil {
+			log.Debug().Err(err).Str("disk", devicePath).Msg("vendor SMART log not available")
+		} else {
+			telemetry.Vendor = vendor
+		}
+	}
+
+	if len(telemetry.EnduranceGroups) == 0 && telemetry.SelfTest == nil && len(telemetry.Vendor) == 0 {
+		return nil
+	}
+	return telemetry
+}
+
+// collectNVMeEnduranceLog collects an endurance group log using nvme endurance-log
+func collectNVMeEnduranceLog(ctx context.Context, devicePath string, groupID int64) (*NVMeEnduranceGroup, error) {
+	out, err := exec.CommandContext(ctx, "nvme", "endurance-log", devicePath, "--group-id", strconv.FormatInt(groupID, 10), "-o", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running nvme endurance-log: %v", err)
+	}
+
+	var raw NVMeEnduranceLogOutput
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing nvme endurance-log JSON: %v", err)
+	}
+
+	return &NVMeEnduranceGroup{
+		GroupID:                 groupID,
+		CriticalWarning:         raw.CriticalWarning,
+		AvailableSpare:          raw.AvailableSpare,
+		AvailableSpareThreshold: raw.AvailableSpareThreshold,
+		PercentageUsed:          raw.PercentUsed,
+		EnduranceEstimate:       jsonNumberToInt64(raw.EnduranceEstimate),
+		DataUnitsRead:           jsonNumberToInt64(raw.DataUnitsRead),
+		DataUnitsWritten:        jsonNumberToInt64(raw.DataUnitsWritten),
+		MediaUnitsWritten:       jsonNumberToInt64(raw.MediaUnitsWritten),
+		MediaIntegrityErrors:    jsonNumberToInt64(raw.MediaDataIntegrityErr),
+	}, nil
+}
+
+// collectNVMeSelfTestLog collects the device self-test log using nvme self-test-log
+func collectNVMeSelfTestLog(ctx context.Context, devicePath string) (*NVMeSelfTestLogOutput, error) {
+	out, err := exec.CommandContext(ctx, "nvme", "self-test-log", devicePath, "-o", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running nvme self-test-log: %v", err)
+	}
+
+	var selfTestLog NVMeSelfTestLogOutput
+	if err := json.Unmarshal(out, &selfTestLog); err != nil {
+		return nil, fmt.Errorf("error parsing nvme self-test-log JSON: %v", err)
+	}
+
+	return &selfTestLog, nil
+}
+
+// collectNVMeIntelSmartLogAdd collects the Intel/Solidigm additional SMART log
+// using the nvme-cli intel plugin and flattens it into named counters.
+func collectNVMeIntelSmartLogAdd(ctx context.Context, devicePath string) (map[string]int64, error) {
+	out, err := exec.CommandContext(ctx, "nvme", "intel", "smart-log-add", devicePath, "-o", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running nvme intel smart-log-add: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing nvme intel smart-log-add JSON: %v", err)
+	}
+
+	return flattenNVMeVendorLog(raw), nil
+}
+
+// nvmeSelfTestResults maps the NVMe self-test result code (low nibble) to text.
+var nvmeSelfTestResults = map[int64]string{
+	0x0: "completed",
+	0x1: "aborted_by_command",
+	0x2: "aborted_by_reset",
+	0x3: "aborted_by_namespace_removal",
+	0x4: "aborted_by_format",
+	0x5: "fatal_error",
+	0x6: "failed_unknown_segment",
+	0x7: "failed_segments",
+	0x8: "aborted_unknown",
+	0x9: "aborted_by_sanitize",
+}
+
+// nvmeSelfTestTypes maps the NVMe self-test code to the test type.
+var nvmeSelfTestTypes = map[int64]string{
+	0x1: "short",
+	0x2: "extended",
+	0xE: "vendor",
+}
+
+// nvmeSelfTestUnused marks an unused entry in the self-test log.
+const nvmeSelfTestUnused = 0xF
+
+// summarizeNVMeSelfTestLog reduces the self-test log to the most recent result
+// and the number of failed tests still present in the log.
+func summarizeNVMeSelfTestLog(selfTestLog *NVMeSelfTestLogOutput) *NVMeSelfTestSummary {
+	summary := &NVMeSelfTestSummary{
+		InProgress:        selfTestLog.CurrentOperation != 0,
+		CompletionPercent: selfTestLog.CurrentCompletion,
+		LastResult:        -1,
+	}
+
+	for _, entry := range selfTestLog.Results {
+		result := entry.Result & 0xF
+		if result == nvmeSelfTestUnused {
+			continue
+		}
+		summary.RecordedTestsInLog++
+		if result >= 0x5 && result <= 0x7 {
+			summary.FailedTestsInLog++
+		}
+		// The log is ordered newest first.
+		if summary.LastResult == -1 {
+			summary.LastResult = result
+			summary.LastResultText = nvmeSelfTestResults[result]
+			summary.LastTestType = nvmeSelfTestTypes[entry.Code&0xF]
+			summary.LastPowerOnHours = entry.PowerOnHours
+		}
+	}
+
+	return summary
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// flattenNVMeVendorLog converts a vendor plugin log into named counters. The
+// plugins report either plain numbers or objects with a "raw" value (and
+// sometimes "normalized"); nested objects such as the per-device wrapper are
+// walked recursively.
+func flattenNVMeVendorLog(raw map[string]any) map[string]int64 {
+	result := make(map[string]int64)
+	var walk func(map[string]any)
+	walk = func(obj map[string]any) {
+		for key, value := range obj {
+			name := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(key), "_"), "_")
+			switch v := value.(type) {
+			case float64:
+				result[name] = clampToInt64(v)
+			case map[string]any:
+				if rawValue, ok := v["raw"].(float64); ok {
+					result[name] = clampToInt64(rawValue)
+					continue
+				}
+				walk(v)
+			}
+		}
+	}
+	walk(raw)
+	return result
+}
+
+func jsonNumberToInt64(n json.Number) int64 {
+	if n == "" {
+		return 0
+	}
+	if v, err := n.Int64(); err == nil {
+		return v
+	}
+	if v, err := n.Float64(); err == nil {
+		return clampToInt64(v)
+	}
+	return 0
+}
+
+func clampToInt64(v float64) int64 {
+	if v >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if v <= math.MinInt64 {
+		return math.MinInt64
+	}
+	return int64(v)
+}
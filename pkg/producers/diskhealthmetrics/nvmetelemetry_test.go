@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeNVMeSelfTestLog(t *testing.T) {
+	raw := []byte(`{
+		"Current Device Self-Test Operation": 0,
+		"Current Device Self-Test Completion": 0,
+		"List of Valid Reports": [
+			{"Self test result": 7, "Self test code": 2, "Power on hours": 1200},
+			{"Self test result": 15, "Self test code": 0, "Power on hours": 0},
+			{"Self test result": 0, "Self test code": 1, "Power on hours": 1100},
+			{"Self test result": 5, "Self test code": 1, "Power on hours": 900}
+		]
+	}`)
+
+	var selfTestLog NVMeSelfTestLogOutput
+	require.NoError(t, json.Unmarshal(raw, &selfTestLog))
+
+	summary := summarizeNVMeSelfTestLog(&selfTestLog)
+	assert.False(t, summary.InProgress)
+	assert.Equal(t, int64(7), summary.LastResult)
+	assert.Equal(t, "failed_segments", summary.LastResultText)
+	assert.Equal(t, "extended", summary.LastTestType)
+	assert.Equal(t, int64(1200), summary.LastPowerOnHours)
+	assert.Equal(t, int64(3), summary.RecordedTestsInLog)
+	assert.Equal(t, int64(2), summary.FailedTestsInLog)
+}
+
+func TestSummarizeNVMeSelfTestLog_Empty(t *testing.T) {
+	summary := summarizeNVMeSelfTestLog(&NVMeSelfTestLogOutput{CurrentOperation: 1, CurrentCompletion: 42})
+	assert.True(t, summary.InProgress)
+	assert.Equal(t, int64(42), summary.CompletionPercent)
+	assert.Equal(t, int64(-1), summary.LastResult)
+}
+
+func TestFlattenNVMeVendorLog(t *testing.T) {
+	raw := []byte(`{
+		"Device stats for nvme0": {
+			"program_fail_count": {"normalized": 100, "raw": 3},
+			"nand_bytes_written": {"normalized": 100, "raw": 123456},
+			"Thermal Throttle Status": 0
+		}
+	}`)
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal(raw, &parsed))
+
+	counters := flattenNVMeVendorLog(parsed)
+	assert.Equal(t, map[string]int64{
+		"program_fail_count":      3,
+		"nand_bytes_written":      123456,
+		"thermal_throttle_status": 0,
+	}, counters)
+}
+
+func TestJSONNumberToInt64(t *testing.T) {
+	assert.Equal(t, int64(0), jsonNumberToInt64(""))
+	assert.Equal(t, int64(42), jsonNumberToInt64("42"))
+	assert.Equal(t, int64(1e18), jsonNumberToInt64("1e18"))
+	assert.Equal(t, int64(9223372036854775807), jsonNumberToInt64("340282366920938463463374607431768211455"))
+}
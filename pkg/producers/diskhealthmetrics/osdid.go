@@ -8,18 +8,27 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 
 	"github.com/rs/zerolog/log"
-	"golang.org/x/sys/unix"
 )
 
-// Cache for OSD mappings: physical device -> OSD ID
-var physicalDeviceToOSDCache = make(map[string]string)
+// cephOSD identifies the Ceph OSD backed by a physical device.
+type cephOSD struct {
+	ID          string
+	ClusterFSID string
+}
+
+// Cache for OSD mappings: physical device -> OSD
+var physicalDeviceToOSDCache = make(map[string]cephOSD)
 var cacheInitialized = false
 
+// osdMappingCacheMutex guards the cache, devices are collected concurrently.
+var osdMappingCacheMutex sync.Mutex
+
 // normalizeDevicePath ensures we always use the same canonical path
 func normalizeDevicePath(device string) string {
 	// Try to get canonical path
@@ -30,10 +39,12 @@ func normalizeDevicePath(device string) string {
 	return device
 }
 
-func getOSDIDForDisk(disk, basePath string) (string, error) {
+// getCephOSDForDisk returns the OSD (and its cluster fsid) that uses disk,
+// or an empty cephOSD if the disk is not an OSD device.
+func getCephOSDForDisk(disk, basePath string) (cephOSD, error) {
 	// Skip if no base path provided
 	if basePath == "" {
-		return "", nil
+		return cephOSD{}, nil
 	}
 
 	// For NVMe controller devices, discover namespace devices using glob
@@ -53,10 +64,13 @@ func getOSDIDForDisk(disk, basePath string) (string, error) {
 		}
 	}
 
+	osdMappingCacheMutex.Lock()
+	defer osdMappingCacheMutex.Unlock()
+
 	// Initialize the cache if not done yet
 	if err := initOSDMappingCache(basePath); err != nil {
 		log.Warn().Err(err).Msg("Failed to initialize OSD mapping cache")
-		return "", nil
+		return cephOSD{}, nil
 	}
 
 	// Try to find OSD ID for any of the actual disks
@@ -65,22 +79,22 @@ func getOSDIDForDisk(disk, basePath string) (string, error) {
 		normalizedDisk := normalizeDevicePath(actualDisk)
 
 		// Try direct lookup with normalized path
-		if osdID, found := physicalDeviceToOSDCache[normalizedDisk]; found {
-			log.Debug().Str("disk", actualDisk).Str("osd_id", osdID).Msg("Found OSD ID for disk")
-			return osdID, nil
+		if osd, found := physicalDeviceToOSDCache[normalizedDisk]; found {
+			log.Debug().Str("disk", actualDisk).Str("osd_id", osd.ID).Msg("Found OSD ID for disk")
+			return osd, nil
 		}
 
 		// Also try with the original path in case normalization changed it
 		if normalizedDisk != actualDisk {
-			if osdID, found := physicalDeviceToOSDCache[actualDisk]; found {
-				log.Debug().Str("disk", actualDisk).Str("osd_id", osdID).Msg("Found OSD ID for disk (original path)")
-				return osdID, nil
+			if osd, found := physicalDeviceToOSDCache[actualDisk]; found {
+				log.Debug().Str("disk", actualDisk).Str("osd_id", osd.ID).Msg("Found OSD ID for disk (original path)")
+				return osd, nil
 			}
 		}
 	}
 
 	log.Debug().Str("disk", disk).Msg("No OSD ID found for disk")
-	return "", nil
+	return cephOSD{}, nil
 }
 
 // resolveDeviceMapperSlaves recursively resolves dm-* devices to physical devices
@@ -128,50 +142,15 @@ func resolveDeviceMapperSlaves(dev string) ([]string, error) {
 	return devices, nil
 }
 
-// Get device mapper minor number using proper unix.Major/Minor functions
-func getMapperDeviceMinor(mapperDevice string) (int, error) {
-	var stat syscall.Stat_t
-	if err := syscall.Stat(mapperDevice, &stat); err != nil {
-		return 0, fmt.Errorf("failed to stat %s: %w", mapperDevice, err)
-	}
-
-	major := int(unix.Major(uint64(stat.Rdev)))
-	minor := int(unix.Minor(uint64(stat.Rdev)))
-
-	matches, err := filepath.Glob("/sys/block/dm-*")
-	if err != nil {
-		return 0, err
-	}
-
-	for _, dmPath := range matches {
-		devFile := filepath.Join(dmPath, "dev")
-		devBytes, err := os.ReadFile(devFile)
-		if err != nil {
-			continue
-		}
-
-		parts := strings.Split(strings.TrimSpace(string(devBytes)), ":")
-		if len(parts) != 2 {
-			continue
-		}
-
-		sysMajor, _ := strconv.Atoi(parts[0])
-		sysMinor, _ := strconv.Atoi(parts[1])
-
-		if sysMajor == major && sysMinor == minor {
-			dmName := filepath.Base(dmPath)
-			return strconv.Atoi(strings.TrimPrefix(dmName, "dm-"))
-		}
-	}
-
-	return 0, fmt.Errorf("could not find dm device for %s", mapperDevice)
-}
-
 func initOSDMappingCache(basePath string) error {
 	if cacheInitialized {
 		return nil
 	}
 
+	// ceph-volume knows about OSDs whose data directory is not mounted in
+	// this container (e.g. bare-metal deployments), so ask it first.
+	mappingCount := mapCephVolumeOSDs()
+
 	// Check if basePath exists
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		log.Debug().Str("base_path", basePath).Msg("Ceph OSD base path does not exist, skipping OSD mapping")
@@ -181,19 +160,27 @@ func initOSDMappingCache(basePath string) error {
 
 	log.Info().Str("base_path", basePath).Msg("Initializing OSD mapping cache")
 
-	pattern := filepath.Join(basePath, "*_*")
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return fmt.Errorf("failed to glob pattern %s: %w", pattern, err)
+	// Rook names OSD directories <fsid>_<osd-uuid>, while ceph-volume and
+	// cephadm hosts use /var/lib/ceph/osd/<cluster>-<id>.
+	var matches []string
+	for _, pattern := range []string{filepath.Join(basePath, "*_*"), filepath.Join(basePath, "*-*")} {
+		found, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("failed to glob pattern %s: %w", pattern, err)
+		}
+		for _, match := range found {
+			if !slices.Contains(matches, match) {
+				matches = append(matches, match)
+			}
+		}
 	}
 
 	if len(matches) == 0 {
-		log.Debug().Str("pattern", pattern).Msg("No OSD directories found")
+		log.Debug().Str("base_path", basePath).Msg("No OSD directories found")
 		cacheInitialized = true
 		return nil
 	}
 
-	mappingCount := 0
 	for _, dirPath := range matches {
 		if stat, err := os.Stat(dirPath); err != nil || !stat.IsDir() {
 			continue
@@ -215,7 +202,10 @@ func initOSDMappingCache(basePath string) error {
 			continue
 		}
 
-		osdID := strings.TrimSpace(string(osdIDBytes))
+		osd := cephOSD{ID: strings.TrimSpace(string(osdIDBytes))}
+		if fsidBytes, err := os.ReadFile(filepath.Join(dirPath, "ceph_fsid")); err == nil {
+			osd.ClusterFSID = strings.TrimSpace(string(fsidBytes))
+		}
 
 		// Handle device mapper devices
 		if strings.HasPrefix(blockDevice, "/dev/mapper/") {
@@ -240,26 +230,26 @@ func initOSDMappingCache(basePath string) error {
 				normalizedDevice := normalizeDevicePath(physicalDevice)
 
 				// Store both original and normalized paths to be safe
-				physicalDeviceToOSDCache[physicalDevice] = osdID
+				physicalDeviceToOSDCache[physicalDevice] = osd
 				if normalizedDevice != physicalDevice {
-					physicalDeviceToOSDCache[normalizedDevice] = osdID
+					physicalDeviceToOSDCache[normalizedDevice] = osd
 				}
 
 				mappingCount++
-				log.Debug().Str("physical_device", physicalDevice).Str("osd_id", osdID).Msg("Mapped physical device to OSD ID")
+				log.Debug().Str("physical_device", physicalDevice).Str("osd_id", osd.ID).Msg("Mapped physical device to OSD ID")
 			}
 		} else {
 			// Direct device mapping (with normalization)
 			normalizedDevice := normalizeDevicePath(blockDevice)
 
 			// Store both original and normalized paths to be safe
-			physicalDeviceToOSDCache[blockDevice] = osdID
+			physicalDeviceToOSDCache[blockDevice] = osd
 			if normalizedDevice != blockDevice {
-				physicalDeviceToOSDCache[normalizedDevice] = osdID
+				physicalDeviceToOSDCache[normalizedDevice] = osd
 			}
 
 			mappingCount++
-			log.Debug().Str("device", blockDevice).Str("osd_id", osdID).Msg("Mapped direct device to OSD ID")
+			log.Debug().Str("device", blockDevice).Str("osd_id", osd.ID).Msg("Mapped direct device to OSD ID")
 		}
 	}
 
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Get device mapper minor number using proper unix.Major/Minor functions
+func getMapperDeviceMinor(mapperDevice string) (int, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(mapperDevice, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", mapperDevice, err)
+	}
+
+	major := int(unix.Major(uint64(stat.Rdev)))
+	minor := int(unix.Minor(uint64(stat.Rdev)))
+
+	matches, err := filepath.Glob("/sys/block/dm-*")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dmPath := range matches {
+		devFile := filepath.Join(dmPath, "dev")
+		devBytes, err := os.ReadFile(devFile)
+		if err != nil {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimSpace(string(devBytes)), ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		sysMajor, _ := strconv.Atoi(parts[0])
+		sysMinor, _ := strconv.Atoi(parts[1])
+
+		if sysMajor == major && sysMinor == minor {
+			dmName := filepath.Base(dmPath)
+			return strconv.Atoi(strings.TrimPrefix(dmName, "dm-"))
+		}
+	}
+
+	return 0, fmt.Errorf("could not find dm device for %s", mapperDevice)
+}
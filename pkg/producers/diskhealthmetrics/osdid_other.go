@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package diskhealthmetrics
+
+import "fmt"
+
+// getMapperDeviceMinor fails, device mapper only exists on Linux.
+func getMapperDeviceMinor(mapperDevice string) (int, error) {
+	return 0, fmt.Errorf("device mapper is not supported on this platform: %s", mapperDevice)
+}
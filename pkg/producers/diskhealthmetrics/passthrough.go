@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PassthroughDevice is a drive that smartctl can only reach with an explicit
+// device type, typically a drive behind a RAID controller
+// (smartctl -d megaraid,3 /dev/bus/0).
+type PassthroughDevice struct {
+	Device string `json:"device"` // e.g. /dev/bus/0, /dev/sg0
+	Type   string `json:"type"`   // smartctl --device type, e.g. megaraid,3, cciss,0, sat
+}
+
+type passthroughFile struct {
+	Devices []PassthroughDevice `json:"devices"`
+}
+
+func comparePassthroughDevices(a, b PassthroughDevice) int {
+	if c := strings.Compare(a.Device, b.Device); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Type, b.Type)
+}
+
+// needsDeviceType reports whether smartctl must be called with --device for
+// a device type reported by smartctl --scan-open. Plain ATA, SCSI and NVMe
+// devices are detected automatically.
+func needsDeviceType(deviceType string) bool {
+	switch deviceType {
+	case "", "auto", "ata", "scsi", "nvme":
+		return false
+	default:
+		return true
+	}
+}
+
+// loadPassthroughDevices reads a JSON file of passthrough devices.
+func loadPassthroughDevices(path string) ([]PassthroughDevice, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passthrough devices %s: %w", path, err)
+	}
+
+	var file passthroughFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse passthrough devices %s: %w", path, err)
+	}
+
+	for _, device := range file.Devices {
+		if device.Device == "" || device.Type == "" {
+			return nil, fmt.Errorf("passthrough device entries need both device and type: %+v", device)
+		}
+	}
+
+	return file.Devices, nil
+}
+
+// raidCLIs are the Broadcom/Dell MegaRAID management tools, in order of
+// preference. perccli is storcli rebranded and has the same output.
+var raidCLIs = []string{"storcli64", "storcli", "perccli64", "perccli"}
+
+// storcliOutput represents the JSON output from storcli /call/eall/sall show J
+type storcliOutput struct {
+	Controllers []struct {
+		CommandStatus struct {
+			Controller int64  `json:"Controller"`
+			Status     string `json:"Status"`
+		} `json:"Command Status"`
+		ResponseData struct {
+			DriveInformation []struct {
+				EIDSlot string `json:"EID:Slt"`
+				DID     int64  `json:"DID"`
+				State   string `json:"State"`
+			} `json:"Drive Information"`
+		} `json:"Response Data"`
+	} `json:"Controllers"`
+}
+
+// discoverRAIDDevices lists the physical drives behind MegaRAID controllers
+// using storcli or perccli. It returns nil if neither tool is installed.
+func discoverRAIDDevices() ([]PassthroughDevice, error) {
+	for _, cli := range raidCLIs {
+		if _, err := exec.LookPath(cli); err != nil {
+			continue
+		}
+
+		out, err := exec.Command(cli, "/call/eall/sall", "show", "J").Output()
+		if err != nil {
+			return nil, fmt.Errorf("error running %s: %v", cli, err)
+		}
+
+		devices, err := parseStorcliDrives(out)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info().Str("tool", cli).Int("drives", len(devices)).Msg("discovered drives behind RAID controllers")
+		return devices, nil
+	}
+
+	log.Debug().Strs("tools", raidCLIs).Msg("no RAID controller CLI found, skipping RAID discovery")
+	return nil, nil
+}
+
+// parseStorcliDrives maps every drive to smartctl -d megaraid,<DID> on the
+// controller's /dev/bus/<controller> device.
+func parseStorcliDrives(out []byte) ([]PassthroughDevice, error) {
+	var output storcliOutput
+	if err := json.Unmarshal(out, &output); err != nil {
+		return nil, fmt.Errorf("error parsing storcli JSON: %v", err)
+	}
+
+	var devices []PassthroughDevice
+	for _, controller := range output.Controllers {
+		for _, drive := range controller.ResponseData.DriveInformation {
+			devices = append(devices, PassthroughDevice{
+				Device: fmt.Sprintf("/dev/bus/%d", controller.CommandStatus.Controller),
+				Type:   fmt.Sprintf("megaraid,%d", drive.DID),
+			})
+		}
+	}
+	return devices, nil
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorcliDrives(t *testing.T) {
+	out := []byte(`{
+		"Controllers": [
+			{
+				"Command Status": {"Controller": 0, "Status": "Success"},
+				"Response Data": {"Drive Information": [
+					{"EID:Slt": "252:0", "DID": 8, "State": "Onln"},
+					{"EID:Slt": "252:1", "DID": 9, "State": "JBOD"}
+				]}
+			},
+			{
+				"Command Status": {"Controller": 1, "Status": "Success"},
+				"Response Data": {"Drive Information": [
+					{"EID:Slt": "64:3", "DID": 3, "State": "UGood"}
+				]}
+			}
+		]
+	}`)
+
+	devices, err := parseStorcliDrives(out)
+	require.NoError(t, err)
+	assert.Equal(t, []PassthroughDevice{
+		{Device: "/dev/bus/0", Type: "megaraid,8"},
+		{Device: "/dev/bus/0", Type: "megaraid,9"},
+		{Device: "/dev/bus/1", Type: "megaraid,3"},
+	}, devices)
+}
+
+func TestLoadPassthroughDevices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passthrough.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"devices": [{"device": "/dev/sg0", "type": "cciss,1"}]}`), 0o644))
+
+	devices, err := loadPassthroughDevices(path)
+	require.NoError(t, err)
+	assert.Equal(t, []PassthroughDevice{{Device: "/dev/sg0", Type: "cciss,1"}}, devices)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"devices": [{"device": "/dev/sg0"}]}`), 0o644))
+	_, err = loadPassthroughDevices(path)
+	assert.Error(t, err)
+}
+
+func TestNeedsDeviceType(t *testing.T) {
+	assert.False(t, needsDeviceType("nvme"))
+	assert.False(t, needsDeviceType("scsi"))
+	assert.True(t, needsDeviceType("megaraid,3"))
+	assert.True(t, needsDeviceType("sat"))
+}
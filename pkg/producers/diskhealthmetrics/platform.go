@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+)
+
+// errHotplugUnsupported is returned by watchHotplug on platforms without a
+// device event source.
+var errHotplugUnsupported = errors.New("hot-plug events are not supported on this platform")
+
+// platform is what the producer needs from the operating system of the
+// node. Linux supports everything; FreeBSD runs smartctl and follows devd
+// for hot-plugged disks, but has no /sys/block and /dev/kmsg for the kernel
+// I/O correlation, nor device mapper for the Ceph OSD mapping of LVM
+// volumes. Each operating system provides its newHostPlatform.
+type platform interface {
+	// name identifies the platform in logs.
+	name() string
+
+	// runSmartctl runs smartctl with args and returns its output.
+	runSmartctl(ctx context.Context, args ...string) ([]byte, error)
+
+	// scannedDevices returns the devices of smartctl --scan-open to monitor,
+	// dropping the ones that are not disks and the device types smartctl
+	// picks by itself.
+	scannedDevices(devices []SmartCtlDevice) []SmartCtlDevice
+
+	// watchHotplug sends the disks added or removed on the node to events
+	// until ctx is canceled.
+	watchHotplug(ctx context.Context, events chan<- DeviceEvent) error
+
+	// kernelIO reports whether the kernel I/O statistics of the devices can
+	// be read.
+	kernelIO() bool
+}
+
+// hostPlatform is the platform the producer runs on.
+var hostPlatform = newHostPlatform()
+
+// smartctlRetry retries smartctl while it can't open the device, e.g. while
+// another process holds it.
+var smartctlRetry = retry.Policy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 4 * time.Second, Jitter: 0.2}
+
+// smartctlOpenFailed is the bit of the smartctl exit status set when the
+// device could not be opened or is in a low-power mode.
+const smartctlOpenFailed = 1 << 1
+
+// execSmartctl runs the smartctl found in PATH, retrying it while the device
+// can't be opened.
+func execSmartctl(ctx context.Context, args ...string) ([]byte, error) {
+	return retry.DoValue(ctx, "smartctl", smartctlRetry, func(ctx context.Context) ([]byte, error) {
+		out, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+		if err != nil && !smartctlRetryable(err, out) {
+			return out, retry.Permanent(err)
+		}
+		return out, err
+	})
+}
+
+// smartctlRetryable tells if smartctl failed to open the device. A disk
+// skipped in a low-power mode by --nocheck sets the same bit, but staying
+// asleep is what was asked for.
+func smartctlRetryable(err error, out []byte) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 || exitErr.ExitCode()&smartctlOpenFailed == 0 {
+		return false
+	}
+	return !bytes.Contains(out, []byte("Device is in "))
+}
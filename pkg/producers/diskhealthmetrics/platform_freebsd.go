@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build freebsd
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// devdSocketPath is where devd publishes the device events.
+const devdSocketPath = "/var/run/devd.seqpacket.pipe"
+
+type freebsdPlatform struct{}
+
+func newHostPlatform() platform {
+	return freebsdPlatform{}
+}
+
+func (freebsdPlatform) name() string {
+	return "freebsd"
+}
+
+func (freebsdPlatform) runSmartctl(ctx context.Context, args ...string) ([]byte, error) {
+	return execSmartctl(ctx, args...)
+}
+
+// scannedDevices drops the CAM passthrough and optical devices and lets
+// smartctl pick atacam itself, so SATA disks are monitored as /dev/adaN
+// like any other disk instead of as passthrough devices.
+func (freebsdPlatform) scannedDevices(devices []SmartCtlDevice) []SmartCtlDevice {
+	var disks []SmartCtlDevice
+	for _, device := range devices {
+		if strings.HasPrefix(device.Name, "/dev/pass") || strings.HasPrefix(device.Name, "/dev/cd") {
+			continue
+		}
+		if device.Type == "atacam" {
+			device.Type = ""
+		}
+		disks = append(disks, device)
+	}
+	return disks
+}
+
+// kernelIO is false, FreeBSD has neither /sys/block nor /dev/kmsg.
+func (freebsdPlatform) kernelIO() bool {
+	return false
+}
+
+// watchHotplug follows the device nodes devd reports being created or
+// destroyed, which requires the devd socket when running in a jail.
+func (freebsdPlatform) watchHotplug(ctx context.Context, events chan<- DeviceEvent) error {
+	conn, err := net.Dial("unixpacket", devdSocketPath)
+	if err != nil {
+		return fmt.Errorf("failed to connect to devd: %w", err)
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 8192)
+	for ctx.Err() == nil {
+		// Wake up regularly to notice cancellation.
+		if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			return fmt.Errorf("failed to set devd read deadline: %w", err)
+		}
+		n, err := conn.Read(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read devd event: %w", err)
+		}
+
+		event, ok := parseDevdEvent(buf[:n])
+		if !ok {
+			continue
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	return nil
+}
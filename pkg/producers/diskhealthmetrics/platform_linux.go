@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/unix"
+)
+
+type linuxPlatform struct{}
+
+func newHostPlatform() platform {
+	return linuxPlatform{}
+}
+
+func (linuxPlatform) name() string {
+	return "linux"
+}
+
+func (linuxPlatform) runSmartctl(ctx context.Context, args ...string) ([]byte, error) {
+	return execSmartctl(ctx, args...)
+}
+
+// scannedDevices keeps every device, smartctl only reports disks on Linux.
+func (linuxPlatform) scannedDevices(devices []SmartCtlDevice) []SmartCtlDevice {
+	return devices
+}
+
+func (linuxPlatform) kernelIO() bool {
+	return true
+}
+
+// watchHotplug listens to the kernel uevents on a netlink socket, which
+// requires the host network namespace when running in a container.
+func (linuxPlatform) watchHotplug(ctx context.Context, events chan<- DeviceEvent) error {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return fmt.Errorf("failed to open uevent socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1}); err != nil {
+		return fmt.Errorf("failed to subscribe to kernel uevents: %w", err)
+	}
+
+	// Wake up regularly to notice cancellation.
+	timeout := unix.NsecToTimeval(time.Second.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return fmt.Errorf("failed to set uevent socket timeout: %w", err)
+	}
+
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if errors.Is(err, unix.ENOBUFS) {
+			log.Warn().Msg("kernel uevents were dropped, hot-plugged devices are picked up by the next scan")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read kernel uevent: %w", err)
+		}
+
+		event, ok := parseUevent(buf[:n])
+		if !ok {
+			continue
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux && !freebsd
+
+package diskhealthmetrics
+
+import "context"
+
+// genericPlatform runs smartctl on the devices it finds and nothing else,
+// enough for development and for reading canned outputs in mock mode.
+type genericPlatform struct{}
+
+func newHostPlatform() platform {
+	return genericPlatform{}
+}
+
+func (genericPlatform) name() string {
+	return "generic"
+}
+
+func (genericPlatform) runSmartctl(ctx context.Context, args ...string) ([]byte, error) {
+	return execSmartctl(ctx, args...)
+}
+
+func (genericPlatform) scannedDevices(devices []SmartCtlDevice) []SmartCtlDevice {
+	return devices
+}
+
+func (genericPlatform) kernelIO() bool {
+	return false
+}
+
+func (genericPlatform) watchHotplug(ctx context.Context, events chan<- DeviceEvent) error {
+	return errHotplugUnsupported
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// scanProbe backs the liveness and readiness probes with the progress of the
+// scan loop.
+type scanProbe struct {
+	mu         sync.Mutex
+	started    time.Time
+	lastScan   time.Time
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// newScanProbe returns a probe that reports the producer dead if no scan
+// completed for three intervals plus the device timeout.
+func newScanProbe(cfg DiskHealthMetricsConfig) *scanProbe {
+	return &scanProbe{
+		started:    time.Now(),
+		staleAfter: 3 * time.Duration(cfg.Interval+cfg.DeviceTimeout) * time.Second,
+		now:        time.Now,
+	}
+}
+
+// scanned records a completed scan.
+func (p *scanProbe) scanned() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastScan = p.now()
+}
+
+// live reports whether the scan loop is making progress. Before the first
+// scan the time since the start counts.
+func (p *scanProbe) live() (bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last := p.lastScan
+	if last.IsZero() {
+		last = p.started
+	}
+	if age := p.now().Sub(last); age > p.staleAfter {
+		return false, fmt.Sprintf("no scan completed for %s", age.Round(time.Second))
+	}
+	return true, "ok"
+}
+
+// ready reports whether a scan completed, so metrics are available.
+func (p *scanProbe) ready() (bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lastScan.IsZero() {
+		return false, "first scan has not completed"
+	}
+	return true, "ok"
+}
+
+func probeHandler(check func() (bool, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ok, message := check()
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprintln(w, message)
+	}
+}
+
+// registerProbes serves the liveness probe on /healthz and the readiness
+// probe on /readyz.
+func registerProbes(mux *http.ServeMux, probe *scanProbe) {
+	mux.Handle("/healthz", probeHandler(probe.live))
+	mux.Handle("/readyz", probeHandler(probe.ready))
+}
+
+// startProbeServer serves the probes and the replacement recommendations on
+// their own port, for deployments without the Prometheus endpoint.
+func startProbeServer(port int, probe *scanProbe, advisor *replacementAdvisor) {
+	mux := http.NewServeMux()
+	registerProbes(mux, probe)
+	mux.Handle("/replacements", advisor)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Msgf("starting probe server on :%d", port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("error starting probe server")
+		}
+	}()
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanProbe(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	probe := newScanProbe(DiskHealthMetricsConfig{Interval: 60, DeviceTimeout: 60})
+	probe.started = now
+	probe.now = func() time.Time { return now }
+
+	mux := http.NewServeMux()
+	registerProbes(mux, probe)
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// Starting up: alive, but not ready before the first scan.
+	assert.Equal(t, http.StatusOK, status("/healthz"))
+	assert.Equal(t, http.StatusServiceUnavailable, status("/readyz"))
+
+	now = now.Add(time.Minute)
+	probe.scanned()
+	assert.Equal(t, http.StatusOK, status("/healthz"))
+	assert.Equal(t, http.StatusOK, status("/readyz"))
+
+	// The scan loop is stuck for longer than three intervals plus timeout.
+	now = now.Add(7 * time.Minute)
+	assert.Equal(t, http.StatusServiceUnavailable, status("/healthz"))
+	assert.Equal(t, http.StatusOK, status("/readyz"))
+}
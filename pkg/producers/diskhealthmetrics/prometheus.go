@@ -7,12 +7,14 @@ package diskhealthmetrics
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -21,7 +23,7 @@ var (
 			Name: "smart_attributes",
 			Help: "SMART attributes of the disk",
 		},
-		[]string{"disk", "attribute", "node", "instance", "osd_id"},
+		[]string{"disk", "attribute", "node", "instance", "osd_id", "ceph_cluster"},
 	)
 
 	temperatureGauge = prometheus.NewGaugeVec(
@@ -29,7 +31,15 @@ var (
 			Name: "disk_temperature_celsius",
 			Help: "Disk temperature in Celsius",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	temperatureAlertGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_temperature_alert_level",
+			Help: "Temperature threshold state of the disk for its media type: 0 = ok, 1 = warning, 2 = critical",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "media_type"},
 	)
 
 	reallocatedSectorsGauge = prometheus.NewGaugeVec(
@@ -37,7 +47,7 @@ var (
 			Name: "disk_reallocated_sectors",
 			Help: "Number of reallocated sectors",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
 	)
 
 	pendingSectorsGauge = prometheus.NewGaugeVec(
@@ -45,7 +55,7 @@ var (
 			Name: "disk_pending_sectors",
 			Help: "Number of pending sectors",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
 	)
 
 	// Counter for cumulative power-on hours
@@ -54,7 +64,7 @@ var (
 			Name: "disk_power_on_hours_total",
 			Help: "Total number of hours the disk has been powered on",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
 	)
 
 	ssdLifeUsedGauge = prometheus.NewGaugeVec(
@@ -62,7 +72,24 @@ var (
 			Name: "ssd_life_used_percentage",
 			Help: "Percentage of SSD life used",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	scsiGrownDefectsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_scsi_grown_defects",
+			Help: "Number of entries in the grown defect list of the SCSI disk",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	// Counter fed with the deltas of the SCSI error counter log
+	scsiErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "disk_scsi_errors_total",
+			Help: "Errors in the error counter log of the SCSI disk by operation (read, write, verify) and error_type (corrected, uncorrected)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "op", "error_type"},
 	)
 
 	// Counter for cumulative error counts
@@ -71,7 +98,47 @@ var (
 			Name: "disk_error_counts_total",
 			Help: "Total error counts for the disk",
 		},
-		[]string{"disk", "node", "instance", "error_type", "osd_id"},
+		[]string{"disk", "node", "instance", "error_type", "osd_id", "ceph_cluster"},
+	)
+
+	collectionErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "disk_collection_errors_total",
+			Help: "Failed SMART collections of the disk by reason (error, timeout, panic, busy)",
+		},
+		[]string{"disk", "device_type", "node", "instance", "reason"},
+	)
+
+	collectionDurationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_collection_duration_seconds",
+			Help: "Duration of the last SMART collection of the disk",
+		},
+		[]string{"disk", "device_type", "node", "instance"},
+	)
+
+	scanDurationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_scan_duration_seconds",
+			Help: "Duration of the last scan of all disks of the node",
+		},
+		[]string{"node", "instance"},
+	)
+
+	hotplugEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "disk_hotplug_events_total",
+			Help: "Disks added to or removed from the node by action (add, remove)",
+		},
+		[]string{"node", "instance", "action"},
+	)
+
+	locateRequestsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "disk_locate_requests_total",
+			Help: "Requests to switch the identification LED of a disk of the node by action (on, off) and result (success, error)",
+		},
+		[]string{"node", "instance", "action", "result"},
 	)
 
 	diskCapacityGauge = prometheus.NewGaugeVec(
@@ -79,7 +146,7 @@ var (
 			Name: "disk_capacity_gb",
 			Help: "Capacity of the disk in GB",
 		},
-		[]string{"disk", "node", "instance", "osd_id"},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
 	)
 
 	// Info metric for device information
@@ -89,11 +156,283 @@ var (
 			Help: "Static information about the disk device",
 		},
 		[]string{
-			"disk", "node", "instance", "osd_id",
+			"disk", "node", "instance", "osd_id", "ceph_cluster",
 			"vendor", "vendor_id", "subsystem_vendor_id", "model", "serial_number", "firmware_version",
 			"product", "model_family", "capacity_gb", "media_type",
-			"form_factor", "rpm", "dwpd",
+			"form_factor", "rpm", "dwpd", "chassis", "enclosure", "slot",
+		},
+	)
+
+	failureRiskScoreGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_failure_risk_score",
+			Help: "Combined disk failure-risk score from 0 (healthy) to 100 (failure imminent)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	failureRiskTrendGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_failure_risk_trend",
+			Help: "Change of the disk failure-risk score over the last 24 hours",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	failureRiskFactorGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_failure_risk_factor",
+			Help: "Contribution (0-1) of a single SMART indicator to the failure-risk score",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "factor"},
+	)
+
+	remainingLifeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_remaining_life_days",
+			Help: "Projected remaining write endurance of the SSD in days at its average write rate so far",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	firmwareCompliantGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_firmware_compliant",
+			Help: "Whether the firmware of the disk is approved for its model (1) or not (0), only for models with a firmware policy",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	attributeBelowThresholdGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_smart_attribute_below_threshold",
+			Help: "Whether the normalized value of the ATA SMART attribute is at or below the threshold reported by the drive (1) or not (0)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "attribute_id", "attribute", "prefailure"},
+	)
+
+	attributeThresholdMarginGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_smart_attribute_threshold_margin",
+			Help: "Normalized value of the ATA SMART attribute minus the threshold reported by the drive, failed at 0 or below",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "attribute_id", "attribute", "prefailure"},
+	)
+
+	attributeWhenFailedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_smart_attribute_when_failed",
+			Help: "When the ATA SMART attribute was at or below its threshold (never, past, now), 1 for the current state",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "attribute_id", "attribute", "when_failed"},
+	)
+
+	remainingLifeDevicesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_remaining_life_devices",
+			Help: "Number of SSDs of the node with a projected remaining life of at most le days, sum by (le) for the fleet histogram",
+		},
+		[]string{"node", "instance", "le"},
+	)
+
+	nodeTemperatureMaxGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_node_temperature_max_celsius",
+			Help: "Temperature of the hottest disk of the node",
+		},
+		[]string{"node", "instance"},
+	)
+
+	nodeTemperatureAvgGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_node_temperature_avg_celsius",
+			Help: "Mean temperature of the disks of the node",
+		},
+		[]string{"node", "instance"},
+	)
+
+	nodeTemperatureOverWarningGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_node_temperature_over_warning_devices",
+			Help: "Number of disks of the node at the warning or critical temperature level",
+		},
+		[]string{"node", "instance"},
+	)
+
+	enclosureTemperatureMaxGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_enclosure_temperature_max_celsius",
+			Help: "Temperature of the hottest disk of the enclosure",
+		},
+		[]string{"node", "instance", "chassis", "enclosure"},
+	)
+
+	enclosureTemperatureAvgGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_enclosure_temperature_avg_celsius",
+			Help: "Mean temperature of the disks of the enclosure",
+		},
+		[]string{"node", "instance", "chassis", "enclosure"},
+	)
+
+	indicatorIncreaseGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_health_indicator_increase",
+			Help: "Increase of a SMART health indicator (grown defects, pending sectors, media errors, wear level) over the window",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "indicator", "window"},
+	)
+
+	ioErrorsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_io_errors",
+			Help: "I/O errors of the disk as seen by the drive (source=smart) and by the kernel (source=kernel)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "source", "error_type"},
+	)
+
+	ioLatencyGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_io_latency_ms",
+			Help: "Average I/O latency of the disk since the previous collection from /sys/block/<dev>/stat",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "op"},
+	)
+
+	ioInFlightGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_io_in_flight",
+			Help: "I/O requests of the disk currently in flight",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	pathCountGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_path_count",
+			Help: "Number of paths (multipath members or SAS ports) to the disk",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "multipath_device"},
+	)
+
+	pathUpGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_path_up",
+			Help: "Whether a path to the disk is in the running state (1) or not (0)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "path", "state"},
+	)
+
+	pathIOErrorsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_path_io_errors",
+			Help: "I/O errors reported by the SCSI layer for a path to the disk",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "path"},
+	)
+
+	selfTestInProgressGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_self_test_in_progress",
+			Help: "Whether a SMART self-test is currently running (1) or not (0)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	selfTestRemainingGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_self_test_remaining_percent",
+			Help: "Remaining work of the running SMART self-test in percent",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	selfTestLastPassedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_self_test_last_passed",
+			Help: "Whether the most recent SMART self-test of this type passed (1) or failed (0)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "test_type"},
+	)
+
+	selfTestLastAgeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_self_test_last_age_hours",
+			Help: "Power-on hours since the most recent SMART self-test of this type",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "test_type"},
+	)
+
+	nvmeEnduranceGroupPercentUsedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_endurance_group_percentage_used",
+			Help: "Estimated percentage of life used for the NVMe endurance group",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "endurance_group"},
+	)
+
+	nvmeEnduranceGroupAvailableSpareGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_endurance_group_available_spare",
+			Help: "Available spare capacity of the NVMe endurance group in percent",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "endurance_group"},
+	)
+
+	nvmeEnduranceGroupDataUnitsWrittenGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_endurance_group_data_units_written",
+			Help: "Host data units (1000 * 512 bytes) written to the NVMe endurance group",
 		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "endurance_group"},
+	)
+
+	nvmeEnduranceGroupMediaUnitsWrittenGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_endurance_group_media_units_written",
+			Help: "Media data units (1000 * 512 bytes) written to the NVMe endurance group",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "endurance_group"},
+	)
+
+	nvmeSelfTestInProgressGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_self_test_in_progress",
+			Help: "Whether an NVMe device self-test is currently running (1) or not (0)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	nvmeSelfTestCompletionGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_self_test_completion_percent",
+			Help: "Completion percentage of the running NVMe device self-test",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	nvmeSelfTestLastResultGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_self_test_last_result",
+			Help: "Result code of the most recent NVMe device self-test (0 = passed)",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "test_type"},
+	)
+
+	nvmeSelfTestFailedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_self_test_failed_count",
+			Help: "Number of failed NVMe device self-tests in the self-test log",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster"},
+	)
+
+	nvmeVendorTelemetryGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "disk_nvme_vendor_telemetry",
+			Help: "Vendor-specific NVMe SMART counters reported by nvme-cli plugins",
+		},
+		[]string{"disk", "node", "instance", "osd_id", "ceph_cluster", "attribute"},
 	)
 
 	// State management for counters
@@ -104,38 +443,84 @@ var (
 type previousMetricState struct {
 	PowerOnHours int64
 	ErrorCounts  map[string]int64
+	SCSIErrors   map[string]int64 // by op and error_type
 }
 
-func init() {
-	// Register all metrics with Prometheus's default registry
-	prometheus.MustRegister(smartAttributesGaugeVec)
-	prometheus.MustRegister(temperatureGauge)
-	prometheus.MustRegister(reallocatedSectorsGauge)
-	prometheus.MustRegister(pendingSectorsGauge)
-	prometheus.MustRegister(powerOnHoursCounter)
-	prometheus.MustRegister(ssdLifeUsedGauge)
-	prometheus.MustRegister(errorCountsCounter)
-	prometheus.MustRegister(diskCapacityGauge)
-	prometheus.MustRegister(diskInfoGauge) // Add this line
+// registerMetrics registers all metrics with registerer.
+func registerMetrics(registerer prometheus.Registerer) {
+	registerer.MustRegister(smartAttributesGaugeVec)
+	registerer.MustRegister(temperatureGauge)
+	registerer.MustRegister(temperatureAlertGauge)
+	registerer.MustRegister(reallocatedSectorsGauge)
+	registerer.MustRegister(pendingSectorsGauge)
+	registerer.MustRegister(powerOnHoursCounter)
+	registerer.MustRegister(ssdLifeUsedGauge)
+	registerer.MustRegister(errorCountsCounter)
+	registerer.MustRegister(scsiGrownDefectsGauge)
+	registerer.MustRegister(scsiErrorsCounter)
+	registerer.MustRegister(collectionErrorsCounter)
+	registerer.MustRegister(collectionDurationGauge)
+	registerer.MustRegister(scanDurationGauge)
+	registerer.MustRegister(hotplugEventsCounter)
+	registerer.MustRegister(locateRequestsCounter)
+	registerer.MustRegister(diskCapacityGauge)
+	registerer.MustRegister(diskInfoGauge) // Add this line
+	registerer.MustRegister(failureRiskScoreGauge)
+	registerer.MustRegister(failureRiskTrendGauge)
+	registerer.MustRegister(failureRiskFactorGauge)
+	registerer.MustRegister(remainingLifeGauge)
+	registerer.MustRegister(remainingLifeDevicesGauge)
+	registerer.MustRegister(nodeTemperatureMaxGauge)
+	registerer.MustRegister(nodeTemperatureAvgGauge)
+	registerer.MustRegister(nodeTemperatureOverWarningGauge)
+	registerer.MustRegister(enclosureTemperatureMaxGauge)
+	registerer.MustRegister(enclosureTemperatureAvgGauge)
+	registerer.MustRegister(firmwareCompliantGauge)
+	registerer.MustRegister(attributeBelowThresholdGauge)
+	registerer.MustRegister(attributeThresholdMarginGauge)
+	registerer.MustRegister(attributeWhenFailedGauge)
+	registerer.MustRegister(indicatorIncreaseGauge)
+	registerer.MustRegister(ioErrorsGauge)
+	registerer.MustRegister(ioLatencyGauge)
+	registerer.MustRegister(ioInFlightGauge)
+	registerer.MustRegister(pathCountGauge)
+	registerer.MustRegister(pathUpGauge)
+	registerer.MustRegister(pathIOErrorsGauge)
+	registerer.MustRegister(selfTestInProgressGauge)
+	registerer.MustRegister(selfTestRemainingGauge)
+	registerer.MustRegister(selfTestLastPassedGauge)
+	registerer.MustRegister(selfTestLastAgeGauge)
+	registerer.MustRegister(nvmeEnduranceGroupPercentUsedGauge)
+	registerer.MustRegister(nvmeEnduranceGroupAvailableSpareGauge)
+	registerer.MustRegister(nvmeEnduranceGroupDataUnitsWrittenGauge)
+	registerer.MustRegister(nvmeEnduranceGroupMediaUnitsWrittenGauge)
+	registerer.MustRegister(nvmeSelfTestInProgressGauge)
+	registerer.MustRegister(nvmeSelfTestCompletionGauge)
+	registerer.MustRegister(nvmeSelfTestLastResultGauge)
+	registerer.MustRegister(nvmeSelfTestFailedGauge)
+	registerer.MustRegister(nvmeVendorTelemetryGauge)
 }
 
 // PublishToPrometheus publishes the SMART data to Prometheus
 func PublishToPrometheus(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
 	for _, metric := range metrics {
 		labels := prometheus.Labels{
-			"disk":     metric.Device,
-			"node":     metric.NodeName,
-			"instance": metric.InstanceID,
-			"osd_id":   metric.OSDID,
+			"disk":         metric.Device,
+			"node":         metric.NodeName,
+			"instance":     metric.InstanceID,
+			"osd_id":       metric.OSDID,
+			"ceph_cluster": metric.CephCluster,
 		}
 
 		// Publish device info metric (static information)
 		if metric.DeviceInfo != nil {
+			location := locationOf(metric)
 			infoLabels := prometheus.Labels{
 				"disk":                metric.Device,
 				"node":                metric.NodeName,
 				"instance":            metric.InstanceID,
 				"osd_id":              metric.OSDID,
+				"ceph_cluster":        metric.CephCluster,
 				"vendor":              metric.DeviceInfo.Vendor,
 				"vendor_id":           metric.DeviceInfo.VendorID,
 				"subsystem_vendor_id": metric.DeviceInfo.SubsystemVendorID,
@@ -149,15 +534,44 @@ func PublishToPrometheus(metrics []NormalizedSmartData, cfg DiskHealthMetricsCon
 				"form_factor":         metric.DeviceInfo.FormFactor,
 				"rpm":                 fmt.Sprintf("%d", metric.DeviceInfo.RPM),
 				"dwpd":                fmt.Sprintf("%.2f", metric.DeviceInfo.DWPD),
+				"chassis":             location.Chassis,
+				"enclosure":           location.Enclosure,
+				"slot":                location.Slot,
 			}
 			// Info metrics are typically set to 1 to indicate presence
 			diskInfoGauge.With(infoLabels).Set(1)
 		}
 
+		if len(metric.Paths) > 0 {
+			publishDevicePaths(metric, labels)
+		}
+
+		publishIOErrors(metric, labels)
+
+		if metric.SelfTest != nil && metric.SelfTest.Supported {
+			publishSelfTestStatus(metric.SelfTest, labels)
+		}
+
+		if metric.DeviceInfo != nil && metric.DeviceInfo.NVMeTelemetry != nil {
+			publishNVMeTelemetry(metric.DeviceInfo.NVMeTelemetry, labels)
+		}
+
 		if metric.TemperatureCelsius != nil {
 			temperatureGauge.With(labels).Set(float64(*metric.TemperatureCelsius))
 		}
 
+		if metric.TemperatureAlert != nil {
+			alertLabels := prometheus.Labels{
+				"disk":         metric.Device,
+				"node":         metric.NodeName,
+				"instance":     metric.InstanceID,
+				"osd_id":       metric.OSDID,
+				"ceph_cluster": metric.CephCluster,
+				"media_type":   metric.DeviceInfo.Media,
+			}
+			temperatureAlertGauge.With(alertLabels).Set(float64(temperatureLevelRank[metric.TemperatureAlert.Level]))
+		}
+
 		if metric.ReallocatedSectors != nil {
 			reallocatedSectorsGauge.With(labels).Set(float64(*metric.ReallocatedSectors))
 		}
@@ -174,30 +588,314 @@ func PublishToPrometheus(metrics []NormalizedSmartData, cfg DiskHealthMetricsCon
 			ssdLifeUsedGauge.With(labels).Set(float64(*metric.SSDLifeUsed))
 		}
 
+		if metric.SCSIErrors != nil {
+			publishSCSIErrors(metric.Device, metric.SCSIErrors, labels)
+		}
+
 		diskCapacityGauge.With(labels).Set(metric.CapacityGB)
 
+		if metric.FailureRisk != nil {
+			failureRiskScoreGauge.With(labels).Set(metric.FailureRisk.Score)
+			failureRiskTrendGauge.With(labels).Set(metric.FailureRisk.Trend)
+			for factor, value := range metric.FailureRisk.Factors {
+				factorLabels := prometheus.Labels{
+					"disk":         metric.Device,
+					"node":         metric.NodeName,
+					"instance":     metric.InstanceID,
+					"osd_id":       metric.OSDID,
+					"ceph_cluster": metric.CephCluster,
+					"factor":       factor,
+				}
+				failureRiskFactorGauge.With(factorLabels).Set(value)
+			}
+		}
+
+		if metric.Endurance != nil {
+			remainingLifeGauge.With(labels).Set(metric.Endurance.RemainingDays)
+		}
+
+		// A reloaded device database may drop the policy of the model.
+		if metric.Firmware != nil {
+			compliant := 0.0
+			if metric.Firmware.Compliant {
+				compliant = 1
+			}
+			firmwareCompliantGauge.With(labels).Set(compliant)
+		} else {
+			firmwareCompliantGauge.Delete(labels)
+		}
+
+		publishAttributeThresholds(metric.Thresholds, labels)
+
+		for _, trend := range metric.Trends {
+			trendLabels := prometheus.Labels{
+				"disk":         metric.Device,
+				"node":         metric.NodeName,
+				"instance":     metric.InstanceID,
+				"osd_id":       metric.OSDID,
+				"ceph_cluster": metric.CephCluster,
+				"indicator":    trend.Indicator,
+				"window":       trend.Window,
+			}
+			indicatorIncreaseGauge.With(trendLabels).Set(float64(trend.Increase))
+		}
+
 		for errorType, count := range metric.ErrorCounts {
 			errorLabels := prometheus.Labels{
-				"disk":       metric.Device,
-				"node":       metric.NodeName,
-				"instance":   metric.InstanceID,
-				"error_type": errorType,
-				"osd_id":     metric.OSDID,
+				"disk":         metric.Device,
+				"node":         metric.NodeName,
+				"instance":     metric.InstanceID,
+				"error_type":   errorType,
+				"osd_id":       metric.OSDID,
+				"ceph_cluster": metric.CephCluster,
 			}
 			updateErrorCountsCounter(metric.Device, errorType, count, errorLabels)
 		}
 
 		for attrName, attrValue := range metric.Attributes {
+			if !prometheusAttributeFilter.exports(attrName) {
+				continue
+			}
 			attrLabels := prometheus.Labels{
-				"disk":      metric.Device,
-				"attribute": attrName,
-				"node":      metric.NodeName,
-				"instance":  metric.InstanceID,
-				"osd_id":    metric.OSDID,
+				"disk":         metric.Device,
+				"attribute":    attrName,
+				"node":         metric.NodeName,
+				"instance":     metric.InstanceID,
+				"osd_id":       metric.OSDID,
+				"ceph_cluster": metric.CephCluster,
 			}
 			smartAttributesGaugeVec.With(attrLabels).Set(float64(attrValue.RawValue))
 		}
 	}
+
+	publishRemainingLifeHistogram(metrics, cfg)
+	publishTemperatureRollups(metrics, cfg)
+}
+
+// publishTemperatureRollups exports the hottest and mean temperature of the
+// node and of each enclosure and the disks above their warning level, so
+// alerts and dashboards of the fleet need no aggregation over every disk.
+// The series of the previous collection are dropped first, so a node or
+// enclosure without temperature readings left reports none.
+func publishTemperatureRollups(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
+	node, enclosures := rollupTemperatures(metrics)
+	nodeLabels := prometheus.Labels{"node": cfg.NodeName, "instance": cfg.InstanceID}
+
+	nodeTemperatureMaxGauge.Reset()
+	nodeTemperatureAvgGauge.Reset()
+	if node.Devices > 0 {
+		nodeTemperatureMaxGauge.With(nodeLabels).Set(float64(node.MaxCelsius))
+		nodeTemperatureAvgGauge.With(nodeLabels).Set(node.AvgCelsius())
+	}
+	nodeTemperatureOverWarningGauge.With(nodeLabels).Set(float64(node.OverWarning))
+
+	enclosureTemperatureMaxGauge.Reset()
+	enclosureTemperatureAvgGauge.Reset()
+	for location, enclosure := range enclosures {
+		if enclosure.Devices == 0 {
+			continue
+		}
+		enclosureLabels := prometheus.Labels{
+			"node":      cfg.NodeName,
+			"instance":  cfg.InstanceID,
+			"chassis":   location.Chassis,
+			"enclosure": location.Enclosure,
+		}
+		enclosureTemperatureMaxGauge.With(enclosureLabels).Set(float64(enclosure.MaxCelsius))
+		enclosureTemperatureAvgGauge.With(enclosureLabels).Set(enclosure.AvgCelsius())
+	}
+}
+
+// publishRemainingLifeHistogram exports the remaining life of all SSDs of the
+// node as cumulative buckets. A Prometheus histogram would accumulate the
+// observations of every collection, so the buckets are gauges instead.
+func publishRemainingLifeHistogram(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
+	counts := remainingLifeHistogram(metrics)
+	for i, count := range counts {
+		le := "+Inf"
+		if i < len(remainingLifeBuckets) {
+			le = strconv.FormatFloat(remainingLifeBuckets[i], 'f', -1, 64)
+		}
+		remainingLifeDevicesGauge.With(prometheus.Labels{
+			"node":     cfg.NodeName,
+			"instance": cfg.InstanceID,
+			"le":       le,
+		}).Set(float64(count))
+	}
+}
+
+// publishAttributeThresholds exports the ATA attributes compared against
+// the thresholds of the drive. The when_failed series of earlier states are
+// dropped so an attribute that failed reports only its current state.
+func publishAttributeThresholds(thresholds []AttributeThreshold, labels prometheus.Labels) {
+	withLabels := func(extra prometheus.Labels) prometheus.Labels {
+		for k, v := range labels {
+			extra[k] = v
+		}
+		return extra
+	}
+
+	for _, attr := range thresholds {
+		id := strconv.FormatInt(attr.ID, 10)
+		attrLabels := withLabels(prometheus.Labels{"attribute_id": id, "attribute": attr.Name, "prefailure": strconv.FormatBool(attr.Prefailure)})
+
+		below := 0.0
+		if attr.BelowThreshold() {
+			below = 1
+		}
+		attributeBelowThresholdGauge.With(attrLabels).Set(below)
+		attributeThresholdMarginGauge.With(attrLabels).Set(float64(attr.Value - attr.Threshold))
+
+		attributeWhenFailedGauge.DeletePartialMatch(withLabels(prometheus.Labels{"attribute_id": id}))
+		attributeWhenFailedGauge.With(withLabels(prometheus.Labels{"attribute_id": id, "attribute": attr.Name, "when_failed": attr.WhenFailed})).Set(1)
+	}
+}
+
+// publishDevicePaths exports path count, state and per-path I/O errors for
+// devices reachable through more than one path.
+func publishDevicePaths(metric NormalizedSmartData, labels prometheus.Labels) {
+	withLabels := func(extra prometheus.Labels) prometheus.Labels {
+		for k, v := range labels {
+			extra[k] = v
+		}
+		return extra
+	}
+
+	pathCountGauge.With(withLabels(prometheus.Labels{"multipath_device": metric.MultipathDevice})).Set(float64(len(metric.Paths)))
+
+	// Drop series of earlier states so a path that changed state does not
+	// keep reporting the old one.
+	for _, path := range metric.Paths {
+		pathUpGauge.DeletePartialMatch(withLabels(prometheus.Labels{"path": path.Name}))
+
+		up := 0.0
+		if path.State == "running" {
+			up = 1
+		}
+		pathUpGauge.With(withLabels(prometheus.Labels{"path": path.Name, "state": path.State})).Set(up)
+		pathIOErrorsGauge.With(withLabels(prometheus.Labels{"path": path.Name})).Set(float64(path.IOErrors))
+	}
+}
+
+// publishIOErrors exports the drive-reported and kernel-reported I/O errors
+// of a device into one metric family, together with the kernel latencies.
+func publishIOErrors(metric NormalizedSmartData, labels prometheus.Labels) {
+	withLabels := func(extra prometheus.Labels) prometheus.Labels {
+		for k, v := range labels {
+			extra[k] = v
+		}
+		return extra
+	}
+
+	ioErrorsGauge.With(withLabels(prometheus.Labels{"source": "smart", "error_type": "media_error"})).Set(float64(riskMediaErrors(metric)))
+	ioErrorsGauge.With(withLabels(prometheus.Labels{"source": "smart", "error_type": "crc_error"})).Set(float64(metric.ErrorCounts["UDMA_CRC_Error_Count"]))
+
+	kernelIO := metric.KernelIO
+	if kernelIO == nil {
+		return
+	}
+
+	ioErrorsGauge.With(withLabels(prometheus.Labels{"source": "kernel", "error_type": "scsi_ioerr"})).Set(float64(kernelIO.SCSIIOErrors))
+	for _, errorType := range []string{KernelErrorIO, KernelErrorMedium, KernelErrorTimeout} {
+		ioErrorsGauge.With(withLabels(prometheus.Labels{"source": "kernel", "error_type": errorType})).Set(float64(kernelIO.KernelErrors[errorType]))
+	}
+
+	ioLatencyGauge.With(withLabels(prometheus.Labels{"op": "read"})).Set(kernelIO.ReadLatencyMs)
+	ioLatencyGauge.With(withLabels(prometheus.Labels{"op": "write"})).Set(kernelIO.WriteLatencyMs)
+	ioInFlightGauge.With(labels).Set(float64(kernelIO.InFlight))
+}
+
+// publishSelfTestStatus exports the SMART self-test state of a device.
+func publishSelfTestStatus(status *SelfTestStatus, labels prometheus.Labels) {
+	inProgress := 0.0
+	if status.InProgress {
+		inProgress = 1
+	}
+	selfTestInProgressGauge.With(labels).Set(inProgress)
+	selfTestRemainingGauge.With(labels).Set(float64(status.RemainingPercent))
+
+	for testType, result := range status.Results {
+		resultLabels := prometheus.Labels{"test_type": testType}
+		for k, v := range labels {
+			resultLabels[k] = v
+		}
+		passed := 0.0
+		if result.Passed {
+			passed = 1
+		}
+		selfTestLastPassedGauge.With(resultLabels).Set(passed)
+		selfTestLastAgeGauge.With(resultLabels).Set(float64(result.AgeHours))
+	}
+}
+
+// publishNVMeTelemetry exports the nvme-cli log pages collected for a device.
+func publishNVMeTelemetry(telemetry *NVMeTelemetry, labels prometheus.Labels) {
+	withLabel := func(name, value string) prometheus.Labels {
+		l := prometheus.Labels{name: value}
+		for k, v := range labels {
+			l[k] = v
+		}
+		return l
+	}
+
+	for _, group := range telemetry.EnduranceGroups {
+		groupLabels := withLabel("endurance_group", fmt.Sprintf("%d", group.GroupID))
+		nvmeEnduranceGroupPercentUsedGauge.With(groupLabels).Set(float64(group.PercentageUsed))
+		nvmeEnduranceGroupAvailableSpareGauge.With(groupLabels).Set(float64(group.AvailableSpare))
+		nvmeEnduranceGroupDataUnitsWrittenGauge.With(groupLabels).Set(float64(group.DataUnitsWritten))
+		nvmeEnduranceGroupMediaUnitsWrittenGauge.With(groupLabels).Set(float64(group.MediaUnitsWritten))
+	}
+
+	if selfTest := telemetry.SelfTest; selfTest != nil {
+		inProgress := 0.0
+		if selfTest.InProgress {
+			inProgress = 1
+		}
+		nvmeSelfTestInProgressGauge.With(labels).Set(inProgress)
+		nvmeSelfTestCompletionGauge.With(labels).Set(float64(selfTest.CompletionPercent))
+		nvmeSelfTestFailedGauge.With(labels).Set(float64(selfTest.FailedTestsInLog))
+		if selfTest.LastResult >= 0 {
+			nvmeSelfTestLastResultGauge.With(withLabel("test_type", selfTest.LastTestType)).Set(float64(selfTest.LastResult))
+		}
+	}
+
+	for name, value := range telemetry.Vendor {
+		nvmeVendorTelemetryGauge.With(withLabel("attribute", name)).Set(float64(value))
+	}
+}
+
+// publishSCSIErrors exports the grown defect list of a SCSI disk and adds the
+// increase of its error counter log since the previous collection.
+func publishSCSIErrors(diskKey string, counters *SCSIErrorCounters, labels prometheus.Labels) {
+	scsiGrownDefectsGauge.With(labels).Set(float64(counters.GrownDefects))
+
+	previousValuesMutex.Lock()
+	defer previousValuesMutex.Unlock()
+
+	prevState := previousValues[diskKey]
+	if prevState.SCSIErrors == nil {
+		prevState.SCSIErrors = make(map[string]int64)
+	}
+
+	for op, count := range counters.Errors {
+		for errorType, currentValue := range map[string]int64{"corrected": count.Corrected, "uncorrected": count.Uncorrected} {
+			key := op + "_" + errorType
+			prevValue, seen := prevState.SCSIErrors[key]
+			if seen && currentValue < prevValue {
+				log.Warn().Msgf("SCSI %s error count decreased for disk %s: %d -> %d", key, diskKey, prevValue, currentValue)
+			}
+			if delta := counterIncrease(prevValue, seen, currentValue); delta > 0 {
+				errorLabels := prometheus.Labels{"op": op, "error_type": errorType}
+				for k, v := range labels {
+					errorLabels[k] = v
+				}
+				scsiErrorsCounter.With(errorLabels).Add(float64(delta))
+			}
+			prevState.SCSIErrors[key] = currentValue
+		}
+	}
+
+	previousValues[diskKey] = prevState
 }
 
 func updatePowerOnHoursCounter(diskKey string, currentValue int64, labels prometheus.Labels) {
@@ -286,13 +984,87 @@ func updateErrorCountsCounter(diskKey, errorType string, currentValue int64, lab
 	previousValues[diskKey] = prevState
 }
 
-func StartPrometheusServer(port int) {
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
+// StartPrometheusServer registers the metrics with the default registry and
+// serves them together with the probes and the replacement recommendations.
+// The node topology in Kubernetes mode is attached to every metric as zone
+// and rack labels.
+func StartPrometheusServer(port int, cfg *DiskHealthMetricsConfig, probe *scanProbe, advisor *replacementAdvisor) {
+	topologyLabels := prometheus.Labels{}
+	if cfg.Zone != "" {
+		topologyLabels["zone"] = cfg.Zone
+	}
+	if cfg.Rack != "" {
+		topologyLabels["rack"] = cfg.Rack
+	}
+	registerMetrics(prometheus.WrapRegistererWith(topologyLabels, prometheus.DefaultRegisterer))
+
+	telemetry.RegisterHealthChecks("disk-health-metrics", probe.live, probe.ready)
+	http.Handle("/replacements", advisor)
+	telemetry.StartMetricsServer(port)
+}
+
+// publishScanResults exports the per-device collection errors and durations
+// of a scan. They are labeled by the configured device, as failed devices
+// have no SMART data to take the device name from.
+func publishScanResults(targets []diskTarget, results []scanResult, scanDuration time.Duration, cfg DiskHealthMetricsConfig) {
+	for i, result := range results {
+		labels := prometheus.Labels{
+			"disk":        targets[i].path,
+			"device_type": targets[i].deviceType,
+			"node":        cfg.NodeName,
+			"instance":    cfg.InstanceID,
 		}
-	}()
+		if result.duration > 0 {
+			collectionDurationGauge.With(labels).Set(result.duration.Seconds())
+		}
+		if result.err != nil && result.reason != "" {
+			labels["reason"] = result.reason
+			collectionErrorsCounter.With(labels).Inc()
+		}
+	}
+
+	scanDurationGauge.With(prometheus.Labels{"node": cfg.NodeName, "instance": cfg.InstanceID}).Set(scanDuration.Seconds())
+}
+
+// deviceMetricVecs are the metrics with a series per disk.
+var deviceMetricVecs = []interface {
+	DeletePartialMatch(labels prometheus.Labels) int
+}{
+	smartAttributesGaugeVec, temperatureGauge, temperatureAlertGauge,
+	reallocatedSectorsGauge, pendingSectorsGauge, powerOnHoursCounter,
+	ssdLifeUsedGauge, errorCountsCounter, scsiGrownDefectsGauge,
+	scsiErrorsCounter, collectionErrorsCounter,
+	collectionDurationGauge, diskCapacityGauge, diskInfoGauge,
+	failureRiskScoreGauge, failureRiskTrendGauge, failureRiskFactorGauge,
+	remainingLifeGauge, firmwareCompliantGauge, indicatorIncreaseGauge, ioErrorsGauge, ioLatencyGauge,
+	attributeBelowThresholdGauge, attributeThresholdMarginGauge, attributeWhenFailedGauge,
+	ioInFlightGauge, pathCountGauge, pathUpGauge, pathIOErrorsGauge,
+	selfTestInProgressGauge, selfTestRemainingGauge, selfTestLastPassedGauge,
+	selfTestLastAgeGauge, nvmeEnduranceGroupPercentUsedGauge,
+	nvmeEnduranceGroupAvailableSpareGauge, nvmeEnduranceGroupDataUnitsWrittenGauge,
+	nvmeEnduranceGroupMediaUnitsWrittenGauge, nvmeSelfTestInProgressGauge,
+	nvmeSelfTestCompletionGauge, nvmeSelfTestLastResultGauge,
+	nvmeSelfTestFailedGauge, nvmeVendorTelemetryGauge,
+}
+
+// deleteDeviceMetrics drops all series and counter state of a removed disk,
+// so it does not keep reporting its last values and a drive inserted under
+// the same name starts from scratch.
+func deleteDeviceMetrics(device string) {
+	for _, vec := range deviceMetricVecs {
+		vec.DeletePartialMatch(prometheus.Labels{"disk": device})
+	}
+
+	previousValuesMutex.Lock()
+	delete(previousValues, device)
+	previousValuesMutex.Unlock()
+}
+
+// publishHotplugEvent counts a disk added to or removed from the node.
+func publishHotplugEvent(event DeviceEvent, cfg DiskHealthMetricsConfig) {
+	hotplugEventsCounter.With(prometheus.Labels{
+		"node":     cfg.NodeName,
+		"instance": cfg.InstanceID,
+		"action":   event.Action,
+	}).Inc()
 }
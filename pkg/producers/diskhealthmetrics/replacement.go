@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Reasons a device is recommended for replacement.
+const (
+	ReplacementReasonSmartFailed  = "smart_failed"  // the drive reports a failed SMART status
+	ReplacementReasonRiskCritical = "risk_critical" // failure-risk score at or above the critical threshold
+	ReplacementReasonRiskWarning  = "risk_warning"  // failure-risk score at or above the requested minimum, below critical
+	ReplacementReasonWornOut      = "worn_out"      // projected remaining write endurance is nearly used up
+	ReplacementReasonAged         = "aged"          // powered on for longer than replacementAgeYears
+)
+
+const (
+	// replacementRemainingLifeDays is the remaining life below which an SSD
+	// is recommended for replacement regardless of its risk score.
+	replacementRemainingLifeDays = 90
+	// replacementAgeYears is the power-on time after which a device gains
+	// one priority point per year, up to replacementMaxAgePoints.
+	replacementAgeYears     = 5
+	replacementMaxAgePoints = 10
+)
+
+// errNoScan is returned for recommendations requested before the first scan.
+var errNoScan = errors.New("no scan has completed yet")
+
+// ReplacementRequest asks for the replacement recommendations of a node.
+type ReplacementRequest struct {
+	NodeName string  `json:"node_name"`           // node to recommend for, must be the producer's node
+	MinScore float64 `json:"min_score,omitempty"` // minimum failure-risk score, the risk warning threshold if 0
+	Limit    int     `json:"limit,omitempty"`     // maximum number of devices, 0 for all
+}
+
+// ReplacementRecommendation lists the devices of a node recommended for
+// replacement, most urgent first.
+type ReplacementRecommendation struct {
+	NodeName   string                 `json:"node_name"`
+	InstanceID string                 `json:"instance_id"`
+	Zone       string                 `json:"zone,omitempty"`
+	Rack       string                 `json:"rack,omitempty"`
+	ScannedAt  time.Time              `json:"scanned_at"` // scan the recommendation is based on
+	Devices    []ReplacementCandidate `json:"devices"`
+	Error      string                 `json:"error,omitempty"` // set instead of devices if the request failed
+}
+
+// ReplacementCandidate is a device recommended for replacement. Candidates
+// are replaced batch by batch: a batch contains at most one device backing
+// an OSD per Ceph cluster, so the cluster recovers one OSD at a time and
+// never loses more redundancy on this node than it has to.
+type ReplacementCandidate struct {
+	Rank              int            `json:"rank"`
+	Batch             int            `json:"batch"`
+	Device            string         `json:"device"`
+	SerialNumber      string         `json:"serial_number,omitempty"`
+	Model             string         `json:"model,omitempty"`
+	Media             string         `json:"media,omitempty"`
+	OSDID             string         `json:"osd_id,omitempty"`
+	CephCluster       string         `json:"ceph_cluster,omitempty"`
+	Location          *DriveLocation `json:"location,omitempty"` // bay to pull the device from
+	Priority          float64        `json:"priority"`           // failure-risk score raised for failed or worn-out drives, plus age points
+	FailureRiskScore  *float64       `json:"failure_risk_score"`
+	PowerOnHours      *int64         `json:"power_on_hours"`
+	RemainingLifeDays *float64       `json:"remaining_life_days,omitempty"`
+	Reasons           []string       `json:"reasons"`
+}
+
+// rankReplacements selects the devices due for replacement and orders them
+// by priority, older devices first on equal priority.
+func rankReplacements(metrics []NormalizedSmartData, minScore, criticalScore float64) []ReplacementCandidate {
+	candidates := []ReplacementCandidate{}
+	for _, metric := range metrics {
+		candidate := ReplacementCandidate{
+			Device:       metric.Device,
+			OSDID:        metric.OSDID,
+			CephCluster:  metric.CephCluster,
+			Location:     metric.Location,
+			PowerOnHours: metric.PowerOnHours,
+		}
+		if info := metric.DeviceInfo; info != nil {
+			candidate.SerialNumber = info.SerialNumber
+			candidate.Model = info.DeviceModel
+			candidate.Media = info.Media
+		}
+
+		if metric.HealthStatus != nil && !*metric.HealthStatus {
+			candidate.Reasons = append(candidate.Reasons, ReplacementReasonSmartFailed)
+			candidate.Priority = 100
+		}
+		if risk := metric.FailureRisk; risk != nil {
+			candidate.FailureRiskScore = &risk.Score
+			if risk.Score >= minScore {
+				reason := ReplacementReasonRiskWarning
+				if risk.Score >= criticalScore {
+					reason = ReplacementReasonRiskCritical
+				}
+				candidate.Reasons = append(candidate.Reasons, reason)
+			}
+			candidate.Priority = math.Max(candidate.Priority, risk.Score)
+		}
+		if endurance := metric.Endurance; endurance != nil {
+			candidate.RemainingLifeDays = &endurance.RemainingDays
+			if endurance.RemainingDays < replacementRemainingLifeDays {
+				candidate.Reasons = append(candidate.Reasons, ReplacementReasonWornOut)
+				candidate.Priority = math.Max(candidate.Priority, criticalScore)
+			}
+		}
+		if len(candidate.Reasons) == 0 {
+			continue
+		}
+
+		if metric.PowerOnHours != nil {
+			if years := float64(*metric.PowerOnHours) / (24 * 365); years > replacementAgeYears {
+				candidate.Reasons = append(candidate.Reasons, ReplacementReasonAged)
+				candidate.Priority += math.Min(years-replacementAgeYears, replacementMaxAgePoints)
+			}
+		}
+		candidate.Priority = math.Round(candidate.Priority*100) / 100
+		candidates = append(candidates, candidate)
+	}
+
+	powerOnHours := func(c ReplacementCandidate) int64 {
+		if c.PowerOnHours == nil {
+			return 0
+		}
+		return *c.PowerOnHours
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		if powerOnHours(candidates[i]) != powerOnHours(candidates[j]) {
+			return powerOnHours(candidates[i]) > powerOnHours(candidates[j])
+		}
+		return candidates[i].Device < candidates[j].Device
+	})
+
+	osdsPerCluster := make(map[string]int)
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+		candidates[i].Batch = 1
+		if candidates[i].OSDID != "" {
+			osdsPerCluster[candidates[i].CephCluster]++
+			candidates[i].Batch = osdsPerCluster[candidates[i].CephCluster]
+		}
+	}
+	return candidates
+}
+
+// replacementAdvisor answers replacement requests from the latest scan.
+type replacementAdvisor struct {
+	cfg DiskHealthMetricsConfig
+
+	mu        sync.Mutex
+	metrics   []NormalizedSmartData
+	scannedAt time.Time
+}
+
+func newReplacementAdvisor(cfg DiskHealthMetricsConfig) *replacementAdvisor {
+	return &replacementAdvisor{cfg: cfg}
+}
+
+// update replaces the scan recommendations are based on.
+func (a *replacementAdvisor) update(metrics []NormalizedSmartData, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.metrics = metrics
+	a.scannedAt = now.UTC()
+}
+
+// recommend ranks the devices of the latest scan for the request.
+func (a *replacementAdvisor) recommend(req ReplacementRequest) (ReplacementRecommendation, error) {
+	recommendation := ReplacementRecommendation{
+		NodeName:   a.cfg.NodeName,
+		InstanceID: a.cfg.InstanceID,
+		Zone:       a.cfg.Zone,
+		Rack:       a.cfg.Rack,
+		Devices:    []ReplacementCandidate{},
+	}
+	if req.NodeName != "" && req.NodeName != a.cfg.NodeName {
+		return recommendation, fmt.Errorf("node %s is not served by this producer (%s)", req.NodeName, a.cfg.NodeName)
+	}
+
+	a.mu.Lock()
+	metrics, scannedAt := a.metrics, a.scannedAt
+	a.mu.Unlock()
+	if scannedAt.IsZero() {
+		return recommendation, errNoScan
+	}
+
+	minScore := req.MinScore
+	if minScore <= 0 {
+		minScore = a.cfg.RiskWarningThreshold
+	}
+	recommendation.ScannedAt = scannedAt
+	recommendation.Devices = rankReplacements(metrics, minScore, a.cfg.RiskCriticalThreshold)
+	if req.Limit > 0 && len(recommendation.Devices) > req.Limit {
+		recommendation.Devices = recommendation.Devices[:req.Limit]
+	}
+	return recommendation, nil
+}
+
+// respond answers a JSON-encoded request received over NATS, where all
+// producers share the subject: requests for other nodes are left to their
+// producer (ok is false), an empty request is answered by every node. Errors
+// are reported in the response.
+func (a *replacementAdvisor) respond(data []byte) (response []byte, ok bool) {
+	var req ReplacementRequest
+	var recommendation ReplacementRecommendation
+	var err error
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &req)
+	}
+	if err == nil {
+		if req.NodeName != "" && req.NodeName != a.cfg.NodeName {
+			return nil, false
+		}
+		recommendation, err = a.recommend(req)
+	}
+	if err != nil {
+		recommendation.Error = err.Error()
+	}
+
+	response, _ = json.Marshal(recommendation)
+	return response, true
+}
+
+// ServeHTTP serves the recommendations on GET /replacements with the
+// optional query parameters node, min_score and limit.
+func (a *replacementAdvisor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := ReplacementRequest{NodeName: query.Get("node")}
+	var err error
+	if value := query.Get("min_score"); value != "" {
+		if req.MinScore, err = strconv.ParseFloat(value, 64); err != nil {
+			http.Error(w, "invalid min_score", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if req.Limit, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	status := http.StatusOK
+	recommendation, err := a.recommend(req)
+	switch {
+	case errors.Is(err, errNoScan):
+		status = http.StatusServiceUnavailable
+	case err != nil:
+		status = http.StatusNotFound
+	}
+	if err != nil {
+		recommendation.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(recommendation)
+}
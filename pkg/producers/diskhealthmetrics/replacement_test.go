@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func replacementTestMetrics() []NormalizedSmartData {
+	failed, healthy := false, true
+	hours := func(h int64) *int64 { return &h }
+	return []NormalizedSmartData{
+		{Device: "/dev/sda", HealthStatus: &healthy, PowerOnHours: hours(1000), FailureRisk: &FailureRisk{Score: 5}},
+		{Device: "/dev/sdb", HealthStatus: &healthy, PowerOnHours: hours(20000), FailureRisk: &FailureRisk{Score: 65}, OSDID: "1", CephCluster: "c1"},
+		{Device: "/dev/sdc", HealthStatus: &failed, PowerOnHours: hours(20000), FailureRisk: &FailureRisk{Score: 40}, OSDID: "2", CephCluster: "c1"},
+		// Seven years old: the age adds two points on top of the score.
+		{Device: "/dev/sdd", HealthStatus: &healthy, PowerOnHours: hours(7 * 24 * 365), FailureRisk: &FailureRisk{Score: 65}, OSDID: "3", CephCluster: "c1"},
+		{Device: "/dev/nvme0n1", HealthStatus: &healthy, PowerOnHours: hours(5000), FailureRisk: &FailureRisk{Score: 10}, Endurance: &EnduranceProjection{RemainingDays: 30}},
+	}
+}
+
+func TestRankReplacements(t *testing.T) {
+	candidates := rankReplacements(replacementTestMetrics(), 60, 80)
+	require.Len(t, candidates, 4, "healthy low-risk devices are not recommended")
+
+	devices := make([]string, len(candidates))
+	for i, c := range candidates {
+		devices[i] = c.Device
+		assert.Equal(t, i+1, c.Rank)
+	}
+	assert.Equal(t, []string{"/dev/sdc", "/dev/nvme0n1", "/dev/sdd", "/dev/sdb"}, devices)
+
+	assert.Equal(t, []string{ReplacementReasonSmartFailed}, candidates[0].Reasons)
+	assert.Equal(t, []string{ReplacementReasonWornOut}, candidates[1].Reasons)
+	assert.Equal(t, 80.0, candidates[1].Priority, "worn-out drives rank as critical")
+	assert.Equal(t, []string{ReplacementReasonRiskWarning, ReplacementReasonAged}, candidates[2].Reasons)
+	assert.Equal(t, 67.0, candidates[2].Priority)
+
+	// One OSD of the cluster per batch, devices without an OSD in the first.
+	batches := map[string]int{}
+	for _, c := range candidates {
+		batches[c.Device] = c.Batch
+	}
+	assert.Equal(t, map[string]int{"/dev/sdc": 1, "/dev/nvme0n1": 1, "/dev/sdd": 2, "/dev/sdb": 3}, batches)
+
+	assert.Len(t, rankReplacements(replacementTestMetrics(), 70, 80), 2, "min score filters risk-based candidates")
+}
+
+func TestReplacementAdvisor(t *testing.T) {
+	advisor := newReplacementAdvisor(DiskHealthMetricsConfig{NodeName: "storage-01", RiskWarningThreshold: 60, RiskCriticalThreshold: 80})
+
+	get := func(query string) (int, ReplacementRecommendation) {
+		rec := httptest.NewRecorder()
+		advisor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replacements"+query, nil))
+		var recommendation ReplacementRecommendation
+		if rec.Code != http.StatusBadRequest { // invalid parameters are answered in plain text
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &recommendation))
+		}
+		return rec.Code, recommendation
+	}
+
+	code, recommendation := get("")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.NotEmpty(t, recommendation.Error)
+
+	advisor.update(replacementTestMetrics(), time.Now())
+	code, recommendation = get("?limit=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "storage-01", recommendation.NodeName)
+	assert.Len(t, recommendation.Devices, 2)
+
+	code, _ = get("?node=storage-02")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("?limit=x")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Over NATS, requests for other nodes are left to their producer.
+	_, ok := advisor.respond([]byte(`{"node_name": "storage-02"}`))
+	assert.False(t, ok)
+	response, ok := advisor.respond([]byte(`{"node_name": "storage-01", "min_score": 70}`))
+	require.True(t, ok)
+	require.NoError(t, json.Unmarshal(response, &recommendation))
+	assert.Len(t, recommendation.Devices, 2)
+	response, ok = advisor.respond([]byte(`not json`))
+	require.True(t, ok)
+	require.NoError(t, json.Unmarshal(response, &recommendation))
+	assert.NotEmpty(t, recommendation.Error)
+}
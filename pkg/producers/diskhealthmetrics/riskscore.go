@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Failure risk levels reported in FailureRisk.Level.
+const (
+	RiskLevelOK       = "ok"
+	RiskLevelWarning  = "warning"
+	RiskLevelCritical = "critical"
+)
+
+const (
+	// riskHistoryResolution is the minimum spacing between samples kept for
+	// trend calculation, independent of the collection interval.
+	riskHistoryResolution = time.Hour
+	// riskHistoryWindow is the period the trend is calculated over.
+	riskHistoryWindow = 24 * time.Hour
+)
+
+// riskFactor describes how a single SMART indicator contributes to the
+// failure-risk score. The indicator is saturated with 1-exp(-value/scale), so
+// the first few occurrences weigh the most, and then capped at maxRisk.
+type riskFactor struct {
+	scale   float64
+	maxRisk float64
+}
+
+var riskFactors = map[string]riskFactor{
+	"reallocated_sectors": {scale: 20, maxRisk: 0.8},
+	"pending_sectors":     {scale: 5, maxRisk: 0.9},
+	"media_errors":        {scale: 10, maxRisk: 0.9},
+	"wear_level":          {scale: 0, maxRisk: 0.7}, // linear, value is percent used
+}
+
+// mediaErrorAttributes are summed into the media_errors factor.
+var mediaErrorAttributes = []string{
+	"nvme_media_errors",
+	"media_and_data_integrity_errors",
+	"reported_uncorrect",
+	"offline_uncorrectable",
+	"total_uncorrected_read_errors",
+	"total_uncorrected_write_errors",
+	"total_uncorrected_verify_errors",
+}
+
+// FailureRisk is the per-device failure-risk assessment.
+type FailureRisk struct {
+	Score         float64            `json:"score"`          // 0 (healthy) to 100 (failure imminent)
+	Trend         float64            `json:"trend"`          // score change over the trend window
+	Level         string             `json:"level"`          // ok, warning or critical
+	PreviousLevel string             `json:"previous_level"` // level of the previous collection, empty on first run
+	Factors       map[string]float64 `json:"factors"`        // per-factor risk contribution (0-1)
+}
+
+// LevelChanged reports whether the score crossed a threshold since the last
+// collection. A device seen for the first time counts as changed unless it is ok.
+func (r *FailureRisk) LevelChanged() bool {
+	if r.PreviousLevel == "" {
+		return r.Level != RiskLevelOK
+	}
+	return r.Level != r.PreviousLevel
+}
+
+type riskSample struct {
+	at    time.Time
+	score float64
+}
+
+type deviceRiskState struct {
+	level   string
+	history []riskSample
+}
+
+var (
+	riskStates      = make(map[string]*deviceRiskState)
+	riskStatesMutex sync.Mutex
+)
+
+// scoreFailureRisk assigns a FailureRisk to every collected device and
+// updates the per-device trend history.
+func scoreFailureRisk(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
+	now := time.Now()
+	for i := range metrics {
+		score, factors := calculateRiskScore(metrics[i])
+		metrics[i].FailureRisk = trackFailureRisk(metrics[i].Device, score, factors, cfg, now)
+	}
+}
+
+// calculateRiskScore combines the critical SMART indicators into a score.
+// Factors are treated as independent failure probabilities:
+// score = 100 * (1 - Π(1 - risk_i)).
+func calculateRiskScore(data NormalizedSmartData) (float64, map[string]float64) {
+	values := map[string]float64{
+		"reallocated_sectors": float64(riskReallocatedSectors(data)),
+		"pending_sectors":     float64(riskPendingSectors(data)),
+		"media_errors":        float64(riskMediaErrors(data)),
+		"wear_level":          float64(riskWearLevel(data)),
+	}
+
+	factors := make(map[string]float64, len(values))
+	survival := 1.0
+	for name, value := range values {
+		factor := riskFactors[name]
+		var risk float64
+		switch {
+		case value <= 0:
+			risk = 0
+		case factor.scale == 0:
+			risk = math.Min(value/100, 1) * factor.maxRisk
+		default:
+			risk = (1 - math.Exp(-value/factor.scale)) * factor.maxRisk
+		}
+		factors[name] = risk
+		survival *= 1 - risk
+	}
+
+	return math.Round((1-survival)*10000) / 100, factors
+}
+
+func riskReallocatedSectors(data NormalizedSmartData) int64 {
+	count := max(data.Attributes["reallocated_sector_ct"].RawValue, data.Attributes["grown_defects_count"].RawValue)
+	if data.ReallocatedSectors != nil {
+		count = max(count, *data.ReallocatedSectors)
+	}
+	return count
+}
+
+func riskPendingSectors(data NormalizedSmartData) int64 {
+	count := data.Attributes["current_pending_sector"].RawValue
+	if data.PendingSectors != nil {
+		count = max(count, *data.PendingSectors)
+	}
+	return count
+}
+
+func riskMediaErrors(data NormalizedSmartData) int64 {
+	var count int64
+	for _, name := range mediaErrorAttributes {
+		if attr, ok := data.Attributes[name]; ok && attr.RawValue > 0 {
+			count += attr.RawValue
+		}
+	}
+	return count
+}
+
+func riskWearLevel(data NormalizedSmartData) int64 {
+	if data.SSDLifeUsed != nil {
+		return *data.SSDLifeUsed
+	}
+	if attr, ok := data.Attributes["percentage_used"]; ok {
+		return attr.RawValue
+	}
+	return 0
+}
+
+// riskLevel maps a score onto the configured thresholds.
+func riskLevel(score float64, cfg DiskHealthMetricsConfig) string {
+	switch {
+	case score >= cfg.RiskCriticalThreshold:
+		return RiskLevelCritical
+	case score >= cfg.RiskWarningThreshold:
+		return RiskLevelWarning
+	default:
+		return RiskLevelOK
+	}
+}
+
+// trackFailureRisk records the score for a device and derives trend and
+// threshold crossings from the stored history.
+func trackFailureRisk(device string, score float64, factors map[string]float64, cfg DiskHealthMetricsConfig, now time.Time) *FailureRisk {
+	riskStatesMutex.Lock()
+	defer riskStatesMutex.Unlock()
+
+	state, exists := riskStates[device]
+	if !exists {
+		state = &deviceRiskState{}
+		riskStates[device] = state
+	}
+
+	if n := len(state.history); n == 0 || now.Sub(state.history[n-1].at) >= riskHistoryResolution {
+		state.history = append(state.history, riskSample{at: now, score: score})
+	}
+	for len(state.history) > 1 && now.Sub(state.history[0].at) > riskHistoryWindow {
+		state.history = state.history[1:]
+	}
+
+	risk := &FailureRisk{
+		Score:         score,
+		Trend:         math.Round((score-state.history[0].score)*100) / 100,
+		Level:         riskLevel(score, cfg),
+		PreviousLevel: state.level,
+		Factors:       factors,
+	}
+	state.level = risk.Level
+
+	return risk
+}
+
+// riskEventMessage describes a threshold crossing for the NATS event.
+func riskEventMessage(risk *FailureRisk) string {
+	if risk.Level == RiskLevelOK {
+		return fmt.Sprintf("Disk failure risk score dropped to %.1f (was %s).", risk.Score, risk.PreviousLevel)
+	}
+	return fmt.Sprintf("Disk failure risk score %.1f reached %s level.", risk.Score, risk.Level)
+}
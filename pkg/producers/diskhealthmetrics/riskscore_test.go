@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRiskConfig = DiskHealthMetricsConfig{RiskWarningThreshold: 40, RiskCriticalThreshold: 70}
+
+func TestCalculateRiskScore_Healthy(t *testing.T) {
+	score, factors := calculateRiskScore(NormalizedSmartData{Attributes: map[string]SmartAttribute{}})
+	assert.Equal(t, 0.0, score)
+	assert.Len(t, factors, len(riskFactors))
+}
+
+func TestCalculateRiskScore_CombinesFactors(t *testing.T) {
+	pending := int64(10)
+	single, _ := calculateRiskScore(NormalizedSmartData{PendingSectors: &pending})
+
+	combined, factors := calculateRiskScore(NormalizedSmartData{
+		PendingSectors: &pending,
+		Attributes: map[string]SmartAttribute{
+			"reported_uncorrect": {RawValue: 5},
+		},
+	})
+
+	assert.Greater(t, single, 40.0)
+	assert.Greater(t, combined, single)
+	assert.LessOrEqual(t, combined, 100.0)
+	assert.Greater(t, factors["media_errors"], 0.0)
+	assert.Equal(t, 0.0, factors["wear_level"])
+}
+
+func TestTrackFailureRisk_TrendAndCrossing(t *testing.T) {
+	device := "/dev/test-risk"
+	t.Cleanup(func() {
+		riskStatesMutex.Lock()
+		delete(riskStates, device)
+		riskStatesMutex.Unlock()
+	})
+
+	start := time.Now()
+	first := trackFailureRisk(device, 10, nil, testRiskConfig, start)
+	assert.Equal(t, RiskLevelOK, first.Level)
+	assert.False(t, first.LevelChanged())
+
+	second := trackFailureRisk(device, 55, nil, testRiskConfig, start.Add(2*time.Hour))
+	assert.Equal(t, RiskLevelWarning, second.Level)
+	assert.True(t, second.LevelChanged())
+	assert.Equal(t, 45.0, second.Trend)
+
+	third := trackFailureRisk(device, 56, nil, testRiskConfig, start.Add(2*time.Hour+time.Minute))
+	assert.False(t, third.LevelChanged())
+
+	// Samples older than the trend window are dropped.
+	fourth := trackFailureRisk(device, 75, nil, testRiskConfig, start.Add(25*time.Hour))
+	assert.Equal(t, RiskLevelCritical, fourth.Level)
+	assert.Equal(t, 20.0, fourth.Trend)
+}
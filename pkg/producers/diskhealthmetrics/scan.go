@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics")
+
+// Reasons reported in the reason label of disk_collection_errors_total.
+const (
+	CollectionErrorFailed  = "error"   // smartctl failed or returned invalid output
+	CollectionErrorTimeout = "timeout" // the device did not answer within the device timeout
+	CollectionErrorPanic   = "panic"   // parsing the device's data panicked
+	CollectionErrorBusy    = "busy"    // the previous collection of the device is still hanging
+)
+
+// collectFunc collects one target and returns its data and device identity.
+type collectFunc func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error)
+
+// scanResult is the outcome of collecting one target. A result without
+// metric and error belongs to a target that was not started because the
+// scan was canceled.
+type scanResult struct {
+	metric   *NormalizedSmartData
+	identity string
+	duration time.Duration
+	err      error
+	reason   string // CollectionError* reason, empty if the scan was canceled
+}
+
+// Targets whose collection has not returned yet. A hung smartctl is not
+// started again for the same device until the previous one finished.
+var (
+	runningCollections      = make(map[diskTarget]bool)
+	runningCollectionsMutex sync.Mutex
+)
+
+// scanTargets collects the targets on at most concurrency workers. Every
+// target gets its own timeout, and a target that hangs or panics is reported
+// as failed without holding up the others. Results are in target order.
+// The scan is traced with a child span per target.
+func scanTargets(ctx context.Context, targets []diskTarget, concurrency int, timeout time.Duration, collect collectFunc) []scanResult {
+	ctx, span := tracer.Start(ctx, "diskhealth.scan", trace.WithAttributes(
+		attribute.Int("disk.targets", len(targets)),
+		attribute.Int("disk.concurrency", concurrency),
+	))
+	defer span.End()
+
+	results := make([]scanResult, len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(concurrency, 1))
+
+schedule:
+	for i, target := range targets {
+		select {
+		case sem <- struct{}{}: // Acquire a worker slot
+		case <-ctx.Done():
+			break schedule
+		}
+
+		wg.Add(1)
+		go func(i int, target diskTarget) {
+			defer wg.Done()
+			defer func() { <-sem }() // Release the slot when done
+
+			results[i] = collectWithTimeout(ctx, target, timeout, collect)
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// collectWithTimeout runs collect in its own goroutine so that a call that
+// ignores the context (e.g. smartctl stuck in uninterruptible I/O) or panics
+// only affects this target.
+func collectWithTimeout(ctx context.Context, target diskTarget, timeout time.Duration, collect collectFunc) (result scanResult) {
+	ctx, span := tracer.Start(ctx, "diskhealth.device", trace.WithAttributes(
+		attribute.String("disk", target.path),
+		attribute.String("disk.device_type", target.deviceType),
+	))
+	defer func() {
+		if result.reason != "" {
+			span.SetAttributes(attribute.String("disk.error_reason", result.reason))
+		}
+		tracing.End(span, result.err)
+	}()
+
+	runningCollectionsMutex.Lock()
+	if runningCollections[target] {
+		runningCollectionsMutex.Unlock()
+		return scanResult{err: errors.New("previous collection of the device has not finished"), reason: CollectionErrorBusy}
+	}
+	runningCollections[target] = true
+	runningCollectionsMutex.Unlock()
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan scanResult, 1)
+	go func() {
+		defer func() {
+			runningCollectionsMutex.Lock()
+			delete(runningCollections, target)
+			runningCollectionsMutex.Unlock()
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				telemetry.ReportPanic("disk-health.collect", r)
+				log.Error().Str("disk", target.path).Msgf("panic while collecting disk: %v", r)
+				done <- scanResult{err: fmt.Errorf("panic: %v", r), reason: CollectionErrorPanic}
+			}
+		}()
+
+		metric, identity, err := collect(ctx, target)
+		result := scanResult{metric: metric, identity: identity, err: err}
+		if err != nil {
+			result.reason = collectionErrorReason(ctx)
+		}
+		done <- result
+	}()
+
+	select {
+	case result := <-done:
+		result.duration = time.Since(start)
+		return result
+	case <-ctx.Done():
+		return scanResult{
+			err:      fmt.Errorf("collection aborted: %w", ctx.Err()),
+			reason:   collectionErrorReason(ctx),
+			duration: time.Since(start),
+		}
+	}
+}
+
+// collectionErrorReason tells a timeout from a canceled scan and other errors.
+func collectionErrorReason(ctx context.Context) string {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return CollectionErrorTimeout
+	case ctx.Err() != nil:
+		return "" // scan canceled, not a device error
+	default:
+		return CollectionErrorFailed
+	}
+}
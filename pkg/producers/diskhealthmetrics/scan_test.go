@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestScanTargets_IsolatesFailures(t *testing.T) {
+	targets := []diskTarget{
+		{path: "/dev/sda"},
+		{path: "/dev/sdb"}, // hangs
+		{path: "/dev/sdc"}, // panics
+		{path: "/dev/sdd"}, // smartctl error
+		{path: "/dev/bus/0", deviceType: "megaraid,1"},
+	}
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	collect := func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error) {
+		switch target.path {
+		case "/dev/sdb":
+			<-release // ignores the context like smartctl in uninterruptible I/O
+			return nil, "", nil
+		case "/dev/sdc":
+			panic("unexpected smartctl output")
+		case "/dev/sdd":
+			return nil, "", errors.New("exit status 2")
+		}
+		return &NormalizedSmartData{Device: target.path}, target.path + "-id", nil
+	}
+
+	start := time.Now()
+	results := scanTargets(context.Background(), targets, 2, 50*time.Millisecond, collect)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, results, len(targets))
+	require.NoError(t, results[0].err)
+	assert.Equal(t, "/dev/sda", results[0].metric.Device)
+	assert.Equal(t, "/dev/sda-id", results[0].identity)
+	assert.Equal(t, CollectionErrorTimeout, results[1].reason)
+	assert.Equal(t, CollectionErrorPanic, results[2].reason)
+	assert.Equal(t, CollectionErrorFailed, results[3].reason)
+	require.NoError(t, results[4].err)
+	assert.Equal(t, "/dev/bus/0", results[4].metric.Device)
+
+	// The hung collection is still running and is not started again.
+	results = scanTargets(context.Background(), targets[1:2], 1, 50*time.Millisecond, collect)
+	assert.Equal(t, CollectionErrorBusy, results[0].reason)
+}
+
+func TestScanTargets_BoundsConcurrency(t *testing.T) {
+	targets := make([]diskTarget, 20)
+	for i := range targets {
+		targets[i] = diskTarget{path: "/dev/concurrency", deviceType: string(rune('a' + i))}
+	}
+
+	var running, peak atomic.Int32
+	collect := func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return &NormalizedSmartData{}, "", nil
+	}
+
+	results := scanTargets(context.Background(), targets, 4, time.Second, collect)
+	assert.Len(t, results, len(targets))
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+}
+
+func TestScanTargets_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	collect := func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error) {
+		return &NormalizedSmartData{}, "", nil
+	}
+	results := scanTargets(ctx, []diskTarget{{path: "/dev/canceled"}}, 1, time.Second, collect)
+
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].reason, "cancellation is not a device error")
+}
+
+func TestScanTargets_Traced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	targets := []diskTarget{{path: "/dev/sde"}, {path: "/dev/sdf"}}
+	collect := func(ctx context.Context, target diskTarget) (*NormalizedSmartData, string, error) {
+		if target.path == "/dev/sdf" {
+			return nil, "", errors.New("exit status 2")
+		}
+		return &NormalizedSmartData{Device: target.path}, target.path, nil
+	}
+	scanTargets(context.Background(), targets, 1, time.Second, collect)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	scan := spans[2]
+	assert.Equal(t, "diskhealth.scan", scan.Name())
+	for _, device := range spans[:2] {
+		assert.Equal(t, "diskhealth.device", device.Name())
+		assert.Equal(t, scan.SpanContext().SpanID(), device.Parent().SpanID())
+		if slices.Contains(device.Attributes(), attribute.String("disk", "/dev/sdf")) {
+			assert.Equal(t, codes.Error, device.Status().Code)
+			assert.Contains(t, device.Attributes(), attribute.String("disk.error_reason", CollectionErrorFailed))
+		} else {
+			assert.Equal(t, codes.Unset, device.Status().Code)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// scanMediaTypes are the media types a scan schedule can match.
+var scanMediaTypes = map[string]bool{"hdd": true, "ssd": true, "nvme": true}
+
+// ScanSchedule is the interval the devices matching Match are scanned at.
+// Match is a media type (hdd, ssd or nvme) or a glob of the device path or
+// its kernel name, e.g. /dev/sd[a-d] or nvme*.
+type ScanSchedule struct {
+	Match    string
+	Interval time.Duration
+}
+
+// ParseScanSchedules parses "match=interval" pairs separated by commas, e.g.
+// "nvme=1m,hdd=30m,/dev/sdz=6h".
+func ParseScanSchedules(spec string) ([]ScanSchedule, error) {
+	var schedules []ScanSchedule
+	if strings.TrimSpace(spec) == "" {
+		return schedules, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		match, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		match = strings.TrimSpace(match)
+		if !ok || match == "" {
+			return nil, fmt.Errorf("invalid scan schedule %q, expected match=interval", entry)
+		}
+		if _, err := path.Match(match, ""); err != nil {
+			return nil, fmt.Errorf("invalid device glob in %q: %w", entry, err)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in %q: %w", entry, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval must be positive in %q", entry)
+		}
+		if scanMediaTypes[strings.ToLower(match)] {
+			match = strings.ToLower(match)
+		}
+
+		schedules = append(schedules, ScanSchedule{Match: match, Interval: interval})
+	}
+
+	return schedules, nil
+}
+
+// scanSchedule tracks when every target was scanned and keeps its last
+// result, so a scan only collects the targets that are due and reports the
+// others as last seen. A target is due once its interval elapsed, less half
+// a tick so that it is not pushed to the next tick by the scan time.
+type scanSchedule struct {
+	schedules []ScanSchedule
+	interval  time.Duration // of the targets no schedule matches
+	tick      time.Duration // the shortest interval, the scan runs at
+
+	lastScan map[diskTarget]time.Time
+	media    map[diskTarget]string
+	results  map[diskTarget]scanResult
+}
+
+// newScanSchedule returns the schedule of cfg, nil without ScanSchedules:
+// then every scan collects all targets.
+func newScanSchedule(cfg DiskHealthMetricsConfig) *scanSchedule {
+	if len(cfg.ScanSchedules) == 0 {
+		return nil
+	}
+
+	s := &scanSchedule{
+		schedules: cfg.ScanSchedules,
+		interval:  time.Duration(cfg.Interval) * time.Second,
+		lastScan:  make(map[diskTarget]time.Time),
+		media:     make(map[diskTarget]string),
+		results:   make(map[diskTarget]scanResult),
+	}
+	s.tick = s.interval
+	for _, schedule := range s.schedules {
+		s.tick = min(s.tick, schedule.Interval)
+	}
+	return s
+}
+
+// intervalOf is the interval of the first schedule matching the target by
+// device glob, else by the media type of its last scan, else the default.
+func (s *scanSchedule) intervalOf(target diskTarget) time.Duration {
+	for _, schedule := range s.schedules {
+		if scanMediaTypes[schedule.Match] {
+			continue
+		}
+		if ok, _ := path.Match(schedule.Match, target.path); ok {
+			return schedule.Interval
+		}
+		if ok, _ := path.Match(schedule.Match, kernelName(target.path)); ok {
+			return schedule.Interval
+		}
+	}
+	if media := s.media[target]; media != "" {
+		for _, schedule := range s.schedules {
+			if schedule.Match == media {
+				return schedule.Interval
+			}
+		}
+	}
+	return s.interval
+}
+
+// due returns the targets to collect at now, in target order. Targets that
+// were never scanned are always due.
+func (s *scanSchedule) due(targets []diskTarget, now time.Time) []diskTarget {
+	var due []diskTarget
+	for _, target := range targets {
+		last, scanned := s.lastScan[target]
+		if !scanned || now.Sub(last) >= s.intervalOf(target)-s.tick/2 {
+			due = append(due, target)
+		}
+	}
+	return due
+}
+
+// record keeps the results of a scan at now. Targets not started because
+// the scan was canceled stay due.
+func (s *scanSchedule) record(targets []diskTarget, results []scanResult, now time.Time) {
+	for i, result := range results {
+		if result.metric == nil && result.err == nil {
+			continue
+		}
+		target := targets[i]
+		s.lastScan[target] = now
+		s.results[target] = result
+		if result.metric != nil && result.metric.DeviceInfo != nil {
+			s.media[target] = result.metric.DeviceInfo.Media
+		}
+	}
+}
+
+// latest returns the last result of every target, in target order, and
+// forgets the targets that are gone.
+func (s *scanSchedule) latest(targets []diskTarget) []scanResult {
+	current := make(map[diskTarget]bool, len(targets))
+	results := make([]scanResult, len(targets))
+	for i, target := range targets {
+		current[target] = true
+		results[i] = s.results[target]
+	}
+
+	for target := range s.lastScan {
+		if !current[target] {
+			delete(s.lastScan, target)
+			delete(s.media, target)
+			delete(s.results, target)
+		}
+	}
+	return results
+}
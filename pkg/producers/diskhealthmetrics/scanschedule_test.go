@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScanSchedules(t *testing.T) {
+	schedules, err := ParseScanSchedules("NVMe=1m, hdd=1h ,/dev/sd[y-z]=6h")
+	require.NoError(t, err)
+	assert.Equal(t, []ScanSchedule{
+		{Match: "nvme", Interval: time.Minute},
+		{Match: "hdd", Interval: time.Hour},
+		{Match: "/dev/sd[y-z]", Interval: 6 * time.Hour},
+	}, schedules)
+
+	schedules, err = ParseScanSchedules("")
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	for _, spec := range []string{"nvme", "=1m", "nvme=fast", "nvme=0s", "/dev/sd[=1m"} {
+		_, err := ParseScanSchedules(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScanSchedule_Due(t *testing.T) {
+	schedule := newScanSchedule(DiskHealthMetricsConfig{
+		Interval:      600,
+		ScanSchedules: []ScanSchedule{{Match: "nvme", Interval: time.Minute}, {Match: "sdz", Interval: time.Hour}},
+	})
+	require.NotNil(t, schedule)
+	assert.Equal(t, time.Minute, schedule.tick)
+
+	nvme := diskTarget{path: "/dev/nvme0n1"}
+	hdd := diskTarget{path: "/dev/sda"}
+	spare := diskTarget{path: "/dev/sdz"}
+	targets := []diskTarget{nvme, hdd, spare}
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, targets, schedule.due(targets, start), "never scanned targets are due")
+	schedule.record(targets, []scanResult{
+		{metric: &NormalizedSmartData{Device: "/dev/nvme0n1", DeviceInfo: &DeviceInfo{Media: "nvme"}}},
+		{metric: &NormalizedSmartData{Device: "/dev/sda", DeviceInfo: &DeviceInfo{Media: "hdd"}}},
+		{metric: &NormalizedSmartData{Device: "/dev/sdz", DeviceInfo: &DeviceInfo{Media: "hdd"}}},
+	}, start)
+
+	// A tick arriving a little early still scans the NVMe drive
+	assert.Equal(t, []diskTarget{nvme}, schedule.due(targets, start.Add(59*time.Second)))
+	assert.Equal(t, []diskTarget{nvme, hdd}, schedule.due(targets, start.Add(10*time.Minute)))
+	assert.Equal(t, targets, schedule.due(targets, start.Add(time.Hour)))
+}
+
+func TestScanSchedule_Latest(t *testing.T) {
+	schedule := newScanSchedule(DiskHealthMetricsConfig{Interval: 60, ScanSchedules: []ScanSchedule{{Match: "hdd", Interval: time.Hour}}})
+	sda := diskTarget{path: "/dev/sda"}
+	sdb := diskTarget{path: "/dev/sdb"}
+	start := time.Now()
+
+	schedule.record([]diskTarget{sda, sdb}, []scanResult{
+		{metric: &NormalizedSmartData{Device: "/dev/sda", DeviceInfo: &DeviceInfo{Media: "hdd"}}},
+		{err: errors.New("smartctl failed"), reason: CollectionErrorFailed},
+	}, start)
+	// The second scan only collects sdb, sda is reported as of the first
+	schedule.record([]diskTarget{sdb}, []scanResult{
+		{metric: &NormalizedSmartData{Device: "/dev/sdb", DeviceInfo: &DeviceInfo{Media: "ssd"}}},
+	}, start.Add(time.Minute))
+
+	results := schedule.latest([]diskTarget{sda, sdb})
+	require.Len(t, results, 2)
+	assert.Equal(t, "/dev/sda", results[0].metric.Device)
+	assert.Equal(t, "/dev/sdb", results[1].metric.Device)
+
+	// Removed targets are forgotten and scanned again when they come back
+	schedule.latest([]diskTarget{sdb})
+	assert.Equal(t, []diskTarget{sda}, schedule.due([]diskTarget{sda}, start.Add(2*time.Minute)))
+}
+
+func TestNewScanSchedule_WithoutSchedules(t *testing.T) {
+	assert.Nil(t, newScanSchedule(DiskHealthMetricsConfig{Interval: 10}))
+}
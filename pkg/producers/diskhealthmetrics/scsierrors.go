@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+// SCSI error counter log operations reported in SCSIErrorCounters.Errors.
+const (
+	SCSIOperationRead   = "read"
+	SCSIOperationWrite  = "write"
+	SCSIOperationVerify = "verify"
+)
+
+// SCSIErrorCounters are the grown defect list and the error counter log of a
+// SCSI/SAS drive. All values are cumulative over the life of the drive.
+type SCSIErrorCounters struct {
+	GrownDefects int64                     `json:"grown_defects"`    // entries in the grown defect list
+	Errors       map[string]SCSIErrorCount `json:"errors,omitempty"` // by operation, empty if the drive has no error counter log
+}
+
+// SCSIErrorCount are the errors of one operation in the error counter log.
+type SCSIErrorCount struct {
+	Corrected   int64 `json:"corrected"`   // errors corrected by ECC or retries
+	Uncorrected int64 `json:"uncorrected"` // errors that reached the host
+}
+
+// scsiErrorCounters extracts the grown defect list and error counter log
+// from the smartctl output of a SCSI drive, nil for other protocols.
+func scsiErrorCounters(smartData *SmartCtlOutput) *SCSIErrorCounters {
+	if smartData.Device.Protocol != "SCSI" {
+		return nil
+	}
+
+	counters := &SCSIErrorCounters{GrownDefects: smartData.SCSIGrownDefectList}
+	if errorLog := smartData.SCSIErrorCounterLog; errorLog != nil {
+		counters.Errors = map[string]SCSIErrorCount{
+			SCSIOperationRead:   {Corrected: errorLog.Read.TotalErrorsCorrected, Uncorrected: errorLog.Read.TotalUncorrectedErrors},
+			SCSIOperationWrite:  {Corrected: errorLog.Write.TotalErrorsCorrected, Uncorrected: errorLog.Write.TotalUncorrectedErrors},
+			SCSIOperationVerify: {Corrected: errorLog.Verify.TotalErrorsCorrected, Uncorrected: errorLog.Verify.TotalUncorrectedErrors},
+		}
+	}
+	return counters
+}
+
+// counterIncrease returns the increase of a cumulative drive counter since
+// the previous collection. The first value seen counts in full, and a value
+// below the previous one means the counter was reset (log cleared or drive
+// replaced) and counts from zero.
+func counterIncrease(previous int64, seen bool, current int64) int64 {
+	if !seen || current < previous {
+		return max(current, 0)
+	}
+	return current - previous
+}
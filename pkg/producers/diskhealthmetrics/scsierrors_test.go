@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCSIErrorCounters(t *testing.T) {
+	data, err := os.ReadFile("testdata/scenarios/failing/sdb.json")
+	require.NoError(t, err)
+	var output SmartCtlOutput
+	require.NoError(t, json.Unmarshal(data, &output))
+
+	counters := scsiErrorCounters(&output)
+	require.NotNil(t, counters)
+	assert.Equal(t, int64(1250), counters.GrownDefects)
+	assert.Equal(t, SCSIErrorCount{Corrected: 23745, Uncorrected: 567}, counters.Errors[SCSIOperationRead])
+	assert.Equal(t, SCSIErrorCount{Corrected: 9940, Uncorrected: 234}, counters.Errors[SCSIOperationWrite])
+	assert.Equal(t, SCSIErrorCount{Corrected: 800, Uncorrected: 45}, counters.Errors[SCSIOperationVerify])
+
+	// Without an error counter log only the grown defects are known.
+	output.SCSIErrorCounterLog = nil
+	assert.Empty(t, scsiErrorCounters(&output).Errors)
+
+	output.Device.Protocol = "ATA"
+	assert.Nil(t, scsiErrorCounters(&output))
+}
+
+func TestCounterIncrease(t *testing.T) {
+	assert.Equal(t, int64(12), counterIncrease(0, false, 12), "the first value counts in full")
+	assert.Equal(t, int64(3), counterIncrease(12, true, 15))
+	assert.Equal(t, int64(0), counterIncrease(15, true, 15))
+	assert.Equal(t, int64(2), counterIncrease(15, true, 2), "a reset counts from zero")
+}
@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Self-test types as used in the test_type label and the smartctl --test flag.
+const (
+	SelfTestShort = "short"
+	SelfTestLong  = "long"
+)
+
+// SelfTestStatus is the self-test state of a device as reported by smartctl.
+type SelfTestStatus struct {
+	Supported        bool                      `json:"supported"`
+	InProgress       bool                      `json:"in_progress"`
+	RemainingPercent int64                     `json:"remaining_percent"`
+	Results          map[string]SelfTestResult `json:"results,omitempty"` // most recent result per test type
+}
+
+// SelfTestResult is the most recent completed self-test of one type.
+type SelfTestResult struct {
+	Passed   bool   `json:"passed"`
+	Status   string `json:"status"`
+	AgeHours int64  `json:"age_hours"` // power-on hours since the test ran
+}
+
+// collectSelfTestStatus reads the self-test capabilities and log using smartctl --json --capabilities --log=selftest
+func collectSelfTestStatus(ctx context.Context, devicePath, deviceType string) (*SelfTestStatus, error) {
+	args := []string{"--json", "--capabilities", "--log=selftest", "--nocheck=standby"}
+	if deviceType != "" {
+		args = append(args, "--device="+deviceType)
+	}
+
+	out, err := smartctlCommand(ctx, append(args, devicePath)...)
+	if err != nil {
+		return nil, fmt.Errorf("error running smartctl self-test log: %v", err)
+	}
+
+	var selfTestOutput SmartCtlSelfTestOutput
+	if err := json.Unmarshal(out, &selfTestOutput); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	return parseSelfTestStatus(&selfTestOutput), nil
+}
+
+// parseSelfTestStatus normalizes the ATA and NVMe self-test logs. SCSI
+// devices are reported as unsupported.
+func parseSelfTestStatus(output *SmartCtlSelfTestOutput) *SelfTestStatus {
+	status := &SelfTestStatus{Results: make(map[string]SelfTestResult)}
+	powerOnHours := output.PowerOnTime.Hours
+
+	if output.ATASmartData != nil {
+		status.Supported = output.ATASmartData.Capabilities.SelfTestsSupported
+
+		// Execution status 0xF_ means a test is running, the low nibble is
+		// the remaining work in tens of percent.
+		current := output.ATASmartData.SelfTest.Status
+		if current.Value>>4 == 0xF {
+			status.InProgress = true
+			status.RemainingPercent = (current.Value & 0xF) * 10
+			if current.RemainingPercent != nil {
+				status.RemainingPercent = *current.RemainingPercent
+			}
+		}
+
+		if output.ATASelfTestLog != nil {
+			// The log is ordered newest first.
+			for _, entry := range output.ATASelfTestLog.Standard.Table {
+				testType := ataSelfTestType(entry.Type.String)
+				if testType == "" {
+					continue
+				}
+				if _, seen := status.Results[testType]; seen {
+					continue
+				}
+				status.Results[testType] = SelfTestResult{
+					Passed:   entry.Status.Passed != nil && *entry.Status.Passed,
+					Status:   entry.Status.String,
+					AgeHours: max(powerOnHours-entry.LifetimeHours, 0),
+				}
+			}
+		}
+	}
+
+	if output.NVMeSelfTestLog != nil {
+		status.Supported = true
+		if output.NVMeSelfTestLog.CurrentSelfTestOperation.Value != 0 {
+			status.InProgress = true
+			status.RemainingPercent = 100 - output.NVMeSelfTestLog.CurrentSelfTestCompletionPercent
+		}
+
+		for _, entry := range output.NVMeSelfTestLog.Table {
+			var testType string
+			switch entry.SelfTestCode.Value {
+			case 0x1:
+				testType = SelfTestShort
+			case 0x2:
+				testType = SelfTestLong
+			default:
+				continue
+			}
+			if _, seen := status.Results[testType]; seen {
+				continue
+			}
+			status.Results[testType] = SelfTestResult{
+				Passed:   entry.SelfTestResult.Value == 0,
+				Status:   entry.SelfTestResult.String,
+				AgeHours: max(powerOnHours-entry.PowerOnHours, 0),
+			}
+		}
+	}
+
+	return status
+}
+
+func ataSelfTestType(description string) string {
+	switch {
+	case strings.HasPrefix(description, "Short"):
+		return SelfTestShort
+	case strings.HasPrefix(description, "Extended"):
+		return SelfTestLong
+	default:
+		return ""
+	}
+}
+
+// startSelfTest triggers a self-test using smartctl --test
+func startSelfTest(devicePath, deviceType, testType string) error {
+	args := []string{"--test=" + testType}
+	if deviceType != "" {
+		args = append(args, "--device="+deviceType)
+	}
+	if _, err := smartctlCommand(context.Background(), append(args, devicePath)...); err != nil {
+		return fmt.Errorf("error starting %s self-test: %v", testType, err)
+	}
+	return nil
+}
+
+// selfTestWindow is a daily time-of-day window in local time. A window whose
+// end lies before its start wraps around midnight.
+type selfTestWindow struct {
+	always     bool
+	start, end int // minutes after midnight
+}
+
+// parseSelfTestWindow parses "HH:MM-HH:MM". An empty string allows
+// self-tests at any time.
+func parseSelfTestWindow(s string) (selfTestWindow, error) {
+	if s == "" {
+		return selfTestWindow{always: true}, nil
+	}
+
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return selfTestWindow{}, fmt.Errorf("invalid self-test window %q, expected HH:MM-HH:MM", s)
+	}
+
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return selfTestWindow{}, fmt.Errorf("invalid self-test window start %q: %w", from, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return selfTestWindow{}, fmt.Errorf("invalid self-test window end %q: %w", to, err)
+	}
+
+	return selfTestWindow{
+		start: start.Hour()*60 + start.Minute(),
+		end:   end.Hour()*60 + end.Minute(),
+	}, nil
+}
+
+// ValidateSelfTestWindow fails unless the window is empty or "HH:MM-HH:MM"
+func ValidateSelfTestWindow(s string) error {
+	_, err := parseSelfTestWindow(s)
+	return err
+}
+
+func (w selfTestWindow) contains(t time.Time) bool {
+	if w.always {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// selfTestScheduler starts SMART self-tests within the configured window,
+// one device at a time with at least the configured stagger in between so
+// that tests do not hit all OSDs of a node at once. The passthrough devices
+// behind a RAID controller are tested with their device type.
+type selfTestScheduler struct {
+	targets   []diskTarget
+	intervals map[string]time.Duration
+	window    selfTestWindow
+	stagger   time.Duration
+
+	lastStart time.Time
+	started   map[diskTarget]map[string]time.Time // device -> test type -> start time
+}
+
+func newSelfTestScheduler(cfg DiskHealthMetricsConfig) (*selfTestScheduler, error) {
+	window, err := parseSelfTestWindow(cfg.SelfTestWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[string]time.Duration)
+	if cfg.SelfTestShortIntervalHours > 0 {
+		intervals[SelfTestShort] = time.Duration(cfg.SelfTestShortIntervalHours) * time.Hour
+	}
+	if cfg.SelfTestLongIntervalHours > 0 {
+		intervals[SelfTestLong] = time.Duration(cfg.SelfTestLongIntervalHours) * time.Hour
+	}
+
+	return &selfTestScheduler{
+		targets:   collectionTargets(cfg),
+		intervals: intervals,
+		window:    window,
+		stagger:   time.Duration(cfg.SelfTestStaggerMinutes) * time.Minute,
+		started:   make(map[diskTarget]map[string]time.Time),
+	}, nil
+}
+
+// RunSelfTestScheduler checks every minute whether a self-test is due and
+// starts at most one per stagger period.
+func RunSelfTestScheduler(cfg DiskHealthMetricsConfig) {
+	scheduler, err := newSelfTestScheduler(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid self-test configuration")
+	}
+
+	log.Info().
+		Str("window", cfg.SelfTestWindow).
+		Int("short_interval_hours", cfg.SelfTestShortIntervalHours).
+		Int("long_interval_hours", cfg.SelfTestLongIntervalHours).
+		Int("stagger_minutes", cfg.SelfTestStaggerMinutes).
+		Msg("self-test scheduler started")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		scheduler.tick(now, func(target diskTarget) (*SelfTestStatus, error) {
+			return collectSelfTestStatus(context.Background(), target.path, target.deviceType)
+		}, func(target diskTarget, testType string) error {
+			return startSelfTest(target.path, target.deviceType, testType)
+		})
+	}
+}
+
+func (s *selfTestScheduler) tick(now time.Time, statusFn func(diskTarget) (*SelfTestStatus, error), startFn func(diskTarget, string) error) {
+	if !s.window.contains(now) || now.Sub(s.lastStart) < s.stagger {
+		return
+	}
+
+	for _, target := range s.targets {
+		logger := log.With().Str("disk", target.path).Str("device_type", target.deviceType).Logger()
+		status, err := statusFn(target)
+		if err != nil {
+			logger.Debug().Err(err).Msg("failed to read self-test status")
+			continue
+		}
+		if !status.Supported || status.InProgress {
+			continue
+		}
+
+		testType := s.dueTest(target, status, now)
+		if testType == "" {
+			continue
+		}
+
+		if err := startFn(target, testType); err != nil {
+			logger.Error().Err(err).Msg("failed to start self-test")
+			continue
+		}
+		logger.Info().Str("test_type", testType).Msg("self-test started")
+
+		if s.started[target] == nil {
+			s.started[target] = make(map[string]time.Time)
+		}
+		s.started[target][testType] = now
+		s.lastStart = now
+		return
+	}
+}
+
+// dueTest returns the test type that is due for the target, preferring the long
+// test since it also covers the short one. The last run is taken from the
+// device's self-test log, so schedules survive restarts of the producer;
+// log ages are in power-on hours, which match wall time for always-on drives.
+func (s *selfTestScheduler) dueTest(target diskTarget, status *SelfTestStatus, now time.Time) string {
+	lastRun := make(map[string]time.Time)
+	for _, testType := range []string{SelfTestLong, SelfTestShort} {
+		lastRun[testType] = s.started[target][testType]
+		if result, ok := status.Results[testType]; ok {
+			if logged := now.Add(-time.Duration(result.AgeHours) * time.Hour); logged.After(lastRun[testType]) {
+				lastRun[testType] = logged
+			}
+		}
+	}
+	if lastRun[SelfTestLong].After(lastRun[SelfTestShort]) {
+		lastRun[SelfTestShort] = lastRun[SelfTestLong]
+	}
+
+	for _, testType := range []string{SelfTestLong, SelfTestShort} {
+		interval, enabled := s.intervals[testType]
+		if !enabled {
+			continue
+		}
+		if lastRun[testType].IsZero() || now.Sub(lastRun[testType]) >= interval {
+			return testType
+		}
+	}
+	return ""
+}
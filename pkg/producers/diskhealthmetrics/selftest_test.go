@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelfTestStatus_ATA(t *testing.T) {
+	out := []byte(`{
+		"power_on_time": {"hours": 5000},
+		"ata_smart_data": {
+			"self_test": {"status": {"value": 249, "string": "in progress, 90% remaining", "remaining_percent": 90}},
+			"capabilities": {"self_tests_supported": true}
+		},
+		"ata_smart_self_test_log": {"standard": {"table": [
+			{"type": {"value": 1, "string": "Short offline"}, "status": {"value": 0, "string": "Completed without error", "passed": true}, "lifetime_hours": 4990},
+			{"type": {"value": 2, "string": "Extended offline"}, "status": {"value": 121, "string": "Completed: read failure", "passed": false}, "lifetime_hours": 4800},
+			{"type": {"value": 1, "string": "Short offline"}, "status": {"value": 0, "string": "Completed without error", "passed": true}, "lifetime_hours": 4700}
+		]}}
+	}`)
+
+	var output SmartCtlSelfTestOutput
+	require.NoError(t, json.Unmarshal(out, &output))
+
+	status := parseSelfTestStatus(&output)
+	assert.True(t, status.Supported)
+	assert.True(t, status.InProgress)
+	assert.Equal(t, int64(90), status.RemainingPercent)
+	assert.Equal(t, SelfTestResult{Passed: true, Status: "Completed without error", AgeHours: 10}, status.Results[SelfTestShort])
+	assert.Equal(t, SelfTestResult{Passed: false, Status: "Completed: read failure", AgeHours: 200}, status.Results[SelfTestLong])
+}
+
+func TestParseSelfTestStatus_NVMe(t *testing.T) {
+	out := []byte(`{
+		"power_on_time": {"hours": 100},
+		"nvme_self_test_log": {
+			"current_self_test_operation": {"value": 0, "string": "No self-test in progress"},
+			"table": [
+				{"self_test_code": {"value": 2, "string": "Extended self-test"}, "self_test_result": {"value": 0, "string": "Completed without error"}, "power_on_hours": 90}
+			]
+		}
+	}`)
+
+	var output SmartCtlSelfTestOutput
+	require.NoError(t, json.Unmarshal(out, &output))
+
+	status := parseSelfTestStatus(&output)
+	assert.True(t, status.Supported)
+	assert.False(t, status.InProgress)
+	assert.Equal(t, SelfTestResult{Passed: true, Status: "Completed without error", AgeHours: 10}, status.Results[SelfTestLong])
+	assert.NotContains(t, status.Results, SelfTestShort)
+}
+
+func TestSelfTestWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 1, 1, hour, minute, 0, 0, time.Local) }
+
+	window, err := parseSelfTestWindow("22:30-04:00")
+	require.NoError(t, err)
+	assert.True(t, window.contains(at(23, 0)))
+	assert.True(t, window.contains(at(3, 59)))
+	assert.False(t, window.contains(at(4, 0)))
+	assert.False(t, window.contains(at(12, 0)))
+
+	always, err := parseSelfTestWindow("")
+	require.NoError(t, err)
+	assert.True(t, always.contains(at(12, 0)))
+
+	_, err = parseSelfTestWindow("01:00")
+	assert.Error(t, err)
+	assert.Error(t, ValidateSelfTestWindow("01:00-25:00"))
+	assert.NoError(t, ValidateSelfTestWindow("01:00-05:00"))
+}
+
+func TestSelfTestScheduler_StaggersAndPrefersLong(t *testing.T) {
+	scheduler, err := newSelfTestScheduler(DiskHealthMetricsConfig{
+		Disks:                      []string{"/dev/sda", "/dev/sdb"},
+		SelfTestShortIntervalHours: 24,
+		SelfTestLongIntervalHours:  168,
+		SelfTestStaggerMinutes:     30,
+	})
+	require.NoError(t, err)
+
+	statuses := map[string]*SelfTestStatus{
+		// Short test ran recently, long test is overdue.
+		"/dev/sda": {Supported: true, Results: map[string]SelfTestResult{
+			SelfTestShort: {Passed: true, AgeHours: 2},
+			SelfTestLong:  {Passed: true, AgeHours: 200},
+		}},
+		// Recent long test also satisfies the short schedule.
+		"/dev/sdb": {Supported: true, Results: map[string]SelfTestResult{
+			SelfTestShort: {Passed: true, AgeHours: 48},
+			SelfTestLong:  {Passed: true, AgeHours: 1},
+		}},
+	}
+	statusFn := func(target diskTarget) (*SelfTestStatus, error) { return statuses[target.path], nil }
+
+	var started []string
+	startFn := func(target diskTarget, testType string) error {
+		started = append(started, target.path+":"+testType)
+		return nil
+	}
+
+	now := time.Now()
+	scheduler.tick(now, statusFn, startFn)
+	assert.Equal(t, []string{"/dev/sda:long"}, started)
+
+	// Within the stagger period nothing else is started.
+	scheduler.tick(now.Add(10*time.Minute), statusFn, startFn)
+	assert.Len(t, started, 1)
+
+	// The long test was just started on sda, sdb has nothing due.
+	scheduler.tick(now.Add(31*time.Minute), statusFn, startFn)
+	assert.Len(t, started, 1)
+}
+
+func TestSelfTestScheduler_PassthroughDevices(t *testing.T) {
+	scheduler, err := newSelfTestScheduler(DiskHealthMetricsConfig{
+		Disks:                      []string{"/dev/sda"},
+		PassthroughDevices:         []PassthroughDevice{{Device: "/dev/bus/0", Type: "megaraid,3"}, {Device: "/dev/bus/0", Type: "megaraid,4"}},
+		SelfTestShortIntervalHours: 24,
+	})
+	require.NoError(t, err)
+
+	var started []diskTarget
+	statusFn := func(target diskTarget) (*SelfTestStatus, error) {
+		if target.deviceType == "" {
+			return &SelfTestStatus{Supported: false}, nil
+		}
+		return &SelfTestStatus{Supported: true}, nil
+	}
+	startFn := func(target diskTarget, testType string) error {
+		started = append(started, target)
+		return nil
+	}
+
+	now := time.Now()
+	scheduler.tick(now, statusFn, startFn)
+	scheduler.tick(now.Add(time.Minute), statusFn, startFn)
+	scheduler.tick(now.Add(2*time.Minute), statusFn, startFn)
+	assert.Equal(t, []diskTarget{
+		{path: "/dev/bus/0", deviceType: "megaraid,3"},
+		{path: "/dev/bus/0", deviceType: "megaraid,4"},
+	}, started, "the drives behind the controller are tested one by one")
+}
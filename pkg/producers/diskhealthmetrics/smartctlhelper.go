@@ -5,6 +5,7 @@
 package diskhealthmetrics
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,7 +13,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func checkSmartctlInstalled() bool {
@@ -23,7 +27,7 @@ func checkSmartctlInstalled() bool {
 // discoverDevices discovers all devices capable of SMART monitoring
 func discoverDevices() (*SmartCtlScanOutput, error) {
 	// Execute the smartctl command to scan for devices
-	out, err := exec.Command("smartctl", "--scan-open", "-j").Output()
+	out, err := smartctlCommand(context.Background(), "--scan-open", "-j")
 	if err != nil {
 		return nil, fmt.Errorf("error running smartctl --scan-open: %v", err)
 	}
@@ -33,14 +37,24 @@ func discoverDevices() (*SmartCtlScanOutput, error) {
 	if err := json.Unmarshal(out, &scanOutput); err != nil {
 		return nil, fmt.Errorf("error parsing JSON: %v", err)
 	}
+	scanOutput.Devices = hostPlatform.scannedDevices(scanOutput.Devices)
 
 	return &scanOutput, nil
 }
 
 // collectSmartData collects SMART data for a specific device using smartctl --json --info --health --attributes --tolerance=verypermissive --nocheck=standby --format=brief --log=error
-func collectSmartData(devicePath string) (*SmartCtlOutput, error) {
+// A non-empty deviceType is passed as --device, e.g. megaraid,3 for drives behind a RAID controller.
+func collectSmartData(ctx context.Context, devicePath, deviceType string) (_ *SmartCtlOutput, err error) {
+	ctx, span := tracer.Start(ctx, "smartctl", trace.WithAttributes(attribute.String("disk", devicePath)))
+	defer func() { tracing.End(span, err) }()
+
+	args := []string{"--json", "--info", "--health", "--attributes", "--tolerance=verypermissive", "--nocheck=standby", "--format=brief", "--log=error"}
+	if deviceType != "" {
+		args = append(args, "--device="+deviceType)
+	}
+
 	// Execute the smartctl command to get extended JSON output
-	out, err := exec.Command("smartctl", "--json", "--info", "--health", "--attributes", "--tolerance=verypermissive", "--nocheck=standby", "--format=brief", "--log=error", devicePath).Output()
+	out, err := smartctlCommand(ctx, append(args, devicePath)...)
 	if err != nil {
 		return nil, fmt.Errorf("error running smartctl: %v", err)
 	}
@@ -75,10 +89,10 @@ func collectSmartDataFromFile(filePath string) (*SmartCtlOutput, error) {
 // ####
 // ///
 
-func ProcessAndUpdateSmartAttributes(smartAttrs map[string]SmartAttribute, smartCtlOutput *SmartCtlOutput) {
+func ProcessAndUpdateSmartAttributes(smartAttrs map[string]SmartAttribute, smartCtlOutput *SmartCtlOutput, deviceInfo *DeviceInfo) {
 	// Process ATA-specific attributes
 	if smartCtlOutput.ATASMARTAttributes != nil {
-		ProcessAndUpdateATASmartAttributes(smartAttrs, smartCtlOutput)
+		ProcessAndUpdateATASmartAttributes(smartAttrs, smartCtlOutput, currentDeviceDB().AttributeInterpretations(deviceInfo))
 	}
 
 	// Process SCSI-specific attributes
@@ -88,11 +102,18 @@ func ProcessAndUpdateSmartAttributes(smartAttrs map[string]SmartAttribute, smart
 	ProcessAndUpdateNVMeSmartAttributes(smartAttrs, smartCtlOutput)
 }
 
-// Process and update ATA-specific SMART attributes
-func ProcessAndUpdateATASmartAttributes(smartAttrs map[string]SmartAttribute, smartCtlOutput *SmartCtlOutput) {
+// Process and update ATA-specific SMART attributes. Attributes with a
+// vendor-specific interpretation are identified by ID, all others by the
+// name smartctl reports.
+func ProcessAndUpdateATASmartAttributes(smartAttrs map[string]SmartAttribute, smartCtlOutput *SmartCtlOutput, interpretations map[int64]AttributeInterpretation) {
 	for _, entry := range smartCtlOutput.ATASMARTAttributes.Table {
 		// Normalize the attribute name and resolve using alias map
 		attrName := strings.ToLower(entry.Name)
+		rawValue := entry.Raw.Value
+		if interpretation, found := interpretations[entry.ID]; found {
+			attrName = interpretation.Name
+			rawValue = interpretation.value(entry)
+		}
 		if resolvedName, found := aliasMap[attrName]; found {
 			attrName = resolvedName
 		}
@@ -110,7 +131,7 @@ func ProcessAndUpdateATASmartAttributes(smartAttrs map[string]SmartAttribute, sm
 				attr.Value = entry.Value
 				attr.Worst = entry.Worst
 				attr.Threshold = entry.Thresh
-				attr.RawValue = entry.Raw.Value
+				attr.RawValue = rawValue
 			}
 			smartAttrs[attrName] = attr
 		} else {
@@ -201,6 +222,9 @@ func ProcessAndUpdateNVMeSmartAttributes(smartAttrs map[string]SmartAttribute, o
 	// Process host write commands
 	updateAttributeFromValue(smartAttrs, "host_write_commands", output.NVMeSmartHealthInfoLog.HostWrites, output.NVMeSmartHealthInfoLog.HostWrites, -1, -1, "commands")
 
+	// Process data units written, used for the endurance projection
+	updateAttributeFromValue(smartAttrs, "data_units_written", output.NVMeSmartHealthInfoLog.DataUnitsWritten, output.NVMeSmartHealthInfoLog.DataUnitsWritten, -1, -1, "512000 bytes")
+
 	// Process controller busy time
 	updateAttributeFromValue(smartAttrs, "controller_busy_time", output.NVMeSmartHealthInfoLog.ControllerBusyTime, output.NVMeSmartHealthInfoLog.ControllerBusyTime, -1, -1, "minutes")
 
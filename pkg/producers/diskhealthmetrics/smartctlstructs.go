@@ -364,3 +364,77 @@ type SmartCtlWWN struct {
 	NAA int64 `json:"naa"`
 	OUI int64 `json:"oui"`
 }
+
+// SmartCtlSelfTestOutput represents the JSON output from smartctl --json --capabilities --log=selftest
+type SmartCtlSelfTestOutput struct {
+	Device          SmartCtlDevice           `json:"device"`
+	PowerOnTime     SmartCtlPowerOnTime      `json:"power_on_time"`
+	ATASmartData    *SmartCtlATASmartData    `json:"ata_smart_data,omitempty"`
+	ATASelfTestLog  *SmartCtlATASelfTestLog  `json:"ata_smart_self_test_log,omitempty"`
+	NVMeSelfTestLog *SmartCtlNVMeSelfTestLog `json:"nvme_self_test_log,omitempty"`
+	Smartctl        SmartCtlDetails          `json:"smartctl"`
+}
+
+// SmartCtlValueString represents a numeric value with its textual description
+type SmartCtlValueString struct {
+	Value  int64  `json:"value"`
+	String string `json:"string"`
+}
+
+// SmartCtlATASmartData represents the ATA SMART data section
+type SmartCtlATASmartData struct {
+	SelfTest     SmartCtlATASelfTest     `json:"self_test"`
+	Capabilities SmartCtlATACapabilities `json:"capabilities"`
+}
+
+// SmartCtlATASelfTest represents the current ATA self-test execution status
+type SmartCtlATASelfTest struct {
+	Status         SmartCtlATASelfTestStatus `json:"status"`
+	PollingMinutes map[string]int64          `json:"polling_minutes,omitempty"`
+}
+
+// SmartCtlATASelfTestStatus represents the ATA self-test execution status
+type SmartCtlATASelfTestStatus struct {
+	Value            int64  `json:"value"`
+	String           string `json:"string"`
+	Passed           *bool  `json:"passed,omitempty"`
+	RemainingPercent *int64 `json:"remaining_percent,omitempty"`
+}
+
+// SmartCtlATACapabilities represents the ATA SMART capabilities
+type SmartCtlATACapabilities struct {
+	SelfTestsSupported bool `json:"self_tests_supported"`
+}
+
+// SmartCtlATASelfTestLog represents the ATA SMART self-test log
+type SmartCtlATASelfTestLog struct {
+	Standard SmartCtlATASelfTestTable `json:"standard"`
+}
+
+// SmartCtlATASelfTestTable represents the entries of the ATA SMART self-test log
+type SmartCtlATASelfTestTable struct {
+	Revision int64                      `json:"revision"`
+	Table    []SmartCtlATASelfTestEntry `json:"table"`
+	Count    int64                      `json:"count"`
+}
+
+// SmartCtlATASelfTestEntry represents a single ATA self-test log entry
+type SmartCtlATASelfTestEntry struct {
+	Type          SmartCtlValueString       `json:"type"`
+	Status        SmartCtlATASelfTestStatus `json:"status"`
+	LifetimeHours int64                     `json:"lifetime_hours"`
+}
+
+// SmartCtlNVMeSelfTestLog represents the NVMe self-test log
+type SmartCtlNVMeSelfTestLog struct {
+	CurrentSelfTestOperation         SmartCtlValueString         `json:"current_self_test_operation"`
+	CurrentSelfTestCompletionPercent int64                       `json:"current_self_test_completion_percent,omitempty"`
+	Table                            []SmartCtlNVMeSelfTestEntry `json:"table,omitempty"`
+}
+
+// SmartCtlNVMeSelfTestEntry represents a single NVMe self-test log entry
+type SmartCtlNVMeSelfTestEntry struct {
+	SelfTestCode   SmartCtlValueString `json:"self_test_code"`
+	SelfTestResult SmartCtlValueString `json:"self_test_result"`
+	PowerOnHours   int64               `json:"power_on_hours"`
+}
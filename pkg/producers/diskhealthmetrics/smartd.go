@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// smartdStatePrefix is the file name prefix of smartd state files, the
+// default of smartd --savestates is /var/lib/smartmontools/smartd.
+const smartdStatePrefix = "smartd."
+
+// errSmartdSelfTest is returned for self-test invocations, which are left
+// to smartd's own schedule in smartd mode.
+var errSmartdSelfTest = errors.New("self-tests are scheduled by smartd")
+
+// smartdFailurePattern matches the smartd log messages of a failing drive,
+// e.g. "Device: /dev/sda [SAT], FAILED SMART self-check. BACK UP DATA NOW!".
+var smartdFailurePattern = regexp.MustCompile(`Device: ([^,]+), (?:FAILED SMART self-check|SMART Failure)`)
+
+// smartdSource answers smartctl invocations from the state files smartd
+// writes with --savestates, so sites already running smartd do not poll
+// their drives twice. Only the identity of a device is read with
+// smartctl --info, once; its attributes come from the state file. smartd
+// stores attributes for ATA devices only, other devices fail to collect.
+//
+// State files do not record the SMART status: a device is reported as
+// failed once the smartd log, if configured, reports a failed self-check.
+type smartdSource struct {
+	stateDir string
+	smartctl func(ctx context.Context, args ...string) ([]byte, error)
+	log      *smartdLog
+
+	mu         sync.Mutex
+	identities map[string]*SmartCtlOutput // keyed by device path and type
+}
+
+// useSmartdState answers smartctl invocations from the smartd state files
+// in stateDir and the smartd log at logPath, which may be empty.
+func useSmartdState(stateDir, logPath string) error {
+	info, err := os.Stat(stateDir)
+	if err != nil {
+		return fmt.Errorf("failed to open smartd state directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("smartd state path %s is not a directory", stateDir)
+	}
+
+	source := &smartdSource{
+		stateDir:   stateDir,
+		smartctl:   smartctlCommand,
+		identities: make(map[string]*SmartCtlOutput),
+	}
+	if logPath != "" {
+		source.log = &smartdLog{path: logPath, failed: make(map[string]bool)}
+	}
+	smartctlCommand = source.run
+	return nil
+}
+
+func (s *smartdSource) run(ctx context.Context, args ...string) ([]byte, error) {
+	var devicePath, deviceType string
+	for _, arg := range args {
+		switch {
+		case arg == "--scan-open":
+			return s.smartctl(ctx, args...) // scanning does not read SMART data
+		case arg == "--log=selftest", strings.HasPrefix(arg, "--test="):
+			return nil, errSmartdSelfTest
+		case strings.HasPrefix(arg, "--device="):
+			deviceType = strings.TrimPrefix(arg, "--device=")
+		case !strings.HasPrefix(arg, "-"):
+			devicePath = arg
+		}
+	}
+
+	identity, err := s.identity(ctx, devicePath, deviceType)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Device.Protocol != "ATA" {
+		return nil, fmt.Errorf("smartd keeps no state for %s devices like %s", identity.Device.Protocol, devicePath)
+	}
+
+	model := identity.ModelName
+	if model == "" {
+		model = identity.DeviceModel
+	}
+	statePath := filepath.Join(s.stateDir, smartdStateFileName(model, identity.SerialNumber))
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		// The drive may have been replaced, identify it again next time.
+		s.forget(devicePath, deviceType)
+		return nil, fmt.Errorf("no smartd state for %s: %w", devicePath, err)
+	}
+	attributes, err := parseSmartdState(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse smartd state %s: %w", statePath, err)
+	}
+
+	output := *identity
+	output.ATASMARTAttributes = &SmartCtlATASMARTAttributes{Table: attributes}
+	for _, entry := range attributes {
+		switch entry.ID {
+		case 9:
+			output.PowerOnTime.Hours = entry.Raw.Value
+		case 190:
+			if output.Temperature.Current == 0 {
+				output.Temperature.Current = entry.Raw.Value & 0xff
+			}
+		case 194:
+			output.Temperature.Current = entry.Raw.Value & 0xff
+		}
+	}
+
+	name := devicePath
+	if deviceType != "" && identity.Device.InfoName != "" {
+		name = identity.Device.InfoName
+	}
+	output.SmartStatus.Passed = s.log == nil || !s.log.reportsFailure(name)
+
+	return json.Marshal(output)
+}
+
+// identity returns the smartctl --info output of the device, read once.
+func (s *smartdSource) identity(ctx context.Context, devicePath, deviceType string) (*SmartCtlOutput, error) {
+	key := devicePath + "|" + deviceType
+	s.mu.Lock()
+	identity, ok := s.identities[key]
+	s.mu.Unlock()
+	if ok {
+		return identity, nil
+	}
+
+	args := []string{"--json", "--info"}
+	if deviceType != "" {
+		args = append(args, "--device="+deviceType)
+	}
+	out, err := s.smartctl(ctx, append(args, devicePath)...)
+	if err != nil {
+		return nil, fmt.Errorf("error identifying %s: %w", devicePath, err)
+	}
+	identity = &SmartCtlOutput{}
+	if err := json.Unmarshal(out, identity); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	s.mu.Lock()
+	s.identities[key] = identity
+	s.mu.Unlock()
+	return identity, nil
+}
+
+func (s *smartdSource) forget(devicePath, deviceType string) {
+	s.mu.Lock()
+	delete(s.identities, devicePath+"|"+deviceType)
+	s.mu.Unlock()
+}
+
+// smartdStateFileName returns the name smartd saves the state of an ATA
+// device under: every character of model and serial number other than
+// letters and digits is replaced by an underscore.
+func smartdStateFileName(model, serial string) string {
+	sanitize := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if ('0' <= r && r <= '9') || ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z') {
+				return r
+			}
+			return '_'
+		}, strings.TrimSpace(s))
+	}
+	return smartdStatePrefix + sanitize(model) + "-" + sanitize(serial) + ".ata.state"
+}
+
+// parseSmartdState reads the ATA attributes of a smartd state file, lines
+// like "ata-smart-attribute.3.raw = 12" where 3 is the attribute's index.
+// Attribute names are not saved and are taken from SMARTAttributes.
+func parseSmartdState(data []byte) ([]SmartCtlATASMARTEntry, error) {
+	entries := make(map[int]*SmartCtlATASMARTEntry)
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		fields := strings.Split(key, ".")
+		if len(fields) != 3 || fields[0] != "ata-smart-attribute" {
+			continue // temperature-min, self-test-errors, ...
+		}
+		index, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute index in %q", line)
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in %q", line)
+		}
+
+		entry, ok := entries[index]
+		if !ok {
+			entry = &SmartCtlATASMARTEntry{}
+			entries[index] = entry
+		}
+		switch fields[2] {
+		case "id":
+			entry.ID = number
+		case "val":
+			entry.Value = number
+		case "worst":
+			entry.Worst = number
+		case "raw":
+			entry.Raw = SmartCtlATASMARTRaw{Value: number, String: value}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, 0, len(entries))
+	for index := range entries {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	attributes := make([]SmartCtlATASMARTEntry, 0, len(entries))
+	for _, index := range indexes {
+		entry := entries[index]
+		if entry.ID == 0 {
+			continue // unused slot
+		}
+		entry.Name = "Unknown_Attribute"
+		if attr, found := lookupSMARTAttribute(int(entry.ID)); found {
+			entry.Name = attr.Key
+		}
+		attributes = append(attributes, *entry)
+	}
+	return attributes, nil
+}
+
+// smartdLog follows the smartd log (e.g. /var/log/syslog) for failure
+// reports. Failures are kept until restart, a failing drive does not heal.
+type smartdLog struct {
+	path string
+
+	mu     sync.Mutex
+	offset int64
+	failed map[string]bool // device as logged by smartd, e.g. "/dev/sda [SAT]"
+}
+
+// reportsFailure reads the log lines added since the last call and reports
+// whether smartd logged a failure for the device path or info name.
+func (l *smartdLog) reportsFailure(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.read(); err != nil {
+		log.Warn().Err(err).Str("path", l.path).Msg("error reading smartd log")
+	}
+	for device := range l.failed {
+		if device == name || strings.HasPrefix(device, name+" [") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *smartdLog) read() error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < l.offset {
+		l.offset = 0 // rotated
+	}
+	if _, err := file.Seek(l.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// An incomplete last line is read again once it is complete.
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		l.offset += int64(len(line))
+		if match := smartdFailurePattern.FindStringSubmatch(line); match != nil {
+			if !l.failed[match[1]] {
+				log.Warn().Str("device", match[1]).Msg("smartd reported a SMART failure")
+			}
+			l.failed[match[1]] = true
+		}
+	}
+}
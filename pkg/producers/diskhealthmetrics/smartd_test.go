@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const smartdTestState = `# smartd state file
+temperature-min = 21
+temperature-max = 44
+self-test-errors = 0
+ata-smart-attribute.0.id = 5
+ata-smart-attribute.0.val = 100
+ata-smart-attribute.0.worst = 100
+ata-smart-attribute.0.raw = 8
+ata-smart-attribute.1.id = 9
+ata-smart-attribute.1.val = 70
+ata-smart-attribute.1.worst = 70
+ata-smart-attribute.1.raw = 26280
+ata-smart-attribute.2.id = 194
+ata-smart-attribute.2.val = 65
+ata-smart-attribute.2.worst = 45
+ata-smart-attribute.2.raw = 193273528355
+ata-smart-attribute.3.id = 0
+`
+
+func TestSmartdStateFileName(t *testing.T) {
+	assert.Equal(t, "smartd.WDC_WD40EFRX_68N32N0-WD_WCC7K1234567.ata.state", smartdStateFileName("WDC WD40EFRX-68N32N0", "WD-WCC7K1234567"))
+}
+
+func TestParseSmartdState(t *testing.T) {
+	attributes, err := parseSmartdState([]byte(smartdTestState))
+	require.NoError(t, err)
+	require.Len(t, attributes, 3, "unused slots are skipped")
+	assert.Equal(t, SmartCtlATASMARTEntry{
+		ID:    5,
+		Name:  "Reallocated_Sector_Ct",
+		Value: 100,
+		Worst: 100,
+		Raw:   SmartCtlATASMARTRaw{Value: 8, String: "8"},
+	}, attributes[0])
+	assert.Equal(t, "Temperature_Celsius", attributes[2].Name)
+
+	_, err = parseSmartdState([]byte("ata-smart-attribute.0.raw = x"))
+	assert.Error(t, err)
+}
+
+func TestSmartdSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "smartd.WDC_WD40EFRX_68N32N0-WD_WCC7K1234567.ata.state"), []byte(smartdTestState), 0o600))
+	logPath := filepath.Join(dir, "syslog")
+	require.NoError(t, os.WriteFile(logPath, []byte("Oct 16 10:00:00 node smartd[42]: Device: /dev/sda [SAT], opened\n"), 0o600))
+
+	identifications := 0
+	original := smartctlCommand
+	t.Cleanup(func() { smartctlCommand = original })
+	smartctlCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "/dev/sda":
+			identifications++
+			return []byte(`{"device": {"name": "/dev/sda", "protocol": "ATA"}, "model_name": "WDC WD40EFRX-68N32N0", "serial_number": "WD-WCC7K1234567"}`), nil
+		case "/dev/nvme0":
+			return []byte(`{"device": {"name": "/dev/nvme0", "protocol": "NVMe"}}`), nil
+		}
+		return []byte(`{"devices": [{"name": "/dev/sda"}]}`), nil
+	}
+	require.NoError(t, useSmartdState(dir, logPath))
+	ctx := context.Background()
+
+	scan, err := discoverDevices()
+	require.NoError(t, err)
+	assert.Len(t, scan.Devices, 1, "scans are passed to smartctl")
+
+	data, err := collectSmartData(ctx, "/dev/sda", "")
+	require.NoError(t, err)
+	assert.Equal(t, "WD-WCC7K1234567", data.SerialNumber)
+	require.NotNil(t, data.ATASMARTAttributes)
+	assert.Len(t, data.ATASMARTAttributes.Table, 3)
+	assert.Equal(t, int64(26280), data.PowerOnTime.Hours)
+	assert.Equal(t, int64(35), data.Temperature.Current)
+	assert.True(t, data.SmartStatus.Passed)
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("Oct 16 10:30:00 node smartd[42]: Device: /dev/sda [SAT], FAILED SMART self-check. BACK UP DATA NOW!\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err = collectSmartData(ctx, "/dev/sda", "")
+	require.NoError(t, err)
+	assert.False(t, data.SmartStatus.Passed, "failures in the smartd log mark the drive as failed")
+	assert.Equal(t, 1, identifications, "devices are identified once")
+
+	_, err = collectSmartData(ctx, "/dev/nvme0", "")
+	assert.ErrorContains(t, err, "smartd keeps no state for NVMe devices")
+	_, err = collectSelfTestStatus(ctx, "/dev/sda", "")
+	assert.ErrorContains(t, err, errSmartdSelfTest.Error())
+
+	assert.Error(t, useSmartdState(filepath.Join(dir, "missing"), ""))
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+// Snapshot is the inventory and health of all devices of a node at one scan,
+// for offline inventory and procurement analysis.
+type Snapshot struct {
+	NodeName   string           `json:"node_name"`
+	InstanceID string           `json:"instance_id"`
+	Zone       string           `json:"zone,omitempty"`
+	Rack       string           `json:"rack,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Devices    []SnapshotDevice `json:"devices"`
+}
+
+// SnapshotDevice is the normalized device information and the key SMART
+// values of one device.
+type SnapshotDevice struct {
+	Device             string   `json:"device"`
+	OSDID              string   `json:"osd_id,omitempty"`
+	CephCluster        string   `json:"ceph_cluster,omitempty"`
+	Chassis            string   `json:"chassis,omitempty"`
+	Enclosure          string   `json:"enclosure,omitempty"`
+	Slot               string   `json:"slot,omitempty"`
+	Vendor             string   `json:"vendor"`
+	Model              string   `json:"model"`
+	ModelFamily        string   `json:"model_family,omitempty"`
+	Product            string   `json:"product,omitempty"`
+	SerialNumber       string   `json:"serial_number"`
+	FirmwareVersion    string   `json:"firmware_version"`
+	Media              string   `json:"media"`
+	FormFactor         string   `json:"form_factor,omitempty"`
+	RPM                int64    `json:"rpm,omitempty"`
+	DWPD               float64  `json:"dwpd,omitempty"`
+	CapacityGB         float64  `json:"capacity_gb"`
+	Healthy            *bool    `json:"healthy"`
+	TemperatureCelsius *int64   `json:"temperature_celsius"`
+	PowerOnHours       *int64   `json:"power_on_hours"`
+	ReallocatedSectors int64    `json:"reallocated_sectors"`
+	PendingSectors     int64    `json:"pending_sectors"`
+	MediaErrors        int64    `json:"media_errors"`
+	WearLevel          int64    `json:"wear_level"` // percent of rated life used
+	FailureRiskScore   *float64 `json:"failure_risk_score"`
+	RemainingLifeDays  *float64 `json:"remaining_life_days"`
+}
+
+// newSnapshot builds a Snapshot from the collected metrics.
+func newSnapshot(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig, now time.Time) Snapshot {
+	snapshot := Snapshot{
+		NodeName:   cfg.NodeName,
+		InstanceID: cfg.InstanceID,
+		Zone:       cfg.Zone,
+		Rack:       cfg.Rack,
+		Timestamp:  now.UTC(),
+		Devices:    make([]SnapshotDevice, 0, len(metrics)),
+	}
+
+	for _, metric := range metrics {
+		location := locationOf(metric)
+		device := SnapshotDevice{
+			Device:             metric.Device,
+			OSDID:              metric.OSDID,
+			CephCluster:        metric.CephCluster,
+			Chassis:            location.Chassis,
+			Enclosure:          location.Enclosure,
+			Slot:               location.Slot,
+			CapacityGB:         metric.CapacityGB,
+			Healthy:            metric.HealthStatus,
+			TemperatureCelsius: metric.TemperatureCelsius,
+			PowerOnHours:       metric.PowerOnHours,
+			ReallocatedSectors: riskReallocatedSectors(metric),
+			PendingSectors:     riskPendingSectors(metric),
+			MediaErrors:        riskMediaErrors(metric),
+			WearLevel:          riskWearLevel(metric),
+		}
+		if info := metric.DeviceInfo; info != nil {
+			device.Vendor = info.Vendor
+			device.Model = info.DeviceModel
+			device.ModelFamily = info.ModelFamily
+			device.Product = info.Product
+			device.SerialNumber = info.SerialNumber
+			device.FirmwareVersion = info.FirmwareVersion
+			device.Media = info.Media
+			device.FormFactor = info.FormFactor
+			device.RPM = info.RPM
+			device.DWPD = info.DWPD
+		}
+		if metric.FailureRisk != nil {
+			device.FailureRiskScore = &metric.FailureRisk.Score
+		}
+		if metric.Endurance != nil {
+			device.RemainingLifeDays = &metric.Endurance.RemainingDays
+		}
+		snapshot.Devices = append(snapshot.Devices, device)
+	}
+
+	return snapshot
+}
+
+var snapshotCSVHeader = []string{
+	"node_name", "instance_id", "zone", "rack", "timestamp", "device", "osd_id", "ceph_cluster",
+	"chassis", "enclosure", "slot", "vendor", "model", "model_family", "product", "serial_number", "firmware_version",
+	"media", "form_factor", "rpm", "dwpd", "capacity_gb", "healthy",
+	"temperature_celsius", "power_on_hours", "reallocated_sectors", "pending_sectors",
+	"media_errors", "wear_level", "failure_risk_score", "remaining_life_days",
+}
+
+// encodeSnapshotCSV writes one row per device; unknown values are empty.
+func encodeSnapshotCSV(snapshot Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(snapshotCSVHeader); err != nil {
+		return nil, err
+	}
+
+	optionalInt := func(v *int64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+
+	for _, d := range snapshot.Devices {
+		healthy := ""
+		if d.Healthy != nil {
+			healthy = strconv.FormatBool(*d.Healthy)
+		}
+		riskScore := ""
+		if d.FailureRiskScore != nil {
+			riskScore = strconv.FormatFloat(*d.FailureRiskScore, 'f', 2, 64)
+		}
+		remainingLife := ""
+		if d.RemainingLifeDays != nil {
+			remainingLife = strconv.FormatFloat(*d.RemainingLifeDays, 'f', 0, 64)
+		}
+
+		row := []string{
+			snapshot.NodeName, snapshot.InstanceID, snapshot.Zone, snapshot.Rack,
+			snapshot.Timestamp.Format(time.RFC3339), d.Device, d.OSDID, d.CephCluster,
+			d.Chassis, d.Enclosure, d.Slot, d.Vendor, d.Model, d.ModelFamily, d.Product, d.SerialNumber, d.FirmwareVersion,
+			d.Media, d.FormFactor, strconv.FormatInt(d.RPM, 10), strconv.FormatFloat(d.DWPD, 'f', 2, 64),
+			strconv.FormatFloat(d.CapacityGB, 'f', 2, 64), healthy,
+			optionalInt(d.TemperatureCelsius), optionalInt(d.PowerOnHours),
+			strconv.FormatInt(d.ReallocatedSectors, 10), strconv.FormatInt(d.PendingSectors, 10),
+			strconv.FormatInt(d.MediaErrors, 10), strconv.FormatInt(d.WearLevel, 10), riskScore, remainingLife,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// writeSnapshotFile writes the snapshot as CSV if path ends in .csv and as
+// JSON otherwise. The file is replaced on every scan.
+func writeSnapshotFile(snapshot Snapshot, path string) error {
+	var data []byte
+	var err error
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		data, err = encodeSnapshotCSV(snapshot)
+	} else {
+		data, err = json.MarshalIndent(snapshot, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+// PublishSnapshot publishes the snapshot as one JSON message.
+func PublishSnapshot(snapshot Snapshot, nc *nats.Conn, subject string) error {
+	return schema.Publish(nc, subject, schema.DiskSnapshot, snapshot)
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshot() Snapshot {
+	healthy := true
+	temperature := int64(38)
+	pending := int64(2)
+	metrics := []NormalizedSmartData{
+		{
+			Device:             "/dev/sda",
+			OSDID:              "12",
+			DeviceInfo:         &DeviceInfo{Vendor: "Seagate", DeviceModel: "ST12000NM0008", SerialNumber: "ZJV0ABCD", Media: "hdd", RPM: 7200},
+			CapacityGB:         12000,
+			HealthStatus:       &healthy,
+			TemperatureCelsius: &temperature,
+			PendingSectors:     &pending,
+			FailureRisk:        &FailureRisk{Score: 12.5},
+		},
+		{Device: "/dev/sdb"},
+	}
+	cfg := DiskHealthMetricsConfig{NodeName: "node-1", InstanceID: "i-1"}
+	return newSnapshot(metrics, cfg, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestWriteSnapshotFile_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, writeSnapshotFile(testSnapshot(), path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var snapshot Snapshot
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+
+	assert.Equal(t, "node-1", snapshot.NodeName)
+	require.Len(t, snapshot.Devices, 2)
+	assert.Equal(t, "ZJV0ABCD", snapshot.Devices[0].SerialNumber)
+	assert.Equal(t, int64(2), snapshot.Devices[0].PendingSectors)
+	require.NotNil(t, snapshot.Devices[0].FailureRiskScore)
+	assert.Equal(t, 12.5, *snapshot.Devices[0].FailureRiskScore)
+	assert.Nil(t, snapshot.Devices[1].Healthy)
+}
+
+func TestWriteSnapshotFile_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.csv")
+	require.NoError(t, writeSnapshotFile(testSnapshot(), path))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, snapshotCSVHeader, records[0])
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	assert.Equal(t, "2025-03-01T12:00:00Z", row["timestamp"])
+	assert.Equal(t, "ST12000NM0008", row["model"])
+	assert.Equal(t, "true", row["healthy"])
+	assert.Equal(t, "38", row["temperature_celsius"])
+	assert.Equal(t, "", row["power_on_hours"])
+	assert.Equal(t, "12.50", row["failure_risk_score"])
+}
@@ -20,31 +20,51 @@ type DiskHealthMetrics struct {
 
 // NormalizedSmartData represents normalized SMART data for consistency across devices
 type NormalizedSmartData struct {
-	NodeName           string                    `json:"node_name"`           // Name of the node where the drive is located
-	InstanceID         string                    `json:"instance_id"`         // ID of the instance (useful in cloud environments)
-	Device             string                    `json:"device"`              // Device name, e.g., "/dev/sda"
-	DeviceInfo         *DeviceInfo               `json:"device_info"`         // Device information (e.g., vendor and model)
-	CapacityGB         float64                   `json:"capacity_gb"`         // Capacity of the drive in gigabytes
-	HealthStatus       *bool                     `json:"health_status"`       // Overall health status of the drive (true if healthy, false if failing, nil if unknown)
-	TemperatureCelsius *int64                    `json:"temperature_celsius"` // Current temperature of the drive in Celsius
-	ReallocatedSectors *int64                    `json:"reallocated_sectors"` // Number of reallocated sectors on the drive
-	PendingSectors     *int64                    `json:"pending_sectors"`     // Number of pending sectors (unreadable sectors waiting to be reallocated)
-	PowerOnHours       *int64                    `json:"power_on_hours"`      // Total number of hours the drive has been powered on
-	SSDLifeUsed        *int64                    `json:"ssd_life_used"`       // Percentage of SSD life used (useful for SSD wear monitoring)
-	ErrorCounts        map[string]int64          `json:"error_counts"`        // Dictionary of various error counts (e.g., command timeouts, CRC errors)
-	Attributes         map[string]SmartAttribute `json:"attributes"`          // key-value pairs of SMART attributes with their values
-	OSDID              string                    `json:"osd_id"`              // OSD ID (useful for Ceph environments for mapping to OSD ID)
+	NodeName           string                    `json:"node_name"`                   // Name of the node where the drive is located
+	InstanceID         string                    `json:"instance_id"`                 // ID of the instance (useful in cloud environments)
+	Device             string                    `json:"device"`                      // Device name, e.g., "/dev/sda"
+	DeviceInfo         *DeviceInfo               `json:"device_info"`                 // Device information (e.g., vendor and model)
+	CapacityGB         float64                   `json:"capacity_gb"`                 // Capacity of the drive in gigabytes
+	HealthStatus       *bool                     `json:"health_status"`               // Overall health status of the drive (true if healthy, false if failing, nil if unknown)
+	TemperatureCelsius *int64                    `json:"temperature_celsius"`         // Current temperature of the drive in Celsius
+	TemperatureAlert   *TemperatureAlert         `json:"temperature_alert,omitempty"` // Temperature threshold state for the drive's media type
+	ReallocatedSectors *int64                    `json:"reallocated_sectors"`         // Number of reallocated sectors on the drive
+	PendingSectors     *int64                    `json:"pending_sectors"`             // Number of pending sectors (unreadable sectors waiting to be reallocated)
+	PowerOnHours       *int64                    `json:"power_on_hours"`              // Total number of hours the drive has been powered on
+	SSDLifeUsed        *int64                    `json:"ssd_life_used"`               // Percentage of SSD life used (useful for SSD wear monitoring)
+	ErrorCounts        map[string]int64          `json:"error_counts"`                // Dictionary of various error counts (e.g., command timeouts, CRC errors)
+	Attributes         map[string]SmartAttribute `json:"attributes"`                  // key-value pairs of SMART attributes with their values
+	Thresholds         []AttributeThreshold      `json:"thresholds,omitempty"`        // ATA attributes compared against the thresholds reported by the drive
+	OSDID              string                    `json:"osd_id"`                      // OSD ID (useful for Ceph environments for mapping to OSD ID)
+	CephCluster        string                    `json:"ceph_cluster"`                // Ceph cluster name or fsid the OSD belongs to
+	Zone               string                    `json:"zone,omitempty"`              // Zone of the node, set in Kubernetes mode or with --zone
+	Rack               string                    `json:"rack,omitempty"`              // Rack of the node, set in Kubernetes mode or with --rack
+	FailureRisk        *FailureRisk              `json:"failure_risk"`                // Combined failure-risk score and trend
+	Endurance          *EnduranceProjection      `json:"endurance,omitempty"`         // Projected remaining write endurance, SSD and NVMe only
+	Trends             []SmartTrend              `json:"trends,omitempty"`            // Increase of SMART health indicators over 24h and 7d
+	SelfTest           *SelfTestStatus           `json:"self_test,omitempty"`         // SMART self-test status, nil unless --self-test is enabled
+	KernelIO           *KernelIOStats            `json:"kernel_io,omitempty"`         // Kernel block-layer stats and logged I/O errors, nil unless --kernel-io is enabled
+	MultipathDevice    string                    `json:"multipath_device,omitempty"`  // dm-multipath map the device belongs to
+	Paths              []DevicePath              `json:"paths,omitempty"`             // All paths to the device if it is reachable more than once
+	SCSIErrors         *SCSIErrorCounters        `json:"scsi_errors,omitempty"`       // Grown defect list and error counter log, SCSI/SAS only
+	Firmware           *FirmwareCompliance       `json:"firmware,omitempty"`          // Firmware checked against the approved versions, nil without a firmware policy
+	Location           *DriveLocation            `json:"location,omitempty"`          // Enclosure slot of the drive, nil unless --enclosure-slots is enabled
 }
 
 // NatsEvent represents an event to be published to NATS
 type NatsEvent struct {
-	NodeName   string            `json:"node_name"`   // Name of the node where the drive is located
-	InstanceID string            `json:"instance_id"` // ID of the instance (useful in cloud environments)
-	Device     string            `json:"device"`      // Device identifier (e.g., /dev/sda)
-	EventType  string            `json:"event_type"`  // e.g., 'health_alert', 'usage_alert'
-	Severity   string            `json:"severity"`    // e.g., 'info', 'warning', 'critical'
-	Message    string            `json:"message"`     // Description of the event
-	Details    map[string]string `json:"details"`     // Additional details, such as SMART attributes
+	NodeName   string            `json:"node_name"`           // Name of the node where the drive is located
+	InstanceID string            `json:"instance_id"`         // ID of the instance (useful in cloud environments)
+	Zone       string            `json:"zone,omitempty"`      // Zone of the node
+	Rack       string            `json:"rack,omitempty"`      // Rack of the node
+	Chassis    string            `json:"chassis,omitempty"`   // Chassis of the drive's enclosure
+	Enclosure  string            `json:"enclosure,omitempty"` // Enclosure the drive sits in
+	Slot       string            `json:"slot,omitempty"`      // Enclosure slot of the drive
+	Device     string            `json:"device"`              // Device identifier (e.g., /dev/sda)
+	EventType  string            `json:"event_type"`          // e.g., 'health_alert', 'usage_alert'
+	Severity   string            `json:"severity"`            // e.g., 'info', 'warning', 'critical'
+	Message    string            `json:"message"`             // Description of the event
+	Details    map[string]string `json:"details"`             // Additional details, such as SMART attributes
 }
 
 type DeviceInfo struct {
@@ -63,6 +83,7 @@ type DeviceInfo struct {
 	FormFactor        string  // Physical form factor, like "sff" or "lff".
 	Media             string  // Media type, such as "ssd", "hdd", "nvme".
 	HealthStatus      bool
+	NVMeTelemetry     *NVMeTelemetry // Additional NVMe log pages from nvme-cli, nil unless --nvme-telemetry is enabled.
 }
 
 // SmartAttribute defines the structure for a SMART attribute's metadata.
@@ -96,6 +117,7 @@ func GetSmartAttributes() map[string]SmartAttribute {
 		"host_reads_32mib":                {"Host Reads in 32 MiB", "32 MiB", -1, -1, -1, -1},
 		"host_writes_mib":                 {"Host Writes in MiB", "MiB", -1, -1, -1, -1},
 		"host_writes_32mib":               {"Host Writes in 32 MiB", "32 MiB", -1, -1, -1, -1},
+		"host_writes_gib":                 {"Host Writes in GiB", "GiB", -1, -1, -1, -1},
 		"load_cycle_count":                {"Load Cycle Count", "count", -1, -1, -1, -1},
 		"helium_level":                    {"Helium Level", "percent", -1, -1, -1, -1},
 		"media_wearout_indicator":         {"Media Wearout Indicator", "percent", -1, -1, -1, -1},
@@ -157,6 +179,7 @@ func GetSmartAttributes() map[string]SmartAttribute {
 		"unsafe_shutdowns":                 {"Unsafe Shutdowns", "count", -1, -1, -1, -1},
 		"host_read_commands":               {"Host Read Commands", "commands", -1, -1, -1, -1},
 		"host_write_commands":              {"Host Write Commands", "commands", -1, -1, -1, -1},
+		"data_units_written":               {"Data Units Written", "512000 bytes", -1, -1, -1, -1},
 		"controller_busy_time":             {"Controller Busy Time", "minutes", -1, -1, -1, -1},
 		"error_information_log_entries":    {"Error Information Log Entries", "count", -1, -1, -1, -1},
 		"available_spare":                  {"Available Spare", "percent", -1, -1, -1, -1},
@@ -180,4 +203,5 @@ func CleanupSmartAttributes(smartAttrs map[string]SmartAttribute) {
 var aliasMap = map[string]string{
 	"current_drive_temperature": "temperature_celsius",
 	"unsafe_shutdown_count":     "unsafe_shutdowns",
+	"lifetime_writes_gib":       "host_writes_gib",
 }
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Temperature alert levels reported in TemperatureAlert.Level.
+const (
+	TemperatureLevelOK       = "ok"
+	TemperatureLevelWarning  = "warning"
+	TemperatureLevelCritical = "critical"
+)
+
+// DefaultTemperatureThresholds are the warning and critical temperatures per
+// media type, in "media=warning:critical" form.
+const DefaultTemperatureThresholds = "hdd=50:60,ssd=60:70,nvme=70:80"
+
+// TemperatureThreshold holds the warning and critical temperature of a media
+// type in Celsius.
+type TemperatureThreshold struct {
+	Warning  int64
+	Critical int64
+}
+
+// ParseTemperatureThresholds parses "media=warning:critical" pairs separated
+// by commas, e.g. "hdd=50:60,nvme=70:80".
+func ParseTemperatureThresholds(spec string) (map[string]TemperatureThreshold, error) {
+	thresholds := make(map[string]TemperatureThreshold)
+	if strings.TrimSpace(spec) == "" {
+		return thresholds, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		media, values, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid temperature threshold %q, expected media=warning:critical", entry)
+		}
+		warningStr, criticalStr, ok := strings.Cut(values, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid temperature threshold %q, expected media=warning:critical", entry)
+		}
+
+		warning, err := strconv.ParseInt(strings.TrimSpace(warningStr), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid warning temperature in %q: %w", entry, err)
+		}
+		critical, err := strconv.ParseInt(strings.TrimSpace(criticalStr), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid critical temperature in %q: %w", entry, err)
+		}
+		if warning > critical {
+			return nil, fmt.Errorf("warning temperature above critical temperature in %q", entry)
+		}
+
+		thresholds[strings.ToLower(strings.TrimSpace(media))] = TemperatureThreshold{Warning: warning, Critical: critical}
+	}
+
+	return thresholds, nil
+}
+
+// TemperatureAlert is the per-device temperature threshold state.
+type TemperatureAlert struct {
+	Level           string `json:"level"`            // ok, warning or critical
+	PreviousLevel   string `json:"previous_level"`   // level of the previous collection, empty on first run
+	WarningCelsius  int64  `json:"warning_celsius"`  // warning threshold for the device's media type
+	CriticalCelsius int64  `json:"critical_celsius"` // critical threshold for the device's media type
+}
+
+// LevelChanged reports whether the device changed its temperature level since
+// the last collection. A device seen for the first time counts as changed
+// unless it is ok.
+func (a *TemperatureAlert) LevelChanged() bool {
+	if a.PreviousLevel == "" {
+		return a.Level != TemperatureLevelOK
+	}
+	return a.Level != a.PreviousLevel
+}
+
+var temperatureLevelRank = map[string]int{
+	TemperatureLevelOK:       0,
+	TemperatureLevelWarning:  1,
+	TemperatureLevelCritical: 2,
+}
+
+var (
+	temperatureLevels      = make(map[string]string)
+	temperatureLevelsMutex sync.Mutex
+)
+
+// evaluateTemperatureAlerts assigns a TemperatureAlert to every device with
+// a temperature reading and thresholds for its media type.
+func evaluateTemperatureAlerts(metrics []NormalizedSmartData, cfg DiskHealthMetricsConfig) {
+	for i := range metrics {
+		if metrics[i].TemperatureCelsius == nil || metrics[i].DeviceInfo == nil {
+			continue
+		}
+		threshold, ok := cfg.TemperatureThresholds[metrics[i].DeviceInfo.Media]
+		if !ok {
+			continue
+		}
+		metrics[i].TemperatureAlert = trackTemperature(metrics[i].Device, *metrics[i].TemperatureCelsius, threshold, cfg.TemperatureHysteresis)
+	}
+}
+
+// temperatureLevel maps a temperature onto the thresholds lowered by offset.
+func temperatureLevel(celsius int64, threshold TemperatureThreshold, offset int64) string {
+	switch {
+	case celsius >= threshold.Critical-offset:
+		return TemperatureLevelCritical
+	case celsius >= threshold.Warning-offset:
+		return TemperatureLevelWarning
+	default:
+		return TemperatureLevelOK
+	}
+}
+
+// trackTemperature derives the temperature level of a device. A level is
+// entered as soon as its threshold is reached, but only left once the
+// temperature dropped hysteresis degrees below it, so a drive hovering
+// around a threshold does not flap.
+func trackTemperature(device string, celsius int64, threshold TemperatureThreshold, hysteresis int64) *TemperatureAlert {
+	temperatureLevelsMutex.Lock()
+	defer temperatureLevelsMutex.Unlock()
+
+	previous := temperatureLevels[device]
+	level := temperatureLevel(celsius, threshold, 0)
+	if previous != "" && temperatureLevelRank[level] < temperatureLevelRank[previous] {
+		level = temperatureLevel(celsius, threshold, hysteresis)
+		if temperatureLevelRank[level] > temperatureLevelRank[previous] {
+			level = previous
+		}
+	}
+	temperatureLevels[device] = level
+
+	return &TemperatureAlert{
+		Level:           level,
+		PreviousLevel:   previous,
+		WarningCelsius:  threshold.Warning,
+		CriticalCelsius: threshold.Critical,
+	}
+}
+
+// temperatureEventMessage describes a temperature level change for the NATS event.
+func temperatureEventMessage(alert *TemperatureAlert, celsius int64) string {
+	if alert.Level == TemperatureLevelOK {
+		return fmt.Sprintf("Disk temperature dropped to %d°C (was %s).", celsius, alert.PreviousLevel)
+	}
+	threshold := alert.WarningCelsius
+	if alert.Level == TemperatureLevelCritical {
+		threshold = alert.CriticalCelsius
+	}
+	return fmt.Sprintf("Disk temperature %d°C reached %s level (threshold %d°C).", celsius, alert.Level, threshold)
+}
+
+// temperatureRollup summarizes the temperatures of a group of drives.
+type temperatureRollup struct {
+	Devices     int   // Drives with a temperature reading
+	MaxCelsius  int64 // Hottest drive
+	SumCelsius  int64
+	OverWarning int // Drives at the warning or critical level
+}
+
+// AvgCelsius is the mean temperature of the drives of the group.
+func (r temperatureRollup) AvgCelsius() float64 {
+	if r.Devices == 0 {
+		return 0
+	}
+	return float64(r.SumCelsius) / float64(r.Devices)
+}
+
+func (r *temperatureRollup) add(metric NormalizedSmartData) {
+	if metric.TemperatureCelsius != nil {
+		if r.Devices == 0 || *metric.TemperatureCelsius > r.MaxCelsius {
+			r.MaxCelsius = *metric.TemperatureCelsius
+		}
+		r.SumCelsius += *metric.TemperatureCelsius
+		r.Devices++
+	}
+	if metric.TemperatureAlert != nil && metric.TemperatureAlert.Level != TemperatureLevelOK {
+		r.OverWarning++
+	}
+}
+
+// rollupTemperatures summarizes the temperatures of all drives of the node
+// and of the drives of each enclosure. Drives without a known enclosure
+// count for the node only.
+func rollupTemperatures(metrics []NormalizedSmartData) (temperatureRollup, map[DriveLocation]temperatureRollup) {
+	var node temperatureRollup
+	enclosures := make(map[DriveLocation]temperatureRollup)
+	for _, metric := range metrics {
+		node.add(metric)
+
+		location := locationOf(metric)
+		if location.Enclosure == "" {
+			continue
+		}
+		key := DriveLocation{Chassis: location.Chassis, Enclosure: location.Enclosure}
+		enclosure := enclosures[key]
+		enclosure.add(metric)
+		enclosures[key] = enclosure
+	}
+	return node, enclosures
+}
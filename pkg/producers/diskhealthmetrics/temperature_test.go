@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemperatureThresholds(t *testing.T) {
+	thresholds, err := ParseTemperatureThresholds(DefaultTemperatureThresholds)
+	require.NoError(t, err)
+	assert.Equal(t, TemperatureThreshold{Warning: 50, Critical: 60}, thresholds["hdd"])
+	assert.Equal(t, TemperatureThreshold{Warning: 70, Critical: 80}, thresholds["nvme"])
+
+	_, err = ParseTemperatureThresholds("hdd=50")
+	assert.Error(t, err)
+	_, err = ParseTemperatureThresholds("hdd=60:50")
+	assert.Error(t, err)
+}
+
+func TestTrackTemperature_Hysteresis(t *testing.T) {
+	device := "/dev/test-temperature"
+	t.Cleanup(func() {
+		temperatureLevelsMutex.Lock()
+		delete(temperatureLevels, device)
+		temperatureLevelsMutex.Unlock()
+	})
+
+	threshold := TemperatureThreshold{Warning: 50, Critical: 60}
+	steps := []struct {
+		celsius int64
+		level   string
+		changed bool
+	}{
+		{45, TemperatureLevelOK, false},
+		{50, TemperatureLevelWarning, true},
+		{48, TemperatureLevelWarning, false}, // within hysteresis
+		{61, TemperatureLevelCritical, true},
+		{58, TemperatureLevelCritical, false}, // within hysteresis
+		{56, TemperatureLevelWarning, true},
+		{46, TemperatureLevelOK, true},
+	}
+
+	for _, step := range steps {
+		alert := trackTemperature(device, step.celsius, threshold, 3)
+		assert.Equal(t, step.level, alert.Level, "%d°C", step.celsius)
+		assert.Equal(t, step.changed, alert.LevelChanged(), "%d°C", step.celsius)
+	}
+}
+
+func TestRollupTemperatures(t *testing.T) {
+	celsius := func(c int64) *int64 { return &c }
+	front := &DriveLocation{Chassis: "CH1", Enclosure: "500605b0000272bf", Slot: "0"}
+	rear := &DriveLocation{Chassis: "CH1", Enclosure: "500605b0000272c0", Slot: "3"}
+	metrics := []NormalizedSmartData{
+		{Device: "/dev/sda", TemperatureCelsius: celsius(40), Location: front},
+		{Device: "/dev/sdb", TemperatureCelsius: celsius(52), Location: front, TemperatureAlert: &TemperatureAlert{Level: TemperatureLevelWarning}},
+		{Device: "/dev/sdc", TemperatureCelsius: celsius(35), Location: rear, TemperatureAlert: &TemperatureAlert{Level: TemperatureLevelOK}},
+		{Device: "/dev/nvme0n1", TemperatureCelsius: celsius(81), TemperatureAlert: &TemperatureAlert{Level: TemperatureLevelCritical}},
+		{Device: "/dev/sdd", Location: rear}, // No temperature reading
+	}
+
+	node, enclosures := rollupTemperatures(metrics)
+	assert.Equal(t, temperatureRollup{Devices: 4, MaxCelsius: 81, SumCelsius: 208, OverWarning: 2}, node)
+	assert.InDelta(t, 52.0, node.AvgCelsius(), 0.001)
+
+	require.Len(t, enclosures, 2, "the NVMe device has no enclosure")
+	frontRollup := enclosures[DriveLocation{Chassis: "CH1", Enclosure: "500605b0000272bf"}]
+	assert.Equal(t, int64(52), frontRollup.MaxCelsius)
+	assert.InDelta(t, 46.0, frontRollup.AvgCelsius(), 0.001)
+	assert.Equal(t, 1, frontRollup.OverWarning)
+	rearRollup := enclosures[DriveLocation{Chassis: "CH1", Enclosure: "500605b0000272c0"}]
+	assert.Equal(t, temperatureRollup{Devices: 1, MaxCelsius: 35, SumCelsius: 35}, rearRollup)
+
+	node, _ = rollupTemperatures(nil)
+	assert.Zero(t, node.AvgCelsius())
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// When an ATA attribute was at or below the threshold of the drive, as
+// reported by smartctl in when_failed.
+const (
+	WhenFailedNever = "never"
+	WhenFailedPast  = "past" // the worst value was, the current one is above again
+	WhenFailedNow   = "now"
+)
+
+// AttributeThreshold is an ATA SMART attribute compared against the
+// threshold the drive reports for it. The drive considers a prefailure
+// attribute at or below its threshold a predicted failure, an old-age
+// attribute the end of the drive's life.
+type AttributeThreshold struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`        // as reported by smartctl, e.g. Reallocated_Sector_Ct
+	Value      int64  `json:"value"`       // normalized value, 1-253, lower is worse
+	Worst      int64  `json:"worst"`       // lowest normalized value seen by the drive
+	Threshold  int64  `json:"threshold"`   // the attribute failed at or below this value
+	Prefailure bool   `json:"prefailure"`  // a failure predicts the failure of the drive
+	WhenFailed string `json:"when_failed"` // never, past or now
+}
+
+// BelowThreshold reports whether the current value is at or below the
+// threshold.
+func (a AttributeThreshold) BelowThreshold() bool {
+	return a.WhenFailed == WhenFailedNow
+}
+
+// attributeThresholds returns the ATA attributes the drive reports a
+// threshold for. Attributes with a threshold of 0 never fail and are left
+// out, as are the attributes read from smartd state files, which do not
+// save thresholds.
+func attributeThresholds(smartData *SmartCtlOutput) []AttributeThreshold {
+	if smartData.ATASMARTAttributes == nil {
+		return nil
+	}
+
+	var thresholds []AttributeThreshold
+	for _, entry := range smartData.ATASMARTAttributes.Table {
+		if entry.Thresh <= 0 {
+			continue
+		}
+		thresholds = append(thresholds, AttributeThreshold{
+			ID:         entry.ID,
+			Name:       entry.Name,
+			Value:      entry.Value,
+			Worst:      entry.Worst,
+			Threshold:  entry.Thresh,
+			Prefailure: entry.Flags.Prefailure,
+			WhenFailed: whenFailed(entry),
+		})
+	}
+	return thresholds
+}
+
+// whenFailed takes when_failed from smartctl and falls back to comparing the
+// values for output that leaves it out.
+func whenFailed(entry SmartCtlATASMARTEntry) string {
+	switch strings.ToLower(entry.WhenFailed) {
+	case "now", "failing_now":
+		return WhenFailedNow
+	case "past", "in_the_past":
+		return WhenFailedPast
+	}
+	switch {
+	case entry.Value <= entry.Thresh:
+		return WhenFailedNow
+	case entry.Worst > 0 && entry.Worst <= entry.Thresh:
+		return WhenFailedPast
+	}
+	return WhenFailedNever
+}
+
+// failingAttributes lists the attributes currently at or below their
+// threshold, e.g. "Reallocated_Sector_Ct (5)", and whether one of them is a
+// prefailure attribute.
+func failingAttributes(thresholds []AttributeThreshold) (names []string, prefailure bool) {
+	for _, attr := range thresholds {
+		if !attr.BelowThreshold() {
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (%d)", attr.Name, attr.ID))
+		prefailure = prefailure || attr.Prefailure
+	}
+	return names, prefailure
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diskhealthmetrics
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeThresholds(t *testing.T) {
+	data, err := os.ReadFile("testdata/scenarios/failing/sda.json")
+	require.NoError(t, err)
+	var output SmartCtlOutput
+	require.NoError(t, json.Unmarshal(data, &output))
+
+	thresholds := attributeThresholds(&output)
+	require.Len(t, thresholds, 5, "attributes with a threshold of 0 never fail")
+	assert.Equal(t, AttributeThreshold{ID: 5, Name: "Reallocated_Sector_Ct", Value: 60, Worst: 60, Threshold: 10, Prefailure: true, WhenFailed: WhenFailedNever}, thresholds[0])
+	names, _ := failingAttributes(thresholds)
+	assert.Empty(t, names)
+
+	output.ATASMARTAttributes.Table[0].Value = 10
+	output.ATASMARTAttributes.Table[0].Worst = 10
+	output.ATASMARTAttributes.Table[5].Worst = 8 // Unused_Rsvd_Blk_Cnt_Tot
+	thresholds = attributeThresholds(&output)
+	assert.Equal(t, WhenFailedNow, thresholds[0].WhenFailed, "failed at the threshold")
+	assert.True(t, thresholds[0].BelowThreshold())
+	assert.Equal(t, WhenFailedPast, thresholds[2].WhenFailed)
+	assert.False(t, thresholds[2].BelowThreshold())
+
+	names, prefailure := failingAttributes(thresholds)
+	assert.Equal(t, []string{"Reallocated_Sector_Ct (5)"}, names)
+	assert.True(t, prefailure)
+
+	output.ATASMARTAttributes = nil
+	assert.Nil(t, attributeThresholds(&output))
+}
+
+func TestWhenFailed(t *testing.T) {
+	entry := SmartCtlATASMARTEntry{Value: 100, Worst: 100, Thresh: 10}
+	assert.Equal(t, WhenFailedNever, whenFailed(entry))
+	entry.WhenFailed = "past"
+	assert.Equal(t, WhenFailedPast, whenFailed(entry), "smartctl knows better")
+	entry.WhenFailed = "In_the_past"
+	assert.Equal(t, WhenFailedPast, whenFailed(entry))
+	entry.WhenFailed = "FAILING_NOW"
+	assert.Equal(t, WhenFailedNow, whenFailed(entry))
+}
+
+func TestPublishAttributeThresholds(t *testing.T) {
+	labels := prometheus.Labels{"disk": "/dev/sdt", "node": "node", "instance": "instance", "osd_id": "", "ceph_cluster": ""}
+	attr := AttributeThreshold{ID: 5, Name: "Reallocated_Sector_Ct", Value: 9, Worst: 9, Threshold: 10, Prefailure: true, WhenFailed: WhenFailedNow}
+	publishAttributeThresholds([]AttributeThreshold{attr}, labels)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(attributeBelowThresholdGauge.WithLabelValues("/dev/sdt", "node", "instance", "", "", "5", "Reallocated_Sector_Ct", "true")))
+	assert.Equal(t, -1.0, testutil.ToFloat64(attributeThresholdMarginGauge.WithLabelValues("/dev/sdt", "node", "instance", "", "", "5", "Reallocated_Sector_Ct", "true")))
+
+	// Only the current state is reported
+	attr.Value = 50
+	attr.WhenFailed = WhenFailedPast
+	publishAttributeThresholds([]AttributeThreshold{attr}, labels)
+	assert.Equal(t, 0.0, testutil.ToFloat64(attributeBelowThresholdGauge.WithLabelValues("/dev/sdt", "node", "instance", "", "", "5", "Reallocated_Sector_Ct", "true")))
+	assert.Equal(t, 1, testutil.CollectAndCount(attributeWhenFailedGauge))
+
+	deleteDeviceMetrics("/dev/sdt")
+	assert.Zero(t, testutil.CollectAndCount(attributeWhenFailedGauge))
+}
@@ -6,6 +6,7 @@ package diskhealthmetrics
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -62,3 +63,22 @@ func FindVendor(deviceModel, modelFamily string) string {
 	}
 	return ""
 }
+
+// writeFileAtomic writes data through a temporary file in the same directory
+// so a crash never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
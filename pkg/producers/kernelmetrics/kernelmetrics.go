@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 
 	"github.com/nats-io/nats.go"
@@ -56,7 +58,7 @@ func StartMonitoring(cfg KernelMetricsConfig) {
 	var nc *nats.Conn
 	var err error
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Fatal().
 				Err(err).
@@ -66,7 +68,7 @@ func StartMonitoring(cfg KernelMetricsConfig) {
 	}
 
 	if cfg.Prometheus {
-		StartPrometheusServer(cfg.PrometheusPort)
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
 	}
 
 	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
@@ -5,16 +5,10 @@
 package kernelmetrics
 
 import (
-	"encoding/json"
-
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 )
 
 func PublishToNATS(nc *nats.Conn, metrics KernelMetrics, cfg KernelMetricsConfig) error {
-	data, err := json.Marshal(metrics)
-	if err != nil {
-		return err
-	}
-
-	return nc.Publish(cfg.NatsSubject, data)
+	return schema.Publish(nc, cfg.NatsSubject, schema.KernelMetrics, metrics)
 }
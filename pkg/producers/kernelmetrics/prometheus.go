@@ -4,15 +4,7 @@
 
 package kernelmetrics
 
-import (
-	"fmt"
-	"net/http"
-
-	"github.com/rs/zerolog/log"
-
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-)
+import "github.com/prometheus/client_golang/prometheus"
 
 var (
 	contextSwitchesGauge = prometheus.NewGaugeVec(
@@ -60,14 +52,3 @@ func PublishToPrometheus(metrics KernelMetrics, cfg KernelMetricsConfig) {
 		"instance": cfg.InstanceID,
 	}).Set(float64(metrics.NetConnections))
 }
-
-func StartPrometheusServer(port int) {
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
-		}
-	}()
-}
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+type NodeInventoryConfig struct {
+	HostRoot       string // root of the host filesystem, e.g. /host with the host mounted there
+	SocketGlob     string // admin sockets of the Ceph daemons, e.g. /var/run/ceph/*.asok
+	Interval       int    // in seconds
+	Timeout        int    // of an admin socket command, in seconds
+	NatsURL        string
+	NatsSubject    string
+	UseNats        bool
+	NodeName       string
+	InstanceID     string
+	Prometheus     bool
+	PrometheusPort int
+}
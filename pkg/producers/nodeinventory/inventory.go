@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/adminsocket"
+	"github.com/rs/zerolog/log"
+)
+
+// Inventory is the hardware and software inventory of a node
+type Inventory struct {
+	NodeName    string       `json:"node_name"`
+	InstanceID  string       `json:"instance_id"`
+	Hostname    string       `json:"hostname"`
+	Kernel      string       `json:"kernel"`
+	OS          string       `json:"os,omitempty"`
+	CPU         CPU          `json:"cpu"`
+	MemoryBytes uint64       `json:"memory_bytes"`
+	NICs        []NIC        `json:"nics"`
+	CephDaemons []CephDaemon `json:"ceph_daemons"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// CPU are the processors of the node
+type CPU struct {
+	Model   string `json:"model"`
+	Sockets int    `json:"sockets"`
+	Cores   int    `json:"cores"`
+	Threads int    `json:"threads"`
+}
+
+// NIC is a physical network interface; SpeedMbps is 0 when the kernel does
+// not know it, e.g. without a link
+type NIC struct {
+	Name      string `json:"name"`
+	MAC       string `json:"mac"`
+	Driver    string `json:"driver,omitempty"`
+	SpeedMbps int64  `json:"speed_mbps,omitempty"`
+	MTU       int    `json:"mtu"`
+	State     string `json:"state"`
+}
+
+// CephDaemon is the version of a Ceph daemon running on the node, e.g.
+// osd.7 of the cluster named ceph
+type CephDaemon struct {
+	Daemon  string `json:"daemon"`
+	Cluster string `json:"cluster"`
+	Version string `json:"version"`
+	Release string `json:"release,omitempty"`
+}
+
+// collect collects the inventory of the node. The CPU, memory and kernel
+// are required, the rest is left out if it cannot be read.
+func collect(ctx context.Context, cfg NodeInventoryConfig) (Inventory, error) {
+	inventory := Inventory{
+		NodeName:   cfg.NodeName,
+		InstanceID: cfg.InstanceID,
+		Timestamp:  time.Now().UTC(),
+	}
+	host := func(path string) string { return filepath.Join(cfg.HostRoot, path) }
+
+	cpuinfo, err := os.ReadFile(host("/proc/cpuinfo"))
+	if err != nil {
+		return Inventory{}, err
+	}
+	inventory.CPU = parseCPUInfo(cpuinfo)
+
+	meminfo, err := os.ReadFile(host("/proc/meminfo"))
+	if err != nil {
+		return Inventory{}, err
+	}
+	if inventory.MemoryBytes, err = parseMemTotal(meminfo); err != nil {
+		return Inventory{}, err
+	}
+
+	if inventory.Kernel, err = readString(host("/proc/sys/kernel/osrelease")); err != nil {
+		return Inventory{}, err
+	}
+	inventory.Hostname, _ = readString(host("/proc/sys/kernel/hostname"))
+	if osRelease, err := os.ReadFile(host("/etc/os-release")); err == nil {
+		inventory.OS = parseOSRelease(osRelease)
+	}
+
+	if inventory.NICs, err = readNICs(host("/sys/class/net")); err != nil {
+		log.Warn().Err(err).Msg("error reading the network interfaces")
+	}
+
+	inventory.CephDaemons = collectCephDaemons(ctx, cfg)
+	return inventory, nil
+}
+
+func readString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// parseCPUInfo counts the threads, the cores of distinct core IDs and the
+// sockets of distinct physical IDs of /proc/cpuinfo. Without physical IDs,
+// e.g. on some ARM servers, every thread is a core of one socket.
+func parseCPUInfo(data []byte) CPU {
+	var cpu CPU
+	sockets := map[string]bool{}
+	cores := map[string]bool{}
+	var physicalID string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			cpu.Threads++
+			physicalID = ""
+		case "model name":
+			if cpu.Model == "" {
+				cpu.Model = value
+			}
+		case "physical id":
+			physicalID = value
+			sockets[value] = true
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		}
+	}
+
+	cpu.Sockets, cpu.Cores = len(sockets), len(cores)
+	if cpu.Sockets == 0 && cpu.Threads > 0 {
+		cpu.Sockets = 1
+	}
+	if cpu.Cores == 0 {
+		cpu.Cores = cpu.Threads
+	}
+	return cpu
+}
+
+// parseMemTotal returns MemTotal of /proc/meminfo in bytes
+func parseMemTotal(data []byte) (uint64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemTotal %q: %w", fields[1], err)
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("no MemTotal in meminfo")
+}
+
+// parseOSRelease returns PRETTY_NAME of os-release
+func parseOSRelease(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "PRETTY_NAME=")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return strings.Trim(value, `'"`)
+	}
+	return ""
+}
+
+// readNICs reads the physical network interfaces of sysfs, those backed by
+// a device; bridges, bonds, VLANs and veths are left out
+func readNICs(classNet string) ([]NIC, error) {
+	entries, err := os.ReadDir(classNet)
+	if err != nil {
+		return nil, err
+	}
+
+	var nics []NIC
+	for _, entry := range entries {
+		dir := filepath.Join(classNet, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+
+		nic := NIC{Name: entry.Name()}
+		nic.MAC, _ = readString(filepath.Join(dir, "address"))
+		nic.State, _ = readString(filepath.Join(dir, "operstate"))
+		if driver, err := filepath.EvalSymlinks(filepath.Join(dir, "device", "driver")); err == nil {
+			nic.Driver = filepath.Base(driver)
+		}
+		// Reading the speed fails with EINVAL without a link, and reads -1
+		// on some drivers
+		if speed, err := readString(filepath.Join(dir, "speed")); err == nil {
+			if mbps, err := strconv.ParseInt(speed, 10, 64); err == nil && mbps > 0 {
+				nic.SpeedMbps = mbps
+			}
+		}
+		if mtu, err := readString(filepath.Join(dir, "mtu")); err == nil {
+			nic.MTU, _ = strconv.Atoi(mtu)
+		}
+		nics = append(nics, nic)
+	}
+	return nics, nil
+}
+
+// collectCephDaemons asks the admin socket of every Ceph daemon of the node
+// for its version. Sockets that do not answer are left over by daemons that
+// stopped and are skipped.
+func collectCephDaemons(ctx context.Context, cfg NodeInventoryConfig) []CephDaemon {
+	if cfg.SocketGlob == "" {
+		return nil
+	}
+	sockets, err := filepath.Glob(cfg.SocketGlob)
+	if err != nil {
+		log.Warn().Err(err).Str("socket_glob", cfg.SocketGlob).Msg("error finding Ceph admin sockets")
+		return nil
+	}
+
+	var daemons []CephDaemon
+	for _, socket := range sockets {
+		cluster, daemon, ok := parseSocketName(socket)
+		if !ok {
+			continue
+		}
+		version, release, err := daemonVersion(ctx, socket, time.Duration(cfg.Timeout)*time.Second)
+		if err != nil {
+			log.Debug().Err(err).Str("socket", socket).Msg("Ceph admin socket did not answer")
+			continue
+		}
+		daemons = append(daemons, CephDaemon{Daemon: daemon, Cluster: cluster, Version: version, Release: release})
+	}
+	sort.Slice(daemons, func(i, j int) bool { return daemons[i].Daemon < daemons[j].Daemon })
+	return daemons
+}
+
+// parseSocketName returns the cluster and daemon of an admin socket named
+// <cluster>-<daemon>.asok, e.g. ceph-osd.7.asok
+func parseSocketName(socket string) (string, string, bool) {
+	name, found := strings.CutSuffix(filepath.Base(socket), ".asok")
+	if !found {
+		return "", "", false
+	}
+	cluster, daemon, found := strings.Cut(name, "-")
+	if !found || cluster == "" || !strings.Contains(daemon, ".") {
+		return "", "", false
+	}
+	return cluster, daemon, true
+}
+
+// daemonVersion runs version on an admin socket. Ceph answers with the
+// version and release, releases before Octopus with the full version
+// string, e.g. "ceph version 14.2.22 (...) nautilus (stable)".
+func daemonVersion(ctx context.Context, socket string, timeout time.Duration) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	data, err := adminsocket.Command(ctx, socket, "version")
+	if err != nil {
+		return "", "", err
+	}
+	var response struct {
+		Version string `json:"version"`
+		Release string `json:"release"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", "", fmt.Errorf("invalid version response: %w", err)
+	}
+
+	if rest, found := strings.CutPrefix(response.Version, "ceph version "); found {
+		fields := strings.Fields(rest)
+		if len(fields) > 0 {
+			response.Version = fields[0]
+		}
+		if len(fields) >= 3 && response.Release == "" {
+			response.Release = fields[2]
+		}
+	}
+	if response.Version == "" {
+		return "", "", fmt.Errorf("no version in response")
+	}
+	return response.Version, response.Release, nil
+}
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+import (
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+func PublishToNATS(nc *nats.Conn, inventory Inventory, cfg NodeInventoryConfig) error {
+	return schema.Publish(nc, cfg.NatsSubject, schema.NodeInventory, inventory)
+}
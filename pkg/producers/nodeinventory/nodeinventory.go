@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+func StartMonitoring(cfg NodeInventoryConfig) {
+	var nc *nats.Conn
+	var err error
+	if cfg.UseNats {
+		nc, err = natsutil.Connect(cfg.NatsURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error connecting to NATS")
+		}
+		defer nc.Close()
+	}
+
+	if cfg.Prometheus {
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+	}
+
+	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		inventory, err := collect(context.Background(), cfg)
+		if err != nil {
+			log.Error().Err(err).Str("host_root", cfg.HostRoot).Msg("error collecting the node inventory")
+			continue
+		}
+
+		if cfg.Prometheus {
+			PublishToPrometheus(inventory, cfg)
+		}
+
+		if cfg.UseNats {
+			if err := PublishToNATS(nc, inventory, cfg); err != nil {
+				log.Error().Err(err).Msg("error publishing the node inventory to NATS")
+			}
+		} else if !cfg.Prometheus {
+			inventoryJSON, err := json.Marshal(inventory)
+			if err != nil {
+				log.Error().Err(err).Msg("error marshalling the node inventory to JSON")
+				continue
+			}
+			fmt.Println(string(inventoryJSON))
+		}
+	}
+}
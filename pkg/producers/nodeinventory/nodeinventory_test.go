@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostRoot returns a copy of testdata/host with the network interfaces of
+// sysfs: eth0 with a link, eth1 without and the virtual lo and br0
+func hostRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS(filepath.Join("testdata", "host"))))
+
+	writeNIC := func(name string, files map[string]string, driver string) {
+		dir := filepath.Join(root, "sys", "class", "net", name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		for file, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0o644))
+		}
+		if driver == "" {
+			return
+		}
+		driverDir := filepath.Join(root, "sys", "bus", "pci", "drivers", driver)
+		require.NoError(t, os.MkdirAll(driverDir, 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "device"), 0o755))
+		require.NoError(t, os.Symlink(driverDir, filepath.Join(dir, "device", "driver")))
+	}
+	writeNIC("eth0", map[string]string{"address": "b8:ce:f6:01:02:03", "operstate": "up", "speed": "25000", "mtu": "9000"}, "mlx5_core")
+	writeNIC("eth1", map[string]string{"address": "b8:ce:f6:01:02:04", "operstate": "down", "speed": "-1", "mtu": "1500"}, "mlx5_core")
+	writeNIC("lo", map[string]string{"address": "00:00:00:00:00:00", "operstate": "unknown", "mtu": "65536"}, "")
+	writeNIC("br0", map[string]string{"address": "b8:ce:f6:01:02:03", "operstate": "up", "mtu": "9000"}, "")
+	return root
+}
+
+// serveVersion answers the version command on an admin socket
+func serveVersion(t *testing.T, path, response string) {
+	t.Helper()
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := bufio.NewReader(conn).ReadBytes(0); err != nil {
+					return
+				}
+				binary.Write(conn, binary.BigEndian, uint32(len(response)))
+				conn.Write([]byte(response))
+			}()
+		}
+	}()
+}
+
+func TestCollect(t *testing.T) {
+	sockets := t.TempDir()
+	serveVersion(t, filepath.Join(sockets, "ceph-osd.7.asok"), `{"version":"18.2.4","release":"reef","release_type":"stable"}`)
+	serveVersion(t, filepath.Join(sockets, "ceph-mon.a.asok"), `{"version":"ceph version 14.2.22 (ca74598065096e6fcbd8433c8779a2be0c889351) nautilus (stable)"}`)
+	// Left over by an OSD that stopped
+	require.NoError(t, os.WriteFile(filepath.Join(sockets, "ceph-osd.8.asok"), nil, 0o644))
+
+	cfg := NodeInventoryConfig{
+		HostRoot:   hostRoot(t),
+		SocketGlob: filepath.Join(sockets, "*.asok"),
+		Timeout:    1,
+		NodeName:   "node-1",
+		InstanceID: "node-inventory",
+	}
+	inventory, err := collect(context.Background(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "node-1", inventory.NodeName)
+	assert.Equal(t, "storage-node-1", inventory.Hostname)
+	assert.Equal(t, "5.15.0-119-generic", inventory.Kernel)
+	assert.Equal(t, "Ubuntu 22.04.4 LTS", inventory.OS)
+	assert.Equal(t, CPU{Model: "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz", Sockets: 2, Cores: 2, Threads: 4}, inventory.CPU)
+	assert.Equal(t, uint64(263846956*1024), inventory.MemoryBytes)
+	assert.Equal(t, []NIC{
+		{Name: "eth0", MAC: "b8:ce:f6:01:02:03", Driver: "mlx5_core", SpeedMbps: 25000, MTU: 9000, State: "up"},
+		{Name: "eth1", MAC: "b8:ce:f6:01:02:04", Driver: "mlx5_core", MTU: 1500, State: "down"},
+	}, inventory.NICs)
+	assert.Equal(t, []CephDaemon{
+		{Daemon: "mon.a", Cluster: "ceph", Version: "14.2.22", Release: "nautilus"},
+		{Daemon: "osd.7", Cluster: "ceph", Version: "18.2.4", Release: "reef"},
+	}, inventory.CephDaemons)
+}
+
+func TestCollect_MissingProc(t *testing.T) {
+	_, err := collect(context.Background(), NodeInventoryConfig{HostRoot: t.TempDir()})
+	assert.Error(t, err)
+}
+
+func TestParseCPUInfo_WithoutTopology(t *testing.T) {
+	// ARM servers list neither model names nor physical and core IDs
+	cpu := parseCPUInfo([]byte("processor\t: 0\nBogoMIPS\t: 50.00\n\nprocessor\t: 1\nBogoMIPS\t: 50.00\n"))
+	assert.Equal(t, CPU{Sockets: 1, Cores: 2, Threads: 2}, cpu)
+}
+
+func TestParseSocketName(t *testing.T) {
+	for socket, expected := range map[string][2]string{
+		"/var/run/ceph/ceph-osd.7.asok":                   {"ceph", "osd.7"},
+		"/var/run/ceph/backup-mon.a.asok":                 {"backup", "mon.a"},
+		"/var/run/ceph/ceph-client.rgw.a.4242.94012.asok": {"ceph", "client.rgw.a.4242.94012"},
+		"/var/lib/rook/exporter/ceph-mgr.b.asok":          {"ceph", "mgr.b"},
+	} {
+		cluster, daemon, ok := parseSocketName(socket)
+		assert.True(t, ok, socket)
+		assert.Equal(t, expected, [2]string{cluster, daemon}, socket)
+	}
+
+	for _, socket := range []string{"/var/run/ceph/ceph-osd.7.log", "/var/run/ceph/osd.7.asok", "/var/run/ceph/ceph-volume.asok"} {
+		_, _, ok := parseSocketName(socket)
+		assert.False(t, ok, socket)
+	}
+}
+
+func TestPublishToPrometheus(t *testing.T) {
+	cfg := NodeInventoryConfig{NodeName: "node-1", InstanceID: "node-inventory"}
+	inventory := Inventory{
+		Hostname:    "storage-node-1",
+		Kernel:      "5.15.0-119-generic",
+		OS:          "Ubuntu 22.04.4 LTS",
+		CPU:         CPU{Model: "AMD EPYC 7543", Sockets: 1, Cores: 32, Threads: 64},
+		MemoryBytes: 1 << 30,
+		NICs: []NIC{
+			{Name: "eth0", MAC: "b8:ce:f6:01:02:03", Driver: "mlx5_core", SpeedMbps: 25000, MTU: 9000, State: "up"},
+			{Name: "eth1", MAC: "b8:ce:f6:01:02:04", Driver: "mlx5_core", MTU: 1500, State: "down"},
+		},
+		CephDaemons: []CephDaemon{{Daemon: "osd.7", Cluster: "ceph", Version: "18.2.4", Release: "reef"}},
+	}
+	PublishToPrometheus(inventory, cfg)
+
+	expected := `
+# HELP node_inventory_nic_speed_bytes Link speed of a physical network interface in bytes per second
+# TYPE node_inventory_nic_speed_bytes gauge
+node_inventory_nic_speed_bytes{instance="node-inventory",nic="eth0",node="node-1"} 3.125e+09
+`
+	require.NoError(t, testutil.CollectAndCompare(nicSpeedGauge, strings.NewReader(expected)))
+	assert.Equal(t, 1, testutil.CollectAndCount(infoGauge))
+	assert.Equal(t, 2, testutil.CollectAndCount(nicInfoGauge))
+	assert.Equal(t, 2, testutil.CollectAndCount(nicMTUGauge))
+	assert.Equal(t, float64(32), testutil.ToFloat64(cpuCoresGauge.WithLabelValues("node-1", "node-inventory")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(cephVersionGauge.WithLabelValues("node-1", "node-inventory", "osd.7", "ceph", "18.2.4", "reef")))
+
+	// Daemons that are gone disappear
+	inventory.CephDaemons = nil
+	PublishToPrometheus(inventory, cfg)
+	assert.Equal(t, 0, testutil.CollectAndCount(cephVersionGauge))
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinventory
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var nodeLabels = []string{"node", "instance"}
+
+func newNodeGauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, append(nodeLabels, labels...))
+}
+
+var (
+	infoGauge        = newNodeGauge("node_inventory_info", "Static information about the node, always 1", "hostname", "kernel", "os", "cpu_model")
+	cpuSocketsGauge  = newNodeGauge("node_inventory_cpu_sockets", "Number of CPU sockets of the node")
+	cpuCoresGauge    = newNodeGauge("node_inventory_cpu_cores", "Number of physical CPU cores of the node")
+	cpuThreadsGauge  = newNodeGauge("node_inventory_cpu_threads", "Number of CPU threads of the node")
+	memoryGauge      = newNodeGauge("node_inventory_memory_bytes", "Total memory of the node")
+	nicInfoGauge     = newNodeGauge("node_inventory_nic_info", "Static information about a physical network interface, always 1", "nic", "mac", "driver", "state")
+	nicSpeedGauge    = newNodeGauge("node_inventory_nic_speed_bytes", "Link speed of a physical network interface in bytes per second", "nic")
+	nicMTUGauge      = newNodeGauge("node_inventory_nic_mtu_bytes", "MTU of a physical network interface", "nic")
+	cephVersionGauge = newNodeGauge("node_inventory_ceph_daemon_info", "Version of a Ceph daemon running on the node, always 1", "daemon", "cluster", "version", "release")
+	inventoryGauges  = []*prometheus.GaugeVec{
+		infoGauge, cpuSocketsGauge, cpuCoresGauge, cpuThreadsGauge, memoryGauge,
+		nicInfoGauge, nicSpeedGauge, nicMTUGauge, cephVersionGauge,
+	}
+)
+
+func init() {
+	for _, gauge := range inventoryGauges {
+		prometheus.MustRegister(gauge)
+	}
+}
+
+// PublishToPrometheus sets the metrics of the last inventory; interfaces
+// and daemons that are gone disappear
+func PublishToPrometheus(inventory Inventory, cfg NodeInventoryConfig) {
+	for _, gauge := range inventoryGauges {
+		gauge.Reset()
+	}
+
+	node := []string{cfg.NodeName, cfg.InstanceID}
+	with := func(values ...string) []string {
+		return append(append([]string{}, node...), values...)
+	}
+
+	infoGauge.WithLabelValues(with(inventory.Hostname, inventory.Kernel, inventory.OS, inventory.CPU.Model)...).Set(1)
+	cpuSocketsGauge.WithLabelValues(node...).Set(float64(inventory.CPU.Sockets))
+	cpuCoresGauge.WithLabelValues(node...).Set(float64(inventory.CPU.Cores))
+	cpuThreadsGauge.WithLabelValues(node...).Set(float64(inventory.CPU.Threads))
+	memoryGauge.WithLabelValues(node...).Set(float64(inventory.MemoryBytes))
+
+	for _, nic := range inventory.NICs {
+		nicInfoGauge.WithLabelValues(with(nic.Name, nic.MAC, nic.Driver, nic.State)...).Set(1)
+		if nic.SpeedMbps > 0 {
+			// The kernel reports megabits per second
+			nicSpeedGauge.WithLabelValues(with(nic.Name)...).Set(float64(nic.SpeedMbps) * 1e6 / 8)
+		}
+		if nic.MTU > 0 {
+			nicMTUGauge.WithLabelValues(with(nic.Name)...).Set(float64(nic.MTU))
+		}
+	}
+
+	for _, daemon := range inventory.CephDaemons {
+		cephVersionGauge.WithLabelValues(with(daemon.Daemon, daemon.Cluster, daemon.Version, daemon.Release)...).Set(1)
+	}
+}
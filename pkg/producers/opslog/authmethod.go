@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Authentication types of a request, from the authentication_type of RGW
+const (
+	AuthAnonymous = "anonymous" // No credentials
+	AuthTempURL   = "temp_url"  // A Swift temporary URL
+	AuthLocal     = "local"     // A key of an RGW user
+	AuthKeystone  = "keystone"  // A Keystone token or EC2 credentials
+	AuthLDAP      = "ldap"      // An LDAP token
+	AuthSTS       = "sts"       // Temporary credentials of an assumed role
+	AuthOther     = "other"     // An authentication type RGW logs that is not known here
+)
+
+// Signature versions of a request
+const (
+	SignatureV2      = "v2"      // AWS signature version 2, deprecated
+	SignatureV4      = "v4"      // AWS signature version 4
+	SignatureNone    = "none"    // Not signed, e.g. anonymous or a Keystone token
+	SignatureUnknown = "unknown" // Signed, but neither the URI nor the logged headers tell the version
+)
+
+// AuthType returns how the request of the entry authenticated. RGW logs
+// Local, Keystone, LDAP, STS or OIDC Provider; older releases log nothing,
+// then the access key tells an RGW user from an anonymous request.
+func AuthType(entry *S3OperationLog) string {
+	if entry.TempURL {
+		return AuthTempURL
+	}
+	if entry.User == "anonymous" {
+		return AuthAnonymous
+	}
+
+	switch strings.ToLower(entry.AuthenticationType) {
+	case "local":
+		return AuthLocal
+	case "keystone":
+		return AuthKeystone
+	case "ldap":
+		return AuthLDAP
+	case "sts", "oidc provider":
+		return AuthSTS
+	case "":
+		if entry.AccessKeyID == "" {
+			return AuthAnonymous
+		}
+		return AuthLocal
+	default:
+		return AuthOther
+	}
+}
+
+// SignatureVersion returns the AWS signature version of the request of the
+// entry. Presigned URLs name it in their query. Requests signed in their
+// headers only tell it if RGW logs the Authorization or the
+// X-Amz-Content-Sha256 header, see rgw_log_http_headers.
+func SignatureVersion(entry *S3OperationLog) string {
+	switch AuthType(entry) {
+	case AuthAnonymous, AuthTempURL:
+		return SignatureNone
+	}
+
+	if query := uriQuery(entry.URI); query != nil {
+		switch {
+		case query.Has("X-Amz-Algorithm") || query.Has("X-Amz-Credential"):
+			return SignatureV4
+		case query.Has("AWSAccessKeyId") && query.Has("Signature"):
+			return SignatureV2
+		}
+	}
+
+	if authorization, ok := entry.Header("Authorization"); ok {
+		switch {
+		case strings.HasPrefix(authorization, "AWS4-"):
+			return SignatureV4
+		case strings.HasPrefix(authorization, "AWS "):
+			return SignatureV2
+		}
+	}
+	if _, ok := entry.Header("X-Amz-Content-Sha256"); ok {
+		return SignatureV4
+	}
+
+	if entry.AccessKeyID == "" {
+		return SignatureNone
+	}
+	return SignatureUnknown
+}
+
+// uriQuery parses the query of the request line of the ops log, e.g.
+// "GET /bucket/key?X-Amz-Algorithm=AWS4-HMAC-SHA256 HTTP/1.1"
+func uriQuery(uri string) url.Values {
+	parts := strings.Fields(uri)
+	if len(parts) < 2 {
+		return nil
+	}
+	_, rawQuery, ok := strings.Cut(parts[1], "?")
+	if !ok {
+		return nil
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil
+	}
+	return query
+}
+
+// observeAuthMethod counts the request of the entry by its authentication
+// type and signature version
+func observeAuthMethod(logEntry *S3OperationLog, user, tenant string) {
+	requestsByAuthMethod.WithLabelValues(tenant, user, AuthType(logEntry), SignatureVersion(logEntry)).Inc()
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMethod(t *testing.T) {
+	testCases := []struct {
+		name      string
+		entry     S3OperationLog
+		authType  string
+		signature string
+	}{
+		{
+			name:      "anonymous",
+			entry:     S3OperationLog{User: "anonymous", URI: "GET /site/index.html HTTP/1.1"},
+			authType:  AuthAnonymous,
+			signature: SignatureNone,
+		},
+		{
+			name:      "swift temp url",
+			entry:     S3OperationLog{User: "alice", TempURL: true, URI: "GET /swift/v1/c/o?temp_url_sig=abc HTTP/1.1"},
+			authType:  AuthTempURL,
+			signature: SignatureNone,
+		},
+		{
+			name: "presigned v4",
+			entry: S3OperationLog{
+				User: "alice", AuthenticationType: "Local", AccessKeyID: "AK",
+				URI: "GET /photos/cat.jpg?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AK%2F20250101 HTTP/1.1",
+			},
+			authType:  AuthLocal,
+			signature: SignatureV4,
+		},
+		{
+			name: "presigned v2",
+			entry: S3OperationLog{
+				User: "alice", AuthenticationType: "Local", AccessKeyID: "AK",
+				URI: "GET /photos/cat.jpg?AWSAccessKeyId=AK&Expires=1700000000&Signature=abc HTTP/1.1",
+			},
+			authType:  AuthLocal,
+			signature: SignatureV2,
+		},
+		{
+			name: "v4 by the logged content hash",
+			entry: S3OperationLog{
+				User: "alice", AuthenticationType: "Local", AccessKeyID: "AK", URI: "PUT /photos/cat.jpg HTTP/1.1",
+				HTTPXHeaders: []map[string]string{{"HTTP_X_AMZ_CONTENT_SHA256": "UNSIGNED-PAYLOAD"}},
+			},
+			authType:  AuthLocal,
+			signature: SignatureV4,
+		},
+		{
+			name: "v2 by the logged authorization",
+			entry: S3OperationLog{
+				User: "alice", AuthenticationType: "Keystone", AccessKeyID: "EC2", URI: "GET /photos HTTP/1.1",
+				HTTPXHeaders: []map[string]string{{"http_authorization": "AWS EC2:c2lnbmF0dXJl"}},
+			},
+			authType:  AuthKeystone,
+			signature: SignatureV2,
+		},
+		{
+			name:      "signed in unlogged headers",
+			entry:     S3OperationLog{User: "alice", AuthenticationType: "Local", AccessKeyID: "AK", URI: "GET /photos HTTP/1.1"},
+			authType:  AuthLocal,
+			signature: SignatureUnknown,
+		},
+		{
+			name:      "keystone token",
+			entry:     S3OperationLog{User: "proj$proj", AuthenticationType: "Keystone", URI: "GET /swift/v1/c HTTP/1.1"},
+			authType:  AuthKeystone,
+			signature: SignatureNone,
+		},
+		{
+			name:      "assumed role",
+			entry:     S3OperationLog{User: "alice", AuthenticationType: "STS", AccessKeyID: "ASIA", URI: "GET /photos HTTP/1.1"},
+			authType:  AuthSTS,
+			signature: SignatureUnknown,
+		},
+		{
+			name:      "no type logged",
+			entry:     S3OperationLog{User: "alice", AccessKeyID: "AK", URI: "GET /photos HTTP/1.1"},
+			authType:  AuthLocal,
+			signature: SignatureUnknown,
+		},
+		{
+			name:      "unknown type",
+			entry:     S3OperationLog{User: "alice", AuthenticationType: "Kerberos", URI: "GET /photos HTTP/1.1"},
+			authType:  AuthOther,
+			signature: SignatureNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.authType, AuthType(&tc.entry))
+			assert.Equal(t, tc.signature, SignatureVersion(&tc.entry))
+		})
+	}
+}
+
+func TestS3OperationLog_HTTPXHeaders(t *testing.T) {
+	var entry S3OperationLog
+	require.NoError(t, json.Unmarshal([]byte(`{"user":"alice","http_x_headers":[{"HTTP_X_FORWARDED_FOR":"192.0.2.1"},{"HTTP_X_AMZ_CONTENT_SHA256":"abc"}]}`), &entry))
+
+	value, ok := entry.Header("X-Amz-Content-Sha256")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", value)
+
+	_, ok = entry.Header("Authorization")
+	assert.False(t, ok)
+}
+
+func TestMetricsUpdate_AuthMethods(t *testing.T) {
+	before := readCounterValue(t, requestsByAuthMethod, "auth-tenant", "carol", AuthLocal, SignatureV2)
+
+	metrics := NewMetrics()
+	metrics.Update(S3OperationLog{
+		User: "carol$auth-tenant", Bucket: "photos", AuthenticationType: "Local", AccessKeyID: "AK",
+		URI: "GET /photos?AWSAccessKeyId=AK&Signature=abc HTTP/1.1", HTTPStatus: "200",
+	}, &MetricsConfig{TrackAuthMethods: true})
+
+	assert.Equal(t, before+1, readCounterValue(t, requestsByAuthMethod, "auth-tenant", "carol", AuthLocal, SignatureV2))
+}
@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+// Classes of the entries under backpressure, in the order they are dropped
+const (
+	EventClassHealthCheck = "health_check" // 2xx GET or HEAD of an allowlisted health checker
+	EventClassRead        = "read"         // Any other successful GET or HEAD
+	EventClassWrite       = "write"        // Any other successful request
+	EventClassError       = "error"        // Requests that failed, or of unknown status
+)
+
+// Priorities of the classes, the lowest is dropped first
+const (
+	priorityHealthCheck = iota
+	priorityRead
+	priorityWrite
+	priorityError
+)
+
+// eventClasses are the classes by priority
+var eventClasses = []string{EventClassHealthCheck, EventClassRead, EventClassWrite, EventClassError}
+
+// Validate checks the queue size and the CIDRs of the health checkers
+func (cfg BackpressureConfig) Validate() error {
+	if cfg.QueueSize < 0 {
+		return fmt.Errorf("queue size must not be negative")
+	}
+	_, err := newEventClassifier(cfg)
+	return err
+}
+
+// eventClassifier tells the class of an entry
+type eventClassifier struct {
+	userAgents []string
+	cidrs      []netip.Prefix
+}
+
+func newEventClassifier(cfg BackpressureConfig) (*eventClassifier, error) {
+	cidrs, err := parseCIDRs(cfg.HealthCheckCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health check CIDRs: %w", err)
+	}
+	c := &eventClassifier{cidrs: cidrs}
+	for _, agent := range strings.Split(cfg.HealthCheckUserAgents, ",") {
+		if agent = strings.ToLower(strings.TrimSpace(agent)); agent != "" {
+			c.userAgents = append(c.userAgents, agent)
+		}
+	}
+	return c, nil
+}
+
+// classify returns the priority of the entry
+func (c *eventClassifier) classify(entry *S3OperationLog) int {
+	status, err := strconv.Atoi(entry.HTTPStatus)
+	if err != nil || status >= 400 {
+		return priorityError
+	}
+
+	method := ExtractHTTPMethod(entry.URI)
+	read := method == "GET" || method == "HEAD" || (method == "UNKNOWN" && isReadOperation(entry.Operation))
+	switch {
+	case !read:
+		return priorityWrite
+	case status < 300 && c.isHealthCheck(entry):
+		return priorityHealthCheck
+	default:
+		return priorityRead
+	}
+}
+
+// isHealthCheck reports whether the entry comes from an allowlisted health
+// checker, by the prefix of its user agent or its client address
+func (c *eventClassifier) isHealthCheck(entry *S3OperationLog) bool {
+	agent := strings.ToLower(entry.UserAgent)
+	for _, prefix := range c.userAgents {
+		if strings.HasPrefix(agent, prefix) {
+			return true
+		}
+	}
+	if len(c.cidrs) == 0 {
+		return false
+	}
+	ip, err := netip.ParseAddr(entry.RemoteAddr)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(entry.RemoteAddr)
+		if err != nil {
+			return false
+		}
+		ip = addrPort.Addr()
+	}
+	return containsAddr(c.cidrs, ip.Unmap())
+}
+
+type queuedEvent struct {
+	seq     uint64
+	publish func()
+}
+
+// eventQueue decouples publishing the entries from reading them. When the
+// publishing falls behind, e.g. on a slow NATS server, and the queue is full,
+// a new entry displaces the oldest entry of the lowest class below its own,
+// or is dropped if there is none. Entries are published in the order they
+// were queued.
+type eventQueue struct {
+	classifier *eventClassifier
+	capacity   int
+
+	mu       sync.Mutex
+	ready    *sync.Cond
+	queues   [][]queuedEvent // By priority
+	size     int
+	seq      uint64
+	closed   bool
+	lastWarn time.Time
+	dropped  int
+	done     chan struct{}
+}
+
+// newEventQueue starts the queue of cfg, nil if QueueSize is not positive;
+// a nil queue publishes synchronously
+func newEventQueue(cfg BackpressureConfig) (*eventQueue, error) {
+	if cfg.QueueSize <= 0 {
+		return nil, nil
+	}
+	classifier, err := newEventClassifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &eventQueue{
+		classifier: classifier,
+		capacity:   cfg.QueueSize,
+		queues:     make([][]queuedEvent, len(eventClasses)),
+		done:       make(chan struct{}),
+	}
+	q.ready = sync.NewCond(&q.mu)
+	go func() {
+		defer close(q.done)
+		telemetry.RunWorker(context.Background(), "ops-log.event-queue", func(context.Context) { q.run() })
+	}()
+
+	log.Info().
+		Int("queue_size", cfg.QueueSize).
+		Int("health_check_user_agents", len(classifier.userAgents)).
+		Int("health_check_cidrs", len(classifier.cidrs)).
+		Msg("Backpressure queue initialized")
+	return q, nil
+}
+
+// Submit queues publish, the publishing of entry. The entry is only read for
+// its class; publish must not refer to memory the caller reuses.
+func (q *eventQueue) Submit(entry *S3OperationLog, publish func()) {
+	if q == nil {
+		publish()
+		return
+	}
+	priority := q.classifier.classify(entry)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	if q.size >= q.capacity {
+		victim := -1
+		for p := 0; p < priority; p++ {
+			if len(q.queues[p]) > 0 {
+				victim = p
+				break
+			}
+		}
+		if victim < 0 {
+			q.drop(priority)
+			return
+		}
+		q.queues[victim][0] = queuedEvent{}
+		q.queues[victim] = q.queues[victim][1:]
+		q.size--
+		q.drop(victim)
+	}
+
+	q.seq++
+	q.queues[priority] = append(q.queues[priority], queuedEvent{seq: q.seq, publish: publish})
+	q.size++
+	eventQueueLength.Set(float64(q.size))
+	q.ready.Signal()
+}
+
+// drop counts a dropped entry of priority, warning at most every 10 seconds
+func (q *eventQueue) drop(priority int) {
+	eventsDropped.WithLabelValues(eventClasses[priority]).Inc()
+	q.dropped++
+	if now := time.Now(); now.Sub(q.lastWarn) >= 10*time.Second {
+		log.Warn().Int("dropped", q.dropped).Int("queue_size", q.capacity).Msg("Publishing ops log entries falls behind, dropping entries of the lowest classes")
+		q.lastWarn = now
+		q.dropped = 0
+	}
+}
+
+// next waits for the oldest queued entry, false once the queue is closed and
+// empty
+func (q *eventQueue) next() (func(), bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.size == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if q.size == 0 {
+		return nil, false
+	}
+
+	oldest := -1
+	for p, queue := range q.queues {
+		if len(queue) > 0 && (oldest < 0 || queue[0].seq < q.queues[oldest][0].seq) {
+			oldest = p
+		}
+	}
+	event := q.queues[oldest][0]
+	q.queues[oldest][0] = queuedEvent{}
+	q.queues[oldest] = q.queues[oldest][1:]
+	q.size--
+	eventQueueLength.Set(float64(q.size))
+	return event.publish, true
+}
+
+func (q *eventQueue) run() {
+	for {
+		publish, ok := q.next()
+		if !ok {
+			return
+		}
+		publish()
+	}
+}
+
+// Close publishes the queued entries and stops the queue
+func (q *eventQueue) Close() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.closed = true
+	q.ready.Signal()
+	q.mu.Unlock()
+	<-q.done
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventClassifier(t *testing.T) {
+	classifier, err := newEventClassifier(BackpressureConfig{
+		HealthCheckUserAgents: "kube-probe, HAProxy",
+		HealthCheckCIDRs:      "10.0.0.0/24",
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		entry    S3OperationLog
+		priority int
+	}{
+		"probe by user agent": {S3OperationLog{URI: "GET /health HTTP/1.1", HTTPStatus: "200", UserAgent: "kube-probe/1.29"}, priorityHealthCheck},
+		"probe by address":    {S3OperationLog{URI: "HEAD /bucket HTTP/1.1", HTTPStatus: "200", RemoteAddr: "10.0.0.7:41234"}, priorityHealthCheck},
+		"probe by case":       {S3OperationLog{URI: "GET / HTTP/1.1", HTTPStatus: "204", UserAgent: "haproxy"}, priorityHealthCheck},
+		"failed probe":        {S3OperationLog{URI: "GET /health HTTP/1.1", HTTPStatus: "503", UserAgent: "kube-probe/1.29"}, priorityError},
+		"probe redirected":    {S3OperationLog{URI: "GET /health HTTP/1.1", HTTPStatus: "301", UserAgent: "kube-probe/1.29"}, priorityRead},
+		"probe writing":       {S3OperationLog{URI: "PUT /bucket/key HTTP/1.1", HTTPStatus: "200", UserAgent: "kube-probe/1.29"}, priorityWrite},
+		"read":                {S3OperationLog{URI: "GET /bucket/key HTTP/1.1", HTTPStatus: "200", RemoteAddr: "192.0.2.1"}, priorityRead},
+		"read by operation":   {S3OperationLog{Operation: "list_bucket", HTTPStatus: "200"}, priorityRead},
+		"write":               {S3OperationLog{URI: "DELETE /bucket/key HTTP/1.1", HTTPStatus: "204"}, priorityWrite},
+		"not found":           {S3OperationLog{URI: "GET /bucket/key HTTP/1.1", HTTPStatus: "404"}, priorityError},
+		"no status":           {S3OperationLog{URI: "GET /bucket/key HTTP/1.1"}, priorityError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.priority, classifier.classify(&tc.entry))
+		})
+	}
+}
+
+func TestBackpressureConfig_Validate(t *testing.T) {
+	assert.NoError(t, BackpressureConfig{}.Validate())
+	assert.NoError(t, BackpressureConfig{QueueSize: 100, HealthCheckCIDRs: "10.0.0.0/8, fd00::/8"}.Validate())
+	assert.Error(t, BackpressureConfig{QueueSize: -1}.Validate())
+	assert.Error(t, BackpressureConfig{QueueSize: 100, HealthCheckCIDRs: "10.0.0.0"}.Validate())
+}
+
+func TestEventQueue_NilPublishesSynchronously(t *testing.T) {
+	q, err := newEventQueue(BackpressureConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, q)
+
+	published := false
+	q.Submit(&S3OperationLog{}, func() { published = true })
+	assert.True(t, published)
+	q.Close()
+}
+
+func TestEventQueue_DropsLowestClassFirst(t *testing.T) {
+	q, err := newEventQueue(BackpressureConfig{QueueSize: 3, HealthCheckUserAgents: "kube-probe"})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var published []string
+	publish := func(name string) func() {
+		return func() {
+			mu.Lock()
+			published = append(published, name)
+			mu.Unlock()
+		}
+	}
+	dropped := func(class string) float64 {
+		return testutil.ToFloat64(eventsDropped.WithLabelValues(class))
+	}
+	before := map[string]float64{}
+	for _, class := range eventClasses {
+		before[class] = dropped(class)
+	}
+
+	probe := &S3OperationLog{URI: "GET / HTTP/1.1", HTTPStatus: "200", UserAgent: "kube-probe/1.29"}
+	read := &S3OperationLog{URI: "GET /b/k HTTP/1.1", HTTPStatus: "200"}
+	write := &S3OperationLog{URI: "PUT /b/k HTTP/1.1", HTTPStatus: "200"}
+	failed := &S3OperationLog{URI: "PUT /b/k HTTP/1.1", HTTPStatus: "500"}
+
+	// Block the publishing until the queue is full
+	blocked, release := make(chan struct{}), make(chan struct{})
+	q.Submit(write, func() {
+		close(blocked)
+		<-release
+		publish("first")()
+	})
+	<-blocked
+
+	q.Submit(probe, publish("probe"))
+	q.Submit(read, publish("read-1"))
+	q.Submit(write, publish("write"))
+	// Full: the error displaces the probe, read-2 finds no lower class and is
+	// dropped, error-2 displaces read-1 and probe-2 is dropped
+	q.Submit(failed, publish("error"))
+	q.Submit(read, publish("read-2"))
+	q.Submit(failed, publish("error-2"))
+	q.Submit(probe, publish("probe-2"))
+
+	close(release)
+	q.Close()
+
+	assert.Equal(t, []string{"first", "write", "error", "error-2"}, published)
+	assert.Equal(t, float64(2), dropped(EventClassHealthCheck)-before[EventClassHealthCheck])
+	assert.Equal(t, float64(2), dropped(EventClassRead)-before[EventClassRead])
+	assert.Equal(t, float64(0), dropped(EventClassWrite)-before[EventClassWrite])
+	assert.Equal(t, float64(0), dropped(EventClassError)-before[EventClassError])
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// bucketDataRecord is the part of the bucket records of the radosgwusage
+// producer the tags are read from
+type bucketDataRecord struct {
+	Bucket string            `json:"bucket"`
+	Tenant string            `json:"tenant"`
+	Owner  string            `json:"owner"`
+	Tagset map[string]string `json:"tagset"`
+}
+
+// bucketMetadata is the owner and the values of the selected tags of a bucket
+type bucketMetadata struct {
+	owner  string
+	values []string // in the order of BucketTagCache.tags, empty if the bucket has no such tag
+}
+
+// BucketTagCache keeps the owner and selected tags of every bucket, read
+// from the bucket data KV of the radosgwusage producer, so the requests of
+// a bucket can be charged to its cost center or environment.
+type BucketTagCache struct {
+	tags []string // selected tag keys, e.g. cost-center
+
+	mu      sync.RWMutex
+	buckets map[string]bucketMetadata // "tenant|bucket"
+	keys    map[string]string         // KV key -> "tenant|bucket", to forget deleted records
+}
+
+// NewBucketTagCache returns an empty cache of the tags
+func NewBucketTagCache(tags []string) *BucketTagCache {
+	return &BucketTagCache{
+		tags:    tags,
+		buckets: map[string]bucketMetadata{},
+		keys:    map[string]string{},
+	}
+}
+
+// ParseBucketTags parses a comma-separated list of tag keys
+func ParseBucketTags(tags string) []string {
+	var keys []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+// Tags returns the selected tag keys
+func (c *BucketTagCache) Tags() []string {
+	return c.tags
+}
+
+// invalidLabelChars are replaced by underscores in the label of a tag
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// TagLabel returns the Prometheus label of a tag key, e.g. cost-center ->
+// tag_cost_center
+func TagLabel(tag string) string {
+	return "tag_" + invalidLabelChars.ReplaceAllString(tag, "_")
+}
+
+// WatchKV fills the cache from the bucket data KV and keeps it up to date
+// until the connection closes
+func (c *BucketTagCache) WatchKV(nc *nats.Conn, bucket string) error {
+	js, err := nc.JetStream()
+	if err != nil {
+		return fmt.Errorf("failed to get JetStream context: %w", err)
+	}
+	kv, err := js.KeyValue(bucket)
+	if err != nil {
+		return fmt.Errorf("failed to open bucket data KV %s: %w", bucket, err)
+	}
+	watcher, err := kv.WatchAll()
+	if err != nil {
+		return fmt.Errorf("failed to watch bucket data KV %s: %w", bucket, err)
+	}
+
+	go func() {
+		defer watcher.Stop()
+		telemetry.RunWorker(context.Background(), "ops-log.bucket-tags", func(context.Context) {
+			c.watch(bucket, watcher)
+		})
+	}()
+	return nil
+}
+
+// watch applies the updates of the KV until the watcher stops
+func (c *BucketTagCache) watch(bucket string, watcher nats.KeyWatcher) {
+	for entry := range watcher.Updates() {
+		if entry == nil {
+			log.Info().Str("kv", bucket).Int("buckets", c.size()).Msg("Loaded the bucket tags")
+			continue
+		}
+		if entry.Operation() != nats.KeyValuePut {
+			c.forget(entry.Key())
+			continue
+		}
+		if err := c.apply(entry.Key(), entry.Value()); err != nil {
+			log.Warn().Err(err).Str("key", entry.Key()).Msg("Failed to decode bucket record")
+		}
+	}
+}
+
+// apply stores the owner and tags of a bucket record
+func (c *BucketTagCache) apply(key string, data []byte) error {
+	var record bucketDataRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+
+	// "|" separates the values in the metric keys
+	metadata := bucketMetadata{owner: strings.ReplaceAll(record.Owner, "|", "_"), values: make([]string, len(c.tags))}
+	for i, tag := range c.tags {
+		metadata.values[i] = strings.ReplaceAll(record.Tagset[tag], "|", "_")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := bucketTagKey(record.Tenant, record.Bucket)
+	c.buckets[id] = metadata
+	c.keys[key] = id
+	return nil
+}
+
+// forget drops the bucket of a deleted record
+func (c *BucketTagCache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := c.keys[key]; ok {
+		delete(c.buckets, id)
+		delete(c.keys, key)
+	}
+}
+
+func (c *BucketTagCache) size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.buckets)
+}
+
+// key returns the "owner|tag 1|...|tag n" key of the metrics of a bucket.
+// Unknown buckets are counted with an empty owner and empty tags, so the
+// tagged metrics still add up to the total.
+func (c *BucketTagCache) key(tenant, bucket string) string {
+	c.mu.RLock()
+	metadata, ok := c.buckets[bucketTagKey(tenant, bucket)]
+	c.mu.RUnlock()
+	if !ok {
+		return strings.Repeat("|", len(c.tags))
+	}
+	return metadata.owner + "|" + strings.Join(metadata.values, "|")
+}
+
+// bucketTagKey identifies a bucket the way the ops log entries do, "none"
+// standing for buckets without a tenant
+func bucketTagKey(tenant, bucket string) string {
+	if tenant == "" {
+		tenant = "none"
+	}
+	return tenant + "|" + bucket
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBucketTags(t *testing.T) {
+	assert.Equal(t, []string{"cost-center", "environment"}, ParseBucketTags(" cost-center,,environment "))
+	assert.Nil(t, ParseBucketTags(""))
+}
+
+func TestTagLabel(t *testing.T) {
+	assert.Equal(t, "tag_cost_center", TagLabel("cost-center"))
+	assert.Equal(t, "tag_app_kubernetes_io_name", TagLabel("app.kubernetes.io/name"))
+}
+
+func TestBucketTagCache(t *testing.T) {
+	cache := NewBucketTagCache([]string{"cost-center", "environment"})
+
+	require.NoError(t, cache.apply("user1.tenant1.bucket1",
+		[]byte(`{"bucket":"bucket1","tenant":"tenant1","owner":"tenant1$user1","tagset":{"cost-center":"cc-42","environment":"prod","team":"storage"}}`)))
+	require.NoError(t, cache.apply("user2.X.bucket2",
+		[]byte(`{"bucket":"bucket2","tenant":"","owner":"user2","tagset":{"environment":"dev|test"}}`)))
+	assert.Error(t, cache.apply("broken", []byte(`{`)))
+
+	assert.Equal(t, "tenant1$user1|cc-42|prod", cache.key("tenant1", "bucket1"))
+	assert.Equal(t, "user2||dev_test", cache.key("none", "bucket2"))
+	assert.Equal(t, "||", cache.key("tenant1", "unknown"))
+
+	cache.forget("user1.tenant1.bucket1")
+	assert.Equal(t, "||", cache.key("tenant1", "bucket1"))
+	assert.Equal(t, 1, cache.size())
+}
+
+func TestMetricsUpdate_BucketTags(t *testing.T) {
+	cache := NewBucketTagCache([]string{"cost-center"})
+	require.NoError(t, cache.apply("user1.tenant1.bucket1",
+		[]byte(`{"bucket":"bucket1","tenant":"tenant1","owner":"tenant1$user1","tagset":{"cost-center":"cc-42"}}`)))
+	config := &MetricsConfig{BucketTags: cache}
+	m := NewMetrics()
+
+	m.Update(S3OperationLog{User: "user1$tenant1", Bucket: "bucket1", URI: "PUT /bucket1/a HTTP/1.1", HTTPStatus: "200", BytesReceived: 100}, config)
+	m.Update(S3OperationLog{User: "user1$tenant1", Bucket: "bucket1", URI: "GET /bucket1/a HTTP/1.1", HTTPStatus: "200", BytesSent: 100}, config)
+	m.Update(S3OperationLog{User: "user2$tenant1", Bucket: "untagged", URI: "GET /untagged/a HTTP/1.1", HTTPStatus: "200", BytesSent: 10}, config)
+	// Requests without a bucket are not charged to any
+	m.Update(S3OperationLog{User: "user1$tenant1", URI: "GET / HTTP/1.1", HTTPStatus: "200"}, config)
+
+	aggregated := m.Aggregate(config)
+	assert.Equal(t, []string{"cost-center"}, aggregated.BucketTagKeys)
+	assert.Equal(t, map[string]uint64{"tenant1$user1|cc-42": 2, "|": 1}, aggregated.RequestsByBucketTags)
+	assert.Equal(t, map[string]uint64{"tenant1$user1|cc-42": 100, "|": 10}, aggregated.BytesSentByBucketTags)
+	assert.Equal(t, uint64(100), aggregated.BytesReceivedByBucketTags["tenant1$user1|cc-42"])
+
+	assert.Nil(t, m.Aggregate(&MetricsConfig{}).RequestsByBucketTags)
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketConcurrency estimates the requests in flight of the buckets between
+// two Prometheus updates
+var bucketConcurrency = newConcurrencyTracker(time.Now())
+
+// bucketInFlight are the estimated requests in flight of a bucket in a window
+type bucketInFlight struct {
+	max float64 // The most requests in flight in a second of the window, on average in that second
+	avg float64 // The requests in flight on average over the window
+}
+
+// concurrencyTracker estimates the requests in flight per bucket. A request
+// is in flight from its time, when RGW received it, for its total_time. The
+// busy milliseconds of the requests of a bucket are summed per second of the
+// window: a second with 2500 busy milliseconds had 2.5 requests in flight on
+// average. Only the part of a request within the window counts, the part
+// before was logged too late for the previous window.
+type concurrencyTracker struct {
+	mu      sync.Mutex
+	start   time.Time
+	seconds map[string]map[int64]int64 // "tenant|bucket" -> Unix second -> busy milliseconds
+}
+
+func newConcurrencyTracker(start time.Time) *concurrencyTracker {
+	return &concurrencyTracker{start: start, seconds: make(map[string]map[int64]int64)}
+}
+
+// observe adds the time the request of the entry was in flight to its
+// bucket. Entries without a bucket, a time or a total_time are skipped.
+func (t *concurrencyTracker) observe(logEntry S3OperationLog, tenant string) {
+	if logEntry.Bucket == "" || logEntry.TotalTime <= 0 {
+		return
+	}
+	received, err := time.Parse("2006-01-02T15:04:05.999999Z", logEntry.Time)
+	if err != nil {
+		return
+	}
+	from := received.UnixMilli()
+	to := from + int64(logEntry.TotalTime)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	from = max(from, t.start.UnixMilli())
+	if from >= to {
+		return
+	}
+
+	key := tenant + "|" + logEntry.Bucket
+	seconds := t.seconds[key]
+	if seconds == nil {
+		seconds = make(map[int64]int64)
+		t.seconds[key] = seconds
+	}
+	for second := from / 1000; second*1000 < to; second++ {
+		seconds[second] += min(to, (second+1)*1000) - max(from, second*1000)
+	}
+}
+
+// flush returns the requests in flight of the buckets in the window ending
+// at end and starts the next window
+func (t *concurrencyTracker) flush(end time.Time) map[string]bucketInFlight {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	window := end.Sub(t.start).Milliseconds()
+	inFlight := make(map[string]bucketInFlight, len(t.seconds))
+	for key, seconds := range t.seconds {
+		var busy, peak int64
+		for _, ms := range seconds {
+			busy += ms
+			peak = max(peak, ms)
+		}
+		bucket := bucketInFlight{max: float64(peak) / 1000}
+		if window > 0 {
+			bucket.avg = float64(busy) / float64(window)
+		}
+		inFlight[key] = bucket
+	}
+
+	t.start = end
+	t.seconds = make(map[string]map[int64]int64)
+	return inFlight
+}
+
+// publishBucketConcurrency sets the gauges of the requests in flight of the
+// window ending at end. Buckets without requests in the window are dropped.
+func publishBucketConcurrency(end time.Time) {
+	bucketRequestsInFlightMax.Reset()
+	bucketRequestsInFlightAvg.Reset()
+	for key, bucket := range bucketConcurrency.flush(end) {
+		tenant, name, _ := strings.Cut(key, "|")
+		bucketRequestsInFlightMax.WithLabelValues(tenant, name).Set(bucket.max)
+		bucketRequestsInFlightAvg.WithLabelValues(tenant, name).Set(bucket.avg)
+	}
+}
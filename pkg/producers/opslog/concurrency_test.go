@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyTracker(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	tracker := newConcurrencyTracker(start)
+
+	request := func(bucket, received string, totalTime int) S3OperationLog {
+		return S3OperationLog{Bucket: bucket, Time: received, TotalTime: totalTime}
+	}
+	// Three overlapping uploads in the first second, one of them until the
+	// middle of the second
+	tracker.observe(request("photos", "2025-03-01T10:00:00.000000Z", 1000), "proj")
+	tracker.observe(request("photos", "2025-03-01T10:00:00.200000Z", 800), "proj")
+	tracker.observe(request("photos", "2025-03-01T10:00:00.500000Z", 1000), "proj")
+	// A request started before the window only counts from its start
+	tracker.observe(request("logs", "2025-03-01T09:59:59.000000Z", 2000), "none")
+	// Entries without a bucket, duration or time are skipped
+	tracker.observe(request("", "2025-03-01T10:00:01.000000Z", 100), "proj")
+	tracker.observe(request("photos", "2025-03-01T10:00:01.000000Z", 0), "proj")
+	tracker.observe(request("photos", "not a time", 100), "proj")
+
+	inFlight := tracker.flush(start.Add(10 * time.Second))
+	assert.Len(t, inFlight, 2)
+	// 2300 busy ms in the first second, 500 in the second
+	assert.InDelta(t, 2.3, inFlight["proj|photos"].max, 1e-9)
+	assert.InDelta(t, 0.28, inFlight["proj|photos"].avg, 1e-9)
+	assert.InDelta(t, 1, inFlight["none|logs"].max, 1e-9)
+	assert.InDelta(t, 0.1, inFlight["none|logs"].avg, 1e-9)
+
+	// The next window starts empty
+	assert.Empty(t, tracker.flush(start.Add(20*time.Second)))
+}
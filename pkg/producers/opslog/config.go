@@ -4,6 +4,13 @@
 
 package opslog
 
+import (
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+)
+
 // AuditSinkConfig defines the RabbitMQ audit sink configuration.
 type AuditSinkConfig struct {
 	Enabled     bool   `mapstructure:"enabled"`
@@ -47,13 +54,67 @@ type AuditSinkConfig struct {
 	DenyDomains  string `mapstructure:"deny_domains"`
 }
 
+// LokiSinkConfig defines the Loki push sink of the raw ops log entries.
+type LokiSinkConfig struct {
+	URL    string // Base URL of Loki, e.g. http://loki:3100; empty disables the sink
+	Tenant string // Sent as X-Scope-OrgID to multi-tenant Loki; empty sends none
+	// Labels is a comma-separated list of entry fields mapped to stream labels:
+	// tenant, bucket, status_class, operation and method. Every combination is
+	// a stream of its own, so keep it to fields of bounded cardinality.
+	Labels    string
+	BatchSize int           // Entries per push, defaults to 1000
+	BatchWait time.Duration // Longest time an entry waits for its batch, defaults to 5s
+	QueueSize int           // Entries buffered while pushing; more are dropped, defaults to 10000
+}
+
+// BackpressureConfig defines the queue between reading the ops log entries
+// and publishing them to NATS and stdout.
+type BackpressureConfig struct {
+	// QueueSize is the number of entries waiting to be published. When the
+	// queue is full, entries of health checks are dropped before other reads,
+	// reads before writes and writes before errors. 0 publishes every entry
+	// before reading the next one.
+	QueueSize int
+	// HealthCheckUserAgents and HealthCheckCIDRs are comma-separated user
+	// agent prefixes and client CIDRs of health checkers, e.g. load balancer
+	// probes; their successful reads are dropped first.
+	HealthCheckUserAgents string
+	HealthCheckCIDRs      string
+}
+
+// SlowRequestsConfig defines the capture of the requests above a latency
+// threshold, kept for the debug server and published to NATS.
+type SlowRequestsConfig struct {
+	Threshold time.Duration // Latency above which a request is captured, 0 for the bucket thresholds only
+	// BucketThresholds are comma-separated <bucket>=<duration> thresholds
+	// overriding Threshold, e.g. backups=30s,web=500ms
+	BucketThresholds string
+	BufferSize       int // Requests kept, the oldest are overwritten; defaults to 100
+}
+
+// KeystoneConfig defines the resolution of the Keystone project IDs of the
+// tenants to project names.
+type KeystoneConfig struct {
+	URL string // Identity API v3, e.g. https://keystone:5000/v3; empty disables the resolution
+	// ApplicationCredentialID and ApplicationCredentialSecret authenticate
+	// with an application credential allowed to read the projects
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+	CacheTTL                    time.Duration // How long a name is used before it is looked up again, defaults to 1h
+}
+
 type OpsLogConfig struct {
 	LogFilePath               string
 	TruncateLogOnStart        bool
 	SocketPath                string
+	JournaldUnit              string // Systemd unit whose journal is read instead of LogFilePath
+	JournaldCursorFile        string // Checkpoint of the last journal entry read, resumed after a restart
 	NatsURL                   string
 	NatsSubject               string
 	NatsMetricsSubject        string
+	NatsTenantSubjects        bool // Publish the entries to <NatsSubject>.<tenant> instead of NatsSubject
+	NatsSecurityEvents        bool // Publish denied and anonymous requests to NatsSecuritySubject
+	NatsSecuritySubject       string
 	UseNats                   bool
 	LogToStdout               bool
 	LogPrettyPrint            bool
@@ -61,11 +122,43 @@ type OpsLogConfig struct {
 	MaxLogFileSize            int64 // Maximum log file size in bytes before rotation
 	Prometheus                bool
 	PrometheusPort            int
-	PodName                   string
+	identity.Identity         // Instance, pod and node labeling the metrics and stamping the published metrics
 	IgnoreAnonymousRequests   bool
 	PrometheusIntervalSeconds int
+	IPInternalCIDRs           string             // Comma-separated CIDRs whose clients are labeled internal
+	IPCrossRegionCIDRs        string             // Comma-separated CIDRs whose clients are labeled cross-region
+	RemoteWrite               remotewrite.Config // Pushes the Prometheus metrics when URL is set
 	MetricsConfig             MetricsConfig
 	AuditSink                 AuditSinkConfig
+	LokiSink                  LokiSinkConfig
+	Backpressure              BackpressureConfig
+	BucketTagsKV              string // Bucket data KV of the radosgwusage producer, e.g. sync_bucket_data
+	BucketTags                string // Comma-separated bucket tags counted by, e.g. cost-center,environment
+
+	// ReplicationUsers and ReplicationCIDRs are the comma-separated sync
+	// users and peer zone endpoints of multisite replication, whose requests
+	// are counted by the replication metrics instead of the request metrics
+	ReplicationUsers string
+	ReplicationCIDRs string
+
+	// CostPrices are the comma-separated prices of the cost estimates, see
+	// NewCostModel
+	CostPrices string
+
+	// Keystone resolves the project names of the tenants
+	Keystone KeystoneConfig
+
+	// NatsRoutes are comma-separated <class>=<subject> routes of the
+	// entries, e.g. error=rgw.s3.errors,write=rgw.s3.writes,all=rgw.s3.ops,
+	// published to instead of NatsSubject, see ParseNatsRoutes
+	NatsRoutes string
+	natsRouter *natsRouter // Set by initNatsRoutes
+
+	// SlowRequests captures the requests above a latency threshold,
+	// published to NatsSlowRequestsSubject with NatsSlowRequests
+	SlowRequests            SlowRequestsConfig
+	NatsSlowRequests        bool
+	NatsSlowRequestsSubject string
 }
 
 // MetricsConfig defines which metrics to collect and at what granularity
@@ -73,6 +166,49 @@ type MetricsConfig struct {
 	// === SHORTCUT CONFIGS ===
 	TrackEverything bool `yaml:"track_everything"` // Enables all metrics at all levels
 	TrackBucketSLO  bool `yaml:"track_bucket_slo"` // Dedicated low-cardinality GET/LIST SLI metrics for Prometheus SLOs
+	TrackSecurity   bool `yaml:"track_security"`   // Denied requests by user, IP and bucket, and anonymous requests
+
+	// TrackBucketConcurrency estimates the requests in flight per bucket
+	// from the time and total_time of the entries, e.g. to size the RGW
+	// thread pools
+	TrackBucketConcurrency bool `yaml:"track_bucket_concurrency"`
+
+	// TrackAuthMethods counts the requests of every user by authentication
+	// type and AWS signature version, e.g. to find the clients still
+	// signing with version 2
+	TrackAuthMethods bool `yaml:"track_auth_methods"`
+
+	// TrackHealthChecks detects health-check-like traffic, small reads of
+	// the same operation on the same key repeated at least
+	// HealthCheckMinPerMinute times a minute, and reports the HealthCheckTop
+	// most frequent patterns, e.g. to work with the tenants on reducing
+	// aggressive HEAD bucket checks
+	TrackHealthChecks       bool `yaml:"track_health_checks"`
+	HealthCheckMinPerMinute int  `yaml:"health_check_min_per_minute"`
+	HealthCheckTop          int  `yaml:"health_check_top"`
+
+	// IPClasses replaces the client address of the ip labels by its network
+	// class; nil keeps the addresses. Built from the CIDRs of OpsLogConfig.
+	IPClasses *IPClassifier `yaml:"-"`
+
+	// Replication recognizes the requests of multisite replication, kept
+	// out of the tenant-facing metrics; nil counts them as any request.
+	// Built from the replication users and CIDRs of OpsLogConfig.
+	Replication *ReplicationMatcher `yaml:"-"`
+
+	// Cost estimates the cost of the requests by tenant and bucket; nil
+	// disables. Built from the CostPrices of OpsLogConfig.
+	Cost CostModel `yaml:"-"`
+
+	// Projects resolves the Keystone project names of the tenants, exported
+	// as radosgw_keystone_project_info and set on the published entries; nil
+	// disables. Built from the Keystone config of OpsLogConfig.
+	Projects *ProjectResolver `yaml:"-"`
+
+	// BucketTags counts the requests and bytes by bucket owner and the
+	// selected bucket tags; nil disables. Built from the BucketTags of
+	// OpsLogConfig.
+	BucketTags *BucketTagCache `yaml:"-"`
 
 	// === REQUEST METRICS ===
 	// Total requests
@@ -148,6 +284,9 @@ type MetricsConfig struct {
 	TrackLatencyPerTenant          bool `yaml:"track_latency_per_tenant"`            // Aggregated: tenant, method
 	TrackLatencyPerMethod          bool `yaml:"track_latency_per_method"`            // Aggregated: method
 	TrackLatencyPerBucketAndMethod bool `yaml:"track_latency_per_bucket_and_method"` // Aggregated: tenant, bucket, method
+
+	// === API CATEGORY METRICS ===
+	TrackAPICategories bool `yaml:"track_api_categories"` // NATS only: user and tenant, bucket by API category, with the summed latency
 }
 
 // ApplyShortcuts applies shortcut configurations
@@ -156,6 +295,10 @@ func (c *MetricsConfig) ApplyShortcuts() {
 		// Enable only detailed metrics - aggregations can be done in Prometheus queries
 		// This is the most efficient approach with lowest cardinality
 		c.TrackBucketSLO = true
+		c.TrackSecurity = true
+		c.TrackBucketConcurrency = true
+		c.TrackAuthMethods = true
+		c.TrackHealthChecks = true
 		c.TrackRequestsDetailed = true
 		c.TrackRequestsByMethodDetailed = true
 		c.TrackRequestsByOperationDetailed = true
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cobaltcore-dev/prysm/pkg/apicategory"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+)
+
+// CostEgress is the cost component of the bytes sent to the clients; the
+// requests are costed by their API category
+const CostEgress = "egress"
+
+// priceEgressGB is the key of the price of a GB sent in the price list
+const priceEgressGB = "egress_gb"
+
+// CostModel estimates what a request costs, e.g. by the prices of a public
+// cloud, so the tenants and buckets can be charged back
+type CostModel interface {
+	// Cost returns the cost of the request and of the bytes it sent, in
+	// the currency of the model. category is the API category of the
+	// request, see apicategory.Of.
+	Cost(entry *S3OperationLog, category string) (request, egress float64)
+}
+
+// PriceTable is the CostModel of a price per GB sent, a GB being 10^9
+// bytes, and a price per 1,000 requests of each API category
+type PriceTable struct {
+	EgressGB           float64
+	PerThousandByClass map[string]float64 // By API category, categories without price cost nothing
+}
+
+// NewCostModel parses the comma-separated prices, e.g.
+// "egress_gb=0.09,read=0.0004,list=0.005,write=0.005". The keys are
+// egress_gb and the API categories. It returns nil if prices is empty,
+// which estimates no cost.
+func NewCostModel(prices string) (CostModel, error) {
+	pairs, err := remotewrite.ParseLabels(prices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cost prices: %w", err)
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	table := &PriceTable{PerThousandByClass: map[string]float64{}}
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		price, err := strconv.ParseFloat(pairs[key], 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("invalid cost price %s=%q, expected a non-negative number", key, pairs[key])
+		}
+		switch key {
+		case priceEgressGB:
+			table.EgressGB = price
+		case apicategory.Read, apicategory.List, apicategory.Write, apicategory.Delete, apicategory.Other:
+			table.PerThousandByClass[key] = price
+		default:
+			return nil, fmt.Errorf("unknown cost price %q, expected %s or an API category %v", key, priceEgressGB, apicategory.All)
+		}
+	}
+	return table, nil
+}
+
+func (t *PriceTable) Cost(entry *S3OperationLog, category string) (request, egress float64) {
+	request = t.PerThousandByClass[category] / 1000
+	egress = t.EgressGB * float64(max(entry.BytesSent, 0)) / 1e9
+	return request, egress
+}
+
+func observeCost(model CostModel, logEntry *S3OperationLog, tenant, method string) {
+	category := apicategory.Of(logEntry.Operation, method)
+	request, egress := model.Cost(logEntry, category)
+	if request > 0 {
+		estimatedCost.WithLabelValues(tenant, logEntry.Bucket, category).Add(request)
+	}
+	if egress > 0 {
+		estimatedCost.WithLabelValues(tenant, logEntry.Bucket, CostEgress).Add(egress)
+	}
+}
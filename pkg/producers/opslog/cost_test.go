@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/apicategory"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCostModel(t *testing.T) {
+	model, err := NewCostModel(" ")
+	require.NoError(t, err)
+	assert.Nil(t, model, "no prices estimate no cost")
+
+	model, err = NewCostModel("egress_gb=0.09, read=0.0004,write=0.005")
+	require.NoError(t, err)
+	table := model.(*PriceTable)
+	assert.Equal(t, 0.09, table.EgressGB)
+	assert.Equal(t, map[string]float64{apicategory.Read: 0.0004, apicategory.Write: 0.005}, table.PerThousandByClass)
+
+	for _, prices := range []string{"read", "read=cheap", "read=-1", "storage_gb=0.02"} {
+		_, err := NewCostModel(prices)
+		assert.Error(t, err, prices)
+	}
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	model, err := NewCostModel("egress_gb=0.09,read=0.4")
+	require.NoError(t, err)
+
+	request, egress := model.Cost(&S3OperationLog{BytesSent: 2_000_000_000}, apicategory.Read)
+	assert.InDelta(t, 0.0004, request, 1e-12)
+	assert.InDelta(t, 0.18, egress, 1e-12)
+
+	request, egress = model.Cost(&S3OperationLog{BytesSent: -1}, apicategory.Delete)
+	assert.Zero(t, request, "categories without price cost nothing")
+	assert.Zero(t, egress)
+}
+
+func TestMetricsUpdate_Cost(t *testing.T) {
+	model, err := NewCostModel("egress_gb=1,write=5")
+	require.NoError(t, err)
+	config := &MetricsConfig{Cost: model}
+
+	m := NewMetrics()
+	m.Update(S3OperationLog{User: "alice$finance", Bucket: "invoices", Operation: "put_obj", URI: "PUT /invoices/a HTTP/1.1", HTTPStatus: "200"}, config)
+	m.Update(S3OperationLog{User: "alice$finance", Bucket: "invoices", Operation: "get_obj", URI: "GET /invoices/a HTTP/1.1", HTTPStatus: "200", BytesSent: 500_000_000}, config)
+
+	assert.InDelta(t, 0.005, testutil.ToFloat64(estimatedCost.WithLabelValues("finance", "invoices", apicategory.Write)), 1e-12)
+	assert.InDelta(t, 0.5, testutil.ToFloat64(estimatedCost.WithLabelValues("finance", "invoices", CostEgress)), 1e-12)
+	assert.Zero(t, testutil.ToFloat64(estimatedCost.WithLabelValues("finance", "invoices", apicategory.Read)))
+}
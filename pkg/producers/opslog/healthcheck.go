@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults of the health check detection
+const (
+	DefaultHealthCheckMinPerMinute = 60 // one request a second on the same key
+	DefaultHealthCheckTop          = 20
+)
+
+// A health check reads little: the responses of HEAD requests are empty,
+// those of health check objects and listings of a single key small
+const healthCheckMaxBytesSent = 4096
+
+// Bounds of the memory of the detector within a window. Patterns beyond
+// maxHealthCheckPatterns are not counted, clients beyond
+// maxHealthCheckClients not told apart.
+const (
+	maxHealthCheckPatterns = 100000
+	maxHealthCheckClients  = 1000
+)
+
+// healthChecks detects the health-check-like traffic between two Prometheus
+// updates
+var healthChecks = newHealthCheckDetector(time.Now())
+
+// healthCheckPattern is the same operation on the same key of a tenant
+type healthCheckPattern struct {
+	tenant    string
+	bucket    string
+	object    string // empty for requests on the bucket, e.g. HEAD bucket
+	operation string
+}
+
+// healthCheckTraffic is the traffic of a pattern in a window
+type healthCheckTraffic struct {
+	healthCheckPattern
+	requests  int64
+	perSecond float64
+	clients   map[string]struct{}
+}
+
+// healthCheckDetector counts the small reads of every pattern in a window.
+// A pattern repeated at least minPerMinute times a minute over the window is
+// health-check-like: monitoring probes and load balancers ask the same
+// question over and over, real reads spread over many keys or read more.
+type healthCheckDetector struct {
+	mu       sync.Mutex
+	start    time.Time
+	patterns map[healthCheckPattern]*healthCheckTraffic
+	dropped  int64 // requests of patterns beyond maxHealthCheckPatterns
+}
+
+func newHealthCheckDetector(start time.Time) *healthCheckDetector {
+	return &healthCheckDetector{start: start, patterns: make(map[healthCheckPattern]*healthCheckTraffic)}
+}
+
+// isHealthCheckCandidate reports whether the entry may be a health check: a
+// read answered without a server error and with a small response
+func isHealthCheckCandidate(logEntry *S3OperationLog, method string) bool {
+	if method != "GET" && method != "HEAD" && (method != "UNKNOWN" || !isReadOperation(logEntry.Operation)) {
+		return false
+	}
+	status, err := strconv.Atoi(logEntry.HTTPStatus)
+	if err != nil || status >= 500 {
+		return false
+	}
+	return logEntry.Bucket != "" && logEntry.BytesSent <= healthCheckMaxBytesSent
+}
+
+// observe counts the entry to its pattern if it may be a health check
+func (d *healthCheckDetector) observe(logEntry *S3OperationLog, tenant, method string) {
+	if !isHealthCheckCandidate(logEntry, method) {
+		return
+	}
+	pattern := healthCheckPattern{tenant: tenant, bucket: logEntry.Bucket, object: logEntry.Object, operation: logEntry.Operation}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	traffic := d.patterns[pattern]
+	if traffic == nil {
+		if len(d.patterns) >= maxHealthCheckPatterns {
+			d.dropped++
+			return
+		}
+		traffic = &healthCheckTraffic{healthCheckPattern: pattern, clients: make(map[string]struct{})}
+		d.patterns[pattern] = traffic
+	}
+	traffic.requests++
+	if len(traffic.clients) < maxHealthCheckClients {
+		client := logEntry.RemoteAddr
+		if ip, ok := parseClientAddr(client); ok {
+			client = ip.String()
+		}
+		traffic.clients[client] = struct{}{}
+	}
+}
+
+// flush returns the health-check-like patterns of the window ending at end,
+// the most frequent first, with the requests of the patterns not counted,
+// and starts the next window
+func (d *healthCheckDetector) flush(end time.Time, minPerMinute int) (detected []healthCheckTraffic, dropped int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	window := end.Sub(d.start).Seconds()
+	if window > 0 {
+		minRequests := float64(minPerMinute) * window / 60
+		for _, traffic := range d.patterns {
+			if float64(traffic.requests) >= minRequests {
+				traffic.perSecond = float64(traffic.requests) / window
+				detected = append(detected, *traffic)
+			}
+		}
+	}
+	slices.SortFunc(detected, func(a, b healthCheckTraffic) int {
+		return cmp.Or(cmp.Compare(b.requests, a.requests), cmp.Compare(a.tenant, b.tenant),
+			cmp.Compare(a.bucket, b.bucket), cmp.Compare(a.object, b.object), cmp.Compare(a.operation, b.operation))
+	})
+	dropped = d.dropped
+
+	d.start = end
+	d.patterns = make(map[healthCheckPattern]*healthCheckTraffic)
+	d.dropped = 0
+	return detected, dropped
+}
+
+// publishHealthChecks sets the gauges of the health-check-like traffic of
+// the window ending at end: the top patterns, and the requests and patterns
+// of all of them per tenant. Patterns not detected in the window are
+// dropped. Unset thresholds take the defaults.
+func publishHealthChecks(end time.Time, cfg *MetricsConfig) {
+	minPerMinute, top := cfg.HealthCheckMinPerMinute, cfg.HealthCheckTop
+	if minPerMinute <= 0 {
+		minPerMinute = DefaultHealthCheckMinPerMinute
+	}
+	if top <= 0 {
+		top = DefaultHealthCheckTop
+	}
+	detected, dropped := healthChecks.flush(end, minPerMinute)
+
+	healthCheckPatternRequests.Reset()
+	healthCheckPatternClients.Reset()
+	healthCheckTenantRequests.Reset()
+	healthCheckTenantPatterns.Reset()
+	for i, traffic := range detected {
+		if i < top {
+			healthCheckPatternRequests.WithLabelValues(traffic.tenant, traffic.bucket, traffic.object, traffic.operation).Set(traffic.perSecond)
+			healthCheckPatternClients.WithLabelValues(traffic.tenant, traffic.bucket, traffic.object, traffic.operation).Set(float64(len(traffic.clients)))
+		}
+		healthCheckTenantRequests.WithLabelValues(traffic.tenant).Add(traffic.perSecond)
+		healthCheckTenantPatterns.WithLabelValues(traffic.tenant).Inc()
+	}
+	healthCheckRequestsNotCounted.Add(float64(dropped))
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHealthCheckCandidate(t *testing.T) {
+	entry := func(operation, status string, bytesSent int) *S3OperationLog {
+		return &S3OperationLog{Bucket: "web", Operation: operation, HTTPStatus: status, BytesSent: bytesSent}
+	}
+	assert.True(t, isHealthCheckCandidate(entry("stat_bucket", "200", 0), "HEAD"))
+	assert.True(t, isHealthCheckCandidate(entry("get_obj", "404", 250), "GET"), "a missing health check object still probes")
+	assert.True(t, isHealthCheckCandidate(entry("list_bucket", "200", 512), "UNKNOWN"))
+	assert.False(t, isHealthCheckCandidate(entry("get_obj", "200", 1<<20), "GET"), "reads of content")
+	assert.False(t, isHealthCheckCandidate(entry("put_obj", "200", 0), "PUT"))
+	assert.False(t, isHealthCheckCandidate(entry("stat_bucket", "503", 0), "HEAD"))
+	assert.False(t, isHealthCheckCandidate(&S3OperationLog{Operation: "list_buckets", HTTPStatus: "200"}, "GET"), "no key")
+}
+
+func TestHealthCheckDetector(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	detector := newHealthCheckDetector(start)
+
+	headBucket := S3OperationLog{Bucket: "web", Operation: "stat_bucket", HTTPStatus: "200"}
+	getObject := S3OperationLog{Bucket: "web", Object: "health.txt", Operation: "get_obj", HTTPStatus: "200", BytesSent: 2}
+	// 300 HEAD bucket of three load balancers and 120 reads of a health
+	// check object in two minutes, 60 reads of another object
+	for i := range 300 {
+		headBucket.RemoteAddr = []string{"10.0.0.1:41000", "10.0.0.2", "10.0.0.3:41000"}[i%3]
+		detector.observe(&headBucket, "shop", "HEAD")
+	}
+	for range 120 {
+		detector.observe(&getObject, "shop", "GET")
+	}
+	getObject.Object = "index.html"
+	for range 60 {
+		detector.observe(&getObject, "blog", "GET")
+	}
+
+	detected, dropped := detector.flush(start.Add(2*time.Minute), 60)
+	assert.Zero(t, dropped)
+	require.Len(t, detected, 2, "the pattern of 30 requests a minute is not frequent enough")
+	assert.Equal(t, healthCheckPattern{tenant: "shop", bucket: "web", operation: "stat_bucket"}, detected[0].healthCheckPattern)
+	assert.InDelta(t, 2.5, detected[0].perSecond, 1e-9)
+	assert.Len(t, detected[0].clients, 3)
+	assert.Equal(t, "health.txt", detected[1].object)
+	assert.InDelta(t, 1, detected[1].perSecond, 1e-9)
+
+	// The next window starts empty
+	detected, _ = detector.flush(start.Add(3*time.Minute), 60)
+	assert.Empty(t, detected)
+}
+
+func TestPublishHealthChecks(t *testing.T) {
+	healthChecks = newHealthCheckDetector(time.Now().Add(-time.Minute))
+	entry := S3OperationLog{Bucket: "web", Operation: "stat_bucket", HTTPStatus: "200", RemoteAddr: "10.0.0.1"}
+	for range 120 {
+		healthChecks.observe(&entry, "shop", "HEAD")
+	}
+	entry.Bucket = "assets"
+	for range 90 {
+		healthChecks.observe(&entry, "shop", "HEAD")
+	}
+
+	publishHealthChecks(time.Now(), &MetricsConfig{HealthCheckTop: 1})
+	assert.Equal(t, 1, testutil.CollectAndCount(healthCheckPatternRequests), "only the top pattern")
+	assert.InDelta(t, 2, testutil.ToFloat64(healthCheckPatternRequests.WithLabelValues("shop", "web", "", "stat_bucket")), 0.1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(healthCheckPatternClients.WithLabelValues("shop", "web", "", "stat_bucket")))
+	assert.InDelta(t, 3.5, testutil.ToFloat64(healthCheckTenantRequests.WithLabelValues("shop")), 0.1)
+	assert.Equal(t, 2.0, testutil.ToFloat64(healthCheckTenantPatterns.WithLabelValues("shop")))
+
+	publishHealthChecks(time.Now(), &MetricsConfig{})
+	assert.Zero(t, testutil.CollectAndCount(healthCheckTenantRequests), "patterns of the last window only")
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Network classes of client addresses
+const (
+	IPClassInternal    = "internal"     // In the internal CIDRs
+	IPClassCrossRegion = "cross-region" // In the cross-region CIDRs
+	IPClassPublic      = "public"       // Any other address
+	IPClassUnknown     = "unknown"      // Not an address, e.g. an empty remote_addr
+)
+
+// IPClassifier maps client addresses to their network class, so the ip labels
+// of the metrics have four values instead of one per client
+type IPClassifier struct {
+	internal    []netip.Prefix
+	crossRegion []netip.Prefix
+}
+
+// NewIPClassifier parses the comma-separated CIDRs of the internal and
+// cross-region classes. It returns nil if both are empty, which keeps the
+// addresses.
+func NewIPClassifier(internal, crossRegion string) (*IPClassifier, error) {
+	internalPrefixes, err := parseCIDRs(internal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid internal CIDRs: %w", err)
+	}
+	crossRegionPrefixes, err := parseCIDRs(crossRegion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cross-region CIDRs: %w", err)
+	}
+	if len(internalPrefixes) == 0 && len(crossRegionPrefixes) == 0 {
+		return nil, nil
+	}
+	return &IPClassifier{internal: internalPrefixes, crossRegion: crossRegionPrefixes}, nil
+}
+
+func parseCIDRs(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, cidr := range strings.Split(list, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Classify returns the class of addr, an address with or without port. A nil
+// classifier returns addr unchanged. Internal CIDRs are matched first.
+func (c *IPClassifier) Classify(addr string) string {
+	if c == nil {
+		return addr
+	}
+
+	ip, ok := parseClientAddr(addr)
+	if !ok {
+		return IPClassUnknown
+	}
+
+	switch {
+	case containsAddr(c.internal, ip):
+		return IPClassInternal
+	case containsAddr(c.crossRegion, ip):
+		return IPClassCrossRegion
+	default:
+		return IPClassPublic
+	}
+}
+
+// parseClientAddr parses the remote_addr of an entry, an address with or
+// without port
+func parseClientAddr(addr string) (netip.Addr, bool) {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(addr)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		ip = addrPort.Addr()
+	}
+	return ip.Unmap(), true
+}
+
+func containsAddr(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPClassifier(t *testing.T) {
+	classes, err := NewIPClassifier("10.0.0.0/8, 192.168.0.0/16,fd00::/8", "100.64.0.0/10")
+	require.NoError(t, err)
+
+	testCases := map[string]string{
+		"10.1.2.3":           IPClassInternal,
+		"192.168.7.1:41234":  IPClassInternal,
+		"::ffff:10.0.0.1":    IPClassInternal,
+		"fd12::1":            IPClassInternal,
+		"100.64.3.4":         IPClassCrossRegion,
+		"203.0.113.7":        IPClassPublic,
+		"[2001:db8::1]:8080": IPClassPublic,
+		"":                   IPClassUnknown,
+		"not-an-ip":          IPClassUnknown,
+	}
+	for addr, expected := range testCases {
+		assert.Equal(t, expected, classes.Classify(addr), addr)
+	}
+}
+
+func TestNewIPClassifier(t *testing.T) {
+	classes, err := NewIPClassifier("", " ")
+	require.NoError(t, err)
+	assert.Nil(t, classes, "no CIDRs keep the addresses")
+	assert.Equal(t, "203.0.113.7", classes.Classify("203.0.113.7"))
+
+	_, err = NewIPClassifier("10.0.0.0/33", "")
+	assert.ErrorContains(t, err, "internal")
+	_, err = NewIPClassifier("", "100.64.0.1")
+	assert.ErrorContains(t, err, "cross-region")
+}
+
+func TestMetricsUpdate_IPClasses(t *testing.T) {
+	classes, err := NewIPClassifier("10.0.0.0/8", "")
+	require.NoError(t, err)
+	config := &MetricsConfig{TrackRequestsByIPPerTenant: true, TrackErrorsByIP: true, IPClasses: classes}
+
+	m := NewMetrics()
+	m.Update(S3OperationLog{User: "alice$proj", RemoteAddr: "10.0.0.1", HTTPStatus: "200"}, config)
+	m.Update(S3OperationLog{User: "alice$proj", RemoteAddr: "10.0.0.2", HTTPStatus: "200"}, config)
+	m.Update(S3OperationLog{User: "alice$proj", RemoteAddr: "203.0.113.7", HTTPStatus: "500"}, config)
+
+	v, ok := m.RequestsPerIPPerTenant.Load("proj|" + IPClassInternal)
+	require.True(t, ok, "requests of internal clients share their class")
+	assert.Equal(t, uint64(2), v.(*atomic.Uint64).Load())
+
+	_, ok = m.RequestsPerIPPerTenant.Load("proj|10.0.0.1")
+	assert.False(t, ok, "the address is not a label")
+
+	_, ok = m.ErrorsPerIP.Load(IPClassPublic + "|proj|500")
+	assert.True(t, ok)
+}
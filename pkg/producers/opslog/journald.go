@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/sapcc/go-bits/audittools"
+)
+
+const (
+	// journalCheckpointInterval is how often the cursor of the last
+	// processed journal entry is saved
+	journalCheckpointInterval = 5 * time.Second
+	// journalRestartDelay is the wait before journalctl is started again
+	// after it exited
+	journalRestartDelay = 5 * time.Second
+	// maxJournalRecordSize bounds a journal record printed by journalctl
+	maxJournalRecordSize = 16 * 1024 * 1024
+)
+
+// journalRecord is a journal entry printed by journalctl --output=json
+type journalRecord struct {
+	Cursor string `json:"__CURSOR"`
+	// MESSAGE is a string, or an array of bytes if it is not valid UTF-8
+	Message json.RawMessage `json:"MESSAGE"`
+}
+
+// message returns the MESSAGE field of the record, nil if it has none
+func (r journalRecord) message() []byte {
+	switch {
+	case len(r.Message) == 0:
+		return nil
+	case r.Message[0] == '"':
+		var message string
+		if err := json.Unmarshal(r.Message, &message); err != nil {
+			return nil
+		}
+		return []byte(message)
+	case r.Message[0] == '[':
+		var message []int
+		if err := json.Unmarshal(r.Message, &message); err != nil {
+			return nil
+		}
+		raw := make([]byte, len(message))
+		for i, b := range message {
+			raw[i] = byte(b)
+		}
+		return raw
+	}
+	return nil
+}
+
+// journalCursor is the cursor of the last processed journal entry and its
+// checkpoint file, so entries are neither lost nor processed twice when the
+// reader restarts
+type journalCursor struct {
+	path string
+
+	mu     sync.Mutex
+	cursor string
+	saved  string
+}
+
+// loadJournalCursor reads the checkpoint at path; a missing file starts
+// without a cursor
+func loadJournalCursor(path string) (*journalCursor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading journal cursor: %w", err)
+	}
+	cursor := strings.TrimSpace(string(data))
+	return &journalCursor{path: path, cursor: cursor, saved: cursor}, nil
+}
+
+func (c *journalCursor) get() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cursor
+}
+
+func (c *journalCursor) set(cursor string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cursor = cursor
+}
+
+// save writes the cursor to the checkpoint file if it moved since the last
+// save. The file is replaced atomically, a crash leaves the old checkpoint.
+func (c *journalCursor) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cursor == c.saved {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("error creating journal cursor directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(c.cursor+"\n"), 0o644); err != nil {
+		return fmt.Errorf("error writing journal cursor: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("error replacing journal cursor: %w", err)
+	}
+	c.saved = c.cursor
+	return nil
+}
+
+// startJournaldReadLoop follows the journal of cfg.JournaldUnit with
+// journalctl and processes the ops log entries of its messages like the
+// entries of the log file, starting after the checkpointed cursor
+func startJournaldReadLoop(cfg OpsLogConfig, nc *nats.Conn, metrics *Metrics, auditor audittools.Auditor, loki *lokiPusher, security *securityTracker, slow *slowRequestCapture, events *eventQueue) error {
+	cursor, err := loadJournalCursor(cfg.JournaldCursorFile)
+	if err != nil {
+		return err
+	}
+
+	// A span per entry would outnumber the entries, the journal is read
+	// without batch spans
+	var timings pipelineTimings
+	handle := newEntryHandler(cfg, nc, metrics, auditor, loki, security, slow, events, &timings)
+
+	// The cursor outlives a restart of the reader after a panic, so the
+	// entries are not processed twice
+	go telemetry.RunWorker(context.Background(), "ops-log.journald", func(ctx context.Context) {
+		for {
+			if err := followJournal(ctx, cfg.JournaldUnit, cursor, handle); err != nil {
+				log.Error().Err(err).Str("unit", cfg.JournaldUnit).Msg("Error reading journald")
+			}
+			if err := cursor.save(); err != nil {
+				log.Error().Err(err).Str("file", cfg.JournaldCursorFile).Msg("Error saving journal cursor")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(journalRestartDelay):
+			}
+		}
+	})
+
+	go telemetry.RunWorker(context.Background(), "ops-log.journald-cursor", func(context.Context) {
+		ticker := time.NewTicker(journalCheckpointInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := cursor.save(); err != nil {
+				log.Error().Err(err).Str("file", cfg.JournaldCursorFile).Msg("Error saving journal cursor")
+			}
+		}
+	})
+
+	log.Info().Str("unit", cfg.JournaldUnit).Bool("resumed", cursor.get() != "").Msg("Started reading journald")
+	return nil
+}
+
+// followJournal runs journalctl until it exits. Without a cursor only new
+// entries are read, like from a log file truncated on start.
+func followJournal(ctx context.Context, unit string, cursor *journalCursor, handle func(raw json.RawMessage, logEntry *S3OperationLog)) error {
+	args := []string{"--unit=" + unit, "--output=json", "--all", "--follow", "--no-pager"}
+	if after := cursor.get(); after != "" {
+		args = append(args, "--after-cursor="+after)
+	} else {
+		args = append(args, "--lines=0")
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error creating journalctl pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting journalctl: %w", err)
+	}
+
+	readErr := readJournal(stdout, cursor, handle)
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+	}
+	if err := cmd.Wait(); err != nil && readErr == nil {
+		return fmt.Errorf("journalctl exited: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return readErr
+}
+
+// readJournal processes the ops log entries in the messages of the journal
+// records in r and moves the cursor past every processed record. Text before
+// the JSON of a message, e.g. a prefix of the container runtime, is skipped.
+func readJournal(r io.Reader, cursor *journalCursor, handle func(raw json.RawMessage, logEntry *S3OperationLog)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJournalRecordSize)
+	for scanner.Scan() {
+		var record journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			opsLogParseErrLogger.warn(err, scanner.Bytes())
+			continue
+		}
+
+		message := record.message()
+		if start := bytes.IndexByte(message, '{'); start >= 0 {
+			decodeOpsLogEntries(bytes.NewReader(message[start:]), handle)
+		}
+		if record.Cursor != "" {
+			cursor.set(record.Cursor)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading journalctl output: %w", err)
+	}
+	return nil
+}
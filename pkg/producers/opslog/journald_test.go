@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	json "github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadJournal(t *testing.T) {
+	// The second message is not valid UTF-8 and printed as an array of bytes
+	binary, err := json.Marshal([]int{0xff, '{', '"', 'b', 'u', 'c', 'k', 'e', 't', '"', ':', '"', 'c', '"', '}'})
+	require.NoError(t, err)
+	out := strings.Join([]string{
+		`{"__CURSOR":"s=1;i=1","MESSAGE":"{\"bucket\":\"a\",\"user\":\"u$t\"}{\"bucket\":\"b\"}"}`,
+		`{"__CURSOR":"s=1;i=2","MESSAGE":` + string(binary) + `}`,
+		`{"__CURSOR":"s=1;i=3","MESSAGE":"rgw: starting"}`,
+		`not a journal record`,
+		`{"__CURSOR":"s=1;i=4","MESSAGE":"2025-03-01T12:00:00 {\"bucket\":\"d\"}"}`,
+	}, "\n")
+
+	cursor := &journalCursor{path: filepath.Join(t.TempDir(), "cursor")}
+	var buckets []string
+	err = readJournal(strings.NewReader(out), cursor, func(_ json.RawMessage, logEntry *S3OperationLog) {
+		buckets = append(buckets, logEntry.Bucket)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, buckets)
+	assert.Equal(t, "s=1;i=4", cursor.get())
+}
+
+func TestJournalCursor_Checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "journald.cursor")
+
+	cursor, err := loadJournalCursor(path)
+	require.NoError(t, err)
+	assert.Empty(t, cursor.get())
+
+	cursor.set("s=1;i=42")
+	require.NoError(t, cursor.save())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "s=1;i=42\n", string(data))
+
+	// A restart resumes after the checkpointed entry
+	resumed, err := loadJournalCursor(path)
+	require.NoError(t, err)
+	assert.Equal(t, "s=1;i=42", resumed.get())
+
+	// An unchanged cursor is not written again
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, resumed.save())
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultKeystoneCacheTTL = time.Hour
+	keystoneRetryInterval   = time.Minute // Until a failed lookup is tried again
+	keystoneQueueSize       = 1000
+)
+
+// errKeystoneUnauthorized is a rejected token, renewed before the lookup is
+// tried again
+var errKeystoneUnauthorized = errors.New("keystone rejected the token")
+
+// keystoneProjectID matches the IDs of the Keystone projects, the tenants of
+// RGW with rgw_keystone_implicit_tenants. Other tenants are never looked up.
+var keystoneProjectID = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// keystoneProject is a cached project; found is false for projects Keystone
+// does not know
+type keystoneProject struct {
+	project KeystoneProject
+	found   bool
+	expires time.Time
+}
+
+// ProjectResolver maps the Keystone project IDs of the tenants to project
+// names. Lookups run in the background, so the entries of a project are
+// enriched once its name is cached; the entries carrying a keystone_scope
+// fill the cache without a lookup.
+type ProjectResolver struct {
+	url          string
+	credentialID string
+	secret       string
+	ttl          time.Duration
+	client       *http.Client
+	now          func() time.Time
+
+	mu       sync.Mutex
+	projects map[string]keystoneProject
+	pending  map[string]bool
+	queue    chan string
+
+	token        string // Used by the lookup worker only
+	tokenExpires time.Time
+}
+
+// NewProjectResolver returns a resolver for cfg, nil if the resolution is
+// disabled. Start runs its lookups.
+func NewProjectResolver(cfg KeystoneConfig) (*ProjectResolver, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	if cfg.ApplicationCredentialID == "" || cfg.ApplicationCredentialSecret == "" {
+		return nil, errors.New("keystone project resolution requires an application credential ID and secret")
+	}
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultKeystoneCacheTTL
+	}
+	return &ProjectResolver{
+		url:          strings.TrimSuffix(cfg.URL, "/"),
+		credentialID: cfg.ApplicationCredentialID,
+		secret:       cfg.ApplicationCredentialSecret,
+		ttl:          ttl,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+		projects:     map[string]keystoneProject{},
+		pending:      map[string]bool{},
+		queue:        make(chan string, keystoneQueueSize),
+	}, nil
+}
+
+// Start looks up the queued projects until ctx is done
+func (r *ProjectResolver) Start(ctx context.Context) {
+	go telemetry.RunWorker(ctx, "ops-log.keystone", func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-r.queue:
+				r.resolve(ctx, id)
+			}
+		}
+	})
+	log.Info().Str("url", r.url).Dur("cache_ttl", r.ttl).Msg("Keystone project resolution initialized")
+}
+
+// Project returns the project of a tenant if its name is known. Unknown and
+// expired projects are queued for a lookup, an expired name is still
+// returned until the lookup replaced it.
+func (r *ProjectResolver) Project(tenant string) (KeystoneProject, bool) {
+	if r == nil || !keystoneProjectID.MatchString(tenant) {
+		return KeystoneProject{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cached, ok := r.projects[tenant]
+	if (!ok || !r.now().Before(cached.expires)) && !r.pending[tenant] {
+		select {
+		case r.queue <- tenant:
+			r.pending[tenant] = true
+		default: // Queued again by the next entry of the project
+		}
+	}
+	return cached.project, cached.found
+}
+
+// Learn caches the project of the keystone_scope of an entry
+func (r *ProjectResolver) Learn(scope *KeystoneScope) {
+	if r == nil || scope == nil || scope.Project.ID == "" || scope.Project.Name == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.projects[scope.Project.ID]; ok && cached.found && cached.project == scope.Project && r.now().Before(cached.expires) {
+		return
+	}
+	r.store(scope.Project.ID, keystoneProject{project: scope.Project, found: true, expires: r.now().Add(r.ttl)})
+}
+
+// Enrich sets the project of an entry RGW logged without a keystone_scope,
+// e.g. of the requests signed with EC2 credentials
+func (r *ProjectResolver) Enrich(entry *S3OperationLog) {
+	if r == nil {
+		return
+	}
+	if entry.KeystoneScope != nil {
+		r.Learn(entry.KeystoneScope)
+		return
+	}
+	_, tenant := extractUserAndTenant(entry.User)
+	if project, ok := r.Project(tenant); ok {
+		entry.Project = &project
+	}
+}
+
+// resolve looks up a project and caches the result. A failed lookup keeps
+// the name cached before and is tried again after keystoneRetryInterval.
+func (r *ProjectResolver) resolve(ctx context.Context, id string) {
+	project, found, err := r.lookup(ctx, id)
+	if errors.Is(err, errKeystoneUnauthorized) {
+		r.token = ""
+		project, found, err = r.lookup(ctx, id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+	switch {
+	case err != nil:
+		keystoneLookups.WithLabelValues("error").Inc()
+		log.Warn().Err(err).Str("project_id", id).Msg("Failed to look up Keystone project")
+		cached := r.projects[id]
+		cached.expires = r.now().Add(keystoneRetryInterval)
+		r.projects[id] = cached
+	case !found:
+		keystoneLookups.WithLabelValues("not_found").Inc()
+		r.store(id, keystoneProject{expires: r.now().Add(r.ttl)})
+	default:
+		keystoneLookups.WithLabelValues("found").Inc()
+		r.store(id, keystoneProject{project: project, found: true, expires: r.now().Add(r.ttl)})
+	}
+}
+
+// store caches a project and updates its info metric, r.mu held
+func (r *ProjectResolver) store(id string, cached keystoneProject) {
+	if previous, ok := r.projects[id]; ok && previous.found {
+		keystoneProjectInfo.DeleteLabelValues(id, previous.project.Name, previous.project.Domain.ID)
+	}
+	r.projects[id] = cached
+	if cached.found {
+		keystoneProjectInfo.WithLabelValues(id, cached.project.Name, cached.project.Domain.ID).Set(1)
+	}
+}
+
+// lookup reads a project from the identity API, found is false if Keystone
+// does not know it
+func (r *ProjectResolver) lookup(ctx context.Context, id string) (KeystoneProject, bool, error) {
+	if err := r.authenticate(ctx); err != nil {
+		return KeystoneProject{}, false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+"/projects/"+id, nil)
+	if err != nil {
+		return KeystoneProject{}, false, err
+	}
+	req.Header.Set("X-Auth-Token", r.token)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return KeystoneProject{}, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return KeystoneProject{}, false, nil
+	case http.StatusUnauthorized:
+		return KeystoneProject{}, false, errKeystoneUnauthorized
+	default:
+		return KeystoneProject{}, false, unexpectedKeystoneStatus(resp)
+	}
+
+	var body struct {
+		Project struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			DomainID string `json:"domain_id"`
+		} `json:"project"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return KeystoneProject{}, false, fmt.Errorf("invalid project response: %w", err)
+	}
+	return KeystoneProject{ID: id, Name: body.Project.Name, Domain: KeystoneDomain{ID: body.Project.DomainID}}, true, nil
+}
+
+// authenticate issues a token with the application credential unless the
+// current one is valid for another minute
+func (r *ProjectResolver) authenticate(ctx context.Context) error {
+	if r.token != "" && r.now().Add(time.Minute).Before(r.tokenExpires) {
+		return nil
+	}
+
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Methods               []string `json:"methods"`
+				ApplicationCredential struct {
+					ID     string `json:"id"`
+					Secret string `json:"secret"`
+				} `json:"application_credential"`
+			} `json:"identity"`
+		} `json:"auth"`
+	}
+	body.Auth.Identity.Methods = []string{"application_credential"}
+	body.Auth.Identity.ApplicationCredential.ID = r.credentialID
+	body.Auth.Identity.ApplicationCredential.Secret = r.secret
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+"/auth/tokens", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("authenticating with keystone: %w", unexpectedKeystoneStatus(resp))
+	}
+
+	var token struct {
+		Token struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		} `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("invalid token response: %w", err)
+	}
+	r.token = resp.Header.Get("X-Subject-Token")
+	r.tokenExpires = token.Token.ExpiresAt
+	if r.token == "" {
+		return errors.New("keystone returned no X-Subject-Token")
+	}
+	return nil
+}
+
+func unexpectedKeystoneStatus(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+}
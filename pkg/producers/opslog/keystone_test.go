@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	projectA = "0123456789abcdef0123456789abcdef"
+	projectB = "fedcba9876543210fedcba9876543210"
+)
+
+// fakeKeystone serves tokens for the credential app-id/app-secret and the
+// project projectA
+func fakeKeystone(t *testing.T, tokens *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v3/auth/tokens":
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"auth":{"identity":{"methods":["application_credential"],"application_credential":{"id":"app-id","secret":"app-secret"}}}}`, string(body))
+			n := tokens.Add(1)
+			w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", n))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":{"expires_at":%q}}`, time.Now().Add(24*time.Hour).Format(time.RFC3339))
+		case r.Header.Get("X-Auth-Token") != fmt.Sprintf("token-%d", tokens.Load()):
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v3/projects/"+projectA:
+			fmt.Fprintf(w, `{"project":{"id":%q,"name":"billing","domain_id":"default"}}`, projectA)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestResolver(t *testing.T, url string) *ProjectResolver {
+	r, err := NewProjectResolver(KeystoneConfig{URL: url + "/v3/", ApplicationCredentialID: "app-id", ApplicationCredentialSecret: "app-secret"})
+	require.NoError(t, err)
+	return r
+}
+
+// drain runs the queued lookups
+func (r *ProjectResolver) drain() {
+	for {
+		select {
+		case id := <-r.queue:
+			r.resolve(context.Background(), id)
+		default:
+			return
+		}
+	}
+}
+
+func TestNewProjectResolver(t *testing.T) {
+	r, err := NewProjectResolver(KeystoneConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, r, "disabled without URL")
+
+	_, err = NewProjectResolver(KeystoneConfig{URL: "https://keystone/v3", ApplicationCredentialID: "app-id"})
+	assert.Error(t, err, "the secret is required")
+}
+
+func TestProjectResolver_Resolve(t *testing.T) {
+	var tokens atomic.Int32
+	r := newTestResolver(t, fakeKeystone(t, &tokens).URL)
+
+	_, ok := r.Project(projectA)
+	assert.False(t, ok, "resolved in the background")
+	_, ok = r.Project(projectA)
+	assert.False(t, ok)
+	assert.Len(t, r.queue, 1, "queued once")
+	r.drain()
+
+	project, ok := r.Project(projectA)
+	require.True(t, ok)
+	assert.Equal(t, KeystoneProject{ID: projectA, Name: "billing", Domain: KeystoneDomain{ID: "default"}}, project)
+	assert.Equal(t, 1.0, testutil.ToFloat64(keystoneProjectInfo.WithLabelValues(projectA, "billing", "default")))
+
+	// Unknown projects are cached too
+	notFound := testutil.ToFloat64(keystoneLookups.WithLabelValues("not_found"))
+	r.Project(projectB)
+	r.drain()
+	_, ok = r.Project(projectB)
+	assert.False(t, ok)
+	assert.Empty(t, r.queue)
+	assert.Equal(t, notFound+1, testutil.ToFloat64(keystoneLookups.WithLabelValues("not_found")))
+
+	// Other tenants are never looked up
+	_, ok = r.Project("none")
+	assert.False(t, ok)
+	assert.Empty(t, r.queue)
+	assert.Equal(t, int32(1), tokens.Load())
+
+	// An expired name is used until it is looked up again, with a new token
+	// once the old one is rejected
+	now := time.Now().Add(2 * time.Hour)
+	r.now = func() time.Time { return now }
+	tokens.Add(1)
+	_, ok = r.Project(projectA)
+	assert.True(t, ok)
+	r.drain()
+	assert.Equal(t, int32(3), tokens.Load())
+	assert.True(t, r.projects[projectA].expires.After(now))
+}
+
+func TestProjectResolver_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	r := newTestResolver(t, server.URL)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	failed := testutil.ToFloat64(keystoneLookups.WithLabelValues("error"))
+	r.Project(projectA)
+	r.drain()
+	assert.Equal(t, failed+1, testutil.ToFloat64(keystoneLookups.WithLabelValues("error")))
+
+	// Tried again after keystoneRetryInterval only
+	r.Project(projectA)
+	assert.Empty(t, r.queue)
+	now = now.Add(keystoneRetryInterval)
+	r.Project(projectA)
+	assert.Len(t, r.queue, 1)
+}
+
+func TestProjectResolver_Enrich(t *testing.T) {
+	var tokens atomic.Int32
+	r := newTestResolver(t, fakeKeystone(t, &tokens).URL)
+
+	// The scope of an entry fills the cache
+	scoped := S3OperationLog{User: projectB + "$" + projectB, KeystoneScope: &KeystoneScope{
+		Project: KeystoneProject{ID: projectB, Name: "storage", Domain: KeystoneDomain{ID: "default", Name: "Default"}},
+	}}
+	r.Enrich(&scoped)
+	assert.Nil(t, scoped.Project, "the scope names the project already")
+
+	entry := S3OperationLog{User: projectB + "$" + projectB}
+	r.Enrich(&entry)
+	require.NotNil(t, entry.Project)
+	assert.Equal(t, "storage", entry.Project.Name)
+	assert.Empty(t, r.queue)
+	assert.Zero(t, tokens.Load(), "no lookup")
+
+	var disabled *ProjectResolver
+	entry = S3OperationLog{User: projectA + "$" + projectA}
+	disabled.Enrich(&entry)
+	assert.Nil(t, entry.Project)
+}
@@ -0,0 +1,306 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+	"github.com/cobaltcore-dev/prysm/pkg/selflimit"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+// Labels the raw entries can be mapped to. Every distinct combination is a
+// Loki stream, so the labels are kept to low-cardinality fields; user and
+// object stay searchable in the line.
+var lokiLabelFields = map[string]func(entry *S3OperationLog) string{
+	"tenant": func(entry *S3OperationLog) string {
+		_, tenant := extractUserAndTenant(entry.User)
+		return tenant
+	},
+	"bucket": func(entry *S3OperationLog) string {
+		if entry.Bucket == "" {
+			return "none"
+		}
+		return entry.Bucket
+	},
+	"status_class": func(entry *S3OperationLog) string {
+		return statusClass(entry.HTTPStatus)
+	},
+	"operation": func(entry *S3OperationLog) string {
+		return entry.Operation
+	},
+	"method": func(entry *S3OperationLog) string {
+		return ExtractHTTPMethod(entry.URI)
+	},
+}
+
+const (
+	defaultLokiBatchSize = 1000
+	defaultLokiBatchWait = 5 * time.Second
+	defaultLokiQueueSize = 10000
+)
+
+// lokiPushRetry retries the pushes rejected by rate limits and server errors
+var lokiPushRetry = retry.Policy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 2 * time.Second, Jitter: 0.2}
+
+// lokiEntry is a raw ops log entry waiting for its batch
+type lokiEntry struct {
+	labels map[string]string
+	time   time.Time
+	line   string
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPusher batches the raw ops log entries and pushes them to the push API
+// of Loki. Pushing runs in its own goroutine, so a slow Loki never holds up the
+// pipeline; entries arriving while the queue is full are dropped and counted.
+type lokiPusher struct {
+	url       string
+	tenant    string
+	labels    []string
+	static    map[string]string
+	batchSize int
+	batchWait time.Duration
+	client    *http.Client
+	entries   chan lokiEntry
+	done      chan struct{}
+}
+
+// newLokiPusher starts a pusher for cfg, nil if the sink is disabled
+func newLokiPusher(cfg LokiSinkConfig, podName string) (*lokiPusher, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+
+	labels, err := parseLokiLabels(cfg.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &lokiPusher{
+		url:       strings.TrimSuffix(cfg.URL, "/") + "/loki/api/v1/push",
+		tenant:    cfg.Tenant,
+		labels:    labels,
+		static:    map[string]string{"source": "ops-log"},
+		batchSize: cfg.BatchSize,
+		batchWait: cfg.BatchWait,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		done:      make(chan struct{}),
+	}
+	if podName != "" {
+		p.static["pod"] = podName
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = defaultLokiBatchSize
+	}
+	if p.batchWait <= 0 {
+		p.batchWait = defaultLokiBatchWait
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultLokiQueueSize
+	}
+	p.entries = make(chan lokiEntry, queueSize)
+
+	go func() {
+		defer close(p.done)
+		telemetry.RunWorker(context.Background(), "ops-log.loki", func(context.Context) { p.run() })
+	}()
+
+	log.Info().
+		Str("url", p.url).
+		Strs("labels", labels).
+		Int("batch_size", p.batchSize).
+		Dur("batch_wait", p.batchWait).
+		Msg("Loki sink initialized")
+	return p, nil
+}
+
+// parseLokiLabels parses the comma-separated label names of the sink
+func parseLokiLabels(names string) ([]string, error) {
+	var labels []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := lokiLabelFields[name]; !ok {
+			return nil, fmt.Errorf("unknown Loki label %q, expected tenant, bucket, status_class, operation or method", name)
+		}
+		labels = append(labels, name)
+	}
+	return labels, nil
+}
+
+// entryTime returns the time of the entry, now if RGW wrote none
+func entryTime(entry *S3OperationLog) time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05.999999Z", entry.Time)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
+
+// Push queues the raw entry for the next batch. The entry has to be decoded
+// into entry already, for its labels.
+func (p *lokiPusher) Push(raw []byte, entry *S3OperationLog) {
+	labels := make(map[string]string, len(p.static)+len(p.labels))
+	for name, value := range p.static {
+		labels[name] = value
+	}
+	for _, name := range p.labels {
+		labels[name] = lokiLabelFields[name](entry)
+	}
+
+	select {
+	case p.entries <- lokiEntry{labels: labels, time: entryTime(entry), line: string(raw)}:
+	default:
+		lokiEntries.WithLabelValues("queue_full").Inc()
+	}
+}
+
+// Close pushes the queued entries and stops the pusher
+func (p *lokiPusher) Close() {
+	close(p.entries)
+	<-p.done
+}
+
+func (p *lokiPusher) run() {
+	ticker := time.NewTicker(p.batchWait)
+	defer ticker.Stop()
+
+	// The batches shrink under memory pressure
+	batch := make([]lokiEntry, 0, p.batchSize)
+	size := selflimit.BatchSize(p.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.push(context.Background(), batch); err != nil {
+			lokiEntries.WithLabelValues("push_failed").Add(float64(len(batch)))
+			log.Error().Err(err).Int("entries", len(batch)).Msg("Error pushing ops log entries to Loki")
+		} else {
+			lokiEntries.WithLabelValues("pushed").Add(float64(len(batch)))
+		}
+		batch = batch[:0]
+		size = selflimit.BatchSize(p.batchSize)
+	}
+
+	for {
+		select {
+		case entry, ok := <-p.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// push sends a batch, retrying on rate limits and server errors
+func (p *lokiPusher) push(ctx context.Context, batch []lokiEntry) error {
+	body, err := encodeLokiPush(batch)
+	if err != nil {
+		return err
+	}
+
+	return retry.Do(ctx, "loki", lokiPushRetry, func(ctx context.Context) error {
+		retryable, err := p.post(ctx, body)
+		if err != nil && !retryable {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+}
+
+func (p *lokiPusher) post(ctx context.Context, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", p.tenant)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("loki returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// encodeLokiPush groups the batch into one stream per label set, each in
+// time order as Loki expects
+func encodeLokiPush(batch []lokiEntry) ([]byte, error) {
+	sorted := append([]lokiEntry(nil), batch...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].time.Before(sorted[j].time) })
+
+	streams := map[string]*lokiStream{}
+	var keys []string
+	for _, entry := range sorted {
+		key := streamKey(entry.labels)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: entry.labels}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.time.UnixNano(), 10), entry.line})
+	}
+
+	push := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range keys {
+		push.Streams = append(push.Streams, streams[key])
+	}
+	return json.Marshal(push)
+}
+
+// streamKey identifies a label set, independent of the map order
+func streamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+		b.WriteByte('\xff')
+	}
+	return b.String()
+}
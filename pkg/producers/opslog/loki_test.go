@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lokiPush struct {
+	tenant  string
+	streams []lokiStream
+}
+
+// fakeLoki records the pushes it receives, answering with status
+func fakeLoki(t *testing.T, status int) (*httptest.Server, func() []lokiPush) {
+	var mu sync.Mutex
+	var pushes []lokiPush
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var push struct {
+			Streams []lokiStream `json:"streams"`
+		}
+		require.NoError(t, json.Unmarshal(body, &push))
+
+		mu.Lock()
+		pushes = append(pushes, lokiPush{tenant: r.Header.Get("X-Scope-OrgID"), streams: push.Streams})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []lokiPush {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]lokiPush(nil), pushes...)
+	}
+}
+
+func pushEntry(p *lokiPusher, raw string) {
+	var entry S3OperationLog
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		panic(err)
+	}
+	entry.CleanupBucketName()
+	p.Push([]byte(raw), &entry)
+}
+
+func TestLokiPusherStreams(t *testing.T) {
+	server, pushes := fakeLoki(t, http.StatusNoContent)
+	p, err := newLokiPusher(LokiSinkConfig{
+		URL:    server.URL + "/",
+		Tenant: "ops",
+		Labels: "tenant, bucket, status_class",
+	}, "rgw-0")
+	require.NoError(t, err)
+
+	later := `{"bucket":"proj/photos","user":"alice$proj","http_status":"404","time":"2025-03-01T10:00:02.000000Z"}`
+	earlier := `{"bucket":"proj/photos","user":"bob$proj","http_status":"403","time":"2025-03-01T10:00:01.000000Z"}`
+	pushEntry(p, later)
+	pushEntry(p, earlier)
+	pushEntry(p, `{"bucket":"","user":"carol","http_status":"200","time":"2025-03-01T10:00:00.000000Z"}`)
+	p.Close()
+
+	received := pushes()
+	require.Len(t, received, 1, "the entries are pushed in one batch")
+	assert.Equal(t, "ops", received[0].tenant)
+	require.Len(t, received[0].streams, 2)
+
+	errors := received[0].streams[1]
+	assert.Equal(t, map[string]string{
+		"source": "ops-log", "pod": "rgw-0", "tenant": "proj", "bucket": "photos", "status_class": "4xx",
+	}, errors.Stream)
+	require.Len(t, errors.Values, 2)
+	assert.Equal(t, [2]string{"1740823201000000000", earlier}, errors.Values[0], "streams are in time order")
+	assert.Equal(t, later, errors.Values[1][1])
+
+	anonymous := received[0].streams[0]
+	assert.Equal(t, "none", anonymous.Stream["tenant"])
+	assert.Equal(t, "none", anonymous.Stream["bucket"])
+	assert.Equal(t, "2xx", anonymous.Stream["status_class"])
+}
+
+func TestLokiPusherBatches(t *testing.T) {
+	server, pushes := fakeLoki(t, http.StatusNoContent)
+	p, err := newLokiPusher(LokiSinkConfig{URL: server.URL, BatchSize: 2, BatchWait: time.Hour}, "")
+	require.NoError(t, err)
+
+	for range 3 {
+		pushEntry(p, `{"bucket":"b","user":"u$t","http_status":"200"}`)
+	}
+	require.Eventually(t, func() bool { return len(pushes()) == 1 }, time.Second, 10*time.Millisecond,
+		"a full batch is pushed without waiting")
+	p.Close()
+
+	received := pushes()
+	require.Len(t, received, 2, "the rest is pushed on close")
+	assert.Empty(t, received[0].tenant)
+	assert.Equal(t, map[string]string{"source": "ops-log"}, received[0].streams[0].Stream, "no labels configured")
+	assert.Len(t, received[1].streams[0].Values, 1)
+}
+
+func TestLokiPusherRejected(t *testing.T) {
+	server, pushes := fakeLoki(t, http.StatusBadRequest)
+	p, err := newLokiPusher(LokiSinkConfig{URL: server.URL}, "")
+	require.NoError(t, err)
+
+	pushEntry(p, `{"bucket":"b","http_status":"200"}`)
+	p.Close()
+	assert.Len(t, pushes(), 1, "client errors are not retried")
+}
+
+func TestNewLokiPusher(t *testing.T) {
+	p, err := newLokiPusher(LokiSinkConfig{}, "rgw-0")
+	require.NoError(t, err)
+	assert.Nil(t, p, "disabled without URL")
+
+	_, err = newLokiPusher(LokiSinkConfig{URL: "http://loki:3100", Labels: "tenant,user"}, "")
+	assert.ErrorContains(t, err, `unknown Loki label "user"`)
+}
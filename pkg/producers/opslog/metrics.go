@@ -5,10 +5,12 @@
 package opslog
 
 import (
-	"encoding/json"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/apicategory"
 )
 
 type Metrics struct {
@@ -95,6 +97,21 @@ type Metrics struct {
 	BytesReceivedByIPDetailed    sync.Map // "user|ip" -> *atomic.Uint64
 	BytesReceivedPerIPPerTenant  sync.Map // "tenant|ip" -> *atomic.Uint64
 	BytesReceivedPerTenantFromIP sync.Map // "tenant" -> *atomic.Uint64
+
+	// API category tracking, the traffic and summed latency of every API
+	// category (see apicategory.Of) joined into the usage of users and buckets
+	RequestsByCategoryPerUser        sync.Map // "user|category" -> *atomic.Uint64
+	RequestTimeByCategoryPerUser     sync.Map // "user|category" -> *atomic.Uint64 (milliseconds)
+	BytesSentByCategoryPerUser       sync.Map // "user|category" -> *atomic.Uint64
+	BytesReceivedByCategoryPerUser   sync.Map // "user|category" -> *atomic.Uint64
+	RequestsByCategoryPerBucket      sync.Map // "tenant|bucket|category" -> *atomic.Uint64
+	RequestTimeByCategoryPerBucket   sync.Map // "tenant|bucket|category" -> *atomic.Uint64 (milliseconds)
+	BytesSentByCategoryPerBucket     sync.Map // "tenant|bucket|category" -> *atomic.Uint64
+	BytesReceivedByCategoryPerBucket sync.Map // "tenant|bucket|category" -> *atomic.Uint64
+
+	RequestsByBucketTags      sync.Map // "owner|tag 1|...|tag n" -> *atomic.Uint64
+	BytesSentByBucketTags     sync.Map // "owner|tag 1|...|tag n" -> *atomic.Uint64
+	BytesReceivedByBucketTags sync.Map // "owner|tag 1|...|tag n" -> *atomic.Uint64
 }
 
 func NewMetrics(obs ...func(user string, tenant string, bucket string, method string, seconds float64)) *Metrics {
@@ -110,178 +127,245 @@ func NewMetrics(obs ...func(user string, tenant string, bucket string, method st
 	}
 }
 
-// Convert metrics to a JSON-friendly struct
-func (m *Metrics) ToJSON(metricsConfig *MetricsConfig) ([]byte, error) {
-	data := map[string]any{
-		"total_requests": m.TotalRequests.Load(),
-		"bytes_sent":     m.BytesSent.Load(),
-		"bytes_received": m.BytesReceived.Load(),
-		"errors":         m.Errors.Load(),
+// AggregatedMetrics are the metrics of an interval published to NATS, the
+// payload of schema.OpsMetrics. The breakdowns are only set if tracked.
+type AggregatedMetrics struct {
+	// WindowStart and WindowEnd are the interval the metrics were counted
+	// in, aligned to its wall-clock boundaries except the first window of a
+	// sidecar, which starts when it does
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	InstanceID  string    `json:"instance_id,omitempty"` // the sidecar, its pod name by default
+	Host        string    `json:"host,omitempty"`        // the node of the sidecar
+
+	TotalRequests uint64 `json:"total_requests"`
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+	Errors        uint64 `json:"errors"`
+
+	RequestsDetailed               map[string]uint64 `json:"requests_detailed,omitempty"`
+	RequestsByUser                 map[string]uint64 `json:"requests_by_user,omitempty"`
+	RequestsByBucket               map[string]uint64 `json:"requests_by_bucket,omitempty"`
+	RequestsByTenant               map[string]uint64 `json:"requests_by_tenant,omitempty"`
+	RequestsByMethodDetailed       map[string]uint64 `json:"requests_by_method_detailed,omitempty"`
+	RequestsByMethodPerUser        map[string]uint64 `json:"requests_by_method_per_user,omitempty"`
+	RequestsByMethodPerBucket      map[string]uint64 `json:"requests_by_method_per_bucket,omitempty"`
+	RequestsByMethodPerTenant      map[string]uint64 `json:"requests_by_method_per_tenant,omitempty"`
+	RequestsByMethodGlobal         map[string]uint64 `json:"requests_by_method_global,omitempty"`
+	RequestsByOperationDetailed    map[string]uint64 `json:"requests_by_operation_detailed,omitempty"`
+	RequestsByOperationPerUser     map[string]uint64 `json:"requests_by_operation_per_user,omitempty"`
+	RequestsByOperationPerBucket   map[string]uint64 `json:"requests_by_operation_per_bucket,omitempty"`
+	RequestsByOperationPerTenant   map[string]uint64 `json:"requests_by_operation_per_tenant,omitempty"`
+	RequestsByOperationGlobal      map[string]uint64 `json:"requests_by_operation_global,omitempty"`
+	RequestsByStatusDetailed       map[string]uint64 `json:"requests_by_status_detailed,omitempty"`
+	RequestsByStatusPerUser        map[string]uint64 `json:"requests_by_status_per_user,omitempty"`
+	RequestsByStatusPerBucket      map[string]uint64 `json:"requests_by_status_per_bucket,omitempty"`
+	RequestsByStatusPerTenant      map[string]uint64 `json:"requests_by_status_per_tenant,omitempty"`
+	RequestsPerStatusCode          map[string]uint64 `json:"requests_per_status,omitempty"`
+	RequestsByIPDetailed           map[string]uint64 `json:"requests_by_ip,omitempty"`
+	RequestsByIPBucketMethodTenant map[string]uint64 `json:"requests_by_ip_bucket_method_tenant,omitempty"`
+	BytesSentDetailed              map[string]uint64 `json:"bytes_sent_detailed,omitempty"`
+	BytesSentPerUser               map[string]uint64 `json:"bytes_sent_per_user,omitempty"`
+	BytesSentPerBucket             map[string]uint64 `json:"bytes_sent_per_bucket,omitempty"`
+	BytesSentPerTenant             map[string]uint64 `json:"bytes_sent_per_tenant,omitempty"`
+	BytesReceivedDetailed          map[string]uint64 `json:"bytes_received_detailed,omitempty"`
+	BytesReceivedPerUser           map[string]uint64 `json:"bytes_received_per_user,omitempty"`
+	BytesReceivedPerBucket         map[string]uint64 `json:"bytes_received_per_bucket,omitempty"`
+	BytesReceivedPerTenant         map[string]uint64 `json:"bytes_received_per_tenant,omitempty"`
+	BytesSentByIPDetailed          map[string]uint64 `json:"bytes_sent_by_ip,omitempty"`
+	BytesReceivedByIPDetailed      map[string]uint64 `json:"bytes_received_by_ip,omitempty"`
+	ErrorsDetailed                 map[string]uint64 `json:"errors_detailed,omitempty"`
+	ErrorsPerUser                  map[string]uint64 `json:"errors_per_user,omitempty"`
+	ErrorsPerBucket                map[string]uint64 `json:"errors_per_bucket,omitempty"`
+	ErrorsPerTenant                map[string]uint64 `json:"errors_per_tenant,omitempty"`
+	ErrorsPerStatus                map[string]uint64 `json:"errors_per_status,omitempty"`
+	ErrorsPerIP                    map[string]uint64 `json:"errors_per_ip,omitempty"`
+	TimeoutErrors                  map[string]uint64 `json:"timeout_errors,omitempty"`
+	ErrorsByCategory               map[string]uint64 `json:"errors_by_category,omitempty"`
+
+	RequestsByCategoryPerUser        map[string]uint64 `json:"requests_by_category_per_user,omitempty"`
+	RequestTimeMsByCategoryPerUser   map[string]uint64 `json:"request_time_ms_by_category_per_user,omitempty"`
+	BytesSentByCategoryPerUser       map[string]uint64 `json:"bytes_sent_by_category_per_user,omitempty"`
+	BytesReceivedByCategoryPerUser   map[string]uint64 `json:"bytes_received_by_category_per_user,omitempty"`
+	RequestsByCategoryPerBucket      map[string]uint64 `json:"requests_by_category_per_bucket,omitempty"`
+	RequestTimeMsByCategoryPerBucket map[string]uint64 `json:"request_time_ms_by_category_per_bucket,omitempty"`
+	BytesSentByCategoryPerBucket     map[string]uint64 `json:"bytes_sent_by_category_per_bucket,omitempty"`
+	BytesReceivedByCategoryPerBucket map[string]uint64 `json:"bytes_received_by_category_per_bucket,omitempty"`
+
+	// BucketTagKeys are the tags of the "owner|tag 1|...|tag n" keys of the
+	// metrics by bucket owner and tags
+	BucketTagKeys             []string          `json:"bucket_tag_keys,omitempty"`
+	RequestsByBucketTags      map[string]uint64 `json:"requests_by_bucket_tags,omitempty"`
+	BytesSentByBucketTags     map[string]uint64 `json:"bytes_sent_by_bucket_tags,omitempty"`
+	BytesReceivedByBucketTags map[string]uint64 `json:"bytes_received_by_bucket_tags,omitempty"`
+}
+
+// Aggregate returns the current metrics with the tracked breakdowns
+func (m *Metrics) Aggregate(metricsConfig *MetricsConfig) AggregatedMetrics {
+	aggregated := AggregatedMetrics{
+		TotalRequests: m.TotalRequests.Load(),
+		BytesSent:     m.BytesSent.Load(),
+		BytesReceived: m.BytesReceived.Load(),
+		Errors:        m.Errors.Load(),
 	}
 
 	if metricsConfig.TrackRequestsDetailed {
-		data["requests_detailed"] = loadSyncMap(&m.RequestsDetailed)
+		aggregated.RequestsDetailed = loadSyncMap(&m.RequestsDetailed)
 	}
-
 	if metricsConfig.TrackRequestsPerUser {
-		data["requests_by_user"] = loadSyncMap(&m.RequestsByUser)
+		aggregated.RequestsByUser = loadSyncMap(&m.RequestsByUser)
 	}
-
 	if metricsConfig.TrackRequestsPerBucket {
-		data["requests_by_bucket"] = loadSyncMap(&m.RequestsByBucket)
+		aggregated.RequestsByBucket = loadSyncMap(&m.RequestsByBucket)
 	}
-
 	if metricsConfig.TrackRequestsPerTenant {
-		data["requests_by_tenant"] = loadSyncMap(&m.RequestsByTenant)
+		aggregated.RequestsByTenant = loadSyncMap(&m.RequestsByTenant)
 	}
-
 	if metricsConfig.TrackRequestsByMethodDetailed {
-		data["requests_by_method_detailed"] = loadSyncMap(&m.RequestsByMethodDetailed)
+		aggregated.RequestsByMethodDetailed = loadSyncMap(&m.RequestsByMethodDetailed)
 	}
-
 	if metricsConfig.TrackRequestsByMethodPerUser {
-		data["requests_by_method_per_user"] = loadSyncMap(&m.RequestsByMethodPerUser)
+		aggregated.RequestsByMethodPerUser = loadSyncMap(&m.RequestsByMethodPerUser)
 	}
-
 	if metricsConfig.TrackRequestsByMethodPerBucket {
-		data["requests_by_method_per_bucket"] = loadSyncMap(&m.RequestsByMethodPerBucket)
+		aggregated.RequestsByMethodPerBucket = loadSyncMap(&m.RequestsByMethodPerBucket)
 	}
-
 	if metricsConfig.TrackRequestsByMethodPerTenant {
-		data["requests_by_method_per_tenant"] = loadSyncMap(&m.RequestsByMethodPerTenant)
+		aggregated.RequestsByMethodPerTenant = loadSyncMap(&m.RequestsByMethodPerTenant)
 	}
-
 	if metricsConfig.TrackRequestsByMethodGlobal {
-		data["requests_by_method_global"] = loadSyncMap(&m.RequestsByMethodGlobal)
+		aggregated.RequestsByMethodGlobal = loadSyncMap(&m.RequestsByMethodGlobal)
 	}
-
 	if metricsConfig.TrackRequestsByOperationDetailed {
-		data["requests_by_operation_detailed"] = loadSyncMap(&m.RequestsByOperationDetailed)
+		aggregated.RequestsByOperationDetailed = loadSyncMap(&m.RequestsByOperationDetailed)
 	}
-
 	if metricsConfig.TrackRequestsByOperationPerUser {
-		data["requests_by_operation_per_user"] = loadSyncMap(&m.RequestsByOperationPerUser)
+		aggregated.RequestsByOperationPerUser = loadSyncMap(&m.RequestsByOperationPerUser)
 	}
-
 	if metricsConfig.TrackRequestsByOperationPerBucket {
-		data["requests_by_operation_per_bucket"] = loadSyncMap(&m.RequestsByOperationPerBucket)
+		aggregated.RequestsByOperationPerBucket = loadSyncMap(&m.RequestsByOperationPerBucket)
 	}
-
 	if metricsConfig.TrackRequestsByOperationPerTenant {
-		data["requests_by_operation_per_tenant"] = loadSyncMap(&m.RequestsByOperationPerTenant)
+		aggregated.RequestsByOperationPerTenant = loadSyncMap(&m.RequestsByOperationPerTenant)
 	}
-
 	if metricsConfig.TrackRequestsByOperationGlobal {
-		data["requests_by_operation_global"] = loadSyncMap(&m.RequestsByOperationGlobal)
+		aggregated.RequestsByOperationGlobal = loadSyncMap(&m.RequestsByOperationGlobal)
 	}
-
 	if metricsConfig.TrackRequestsByStatusDetailed {
-		data["requests_by_status_detailed"] = loadSyncMap(&m.RequestsByStatusDetailed)
+		aggregated.RequestsByStatusDetailed = loadSyncMap(&m.RequestsByStatusDetailed)
 	}
-
 	if metricsConfig.TrackRequestsByStatusPerUser {
-		data["requests_by_status_per_user"] = loadSyncMap(&m.RequestsByStatusPerUser)
+		aggregated.RequestsByStatusPerUser = loadSyncMap(&m.RequestsByStatusPerUser)
 	}
 	if metricsConfig.TrackRequestsByStatusPerBucket {
-		data["requests_by_status_per_bucket"] = loadSyncMap(&m.RequestsByStatusPerBucket)
+		aggregated.RequestsByStatusPerBucket = loadSyncMap(&m.RequestsByStatusPerBucket)
 	}
-
 	if metricsConfig.TrackRequestsByStatusPerTenant {
-		data["requests_by_status_per_tenant"] = loadSyncMap(&m.RequestsByStatusPerTenant)
+		aggregated.RequestsByStatusPerTenant = loadSyncMap(&m.RequestsByStatusPerTenant)
 	}
-
 	if metricsConfig.TrackRequestsByStatusDetailed {
-		data["requests_per_status"] = loadSyncMap(&m.RequestsPerStatusCode)
+		aggregated.RequestsPerStatusCode = loadSyncMap(&m.RequestsPerStatusCode)
 	}
-
 	if metricsConfig.TrackRequestsByIPDetailed {
-		data["requests_by_ip"] = loadSyncMap(&m.RequestsByIPDetailed)
+		aggregated.RequestsByIPDetailed = loadSyncMap(&m.RequestsByIPDetailed)
 	}
 	if metricsConfig.TrackRequestsByIPBucketMethodTenant {
-		data["requests_by_ip_bucket_method_tenant"] = loadSyncMap(&m.RequestsByIPBucketMethodTenant)
+		aggregated.RequestsByIPBucketMethodTenant = loadSyncMap(&m.RequestsByIPBucketMethodTenant)
 	}
-
-	// Conditional fields (bytes tracking)
 	if metricsConfig.TrackBytesSentDetailed {
-		data["bytes_sent_detailed"] = loadSyncMap(&m.BytesSentDetailed)
+		aggregated.BytesSentDetailed = loadSyncMap(&m.BytesSentDetailed)
 	}
-
 	if metricsConfig.TrackBytesSentPerUser {
-		data["bytes_sent_per_user"] = loadSyncMap(&m.BytesSentPerUser)
+		aggregated.BytesSentPerUser = loadSyncMap(&m.BytesSentPerUser)
 	}
-
 	if metricsConfig.TrackBytesSentPerBucket {
-		data["bytes_sent_per_bucket"] = loadSyncMap(&m.BytesSentPerBucket)
+		aggregated.BytesSentPerBucket = loadSyncMap(&m.BytesSentPerBucket)
 	}
 	if metricsConfig.TrackBytesSentPerTenant {
-		data["bytes_sent_per_tenant"] = loadSyncMap(&m.BytesSentPerTenant)
+		aggregated.BytesSentPerTenant = loadSyncMap(&m.BytesSentPerTenant)
 	}
-
 	if metricsConfig.TrackBytesReceivedDetailed {
-		data["bytes_received_detailed"] = loadSyncMap(&m.BytesReceivedDetailed)
+		aggregated.BytesReceivedDetailed = loadSyncMap(&m.BytesReceivedDetailed)
 	}
-
 	if metricsConfig.TrackBytesReceivedPerUser {
-		data["bytes_received_per_user"] = loadSyncMap(&m.BytesReceivedPerUser)
+		aggregated.BytesReceivedPerUser = loadSyncMap(&m.BytesReceivedPerUser)
 	}
-
 	if metricsConfig.TrackBytesReceivedPerBucket {
-		data["bytes_received_per_bucket"] = loadSyncMap(&m.BytesReceivedPerBucket)
+		aggregated.BytesReceivedPerBucket = loadSyncMap(&m.BytesReceivedPerBucket)
 	}
 	if metricsConfig.TrackBytesReceivedPerTenant {
-		data["bytes_received_per_tenant"] = loadSyncMap(&m.BytesReceivedPerTenant)
+		aggregated.BytesReceivedPerTenant = loadSyncMap(&m.BytesReceivedPerTenant)
 	}
-
 	if metricsConfig.TrackBytesSentByIPDetailed {
-		data["bytes_sent_by_ip"] = loadSyncMap(&m.BytesSentByIPDetailed)
+		aggregated.BytesSentByIPDetailed = loadSyncMap(&m.BytesSentByIPDetailed)
 	}
-
 	if metricsConfig.TrackBytesReceivedByIPDetailed {
-		data["bytes_received_by_ip"] = loadSyncMap(&m.BytesReceivedByIPDetailed)
+		aggregated.BytesReceivedByIPDetailed = loadSyncMap(&m.BytesReceivedByIPDetailed)
 	}
-
-	// Conditional fields (errors tracking)
 	if metricsConfig.TrackErrorsDetailed {
-		data["errors_detailed"] = loadSyncMap(&m.ErrorsDetailed)
+		aggregated.ErrorsDetailed = loadSyncMap(&m.ErrorsDetailed)
 	}
 	if metricsConfig.TrackErrorsPerUser {
-		data["errors_per_user"] = loadSyncMap(&m.ErrorsPerUser)
+		aggregated.ErrorsPerUser = loadSyncMap(&m.ErrorsPerUser)
 	}
-
 	if metricsConfig.TrackErrorsPerBucket {
-		data["errors_per_bucket"] = loadSyncMap(&m.ErrorsPerBucket)
+		aggregated.ErrorsPerBucket = loadSyncMap(&m.ErrorsPerBucket)
 	}
-
 	if metricsConfig.TrackErrorsPerTenant {
-		data["errors_per_tenant"] = loadSyncMap(&m.ErrorsPerTenant)
+		aggregated.ErrorsPerTenant = loadSyncMap(&m.ErrorsPerTenant)
 	}
-
 	if metricsConfig.TrackErrorsPerStatus {
-		data["errors_per_status"] = loadSyncMap(&m.ErrorsPerStatus)
+		aggregated.ErrorsPerStatus = loadSyncMap(&m.ErrorsPerStatus)
 	}
 	if metricsConfig.TrackErrorsByIP {
-		data["errors_per_ip"] = loadSyncMap(&m.ErrorsPerIP)
+		aggregated.ErrorsPerIP = loadSyncMap(&m.ErrorsPerIP)
 	}
-
 	if metricsConfig.TrackTimeoutErrors {
-		data["timeout_errors"] = loadSyncMap(&m.TimeoutErrors)
+		aggregated.TimeoutErrors = loadSyncMap(&m.TimeoutErrors)
 	}
-
 	if metricsConfig.TrackErrorsByCategory {
-		data["errors_by_category"] = loadSyncMap(&m.ErrorsByCategory)
-	}
-
-	return json.Marshal(data)
+		aggregated.ErrorsByCategory = loadSyncMap(&m.ErrorsByCategory)
+	}
+	if metricsConfig.TrackAPICategories {
+		aggregated.RequestsByCategoryPerUser = loadSyncMap(&m.RequestsByCategoryPerUser)
+		aggregated.RequestTimeMsByCategoryPerUser = loadSyncMap(&m.RequestTimeByCategoryPerUser)
+		aggregated.BytesSentByCategoryPerUser = loadSyncMap(&m.BytesSentByCategoryPerUser)
+		aggregated.BytesReceivedByCategoryPerUser = loadSyncMap(&m.BytesReceivedByCategoryPerUser)
+		aggregated.RequestsByCategoryPerBucket = loadSyncMap(&m.RequestsByCategoryPerBucket)
+		aggregated.RequestTimeMsByCategoryPerBucket = loadSyncMap(&m.RequestTimeByCategoryPerBucket)
+		aggregated.BytesSentByCategoryPerBucket = loadSyncMap(&m.BytesSentByCategoryPerBucket)
+		aggregated.BytesReceivedByCategoryPerBucket = loadSyncMap(&m.BytesReceivedByCategoryPerBucket)
+	}
+	if metricsConfig.BucketTags != nil {
+		aggregated.BucketTagKeys = metricsConfig.BucketTags.Tags()
+		aggregated.RequestsByBucketTags = loadSyncMap(&m.RequestsByBucketTags)
+		aggregated.BytesSentByBucketTags = loadSyncMap(&m.BytesSentByBucketTags)
+		aggregated.BytesReceivedByBucketTags = loadSyncMap(&m.BytesReceivedByBucketTags)
+	}
+
+	return aggregated
 }
 
 // Update increments metrics based on a new log entry
 func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig) {
+	if metricsConfig.Replication.Matches(&logEntry) {
+		// Replication requests still hold RGW threads, but would inflate the
+		// traffic of the tenants
+		userStr, tenantStr := extractUserAndTenant(logEntry.User)
+		if metricsConfig.TrackBucketConcurrency {
+			bucketConcurrency.observe(logEntry, tenantStr)
+		}
+		observeReplication(&logEntry, userStr, tenantStr, ExtractHTTPMethod(logEntry.URI))
+		return
+	}
+
 	m.TotalRequests.Add(1)
 	m.BytesSent.Add(uint64(logEntry.BytesSent))
 	m.BytesReceived.Add(uint64(logEntry.BytesReceived))
 
 	method := ExtractHTTPMethod(logEntry.URI)
 	userStr, tenantStr := extractUserAndTenant(logEntry.User)
+	ip := metricsConfig.IPClasses.Classify(logEntry.RemoteAddr)
 
 	if metricsConfig.TrackBucketSLO {
 		// observeBucketSLI writes directly to Prometheus CounterVec/HistogramVec rather than
@@ -292,6 +376,22 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 		observeBucketSLI(logEntry, tenantStr)
 	}
 
+	if metricsConfig.TrackBucketConcurrency {
+		bucketConcurrency.observe(logEntry, tenantStr)
+	}
+
+	if metricsConfig.TrackAuthMethods {
+		observeAuthMethod(&logEntry, userStr, tenantStr)
+	}
+
+	if metricsConfig.TrackHealthChecks {
+		healthChecks.observe(&logEntry, tenantStr, method)
+	}
+
+	if metricsConfig.Cost != nil {
+		observeCost(metricsConfig.Cost, &logEntry, tenantStr, method)
+	}
+
 	if metricsConfig.TrackRequestsDetailed {
 		key := logEntry.User + "|" + logEntry.Bucket + "|" + method + "|" + logEntry.HTTPStatus
 		incrementSyncMap(&m.RequestsDetailed, key)
@@ -381,12 +481,12 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 	incrementSyncMap(&m.RequestsPerStatusCode, logEntry.HTTPStatus)
 
 	if metricsConfig.TrackRequestsByIPDetailed {
-		key := logEntry.User + "|" + logEntry.RemoteAddr
+		key := logEntry.User + "|" + ip
 		incrementSyncMap(&m.RequestsByIPDetailed, key)
 	}
 
 	if metricsConfig.TrackRequestsByIPPerTenant {
-		key := tenantStr + "|" + logEntry.RemoteAddr
+		key := tenantStr + "|" + ip
 		incrementSyncMap(&m.RequestsPerIPPerTenant, key)
 	}
 
@@ -395,7 +495,7 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 	}
 
 	if metricsConfig.TrackRequestsByIPBucketMethodTenant {
-		key := logEntry.RemoteAddr + "|" + logEntry.Bucket + "|" + method + "|" + tenantStr
+		key := ip + "|" + logEntry.Bucket + "|" + method + "|" + tenantStr
 		incrementSyncMap(&m.RequestsByIPBucketMethodTenant, key)
 	}
 
@@ -419,12 +519,12 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 			incrementSyncMapValue(&m.BytesSentPerTenant, tenantStr, uint64(logEntry.BytesSent))
 		}
 		if metricsConfig.TrackBytesSentByIPDetailed {
-			key := logEntry.User + "|" + logEntry.RemoteAddr
+			key := logEntry.User + "|" + ip
 			incrementSyncMapValue(&m.BytesSentByIPDetailed, key, uint64(logEntry.BytesSent))
 		}
 
 		if metricsConfig.TrackBytesSentByIPPerTenant {
-			key := tenantStr + "|" + logEntry.RemoteAddr
+			key := tenantStr + "|" + ip
 			incrementSyncMapValue(&m.BytesSentPerIPPerTenant, key, uint64(logEntry.BytesSent))
 		}
 
@@ -454,12 +554,12 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 		}
 
 		if metricsConfig.TrackBytesReceivedByIPDetailed {
-			key := logEntry.User + "|" + logEntry.RemoteAddr
+			key := logEntry.User + "|" + ip
 			incrementSyncMapValue(&m.BytesReceivedByIPDetailed, key, uint64(logEntry.BytesReceived))
 		}
 
 		if metricsConfig.TrackBytesReceivedByIPPerTenant {
-			key := tenantStr + "|" + logEntry.RemoteAddr
+			key := tenantStr + "|" + ip
 			incrementSyncMapValue(&m.BytesReceivedPerIPPerTenant, key, uint64(logEntry.BytesReceived))
 		}
 
@@ -505,13 +605,36 @@ func (m *Metrics) Update(logEntry S3OperationLog, metricsConfig *MetricsConfig)
 		}
 
 		if metricsConfig.TrackErrorsByIP {
-			key := logEntry.RemoteAddr + "|" + tenantStr + "|" + logEntry.HTTPStatus
+			key := ip + "|" + tenantStr + "|" + logEntry.HTTPStatus
 			incrementSyncMap(&m.ErrorsPerIP, key)
 		}
 
 		m.Errors.Add(1)
 	}
 
+	if metricsConfig.TrackAPICategories {
+		category := apicategory.Of(logEntry.Operation, method)
+		userKey := logEntry.User + "|" + category
+		incrementSyncMap(&m.RequestsByCategoryPerUser, userKey)
+		incrementSyncMapValue(&m.RequestTimeByCategoryPerUser, userKey, uint64(max(logEntry.TotalTime, 0)))
+		incrementSyncMapValue(&m.BytesSentByCategoryPerUser, userKey, uint64(max(logEntry.BytesSent, 0)))
+		incrementSyncMapValue(&m.BytesReceivedByCategoryPerUser, userKey, uint64(max(logEntry.BytesReceived, 0)))
+		if logEntry.Bucket != "" {
+			bucketKey := tenantStr + "|" + logEntry.Bucket + "|" + category
+			incrementSyncMap(&m.RequestsByCategoryPerBucket, bucketKey)
+			incrementSyncMapValue(&m.RequestTimeByCategoryPerBucket, bucketKey, uint64(max(logEntry.TotalTime, 0)))
+			incrementSyncMapValue(&m.BytesSentByCategoryPerBucket, bucketKey, uint64(max(logEntry.BytesSent, 0)))
+			incrementSyncMapValue(&m.BytesReceivedByCategoryPerBucket, bucketKey, uint64(max(logEntry.BytesReceived, 0)))
+		}
+	}
+
+	if metricsConfig.BucketTags != nil && logEntry.Bucket != "" {
+		key := metricsConfig.BucketTags.key(tenantStr, logEntry.Bucket)
+		incrementSyncMap(&m.RequestsByBucketTags, key)
+		incrementSyncMapValue(&m.BytesSentByBucketTags, key, uint64(max(logEntry.BytesSent, 0)))
+		incrementSyncMapValue(&m.BytesReceivedByBucketTags, key, uint64(max(logEntry.BytesReceived, 0)))
+	}
+
 	// Latency Tracking
 	if logEntry.TotalTime > 0 {
 		if metricsConfig.TrackLatencyDetailed ||
@@ -580,6 +703,17 @@ func (m *Metrics) Reset() {
 	resetSyncMap(&m.BytesReceivedByIPDetailed)
 	resetSyncMap(&m.BytesReceivedPerIPPerTenant)
 	resetSyncMap(&m.BytesReceivedPerTenantFromIP)
+	resetSyncMap(&m.RequestsByCategoryPerUser)
+	resetSyncMap(&m.RequestTimeByCategoryPerUser)
+	resetSyncMap(&m.BytesSentByCategoryPerUser)
+	resetSyncMap(&m.BytesReceivedByCategoryPerUser)
+	resetSyncMap(&m.RequestsByCategoryPerBucket)
+	resetSyncMap(&m.RequestTimeByCategoryPerBucket)
+	resetSyncMap(&m.BytesSentByCategoryPerBucket)
+	resetSyncMap(&m.BytesReceivedByCategoryPerBucket)
+	resetSyncMap(&m.RequestsByBucketTags)
+	resetSyncMap(&m.BytesSentByBucketTags)
+	resetSyncMap(&m.BytesReceivedByBucketTags)
 }
 
 // Helper function: Update max atomic value
@@ -747,6 +881,17 @@ func (m *Metrics) Clone() *Metrics {
 	copySyncMap(&m.BytesReceivedByIPDetailed, &clone.BytesReceivedByIPDetailed)
 	copySyncMap(&m.BytesReceivedPerIPPerTenant, &clone.BytesReceivedPerIPPerTenant)
 	copySyncMap(&m.BytesReceivedPerTenantFromIP, &clone.BytesReceivedPerTenantFromIP)
+	copySyncMap(&m.RequestsByCategoryPerUser, &clone.RequestsByCategoryPerUser)
+	copySyncMap(&m.RequestTimeByCategoryPerUser, &clone.RequestTimeByCategoryPerUser)
+	copySyncMap(&m.BytesSentByCategoryPerUser, &clone.BytesSentByCategoryPerUser)
+	copySyncMap(&m.BytesReceivedByCategoryPerUser, &clone.BytesReceivedByCategoryPerUser)
+	copySyncMap(&m.RequestsByCategoryPerBucket, &clone.RequestsByCategoryPerBucket)
+	copySyncMap(&m.RequestTimeByCategoryPerBucket, &clone.RequestTimeByCategoryPerBucket)
+	copySyncMap(&m.BytesSentByCategoryPerBucket, &clone.BytesSentByCategoryPerBucket)
+	copySyncMap(&m.BytesReceivedByCategoryPerBucket, &clone.BytesReceivedByCategoryPerBucket)
+	copySyncMap(&m.RequestsByBucketTags, &clone.RequestsByBucketTags)
+	copySyncMap(&m.BytesSentByBucketTags, &clone.BytesSentByBucketTags)
+	copySyncMap(&m.BytesReceivedByBucketTags, &clone.BytesReceivedByBucketTags)
 
 	return clone
 }
@@ -817,6 +962,17 @@ func SubtractMetrics(total, previous *Metrics) *Metrics {
 	subtractSyncMap(&total.BytesReceivedByIPDetailed, &previous.BytesReceivedByIPDetailed, &delta.BytesReceivedByIPDetailed)
 	subtractSyncMap(&total.BytesReceivedPerIPPerTenant, &previous.BytesReceivedPerIPPerTenant, &delta.BytesReceivedPerIPPerTenant)
 	subtractSyncMap(&total.BytesReceivedPerTenantFromIP, &previous.BytesReceivedPerTenantFromIP, &delta.BytesReceivedPerTenantFromIP)
+	subtractSyncMap(&total.RequestsByCategoryPerUser, &previous.RequestsByCategoryPerUser, &delta.RequestsByCategoryPerUser)
+	subtractSyncMap(&total.RequestTimeByCategoryPerUser, &previous.RequestTimeByCategoryPerUser, &delta.RequestTimeByCategoryPerUser)
+	subtractSyncMap(&total.BytesSentByCategoryPerUser, &previous.BytesSentByCategoryPerUser, &delta.BytesSentByCategoryPerUser)
+	subtractSyncMap(&total.BytesReceivedByCategoryPerUser, &previous.BytesReceivedByCategoryPerUser, &delta.BytesReceivedByCategoryPerUser)
+	subtractSyncMap(&total.RequestsByCategoryPerBucket, &previous.RequestsByCategoryPerBucket, &delta.RequestsByCategoryPerBucket)
+	subtractSyncMap(&total.RequestTimeByCategoryPerBucket, &previous.RequestTimeByCategoryPerBucket, &delta.RequestTimeByCategoryPerBucket)
+	subtractSyncMap(&total.BytesSentByCategoryPerBucket, &previous.BytesSentByCategoryPerBucket, &delta.BytesSentByCategoryPerBucket)
+	subtractSyncMap(&total.BytesReceivedByCategoryPerBucket, &previous.BytesReceivedByCategoryPerBucket, &delta.BytesReceivedByCategoryPerBucket)
+	subtractSyncMap(&total.RequestsByBucketTags, &previous.RequestsByBucketTags, &delta.RequestsByBucketTags)
+	subtractSyncMap(&total.BytesSentByBucketTags, &previous.BytesSentByBucketTags, &delta.BytesSentByBucketTags)
+	subtractSyncMap(&total.BytesReceivedByBucketTags, &previous.BytesReceivedByBucketTags, &delta.BytesReceivedByBucketTags)
 
 	return delta
 }
@@ -449,6 +449,25 @@ func TestMetricsUpdate_ConditionalTracking(t *testing.T) {
 	assert.False(t, ok2, "Should not track detailed errors when disabled")
 }
 
+func TestMetricsUpdate_TrackAPICategories(t *testing.T) {
+	config := &MetricsConfig{TrackAPICategories: true}
+	m := NewMetrics()
+
+	m.Update(S3OperationLog{User: "user1$tenant1", Bucket: "bucket1", Operation: "get_obj", URI: "GET /bucket1/a HTTP/1.1", HTTPStatus: "200", BytesSent: 100, TotalTime: 20}, config)
+	m.Update(S3OperationLog{User: "user1$tenant1", Bucket: "bucket1", Operation: "get_obj", URI: "GET /bucket1/b HTTP/1.1", HTTPStatus: "200", BytesSent: 300, TotalTime: 40}, config)
+	m.Update(S3OperationLog{User: "user1$tenant1", Operation: "list_buckets", URI: "GET / HTTP/1.1", HTTPStatus: "200", TotalTime: 5}, config)
+
+	aggregated := m.Aggregate(config)
+	assert.Equal(t, map[string]uint64{"user1$tenant1|read": 2, "user1$tenant1|list": 1}, aggregated.RequestsByCategoryPerUser)
+	assert.Equal(t, uint64(60), aggregated.RequestTimeMsByCategoryPerUser["user1$tenant1|read"])
+	assert.Equal(t, uint64(400), aggregated.BytesSentByCategoryPerUser["user1$tenant1|read"])
+	// Requests without a bucket only count for the user
+	assert.Equal(t, map[string]uint64{"tenant1|bucket1|read": 2}, aggregated.RequestsByCategoryPerBucket)
+	assert.Equal(t, uint64(60), aggregated.RequestTimeMsByCategoryPerBucket["tenant1|bucket1|read"])
+
+	assert.Nil(t, m.Aggregate(&MetricsConfig{}).RequestsByCategoryPerUser)
+}
+
 func newUint64(val uint64) *atomic.Uint64 {
 	var u atomic.Uint64
 	u.Store(val)
@@ -5,16 +5,114 @@
 package opslog
 
 import (
-	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 )
 
+// PublishToNATS publishes an ops log entry as a message of schema.OpsEvent
 func PublishToNATS(nc *nats.Conn, msg interface{}, natsSubject string) error {
-	data, err := json.Marshal(msg)
+	return schema.Publish(nc, natsSubject, schema.OpsEvent, msg)
+}
+
+// TenantSubject returns the subject of the entry's tenant below subject, e.g.
+// rgw.s3.ops.<tenant>, so consumers of a tenant can be authorized for its
+// subject alone. Entries without a tenant go to <subject>.none.
+func TenantSubject(subject string, entry *S3OperationLog) string {
+	_, tenant := extractUserAndTenant(entry.User)
+	return subject + "." + natsutil.SubjectToken(tenant)
+}
+
+// NatsRouteAll is the class of the routes every entry is published to
+const NatsRouteAll = "all"
+
+// natsRoute publishes the entries of a class, see EventClassRead etc., to a
+// subject
+type natsRoute struct {
+	class   string
+	subject string
+}
+
+// natsRouter publishes the entries to the subjects of the routes of their
+// class, e.g. the failed requests to rgw.s3.errors and the writes to
+// rgw.s3.writes, so consumers subscribe to what they need instead of the
+// firehose
+type natsRouter struct {
+	classifier *eventClassifier
+	routes     []natsRoute
+}
+
+// ParseNatsRoutes parses comma-separated <class>=<subject> routes, the class
+// one of health_check, read, write, error or all
+func ParseNatsRoutes(routes string) ([]natsRoute, error) {
+	var parsed []natsRoute
+	for _, route := range strings.Split(routes, ",") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+		class, subject, ok := strings.Cut(route, "=")
+		class, subject = strings.TrimSpace(class), strings.TrimSpace(subject)
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("invalid route %q, expected <class>=<subject>", route)
+		}
+		if class != NatsRouteAll && !slices.Contains(eventClasses, class) {
+			return nil, fmt.Errorf("unknown class %q of route %q, expected one of %s or %s", class, route, strings.Join(eventClasses, ", "), NatsRouteAll)
+		}
+		if strings.ContainsAny(subject, "*> \t") {
+			return nil, fmt.Errorf("subject %q of route %q must not contain wildcards or whitespace", subject, route)
+		}
+		parsed = append(parsed, natsRoute{class: class, subject: subject})
+	}
+	return parsed, nil
+}
+
+// initNatsRoutes parses the routes of the entries, if configured. The
+// classes of the entries are told as under backpressure.
+func initNatsRoutes(cfg *OpsLogConfig) error {
+	routes, err := ParseNatsRoutes(cfg.NatsRoutes)
+	if err != nil || len(routes) == 0 {
+		return err
+	}
+	classifier, err := newEventClassifier(cfg.Backpressure)
 	if err != nil {
 		return err
 	}
+	cfg.natsRouter = &natsRouter{classifier: classifier, routes: routes}
+	return nil
+}
+
+// eventSubjects returns the subjects an entry is published to: NatsSubject,
+// or the subjects of the routes of its class if routes are configured. With
+// NatsTenantSubjects, the tenant of the entry is appended to each.
+func eventSubjects(cfg OpsLogConfig, entry *S3OperationLog) []string {
+	if cfg.natsRouter == nil {
+		return []string{eventSubject(cfg, entry)}
+	}
+	class := eventClasses[cfg.natsRouter.classifier.classify(entry)]
+	var subjects []string
+	for _, route := range cfg.natsRouter.routes {
+		if route.class != class && route.class != NatsRouteAll {
+			continue
+		}
+		subject := route.subject
+		if cfg.NatsTenantSubjects {
+			subject = TenantSubject(subject, entry)
+		}
+		if !slices.Contains(subjects, subject) {
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects
+}
 
-	return nc.Publish(natsSubject, data)
+// eventSubject returns the subject an entry is published to without routes
+func eventSubject(cfg OpsLogConfig, entry *S3OperationLog) string {
+	if cfg.NatsTenantSubjects {
+		return TenantSubject(cfg.NatsSubject, entry)
+	}
+	return cfg.NatsSubject
 }
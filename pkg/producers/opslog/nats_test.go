@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSubject(t *testing.T) {
+	cfg := OpsLogConfig{NatsSubject: "rgw.s3.ops"}
+	entry := &S3OperationLog{User: "alice$proj"}
+	assert.Equal(t, "rgw.s3.ops", eventSubject(cfg, entry), "one subject by default")
+
+	cfg.NatsTenantSubjects = true
+	assert.Equal(t, "rgw.s3.ops.proj", eventSubject(cfg, entry))
+	assert.Equal(t, "rgw.s3.ops.none", eventSubject(cfg, &S3OperationLog{User: "anonymous"}))
+	assert.Equal(t, "rgw.s3.ops.a%2Eb%3E", eventSubject(cfg, &S3OperationLog{User: "mallory$a.b>"}),
+		"tenants never add tokens or wildcards to the subject")
+}
+
+func TestParseNatsRoutes(t *testing.T) {
+	routes, err := ParseNatsRoutes(" error=rgw.s3.errors, write=rgw.s3.writes,all=rgw.s3.ops ,")
+	require.NoError(t, err)
+	assert.Equal(t, []natsRoute{
+		{class: EventClassError, subject: "rgw.s3.errors"},
+		{class: EventClassWrite, subject: "rgw.s3.writes"},
+		{class: NatsRouteAll, subject: "rgw.s3.ops"},
+	}, routes)
+
+	routes, err = ParseNatsRoutes("")
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+
+	for _, invalid := range []string{"errors=rgw.s3.errors", "rgw.s3.errors", "error=", "all=rgw.s3.>", "read=rgw.s3.*"} {
+		_, err := ParseNatsRoutes(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestEventSubjects(t *testing.T) {
+	cfg := OpsLogConfig{NatsSubject: "rgw.s3.ops"}
+	failed := &S3OperationLog{User: "alice$proj", URI: "PUT /photos/cat.jpg HTTP/1.1", HTTPStatus: "503"}
+	assert.Equal(t, []string{"rgw.s3.ops"}, eventSubjects(cfg, failed), "NatsSubject without routes")
+
+	cfg.NatsRoutes = "error=rgw.s3.errors,write=rgw.s3.writes,all=rgw.s3.firehose,error=rgw.s3.errors"
+	cfg.Backpressure.HealthCheckUserAgents = "kube-probe"
+	require.NoError(t, initNatsRoutes(&cfg))
+	assert.Equal(t, []string{"rgw.s3.errors", "rgw.s3.firehose"}, eventSubjects(cfg, failed), "each subject once")
+
+	written := &S3OperationLog{User: "alice$proj", URI: "PUT /photos/cat.jpg HTTP/1.1", HTTPStatus: "200"}
+	assert.Equal(t, []string{"rgw.s3.writes", "rgw.s3.firehose"}, eventSubjects(cfg, written))
+
+	probe := &S3OperationLog{URI: "HEAD /photos HTTP/1.1", HTTPStatus: "200", UserAgent: "kube-probe/1.30"}
+	assert.Equal(t, []string{"rgw.s3.firehose"}, eventSubjects(cfg, probe))
+
+	cfg.NatsTenantSubjects = true
+	assert.Equal(t, []string{"rgw.s3.writes.proj", "rgw.s3.firehose.proj"}, eventSubjects(cfg, written))
+
+	cfg.NatsRoutes = "error=rgw.s3.errors"
+	require.NoError(t, initNatsRoutes(&cfg))
+	assert.Empty(t, eventSubjects(cfg, written), "entries of no route are not published")
+}
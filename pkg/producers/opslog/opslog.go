@@ -16,6 +16,11 @@ import (
 
 	json "github.com/goccy/go-json"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
 	"github.com/fsnotify/fsnotify"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
@@ -79,6 +84,28 @@ type S3OperationLog struct {
 	AccessKeyID        string         `json:"access_key_id"`
 	TempURL            bool           `json:"temp_url"`
 	KeystoneScope      *KeystoneScope `json:"keystone_scope,omitempty"`
+
+	// Project is the Keystone project of the tenant of an entry without
+	// keystone_scope, resolved by the Keystone URL of the producer
+	Project *KeystoneProject `json:"project,omitempty"`
+
+	// HTTPXHeaders are the headers RGW logs by rgw_log_http_headers, one
+	// object per header named like a CGI variable, e.g.
+	// [{"HTTP_X_AMZ_CONTENT_SHA256": "..."}]
+	HTTPXHeaders []map[string]string `json:"http_x_headers,omitempty"`
+}
+
+// Header returns the value of the HTTP header name if RGW logged it
+func (log *S3OperationLog) Header(name string) (string, bool) {
+	variable := "HTTP_" + strings.ReplaceAll(name, "-", "_")
+	for _, headers := range log.HTTPXHeaders {
+		for key, value := range headers {
+			if strings.EqualFold(key, variable) {
+				return value, true
+			}
+		}
+	}
+	return "", false
 }
 
 // CleanupBucketName extracts the actual bucket name, removing any tenant/user prefixes.
@@ -110,43 +137,135 @@ func StartFileOpsLogger(cfg OpsLogConfig) {
 		defer nc.Close()
 	}
 
+	// Count by bucket tags, before the Prometheus counters are registered
+	if err := initBucketTags(&cfg, nc); err != nil {
+		log.Error().Err(err).Msg("Error initializing bucket tags")
+		return
+	}
+
 	if cfg.Prometheus {
 		StartPrometheusServer(cfg.PrometheusPort, &cfg)
+		if cfg.RemoteWrite.URL != "" {
+			remotewrite.Start(cfg.RemoteWrite)
+		}
 	}
 
 	// Initialize audit trail
 	auditor := InitAuditor(context.Background(), cfg.AuditSink, nil)
 
+	// Initialize the Loki sink of the raw entries
+	loki, err := newLokiPusher(cfg.LokiSink, cfg.PodName)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing Loki sink")
+		return
+	}
+
+	// Classify the client addresses of the ip labels
+	cfg.MetricsConfig.IPClasses, err = NewIPClassifier(cfg.IPInternalCIDRs, cfg.IPCrossRegionCIDRs)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing IP classes")
+		return
+	}
+
+	// Recognize the requests of multisite replication
+	cfg.MetricsConfig.Replication, err = NewReplicationMatcher(cfg.ReplicationUsers, cfg.ReplicationCIDRs)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing replication matcher")
+		return
+	}
+
+	// Estimate the cost of the requests
+	cfg.MetricsConfig.Cost, err = NewCostModel(cfg.CostPrices)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing cost model")
+		return
+	}
+
+	// Resolve the Keystone project names of the tenants
+	if err := initProjectResolver(&cfg); err != nil {
+		log.Error().Err(err).Msg("Error initializing Keystone project resolution")
+		return
+	}
+
+	// Initialize the tracking of denied and anonymous requests
+	security := newSecurityTracker(cfg, nc)
+
+	// Initialize the capture of slow requests
+	slow, err := newSlowRequestCapture(cfg, nc)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing slow request capture")
+		return
+	}
+	if slow != nil {
+		slow.serve()
+	}
+
+	// Initialize the routes of the published entries
+	if err := initNatsRoutes(&cfg); err != nil {
+		log.Error().Err(err).Msg("Error initializing NATS routes")
+		return
+	}
+
+	// Initialize the backpressure queue of the published entries
+	events, err := newEventQueue(cfg.Backpressure)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing backpressure queue")
+		return
+	}
+
 	// Initialize metrics
 	metrics := NewMetrics(LatencyObs)
 	interval := time.Duration(cfg.PrometheusIntervalSeconds) * time.Second
-	ticker := time.NewTicker(interval)
+	ticker := newWindowTicker(interval)
 	defer ticker.Stop()
 
-	watcher := createLogWatcher(cfg)
-	if watcher == nil {
-		return
-	}
-	defer watcher.Close()
+	if cfg.JournaldUnit != "" {
+		// Read the entries from journald instead of the log file
+		if err := startJournaldReadLoop(cfg, nc, metrics, auditor, loki, security, slow, events); err != nil {
+			log.Error().Err(err).Str("unit", cfg.JournaldUnit).Msg("Error initializing journald reader")
+			return
+		}
+	} else {
+		watcher := createLogWatcher(cfg)
+		if watcher == nil {
+			return
+		}
+		defer watcher.Close()
 
-	startLogWatchLoop(cfg, nc, watcher, metrics, auditor)
+		startLogWatchLoop(cfg, nc, watcher, metrics, auditor, loki, security, slow, events)
 
-	if cfg.TruncateLogOnStart && cfg.LogFilePath != "" {
-		if err := rotateLogFile(cfg, watcher); err != nil {
-			log.Error().Err(err).Str("file", cfg.LogFilePath).Msg("Error rotating log file")
-		} else {
-			log.Info().Str("file", cfg.LogFilePath).Msg("Log file rotated successfully")
+		if cfg.TruncateLogOnStart && cfg.LogFilePath != "" {
+			if err := rotateLogFile(cfg, watcher); err != nil {
+				log.Error().Err(err).Str("file", cfg.LogFilePath).Msg("Error rotating log file")
+			} else {
+				log.Info().Str("file", cfg.LogFilePath).Msg("Log file rotated successfully")
+			}
 		}
 	}
 
-	for range ticker.C {
+	// The metrics count from the start, NATS gets the difference of every
+	// window
+	window := newMetricsWindow(cfg)
+	published := NewMetrics()
+	for end := range ticker.C {
+		_, span := tracer.Start(context.Background(), "opslog.flush")
 		if cfg.Prometheus {
 			PublishToPrometheus(metrics, cfg)
+			if cfg.MetricsConfig.TrackBucketConcurrency {
+				publishBucketConcurrency(end)
+			}
+			if cfg.MetricsConfig.TrackHealthChecks {
+				publishHealthChecks(end, &cfg.MetricsConfig)
+			}
 		}
 
+		current := metrics.Clone()
+		aggregated := window.close(SubtractMetrics(current, published).Aggregate(&cfg.MetricsConfig), end)
+		published = current
 		if cfg.UseNats {
-			publishMetricsToNATS(cfg, nc, metrics)
+			publishMetricsToNATS(cfg, nc, aggregated)
 		}
+		span.End()
 	}
 
 	// Keep the program running
@@ -154,7 +273,7 @@ func StartFileOpsLogger(cfg OpsLogConfig) {
 }
 
 func connectToNATS(cfg OpsLogConfig) *nats.Conn {
-	nc, err := nats.Connect(cfg.NatsURL)
+	nc, err := natsutil.Connect(cfg.NatsURL)
 	if err != nil {
 		log.Error().Err(err).Str("nats_url", cfg.NatsURL).Msg("Error connecting to NATS server")
 		return nil
@@ -163,6 +282,36 @@ func connectToNATS(cfg OpsLogConfig) *nats.Conn {
 	return nc
 }
 
+// initProjectResolver starts the resolution of the Keystone project names of
+// the tenants if configured
+func initProjectResolver(cfg *OpsLogConfig) error {
+	projects, err := NewProjectResolver(cfg.Keystone)
+	if err != nil || projects == nil {
+		return err
+	}
+	projects.Start(context.Background())
+	cfg.MetricsConfig.Projects = projects
+	return nil
+}
+
+// initBucketTags fills the bucket tags of the metrics from the bucket data
+// KV, if configured
+func initBucketTags(cfg *OpsLogConfig, nc *nats.Conn) error {
+	if cfg.BucketTagsKV == "" {
+		return nil
+	}
+	if nc == nil {
+		return fmt.Errorf("reading the bucket tags from %s requires NATS", cfg.BucketTagsKV)
+	}
+
+	tags := NewBucketTagCache(ParseBucketTags(cfg.BucketTags))
+	if err := tags.WatchKV(nc, cfg.BucketTagsKV); err != nil {
+		return err
+	}
+	cfg.MetricsConfig.BucketTags = tags
+	return nil
+}
+
 func createLogWatcher(cfg OpsLogConfig) *fsnotify.Watcher {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -181,11 +330,13 @@ func createLogWatcher(cfg OpsLogConfig) *fsnotify.Watcher {
 	return watcher
 }
 
-func startLogWatchLoop(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watcher, metrics *Metrics, auditor audittools.Auditor) {
+func startLogWatchLoop(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watcher, metrics *Metrics, auditor audittools.Auditor, loki *lokiPusher, security *securityTracker, slow *slowRequestCapture, events *eventQueue) {
 	// var lastModTime time.Time
 	var lastOffset int64 = 0
 
-	go func() {
+	// lastOffset outlives a restart of the tailer after a panic, so the
+	// entries are not processed twice
+	go telemetry.RunWorker(context.Background(), "ops-log.tailer", func(context.Context) {
 		for {
 			select {
 			case event, ok := <-watcher.Events:
@@ -197,7 +348,7 @@ func startLogWatchLoop(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watche
 				if event.Op&fsnotify.Write == fsnotify.Write {
 					time.Sleep(100 * time.Millisecond)
 
-					offset, err := processLogEntries(cfg, nc, watcher, metrics, auditor, lastOffset)
+					offset, err := processLogEntries(cfg, nc, watcher, metrics, auditor, loki, security, slow, events, lastOffset)
 					if err != nil {
 						log.Error().Err(err).Msg("Failed to process log entries")
 						continue
@@ -212,16 +363,11 @@ func startLogWatchLoop(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watche
 				log.Error().Err(err).Msg("File watcher encountered an error")
 			}
 		}
-	}()
+	})
 }
 
-func publishMetricsToNATS(cfg OpsLogConfig, nc *nats.Conn, metrics *Metrics) {
-	jsonData, err := metrics.ToJSON(&cfg.MetricsConfig)
-	if err != nil || len(jsonData) == 0 {
-		log.Error().Err(err).Msg("Skipping NATS publish: JSON encoding failed or empty!")
-		return
-	}
-	err = PublishToNATS(nc, jsonData, fmt.Sprintf("%s.metrics", cfg.NatsMetricsSubject))
+func publishMetricsToNATS(cfg OpsLogConfig, nc *nats.Conn, metrics AggregatedMetrics) {
+	err := schema.Publish(nc, fmt.Sprintf("%s.metrics", cfg.NatsMetricsSubject), schema.OpsMetrics, metrics)
 	if err != nil {
 		log.Error().Err(err).Msg("Error sending metrics to NATS")
 	} else {
@@ -229,7 +375,15 @@ func publishMetricsToNATS(cfg OpsLogConfig, nc *nats.Conn, metrics *Metrics) {
 	}
 }
 
-func processLogEntries(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watcher, metrics *Metrics, auditor audittools.Auditor, lastOffset int64) (int64, error) {
+func processLogEntries(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watcher, metrics *Metrics, auditor audittools.Auditor, loki *lokiPusher, security *securityTracker, slow *slowRequestCapture, events *eventQueue, lastOffset int64) (newOffset int64, err error) {
+	// One span per batch of new entries, with the time spent in each stage
+	_, span := tracer.Start(context.Background(), "opslog.process")
+	var timings pipelineTimings
+	defer func() {
+		timings.record(span, newOffset-lastOffset)
+		tracing.End(span, err)
+	}()
+
 	file, err := os.Open(cfg.LogFilePath)
 	if err != nil {
 		return lastOffset, fmt.Errorf("error opening log file: %w", err)
@@ -263,7 +417,33 @@ func processLogEntries(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watche
 	// `{...}{...}`. decodeOpsLogEntries yields one complete object at a time and
 	// reports the byte offset just past the last COMPLETE object, so a partial
 	// tail write is neither lost nor double-counted.
-	consumed := decodeOpsLogEntries(reader, func(raw json.RawMessage, logEntry *S3OperationLog) {
+	decodeStart := time.Now()
+	consumed := decodeOpsLogEntries(reader, newEntryHandler(cfg, nc, metrics, auditor, loki, security, slow, events, &timings))
+	timings.parse = time.Since(decodeStart) - timings.handle
+
+	newOffset = lastOffset + consumed
+
+	// Rotate log file if needed
+	rotateLogIfNeeded(cfg, watcher)
+	return newOffset, nil
+}
+
+// newEntryHandler returns the processing of the decoded ops log entries read
+// from the log file or journald: security tracking, metrics, audit, stdout,
+// NATS and Loki. The time spent in each stage is added to timings.
+func newEntryHandler(cfg OpsLogConfig, nc *nats.Conn, metrics *Metrics, auditor audittools.Auditor, loki *lokiPusher, security *securityTracker, slow *slowRequestCapture, events *eventQueue, timings *pipelineTimings) func(raw json.RawMessage, logEntry *S3OperationLog) {
+	return func(raw json.RawMessage, logEntry *S3OperationLog) {
+		handleStart := time.Now()
+		defer func() { timings.handle += time.Since(handleStart) }()
+		timings.entries++
+
+		// Track denied and anonymous requests, before anonymous ones are skipped
+		if security != nil {
+			stageStart := time.Now()
+			security.Observe(logEntry)
+			timings.aggregate += time.Since(stageStart)
+		}
+
 		// Ignore anonymous requests if configured
 		if cfg.IgnoreAnonymousRequests && logEntry.User == "anonymous" {
 			log.Trace().Str("user", logEntry.User).Msg("Skipping anonymous request")
@@ -273,10 +453,21 @@ func processLogEntries(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watche
 		// Normalize bucket name before processing
 		logEntry.CleanupBucketName()
 
+		// Capture the entry if it is slow
+		if slow != nil {
+			slow.Observe(raw, logEntry)
+		}
+
+		// Name the Keystone project of the tenant
+		cfg.MetricsConfig.Projects.Enrich(logEntry)
+
 		// Update metrics with the log entry
+		stageStart := time.Now()
 		metrics.Update(*logEntry, &cfg.MetricsConfig)
+		timings.aggregate += time.Since(stageStart)
 
 		// Publish audit event if auditor is configured
+		stageStart = time.Now()
 		if auditor != nil && cfg.AuditSink.Enabled {
 			// Audit gates, most critical first. Each drop is counted (not
 			// silent); only the audit publish is skipped — NATS/stdout still
@@ -326,25 +517,38 @@ func processLogEntries(cfg OpsLogConfig, nc *nats.Conn, watcher *fsnotify.Watche
 				}
 			}
 		}
-
-		// Print to stdout if enabled
-		if cfg.LogToStdout {
-			printOpsLogLine(raw, cfg.LogPrettyPrint)
-		}
-
-		// Publish raw log entry to NATS
-		if cfg.UseNats {
-			if err := PublishToNATS(nc, logEntry, cfg.NatsSubject); err != nil {
-				log.Error().Err(err).Msg("Error publishing log entry to NATS")
+		timings.audit += time.Since(stageStart)
+
+		// Print to stdout if enabled and publish raw log entry to NATS,
+		// through the backpressure queue if configured
+		stageStart = time.Now()
+		if cfg.LogToStdout || cfg.UseNats {
+			entry := logEntry
+			if events != nil {
+				// The decoder reuses logEntry for the next entry
+				queued := *logEntry
+				entry = &queued
 			}
+			events.Submit(entry, func() {
+				if cfg.LogToStdout {
+					printOpsLogLine(raw, cfg.LogPrettyPrint)
+				}
+				if cfg.UseNats {
+					for _, subject := range eventSubjects(cfg, entry) {
+						if err := PublishToNATS(nc, entry, subject); err != nil {
+							log.Error().Err(err).Str("subject", subject).Msg("Error publishing log entry to NATS")
+						}
+					}
+				}
+			})
 		}
-	})
 
-	newOffset := lastOffset + consumed
-
-	// Rotate log file if needed
-	rotateLogIfNeeded(cfg, watcher)
-	return newOffset, nil
+		// Queue the raw log entry for Loki
+		if loki != nil {
+			loki.Push(raw, logEntry)
+		}
+		timings.publish += time.Since(stageStart)
+	}
 }
 
 func StartSocketOpsLogger(cfg OpsLogConfig) {
@@ -353,7 +557,7 @@ func StartSocketOpsLogger(cfg OpsLogConfig) {
 
 	// Configure and connect to NATS if enabled
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Error().Err(err).Str("nats_url", cfg.NatsURL).Msg("Error connecting to NATS server")
 			return
@@ -362,9 +566,66 @@ func StartSocketOpsLogger(cfg OpsLogConfig) {
 		log.Info().Str("nats_url", cfg.NatsURL).Msg("Connected to NATS server")
 	}
 
+	if err := initBucketTags(&cfg, nc); err != nil {
+		log.Error().Err(err).Msg("Error initializing bucket tags")
+		return
+	}
+
+	loki, err := newLokiPusher(cfg.LokiSink, cfg.PodName)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing Loki sink")
+		return
+	}
+
+	cfg.MetricsConfig.IPClasses, err = NewIPClassifier(cfg.IPInternalCIDRs, cfg.IPCrossRegionCIDRs)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing IP classes")
+		return
+	}
+
+	cfg.MetricsConfig.Replication, err = NewReplicationMatcher(cfg.ReplicationUsers, cfg.ReplicationCIDRs)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing replication matcher")
+		return
+	}
+
+	cfg.MetricsConfig.Cost, err = NewCostModel(cfg.CostPrices)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing cost model")
+		return
+	}
+
+	if err := initProjectResolver(&cfg); err != nil {
+		log.Error().Err(err).Msg("Error initializing Keystone project resolution")
+		return
+	}
+
+	security := newSecurityTracker(cfg, nc)
+
+	slow, err := newSlowRequestCapture(cfg, nc)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing slow request capture")
+		return
+	}
+	if slow != nil {
+		slow.serve()
+	}
+
+	if err := initNatsRoutes(&cfg); err != nil {
+		log.Error().Err(err).Msg("Error initializing NATS routes")
+		return
+	}
+
+	events, err := newEventQueue(cfg.Backpressure)
+	if err != nil {
+		log.Error().Err(err).Msg("Error initializing backpressure queue")
+		return
+	}
+
 	metrics := NewMetrics(latencyObs)
-	ticker := time.NewTicker(1 * time.Minute) // Set up a ticker to trigger every 1 minute
+	ticker := newWindowTicker(time.Minute) // Ticks at every full minute
 	defer ticker.Stop()
+	window := newMetricsWindow(cfg)
 
 	// Remove any existing socket file to avoid "address already in use" errors
 	err = os.Remove(cfg.SocketPath)
@@ -389,22 +650,29 @@ func StartSocketOpsLogger(cfg OpsLogConfig) {
 	log.Info().Str("socket_path", cfg.SocketPath).Msg("Listening on Unix domain socket")
 
 	// Goroutine to handle incoming connections
-	go func() {
+	go telemetry.RunWorker(context.Background(), "ops-log.accept", func(context.Context) {
 		for {
 			conn, err := listener.Accept()
 			if err != nil {
 				log.Error().Err(err).Msg("Error accepting connection on Unix domain socket")
 				continue
 			}
-			go handleConnection(cfg, conn, nc, metrics) // Handle each connection in a separate goroutine
+			go handleConnection(cfg, conn, nc, metrics, loki, security, slow, events) // Handle each connection in a separate goroutine
 		}
-	}()
+	})
 
 	// Use a range loop over ticker.C to handle periodic metric reporting
-	for range ticker.C {
+	for end := range ticker.C {
 		// Every minute, send the aggregated metrics to NATS and reset
+		if cfg.Prometheus && cfg.MetricsConfig.TrackBucketConcurrency {
+			publishBucketConcurrency(end)
+		}
+		if cfg.Prometheus && cfg.MetricsConfig.TrackHealthChecks {
+			publishHealthChecks(end, &cfg.MetricsConfig)
+		}
+		aggregated := window.close(metrics.Aggregate(&cfg.MetricsConfig), end)
 		if cfg.UseNats {
-			err := PublishToNATS(nc, metrics, cfg.NatsMetricsSubject)
+			err := schema.Publish(nc, cfg.NatsMetricsSubject, schema.OpsMetrics, aggregated)
 			if err != nil {
 				log.Error().Err(err).Msg("Error sending metrics to NATS")
 			} else {
@@ -417,7 +685,8 @@ func StartSocketOpsLogger(cfg OpsLogConfig) {
 	}
 }
 
-func handleConnection(cfg OpsLogConfig, conn net.Conn, nc *nats.Conn, metrics *Metrics) {
+func handleConnection(cfg OpsLogConfig, conn net.Conn, nc *nats.Conn, metrics *Metrics, loki *lokiPusher, security *securityTracker, slow *slowRequestCapture, events *eventQueue) {
+	defer telemetry.RecoverPanic("ops-log.connection")
 	defer func() {
 		err := conn.Close()
 		if err != nil {
@@ -434,54 +703,73 @@ func handleConnection(cfg OpsLogConfig, conn net.Conn, nc *nats.Conn, metrics *M
 			continue
 		}
 
-		// Send logEntry to NATS if configured
-		if cfg.UseNats {
-			err := PublishToNATS(nc, logEntry, cfg.NatsSubject)
-			if err != nil {
-				log.Error().Err(err).Msg("Error sending op event to NATS")
-			} else {
-				log.Info().Msg("Op event sent to NATS successfully")
+		// The fields of the entry label it for Loki, name its tenant subject,
+		// tell denied and anonymous requests, name its Keystone project and
+		// classify it under backpressure and for the NATS routes
+		var entry S3OperationLog
+		projects := cfg.MetricsConfig.Projects
+		if loki != nil || cfg.NatsTenantSubjects || cfg.natsRouter != nil || security != nil || slow != nil || events != nil || projects != nil {
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				log.Error().Err(err).Msg("Error unmarshalling log entry fields")
+				continue
 			}
-		}
-
-		// Conditional logging to stdout if enabled
-		if cfg.LogToStdout {
-			var b []byte
-			var err error
-			if cfg.LogPrettyPrint {
-				b, err = json.MarshalIndent(logEntry, "", "  ")
-			} else {
-				b, err = json.Marshal(logEntry)
+			if security != nil {
+				security.Observe(&entry)
 			}
-			if err != nil {
-				log.Error().Err(err).Msg("Error marshalling log entry for stdout")
-				continue
+			entry.CleanupBucketName()
+			if slow != nil {
+				slow.Observe(scanner.Bytes(), &entry)
+			}
+			projects.Enrich(&entry)
+			if fields, ok := logEntry.(map[string]any); ok && entry.Project != nil {
+				fields["project"] = entry.Project
 			}
-			fmt.Println(string(b)) // Print log entry to stdout
 		}
+		subjects := eventSubjects(cfg, &entry)
 
-		// Publish the individual log entry to NATS or print locally
-		if cfg.UseNats {
-			logEntryBytes, err := json.Marshal(logEntry)
-			if err != nil {
-				log.Error().Err(err).Msg("Error marshalling log entry for NATS")
-				continue
+		// Queue the raw log entry for Loki
+		if loki != nil {
+			loki.Push(scanner.Bytes(), &entry)
+		}
+
+		// Publish the entry and print it, through the backpressure queue if
+		// configured
+		events.Submit(&entry, func() {
+			// Conditional logging to stdout if enabled
+			if cfg.LogToStdout {
+				var b []byte
+				var err error
+				if cfg.LogPrettyPrint {
+					b, err = json.MarshalIndent(logEntry, "", "  ")
+				} else {
+					b, err = json.Marshal(logEntry)
+				}
+				if err != nil {
+					log.Error().Err(err).Msg("Error marshalling log entry for stdout")
+					return
+				}
+				fmt.Println(string(b)) // Print log entry to stdout
 			}
 
-			err = nc.Publish(cfg.NatsSubject, logEntryBytes)
-			if err != nil {
-				log.Error().Err(err).Msg("Error publishing log entry to NATS")
+			// Publish the individual log entry to NATS or print locally
+			if cfg.UseNats {
+				for _, subject := range subjects {
+					err := schema.Publish(nc, subject, schema.OpsEvent, logEntry)
+					if err != nil {
+						log.Error().Err(err).Str("subject", subject).Msg("Error publishing log entry to NATS")
+					} else {
+						log.Info().Msg("Log entry published to NATS successfully")
+					}
+				}
 			} else {
-				log.Info().Msg("Log entry published to NATS successfully")
-			}
-		} else {
-			logEntryBytes, err := json.MarshalIndent(logEntry, "", "  ")
-			if err != nil {
-				log.Error().Err(err).Msg("Error marshalling log entry for local logging")
-				continue
+				logEntryBytes, err := json.MarshalIndent(logEntry, "", "  ")
+				if err != nil {
+					log.Error().Err(err).Msg("Error marshalling log entry for local logging")
+					return
+				}
+				log.Trace().Msg(string(logEntryBytes))
 			}
-			log.Trace().Msg(string(logEntryBytes))
-		}
+		})
 	}
 
 	if err := scanner.Err(); err != nil {
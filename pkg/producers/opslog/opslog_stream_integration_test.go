@@ -17,6 +17,10 @@ import (
 	json "github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // entryJSON builds a compact ops-log entry carrying a unique trans_id.
@@ -211,7 +215,29 @@ func TestProcessLogEntries_RealWrapper(t *testing.T) {
 		MetricsConfig:  MetricsConfig{TrackRequestsPerBucket: true},
 	}
 
-	newOffset, err := processLogEntries(cfg, nil, nil, NewMetrics(), nil, 0)
+	newOffset, err := processLogEntries(cfg, nil, nil, NewMetrics(), nil, nil, nil, nil, nil, 0)
 	require.NoError(t, err)
 	assert.Equal(t, int64(len(content)), newOffset, "whole concatenated file consumed")
 }
+
+// TestProcessLogEntries_Span checks that a batch is traced with its entries
+// and bytes.
+func TestProcessLogEntries_Span(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	path := filepath.Join(t.TempDir(), "ops.log")
+	content := entryJSON("s1") + entryJSON("s2")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	_, err := processLogEntries(OpsLogConfig{LogFilePath: path}, nil, nil, NewMetrics(), nil, nil, nil, nil, nil, 0)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "opslog.process", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("opslog.entries", 2))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int64("opslog.bytes", int64(len(content))))
+}
@@ -5,10 +5,7 @@
 package opslog
 
 import (
-	"fmt"
-	"net/http"
-
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/rs/zerolog/log"
 )
 
@@ -48,6 +45,9 @@ func initPrometheusSettings(cfg *OpsLogConfig) {
 	// Register IP-based metrics
 	registerIPMetrics(metricsConfig)
 
+	// Register the counters by bucket owner and tags
+	registerBucketTagMetrics(metricsConfig)
+
 	// Register latency metrics and set up LatencyObs function
 	registerLatencyMetrics(metricsConfig)
 
@@ -56,9 +56,53 @@ func initPrometheusSettings(cfg *OpsLogConfig) {
 		registerSLIMetrics()
 	}
 
+	// Register the security metrics of denied and anonymous requests
+	if metricsConfig.TrackSecurity {
+		registerSecurityMetrics()
+	}
+
+	// Register the estimated requests in flight of the buckets
+	if metricsConfig.TrackBucketConcurrency {
+		registerConcurrencyMetrics()
+	}
+
+	// Register the requests by authentication type and signature version
+	if metricsConfig.TrackAuthMethods {
+		registerAuthMethodMetrics()
+	}
+
+	// Register the health-check-like traffic
+	if metricsConfig.TrackHealthChecks {
+		registerHealthCheckMetrics()
+	}
+
+	// Register the requests and bytes of multisite replication
+	if cfg.ReplicationUsers != "" || cfg.ReplicationCIDRs != "" {
+		registerReplicationMetrics()
+	}
+
+	// Register the project names of the tenants
+	if cfg.Keystone.URL != "" {
+		registerKeystoneMetrics()
+	}
+
+	// Register the cost estimates of the requests
+	if cfg.CostPrices != "" {
+		registerCostMetrics()
+	}
+
 	// Register audit drop counters
 	registerAuditMetrics()
 
+	// Register Loki sink counters
+	registerLokiMetrics()
+
+	// Register backpressure counters
+	registerBackpressureMetrics()
+
+	// Register slow request counters
+	registerSlowRequestMetrics()
+
 	// Set up the global LatencyObs function
 	LatencyObs = latencyObs
 }
@@ -91,6 +135,8 @@ func PublishToPrometheus(totalMetrics *Metrics, cfg OpsLogConfig) {
 
 	publishErrorCounters(diffMetrics, cfg)
 
+	publishBucketTagCounters(diffMetrics, cfg)
+
 	publishIPGauges(currentMetrics, cfg)
 
 	log.Info().Msg("Updated Prometheus metrics for users and buckets")
@@ -102,12 +148,5 @@ func StartPrometheusServer(port int, cfg *OpsLogConfig) {
 	initPrometheusSettings(cfg)
 
 	// Start the Prometheus HTTP server
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
-		}
-	}()
+	telemetry.StartMetricsServer(port)
 }
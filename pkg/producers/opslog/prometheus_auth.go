@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var requestsByAuthMethod = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "radosgw_requests_by_auth_method_total",
+		Help: "Requests by user, authentication type and AWS signature version",
+	},
+	[]string{"tenant", "user", "auth_type", "signature"},
+)
+
+func registerAuthMethodMetrics() {
+	prometheus.MustRegister(requestsByAuthMethod)
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// eventsDropped counts the entries the backpressure queue dropped, by class:
+// health_check, read, write or error. Like the audit counters it is always
+// defined and only exposed once registered.
+var eventsDropped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "prysm_ops_log_events_dropped_total",
+		Help: "Ops log entries dropped by the backpressure queue before publishing, by class",
+	},
+	[]string{"class"},
+)
+
+// eventQueueLength is the number of entries waiting in the backpressure queue
+var eventQueueLength = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "prysm_ops_log_event_queue_length",
+		Help: "Ops log entries waiting in the backpressure queue to be published",
+	},
+)
+
+func registerBackpressureMetrics() {
+	prometheus.MustRegister(eventsDropped, eventQueueLength)
+}
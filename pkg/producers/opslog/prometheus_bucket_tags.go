@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The label names of the bucket tag counters depend on the selected tags, so
+// they are created on registration
+var (
+	requestsByBucketTagsCounter      *prometheus.CounterVec
+	bytesSentByBucketTagsCounter     *prometheus.CounterVec
+	bytesReceivedByBucketTagsCounter *prometheus.CounterVec
+)
+
+func registerBucketTagMetrics(metricsConfig *MetricsConfig) {
+	if metricsConfig.BucketTags == nil {
+		return
+	}
+
+	labels := []string{"pod", "owner"}
+	for _, tag := range metricsConfig.BucketTags.Tags() {
+		labels = append(labels, TagLabel(tag))
+	}
+
+	requestsByBucketTagsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_requests_by_bucket_tags",
+			Help: "Total requests aggregated per bucket owner and bucket tags",
+		},
+		labels,
+	)
+	bytesSentByBucketTagsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_bytes_sent_by_bucket_tags",
+			Help: "Total bytes sent aggregated per bucket owner and bucket tags",
+		},
+		labels,
+	)
+	bytesReceivedByBucketTagsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_bytes_received_by_bucket_tags",
+			Help: "Total bytes received aggregated per bucket owner and bucket tags",
+		},
+		labels,
+	)
+	prometheus.MustRegister(requestsByBucketTagsCounter, bytesSentByBucketTagsCounter, bytesReceivedByBucketTagsCounter)
+}
+
+func publishBucketTagCounters(diffMetrics *Metrics, cfg OpsLogConfig) {
+	if cfg.MetricsConfig.BucketTags == nil || requestsByBucketTagsCounter == nil {
+		return
+	}
+
+	labelCount := 2 + len(cfg.MetricsConfig.BucketTags.Tags())
+	publish := func(m *sync.Map, counter *prometheus.CounterVec) {
+		m.Range(func(key, value any) bool {
+			// key is "owner|tag 1|...|tag n", the label values after the pod
+			values := append([]string{cfg.PodName}, strings.Split(key.(string), "|")...)
+			if len(values) != labelCount {
+				return true
+			}
+			if count := float64(value.(*atomic.Uint64).Load()); count > 0 {
+				counter.WithLabelValues(values...).Add(count)
+			}
+			return true
+		})
+	}
+	publish(&diffMetrics.RequestsByBucketTags, requestsByBucketTagsCounter)
+	publish(&diffMetrics.BytesSentByBucketTags, bytesSentByBucketTagsCounter)
+	publish(&diffMetrics.BytesReceivedByBucketTags, bytesReceivedByBucketTagsCounter)
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	bucketRequestsInFlightMax = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_bucket_requests_in_flight_max",
+			Help: "Estimated requests in flight of the bucket in the busiest second of the last interval",
+		},
+		[]string{"tenant", "bucket"},
+	)
+
+	bucketRequestsInFlightAvg = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_bucket_requests_in_flight_avg",
+			Help: "Estimated requests in flight of the bucket on average over the last interval",
+		},
+		[]string{"tenant", "bucket"},
+	)
+)
+
+func registerConcurrencyMetrics() {
+	prometheus.MustRegister(bucketRequestsInFlightMax)
+	prometheus.MustRegister(bucketRequestsInFlightAvg)
+}
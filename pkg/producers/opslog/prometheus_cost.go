@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var estimatedCost = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "radosgw_estimated_cost_total",
+		Help: "Estimated cost of the requests by tenant, bucket and component (egress or the API category of the requests), in the currency of the prices",
+	},
+	[]string{"tenant", "bucket", "component"},
+)
+
+func registerCostMetrics() {
+	prometheus.MustRegister(estimatedCost)
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	healthCheckPatternRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_health_check_pattern_requests_per_second",
+			Help: "Requests per second of the top health-check-like patterns, the same operation on the same key, over the last interval",
+		},
+		[]string{"tenant", "bucket", "object", "operation"},
+	)
+
+	healthCheckPatternClients = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_health_check_pattern_clients",
+			Help: "Client addresses sending the top health-check-like patterns over the last interval",
+		},
+		[]string{"tenant", "bucket", "object", "operation"},
+	)
+
+	healthCheckTenantRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_health_check_requests_per_second",
+			Help: "Requests per second of all health-check-like patterns of the tenant over the last interval",
+		},
+		[]string{"tenant"},
+	)
+
+	healthCheckTenantPatterns = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_health_check_patterns",
+			Help: "Health-check-like patterns of the tenant detected over the last interval",
+		},
+		[]string{"tenant"},
+	)
+
+	healthCheckRequestsNotCounted = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "radosgw_health_check_requests_not_counted_total",
+			Help: "Small reads not counted by the health check detection, the patterns of their window exceeded the limit",
+		},
+	)
+)
+
+func registerHealthCheckMetrics() {
+	prometheus.MustRegister(healthCheckPatternRequests)
+	prometheus.MustRegister(healthCheckPatternClients)
+	prometheus.MustRegister(healthCheckTenantRequests)
+	prometheus.MustRegister(healthCheckTenantPatterns)
+	prometheus.MustRegister(healthCheckRequestsNotCounted)
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	keystoneProjectInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "radosgw_keystone_project_info",
+			Help: "Keystone project name and domain of a tenant, always 1, to join the tenant metrics with",
+		},
+		[]string{"tenant", "project", "domain_id"},
+	)
+	keystoneLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_keystone_lookups_total",
+			Help: "Lookups of Keystone projects by result: found, not_found or error",
+		},
+		[]string{"result"},
+	)
+)
+
+func registerKeystoneMetrics() {
+	prometheus.MustRegister(keystoneProjectInfo, keystoneLookups)
+}
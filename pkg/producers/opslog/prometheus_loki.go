@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// lokiEntries counts the raw entries of the Loki sink by result: pushed,
+// push_failed, or queue_full when Loki could not keep up. Like the audit
+// counters it is always defined and only exposed once registered.
+var lokiEntries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "prysm_loki_entries_total",
+		Help: "Raw ops log entries of the Loki sink, by result",
+	},
+	[]string{"result"},
+)
+
+func registerLokiMetrics() {
+	prometheus.MustRegister(lokiEntries)
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	replicationRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_replication_requests_total",
+			Help: "Multisite replication requests by sync user, method and HTTP status, not counted in the request metrics",
+		},
+		[]string{"tenant", "user", "method", "http_status"},
+	)
+	replicationBytesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_replication_bytes_sent_total",
+			Help: "Bytes sent to multisite replication by sync user, not counted in the byte metrics",
+		},
+		[]string{"tenant", "user"},
+	)
+	replicationBytesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_replication_bytes_received_total",
+			Help: "Bytes received from multisite replication by sync user, not counted in the byte metrics",
+		},
+		[]string{"tenant", "user"},
+	)
+)
+
+func registerReplicationMetrics() {
+	prometheus.MustRegister(replicationRequests, replicationBytesSent, replicationBytesReceived)
+}
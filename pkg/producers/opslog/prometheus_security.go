@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	securityDeniedByUser = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_security_denied_requests_by_user_total",
+			Help: "Requests denied with 401 or 403, by user and RGW error code",
+		},
+		[]string{"tenant", "user", "error_code"},
+	)
+
+	securityDeniedByIP = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_security_denied_requests_by_ip_total",
+			Help: "Requests denied with 401 or 403, by client IP",
+		},
+		[]string{"ip"},
+	)
+
+	securityDeniedByBucket = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_security_denied_requests_by_bucket_total",
+			Help: "Requests denied with 401 or 403, by bucket",
+		},
+		[]string{"tenant", "bucket"},
+	)
+
+	securityAnonymousRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "radosgw_security_anonymous_requests_total",
+			Help: "Requests without credentials, including those ignored by the other metrics",
+		},
+		[]string{"tenant", "bucket", "method", "status_class"},
+	)
+)
+
+func registerSecurityMetrics() {
+	prometheus.MustRegister(securityDeniedByUser)
+	prometheus.MustRegister(securityDeniedByIP)
+	prometheus.MustRegister(securityDeniedByBucket)
+	prometheus.MustRegister(securityAnonymousRequests)
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// slowRequestsCaptured counts the requests captured above their latency
+// threshold. Like the audit counters it is always defined and only exposed
+// once registered.
+var slowRequestsCaptured = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "prysm_ops_log_slow_requests_captured_total",
+		Help: "Ops log entries captured for a latency above the threshold of their bucket",
+	},
+)
+
+func registerSlowRequestMetrics() {
+	prometheus.MustRegister(slowRequestsCaptured)
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ReplicationMatcher recognizes the requests of multisite replication, sent
+// by the sync users of the zones or from the endpoints of the peer zones, so
+// they are counted apart from the requests of the tenants
+type ReplicationMatcher struct {
+	users    map[string]bool
+	prefixes []netip.Prefix
+}
+
+// NewReplicationMatcher parses the comma-separated replication users, e.g.
+// sync-user or sync-user$tenant, and the CIDRs of the peer zone endpoints. It
+// returns nil if both are empty, which matches no request.
+func NewReplicationMatcher(users, cidrs string) (*ReplicationMatcher, error) {
+	prefixes, err := parseCIDRs(cidrs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replication CIDRs: %w", err)
+	}
+	matcher := &ReplicationMatcher{users: map[string]bool{}, prefixes: prefixes}
+	for _, user := range strings.Split(users, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			matcher.users[user] = true
+		}
+	}
+	if len(matcher.users) == 0 && len(matcher.prefixes) == 0 {
+		return nil, nil
+	}
+	return matcher, nil
+}
+
+// Matches reports whether the entry is a replication request, sent by a
+// replication user or from a replication CIDR. A nil matcher matches none.
+func (m *ReplicationMatcher) Matches(entry *S3OperationLog) bool {
+	if m == nil {
+		return false
+	}
+	if m.users[entry.User] {
+		return true
+	}
+	if len(m.prefixes) == 0 {
+		return false
+	}
+	ip, ok := parseClientAddr(entry.RemoteAddr)
+	return ok && containsAddr(m.prefixes, ip)
+}
+
+func observeReplication(logEntry *S3OperationLog, user, tenant, method string) {
+	replicationRequests.WithLabelValues(tenant, user, method, logEntry.HTTPStatus).Inc()
+	replicationBytesSent.WithLabelValues(tenant, user).Add(float64(logEntry.BytesSent))
+	replicationBytesReceived.WithLabelValues(tenant, user).Add(float64(logEntry.BytesReceived))
+}
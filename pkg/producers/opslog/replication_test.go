@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicationMatcher(t *testing.T) {
+	matcher, err := NewReplicationMatcher("sync-user, zone-b$system", "10.20.0.0/16")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		entry    S3OperationLog
+		expected bool
+	}{
+		{"sync user", S3OperationLog{User: "sync-user", RemoteAddr: "203.0.113.7"}, true},
+		{"sync user of a tenant", S3OperationLog{User: "zone-b$system", RemoteAddr: "203.0.113.7"}, true},
+		{"peer zone endpoint", S3OperationLog{User: "alice$proj", RemoteAddr: "10.20.1.2:41234"}, true},
+		{"tenant", S3OperationLog{User: "alice$proj", RemoteAddr: "203.0.113.7"}, false},
+		{"user of another tenant", S3OperationLog{User: "sync-user$proj", RemoteAddr: "203.0.113.7"}, false},
+		{"no address", S3OperationLog{User: "alice$proj"}, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, matcher.Matches(&tc.entry), tc.name)
+	}
+}
+
+func TestNewReplicationMatcher(t *testing.T) {
+	matcher, err := NewReplicationMatcher(" ", "")
+	require.NoError(t, err)
+	assert.Nil(t, matcher, "no users or CIDRs match no request")
+	assert.False(t, matcher.Matches(&S3OperationLog{User: "sync-user"}))
+
+	_, err = NewReplicationMatcher("", "10.20.0.1")
+	assert.ErrorContains(t, err, "replication CIDRs")
+}
+
+func TestMetricsUpdate_Replication(t *testing.T) {
+	matcher, err := NewReplicationMatcher("sync-user", "")
+	require.NoError(t, err)
+	config := &MetricsConfig{TrackRequestsPerTenant: true, Replication: matcher}
+
+	m := NewMetrics()
+	m.Update(S3OperationLog{User: "sync-user", URI: "GET /bucket/obj HTTP/1.1", HTTPStatus: "200", BytesSent: 4096}, config)
+	m.Update(S3OperationLog{User: "alice$proj", URI: "PUT /bucket/obj HTTP/1.1", HTTPStatus: "200", BytesReceived: 1024}, config)
+
+	assert.Equal(t, uint64(1), m.TotalRequests.Load(), "replication is not counted as tenant traffic")
+	assert.Equal(t, uint64(0), m.BytesSent.Load())
+	_, ok := m.RequestsByTenant.Load("none|GET|200")
+	assert.False(t, ok)
+	v, ok := m.RequestsByTenant.Load("proj|PUT|200")
+	require.True(t, ok)
+	assert.Equal(t, uint64(1), v.(*atomic.Uint64).Load())
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(replicationRequests.WithLabelValues("none", "sync-user", "GET", "200")))
+	assert.Equal(t, float64(4096), testutil.ToFloat64(replicationBytesSent.WithLabelValues("none", "sync-user")))
+}
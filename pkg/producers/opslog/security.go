@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// Types of security events
+const (
+	SecurityAuthFailure     = "auth_failure"     // A request of a user denied with 401 or 403
+	SecurityAnonymousAccess = "anonymous_access" // A request without credentials, allowed or not
+)
+
+// SecurityEvent is a denied or anonymous request, published with
+// schema.OpsSecurityEvent
+type SecurityEvent struct {
+	Type       string `json:"type"` // auth_failure or anonymous_access
+	Time       string `json:"time"`
+	Pod        string `json:"pod,omitempty"`
+	User       string `json:"user"`
+	Tenant     string `json:"tenant"`
+	Bucket     string `json:"bucket,omitempty"`
+	Object     string `json:"object,omitempty"`
+	RemoteAddr string `json:"remote_addr"`
+	Method     string `json:"method"`
+	Operation  string `json:"operation"`
+	HTTPStatus string `json:"http_status"`
+	ErrorCode  string `json:"error_code,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+}
+
+// securityTracker counts the denied and anonymous requests and publishes them
+// as security events. It sees every entry, also the anonymous ones that
+// --ignore-anonymous-requests keeps out of the other metrics.
+type securityTracker struct {
+	metrics   bool          // Update the Prometheus metrics
+	ipClasses *IPClassifier // Classifies the ip label of the metrics when set
+	nc        *nats.Conn    // Publish the events when set
+	subject   string
+	pod       string
+}
+
+// newSecurityTracker returns the tracker of cfg, nil if neither the metrics
+// nor the events are enabled
+func newSecurityTracker(cfg OpsLogConfig, nc *nats.Conn) *securityTracker {
+	events := cfg.UseNats && cfg.NatsSecurityEvents
+	if !cfg.MetricsConfig.TrackSecurity && !events {
+		return nil
+	}
+
+	t := &securityTracker{
+		metrics:   cfg.MetricsConfig.TrackSecurity,
+		ipClasses: cfg.MetricsConfig.IPClasses,
+		subject:   cfg.NatsSecuritySubject,
+		pod:       cfg.PodName,
+	}
+	if events {
+		t.nc = nc
+	}
+	return t
+}
+
+// Observe records the entry if it is denied or anonymous. The bucket of the
+// entry must not be cleaned up yet, anonymous requests name their tenant only
+// in it.
+func (t *securityTracker) Observe(entry *S3OperationLog) {
+	event, ok := newSecurityEvent(entry)
+	if !ok {
+		return
+	}
+	event.Pod = t.pod
+
+	if t.metrics {
+		observeSecurityMetrics(event, t.ipClasses.Classify(event.RemoteAddr))
+	}
+	if t.nc != nil {
+		if err := schema.Publish(t.nc, t.subject, schema.OpsSecurityEvent, event); err != nil {
+			log.Error().Err(err).Msg("Error publishing security event to NATS")
+		}
+	}
+}
+
+// isDenied reports whether RGW refused the request for its credentials or
+// permissions. S3 answers 403, Swift 401.
+func isDenied(status string) bool {
+	return status == "403" || status == "401"
+}
+
+// newSecurityEvent returns the event of a denied or anonymous entry, false for
+// all other entries
+func newSecurityEvent(entry *S3OperationLog) (SecurityEvent, bool) {
+	var eventType string
+	switch {
+	case entry.User == "anonymous":
+		eventType = SecurityAnonymousAccess
+	case isDenied(entry.HTTPStatus):
+		eventType = SecurityAuthFailure
+	default:
+		return SecurityEvent{}, false
+	}
+
+	user, tenant := extractUserAndTenant(entry.User)
+	bucket := entry.Bucket
+	if prefix, name, ok := strings.Cut(bucket, "/"); ok {
+		bucket = name
+		if tenant == "none" && prefix != "" {
+			tenant = prefix
+		}
+	}
+
+	return SecurityEvent{
+		Type:       eventType,
+		Time:       entry.Time,
+		User:       user,
+		Tenant:     tenant,
+		Bucket:     bucket,
+		Object:     entry.Object,
+		RemoteAddr: entry.RemoteAddr,
+		Method:     ExtractHTTPMethod(entry.URI),
+		Operation:  entry.Operation,
+		HTTPStatus: entry.HTTPStatus,
+		ErrorCode:  entry.ErrorCode,
+		UserAgent:  entry.UserAgent,
+	}, true
+}
+
+// observeSecurityMetrics counts the event, with ip as the label of its client.
+// The events keep the address.
+func observeSecurityMetrics(event SecurityEvent, ip string) {
+	bucket := event.Bucket
+	if bucket == "" {
+		bucket = "none"
+	}
+
+	if event.Type == SecurityAnonymousAccess {
+		securityAnonymousRequests.WithLabelValues(
+			event.Tenant,
+			bucket,
+			event.Method,
+			statusClass(event.HTTPStatus),
+		).Inc()
+	}
+
+	if !isDenied(event.HTTPStatus) {
+		return
+	}
+	errorCode := event.ErrorCode
+	if errorCode == "" {
+		errorCode = "unknown"
+	}
+	securityDeniedByUser.WithLabelValues(event.Tenant, event.User, errorCode).Inc()
+	securityDeniedByIP.WithLabelValues(ip).Inc()
+	securityDeniedByBucket.WithLabelValues(event.Tenant, bucket).Inc()
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSecurityEvent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		entry    S3OperationLog
+		expected SecurityEvent
+		ok       bool
+	}{
+		{
+			name: "denied user",
+			entry: S3OperationLog{
+				User: "alice$proj", Bucket: "proj/photos", HTTPStatus: "403", ErrorCode: "AccessDenied",
+				URI: "PUT /photos/cat.jpg HTTP/1.1", RemoteAddr: "10.0.0.1", Operation: "put_obj",
+			},
+			expected: SecurityEvent{
+				Type: SecurityAuthFailure, User: "alice", Tenant: "proj", Bucket: "photos", HTTPStatus: "403",
+				ErrorCode: "AccessDenied", Method: "PUT", RemoteAddr: "10.0.0.1", Operation: "put_obj",
+			},
+			ok: true,
+		},
+		{
+			name:  "anonymous takes the tenant of the bucket",
+			entry: S3OperationLog{User: "anonymous", Bucket: "proj/site", HTTPStatus: "200", URI: "GET /site/index.html HTTP/1.1"},
+			expected: SecurityEvent{
+				Type: SecurityAnonymousAccess, User: "anonymous", Tenant: "proj", Bucket: "site", HTTPStatus: "200", Method: "GET",
+			},
+			ok: true,
+		},
+		{
+			name:  "anonymous without tenant",
+			entry: S3OperationLog{User: "anonymous", Bucket: "site", HTTPStatus: "403"},
+			expected: SecurityEvent{
+				Type: SecurityAnonymousAccess, User: "anonymous", Tenant: "none", Bucket: "site", HTTPStatus: "403", Method: "UNKNOWN",
+			},
+			ok: true,
+		},
+		{
+			name:  "swift denial",
+			entry: S3OperationLog{User: "bob", HTTPStatus: "401"},
+			expected: SecurityEvent{
+				Type: SecurityAuthFailure, User: "bob", Tenant: "none", HTTPStatus: "401", Method: "UNKNOWN",
+			},
+			ok: true,
+		},
+		{
+			name:  "allowed user",
+			entry: S3OperationLog{User: "alice$proj", Bucket: "proj/photos", HTTPStatus: "200"},
+		},
+		{
+			name:  "other error",
+			entry: S3OperationLog{User: "alice$proj", Bucket: "proj/photos", HTTPStatus: "404"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, ok := newSecurityEvent(&tc.entry)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, event)
+		})
+	}
+}
+
+func TestNewSecurityTracker(t *testing.T) {
+	assert.Nil(t, newSecurityTracker(OpsLogConfig{}, nil), "disabled by default")
+	assert.Nil(t, newSecurityTracker(OpsLogConfig{NatsSecurityEvents: true}, nil), "events need NATS")
+
+	tracker := newSecurityTracker(OpsLogConfig{MetricsConfig: MetricsConfig{TrackSecurity: true}}, nil)
+	require.NotNil(t, tracker)
+	assert.True(t, tracker.metrics)
+	assert.Nil(t, tracker.nc)
+}
+
+func TestSecurityMetrics(t *testing.T) {
+	tracker := &securityTracker{metrics: true}
+	deniedBefore := readCounterValue(t, securityDeniedByUser, "sec-tenant", "mallory", "SignatureDoesNotMatch")
+	ipBefore := readCounterValue(t, securityDeniedByIP, "192.0.2.10")
+	bucketBefore := readCounterValue(t, securityDeniedByBucket, "sec-tenant", "sec-bucket")
+	anonymousBefore := readCounterValue(t, securityAnonymousRequests, "sec-tenant", "sec-bucket", "GET", "4xx")
+
+	tracker.Observe(&S3OperationLog{
+		User: "mallory$sec-tenant", Bucket: "sec-tenant/sec-bucket", HTTPStatus: "403",
+		ErrorCode: "SignatureDoesNotMatch", RemoteAddr: "192.0.2.10",
+	})
+	tracker.Observe(&S3OperationLog{
+		User: "anonymous", Bucket: "sec-tenant/sec-bucket", HTTPStatus: "403",
+		URI: "GET /sec-bucket HTTP/1.1", RemoteAddr: "192.0.2.10",
+	})
+	tracker.Observe(&S3OperationLog{User: "alice$sec-tenant", Bucket: "sec-tenant/sec-bucket", HTTPStatus: "200"})
+
+	assert.Equal(t, deniedBefore+1, readCounterValue(t, securityDeniedByUser, "sec-tenant", "mallory", "SignatureDoesNotMatch"))
+	assert.Equal(t, ipBefore+2, readCounterValue(t, securityDeniedByIP, "192.0.2.10"), "denied anonymous requests count too")
+	assert.Equal(t, bucketBefore+2, readCounterValue(t, securityDeniedByBucket, "sec-tenant", "sec-bucket"))
+	assert.Equal(t, anonymousBefore+1, readCounterValue(t, securityAnonymousRequests, "sec-tenant", "sec-bucket", "GET", "4xx"))
+}
+
+// TestProcessLogEntries_SecurityIgnoresAnonymousSkip checks that anonymous
+// requests are tracked even when they are ignored by the aggregate metrics.
+func TestProcessLogEntries_SecurityIgnoresAnonymousSkip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ops.log")
+	content := `{"bucket":"skip-tenant/skip-bucket","user":"anonymous","uri":"GET /skip-bucket HTTP/1.1","http_status":"200"}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	cfg := OpsLogConfig{
+		LogFilePath:             path,
+		IgnoreAnonymousRequests: true,
+		MetricsConfig:           MetricsConfig{TrackRequestsPerBucket: true, TrackSecurity: true},
+	}
+	before := readCounterValue(t, securityAnonymousRequests, "skip-tenant", "skip-bucket", "GET", "2xx")
+
+	metrics := NewMetrics()
+	_, err := processLogEntries(cfg, nil, nil, metrics, nil, nil, newSecurityTracker(cfg, nil), nil, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, before+1, readCounterValue(t, securityAnonymousRequests, "skip-tenant", "skip-bucket", "GET", "2xx"))
+	assert.Zero(t, metrics.TotalRequests.Load(), "anonymous requests stay out of the aggregate metrics")
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/debugserver"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSlowRequestsBufferSize is the number of slow requests kept for the
+// debug endpoint
+const DefaultSlowRequestsBufferSize = 100
+
+// SlowRequestsEndpoint is the path of the slow requests on the debug server
+const SlowRequestsEndpoint = "/debug/slow-requests"
+
+// SlowRequest is a request captured for its latency, with the raw entry
+type SlowRequest struct {
+	CapturedAt  time.Time       `json:"captured_at"`
+	Bucket      string          `json:"bucket,omitempty"`
+	LatencyMs   int             `json:"latency_ms"`
+	ThresholdMs int64           `json:"threshold_ms"`
+	Entry       json.RawMessage `json:"entry"`
+}
+
+// parseBucketThresholds parses comma-separated <bucket>=<duration>
+// thresholds, e.g. backups=30s,web=500ms
+func parseBucketThresholds(thresholds string) (map[string]time.Duration, error) {
+	parsed := make(map[string]time.Duration)
+	for _, threshold := range strings.Split(thresholds, ",") {
+		if threshold = strings.TrimSpace(threshold); threshold == "" {
+			continue
+		}
+		bucket, value, ok := strings.Cut(threshold, "=")
+		bucket = strings.TrimSpace(bucket)
+		if !ok || bucket == "" {
+			return nil, fmt.Errorf("invalid threshold %q, expected <bucket>=<duration>", threshold)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("threshold of bucket %s is not a positive duration, e.g. 500ms", bucket)
+		}
+		parsed[bucket] = d
+	}
+	return parsed, nil
+}
+
+// Validate checks the thresholds and the buffer size
+func (cfg SlowRequestsConfig) Validate() error {
+	if cfg.Threshold < 0 {
+		return fmt.Errorf("slow request threshold must not be negative")
+	}
+	if cfg.BufferSize < 0 {
+		return fmt.Errorf("slow requests buffer size must not be negative")
+	}
+	_, err := parseBucketThresholds(cfg.BucketThresholds)
+	return err
+}
+
+// slowRequestCapture keeps the last requests above their latency threshold
+// in a ring buffer, served on the debug server, and publishes them to NATS
+// if configured
+type slowRequestCapture struct {
+	threshold time.Duration            // Of all buckets, 0 if none
+	buckets   map[string]time.Duration // Of single buckets, overriding threshold
+	nc        *nats.Conn               // Publish the entries when set
+	subject   string
+
+	mu       sync.Mutex
+	requests []SlowRequest
+	next     int // Position of the next capture in requests
+	full     bool
+}
+
+// Endpoint of the debug server, registered once
+var registerSlowRequests sync.Once
+
+// newSlowRequestCapture returns the capture of cfg, nil if no threshold is
+// set
+func newSlowRequestCapture(cfg OpsLogConfig, nc *nats.Conn) (*slowRequestCapture, error) {
+	buckets, err := parseBucketThresholds(cfg.SlowRequests.BucketThresholds)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SlowRequests.Threshold <= 0 && len(buckets) == 0 {
+		return nil, nil
+	}
+	size := cfg.SlowRequests.BufferSize
+	if size <= 0 {
+		size = DefaultSlowRequestsBufferSize
+	}
+
+	c := &slowRequestCapture{
+		threshold: cfg.SlowRequests.Threshold,
+		buckets:   buckets,
+		subject:   cfg.NatsSlowRequestsSubject,
+		requests:  make([]SlowRequest, size),
+	}
+	if cfg.UseNats && cfg.NatsSlowRequests {
+		c.nc = nc
+	}
+	log.Info().
+		Dur("threshold", c.threshold).
+		Int("bucket_thresholds", len(buckets)).
+		Int("buffer_size", size).
+		Bool("nats", c.nc != nil).
+		Msg("Slow request capture initialized")
+	return c, nil
+}
+
+// serve registers the capture on the debug server
+func (c *slowRequestCapture) serve() {
+	registerSlowRequests.Do(func() { debugserver.Handle(SlowRequestsEndpoint, c) })
+}
+
+// Observe captures the raw entry if its latency exceeds the threshold of its
+// bucket. The bucket name of the entry must be cleaned up already.
+func (c *slowRequestCapture) Observe(raw []byte, entry *S3OperationLog) {
+	threshold, ok := c.buckets[entry.Bucket]
+	if !ok {
+		threshold = c.threshold
+	}
+	if threshold <= 0 || time.Duration(entry.TotalTime)*time.Millisecond <= threshold {
+		return
+	}
+
+	request := SlowRequest{
+		CapturedAt:  time.Now().UTC(),
+		Bucket:      entry.Bucket,
+		LatencyMs:   entry.TotalTime,
+		ThresholdMs: threshold.Milliseconds(),
+		Entry:       append(json.RawMessage(nil), raw...), // raw is reused by the reader
+	}
+	slowRequestsCaptured.Inc()
+
+	c.mu.Lock()
+	c.requests[c.next] = request
+	c.next = (c.next + 1) % len(c.requests)
+	c.full = c.full || c.next == 0
+	c.mu.Unlock()
+
+	if c.nc != nil {
+		if err := schema.Publish(c.nc, c.subject, schema.OpsEvent, entry); err != nil {
+			log.Error().Err(err).Msg("Error publishing slow request to NATS")
+		}
+	}
+}
+
+// Snapshot returns the captured requests, the newest first
+func (c *slowRequestCapture) Snapshot() []SlowRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	requests := make([]SlowRequest, 0, len(c.requests))
+	if c.full {
+		requests = append(requests, c.requests[c.next:]...)
+	}
+	requests = append(requests, c.requests[:c.next]...)
+	slices.Reverse(requests)
+	return requests
+}
+
+// ServeHTTP returns the captured requests as JSON, the newest first or the
+// slowest with ?sort=latency, of a single bucket with ?bucket= and at most
+// ?limit= of them
+func (c *slowRequestCapture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requests := c.Snapshot()
+	query := r.URL.Query()
+	if bucket := query.Get("bucket"); bucket != "" {
+		filtered := requests[:0]
+		for _, request := range requests {
+			if request.Bucket == bucket {
+				filtered = append(filtered, request)
+			}
+		}
+		requests = filtered
+	}
+	switch query.Get("sort") {
+	case "", "time":
+	case "latency":
+		slices.SortStableFunc(requests, func(a, b SlowRequest) int { return cmp.Compare(b.LatencyMs, a.LatencyMs) })
+	default:
+		http.Error(w, "sort must be time or latency", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		requests = requests[:min(limit, len(requests))]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(requests); err != nil {
+		log.Error().Err(err).Msg("Error writing slow requests")
+	}
+}
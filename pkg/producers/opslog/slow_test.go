@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBucketThresholds(t *testing.T) {
+	thresholds, err := parseBucketThresholds(" backups=30s, web=500ms,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"backups": 30 * time.Second, "web": 500 * time.Millisecond}, thresholds)
+
+	for _, invalid := range []string{"backups", "=1s", "web=fast", "web=0s"} {
+		_, err := parseBucketThresholds(invalid)
+		assert.Error(t, err, invalid)
+	}
+	assert.Error(t, SlowRequestsConfig{Threshold: -time.Second}.Validate())
+	assert.NoError(t, SlowRequestsConfig{Threshold: time.Second, BucketThresholds: "web=500ms"}.Validate())
+}
+
+func TestSlowRequestCapture(t *testing.T) {
+	capture, err := newSlowRequestCapture(OpsLogConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, capture, "disabled without thresholds")
+
+	capture, err = newSlowRequestCapture(OpsLogConfig{SlowRequests: SlowRequestsConfig{
+		Threshold: 2 * time.Second, BucketThresholds: "web=500ms", BufferSize: 2,
+	}}, nil)
+	require.NoError(t, err)
+
+	observe := func(bucket string, totalTime int) {
+		entry := &S3OperationLog{Bucket: bucket, TotalTime: totalTime}
+		raw, err := json.Marshal(entry)
+		require.NoError(t, err)
+		capture.Observe(raw, entry)
+	}
+	observe("backups", 1500) // Below the global threshold
+	observe("web", 800)      // Above the threshold of web
+	observe("backups", 2500)
+	requests := capture.Snapshot()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "backups", requests[0].Bucket, "the newest first")
+	assert.Equal(t, int64(2000), requests[0].ThresholdMs)
+	assert.Equal(t, 800, requests[1].LatencyMs)
+	assert.Equal(t, int64(500), requests[1].ThresholdMs)
+	assert.JSONEq(t, `{"bucket": "web", "total_time": 800}`, filterJSON(t, requests[1].Entry, "bucket", "total_time"))
+
+	// The oldest request is overwritten
+	observe("web", 3000)
+	requests = capture.Snapshot()
+	require.Len(t, requests, 2)
+	assert.Equal(t, []int{3000, 2500}, []int{requests[0].LatencyMs, requests[1].LatencyMs})
+}
+
+func TestSlowRequestCapture_ServeHTTP(t *testing.T) {
+	capture, err := newSlowRequestCapture(OpsLogConfig{SlowRequests: SlowRequestsConfig{Threshold: time.Second}}, nil)
+	require.NoError(t, err)
+	for _, entry := range []*S3OperationLog{{Bucket: "web", TotalTime: 1200}, {Bucket: "backups", TotalTime: 9000}, {Bucket: "web", TotalTime: 4000}} {
+		capture.Observe([]byte(`{}`), entry)
+	}
+
+	get := func(query string) (int, []SlowRequest) {
+		recorder := httptest.NewRecorder()
+		capture.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SlowRequestsEndpoint+query, nil))
+		var requests []SlowRequest
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &requests))
+		}
+		return recorder.Code, requests
+	}
+
+	code, requests := get("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, requests, 3)
+
+	_, requests = get("?bucket=web&sort=latency&limit=1")
+	require.Len(t, requests, 1)
+	assert.Equal(t, 4000, requests[0].LatencyMs)
+
+	_, requests = get("?sort=latency")
+	assert.Equal(t, 9000, requests[0].LatencyMs)
+
+	code, _ = get("?limit=-1")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("?sort=bucket")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// filterJSON returns the fields of a JSON object
+func filterJSON(t *testing.T, data []byte, fields ...string) string {
+	var object map[string]any
+	require.NoError(t, json.Unmarshal(data, &object))
+	filtered := make(map[string]any)
+	for _, field := range fields {
+		filtered[field] = object[field]
+	}
+	b, err := json.Marshal(filtered)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/cobaltcore-dev/prysm/pkg/producers/opslog")
+
+// pipelineTimings sums up the time a batch of ops log entries spent in each
+// stage of the pipeline. A span per entry would outnumber the entries the
+// sidecar can process, so the stages are attributes of the batch span.
+type pipelineTimings struct {
+	entries   int
+	parse     time.Duration // decoding the JSON stream
+	handle    time.Duration // everything after decoding, the stages below and filtering
+	aggregate time.Duration // updating the metrics
+	audit     time.Duration // filtering and recording audit events
+	publish   time.Duration // printing to stdout and publishing to NATS
+}
+
+// record sets the timings and the bytes read on the span of the batch
+func (t *pipelineTimings) record(span trace.Span, bytes int64) {
+	span.SetAttributes(
+		attribute.Int("opslog.entries", t.entries),
+		attribute.Int64("opslog.bytes", bytes),
+		attribute.Float64("opslog.parse.seconds", t.parse.Seconds()),
+		attribute.Float64("opslog.aggregate.seconds", t.aggregate.Seconds()),
+		attribute.Float64("opslog.audit.seconds", t.audit.Seconds()),
+		attribute.Float64("opslog.publish.seconds", t.publish.Seconds()),
+	)
+}
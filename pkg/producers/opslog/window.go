@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import "time"
+
+// windowTicker ticks at the wall-clock boundaries of its interval, e.g. at
+// every full minute, so the metric windows of all sidecars line up and can be
+// summed across nodes. C receives the boundary, the end of the window; a
+// slow receiver gets the latest boundary.
+type windowTicker struct {
+	C    <-chan time.Time
+	stop chan struct{}
+}
+
+func newWindowTicker(interval time.Duration) *windowTicker {
+	c := make(chan time.Time, 1)
+	t := &windowTicker{C: c, stop: make(chan struct{})}
+	go func() {
+		for {
+			end := nextWindowBoundary(time.Now(), interval)
+			timer := time.NewTimer(time.Until(end))
+			select {
+			case <-t.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			sendLatest(c, end)
+		}
+	}()
+	return t
+}
+
+// sendLatest sends the boundary end without blocking. A boundary the
+// receiver has not taken yet is replaced: the window it closes then starts at
+// the last boundary it received and spans the missed ones, so no counts are
+// dropped and the window ends when its metrics were taken.
+func sendLatest(c chan time.Time, end time.Time) {
+	select {
+	case c <- end:
+		return
+	default:
+	}
+	select {
+	case <-c:
+	default:
+	}
+	// Only the ticker sends, the buffer has room now
+	c <- end
+}
+
+func (t *windowTicker) Stop() {
+	close(t.stop)
+}
+
+// nextWindowBoundary returns the first boundary of interval after now,
+// counted from the Unix epoch
+func nextWindowBoundary(now time.Time, interval time.Duration) time.Time {
+	return now.Truncate(interval).Add(interval)
+}
+
+// metricsWindow stamps the metrics published to NATS with the window they
+// were counted in and the sidecar that counted them
+type metricsWindow struct {
+	start      time.Time
+	instanceID string
+	host       string
+}
+
+// newMetricsWindow starts the first window now. It ends at the next
+// boundary, so it is shorter than the interval.
+func newMetricsWindow(cfg OpsLogConfig) *metricsWindow {
+	return &metricsWindow{start: time.Now(), instanceID: cfg.InstanceID, host: cfg.NodeName}
+}
+
+// close stamps the metrics of the window ending at end and starts the next
+func (w *metricsWindow) close(metrics AggregatedMetrics, end time.Time) AggregatedMetrics {
+	metrics.WindowStart = w.start.UTC()
+	metrics.WindowEnd = end.UTC()
+	metrics.InstanceID = w.instanceID
+	metrics.Host = w.host
+	w.start = end
+	return metrics
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextWindowBoundary(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 35, 0, 0, time.UTC), nextWindowBoundary(now, time.Minute))
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 35, 0, 0, time.UTC), nextWindowBoundary(now, 5*time.Minute))
+
+	// A boundary ends the window it starts, the next one is a full interval later
+	onBoundary := time.Date(2025, 3, 1, 12, 35, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 36, 0, 0, time.UTC), nextWindowBoundary(onBoundary, time.Minute))
+}
+
+func TestSendLatest(t *testing.T) {
+	c := make(chan time.Time, 1)
+	first := time.Date(2025, 3, 1, 12, 35, 0, 0, time.UTC)
+	sendLatest(c, first)
+
+	// The receiver missed a boundary, it gets the latest one
+	second := first.Add(time.Minute)
+	sendLatest(c, second)
+	assert.Equal(t, second, <-c)
+	assert.Empty(t, c)
+}
+
+func TestMetricsWindow_Close(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+	window := &metricsWindow{start: start, instanceID: "rgw-0", host: "node-1"}
+
+	first := window.close(AggregatedMetrics{TotalRequests: 3}, nextWindowBoundary(start, time.Minute))
+	assert.Equal(t, start, first.WindowStart)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 35, 0, 0, time.UTC), first.WindowEnd)
+	assert.Equal(t, "rgw-0", first.InstanceID)
+	assert.Equal(t, "node-1", first.Host)
+	assert.Equal(t, uint64(3), first.TotalRequests)
+
+	// The next window starts where the last one ended
+	second := window.close(AggregatedMetrics{}, first.WindowEnd.Add(time.Minute))
+	assert.Equal(t, first.WindowEnd, second.WindowStart)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 36, 0, 0, time.UTC), second.WindowEnd)
+
+	// After a missed boundary the window spans both intervals
+	third := window.close(AggregatedMetrics{}, second.WindowEnd.Add(2*time.Minute))
+	assert.Equal(t, second.WindowEnd, third.WindowStart)
+	assert.Equal(t, 2*time.Minute, third.WindowEnd.Sub(third.WindowStart))
+}
+
+func TestNewMetricsWindow_Identity(t *testing.T) {
+	window := newMetricsWindow(OpsLogConfig{Identity: identity.Identity{InstanceID: "rgw-a", PodName: "rgw-0", NodeName: "node-1"}})
+	assert.Equal(t, "rgw-a", window.instanceID)
+	assert.Equal(t, "node-1", window.host)
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+type OSDPerfConfig struct {
+	SocketGlob     string // admin sockets of the OSDs, e.g. /var/run/ceph/ceph-osd.*.asok
+	Interval       int    // in seconds
+	Timeout        int    // of an admin socket command, in seconds
+	NatsURL        string
+	NatsSubject    string
+	UseNats        bool
+	NodeName       string
+	InstanceID     string
+	Prometheus     bool
+	PrometheusPort int
+}
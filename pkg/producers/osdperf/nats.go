@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+import (
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+)
+
+func PublishToNATS(nc *nats.Conn, perfs []OSDPerf, cfg OSDPerfConfig) error {
+	for _, perf := range perfs {
+		if err := schema.Publish(nc, cfg.NatsSubject, schema.OSDPerf, perf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/adminsocket"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// Admin sockets of OSDs are named ceph-osd.<id>.asok, or <cluster>-osd.<id>.asok
+var osdSocketName = regexp.MustCompile(`-osd\.(\d+)\.asok$`)
+
+// collector collects the OSDs of the node, keeping the latency counters of
+// the previous collection by admin socket
+type collector struct {
+	cfg      OSDPerfConfig
+	previous map[string]latencies
+}
+
+func newCollector(cfg OSDPerfConfig) *collector {
+	return &collector{cfg: cfg, previous: map[string]latencies{}}
+}
+
+// sockets finds the admin sockets of the OSDs
+func (c *collector) sockets() ([]string, error) {
+	matches, err := filepath.Glob(c.cfg.SocketGlob)
+	if err != nil {
+		return nil, err
+	}
+	var sockets []string
+	for _, match := range matches {
+		if osdSocketName.MatchString(match) {
+			sockets = append(sockets, match)
+		}
+	}
+	return sockets, nil
+}
+
+// collect collects every OSD; OSDs that do not answer are logged and
+// reported as down
+func (c *collector) collect(ctx context.Context) ([]OSDPerf, []string, error) {
+	sockets, err := c.sockets()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var perfs []OSDPerf
+	var down []string
+	seen := map[string]bool{}
+	for _, socket := range sockets {
+		seen[socket] = true
+		perf, err := c.collectOSD(ctx, socket)
+		if err != nil {
+			osdID := osdSocketName.FindStringSubmatch(socket)[1]
+			log.Warn().Err(err).Str("socket", socket).Str("osd_id", osdID).Msg("error collecting OSD perf counters")
+			down = append(down, osdID)
+			delete(c.previous, socket)
+			continue
+		}
+		perfs = append(perfs, perf)
+	}
+	// Forget OSDs that went away
+	for socket := range c.previous {
+		if !seen[socket] {
+			delete(c.previous, socket)
+		}
+	}
+	return perfs, down, nil
+}
+
+func (c *collector) collectOSD(ctx context.Context, socket string) (OSDPerf, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	data, err := adminsocket.Command(ctx, socket, "status")
+	if err != nil {
+		return OSDPerf{}, err
+	}
+	status, err := parseStatus(data)
+	if err != nil {
+		return OSDPerf{}, err
+	}
+
+	data, err = adminsocket.Command(ctx, socket, "perf dump")
+	if err != nil {
+		return OSDPerf{}, err
+	}
+	perf, current, err := parsePerfDump(data)
+	if err != nil {
+		return OSDPerf{}, err
+	}
+
+	var previous *latencies
+	if lat, found := c.previous[socket]; found {
+		previous = &lat
+	}
+	perf.setLatencies(current, previous)
+	c.previous[socket] = current
+
+	perf.OSDID = strconv.Itoa(status.WhoAmI)
+	perf.CephCluster = status.ClusterFSID
+	perf.State = status.State
+	perf.PGs = status.NumPGs
+	perf.NodeName = c.cfg.NodeName
+	perf.InstanceID = c.cfg.InstanceID
+	perf.Timestamp = time.Now().UTC()
+	return perf, nil
+}
+
+func StartMonitoring(cfg OSDPerfConfig) {
+	var nc *nats.Conn
+	var err error
+	if cfg.UseNats {
+		nc, err = natsutil.Connect(cfg.NatsURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error connecting to NATS")
+		}
+		defer nc.Close()
+	}
+
+	if cfg.Prometheus {
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+	}
+
+	c := newCollector(cfg)
+	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		perfs, down, err := c.collect(context.Background())
+		if err != nil {
+			log.Error().Err(err).Str("socket_glob", cfg.SocketGlob).Msg("error finding OSD admin sockets")
+			continue
+		}
+		if len(perfs) == 0 && len(down) == 0 {
+			log.Warn().Str("socket_glob", cfg.SocketGlob).Msg("no OSD admin sockets found")
+		}
+
+		if cfg.Prometheus {
+			PublishToPrometheus(perfs, down, cfg)
+		}
+
+		if cfg.UseNats {
+			if err := PublishToNATS(nc, perfs, cfg); err != nil {
+				log.Error().Err(err).Msg("error publishing OSD perf counters to NATS")
+			}
+		} else if !cfg.Prometheus {
+			for _, perf := range perfs {
+				perfJSON, err := json.Marshal(perf)
+				if err != nil {
+					log.Error().Err(err).Msg("error marshalling OSD perf counters to JSON")
+					continue
+				}
+				fmt.Println(string(perfJSON))
+			}
+		}
+	}
+}
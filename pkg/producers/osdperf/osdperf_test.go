@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+// serveAdminSocket answers admin socket commands with responses by prefix
+func serveAdminSocket(t *testing.T, path string, responses map[string][]byte) {
+	t.Helper()
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				request, err := bufio.NewReader(conn).ReadBytes(0)
+				if err != nil {
+					return
+				}
+				var command struct {
+					Prefix string `json:"prefix"`
+				}
+				if json.Unmarshal(request[:len(request)-1], &command) != nil {
+					return
+				}
+				response := responses[command.Prefix]
+				binary.Write(conn, binary.BigEndian, uint32(len(response)))
+				conn.Write(response)
+			}()
+		}
+	}()
+}
+
+func TestParsePerfDump(t *testing.T) {
+	perf, lat, err := parsePerfDump(readTestdata(t, "perf_dump.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "bluestore", perf.ObjectStore)
+	assert.Equal(t, int64(3), perf.OpQueueDepth)
+	assert.Equal(t, uint64(120000), perf.ReadOps)
+	assert.Equal(t, uint64(64512), perf.WriteOps)
+	assert.Equal(t, uint64(104857600000), perf.ReadBytes)
+	assert.Equal(t, uint64(52428800000), perf.WriteBytes)
+	assert.Equal(t, uint64(70000), lat[0].AvgCount)
+	assert.Equal(t, lat[0], lat[1])
+
+	_, _, err = parsePerfDump([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestParsePerfDumpFileStore(t *testing.T) {
+	perf, lat, err := parsePerfDump([]byte(`{"osd": {}, "filestore": {"journal_latency": {"avgcount": 10, "sum": 0.1}, "apply_latency": {"avgcount": 10, "sum": 0.5}}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "filestore", perf.ObjectStore)
+	assert.Equal(t, 0.1, lat[0].Sum)
+	assert.Equal(t, 0.5, lat[1].Sum)
+}
+
+func TestSetLatencies(t *testing.T) {
+	current := latencies{
+		{AvgCount: 200, Sum: 3, AvgTime: 0.015},
+		{AvgCount: 200, Sum: 3, AvgTime: 0.015},
+		{AvgCount: 50, Sum: 1, AvgTime: 0.02},
+		{AvgCount: 50, Sum: 1, AvgTime: 0.02},
+		{AvgCount: 5, Sum: 1, AvgTime: 0.2},
+	}
+
+	t.Run("first collection", func(t *testing.T) {
+		var perf OSDPerf
+		perf.setLatencies(current, nil)
+		assert.Equal(t, 0.015, perf.CommitLatency)
+		assert.Equal(t, 0.2, perf.WriteLatency)
+	})
+
+	t.Run("since previous", func(t *testing.T) {
+		previous := latencies{
+			{AvgCount: 100, Sum: 1},
+			{AvgCount: 100, Sum: 1},
+			{AvgCount: 50, Sum: 1},
+			{AvgCount: 40, Sum: 0.9},
+			{AvgCount: 10, Sum: 2},
+		}
+		var perf OSDPerf
+		perf.setLatencies(current, &previous)
+		assert.InDelta(t, 0.02, perf.CommitLatency, 1e-9)
+		assert.InDelta(t, 0.02, perf.ApplyLatency, 1e-9)
+		assert.Zero(t, perf.OpLatency, "no operations in between")
+		assert.InDelta(t, 0.01, perf.ReadLatency, 1e-9)
+		assert.Zero(t, perf.WriteLatency, "restarted OSD")
+	})
+}
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	serveAdminSocket(t, filepath.Join(dir, "ceph-osd.7.asok"), map[string][]byte{
+		"status":    readTestdata(t, "status.json"),
+		"perf dump": readTestdata(t, "perf_dump.json"),
+	})
+	// A socket file without an OSD listening
+	dead, err := net.Listen("unix", filepath.Join(dir, "ceph-osd.12.asok"))
+	require.NoError(t, err)
+	dead.(*net.UnixListener).SetUnlinkOnClose(false)
+	dead.Close()
+	// Sockets of other daemons are ignored
+	serveAdminSocket(t, filepath.Join(dir, "ceph-mon.a.asok"), nil)
+
+	cfg := OSDPerfConfig{
+		SocketGlob: filepath.Join(dir, "*.asok"),
+		Timeout:    5,
+		NodeName:   "node-1",
+		InstanceID: "prysm-1",
+	}
+	c := newCollector(cfg)
+	perfs, down, err := c.collect(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, perfs, 1)
+	perf := perfs[0]
+	assert.Equal(t, "7", perf.OSDID)
+	assert.Equal(t, "c4a5d3a8-1b6f-4a52-9d3e-7f0b1e2d3c4f", perf.CephCluster)
+	assert.Equal(t, "active", perf.State)
+	assert.Equal(t, 118, perf.PGs)
+	assert.Equal(t, 0.002, perf.CommitLatency)
+	assert.Equal(t, "node-1", perf.NodeName)
+	assert.Equal(t, []string{"12"}, down)
+	assert.Contains(t, c.previous, filepath.Join(dir, "ceph-osd.7.asok"))
+
+	PublishToPrometheus(perfs, down, cfg)
+	assert.Equal(t, 0.002, testutil.ToFloat64(commitLatencyGauge.WithLabelValues("7", perf.CephCluster, "node-1", "prysm-1")))
+	assert.Equal(t, float64(120000), testutil.ToFloat64(opsGauge.WithLabelValues("7", perf.CephCluster, "node-1", "prysm-1", "read")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(upGauge.WithLabelValues("7", "node-1", "prysm-1")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(upGauge.WithLabelValues("12", "node-1", "prysm-1")))
+}
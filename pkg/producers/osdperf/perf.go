@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OSDPerf holds the performance of an OSD, published on NATS on every
+// collection. Latencies are averages since the previous collection, the
+// lifetime averages on the first one; counters are totals since the OSD
+// started.
+type OSDPerf struct {
+	OSDID         string    `json:"osd_id"`
+	CephCluster   string    `json:"ceph_cluster"`
+	State         string    `json:"state"`
+	PGs           int       `json:"pgs"`
+	ObjectStore   string    `json:"object_store"` // bluestore or filestore
+	CommitLatency float64   `json:"commit_latency_seconds"`
+	ApplyLatency  float64   `json:"apply_latency_seconds"`
+	OpLatency     float64   `json:"op_latency_seconds"`
+	ReadLatency   float64   `json:"op_read_latency_seconds"`
+	WriteLatency  float64   `json:"op_write_latency_seconds"`
+	OpQueueDepth  int64     `json:"op_queue_depth"`
+	ReadOps       uint64    `json:"read_ops"`
+	WriteOps      uint64    `json:"write_ops"`
+	ReadBytes     uint64    `json:"read_bytes"`
+	WriteBytes    uint64    `json:"write_bytes"`
+	NodeName      string    `json:"node_name"`
+	InstanceID    string    `json:"instance_id"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// The answer of the status command of an OSD
+type osdStatus struct {
+	ClusterFSID string `json:"cluster_fsid"`
+	WhoAmI      int    `json:"whoami"`
+	State       string `json:"state"`
+	NumPGs      int    `json:"num_pgs"`
+}
+
+// A long running average of a perf counter, sum is in seconds
+type avgCounter struct {
+	AvgCount uint64  `json:"avgcount"`
+	Sum      float64 `json:"sum"`
+	AvgTime  float64 `json:"avgtime"`
+}
+
+// The counters of perf dump prysm uses
+type perfDump struct {
+	OSD struct {
+		OpWip      int64      `json:"op_wip"`
+		OpR        uint64     `json:"op_r"`
+		OpW        uint64     `json:"op_w"`
+		OpOutBytes uint64     `json:"op_out_bytes"` // read by clients
+		OpInBytes  uint64     `json:"op_in_bytes"`  // written by clients
+		OpLatency  avgCounter `json:"op_latency"`
+		OpRLatency avgCounter `json:"op_r_latency"`
+		OpWLatency avgCounter `json:"op_w_latency"`
+	} `json:"osd"`
+	BlueStore *struct {
+		CommitLat avgCounter `json:"commit_lat"`
+	} `json:"bluestore"`
+	FileStore *struct {
+		JournalLatency avgCounter `json:"journal_latency"`
+		ApplyLatency   avgCounter `json:"apply_latency"`
+	} `json:"filestore"`
+}
+
+// latencies are the latency counters of an OSD, in the order of the
+// fields of OSDPerf
+type latencies [5]avgCounter
+
+func parseStatus(data []byte) (osdStatus, error) {
+	var status osdStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return osdStatus{}, fmt.Errorf("failed to parse OSD status: %w", err)
+	}
+	return status, nil
+}
+
+// parsePerfDump returns the counters of perf dump, and the latency counters
+// to average between collections
+func parsePerfDump(data []byte) (OSDPerf, latencies, error) {
+	var dump perfDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return OSDPerf{}, latencies{}, fmt.Errorf("failed to parse perf dump: %w", err)
+	}
+
+	perf := OSDPerf{
+		OpQueueDepth: dump.OSD.OpWip,
+		ReadOps:      dump.OSD.OpR,
+		WriteOps:     dump.OSD.OpW,
+		ReadBytes:    dump.OSD.OpOutBytes,
+		WriteBytes:   dump.OSD.OpInBytes,
+	}
+	lat := latencies{2: dump.OSD.OpLatency, 3: dump.OSD.OpRLatency, 4: dump.OSD.OpWLatency}
+	switch {
+	case dump.BlueStore != nil:
+		// BlueStore applies when it commits, ceph osd perf reports the
+		// commit latency for both
+		perf.ObjectStore = "bluestore"
+		lat[0], lat[1] = dump.BlueStore.CommitLat, dump.BlueStore.CommitLat
+	case dump.FileStore != nil:
+		perf.ObjectStore = "filestore"
+		lat[0], lat[1] = dump.FileStore.JournalLatency, dump.FileStore.ApplyLatency
+	}
+	return perf, lat, nil
+}
+
+// setLatencies sets the averages of the latencies since previous, or the
+// lifetime averages without previous. Without operations in between the
+// latency is 0, as in ceph osd perf.
+func (perf *OSDPerf) setLatencies(current latencies, previous *latencies) {
+	fields := []*float64{&perf.CommitLatency, &perf.ApplyLatency, &perf.OpLatency, &perf.ReadLatency, &perf.WriteLatency}
+	for i, field := range fields {
+		if previous == nil {
+			*field = current[i].AvgTime
+			continue
+		}
+		*field = 0
+		// A restarted OSD starts its counters from 0
+		if current[i].AvgCount > previous[i].AvgCount {
+			*field = (current[i].Sum - previous[i].Sum) / float64(current[i].AvgCount-previous[i].AvgCount)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package osdperf
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The labels of the disk health metrics, so the OSD metrics join with the
+// disks backing them, e.g. on disk_info
+var osdLabels = []string{"osd_id", "ceph_cluster", "node", "instance"}
+
+func newOSDGauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, append(osdLabels, labels...))
+}
+
+var (
+	commitLatencyGauge = newOSDGauge("ceph_osd_commit_latency_seconds", "Average commit latency since the previous collection")
+	applyLatencyGauge  = newOSDGauge("ceph_osd_apply_latency_seconds", "Average apply latency since the previous collection")
+	opLatencyGauge     = newOSDGauge("ceph_osd_op_latency_seconds", "Average client operation latency since the previous collection", "op")
+	opQueueDepthGauge  = newOSDGauge("ceph_osd_op_queue_depth", "Client operations in flight")
+	opsGauge           = newOSDGauge("ceph_osd_ops_total", "Client operations since the OSD started", "op")
+	bytesGauge         = newOSDGauge("ceph_osd_bytes_total", "Client bytes since the OSD started", "direction")
+	pgsGauge           = newOSDGauge("ceph_osd_pgs", "Number of placement groups on the OSD")
+
+	upGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ceph_osd_perf_up",
+			Help: "1 if the admin socket of the OSD answered the last collection",
+		},
+		[]string{"osd_id", "node", "instance"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		commitLatencyGauge, applyLatencyGauge, opLatencyGauge,
+		opQueueDepthGauge, opsGauge, bytesGauge, pgsGauge,
+		upGauge,
+	)
+}
+
+// PublishToPrometheus sets the metrics of the OSDs of the last collection;
+// OSDs that are gone or down disappear, apart from ceph_osd_perf_up
+func PublishToPrometheus(perfs []OSDPerf, down []string, cfg OSDPerfConfig) {
+	for _, gauge := range []*prometheus.GaugeVec{
+		commitLatencyGauge, applyLatencyGauge, opLatencyGauge,
+		opQueueDepthGauge, opsGauge, bytesGauge, pgsGauge,
+		upGauge,
+	} {
+		gauge.Reset()
+	}
+
+	for _, perf := range perfs {
+		labels := prometheus.Labels{
+			"osd_id":       perf.OSDID,
+			"ceph_cluster": perf.CephCluster,
+			"node":         cfg.NodeName,
+			"instance":     cfg.InstanceID,
+		}
+		with := func(name, value string) prometheus.Labels {
+			l := prometheus.Labels{name: value}
+			for k, v := range labels {
+				l[k] = v
+			}
+			return l
+		}
+
+		commitLatencyGauge.With(labels).Set(perf.CommitLatency)
+		applyLatencyGauge.With(labels).Set(perf.ApplyLatency)
+		opLatencyGauge.With(with("op", "all")).Set(perf.OpLatency)
+		opLatencyGauge.With(with("op", "read")).Set(perf.ReadLatency)
+		opLatencyGauge.With(with("op", "write")).Set(perf.WriteLatency)
+		opQueueDepthGauge.With(labels).Set(float64(perf.OpQueueDepth))
+		opsGauge.With(with("op", "read")).Set(float64(perf.ReadOps))
+		opsGauge.With(with("op", "write")).Set(float64(perf.WriteOps))
+		bytesGauge.With(with("direction", "read")).Set(float64(perf.ReadBytes))
+		bytesGauge.With(with("direction", "write")).Set(float64(perf.WriteBytes))
+		pgsGauge.With(labels).Set(float64(perf.PGs))
+		upGauge.WithLabelValues(perf.OSDID, cfg.NodeName, cfg.InstanceID).Set(1)
+	}
+	for _, osdID := range down {
+		upGauge.WithLabelValues(osdID, cfg.NodeName, cfg.InstanceID).Set(0)
+	}
+}
@@ -5,16 +5,10 @@
 package quotausagemonitor
 
 import (
-	"encoding/json"
-
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 )
 
 func PublishToNATS(nc *nats.Conn, quotas []QuotaUsage, cfg QuotaUsageMonitorConfig) error {
-	data, err := json.Marshal(quotas)
-	if err != nil {
-		return err
-	}
-
-	return nc.Publish(cfg.NatsSubject, data)
+	return schema.Publish(nc, cfg.NatsSubject, schema.QuotaUsage, quotas)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
 	"github.com/nats-io/nats.go"
 )
 
@@ -107,7 +108,7 @@ func StartMonitoring(cfg QuotaUsageMonitorConfig) {
 	var nc *nats.Conn
 	var err error
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Error connecting to NATS")
 		}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cobaltcore-dev/prysm/pkg/apicategory"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/nats-io/nats.go"
+)
+
+// APIUsage is the usage of an API category as reported by the RGW usage
+// log, summed over the RGW operations of the category
+type APIUsage struct {
+	Ops           uint64
+	SuccessfulOps uint64
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// usageByAPICategory sums the categories of the usage log, named by the RGW
+// operation, by the API categories of the ops log
+func usageByAPICategory(categories []rgwadmin.UsageEntryCategory) map[string]APIUsage {
+	usage := map[string]APIUsage{}
+	for _, category := range categories {
+		api := apicategory.OfUsage(category.Category)
+		u := usage[api]
+		u.Ops += category.Ops
+		u.SuccessfulOps += category.SuccessfulOps
+		u.BytesSent += category.BytesSent
+		u.BytesReceived += category.BytesReceived
+		usage[api] = u
+	}
+	return usage
+}
+
+// loadBucketAPIUsage returns the usage of a bucket by API category, nil if
+// the usage log has no entry of the bucket
+func loadBucketAPIUsage(userUsageData nats.KeyValue, key string) (map[string]APIUsage, error) {
+	entry, err := userUsageData.Get(key)
+	if err != nil {
+		if errors.Is(err, nats.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch the usage of %s: %w", key, err)
+	}
+	var usage rgwadmin.UsageEntryBucket
+	if err := json.Unmarshal(entry.Value(), &usage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the usage of %s: %w", key, err)
+	}
+	return usageByAPICategory(usage.Categories), nil
+}
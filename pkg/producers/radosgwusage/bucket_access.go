@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// BucketAccess is the access a bucket grants beyond its owner, by its ACL or
+// its bucket policy
+type BucketAccess struct {
+	PublicRead    bool     `json:"public_read"`      // Anyone may read objects or list the bucket
+	PublicWrite   bool     `json:"public_write"`     // Anyone may write or delete objects
+	Authenticated bool     `json:"authenticated"`    // Every user of the cluster is granted access
+	Grants        []string `json:"grants,omitempty"` // What grants it, e.g. acl:AllUsers:READ
+}
+
+// Public reports whether anonymous users have access
+func (a *BucketAccess) Public() bool {
+	return a != nil && (a.PublicRead || a.PublicWrite)
+}
+
+// bucketRecord is the bucket data stored in KV: the bucket info of RGW and,
+// when access is audited, the access of the bucket
+type bucketRecord struct {
+	rgwadmin.Bucket
+	Access *BucketAccess `json:"access,omitempty"`
+}
+
+// Actions of bucket policies that read or write a bucket. Statements match
+// them with wildcards, e.g. s3:Get* or s3:*.
+var (
+	readActions  = []string{"s3:getobject", "s3:getobjectversion", "s3:listbucket", "s3:listbucketversions"}
+	writeActions = []string{"s3:putobject", "s3:deleteobject", "s3:deleteobjectversion"}
+)
+
+// fetchBucketAccess evaluates the ACL and the bucket policy of bucket
+func fetchBucketAccess(ctx context.Context, co Collector, bucket rgwadmin.Bucket) (*BucketAccess, error) {
+	acl, err := co.GetBucketPolicy(ctx, bucket.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the ACL: %w", err)
+	}
+	document, err := co.GetBucketIAMPolicy(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the bucket policy: %w", err)
+	}
+
+	access := &BucketAccess{}
+	accessFromACL(acl, access)
+	if err := accessFromPolicy(document, access); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// accessFromACL adds the grants of the AllUsers and AuthenticatedUsers groups
+func accessFromACL(acl rgwadmin.BucketPolicy, access *BucketAccess) {
+	public := acl.GroupPermissions(rgwadmin.ACLGroupAllUsers)
+	if public&rgwadmin.ACLPermRead != 0 {
+		access.PublicRead = true
+		access.Grants = append(access.Grants, "acl:AllUsers:READ")
+	}
+	if public&rgwadmin.ACLPermWrite != 0 {
+		access.PublicWrite = true
+		access.Grants = append(access.Grants, "acl:AllUsers:WRITE")
+	}
+	if acl.GroupPermissions(rgwadmin.ACLGroupAuthenticatedUser) != 0 {
+		access.Authenticated = true
+		access.Grants = append(access.Grants, "acl:AuthenticatedUsers")
+	}
+}
+
+// policyDocument is the part of a bucket policy deciding public access.
+// Statement, Principal.AWS and Action may be a single value or a list.
+type policyDocument struct {
+	Statement oneOrMany[policyStatement] `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect    string            `json:"Effect"`
+	Principal json.RawMessage   `json:"Principal"`
+	Action    oneOrMany[string] `json:"Action"`
+	Condition json.RawMessage   `json:"Condition"`
+}
+
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]T)(o))
+	}
+	var one T
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*o = oneOrMany[T]{one}
+	return nil
+}
+
+// anyone reports whether a principal is everyone, "*" or {"AWS": "*"}
+func anyone(principal json.RawMessage) bool {
+	var wildcard string
+	if json.Unmarshal(principal, &wildcard) == nil {
+		return wildcard == "*"
+	}
+	var principals struct {
+		AWS oneOrMany[string] `json:"AWS"`
+	}
+	if json.Unmarshal(principal, &principals) != nil {
+		return false
+	}
+	for _, p := range principals.AWS {
+		if p == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// grantsAny reports whether a statement's actions cover one of actions
+func grantsAny(statement policyStatement, actions []string) bool {
+	for _, pattern := range statement.Action {
+		pattern = strings.ToLower(pattern)
+		for _, action := range actions {
+			if matched, _ := path.Match(pattern, action); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessFromPolicy adds the grants of statements allowing everyone. Statements
+// with conditions, e.g. on the source IP, are not taken as public.
+func accessFromPolicy(document []byte, access *BucketAccess) error {
+	if len(document) == 0 {
+		return nil
+	}
+	var policy policyDocument
+	if err := json.Unmarshal(document, &policy); err != nil {
+		return fmt.Errorf("parsing the bucket policy: %w", err)
+	}
+
+	for _, statement := range policy.Statement {
+		if !strings.EqualFold(statement.Effect, "Allow") || !anyone(statement.Principal) || len(statement.Condition) > 0 {
+			continue
+		}
+		if grantsAny(statement, readActions) && !access.PublicRead {
+			access.PublicRead = true
+			access.Grants = append(access.Grants, "policy:read")
+		}
+		if grantsAny(statement, writeActions) && !access.PublicWrite {
+			access.PublicWrite = true
+			access.Grants = append(access.Grants, "policy:write")
+		}
+	}
+	return nil
+}
+
+// publicBucketTracker remembers the public buckets of the previous cycle, to
+// report the buckets that became public since
+type publicBucketTracker struct {
+	public map[string]*BucketAccess // nil until the first cycle
+}
+
+// collectPublicBuckets returns the access of all public buckets in the
+// stored metrics, by KV key
+func collectPublicBuckets(bucketMetrics nats.KeyValue) (map[string]*BucketAccess, error) {
+	keys, err := bucketMetrics.Keys()
+	if err != nil {
+		if errors.Is(err, nats.ErrNoKeysFound) {
+			return map[string]*BucketAccess{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch keys from bucket metrics: %w", err)
+	}
+
+	public := map[string]*BucketAccess{}
+	for _, key := range keys {
+		entry, err := bucketMetrics.Get(key)
+		if err != nil {
+			if !errors.Is(err, nats.ErrKeyNotFound) {
+				log.Warn().Str("key", key).Err(err).Msg("Failed to fetch bucket metric")
+			}
+			continue
+		}
+
+		var metrics UserBucketMetrics
+		if err := json.Unmarshal(entry.Value(), &metrics); err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to unmarshal bucket metric")
+			continue
+		}
+		if metrics.Access.Public() {
+			public[key] = metrics.Access
+		}
+	}
+	return public, nil
+}
+
+// newlyPublic returns the keys of the buckets public now but not in the
+// previous cycle. Before the first cycle is remembered it returns none, so
+// buckets public before the start are not reported.
+func (t *publicBucketTracker) newlyPublic(current map[string]*BucketAccess) []string {
+	if t.public == nil {
+		return nil
+	}
+
+	var keys []string
+	for key := range current {
+		if _, ok := t.public[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// remember keeps the public buckets of a cycle, once its events are published
+func (t *publicBucketTracker) remember(current map[string]*BucketAccess) {
+	t.public = current
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+)
+
+func TestAccessFromACL(t *testing.T) {
+	var acl rgwadmin.BucketPolicy
+	if err := json.Unmarshal([]byte(`{
+		"acl": {
+			"acl_group_map": [{"group": 1, "acl": 1}],
+			"grant_map": [
+				{"id": "owner", "grant": {"type": {"type": 0}, "id": "owner", "permission": {"flags": 15}}},
+				{"id": "", "grant": {"type": {"type": 2}, "group": 2, "permission": {"flags": 2}}}
+			]
+		},
+		"owner": {"id": "owner", "display_name": "Owner"}
+	}`), &acl); err != nil {
+		t.Fatalf("unmarshal ACL: %v", err)
+	}
+
+	access := &BucketAccess{}
+	accessFromACL(acl, access)
+
+	want := &BucketAccess{
+		PublicRead:    true,
+		Authenticated: true,
+		Grants:        []string{"acl:AllUsers:READ", "acl:AuthenticatedUsers"},
+	}
+	if !reflect.DeepEqual(access, want) {
+		t.Fatalf("expected %+v, got %+v", want, access)
+	}
+}
+
+func TestAccessFromPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		read     bool
+		write    bool
+	}{
+		{
+			name:     "no policy",
+			document: "",
+		},
+		{
+			name:     "public read",
+			document: `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}}`,
+			read:     true,
+		},
+		{
+			name:     "wildcard actions",
+			document: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam:::user/alice", "*"]}, "Action": ["s3:*"]}]}`,
+			read:     true,
+			write:    true,
+		},
+		{
+			name:     "named principal",
+			document: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam:::user/alice"}, "Action": "s3:*"}]}`,
+		},
+		{
+			name:     "conditional",
+			document: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}}]}`,
+		},
+		{
+			name:     "deny",
+			document: `{"Statement": [{"Effect": "Deny", "Principal": "*", "Action": "s3:PutObject"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access := &BucketAccess{}
+			if err := accessFromPolicy([]byte(tt.document), access); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if access.PublicRead != tt.read || access.PublicWrite != tt.write {
+				t.Fatalf("expected read=%v write=%v, got %+v", tt.read, tt.write, access)
+			}
+		})
+	}
+
+	if err := accessFromPolicy([]byte(`{"Statement": 1}`), &BucketAccess{}); err == nil {
+		t.Fatalf("expected an error for a malformed policy")
+	}
+}
+
+func TestPublicBucketTracker(t *testing.T) {
+	tracker := &publicBucketTracker{}
+	public := &BucketAccess{PublicRead: true}
+
+	first := map[string]*BucketAccess{"t.u.a": public}
+	if keys := tracker.newlyPublic(first); keys != nil {
+		t.Fatalf("expected no buckets before the first cycle, got %v", keys)
+	}
+	tracker.remember(first)
+
+	second := map[string]*BucketAccess{"t.u.a": public, "t.u.c": public, "t.u.b": public}
+	if keys := tracker.newlyPublic(second); !reflect.DeepEqual(keys, []string{"t.u.b", "t.u.c"}) {
+		t.Fatalf("expected the new public buckets, got %v", keys)
+	}
+	if keys := tracker.newlyPublic(second); len(keys) != 2 {
+		t.Fatalf("expected the buckets to be reported until remembered, got %v", keys)
+	}
+	tracker.remember(second)
+
+	if keys := tracker.newlyPublic(map[string]*BucketAccess{"t.u.a": public}); len(keys) != 0 {
+		t.Fatalf("expected no new buckets, got %v", keys)
+	}
+}
+
+func TestProcessBucketMetrics_Access(t *testing.T) {
+	key := BuildUserTenantBucketKey("user-a", "tenant-a", "bucket-a")
+	record := bucketRecord{
+		Bucket: rgwadmin.Bucket{Bucket: "bucket-a", Owner: "user-a$tenant-a", Tenant: "tenant-a"},
+		Access: &BucketAccess{PublicWrite: true, Grants: []string{"policy:write"}},
+	}
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("marshal bucket: %v", err)
+	}
+
+	bucketMetrics := newTestKV("bucket_metrics", nil)
+	processBucketMetrics(key, newTestKV("bucket_data", map[string][]byte{key: recordJSON}), newTestKV("user_usage_data", nil), bucketMetrics, 0, 0, false)
+
+	public, err := collectPublicBuckets(bucketMetrics)
+	if err != nil {
+		t.Fatalf("collect public buckets: %v", err)
+	}
+	if access, ok := public[key]; !ok || !access.PublicWrite {
+		t.Fatalf("expected %s to be public, got %+v", key, public)
+	}
+}
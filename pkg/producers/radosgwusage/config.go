@@ -4,12 +4,24 @@
 
 package radosgwusage
 
+import (
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+)
+
 type RadosGWUsageConfig struct {
+	Source                  string // SourceAdminAPI or SourceCLI
 	AdminURL                string
 	AccessKey               string
 	SecretKey               string
+	RadosGWAdminBinary      string // radosgw-admin of SourceCLI
+	CephConf                string // --conf of radosgw-admin, its default if empty
+	CephName                string // --name of radosgw-admin, e.g. client.admin
+	CephKeyring             string // --keyring of radosgw-admin
 	Prometheus              bool
 	PrometheusPort          int
+	RemoteWrite             remotewrite.Config // Pushes the Prometheus metrics when URL is set
 	NodeName                string
 	InstanceID              string
 	CooldownInterval        int // in seconds
@@ -18,4 +30,39 @@ type RadosGWUsageConfig struct {
 	SyncExternalNats        bool   // Use external NATS for sync control
 	SyncControlURL          string // URL for the external NATS server (if applicable)
 	SyncControlBucketPrefix string // NATS-KV bucket prefix for sync data
+	ReshardObjectsPerShard  int    // Objects-per-shard threshold above which resharding is recommended (0 disables)
+	ReshardNotify           bool   // Publish a NATS event listing buckets that need resharding
+	LargeOmapKeysPerShard   int    // OMAP keys per bucket index shard above which the index is a large OMAP object (0 disables)
+	AuditBucketAccess       bool   // Fetch the ACLs and bucket policies and export the public access of the buckets
+	PublicBucketNotify      bool   // Publish a NATS event when a bucket becomes public
+	OpsMetricsJoin          bool   // Join the traffic and latency of the ops log metrics into the user and bucket metrics
+	OpsMetricsSubject       string // NATS subject of the ops log metrics
+	AdminAPIFaults          string // Faults injected into the admin API requests for testing, see ParseAdminAPIFaults
+	DryRun                  bool   // Run one collection cycle in memory, print the metrics as JSON and exit
+
+	// Multi-target mode, one collection cycle per scrape of /probe?cluster=<cluster>
+	ProbeTargets string // JSON file of the targets, see LoadProbeTargets
+
+	// Rook mode, the CephObjectStores of the Kubernetes cluster are probe
+	// targets too
+	RookDiscovery         bool
+	RookNamespace         string        // Namespace of the stores, all namespaces if empty
+	RookDiscoveryInterval time.Duration // Interval the stores are listed again
+
+	// Growth of the NATS-KV buckets
+	KVTTLs            string        // TTLs of the buckets, see ParseKVTTLs
+	KVCompactAge      time.Duration // Keys not written for longer are purged (0 disables the compaction)
+	KVCompactInterval time.Duration // Interval of the compaction
+	KVWriteWorkers    int           // Parallel writes of the batches to the buckets
+
+	// Tenant usage anomalies, published as NATS events
+	TenantAnomalyNotify          bool // Publish a NATS event when the storage of a tenant grows or shrinks anomalously
+	TenantAnomalyHistory         int  // Cycles the growth of a tenant is compared with
+	TenantAnomalyGrowthFactor    int  // Growth above this many times the average growth of the history is anomalous
+	TenantAnomalyDeletionPercent int  // Loss of this percentage of the bytes or objects of a tenant is anomalous
+	TenantAnomalyMinGiB          int  // Growth or loss below is never anomalous
+
+	// Percent of the users and buckets a cycle must collect, below it the
+	// metrics and events derived from them are marked partial (0 never marks)
+	CompletenessPercent int
 }
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// dryRunReport is the output of a dry run
+type dryRunReport struct {
+	Users   []UserLevelMetrics  `json:"users"`
+	Buckets []UserBucketMetrics `json:"buckets"`
+	Cluster dryRunCluster       `json:"cluster"`
+}
+
+// dryRunCluster sums up the metrics of the cycle and how long it took
+type dryRunCluster struct {
+	ClusterID         string         `json:"cluster_id"`
+	Users             int            `json:"users"`
+	Buckets           int            `json:"buckets"`
+	ObjectsTotal      uint64         `json:"objects_total"`
+	DataSizeTotal     uint64         `json:"data_size_total"`
+	ReshardNeeded     int            `json:"reshard_needed"` // Buckets above --reshard-objects-per-shard
+	LargeOmap         int            `json:"large_omap"`     // Buckets above --large-omap-keys-per-shard
+	CollectionSeconds float64        `json:"collection_seconds"`
+	Stages            []dryRunTiming `json:"stages"`
+}
+
+// dryRunTiming is the duration of a stage of the cycle
+type dryRunTiming struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+// RunDryRun runs one collection cycle and writes the user, bucket and
+// cluster metrics to out as JSON. The data of the cycle is kept in memory:
+// nothing is stored in NATS KV, exported to Prometheus or published.
+func RunDryRun(cfg RadosGWUsageConfig, out io.Writer) error {
+	if cfg.AdminAPIFaults != "" {
+		log.Warn().Str("admin_api_faults", cfg.AdminAPIFaults).Msg("Injecting faults into the admin API requests, do not use in production")
+	}
+
+	kv := func(name string) *memoryKV {
+		return newMemoryKV(fmt.Sprintf("%s_%s", cfg.SyncControlBucketPrefix, name))
+	}
+	userData, userUsageData, bucketData := kv("user_data"), kv("user_usage_data"), kv("bucket_data")
+	userMetrics, bucketMetrics := kv("user_metrics"), kv("bucket_metrics")
+
+	stages := syncStages(cfg, &PrysmStatus{}, userData, userUsageData, bucketData, userMetrics, bucketMetrics)
+	var timings []dryRunTiming
+	for i, stage := range stages {
+		run := stage.run
+		stages[i].run = func(ctx context.Context) error {
+			start := time.Now()
+			err := run(ctx)
+			timings = append(timings, dryRunTiming{Name: stage.name, Seconds: time.Since(start).Seconds()})
+			return err
+		}
+	}
+
+	start := time.Now()
+	if err := runCollectionCycle(context.Background(), stages); err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	report, err := newDryRunReport(cfg, userMetrics, bucketMetrics)
+	if err != nil {
+		return err
+	}
+	report.Cluster.CollectionSeconds = elapsed.Seconds()
+	report.Cluster.Stages = timings
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// newDryRunReport reads the user and bucket metrics of the cycle, ordered
+// by their keys, and sums them up for the cluster
+func newDryRunReport(cfg RadosGWUsageConfig, userMetrics, bucketMetrics nats.KeyValue) (dryRunReport, error) {
+	report := dryRunReport{
+		Users:   []UserLevelMetrics{},
+		Buckets: []UserBucketMetrics{},
+		Cluster: dryRunCluster{ClusterID: cfg.ClusterID},
+	}
+
+	err := readSortedKV(userMetrics, func(user UserLevelMetrics) {
+		report.Users = append(report.Users, user)
+		report.Cluster.ObjectsTotal += user.ObjectsTotal
+		report.Cluster.DataSizeTotal += user.DataSizeTotal
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to read the user metrics: %w", err)
+	}
+	err = readSortedKV(bucketMetrics, func(bucket UserBucketMetrics) {
+		report.Buckets = append(report.Buckets, bucket)
+		if bucket.ReshardNeeded {
+			report.Cluster.ReshardNeeded++
+		}
+		if bucket.LargeOmap {
+			report.Cluster.LargeOmap++
+		}
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to read the bucket metrics: %w", err)
+	}
+
+	report.Cluster.Users = len(report.Users)
+	report.Cluster.Buckets = len(report.Buckets)
+	return report, nil
+}
+
+// readSortedKV decodes the records of kv in the order of their keys
+func readSortedKV[T any](kv nats.KeyValue, read func(T)) error {
+	keys, err := kv.Keys()
+	if err != nil {
+		if errors.Is(err, nats.ErrNoKeysFound) {
+			return nil
+		}
+		return err
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entry, err := kv.Get(key)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", key, err)
+		}
+		var record T
+		if err := json.Unmarshal(entry.Value(), &record); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+		read(record)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewDryRunReport(t *testing.T) {
+	seed := func(records map[string]any) map[string][]byte {
+		data := map[string][]byte{}
+		for key, record := range records {
+			value, err := json.Marshal(record)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			data[key] = value
+		}
+		return data
+	}
+	userMetrics := newTestKV("user_metrics", seed(map[string]any{
+		"user-b": UserLevelMetrics{User: "user-b", BucketsTotal: 1, ObjectsTotal: 5, DataSizeTotal: 500},
+		"user-a": UserLevelMetrics{User: "user-a", BucketsTotal: 1, ObjectsTotal: 2, DataSizeTotal: 200},
+	}))
+	bucketMetrics := newTestKV("bucket_metrics", seed(map[string]any{
+		"user-b.photos": UserBucketMetrics{BucketID: "photos", User: "user-b", ObjectCount: 5, ReshardNeeded: true},
+		"user-a.logs":   UserBucketMetrics{BucketID: "logs", User: "user-a", ObjectCount: 2},
+	}))
+
+	report, err := newDryRunReport(RadosGWUsageConfig{ClusterID: "rgw-a"}, userMetrics, bucketMetrics)
+	if err != nil {
+		t.Fatalf("new dry run report: %v", err)
+	}
+	if len(report.Users) != 2 || report.Users[0].User != "user-a" || report.Users[1].User != "user-b" {
+		t.Fatalf("expected the users ordered by key, got %+v", report.Users)
+	}
+	if len(report.Buckets) != 2 || report.Buckets[0].BucketID != "logs" || report.Buckets[1].BucketID != "photos" {
+		t.Fatalf("expected the buckets ordered by key, got %+v", report.Buckets)
+	}
+	want := dryRunCluster{ClusterID: "rgw-a", Users: 2, Buckets: 2, ObjectsTotal: 7, DataSizeTotal: 700, ReshardNeeded: 1}
+	if report.Cluster.ClusterID != want.ClusterID || report.Cluster.Users != want.Users || report.Cluster.Buckets != want.Buckets ||
+		report.Cluster.ObjectsTotal != want.ObjectsTotal || report.Cluster.DataSizeTotal != want.DataSizeTotal || report.Cluster.ReshardNeeded != want.ReshardNeeded {
+		t.Fatalf("unexpected cluster summary %+v, want %+v", report.Cluster, want)
+	}
+}
+
+func TestNewDryRunReport_Empty(t *testing.T) {
+	report, err := newDryRunReport(RadosGWUsageConfig{}, newTestKV("user_metrics", nil), newTestKV("bucket_metrics", nil))
+	if err != nil {
+		t.Fatalf("new dry run report: %v", err)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if string(decoded["users"]) != "[]" || string(decoded["buckets"]) != "[]" {
+		t.Fatalf("expected empty lists rather than null, got %s", data)
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Faults injected into the admin API requests
+const (
+	FaultTimeout = "timeout" // The request hangs until it times out
+	FaultError   = "error"   // RGW answers 500 InternalError
+	FaultPartial = "partial" // The response is cut in the middle
+)
+
+// faultTimeoutFallback ends a timeout fault of a request without a deadline
+const faultTimeoutFallback = 30 * time.Second
+
+// AdminAPIFaults are the probabilities of the faults injected into every
+// admin API request, to try the alerting and the degraded mode before a real
+// outage. At most one fault is injected into a request.
+type AdminAPIFaults struct {
+	Timeout float64
+	Error   float64
+	Partial float64
+}
+
+// ParseAdminAPIFaults parses faults as comma-separated fault=probability
+// pairs, e.g. "timeout=0.1,error=0.05,partial=0.2". Empty injects none.
+func ParseAdminAPIFaults(faults string) (AdminAPIFaults, error) {
+	var f AdminAPIFaults
+	for _, pair := range strings.Split(faults, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return AdminAPIFaults{}, fmt.Errorf("invalid fault %q, expected fault=probability", pair)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || p < 0 || p > 1 {
+			return AdminAPIFaults{}, fmt.Errorf("invalid probability %q of %s, expected 0 to 1", value, name)
+		}
+		switch strings.TrimSpace(name) {
+		case FaultTimeout:
+			f.Timeout = p
+		case FaultError:
+			f.Error = p
+		case FaultPartial:
+			f.Partial = p
+		default:
+			return AdminAPIFaults{}, fmt.Errorf("unknown fault %q, expected %s, %s or %s", name, FaultTimeout, FaultError, FaultPartial)
+		}
+	}
+	if f.Timeout+f.Error+f.Partial > 1 {
+		return AdminAPIFaults{}, fmt.Errorf("probabilities of the faults sum to more than 1")
+	}
+	return f, nil
+}
+
+// Enabled reports whether any fault is injected
+func (f AdminAPIFaults) Enabled() bool {
+	return f.Timeout > 0 || f.Error > 0 || f.Partial > 0
+}
+
+// pick returns the fault of a request by a random number in [0, 1), none
+// with an empty string
+func (f AdminAPIFaults) pick(r float64) string {
+	switch {
+	case r < f.Timeout:
+		return FaultTimeout
+	case r < f.Timeout+f.Error:
+		return FaultError
+	case r < f.Timeout+f.Error+f.Partial:
+		return FaultPartial
+	default:
+		return ""
+	}
+}
+
+// faultTransport injects faults into the requests of next
+type faultTransport struct {
+	next   http.RoundTripper
+	faults AdminAPIFaults
+	random func() float64
+}
+
+// injectFaults wraps next with the faults, next itself if there are none
+func injectFaults(next http.RoundTripper, faults AdminAPIFaults) http.RoundTripper {
+	if !faults.Enabled() {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &faultTransport{next: next, faults: faults, random: rand.Float64}
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.faults.pick(t.random())
+	if fault == "" {
+		return t.next.RoundTrip(req)
+	}
+	injectedFaults.WithLabelValues(fault).Inc()
+	log.Debug().Str("fault", fault).Str("path", req.URL.Path).Msg("Injecting admin API fault")
+
+	switch fault {
+	case FaultTimeout:
+		timer := time.NewTimer(faultTimeoutFallback)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+			return nil, fmt.Errorf("injected fault: %s timed out", req.URL.Path)
+		}
+	case FaultError:
+		body := `{"Code":"InternalError","Message":"injected fault"}`
+		return &http.Response{
+			Status:        "500 Internal Server Error",
+			StatusCode:    http.StatusInternalServerError,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	default:
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		data = data[:len(data)/2]
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+}
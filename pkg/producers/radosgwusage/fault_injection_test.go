@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAdminAPIFaults(t *testing.T) {
+	faults, err := ParseAdminAPIFaults(" timeout=0.1, error=0.05,partial=0.2")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if faults != (AdminAPIFaults{Timeout: 0.1, Error: 0.05, Partial: 0.2}) {
+		t.Fatalf("unexpected faults %+v", faults)
+	}
+
+	if faults, err := ParseAdminAPIFaults(""); err != nil || faults.Enabled() {
+		t.Fatalf("expected no faults, got %+v, %v", faults, err)
+	}
+	for _, invalid := range []string{"timeout", "timeout=1.5", "error=-0.1", "slow=0.1", "timeout=0.6,error=0.6"} {
+		if _, err := ParseAdminAPIFaults(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestFaultTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"user_id":"user-a"},{"user_id":"user-b"}]`))
+	}))
+	defer server.Close()
+
+	get := func(fault string, timeout time.Duration) (*http.Response, error) {
+		transport := &faultTransport{next: http.DefaultTransport, faults: AdminAPIFaults{Timeout: 0.25, Error: 0.25, Partial: 0.25}}
+		// Pick the fault by the random number of its range
+		transport.random = func() float64 {
+			return map[string]float64{FaultTimeout: 0.1, FaultError: 0.3, FaultPartial: 0.6, "": 0.9}[fault]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		t.Cleanup(cancel)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/admin/user", nil)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		return (&http.Client{Transport: transport}).Do(req)
+	}
+	body := func(resp *http.Response) string {
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return string(data)
+	}
+
+	resp, err := get("", time.Second)
+	if err != nil || body(resp) != `[{"user_id":"user-a"},{"user_id":"user-b"}]` {
+		t.Fatalf("expected the request to pass, got %v", err)
+	}
+
+	if _, err := get(FaultTimeout, 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	resp, err = get(FaultError, time.Second)
+	if err != nil || resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected a 500, got %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = get(FaultPartial, time.Second)
+	if err != nil {
+		t.Fatalf("partial: %v", err)
+	}
+	if got := body(resp); got != `[{"user_id":"user-a"}` {
+		t.Fatalf("expected half of the response, got %q", got)
+	}
+}
+
+func TestInjectFaults_None(t *testing.T) {
+	if injectFaults(http.DefaultTransport, AdminAPIFaults{}) != http.DefaultTransport {
+		t.Fatal("expected the transport unchanged without faults")
+	}
+}
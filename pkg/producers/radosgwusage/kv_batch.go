@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// maxKVWriteErrors caps the errors of the failed writes reported by
+// putKVBatch, a lost KV fails all of them the same way
+const maxKVWriteErrors = 5
+
+// kvWrite is a key to put into a KV bucket
+type kvWrite struct {
+	key   string
+	value []byte
+}
+
+// putKVBatch puts the writes into the bucket with that many workers. It
+// returns how many failed and the errors of the first of them.
+func putKVBatch(kv nats.KeyValue, writes []kvWrite, workers int) (int, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	bucket := kv.Bucket()
+	start := time.Now()
+	defer func() {
+		kvBatchDuration.WithLabelValues(bucket).Set(time.Since(start).Seconds())
+	}()
+
+	var (
+		mu     sync.Mutex
+		failed int
+		errs   []error
+		wg     sync.WaitGroup
+	)
+	writeCh := make(chan kvWrite)
+	for i := 0; i < workers && i < len(writes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for write := range writeCh {
+				putStart := time.Now()
+				_, err := kv.Put(write.key, write.value)
+				kvPutDuration.WithLabelValues(bucket).Observe(time.Since(putStart).Seconds())
+				if err == nil {
+					kvWrites.WithLabelValues(bucket, "success").Inc()
+					continue
+				}
+				kvWrites.WithLabelValues(bucket, "failure").Inc()
+				mu.Lock()
+				failed++
+				if len(errs) < maxKVWriteErrors {
+					errs = append(errs, fmt.Errorf("key %s: %w", write.key, err))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, write := range writes {
+		writeCh <- write
+	}
+	close(writeCh)
+	wg.Wait()
+
+	if failed == 0 {
+		return 0, nil
+	}
+	return failed, fmt.Errorf("%d of %d KV writes to %s failed: %w", failed, len(writes), bucket, errors.Join(errs...))
+}
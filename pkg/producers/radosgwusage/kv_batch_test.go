@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// failingKV fails the puts of the keys with the prefix
+type failingKV struct {
+	*memoryKV
+	prefix string
+}
+
+func (kv *failingKV) Put(key string, value []byte) (uint64, error) {
+	if strings.HasPrefix(key, kv.prefix) {
+		return 0, nats.ErrConnectionClosed
+	}
+	return kv.memoryKV.Put(key, value)
+}
+
+func TestPutKVBatch(t *testing.T) {
+	kv := newMemoryKV("batch_usage_data")
+	var writes []kvWrite
+	for i := 0; i < 100; i++ {
+		writes = append(writes, kvWrite{key: fmt.Sprintf("user-%d", i), value: []byte("{}")})
+	}
+
+	succeeded := testutil.ToFloat64(kvWrites.WithLabelValues("batch_usage_data", "success"))
+	failed, err := putKVBatch(kv, writes, 8)
+	if failed != 0 || err != nil {
+		t.Fatalf("expected all writes to succeed, got %d failed: %v", failed, err)
+	}
+	if len(kv.data) != 100 {
+		t.Fatalf("expected 100 keys, got %d", len(kv.data))
+	}
+	if got := testutil.ToFloat64(kvWrites.WithLabelValues("batch_usage_data", "success")); got != succeeded+100 {
+		t.Fatalf("expected 100 more successful writes, got %v", got-succeeded)
+	}
+}
+
+func TestPutKVBatch_Failures(t *testing.T) {
+	kv := &failingKV{memoryKV: newMemoryKV("failing_usage_data"), prefix: "user-1"}
+	var writes []kvWrite
+	for i := 0; i < 30; i++ {
+		writes = append(writes, kvWrite{key: fmt.Sprintf("user-%d", i), value: []byte("{}")})
+	}
+
+	// user-1 and user-10 to user-19 fail, the others are still written
+	failed, err := putKVBatch(kv, writes, 4)
+	if failed != 11 || err == nil {
+		t.Fatalf("expected 11 failed writes, got %d: %v", failed, err)
+	}
+	if len(kv.data) != 19 {
+		t.Fatalf("expected 19 keys, got %d", len(kv.data))
+	}
+	if !strings.Contains(err.Error(), "11 of 30 KV writes to failing_usage_data failed") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if n := strings.Count(err.Error(), nats.ErrConnectionClosed.Error()); n != maxKVWriteErrors {
+		t.Fatalf("expected %d errors, got %d: %v", maxKVWriteErrors, n, err)
+	}
+}
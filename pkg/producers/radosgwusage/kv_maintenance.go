@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// kvBuckets are the names of the NATS KV buckets of the exporter, without
+// the --sync-control-bucket-prefix
+var kvBuckets = []string{
+	"user_data",       // User information
+	"user_usage_data", // User Usage information
+	"bucket_data",     // Bucket information
+	"user_metrics",    // User metrics
+	"bucket_metrics",  // Bucket metrics
+	"cluster_metrics", // Cluster metrics
+}
+
+// ParseKVTTLs parses the TTLs of the KV buckets from comma-separated
+// bucket=duration pairs, e.g. user_usage_data=72h,bucket_data=72h. The
+// buckets are named without the prefix, a TTL of 0 keeps the keys forever.
+func ParseKVTTLs(ttls string) (map[string]time.Duration, error) {
+	parsed := map[string]time.Duration{}
+	for _, pair := range strings.Split(ttls, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid TTL %q, expected bucket=duration", pair)
+		}
+		name = strings.TrimSpace(name)
+		if !slices.Contains(kvBuckets, name) {
+			return nil, fmt.Errorf("unknown bucket %q, expected one of %s", name, strings.Join(kvBuckets, ", "))
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid TTL %q of %s, expected a duration like 72h", value, name)
+		}
+		parsed[name] = ttl
+	}
+	return parsed, nil
+}
+
+// applyKVTTL sets the TTL of an existing bucket, the TTL of a new bucket is
+// set on creation. The keys of a KV bucket are the messages of its stream,
+// which expire after the MaxAge of the stream.
+func applyKVTTL(js nats.JetStreamContext, kv nats.KeyValue, ttl time.Duration) error {
+	status, err := kv.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get the status of %s: %w", kv.Bucket(), err)
+	}
+	if status.TTL() == ttl {
+		return nil
+	}
+	info, err := js.StreamInfo("KV_" + kv.Bucket())
+	if err != nil {
+		return fmt.Errorf("failed to get the stream of %s: %w", kv.Bucket(), err)
+	}
+	info.Config.MaxAge = ttl
+	if _, err := js.UpdateStream(&info.Config); err != nil {
+		return fmt.Errorf("failed to update the TTL of %s: %w", kv.Bucket(), err)
+	}
+	log.Info().Str("bucket", kv.Bucket()).Dur("ttl", ttl).Msg("Updated the TTL of the KV bucket")
+	return nil
+}
+
+// compactKV purges the keys of kv last written more than maxAge before now,
+// e.g. of users and buckets deleted while the exporter was down, then the
+// delete markers left by the purges and the reconciliations of the cycles.
+// It returns the number of keys purged.
+func compactKV(kv nats.KeyValue, maxAge time.Duration, now time.Time) (int, error) {
+	keys, err := kv.Keys()
+	if err != nil && !errors.Is(err, nats.ErrNoKeysFound) {
+		return 0, fmt.Errorf("failed to list the keys of %s: %w", kv.Bucket(), err)
+	}
+
+	purged := 0
+	for _, key := range keys {
+		entry, err := kv.Get(key)
+		if err != nil {
+			if errors.Is(err, nats.ErrKeyNotFound) {
+				continue // Deleted since the listing
+			}
+			return purged, fmt.Errorf("failed to get %s of %s: %w", key, kv.Bucket(), err)
+		}
+		if now.Sub(entry.Created()) <= maxAge {
+			continue
+		}
+		if err := kv.Purge(key); err != nil {
+			return purged, fmt.Errorf("failed to purge %s of %s: %w", key, kv.Bucket(), err)
+		}
+		purged++
+	}
+
+	if err := kv.PurgeDeletes(); err != nil {
+		return purged, fmt.Errorf("failed to purge the delete markers of %s: %w", kv.Bucket(), err)
+	}
+	return purged, nil
+}
+
+// compactKeyValueStores compacts every KV bucket of the exporter
+func compactKeyValueStores(kvStores map[string]nats.KeyValue, maxAge time.Duration) {
+	for name, kv := range kvStores {
+		purged, err := compactKV(kv, maxAge, time.Now())
+		if purged > 0 {
+			kvPurgedKeys.WithLabelValues(name).Add(float64(purged))
+			log.Info().Str("bucket", name).Int("purged", purged).Msg("Purged stale keys of the KV bucket")
+		}
+		if err != nil {
+			log.Error().Err(err).Str("bucket", name).Msg("Failed to compact the KV bucket")
+		}
+	}
+}
+
+// compactionLoop compacts the KV buckets every interval until ctx is
+// canceled
+func compactionLoop(ctx context.Context, kvStores map[string]nats.KeyValue, cfg RadosGWUsageConfig) {
+	ticker := time.NewTicker(cfg.KVCompactInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			compactKeyValueStores(kvStores, cfg.KVCompactAge)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// populateKVStatus exports the entries and bytes of every KV bucket of the
+// exporter
+func populateKVStatus(kvStores map[string]nats.KeyValue) error {
+	var errs []error
+	for name, kv := range kvStores {
+		status, err := kv.Status()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get the status of %s: %w", name, err))
+			continue
+		}
+		kvEntries.WithLabelValues(name).Set(float64(status.Values()))
+		kvBytes.WithLabelValues(name).Set(float64(status.Bytes()))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseKVTTLs(t *testing.T) {
+	ttls, err := ParseKVTTLs(" user_usage_data=72h, bucket_data=0s,")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(ttls) != 2 || ttls["user_usage_data"] != 72*time.Hour || ttls["bucket_data"] != 0 {
+		t.Fatalf("unexpected TTLs %v", ttls)
+	}
+
+	if ttls, err := ParseKVTTLs(""); err != nil || len(ttls) != 0 {
+		t.Fatalf("expected no TTLs, got %v, %v", ttls, err)
+	}
+	for _, invalid := range []string{"user_data", "user_data=3d", "user_data=-1h", "sync_user_data=1h"} {
+		if _, err := ParseKVTTLs(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestCompactKV(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	kv := newMemoryKV("sync_user_data")
+
+	kv.now = func() time.Time { return now.Add(-48 * time.Hour) }
+	_, _ = kv.Put("deleted-user", []byte("{}"))
+	kv.now = func() time.Time { return now.Add(-time.Hour) }
+	_, _ = kv.Put("active-user", []byte("{}"))
+
+	purged, err := compactKV(kv, 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 key purged, got %d", purged)
+	}
+	if _, err := kv.Get("deleted-user"); err != nats.ErrKeyNotFound {
+		t.Fatalf("expected the stale key to be purged, got %v", err)
+	}
+	if _, err := kv.Get("active-user"); err != nil {
+		t.Fatalf("expected the recent key to be kept, got %v", err)
+	}
+
+	if purged, err := compactKV(newMemoryKV("sync_bucket_data"), 24*time.Hour, now); err != nil || purged != 0 {
+		t.Fatalf("expected an empty bucket to compact to nothing, got %d, %v", purged, err)
+	}
+}
+
+func TestPopulateKVStatus(t *testing.T) {
+	kvEntries.Reset()
+	kvBytes.Reset()
+
+	kvStores := map[string]nats.KeyValue{
+		"sync_user_data":   newTestKV("sync_user_data", map[string][]byte{"a": []byte("1234"), "b": []byte("56")}),
+		"sync_bucket_data": newTestKV("sync_bucket_data", nil),
+	}
+	if err := populateKVStatus(kvStores); err != nil {
+		t.Fatalf("populate: %v", err)
+	}
+	if got := testutil.ToFloat64(kvEntries.WithLabelValues("sync_user_data")); got != 2 {
+		t.Errorf("expected 2 entries, got %v", got)
+	}
+	if got := testutil.ToFloat64(kvBytes.WithLabelValues("sync_user_data")); got != 6 {
+		t.Errorf("expected 6 bytes, got %v", got)
+	}
+	if got := testutil.ToFloat64(kvEntries.WithLabelValues("sync_bucket_data")); got != 0 {
+		t.Errorf("expected no entries, got %v", got)
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"sync"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+)
+
+// maintenanceKV is a KV bucket of the external NATS servers replaced by an
+// in-memory copy while the read-only maintenance mode is on. Every cycle
+// rewrites the buckets, so the cycles in maintenance fill the copy and the
+// metrics keep being exported without writing to NATS. Leaving the mode
+// drops the copy, the next cycle writes the external bucket again.
+type maintenanceKV struct {
+	nats.KeyValue
+
+	mu     sync.Mutex
+	memory *memoryKV // nil unless in read-only mode
+}
+
+func newMaintenanceKV(kv nats.KeyValue) *maintenanceKV {
+	return &maintenanceKV{KeyValue: kv}
+}
+
+// current returns the bucket of the mode
+func (kv *maintenanceKV) current() nats.KeyValue {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if !natsutil.ReadOnly() {
+		kv.memory = nil
+		return kv.KeyValue
+	}
+	if kv.memory == nil {
+		kv.memory = newMemoryKV(kv.Bucket())
+	}
+	return kv.memory
+}
+
+func (kv *maintenanceKV) Get(key string) (nats.KeyValueEntry, error) {
+	return kv.current().Get(key)
+}
+
+func (kv *maintenanceKV) Put(key string, value []byte) (uint64, error) {
+	return kv.current().Put(key, value)
+}
+
+func (kv *maintenanceKV) Delete(key string, opts ...nats.DeleteOpt) error {
+	return kv.current().Delete(key, opts...)
+}
+
+func (kv *maintenanceKV) Purge(key string, opts ...nats.DeleteOpt) error {
+	return kv.current().Purge(key, opts...)
+}
+
+func (kv *maintenanceKV) PurgeDeletes(opts ...nats.PurgeOpt) error {
+	return kv.current().PurgeDeletes(opts...)
+}
+
+func (kv *maintenanceKV) Keys(opts ...nats.WatchOpt) ([]string, error) {
+	return kv.current().Keys(opts...)
+}
+
+func (kv *maintenanceKV) Status() (nats.KeyValueStatus, error) {
+	return kv.current().Status()
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+)
+
+func TestMaintenanceKV(t *testing.T) {
+	external := newTestKV("sync_user_data", map[string][]byte{"alice": []byte("1")})
+	kv := newMaintenanceKV(external)
+	defer natsutil.SetReadOnly(false)
+
+	natsutil.SetReadOnly(true)
+	if _, err := kv.Get("alice"); err != nats.ErrKeyNotFound {
+		t.Fatalf("expected an empty copy in read-only mode, got %v", err)
+	}
+	if _, err := kv.Put("bob", []byte("2")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if entry, err := kv.Get("bob"); err != nil || string(entry.Value()) != "2" {
+		t.Fatalf("expected the copy to hold bob, got %v", err)
+	}
+	if _, ok := external.data["bob"]; ok {
+		t.Fatalf("expected no write to the external bucket in read-only mode")
+	}
+
+	natsutil.SetReadOnly(false)
+	if entry, err := kv.Get("alice"); err != nil || string(entry.Value()) != "1" {
+		t.Fatalf("expected the external bucket back, got %v", err)
+	}
+	if _, err := kv.Put("carol", []byte("3")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, ok := external.data["carol"]; !ok {
+		t.Fatalf("expected the write to reach the external bucket")
+	}
+
+	natsutil.SetReadOnly(true)
+	if _, err := kv.Get("bob"); err != nats.ErrKeyNotFound {
+		t.Fatalf("expected a fresh copy in the next maintenance, got %v", err)
+	}
+}
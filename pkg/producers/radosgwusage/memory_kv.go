@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+var errMemoryKVNotSupported = errors.New("not supported by the in-memory KV")
+
+// memoryKV is a nats.KeyValue in memory without history, for the dry run,
+// the read-only maintenance mode and the tests. Watching and listing the
+// keys is not supported, the collection stages only get, put, delete and
+// list keys.
+type memoryKV struct {
+	bucket string
+
+	mu       sync.Mutex
+	data     map[string][]byte
+	created  map[string]time.Time
+	revision uint64
+	now      func() time.Time // Time of the puts, time.Now if nil
+}
+
+func newMemoryKV(bucket string) *memoryKV {
+	return &memoryKV{bucket: bucket, data: make(map[string][]byte), created: make(map[string]time.Time)}
+}
+
+func (kv *memoryKV) Get(key string) (nats.KeyValueEntry, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	v, ok := kv.data[key]
+	if !ok {
+		return nil, nats.ErrKeyNotFound
+	}
+	return &memoryKVEntry{bucket: kv.bucket, key: key, value: append([]byte(nil), v...), revision: kv.revision, created: kv.created[key]}, nil
+}
+
+func (kv *memoryKV) GetRevision(key string, revision uint64) (nats.KeyValueEntry, error) {
+	_ = revision
+	return kv.Get(key)
+}
+
+func (kv *memoryKV) Put(key string, value []byte) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.data[key] = append([]byte(nil), value...)
+	kv.created[key] = time.Now()
+	if kv.now != nil {
+		kv.created[key] = kv.now()
+	}
+	kv.revision++
+	return kv.revision, nil
+}
+
+func (kv *memoryKV) PutString(key string, value string) (uint64, error) {
+	return kv.Put(key, []byte(value))
+}
+
+func (kv *memoryKV) Create(key string, value []byte) (uint64, error) {
+	if _, err := kv.Get(key); err == nil {
+		return 0, nats.ErrKeyExists
+	}
+	return kv.Put(key, value)
+}
+
+func (kv *memoryKV) Update(key string, value []byte, last uint64) (uint64, error) {
+	_ = last
+	return kv.Put(key, value)
+}
+
+func (kv *memoryKV) Delete(key string, opts ...nats.DeleteOpt) error {
+	_ = opts
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	delete(kv.data, key)
+	delete(kv.created, key)
+	return nil
+}
+
+func (kv *memoryKV) Purge(key string, opts ...nats.DeleteOpt) error {
+	return kv.Delete(key, opts...)
+}
+
+func (kv *memoryKV) Watch(keys string, opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
+	_ = keys
+	_ = opts
+	return nil, errMemoryKVNotSupported
+}
+
+func (kv *memoryKV) WatchAll(opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
+	_ = opts
+	return nil, errMemoryKVNotSupported
+}
+
+func (kv *memoryKV) WatchFiltered(keys []string, opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
+	_ = keys
+	_ = opts
+	return nil, errMemoryKVNotSupported
+}
+
+func (kv *memoryKV) Keys(opts ...nats.WatchOpt) ([]string, error) {
+	_ = opts
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	keys := make([]string, 0, len(kv.data))
+	for k := range kv.data {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (kv *memoryKV) ListKeys(opts ...nats.WatchOpt) (nats.KeyLister, error) {
+	_ = opts
+	return nil, errMemoryKVNotSupported
+}
+
+func (kv *memoryKV) History(key string, opts ...nats.WatchOpt) ([]nats.KeyValueEntry, error) {
+	_ = opts
+	entry, err := kv.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return []nats.KeyValueEntry{entry}, nil
+}
+
+func (kv *memoryKV) Bucket() string {
+	return kv.bucket
+}
+
+func (kv *memoryKV) PurgeDeletes(opts ...nats.PurgeOpt) error {
+	_ = opts
+	return nil
+}
+
+func (kv *memoryKV) Status() (nats.KeyValueStatus, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	var bytes uint64
+	for _, v := range kv.data {
+		bytes += uint64(len(v))
+	}
+	return &memoryKVStatus{bucket: kv.bucket, values: uint64(len(kv.data)), bytes: bytes}, nil
+}
+
+type memoryKVEntry struct {
+	bucket   string
+	key      string
+	value    []byte
+	revision uint64
+	created  time.Time
+}
+
+func (e *memoryKVEntry) Bucket() string {
+	return e.bucket
+}
+
+func (e *memoryKVEntry) Key() string {
+	return e.key
+}
+
+func (e *memoryKVEntry) Value() []byte {
+	return e.value
+}
+
+func (e *memoryKVEntry) Revision() uint64 {
+	return e.revision
+}
+
+func (e *memoryKVEntry) Created() time.Time {
+	return e.created
+}
+
+func (e *memoryKVEntry) Delta() uint64 {
+	return 0
+}
+
+func (e *memoryKVEntry) Operation() nats.KeyValueOp {
+	return nats.KeyValuePut
+}
+
+type memoryKVStatus struct {
+	bucket string
+	values uint64
+	bytes  uint64
+}
+
+func (s *memoryKVStatus) Bucket() string {
+	return s.bucket
+}
+
+func (s *memoryKVStatus) Values() uint64 {
+	return s.values
+}
+
+func (s *memoryKVStatus) History() int64 {
+	return 1
+}
+
+func (s *memoryKVStatus) TTL() time.Duration {
+	return 0
+}
+
+func (s *memoryKVStatus) BackingStore() string {
+	return "memory"
+}
+
+func (s *memoryKVStatus) Bytes() uint64 {
+	return s.bytes
+}
+
+func (s *memoryKVStatus) IsCompressed() bool {
+	return false
+}
+
+func (s *memoryKVStatus) Config() nats.KeyValueConfig {
+	return nats.KeyValueConfig{Bucket: s.bucket}
+}
@@ -5,9 +5,11 @@
 package radosgwusage
 
 import (
-	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
@@ -41,31 +43,89 @@ func ensureStream(js nats.JetStreamContext, streamName string) error {
 	return nil
 }
 
+// Event is a notification on the notifications subject, the payload of
+// schema.RadosGWUsageEvent
+type Event struct {
+	Event    string            `json:"event"`  // e.g. sync_users, reshard_recommended
+	Status   string            `json:"status"` // e.g. in_progress, completed, failed, detected
+	IDs      []string          `json:"ids"`
+	Metadata map[string]string `json:"metadata"`
+}
+
 // publishEvent(nc, "sync_users", "in_progress", nil, map[string]string{"sync_mode": "full"})
 func publishEvent(nc *nats.Conn, eventType string, status string, ids []string, metadata map[string]string) error {
-	eventData := map[string]any{
-		"event":    eventType,
-		"status":   status,
-		"ids":      ids,
-		"metadata": metadata,
+	return schema.Publish(nc, "notifications", schema.RadosGWUsageEvent, Event{
+		Event:    eventType,
+		Status:   status,
+		IDs:      ids,
+		Metadata: metadata,
+	})
+}
+
+// markPartial sets "partial" in the metadata of an event derived from a
+// cycle that collected less than --completeness-percent of the users or
+// buckets
+func markPartial(metadata map[string]string, partial bool) map[string]string {
+	if partial {
+		metadata["partial"] = "true"
 	}
-	data, err := json.Marshal(eventData)
+	return metadata
+}
+
+// publishReshardRecommendations emits a "reshard_recommended" event listing
+// the buckets whose objects-per-shard ratio exceeds the configured threshold.
+func publishReshardRecommendations(nc *nats.Conn, bucketMetrics nats.KeyValue, cfg RadosGWUsageConfig, partial bool) error {
+	candidates, err := collectReshardCandidates(bucketMetrics)
 	if err != nil {
 		return err
 	}
-	return nc.Publish("notifications", data)
+	if len(candidates) == 0 {
+		log.Debug().Msg("No buckets need resharding")
+		return nil
+	}
+
+	log.Info().Int("buckets", len(candidates)).Msg("Publishing resharding recommendation")
+	return publishEvent(nc, "reshard_recommended", "detected", candidates, markPartial(map[string]string{
+		"rgw_cluster_id":            cfg.ClusterID,
+		"objects_per_shard_maximum": strconv.Itoa(cfg.ReshardObjectsPerShard),
+	}, partial))
+}
+
+// publishPublicBuckets emits a "bucket_public" event for every bucket that
+// became public since the previous cycle, with what grants the access.
+func publishPublicBuckets(nc *nats.Conn, bucketMetrics nats.KeyValue, tracker *publicBucketTracker, cfg RadosGWUsageConfig, partial bool) error {
+	current, err := collectPublicBuckets(bucketMetrics)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range tracker.newlyPublic(current) {
+		access := current[key]
+		log.Warn().Str("bucket_key", key).Strs("grants", access.Grants).Msg("Bucket became public")
+		err := publishEvent(nc, "bucket_public", "detected", []string{key}, markPartial(map[string]string{
+			"rgw_cluster_id": cfg.ClusterID,
+			"public_read":    strconv.FormatBool(access.PublicRead),
+			"public_write":   strconv.FormatBool(access.PublicWrite),
+			"grants":         strings.Join(access.Grants, ","),
+		}, partial))
+		if err != nil {
+			return err // Reported again in the next cycle
+		}
+	}
+	tracker.remember(current)
+	return nil
 }
 
 func listenForEvents(nc *nats.Conn) {
 	sub, err := nc.Subscribe("notifications", func(msg *nats.Msg) {
-		var event map[string]any
-		if err := json.Unmarshal(msg.Data, &event); err != nil {
+		var event Event
+		if err := schema.Unmarshal(msg, schema.RadosGWUsageEvent, &event); err != nil {
 			log.Error().Err(err).Msg("Failed to parse event")
 			return
 		}
 
-		eventType := event["event"].(string)
-		status := event["status"].(string)
+		eventType := event.Event
+		status := event.Status
 
 		switch eventType {
 		case "sync_users":
@@ -101,15 +161,15 @@ func listenForEvents(nc *nats.Conn) {
 
 func retryFailedEvents(nc *nats.Conn) {
 	sub, err := nc.Subscribe("notifications", func(msg *nats.Msg) {
-		var event map[string]any
-		if err := json.Unmarshal(msg.Data, &event); err != nil {
+		var event Event
+		if err := schema.Unmarshal(msg, schema.RadosGWUsageEvent, &event); err != nil {
 			log.Error().Err(err).Msg("Failed to parse event")
 			return
 		}
 
-		if event["status"].(string) == "failed" {
-			log.Warn().Str("event", event["event"].(string)).Msg("Retrying failed event")
-			publishEvent(nc, event["event"].(string), "in_progress", nil, nil)
+		if event.Status == "failed" {
+			log.Warn().Str("event", event.Event).Msg("Retrying failed event")
+			publishEvent(nc, event.Event, "in_progress", nil, nil)
 		}
 	})
 	if err != nil {
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// APITraffic is the traffic of an API category in a collection cycle, joined
+// from the ops log metrics
+type APITraffic struct {
+	Requests       uint64
+	BytesSent      uint64
+	BytesReceived  uint64
+	LatencySeconds float64 // Mean latency of the requests
+}
+
+// apiTotals sums the ops log metrics of an API category until they are joined
+type apiTotals struct {
+	requests      uint64
+	timeMs        uint64
+	bytesSent     uint64
+	bytesReceived uint64
+}
+
+// opsMetricsJoiner sums the API category metrics the ops-log sidecars
+// publish, by user and by bucket, until a collection cycle joins them into
+// the user and bucket metrics
+type opsMetricsJoiner struct {
+	mu      sync.Mutex
+	users   map[string]map[string]*apiTotals // "user$tenant" -> category
+	buckets map[string]map[string]*apiTotals // "tenant|bucket" -> category
+}
+
+func newOpsMetricsJoiner() *opsMetricsJoiner {
+	return &opsMetricsJoiner{
+		users:   map[string]map[string]*apiTotals{},
+		buckets: map[string]map[string]*apiTotals{},
+	}
+}
+
+// subscribe adds the metrics published on subject
+func (j *opsMetricsJoiner) subscribe(nc *nats.Conn, subject string) (*nats.Subscription, error) {
+	sub, err := nc.Subscribe(subject, func(msg *nats.Msg) {
+		var metrics opslog.AggregatedMetrics
+		if err := schema.Unmarshal(msg, schema.OpsMetrics, &metrics); err != nil {
+			log.Warn().Err(err).Str("subject", msg.Subject).Msg("Failed to decode ops log metrics")
+			return
+		}
+		j.add(metrics)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	log.Info().Str("subject", subject).Msg("Joining the ops log metrics into the user and bucket metrics")
+	return sub, nil
+}
+
+// add sums the API category metrics of an interval. Metrics published
+// without --track-api-categories have none and are ignored.
+func (j *opsMetricsJoiner) add(metrics opslog.AggregatedMetrics) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for key, requests := range metrics.RequestsByCategoryPerUser {
+		i := strings.LastIndex(key, "|")
+		if i < 0 {
+			continue
+		}
+		addTotals(j.users, key[:i], key[i+1:], requests,
+			metrics.RequestTimeMsByCategoryPerUser[key], metrics.BytesSentByCategoryPerUser[key], metrics.BytesReceivedByCategoryPerUser[key])
+	}
+	for key, requests := range metrics.RequestsByCategoryPerBucket {
+		i := strings.LastIndex(key, "|")
+		if i < 0 || !strings.Contains(key[:i], "|") {
+			continue
+		}
+		addTotals(j.buckets, key[:i], key[i+1:], requests,
+			metrics.RequestTimeMsByCategoryPerBucket[key], metrics.BytesSentByCategoryPerBucket[key], metrics.BytesReceivedByCategoryPerBucket[key])
+	}
+}
+
+func addTotals(totals map[string]map[string]*apiTotals, id, category string, requests, timeMs, bytesSent, bytesReceived uint64) {
+	categories, ok := totals[id]
+	if !ok {
+		categories = map[string]*apiTotals{}
+		totals[id] = categories
+	}
+	t, ok := categories[category]
+	if !ok {
+		t = &apiTotals{}
+		categories[category] = t
+	}
+	t.requests += requests
+	t.timeMs += timeMs
+	t.bytesSent += bytesSent
+	t.bytesReceived += bytesReceived
+}
+
+// take returns the traffic by user and by bucket summed since the last call
+func (j *opsMetricsJoiner) take() (users, buckets map[string]map[string]APITraffic) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	users, buckets = trafficOf(j.users), trafficOf(j.buckets)
+	j.users = map[string]map[string]*apiTotals{}
+	j.buckets = map[string]map[string]*apiTotals{}
+	return users, buckets
+}
+
+func trafficOf(totals map[string]map[string]*apiTotals) map[string]map[string]APITraffic {
+	traffic := make(map[string]map[string]APITraffic, len(totals))
+	for id, categories := range totals {
+		traffic[id] = make(map[string]APITraffic, len(categories))
+		for category, t := range categories {
+			api := APITraffic{Requests: t.requests, BytesSent: t.bytesSent, BytesReceived: t.bytesReceived}
+			if t.requests > 0 {
+				api.LatencySeconds = float64(t.timeMs) / float64(t.requests) / 1000
+			}
+			traffic[id][category] = api
+		}
+	}
+	return traffic
+}
+
+// joinOpsMetricsInKV joins the traffic of the cycle into the user and bucket
+// metrics, making combined records of capacity, traffic and latency. Users
+// and buckets without requests get an empty traffic, telling them apart from
+// records that were not joined.
+func joinOpsMetricsInKV(joiner *opsMetricsJoiner, userMetrics, bucketMetrics nats.KeyValue) error {
+	users, buckets := joiner.take()
+
+	err := updateKVRecords(userMetrics, func(metrics *UserLevelMetrics) {
+		metrics.Traffic = users[metrics.GetUserIdentification()]
+		if metrics.Traffic == nil {
+			metrics.Traffic = map[string]APITraffic{}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to join user metrics: %w", err)
+	}
+
+	err = updateKVRecords(bucketMetrics, func(metrics *UserBucketMetrics) {
+		tenant := metrics.Tenant
+		if tenant == "" {
+			tenant = MissingTenantPlaceholder
+		}
+		metrics.Traffic = buckets[tenant+"|"+metrics.BucketID]
+		if metrics.Traffic == nil {
+			metrics.Traffic = map[string]APITraffic{}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to join bucket metrics: %w", err)
+	}
+
+	log.Info().Int("users", len(users)).Int("buckets", len(buckets)).Msg("Joined the ops log metrics")
+	return nil
+}
+
+// updateKVRecords applies update to every JSON record of kv
+func updateKVRecords[T any](kv nats.KeyValue, update func(*T)) error {
+	keys, err := kv.Keys()
+	if err != nil {
+		if errors.Is(err, nats.ErrNoKeysFound) {
+			return nil
+		}
+		return err
+	}
+
+	for _, key := range keys {
+		entry, err := kv.Get(key)
+		if err != nil {
+			if !errors.Is(err, nats.ErrKeyNotFound) {
+				log.Warn().Str("key", key).Err(err).Msg("Failed to fetch metrics from KV")
+			}
+			continue
+		}
+		var record T
+		if err := json.Unmarshal(entry.Value(), &record); err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to unmarshal metrics")
+			continue
+		}
+		update(&record)
+		data, err := json.Marshal(record)
+		if err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to serialize metrics")
+			continue
+		}
+		if _, err := kv.Put(key, data); err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to store metrics in KV")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+)
+
+func TestOpsMetricsJoiner_SumsIntervals(t *testing.T) {
+	joiner := newOpsMetricsJoiner()
+	joiner.add(opslog.AggregatedMetrics{
+		RequestsByCategoryPerUser:      map[string]uint64{"user-a$tenant-a|read": 3},
+		RequestTimeMsByCategoryPerUser: map[string]uint64{"user-a$tenant-a|read": 30},
+		BytesSentByCategoryPerUser:     map[string]uint64{"user-a$tenant-a|read": 300},
+	})
+	joiner.add(opslog.AggregatedMetrics{
+		RequestsByCategoryPerUser:        map[string]uint64{"user-a$tenant-a|read": 1},
+		RequestTimeMsByCategoryPerUser:   map[string]uint64{"user-a$tenant-a|read": 50},
+		RequestsByCategoryPerBucket:      map[string]uint64{"tenant-a|bucket-a|write": 2, "malformed|write": 1},
+		RequestTimeMsByCategoryPerBucket: map[string]uint64{"tenant-a|bucket-a|write": 500},
+	})
+	// Metrics published without --track-api-categories
+	joiner.add(opslog.AggregatedMetrics{TotalRequests: 10})
+
+	users, buckets := joiner.take()
+	read := users["user-a$tenant-a"]["read"]
+	if read.Requests != 4 || read.BytesSent != 300 || read.LatencySeconds != 0.02 {
+		t.Fatalf("unexpected user traffic %+v", read)
+	}
+	write := buckets["tenant-a|bucket-a"]["write"]
+	if write.Requests != 2 || write.LatencySeconds != 0.25 {
+		t.Fatalf("unexpected bucket traffic %+v", write)
+	}
+	if len(buckets) != 1 {
+		t.Fatalf("expected the malformed key to be skipped, got %v", buckets)
+	}
+
+	// The next cycle starts over
+	if users, _ := joiner.take(); len(users) != 0 {
+		t.Fatalf("expected no traffic after take, got %v", users)
+	}
+}
+
+func TestJoinOpsMetricsInKV(t *testing.T) {
+	userKey := BuildUserTenantKey("user-a", "tenant-a")
+	idleKey := BuildUserTenantKey("user-b", "")
+	bucketKey := BuildUserTenantBucketKey("user-a", "tenant-a", "bucket-a")
+	marshal := func(v any) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return data
+	}
+	userMetrics := newTestKV("user_metrics", map[string][]byte{
+		userKey: marshal(UserLevelMetrics{User: "user-a", Tenant: "tenant-a", DataSizeTotal: 1024}),
+		idleKey: marshal(UserLevelMetrics{User: "user-b"}),
+	})
+	bucketMetrics := newTestKV("bucket_metrics", map[string][]byte{
+		bucketKey: marshal(UserBucketMetrics{BucketID: "bucket-a", User: "user-a", Tenant: "tenant-a", BucketSize: 512}),
+	})
+
+	joiner := newOpsMetricsJoiner()
+	joiner.add(opslog.AggregatedMetrics{
+		RequestsByCategoryPerUser:        map[string]uint64{"user-a$tenant-a|list": 2},
+		RequestTimeMsByCategoryPerUser:   map[string]uint64{"user-a$tenant-a|list": 100},
+		RequestsByCategoryPerBucket:      map[string]uint64{"tenant-a|bucket-a|list": 2},
+		RequestTimeMsByCategoryPerBucket: map[string]uint64{"tenant-a|bucket-a|list": 100},
+	})
+	if err := joinOpsMetricsInKV(joiner, userMetrics, bucketMetrics); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	var user UserLevelMetrics
+	if err := json.Unmarshal(userMetrics.data[userKey], &user); err != nil {
+		t.Fatalf("unmarshal user: %v", err)
+	}
+	if user.DataSizeTotal != 1024 || user.Traffic["list"].Requests != 2 || user.Traffic["list"].LatencySeconds != 0.05 {
+		t.Fatalf("expected capacity and traffic combined, got %+v", user)
+	}
+
+	var idle UserLevelMetrics
+	if err := json.Unmarshal(userMetrics.data[idleKey], &idle); err != nil {
+		t.Fatalf("unmarshal user: %v", err)
+	}
+	if idle.Traffic == nil || len(idle.Traffic) != 0 {
+		t.Fatalf("expected an empty traffic of the idle user, got %+v", idle.Traffic)
+	}
+
+	var bucket UserBucketMetrics
+	if err := json.Unmarshal(bucketMetrics.data[bucketKey], &bucket); err != nil {
+		t.Fatalf("unmarshal bucket: %v", err)
+	}
+	if bucket.BucketSize != 512 || bucket.Traffic["list"].Requests != 2 {
+		t.Fatalf("expected capacity and traffic combined, got %+v", bucket)
+	}
+}
@@ -0,0 +1,326 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/kubeclient"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultProbeTimeout bounds the probes of the targets without a timeout
+	defaultProbeTimeout = 30 * time.Second
+	// scrapeTimeoutOffset leaves Prometheus the time to read the metrics of
+	// a probe before its scrape timeout
+	scrapeTimeoutOffset = 500 * time.Millisecond
+)
+
+// ProbeTarget is an RGW cluster probed on /probe?cluster=<Cluster>. The
+// source and the credentials override the ones of the exporter.
+type ProbeTarget struct {
+	Cluster            string `json:"cluster"`
+	Source             string `json:"source,omitempty"`
+	AdminURL           string `json:"admin_url,omitempty"`
+	AccessKey          string `json:"access_key,omitempty"`
+	SecretKey          string `json:"secret_key,omitempty"`
+	RadosGWAdminBinary string `json:"radosgw_admin,omitempty"`
+	CephConf           string `json:"ceph_conf,omitempty"`
+	CephName           string `json:"ceph_name,omitempty"`
+	CephKeyring        string `json:"ceph_keyring,omitempty"`
+	Timeout            string `json:"timeout,omitempty"` // e.g. 45s, defaultProbeTimeout if empty
+
+	timeout time.Duration
+}
+
+// probeTargetsFile is the JSON file of --probe-targets
+type probeTargetsFile struct {
+	Targets []ProbeTarget `json:"targets"`
+}
+
+// LoadProbeTargets reads the JSON file of the probe targets, by cluster
+func LoadProbeTargets(path string) (map[string]ProbeTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read probe targets %s: %w", path, err)
+	}
+
+	var file probeTargetsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse probe targets %s: %w", path, err)
+	}
+	if len(file.Targets) == 0 {
+		return nil, fmt.Errorf("no probe targets in %s", path)
+	}
+
+	targets := make(map[string]ProbeTarget, len(file.Targets))
+	for _, target := range file.Targets {
+		if target.Cluster == "" {
+			return nil, fmt.Errorf("probe target without cluster in %s", path)
+		}
+		if _, ok := targets[target.Cluster]; ok {
+			return nil, fmt.Errorf("duplicate probe target %q in %s", target.Cluster, path)
+		}
+		switch target.Source {
+		case "", SourceAdminAPI, SourceCLI:
+		default:
+			return nil, fmt.Errorf("unknown source %q of probe target %q, expected %s or %s", target.Source, target.Cluster, SourceAdminAPI, SourceCLI)
+		}
+		target.timeout = defaultProbeTimeout
+		if target.Timeout != "" {
+			target.timeout, err = time.ParseDuration(target.Timeout)
+			if err != nil || target.timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout %q of probe target %q", target.Timeout, target.Cluster)
+			}
+		}
+		targets[target.Cluster] = target
+	}
+	return targets, nil
+}
+
+// config is the configuration of the exporter for the target
+func (t ProbeTarget) config(base RadosGWUsageConfig) RadosGWUsageConfig {
+	cfg := base
+	cfg.ClusterID = t.Cluster
+	if t.Source != "" {
+		cfg.Source = t.Source
+	}
+	if t.AdminURL != "" {
+		cfg.AdminURL = t.AdminURL
+	}
+	if t.AccessKey != "" {
+		cfg.AccessKey = t.AccessKey
+	}
+	if t.SecretKey != "" {
+		cfg.SecretKey = t.SecretKey
+	}
+	if t.RadosGWAdminBinary != "" {
+		cfg.RadosGWAdminBinary = t.RadosGWAdminBinary
+	}
+	if t.CephConf != "" {
+		cfg.CephConf = t.CephConf
+	}
+	if t.CephName != "" {
+		cfg.CephName = t.CephName
+	}
+	if t.CephKeyring != "" {
+		cfg.CephKeyring = t.CephKeyring
+	}
+	return cfg
+}
+
+// probeTimeout is the timeout of the target, shortened to the scrape
+// timeout Prometheus sends in X-Prometheus-Scrape-Timeout-Seconds
+func probeTimeout(target ProbeTarget, r *http.Request) time.Duration {
+	timeout := target.timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	if header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); header != "" {
+		seconds, err := strconv.ParseFloat(header, 64)
+		if err == nil && seconds > 0 {
+			scrapeTimeout := time.Duration(seconds*float64(time.Second)) - scrapeTimeoutOffset
+			if scrapeTimeout > 0 && scrapeTimeout < timeout {
+				timeout = scrapeTimeout
+			}
+		}
+	}
+	return timeout
+}
+
+// probeMu serializes the probes, they share the metric vectors of the
+// targets
+var probeMu sync.Mutex
+
+// probeHandler runs a collection cycle of the target of the cluster query
+// parameter and serves its metrics, like the multi-target exporters
+type probeHandler struct {
+	base RadosGWUsageConfig
+
+	mu      sync.RWMutex // Guards targets, replaced by the Rook discovery
+	targets map[string]ProbeTarget
+}
+
+func (h *probeHandler) target(cluster string) (ProbeTarget, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	target, ok := h.targets[cluster]
+	return target, ok
+}
+
+// setTargets replaces the targets and returns the clusters added and
+// removed
+func (h *probeHandler) setTargets(targets map[string]ProbeTarget) (added, removed []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for cluster := range targets {
+		if _, ok := h.targets[cluster]; !ok {
+			added = append(added, cluster)
+		}
+	}
+	for cluster := range h.targets {
+		if _, ok := targets[cluster]; !ok {
+			removed = append(removed, cluster)
+		}
+	}
+	h.targets = targets
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// serveTargets lists the targets for the HTTP service discovery of
+// Prometheus, each probed on the address the list was requested on
+func (h *probeHandler) serveTargets(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	clusters := slices.Sorted(maps.Keys(h.targets))
+	h.mu.RUnlock()
+
+	groups := make([]httpSDTargetGroup, 0, len(clusters))
+	for _, cluster := range clusters {
+		groups = append(groups, httpSDTargetGroup{
+			Targets: []string{r.Host},
+			Labels:  map[string]string{"__param_cluster": cluster},
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(groups); err != nil {
+		log.Error().Err(err).Msg("Error writing the probe targets")
+	}
+}
+
+// httpSDTargetGroup is a target group of the Prometheus HTTP service
+// discovery
+type httpSDTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster := r.URL.Query().Get("cluster")
+	if cluster == "" {
+		http.Error(w, "cluster parameter is missing", http.StatusBadRequest)
+		return
+	}
+	target, ok := h.target(cluster)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown cluster %q", cluster), http.StatusBadRequest)
+		return
+	}
+
+	probeSuccess := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_success",
+		Help: "Whether the collection cycle of the target succeeded (1 = success, 0 = failure)",
+	})
+	probeDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_duration_seconds",
+		Help: "Duration of the collection cycle of the target in seconds",
+	})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(probeSuccess, probeDuration)
+
+	cfg := target.config(h.base)
+	ctx, cancel := context.WithTimeout(r.Context(), probeTimeout(target, r))
+	defer cancel()
+
+	probeMu.Lock()
+	defer probeMu.Unlock()
+
+	start := time.Now()
+	userMetrics, bucketMetrics, err := runProbeCycle(ctx, cfg)
+	probeDuration.Set(time.Since(start).Seconds())
+	if err != nil {
+		log.Warn().Err(err).Str("cluster", cluster).Msg("Probe failed")
+	} else {
+		probeSuccess.Set(1)
+		for _, metric := range targetMetrics {
+			metric.Reset()
+			registry.MustRegister(metric)
+		}
+		populateMetricsFromKV(userMetrics, bucketMetrics, cfg)
+		// The first sighting of a user needs the NATS KV of the collection loop
+		userFirstSeen.Reset()
+		defer func() {
+			for _, metric := range targetMetrics {
+				metric.Reset()
+			}
+		}()
+	}
+
+	promhttp.HandlerFor(metricnames.Gatherer(registry), promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// runProbeCycle runs a collection cycle of the target in memory and
+// returns the user and bucket metrics of the cycle
+func runProbeCycle(ctx context.Context, cfg RadosGWUsageConfig) (userMetrics, bucketMetrics *memoryKV, err error) {
+	kv := func(name string) *memoryKV {
+		return newMemoryKV(fmt.Sprintf("%s_%s", cfg.SyncControlBucketPrefix, name))
+	}
+	userData, userUsageData, bucketData := kv("user_data"), kv("user_usage_data"), kv("bucket_data")
+	userMetrics, bucketMetrics = kv("user_metrics"), kv("bucket_metrics")
+
+	stages := syncStages(cfg, &PrysmStatus{}, userData, userUsageData, bucketData, userMetrics, bucketMetrics)
+	if err := runCollectionCycle(ctx, stages); err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("probe timed out: %w", err)
+	}
+	return userMetrics, bucketMetrics, nil
+}
+
+// StartProbeExporter serves the metrics of the targets on /probe, one
+// collection cycle per scrape, instead of running the collection loop, and
+// lists them on /targets. With RookDiscovery, the CephObjectStores are added
+// to the targets. The metrics of the exporter stay on /metrics.
+func StartProbeExporter(cfg RadosGWUsageConfig, targets map[string]ProbeTarget) {
+	if cfg.AdminAPIFaults != "" {
+		log.Warn().Str("admin_api_faults", cfg.AdminAPIFaults).Msg("Injecting faults into the admin API requests, do not use in production")
+	}
+
+	// The metrics of the targets are only served by their probes
+	for _, metric := range targetMetrics {
+		prometheus.Unregister(metric)
+	}
+	handler := &probeHandler{base: cfg, targets: targets}
+	http.Handle("/probe", handler)
+	http.HandleFunc("/targets", handler.serveTargets)
+	telemetry.StartMetricsServer(cfg.PrometheusPort)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if cfg.RookDiscovery {
+		kube, err := kubeclient.InCluster()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Rook discovery requires the service account of the pod")
+		}
+		go runRookDiscovery(ctx, cfg, kube, handler, targets)
+	}
+
+	// Wait for termination signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	log.Info().Int("targets", len(targets)).Msg("Serving the probes of the targets. Waiting for termination signal.")
+	<-sigChan
+	log.Info().Msg("Termination signal received. Exiting...")
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeProbeTargets(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write targets: %v", err)
+	}
+	return path
+}
+
+func TestLoadProbeTargets(t *testing.T) {
+	path := writeProbeTargets(t, `{"targets":[
+		{"cluster":"rgw-a","admin_url":"http://rgw-a:7480","access_key":"AK","secret_key":"SK","timeout":"45s"},
+		{"cluster":"rgw-b","source":"cli","ceph_name":"client.admin"}
+	]}`)
+	targets, err := LoadProbeTargets(path)
+	if err != nil {
+		t.Fatalf("load targets: %v", err)
+	}
+	if len(targets) != 2 || targets["rgw-a"].timeout != 45*time.Second || targets["rgw-b"].timeout != defaultProbeTimeout {
+		t.Fatalf("unexpected targets %+v", targets)
+	}
+
+	cfg := targets["rgw-b"].config(RadosGWUsageConfig{Source: SourceAdminAPI, ClusterID: "default", CephName: "client.rgw", NodeName: "node-1"})
+	if cfg.Source != SourceCLI || cfg.ClusterID != "rgw-b" || cfg.CephName != "client.admin" || cfg.NodeName != "node-1" {
+		t.Fatalf("expected the target to override the source, cluster and credentials, got %+v", cfg)
+	}
+
+	for name, content := range map[string]string{
+		"empty":     `{"targets":[]}`,
+		"cluster":   `{"targets":[{"admin_url":"http://rgw"}]}`,
+		"duplicate": `{"targets":[{"cluster":"rgw-a"},{"cluster":"rgw-a"}]}`,
+		"source":    `{"targets":[{"cluster":"rgw-a","source":"s3"}]}`,
+		"timeout":   `{"targets":[{"cluster":"rgw-a","timeout":"soon"}]}`,
+	} {
+		if _, err := LoadProbeTargets(writeProbeTargets(t, content)); err == nil {
+			t.Errorf("%s: expected the targets to be rejected", name)
+		}
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	target := ProbeTarget{timeout: 20 * time.Second}
+	r := httptest.NewRequest(http.MethodGet, "/probe?cluster=rgw-a", nil)
+	if got := probeTimeout(target, r); got != 20*time.Second {
+		t.Fatalf("expected the timeout of the target, got %s", got)
+	}
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "10")
+	if got := probeTimeout(target, r); got != 10*time.Second-scrapeTimeoutOffset {
+		t.Fatalf("expected the scrape timeout, got %s", got)
+	}
+}
+
+func TestProbeHandler(t *testing.T) {
+	rgw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/metadata/user":
+			io.WriteString(w, `["alice"]`)
+		case "/admin/user":
+			io.WriteString(w, `{"user_id":"alice","display_name":"Alice","stats":{"size":2048,"num_objects":3}}`)
+		case "/admin/bucket":
+			io.WriteString(w, `[]`)
+		case "/admin/usage":
+			io.WriteString(w, `{"entries":[],"summary":[]}`)
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer rgw.Close()
+
+	handler := &probeHandler{
+		base: RadosGWUsageConfig{Source: SourceAdminAPI, SyncControlBucketPrefix: "sync"},
+		targets: map[string]ProbeTarget{
+			"rgw-a": {Cluster: "rgw-a", AdminURL: rgw.URL, AccessKey: "AK", SecretKey: "SK", timeout: 5 * time.Second},
+		},
+	}
+	probe := func(query string) (int, string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probe"+query, nil))
+		return w.Code, w.Body.String()
+	}
+
+	if code, _ := probe(""); code != http.StatusBadRequest {
+		t.Fatalf("expected a probe without cluster to be rejected, got %d", code)
+	}
+	if code, _ := probe("?cluster=rgw-z"); code != http.StatusBadRequest {
+		t.Fatalf("expected a probe of an unknown cluster to be rejected, got %d", code)
+	}
+
+	code, body := probe("?cluster=rgw-a")
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", code, body)
+	}
+	for _, want := range []string{
+		"probe_success 1",
+		`radosgw_user_objects_total{instance_id="",node="",rgw_cluster_id="rgw-a",user="alice"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in the metrics of the probe:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "radosgw_usage_user_first_seen_timestamp_seconds{") {
+		t.Fatalf("expected no first sighting of the users in the probe:\n%s", body)
+	}
+}
+
+func TestProbeHandler_Failure(t *testing.T) {
+	rgw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer rgw.Close()
+
+	handler := &probeHandler{targets: map[string]ProbeTarget{
+		"rgw-a": {Cluster: "rgw-a", AdminURL: rgw.URL, AccessKey: "AK", SecretKey: "SK", timeout: 5 * time.Second},
+	}}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probe?cluster=rgw-a", nil))
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "probe_success 0") || !strings.Contains(body, "probe_duration_seconds") {
+		t.Fatalf("expected a failed probe, got %d:\n%s", w.Code, body)
+	}
+	if strings.Contains(body, "radosgw_user_") {
+		t.Fatalf("expected no metrics of the target:\n%s", body)
+	}
+}
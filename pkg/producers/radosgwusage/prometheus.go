@@ -7,12 +7,10 @@ package radosgwusage
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
+	"strconv"
 
 	"github.com/nats-io/nats.go"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 )
 
@@ -33,18 +31,91 @@ var (
 	userQuotaMaxSize    = newGaugeVec("radosgw_usage_user_quota_size", "Maximum allowed size for user", userLabels)
 	userQuotaMaxObjects = newGaugeVec("radosgw_usage_user_quota_size_objects", "Maximum allowed number of objects across all user buckets", userLabels)
 
+	// User lifecycle, suspended and key count are labels of the info metric
+	userInfo      = newGaugeVec("radosgw_usage_user_info", "User account state, always 1", []string{"user", "suspended", "keys", "rgw_cluster_id", "node", "instance_id"})
+	userFirstSeen = newGaugeVec("radosgw_usage_user_first_seen_timestamp_seconds", "Unix time the exporter first saw the user", userLabels)
+	usersCreated  = newGaugeVec("radosgw_usage_users_created", "Users created since the previous user sync", []string{"rgw_cluster_id", "node", "instance_id"})
+	usersRemoved  = newGaugeVec("radosgw_usage_users_removed", "Users removed since the previous user sync", []string{"rgw_cluster_id", "node", "instance_id"})
+
+	// Completeness of the last user and bucket sync, kind is users or buckets
+	collectionTotal        = newGaugeVec("radosgw_usage_collection_total", "Users or buckets listed by the last sync", []string{"kind", "rgw_cluster_id", "node", "instance_id"})
+	collectionFailed       = newGaugeVec("radosgw_usage_collection_failed", "Users or buckets the last sync failed to collect, their data is of a previous sync", []string{"kind", "rgw_cluster_id", "node", "instance_id"})
+	collectionCompleteness = newGaugeVec("radosgw_usage_collection_completeness_ratio", "Share of the users or buckets listed that the last sync collected", []string{"kind", "rgw_cluster_id", "node", "instance_id"})
+	collectionPartial      = newGaugeVec("radosgw_usage_collection_partial", "The last cycle collected less than --completeness-percent of the users or buckets, its metrics are partial (1 = yes, 0 = no)", []string{"rgw_cluster_id", "node", "instance_id"})
+
 	// Bucket-level metrics
 	bucketLabels      = []string{"bucket", "owner", "zonegroup", "rgw_cluster_id", "node", "instance_id"}
 	bucketSize        = newGaugeVec("radosgw_usage_bucket_size", "Size of bucket", bucketLabels)
 	bucketObjectCount = newGaugeVec("radosgw_usage_bucket_objects", "Number of objects in bucket", bucketLabels)
 	bucketShards      = newGaugeVec("radosgw_usage_bucket_shards", "Number of shards in bucket", bucketLabels)
 
+	// Shard pressure metrics
+	bucketObjectsPerShard    = newGaugeVec("radosgw_usage_bucket_objects_per_shard", "Average number of objects per bucket index shard", bucketLabels)
+	bucketReshardRecommended = newGaugeVec("radosgw_usage_bucket_reshard_recommended", "Bucket exceeds the objects-per-shard threshold and should be resharded (1 = yes, 0 = no)", bucketLabels)
+
+	// OMAP pressure of the bucket index, see --large-omap-keys-per-shard
+	bucketIndexEntries     = newGaugeVec("radosgw_usage_bucket_index_entries", "Entries of the bucket index, including incomplete multipart uploads and delete markers", bucketLabels)
+	bucketOmapKeysPerShard = newGaugeVec("radosgw_usage_bucket_omap_keys_per_shard", "Average number of OMAP keys per bucket index shard", bucketLabels)
+	bucketLargeOmap        = newGaugeVec("radosgw_usage_bucket_large_omap_warning", "Bucket index shards exceed the large OMAP key threshold, raising LARGE_OMAP_OBJECTS (1 = yes, 0 = no)", bucketLabels)
+	largeOmapBuckets       = newGaugeVec("radosgw_usage_large_omap_buckets", "Number of buckets whose index shards exceed the large OMAP key threshold", []string{"rgw_cluster_id", "node", "instance_id"})
+
 	// Quota metrics
 	bucketQuotaEnabled    = newGaugeVec("radosgw_usage_bucket_quota_enabled", "Quota enabled for bucket", bucketLabels)
 	bucketQuotaMaxSize    = newGaugeVec("radosgw_usage_bucket_quota_size", "Maximum allowed bucket size", bucketLabels)
 	bucketQuotaMaxObjects = newGaugeVec("radosgw_usage_bucket_quota_size_objects", "Maximum allowed bucket size in number of objects", bucketLabels)
+
+	// Access audit metrics, by access: public_read, public_write or authenticated
+	bucketAccessLabels = []string{"bucket", "owner", "zonegroup", "access", "rgw_cluster_id", "node", "instance_id"}
+	bucketAccess       = newGaugeVec("radosgw_usage_bucket_access", "Access the bucket grants beyond its owner (1 = granted, 0 = not)", bucketAccessLabels)
+	accessBuckets      = newGaugeVec("radosgw_usage_buckets_with_access", "Number of buckets granting the access", []string{"access", "rgw_cluster_id", "node", "instance_id"})
+
+	// Ops log traffic of the last cycle by API category, see --ops-metrics-join
+	userAPILabels     = []string{"user", "category", "rgw_cluster_id", "node", "instance_id"}
+	userAPIRequests   = newGaugeVec("radosgw_usage_user_api_requests", "Requests of the user in the last collection cycle by API category", userAPILabels)
+	userAPILatency    = newGaugeVec("radosgw_usage_user_api_latency_seconds", "Mean latency of the requests of the user in the last collection cycle by API category", userAPILabels)
+	bucketAPILabels   = []string{"bucket", "owner", "zonegroup", "category", "rgw_cluster_id", "node", "instance_id"}
+	bucketAPIRequests = newGaugeVec("radosgw_usage_bucket_api_requests", "Requests to the bucket in the last collection cycle by API category", bucketAPILabels)
+	bucketAPILatency  = newGaugeVec("radosgw_usage_bucket_api_latency_seconds", "Mean latency of the requests to the bucket in the last collection cycle by API category", bucketAPILabels)
+
+	// Usage log totals by API category, summed over the RGW operations
+	bucketAPIOps           = newGaugeVec("radosgw_usage_bucket_api_ops", "Operations on the bucket in the usage log by API category", bucketAPILabels)
+	bucketAPISuccessfulOps = newGaugeVec("radosgw_usage_bucket_api_successful_ops", "Successful operations on the bucket in the usage log by API category", bucketAPILabels)
+	bucketAPIBytesSent     = newGaugeVec("radosgw_usage_bucket_api_bytes_sent", "Bytes sent from the bucket in the usage log by API category", bucketAPILabels)
+	bucketAPIBytesReceived = newGaugeVec("radosgw_usage_bucket_api_bytes_received", "Bytes received by the bucket in the usage log by API category", bucketAPILabels)
+
+	// Faults injected into the admin API requests, see --admin-api-faults
+	injectedFaults = newCounterVec("radosgw_usage_injected_faults_total", "Faults injected into the admin API requests by fault", []string{"fault"})
+
+	// NATS KV buckets of the exporter, see --kv-ttl and --kv-compact-age
+	kvEntries    = newGaugeVec("radosgw_usage_kv_entries", "Entries of the NATS KV bucket, including delete markers and history", []string{"kv_bucket"})
+	kvBytes      = newGaugeVec("radosgw_usage_kv_bytes", "Bytes stored by the NATS KV bucket", []string{"kv_bucket"})
+	kvPurgedKeys = newCounterVec("radosgw_usage_kv_purged_keys_total", "Keys purged by the compaction of the NATS KV bucket", []string{"kv_bucket"})
+
+	// Batched writes to the NATS KV buckets, see --kv-write-workers
+	kvPutDuration   = newHistogramVec("radosgw_usage_kv_put_duration_seconds", "Duration of the writes of single keys to the NATS KV bucket", []string{"kv_bucket"})
+	kvBatchDuration = newGaugeVec("radosgw_usage_kv_batch_duration_seconds", "Duration of the last batch of writes to the NATS KV bucket", []string{"kv_bucket"})
+	kvWrites        = newCounterVec("radosgw_usage_kv_writes_total", "Writes to the NATS KV bucket by result", []string{"kv_bucket", "result"})
+
+	// Collection cycle metrics
+	lastSync = newGaugeVec("radosgw_usage_last_sync_timestamp_seconds", "Unix time the last collection cycle completed", []string{"rgw_cluster_id", "node", "instance_id"})
 )
 
+// targetMetrics are the user and bucket metrics of the RGW cluster, served
+// by the probes of the targets in the multi-target mode, see --probe-targets
+var targetMetrics = []interface {
+	prometheus.Collector
+	Reset()
+}{
+	userMetadata, userInfo, userBucketsTotal, userObjectsTotal, userDataSizeTotal,
+	userQuotaEnabled, userQuotaMaxSize, userQuotaMaxObjects,
+	bucketSize, bucketObjectCount, bucketShards, bucketObjectsPerShard, bucketReshardRecommended,
+	bucketIndexEntries, bucketOmapKeysPerShard, bucketLargeOmap, largeOmapBuckets,
+	bucketQuotaEnabled, bucketQuotaMaxSize, bucketQuotaMaxObjects,
+	bucketAccess, accessBuckets,
+	userAPIRequests, userAPILatency, bucketAPIRequests, bucketAPILatency,
+	bucketAPIOps, bucketAPISuccessfulOps, bucketAPIBytesSent, bucketAPIBytesReceived,
+}
+
 func newCounterVec(name, help string, labels []string) *prometheus.CounterVec {
 	return prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: name,
@@ -80,23 +151,32 @@ func init() {
 	prometheus.MustRegister(userQuotaMaxSize)
 	prometheus.MustRegister(userQuotaMaxObjects)
 
+	prometheus.MustRegister(userInfo, userFirstSeen, usersCreated, usersRemoved)
+	prometheus.MustRegister(collectionTotal, collectionFailed, collectionCompleteness, collectionPartial)
+
 	prometheus.MustRegister(bucketSize)
 	prometheus.MustRegister(bucketObjectCount)
 	prometheus.MustRegister(bucketShards)
+	prometheus.MustRegister(bucketObjectsPerShard)
+	prometheus.MustRegister(bucketReshardRecommended)
+	prometheus.MustRegister(bucketIndexEntries, bucketOmapKeysPerShard, bucketLargeOmap, largeOmapBuckets)
 	prometheus.MustRegister(bucketQuotaEnabled)
 	prometheus.MustRegister(bucketQuotaMaxSize)
 	prometheus.MustRegister(bucketQuotaMaxObjects)
-}
 
-func startPrometheusMetricsServer(port int) {
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
-		}
-	}()
+	prometheus.MustRegister(bucketAccess)
+	prometheus.MustRegister(accessBuckets)
+
+	prometheus.MustRegister(userAPIRequests, userAPILatency)
+	prometheus.MustRegister(bucketAPIRequests, bucketAPILatency)
+	prometheus.MustRegister(bucketAPIOps, bucketAPISuccessfulOps, bucketAPIBytesSent, bucketAPIBytesReceived)
+
+	prometheus.MustRegister(injectedFaults)
+
+	prometheus.MustRegister(kvEntries, kvBytes, kvPurgedKeys)
+	prometheus.MustRegister(kvPutDuration, kvBatchDuration, kvWrites)
+
+	prometheus.MustRegister(lastSync)
 }
 
 func populateStatus(status *PrysmStatus) {
@@ -110,9 +190,70 @@ func populateStatus(status *PrysmStatus) {
 	log.Trace().Msg("Completed populating prysmStatus")
 }
 
+// populateLastSync records that a collection cycle completed now
+func populateLastSync(cfg RadosGWUsageConfig) {
+	lastSync.With(prometheus.Labels{
+		"rgw_cluster_id": cfg.ClusterID,
+		"node":           cfg.NodeName,
+		"instance_id":    cfg.InstanceID,
+	}).SetToCurrentTime()
+}
+
+// populateUserLifecycle exports the users created and removed by the last
+// user sync
+func populateUserLifecycle(status *PrysmStatus, cfg RadosGWUsageConfig) {
+	created, removed := status.GetUserLifecycle()
+	labels := prometheus.Labels{
+		"rgw_cluster_id": cfg.ClusterID,
+		"node":           cfg.NodeName,
+		"instance_id":    cfg.InstanceID,
+	}
+	usersCreated.With(labels).Set(float64(created))
+	usersRemoved.With(labels).Set(float64(removed))
+}
+
+// populateCompleteness exports the users and buckets collected by the last
+// syncs and whether the cycle is partial
+func populateCompleteness(status *PrysmStatus, cfg RadosGWUsageConfig) {
+	users, buckets := status.GetCompleteness()
+	for kind, completeness := range map[string]Completeness{"users": users, "buckets": buckets} {
+		labels := prometheus.Labels{
+			"kind":           kind,
+			"rgw_cluster_id": cfg.ClusterID,
+			"node":           cfg.NodeName,
+			"instance_id":    cfg.InstanceID,
+		}
+		collectionTotal.With(labels).Set(float64(completeness.Total))
+		collectionFailed.With(labels).Set(float64(completeness.Failed))
+		collectionCompleteness.With(labels).Set(completeness.Ratio())
+	}
+	partial := 0.0
+	if status.Partial(cfg.CompletenessPercent) {
+		partial = 1
+	}
+	collectionPartial.With(prometheus.Labels{
+		"rgw_cluster_id": cfg.ClusterID,
+		"node":           cfg.NodeName,
+		"instance_id":    cfg.InstanceID,
+	}).Set(partial)
+}
+
 func populateMetricsFromKV(userMetrics, bucketMetrics nats.KeyValue, cfg RadosGWUsageConfig) {
 	log.Info().Msg("Starting to populate metrics from KV")
 
+	// The traffic is of the last cycle, categories without requests since go
+	userAPIRequests.Reset()
+	userAPILatency.Reset()
+	bucketAPIRequests.Reset()
+	bucketAPILatency.Reset()
+	bucketAPIOps.Reset()
+	bucketAPISuccessfulOps.Reset()
+	bucketAPIBytesSent.Reset()
+	bucketAPIBytesReceived.Reset()
+
+	// Suspending a user or changing its keys changes the labels of its info
+	userInfo.Reset()
+
 	// Process user metrics
 	populateUserMetricsFromKV(userMetrics, cfg)
 
@@ -156,6 +297,15 @@ func populateUserMetricsFromKV(userMetrics nats.KeyValue, cfg RadosGWUsageConfig
 			"instance_id":    cfg.InstanceID,
 		}).Set(1)
 
+		userInfo.With(prometheus.Labels{
+			"user":           metrics.GetUserIdentification(),
+			"suspended":      strconv.FormatBool(metrics.Suspended),
+			"keys":           strconv.Itoa(metrics.KeyCount),
+			"rgw_cluster_id": cfg.ClusterID,
+			"node":           cfg.NodeName,
+			"instance_id":    cfg.InstanceID,
+		}).Set(1)
+
 		labels := prometheus.Labels{
 			"user":           metrics.GetUserIdentification(),
 			"rgw_cluster_id": cfg.ClusterID,
@@ -166,6 +316,9 @@ func populateUserMetricsFromKV(userMetrics nats.KeyValue, cfg RadosGWUsageConfig
 		userBucketsTotal.With(labels).Set(float64(metrics.BucketsTotal))
 		userObjectsTotal.With(labels).Set(float64(metrics.ObjectsTotal))
 		userDataSizeTotal.With(labels).Set(float64(metrics.DataSizeTotal))
+		if !metrics.FirstSeen.IsZero() {
+			userFirstSeen.With(labels).Set(float64(metrics.FirstSeen.Unix()))
+		}
 
 		// User quota metrics
 		userQuotaEnabled.With(labels).Set(boolToFloat64(&metrics.UserQuotaEnabled))
@@ -175,6 +328,19 @@ func populateUserMetricsFromKV(userMetrics nats.KeyValue, cfg RadosGWUsageConfig
 		if metrics.UserQuotaMaxObjects != nil && *metrics.UserQuotaMaxObjects > 0 {
 			userQuotaMaxObjects.With(labels).Set(float64(*metrics.UserQuotaMaxObjects))
 		}
+
+		// Ops log traffic
+		for category, traffic := range metrics.Traffic {
+			apiLabels := prometheus.Labels{
+				"user":           metrics.GetUserIdentification(),
+				"category":       category,
+				"rgw_cluster_id": cfg.ClusterID,
+				"node":           cfg.NodeName,
+				"instance_id":    cfg.InstanceID,
+			}
+			userAPIRequests.With(apiLabels).Set(float64(traffic.Requests))
+			userAPILatency.With(apiLabels).Set(traffic.LatencySeconds)
+		}
 	}
 }
 
@@ -185,6 +351,8 @@ func populateBucketMetricsFromKV(bucketMetrics nats.KeyValue, cfg RadosGWUsageCo
 		return
 	}
 
+	accessCounts := map[string]int{}
+	largeOmapCount := 0
 	for _, key := range keys {
 		entry, err := bucketMetrics.Get(key)
 		if err != nil {
@@ -217,6 +385,20 @@ func populateBucketMetricsFromKV(bucketMetrics nats.KeyValue, cfg RadosGWUsageCo
 		if metrics.NumShards != nil {
 			bucketShards.With(labels).Set(float64(*metrics.NumShards))
 		}
+		if metrics.NumShards != nil && *metrics.NumShards > 0 {
+			bucketObjectsPerShard.With(labels).Set(metrics.ObjectsPerShard)
+			bucketReshardRecommended.With(labels).Set(boolToFloat64(&metrics.ReshardNeeded))
+		}
+
+		// Set OMAP pressure information
+		bucketIndexEntries.With(labels).Set(float64(metrics.IndexEntries))
+		if metrics.NumShards != nil && *metrics.NumShards > 0 {
+			bucketOmapKeysPerShard.With(labels).Set(metrics.OmapKeysPerShard)
+			bucketLargeOmap.With(labels).Set(boolToFloat64(&metrics.LargeOmap))
+		}
+		if metrics.LargeOmap {
+			largeOmapCount++
+		}
 
 		// Set quota information
 		bucketQuotaEnabled.With(labels).Set(boolToFloat64(&metrics.QuotaEnabled))
@@ -226,6 +408,84 @@ func populateBucketMetricsFromKV(bucketMetrics nats.KeyValue, cfg RadosGWUsageCo
 		if metrics.QuotaMaxObjects != nil && *metrics.QuotaMaxObjects > 0 {
 			bucketQuotaMaxObjects.With(labels).Set(float64(*metrics.QuotaMaxObjects))
 		}
+
+		// Ops log traffic
+		for category, traffic := range metrics.Traffic {
+			apiLabels := prometheus.Labels{
+				"bucket":         metrics.BucketID,
+				"owner":          metrics.GetUserIdentification(),
+				"zonegroup":      metrics.Zonegroup,
+				"category":       category,
+				"rgw_cluster_id": cfg.ClusterID,
+				"node":           cfg.NodeName,
+				"instance_id":    cfg.InstanceID,
+			}
+			bucketAPIRequests.With(apiLabels).Set(float64(traffic.Requests))
+			bucketAPILatency.With(apiLabels).Set(traffic.LatencySeconds)
+		}
+
+		// Usage log totals
+		for category, usage := range metrics.Usage {
+			apiLabels := prometheus.Labels{
+				"bucket":         metrics.BucketID,
+				"owner":          metrics.GetUserIdentification(),
+				"zonegroup":      metrics.Zonegroup,
+				"category":       category,
+				"rgw_cluster_id": cfg.ClusterID,
+				"node":           cfg.NodeName,
+				"instance_id":    cfg.InstanceID,
+			}
+			bucketAPIOps.With(apiLabels).Set(float64(usage.Ops))
+			bucketAPISuccessfulOps.With(apiLabels).Set(float64(usage.SuccessfulOps))
+			bucketAPIBytesSent.With(apiLabels).Set(float64(usage.BytesSent))
+			bucketAPIBytesReceived.With(apiLabels).Set(float64(usage.BytesReceived))
+		}
+
+		// Set access audit information
+		if metrics.Access != nil {
+			for access, granted := range accessFlags(metrics.Access) {
+				bucketAccess.With(prometheus.Labels{
+					"bucket":         metrics.BucketID,
+					"owner":          metrics.GetUserIdentification(),
+					"zonegroup":      metrics.Zonegroup,
+					"access":         access,
+					"rgw_cluster_id": cfg.ClusterID,
+					"node":           cfg.NodeName,
+					"instance_id":    cfg.InstanceID,
+				}).Set(boolToFloat64(&granted))
+				if granted {
+					accessCounts[access]++
+				}
+			}
+		}
+	}
+
+	if cfg.LargeOmapKeysPerShard > 0 {
+		largeOmapBuckets.With(prometheus.Labels{
+			"rgw_cluster_id": cfg.ClusterID,
+			"node":           cfg.NodeName,
+			"instance_id":    cfg.InstanceID,
+		}).Set(float64(largeOmapCount))
+	}
+
+	if cfg.AuditBucketAccess {
+		for access := range accessFlags(&BucketAccess{}) {
+			accessBuckets.With(prometheus.Labels{
+				"access":         access,
+				"rgw_cluster_id": cfg.ClusterID,
+				"node":           cfg.NodeName,
+				"instance_id":    cfg.InstanceID,
+			}).Set(float64(accessCounts[access]))
+		}
+	}
+}
+
+// accessFlags returns the access of a bucket by the access label
+func accessFlags(access *BucketAccess) map[string]bool {
+	return map[string]bool{
+		"public_read":   access.PublicRead,
+		"public_write":  access.PublicWrite,
+		"authenticated": access.Authenticated,
 	}
 }
 
@@ -12,6 +12,28 @@ type PrysmStatus struct {
 	mu           sync.Mutex
 	TargetUp     float64
 	ScrapeErrors int
+	UsersCreated int // Users created since the previous user sync
+	UsersRemoved int // Users removed since the previous user sync
+
+	// Users and buckets collected by the last user and bucket sync
+	Users   Completeness
+	Buckets Completeness
+}
+
+// Completeness counts the users or buckets a sync listed and the ones it
+// failed to collect, which keep the data of a previous sync
+type Completeness struct {
+	Total  int
+	Failed int
+}
+
+// Ratio is the share of the listed users or buckets collected, 1 if none
+// were listed
+func (c Completeness) Ratio() float64 {
+	if c.Total == 0 {
+		return 1
+	}
+	return float64(c.Total-c.Failed) / float64(c.Total)
 }
 
 func (s *PrysmStatus) UpdateTargetUp(up bool) {
@@ -35,3 +57,46 @@ func (s *PrysmStatus) GetSnapshot() (float64, int) {
 	defer s.mu.Unlock()
 	return s.TargetUp, s.ScrapeErrors
 }
+
+// UpdateUserLifecycle records the users created and removed by the last
+// user sync
+func (s *PrysmStatus) UpdateUserLifecycle(created, removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.UsersCreated = created
+	s.UsersRemoved = removed
+}
+
+func (s *PrysmStatus) GetUserLifecycle() (created, removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.UsersCreated, s.UsersRemoved
+}
+
+// UpdateUserCompleteness records the users collected by the last user sync
+func (s *PrysmStatus) UpdateUserCompleteness(c Completeness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Users = c
+}
+
+// UpdateBucketCompleteness records the buckets collected by the last bucket
+// sync
+func (s *PrysmStatus) UpdateBucketCompleteness(c Completeness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Buckets = c
+}
+
+func (s *PrysmStatus) GetCompleteness() (users, buckets Completeness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Users, s.Buckets
+}
+
+// Partial reports whether the last syncs collected less than percent of the
+// users or buckets, so the metrics derived from them are partial
+func (s *PrysmStatus) Partial(percent int) bool {
+	users, buckets := s.GetCompleteness()
+	return min(users.Ratio(), buckets.Ratio())*100 < float64(percent)
+}
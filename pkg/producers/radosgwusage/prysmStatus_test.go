@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import "testing"
+
+func TestCompletenessRatio(t *testing.T) {
+	if ratio := (Completeness{}).Ratio(); ratio != 1 {
+		t.Fatalf("expected a sync without users complete, got %v", ratio)
+	}
+	if ratio := (Completeness{Total: 200, Failed: 3}).Ratio(); ratio != 0.985 {
+		t.Fatalf("expected 0.985, got %v", ratio)
+	}
+}
+
+func TestPrysmStatusPartial(t *testing.T) {
+	status := &PrysmStatus{}
+	if status.Partial(100) {
+		t.Fatal("expected a status before the first sync not partial")
+	}
+
+	status.UpdateUserCompleteness(Completeness{Total: 50})
+	status.UpdateBucketCompleteness(Completeness{Total: 200, Failed: 3})
+	if !status.Partial(100) {
+		t.Fatal("expected a failed bucket to make the cycle partial at 100 percent")
+	}
+	if status.Partial(95) {
+		t.Fatal("expected 98.5 percent of the buckets complete enough at 95 percent")
+	}
+	if status.Partial(0) {
+		t.Fatal("expected 0 percent to never mark partial")
+	}
+}
+
+func TestMarkPartial(t *testing.T) {
+	if metadata := markPartial(map[string]string{"rgw_cluster_id": "a"}, false); len(metadata) != 1 {
+		t.Fatalf("expected a complete cycle unmarked, got %v", metadata)
+	}
+	if metadata := markPartial(map[string]string{"rgw_cluster_id": "a"}, true); metadata["partial"] != "true" {
+		t.Fatalf("expected the event marked partial, got %v", metadata)
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+)
+
+// fakeRadosGWAdmin answers radosgw-admin commands from their outputs, keyed
+// by the command line without the common options
+func fakeRadosGWAdmin(t *testing.T, outputs map[string]string) func(ctx context.Context, args ...string) ([]byte, error) {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		i := strings.Index(strings.Join(args, " "), " --format json")
+		if i < 0 {
+			t.Fatalf("expected JSON output of %v", args)
+		}
+		command := strings.Join(args, " ")[:i]
+		out, ok := outputs[command]
+		if !ok {
+			return nil, fmt.Errorf("radosgw-admin %s failed: exit status 2", command)
+		}
+		return []byte(out), nil
+	}
+}
+
+func TestCLICollector_SyncUsers(t *testing.T) {
+	cli := rgwadmin.NewCLI("", "", "client.admin", "")
+	cli.Run = fakeRadosGWAdmin(t, map[string]string{
+		"user list":                     `["alice","tenant-a$bob"]`,
+		"user info --uid alice":         `{"user_id":"alice","display_name":"Alice","max_buckets":1000,"keys":[{"user":"alice","access_key":"AK","secret_key":"SK"}],"swift_keys":[{"user":"alice:swift","secret_key":"SW"}]}`,
+		"user stats --uid alice":        `{"stats":{"size":2048,"size_actual":4096,"num_objects":3}}`,
+		"user info --uid tenant-a$bob":  `{"user_id":"bob","tenant":"tenant-a"}`,
+		"user stats --uid tenant-a$bob": `{"stats":{"size":0,"size_actual":0,"num_objects":0}}`,
+	})
+
+	userData := newTestKV("user_data", nil)
+	completeness, err := fetchAllUsers(context.Background(), cli, userData)
+	if err != nil {
+		t.Fatalf("fetch users: %v", err)
+	}
+	if completeness != (Completeness{Total: 2}) {
+		t.Fatalf("expected both users collected, got %+v", completeness)
+	}
+
+	var alice rgwadmin.KVUser
+	if err := json.Unmarshal(userData.data[BuildUserTenantKey("alice", "")], &alice); err != nil {
+		t.Fatalf("unmarshal alice: %v", err)
+	}
+	if alice.DisplayName != "Alice" || *alice.Stats.Size != 2048 || *alice.Stats.SizeRounded != 4096 || *alice.Stats.NumObjects != 3 {
+		t.Fatalf("unexpected user %+v", alice)
+	}
+	if alice.KeyCount != 2 {
+		t.Fatalf("expected the S3 and Swift key counted, got %d", alice.KeyCount)
+	}
+	if strings.Contains(string(userData.data[BuildUserTenantKey("alice", "")]), "SK") {
+		t.Fatalf("expected no secrets in KV, got %s", userData.data[BuildUserTenantKey("alice", "")])
+	}
+	if _, ok := userData.data[BuildUserTenantKey("bob", "tenant-a")]; !ok {
+		t.Fatalf("expected the tenant user in KV, got %v", userData.data)
+	}
+}
+
+func TestCLICollector_Buckets(t *testing.T) {
+	cli := rgwadmin.NewCLI("", "/etc/ceph/ceph.conf", "", "")
+	cli.Run = fakeRadosGWAdmin(t, map[string]string{
+		"bucket list":                               `["photos"]`,
+		"bucket stats --bucket photos":              `{"bucket":"photos","id":"abc.1","owner":"alice","num_shards":11,"usage":{"rgw.main":{"size":100,"num_objects":2}}}`,
+		"policy --bucket photos":                    `{"acl":{"acl_group_map":[{"group":1,"acl":1}],"grant_map":[]},"owner":{"id":"alice"}}`,
+		"metadata get bucket.instance:photos:abc.1": `{"data":{"attrs":[]}}`,
+	})
+
+	bucket, err := cli.GetBucketInfo(context.Background(), rgwadmin.Bucket{Bucket: "photos"})
+	if err != nil {
+		t.Fatalf("bucket stats: %v", err)
+	}
+	if bucket.Owner != "alice" || bucket.ID != "abc.1" {
+		t.Fatalf("unexpected bucket %+v", bucket)
+	}
+
+	access, err := fetchBucketAccess(context.Background(), cli, bucket)
+	if err != nil {
+		t.Fatalf("bucket access: %v", err)
+	}
+	if !access.PublicRead || access.PublicWrite {
+		t.Fatalf("expected public read access, got %+v", access)
+	}
+
+	if _, err := cli.GetBucketInfo(context.Background(), rgwadmin.Bucket{Bucket: "missing"}); err == nil {
+		t.Fatal("expected an error of a failed command")
+	}
+}
+
+func TestCLICollector_Usage(t *testing.T) {
+	var got []string
+	cli := rgwadmin.NewCLI("", "", "", "")
+	cli.Run = func(ctx context.Context, args ...string) ([]byte, error) {
+		got = args
+		return []byte(`{"entries":[{"user":"alice","buckets":[{"bucket":"photos","categories":[{"category":"get_obj","ops":5,"successful_ops":5}]}]}],"summary":[]}`), nil
+	}
+
+	usage, err := cli.GetUsage(context.Background(), rgwadmin.Usage{UserID: "alice", ShowEntries: ptr(true)})
+	if err != nil {
+		t.Fatalf("usage show: %v", err)
+	}
+	if want := "usage show --uid alice --show-log-entries=true --format json"; strings.Join(got, " ") != want {
+		t.Fatalf("expected %q, got %q", want, strings.Join(got, " "))
+	}
+	if len(usage.Entries) != 1 || usage.Entries[0].Buckets[0].Categories[0].Ops != 5 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+}
+
+func TestCreateRadosGWClient_Source(t *testing.T) {
+	status := &PrysmStatus{}
+	co, err := createRadosGWClient(RadosGWUsageConfig{Source: SourceCLI}, status)
+	if err != nil {
+		t.Fatalf("cli source: %v", err)
+	}
+	if _, ok := co.(*rgwadmin.CLI); !ok {
+		t.Fatalf("expected the radosgw-admin collector, got %T", co)
+	}
+	if _, err := createRadosGWClient(RadosGWUsageConfig{Source: "s3"}, status); err == nil {
+		t.Fatal("expected an unknown source to be rejected")
+	}
+}
@@ -5,14 +5,49 @@
 package radosgwusage
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
 )
 
-func createRadosGWClient(cfg RadosGWUsageConfig, status *PrysmStatus) (*rgwadmin.API, error) {
-	httpClient := &http.Client{Timeout: 30 * time.Second}
+// Sources of the users, buckets and usage
+const (
+	SourceAdminAPI = "admin-api" // the admin REST API of RGW
+	SourceCLI      = "cli"       // radosgw-admin, needs ceph.conf and a keyring
+)
+
+// Collector reads the users, buckets and usage of the object store, from the
+// admin API (rgwadmin.API) or the radosgw-admin CLI (rgwadmin.CLI)
+type Collector interface {
+	GetUsers(ctx context.Context) ([]string, error)
+	GetKVUser(ctx context.Context, user rgwadmin.User) (rgwadmin.KVUser, error)
+	ListBuckets(ctx context.Context) ([]string, error)
+	GetBucketInfo(ctx context.Context, bucket rgwadmin.Bucket) (rgwadmin.Bucket, error)
+	GetUsage(ctx context.Context, usage rgwadmin.Usage) (rgwadmin.Usage, error)
+	GetBucketPolicy(ctx context.Context, bucket string) (rgwadmin.BucketPolicy, error)
+	GetBucketIAMPolicy(ctx context.Context, bucket rgwadmin.Bucket) ([]byte, error)
+}
+
+func createRadosGWClient(cfg RadosGWUsageConfig, status *PrysmStatus) (Collector, error) {
+	switch cfg.Source {
+	case SourceAdminAPI, "":
+	case SourceCLI:
+		status.UpdateTargetUp(true)
+		return rgwadmin.NewCLI(cfg.RadosGWAdminBinary, cfg.CephConf, cfg.CephName, cfg.CephKeyring), nil
+	default:
+		return nil, fmt.Errorf("unknown source %q, expected %s or %s", cfg.Source, SourceAdminAPI, SourceCLI)
+	}
+
+	faults, err := ParseAdminAPIFaults(cfg.AdminAPIFaults)
+	if err != nil {
+		return nil, err
+	}
+	// Every admin API request is traced as a child of the sync stage
+	httpClient := &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(injectFaults(nil, faults))}
 	co, err := rgwadmin.New(cfg.AdminURL, cfg.AccessKey, cfg.SecretKey, httpClient)
 	if err != nil {
 		// Explicitly set TargetUp to false on failure
@@ -8,9 +8,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
@@ -24,9 +26,21 @@ type UserBucketMetrics struct {
 	BucketSize      uint64  // Total size consumed by the bucket, including all objects. Important for capacity tracking.
 	CreationTime    string  // Knowing when a bucket was created can be useful for tracking lifecycle and access management.
 	NumShards       *uint64 // Shards
+	ObjectsPerShard float64 // Average number of objects per index shard; zero when the shard count is unknown.
+	ReshardNeeded   bool    // Set when ObjectsPerShard exceeds the configured resharding threshold.
+
+	// OMAP pressure of the bucket index, one OMAP key per index entry
+	IndexEntries     uint64  // Entries of the bucket index: objects, incomplete multipart uploads and entries without data
+	OmapKeysPerShard float64 // Average number of OMAP keys per index shard; zero when the shard count is unknown.
+	LargeOmap        bool    // Set when OmapKeysPerShard exceeds the large OMAP threshold.
+
 	QuotaEnabled    bool
 	QuotaMaxSize    *int64
 	QuotaMaxObjects *int64
+	Access          *BucketAccess         // Access granted beyond the owner; nil when not audited.
+	Traffic         map[string]APITraffic // Ops log traffic of the last cycle by API category; nil unless joined.
+	Usage           map[string]APIUsage   // Usage log totals by API category; nil when RGW logged no usage.
+	Partial         bool                  // The cycle collected less than --completeness-percent of the users or buckets.
 }
 
 func (m *UserBucketMetrics) GetUserIdentification() string {
@@ -36,7 +50,7 @@ func (m *UserBucketMetrics) GetUserIdentification() string {
 	return m.User
 }
 
-func updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics nats.KeyValue) error {
+func updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics nats.KeyValue, reshardThreshold, largeOmapThreshold int, partial bool) error {
 	log.Debug().Msg("Starting bucket-level metrics aggregation")
 
 	bucketKeys, err := bucketData.Keys()
@@ -54,8 +68,9 @@ func updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics nats.KeyVa
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			defer telemetry.RecoverPanic("radosgw-usage.bucket-metrics")
 			for key := range bucketCh {
-				processBucketMetrics(key, bucketData, userUsageData, bucketMetrics)
+				processBucketMetrics(key, bucketData, userUsageData, bucketMetrics, reshardThreshold, largeOmapThreshold, partial)
 			}
 		}()
 	}
@@ -71,7 +86,7 @@ func updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics nats.KeyVa
 	return nil
 }
 
-func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics nats.KeyValue) {
+func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics nats.KeyValue, reshardThreshold, largeOmapThreshold int, partial bool) {
 	// Fetch bucket metadata
 	entry, err := bucketData.Get(key)
 	if err != nil {
@@ -83,11 +98,12 @@ func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics n
 		return
 	}
 
-	var bucket rgwadmin.Bucket
-	if err := json.Unmarshal(entry.Value(), &bucket); err != nil {
+	var record bucketRecord
+	if err := json.Unmarshal(entry.Value(), &record); err != nil {
 		log.Warn().Str("bucket_key", key).Err(err).Msg("Failed to unmarshal bucket data")
 		return
 	}
+	bucket := record.Bucket
 
 	log.Debug().
 		Str("bucket_id", bucket.Bucket).
@@ -102,6 +118,8 @@ func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics n
 		Tenant:       tenant,
 		CreationTime: bucket.Mtime, // Using Mtime as a substitute for creation time.
 		Zonegroup:    bucket.Zonegroup,
+		Access:       record.Access,
+		Partial:      partial,
 	}
 
 	// (Populate other static fields as needed.)
@@ -111,10 +129,18 @@ func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics n
 	if bucket.Usage.RgwMain.SizeActual != nil {
 		metrics.BucketSize = *bucket.Usage.RgwMain.SizeActual
 	}
+	metrics.NumShards = bucket.NumShards
+	metrics.ObjectsPerShard, metrics.ReshardNeeded = shardPressure(metrics.ObjectCount, metrics.NumShards, reshardThreshold)
+	metrics.IndexEntries = indexEntries(bucket.Usage)
+	metrics.OmapKeysPerShard, metrics.LargeOmap = shardPressure(metrics.IndexEntries, metrics.NumShards, largeOmapThreshold)
 
 	// Keep bucket metrics independent from usage KV availability.
 	// Usage records can legitimately be missing for some buckets.
-	_ = userUsageData
+	usage, err := loadBucketAPIUsage(userUsageData, key)
+	if err != nil {
+		log.Warn().Str("bucket_key", key).Err(err).Msg("Failed to load bucket usage")
+	}
+	metrics.Usage = usage
 
 	// Set quota information.
 	metrics.QuotaEnabled = false
@@ -144,3 +170,61 @@ func processBucketMetrics(key string, bucketData, userUsageData, bucketMetrics n
 			Msg("Bucket metrics stored in KV successfully")
 	}
 }
+
+// shardPressure returns the average number of objects per bucket index shard
+// and whether that average exceeds threshold. A nil or zero shard count means
+// RGW did not report a usable value, in which case no recommendation is made.
+func shardPressure(objects uint64, shards *uint64, threshold int) (float64, bool) {
+	if shards == nil || *shards == 0 {
+		return 0, false
+	}
+	perShard := float64(objects) / float64(*shards)
+	return perShard, threshold > 0 && perShard > float64(threshold)
+}
+
+// indexEntries sums the entries of the bucket index over the usage
+// categories. Neither the admin API nor the bucket stats report the OMAP keys
+// of the shards, but every entry is one key of its shard.
+func indexEntries(usage rgwadmin.BucketUsage) uint64 {
+	var entries uint64
+	for _, count := range []*uint64{usage.RgwMain.NumObjects, usage.RgwMultimeta.NumObjects, usage.RgwNone.NumObjects} {
+		if count != nil {
+			entries += *count
+		}
+	}
+	return entries
+}
+
+// collectReshardCandidates returns the KV keys of all buckets whose stored
+// metrics are flagged as needing resharding.
+func collectReshardCandidates(bucketMetrics nats.KeyValue) ([]string, error) {
+	keys, err := bucketMetrics.Keys()
+	if err != nil {
+		if errors.Is(err, nats.ErrNoKeysFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch keys from bucket metrics: %w", err)
+	}
+
+	var candidates []string
+	for _, key := range keys {
+		entry, err := bucketMetrics.Get(key)
+		if err != nil {
+			if !errors.Is(err, nats.ErrKeyNotFound) {
+				log.Warn().Str("key", key).Err(err).Msg("Failed to fetch bucket metric")
+			}
+			continue
+		}
+
+		var metrics UserBucketMetrics
+		if err := json.Unmarshal(entry.Value(), &metrics); err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to unmarshal bucket metric")
+			continue
+		}
+		if metrics.ReshardNeeded {
+			candidates = append(candidates, key)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
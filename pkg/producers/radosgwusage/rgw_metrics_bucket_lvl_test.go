@@ -6,12 +6,9 @@ package radosgwusage
 
 import (
 	"encoding/json"
-	"fmt"
 	"testing"
-	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
-	"github.com/nats-io/nats.go"
 )
 
 func TestProcessBucketMetrics_ContinuesWhenUsageKeyMissing(t *testing.T) {
@@ -54,7 +51,7 @@ func TestProcessBucketMetrics_ContinuesWhenUsageKeyMissing(t *testing.T) {
 	userUsageData := newTestKV("user_usage_data", nil)
 	bucketMetrics := newTestKV("bucket_metrics", nil)
 
-	processBucketMetrics(key, bucketData, userUsageData, bucketMetrics)
+	processBucketMetrics(key, bucketData, userUsageData, bucketMetrics, 0, 0, false)
 
 	entry, err := bucketMetrics.Get(key)
 	if err != nil {
@@ -95,194 +92,172 @@ func TestProcessBucketMetrics_ContinuesWhenUsageKeyMissing(t *testing.T) {
 	}
 }
 
-type testKV struct {
-	bucket string
-	data   map[string][]byte
-}
+func TestProcessBucketMetrics_ShardPressure(t *testing.T) {
+	numObjects := uint64(250000)
+	numShards := uint64(2)
 
-func newTestKV(bucket string, seed map[string][]byte) *testKV {
-	cloned := make(map[string][]byte, len(seed))
-	for k, v := range seed {
-		vv := make([]byte, len(v))
-		copy(vv, v)
-		cloned[k] = vv
-	}
-	return &testKV{
-		bucket: bucket,
-		data:   cloned,
+	bucketJSON := func(name string, shards *uint64) []byte {
+		b, err := json.Marshal(rgwadmin.Bucket{
+			Bucket:    name,
+			Owner:     "user-a",
+			NumShards: shards,
+			Usage: rgwadmin.BucketUsage{
+				RgwMain: rgwadmin.BucketUsageRgwMain{NumObjects: &numObjects},
+			},
+		})
+		if err != nil {
+			t.Fatalf("marshal bucket: %v", err)
+		}
+		return b
 	}
-}
-
-func (kv *testKV) Get(key string) (nats.KeyValueEntry, error) {
-	v, ok := kv.data[key]
-	if !ok {
-		return nil, nats.ErrKeyNotFound
-	}
-	vv := make([]byte, len(v))
-	copy(vv, v)
-	return &testKVEntry{bucket: kv.bucket, key: key, value: vv}, nil
-}
 
-func (kv *testKV) GetRevision(key string, revision uint64) (nats.KeyValueEntry, error) {
-	_ = revision
-	return kv.Get(key)
-}
-
-func (kv *testKV) Put(key string, value []byte) (uint64, error) {
-	v := make([]byte, len(value))
-	copy(v, value)
-	kv.data[key] = v
-	return uint64(len(kv.data)), nil
-}
+	hotKey := BuildUserTenantBucketKey("user-a", "", "hot")
+	unknownKey := BuildUserTenantBucketKey("user-a", "", "unknown")
+	bucketData := newTestKV("bucket_data", map[string][]byte{
+		hotKey:     bucketJSON("hot", &numShards),
+		unknownKey: bucketJSON("unknown", nil),
+	})
+	bucketMetrics := newTestKV("bucket_metrics", nil)
 
-func (kv *testKV) PutString(key string, value string) (uint64, error) {
-	return kv.Put(key, []byte(value))
-}
+	processBucketMetrics(hotKey, bucketData, newTestKV("user_usage_data", nil), bucketMetrics, 100000, 0, false)
+	processBucketMetrics(unknownKey, bucketData, newTestKV("user_usage_data", nil), bucketMetrics, 100000, 0, false)
 
-func (kv *testKV) Create(key string, value []byte) (uint64, error) {
-	if _, exists := kv.data[key]; exists {
-		return 0, fmt.Errorf("key exists")
+	entry, err := bucketMetrics.Get(hotKey)
+	if err != nil {
+		t.Fatalf("expected bucket metric to be stored, got error: %v", err)
 	}
-	return kv.Put(key, value)
-}
-
-func (kv *testKV) Update(key string, value []byte, last uint64) (uint64, error) {
-	_ = last
-	return kv.Put(key, value)
-}
-
-func (kv *testKV) Delete(key string, opts ...nats.DeleteOpt) error {
-	_ = opts
-	delete(kv.data, key)
-	return nil
-}
-
-func (kv *testKV) Purge(key string, opts ...nats.DeleteOpt) error {
-	_ = opts
-	delete(kv.data, key)
-	return nil
-}
-
-func (kv *testKV) Watch(keys string, opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
-	_ = keys
-	_ = opts
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (kv *testKV) WatchAll(opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
-	_ = opts
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (kv *testKV) WatchFiltered(keys []string, opts ...nats.WatchOpt) (nats.KeyWatcher, error) {
-	_ = keys
-	_ = opts
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (kv *testKV) Keys(opts ...nats.WatchOpt) ([]string, error) {
-	_ = opts
-	keys := make([]string, 0, len(kv.data))
-	for k := range kv.data {
-		keys = append(keys, k)
+	var got UserBucketMetrics
+	if err := json.Unmarshal(entry.Value(), &got); err != nil {
+		t.Fatalf("unmarshal stored metric: %v", err)
+	}
+	if got.NumShards == nil || *got.NumShards != numShards {
+		t.Fatalf("unexpected shard count: %+v", got.NumShards)
+	}
+	if got.ObjectsPerShard != 125000 {
+		t.Fatalf("unexpected objects per shard: %v", got.ObjectsPerShard)
+	}
+	if !got.ReshardNeeded {
+		t.Fatalf("expected reshard to be recommended")
 	}
-	return keys, nil
-}
-
-func (kv *testKV) ListKeys(opts ...nats.WatchOpt) (nats.KeyLister, error) {
-	_ = opts
-	return nil, fmt.Errorf("not implemented")
-}
 
-func (kv *testKV) History(key string, opts ...nats.WatchOpt) ([]nats.KeyValueEntry, error) {
-	_ = opts
-	entry, err := kv.Get(key)
+	candidates, err := collectReshardCandidates(bucketMetrics)
 	if err != nil {
-		return nil, err
+		t.Fatalf("collect reshard candidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0] != hotKey {
+		t.Fatalf("unexpected reshard candidates: %v", candidates)
 	}
-	return []nats.KeyValueEntry{entry}, nil
-}
-
-func (kv *testKV) Bucket() string {
-	return kv.bucket
-}
-
-func (kv *testKV) PurgeDeletes(opts ...nats.PurgeOpt) error {
-	_ = opts
-	return nil
-}
-
-func (kv *testKV) Status() (nats.KeyValueStatus, error) {
-	return &testKVStatus{bucket: kv.bucket, values: uint64(len(kv.data))}, nil
-}
-
-type testKVEntry struct {
-	bucket string
-	key    string
-	value  []byte
-}
-
-func (e *testKVEntry) Bucket() string {
-	return e.bucket
-}
-
-func (e *testKVEntry) Key() string {
-	return e.key
-}
-
-func (e *testKVEntry) Value() []byte {
-	return e.value
-}
-
-func (e *testKVEntry) Revision() uint64 {
-	return 1
-}
-
-func (e *testKVEntry) Created() time.Time {
-	return time.Unix(0, 0)
-}
-
-func (e *testKVEntry) Delta() uint64 {
-	return 0
-}
-
-func (e *testKVEntry) Operation() nats.KeyValueOp {
-	return nats.KeyValuePut
-}
-
-type testKVStatus struct {
-	bucket string
-	values uint64
 }
 
-func (s *testKVStatus) Bucket() string {
-	return s.bucket
-}
+func TestProcessBucketMetrics_LargeOmap(t *testing.T) {
+	objects, uploads, markers := uint64(300000), uint64(50000), uint64(60000)
+	numShards := uint64(2)
+	key := BuildUserTenantBucketKey("user-a", "", "versions")
+	bucketJSON, err := json.Marshal(rgwadmin.Bucket{
+		Bucket:    "versions",
+		Owner:     "user-a",
+		NumShards: &numShards,
+		Usage: rgwadmin.BucketUsage{
+			RgwMain:      rgwadmin.BucketUsageRgwMain{NumObjects: &objects},
+			RgwMultimeta: rgwadmin.BucketUsageRgwMultimeta{NumObjects: &uploads},
+			RgwNone:      rgwadmin.BucketUsageRgwNone{NumObjects: &markers},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal bucket: %v", err)
+	}
 
-func (s *testKVStatus) Values() uint64 {
-	return s.values
-}
+	bucketMetrics := newTestKV("bucket_metrics", nil)
+	processBucketMetrics(key, newTestKV("bucket_data", map[string][]byte{key: bucketJSON}), newTestKV("user_usage_data", nil), bucketMetrics, 0, 200000, false)
 
-func (s *testKVStatus) History() int64 {
-	return 1
+	entry, err := bucketMetrics.Get(key)
+	if err != nil {
+		t.Fatalf("expected bucket metric to be stored, got error: %v", err)
+	}
+	var got UserBucketMetrics
+	if err := json.Unmarshal(entry.Value(), &got); err != nil {
+		t.Fatalf("unmarshal stored metric: %v", err)
+	}
+	if got.IndexEntries != 410000 || got.OmapKeysPerShard != 205000 {
+		t.Fatalf("unexpected index entries %d and OMAP keys per shard %v", got.IndexEntries, got.OmapKeysPerShard)
+	}
+	if !got.LargeOmap {
+		t.Fatalf("expected a large OMAP warning from the uploads and delete markers")
+	}
+	if got.ReshardNeeded {
+		t.Fatalf("expected no reshard recommendation with the threshold disabled")
+	}
 }
 
-func (s *testKVStatus) TTL() time.Duration {
-	return 0
-}
+func TestProcessBucketMetrics_UsageByAPICategory(t *testing.T) {
+	key := BuildUserTenantBucketKey("user-a", "", "photos")
+	bucketJSON, err := json.Marshal(rgwadmin.Bucket{Bucket: "photos", Owner: "user-a"})
+	if err != nil {
+		t.Fatalf("marshal bucket: %v", err)
+	}
+	usageJSON, err := json.Marshal(rgwadmin.UsageEntryBucket{
+		Bucket: "photos",
+		Owner:  "user-a",
+		Categories: []rgwadmin.UsageEntryCategory{
+			{Category: "get_obj", Ops: 5, SuccessfulOps: 4, BytesSent: 500},
+			{Category: "stat_object", Ops: 2, SuccessfulOps: 2},
+			{Category: "put_obj", Ops: 3, SuccessfulOps: 3, BytesReceived: 300},
+			{Category: "list_bucket", Ops: 1, SuccessfulOps: 1, BytesSent: 10},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal usage: %v", err)
+	}
 
-func (s *testKVStatus) BackingStore() string {
-	return "memory"
-}
+	bucketMetrics := newTestKV("bucket_metrics", nil)
+	processBucketMetrics(key, newTestKV("bucket_data", map[string][]byte{key: bucketJSON}),
+		newTestKV("user_usage_data", map[string][]byte{key: usageJSON}), bucketMetrics, 0, 0, false)
 
-func (s *testKVStatus) Bytes() uint64 {
-	return 0
+	entry, err := bucketMetrics.Get(key)
+	if err != nil {
+		t.Fatalf("expected bucket metric to be stored, got error: %v", err)
+	}
+	var got UserBucketMetrics
+	if err := json.Unmarshal(entry.Value(), &got); err != nil {
+		t.Fatalf("unmarshal stored metric: %v", err)
+	}
+	expected := map[string]APIUsage{
+		"read":  {Ops: 7, SuccessfulOps: 6, BytesSent: 500},
+		"write": {Ops: 3, SuccessfulOps: 3, BytesReceived: 300},
+		"list":  {Ops: 1, SuccessfulOps: 1, BytesSent: 10},
+	}
+	if len(got.Usage) != len(expected) {
+		t.Fatalf("unexpected usage: %+v", got.Usage)
+	}
+	for category, usage := range expected {
+		if got.Usage[category] != usage {
+			t.Fatalf("unexpected %s usage: %+v", category, got.Usage[category])
+		}
+	}
 }
 
-func (s *testKVStatus) IsCompressed() bool {
-	return false
+func TestShardPressure_Threshold(t *testing.T) {
+	shards := uint64(10)
+	if perShard, needed := shardPressure(1000, &shards, 100); perShard != 100 || needed {
+		t.Fatalf("expected 100 objects per shard without recommendation, got %v/%v", perShard, needed)
+	}
+	if _, needed := shardPressure(1001, &shards, 100); !needed {
+		t.Fatalf("expected recommendation above threshold")
+	}
+	if _, needed := shardPressure(1001, &shards, 0); needed {
+		t.Fatalf("expected no recommendation when threshold is disabled")
+	}
+	zero := uint64(0)
+	if perShard, needed := shardPressure(1001, &zero, 100); perShard != 0 || needed {
+		t.Fatalf("expected zero shards to be ignored, got %v/%v", perShard, needed)
+	}
 }
 
-func (s *testKVStatus) Config() nats.KeyValueConfig {
-	return nats.KeyValueConfig{Bucket: s.bucket}
+// newTestKV returns an in-memory KV holding seed
+func newTestKV(bucket string, seed map[string][]byte) *memoryKV {
+	kv := newMemoryKV(bucket)
+	for k, v := range seed {
+		_, _ = kv.Put(k, v)
+	}
+	return kv
 }
@@ -10,8 +10,9 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
@@ -29,6 +30,11 @@ type UserLevelMetrics struct {
 	UserQuotaEnabled    bool
 	UserQuotaMaxSize    *int64
 	UserQuotaMaxObjects *int64
+	Suspended           bool
+	KeyCount            int                   // S3 and Swift keys of the user
+	FirstSeen           time.Time             // When the exporter first saw the user
+	Traffic             map[string]APITraffic // Ops log traffic of the last cycle by API category; nil unless joined
+	Partial             bool                  // The cycle collected less than --completeness-percent of the users or buckets
 }
 
 func (m *UserLevelMetrics) GetUserIdentification() string {
@@ -38,7 +44,7 @@ func (m *UserLevelMetrics) GetUserIdentification() string {
 	return m.User
 }
 
-func updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics nats.KeyValue) error {
+func updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics nats.KeyValue, partial bool) error {
 	log.Debug().Msg("Starting user-level metrics aggregation")
 	_ = userUsageData
 
@@ -68,8 +74,9 @@ func updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics nats
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			defer telemetry.RecoverPanic("radosgw-usage.user-metrics")
 			for key := range userCh {
-				processUserMetrics(key, userData, userMetrics, bucketKeyMap)
+				processUserMetrics(key, userData, userMetrics, bucketKeyMap, partial)
 			}
 		}()
 	}
@@ -85,7 +92,7 @@ func updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics nats
 	return nil
 }
 
-func processUserMetrics(key string, userData, userMetrics nats.KeyValue, bucketKeyMap map[string]uint64) {
+func processUserMetrics(key string, userData, userMetrics nats.KeyValue, bucketKeyMap map[string]uint64, partial bool) {
 	entry, err := userData.Get(key)
 	if err != nil {
 		if errors.Is(err, nats.ErrKeyNotFound) {
@@ -96,7 +103,7 @@ func processUserMetrics(key string, userData, userMetrics nats.KeyValue, bucketK
 		return
 	}
 
-	var user rgwadmin.KVUser
+	var user userRecord
 	if err := json.Unmarshal(entry.Value(), &user); err != nil {
 		log.Warn().Str("key", key).Err(err).Msg("Failed to unmarshal user data")
 		return
@@ -115,6 +122,10 @@ func processUserMetrics(key string, userData, userMetrics nats.KeyValue, bucketK
 		DisplayName:         user.DisplayName,
 		Email:               user.Email,
 		DefaultStorageClass: user.DefaultStorageClass,
+		Suspended:           user.Suspended != nil && *user.Suspended != 0,
+		KeyCount:            user.KeyCount,
+		FirstSeen:           user.FirstSeen,
+		Partial:             partial,
 		// Initialize numeric fields to zero.
 	}
 
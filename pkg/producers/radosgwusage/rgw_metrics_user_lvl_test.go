@@ -54,7 +54,7 @@ func TestProcessUserMetrics_PreservesExpectedFields(t *testing.T) {
 		userKey: 3,
 	}
 
-	processUserMetrics(userKey, userData, userMetrics, bucketKeyMap)
+	processUserMetrics(userKey, userData, userMetrics, bucketKeyMap, false)
 
 	entry, err := userMetrics.Get(userKey)
 	if err != nil {
@@ -8,14 +8,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
 
-func syncBuckets(bucketData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
+func syncBuckets(ctx context.Context, bucketData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
 	log.Info().Msg("Starting bucket sync process")
 
 	// Initialize the RadosGW client
@@ -26,28 +26,31 @@ func syncBuckets(bucketData nats.KeyValue, cfg RadosGWUsageConfig, status *Prysm
 	}
 
 	// Fetch all buckets
-	err = fetchAllBuckets(co, bucketData)
+	completeness, err := fetchAllBuckets(ctx, co, bucketData, cfg.AuditBucketAccess)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch all buckets")
 		return err
 	}
+	status.UpdateBucketCompleteness(completeness)
 
 	log.Info().Msg("Bucket synchronization completed")
 
 	return nil
 }
 
-func fetchAllBuckets(co *rgwadmin.API, bucketData nats.KeyValue) error {
+// fetchAllBuckets stores the buckets in the KV and returns how many of them
+// were collected
+func fetchAllBuckets(ctx context.Context, co Collector, bucketData nats.KeyValue, auditAccess bool) (Completeness, error) {
 	// Step 1: Fetch the list of bucket names
-	bucketNames, err := co.ListBuckets(context.Background())
+	bucketNames, err := co.ListBuckets(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list buckets: %w", err)
+		return Completeness{}, fmt.Errorf("failed to list buckets: %w", err)
 	}
 
 	log.Info().Int("total_buckets", len(bucketNames)).Msg("Fetched bucket names")
 
 	// Step 2: Create channels for results and errors
-	bucketDataCh := make(chan rgwadmin.Bucket, len(bucketNames))
+	bucketDataCh := make(chan bucketRecord, len(bucketNames))
 	errCh := make(chan string, len(bucketNames))
 
 	// Step 3: Use a WaitGroup and semaphore to fetch bucket details concurrently
@@ -61,13 +64,26 @@ func fetchAllBuckets(co *rgwadmin.API, bucketData nats.KeyValue) error {
 		go func(bucketName string) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release the token when done
+			defer telemetry.RecoverPanic("radosgw-usage.sync-buckets")
 
-			bucketInfo, err := fetchBucketInfo(co, bucketName)
+			bucketInfo, err := fetchBucketInfo(ctx, co, bucketName)
 			if err != nil {
 				errCh <- bucketName
 				return
 			}
-			bucketDataCh <- bucketInfo
+			record := bucketRecord{Bucket: bucketInfo}
+
+			// A bucket whose access is unknown keeps its previous data, so
+			// a failed fetch never looks like a change of its access
+			if auditAccess {
+				record.Access, err = fetchBucketAccess(ctx, co, bucketInfo)
+				if err != nil {
+					log.Warn().Str("bucket", bucketName).Err(err).Msg("Failed to fetch bucket access")
+					errCh <- bucketName
+					return
+				}
+			}
+			bucketDataCh <- record
 		}(bucketName)
 	}
 
@@ -81,12 +97,13 @@ func fetchAllBuckets(co *rgwadmin.API, bucketData nats.KeyValue) error {
 	var bucketsProcessed, bucketsFailed int
 	seenBucketKeys := make(map[string]struct{}, len(bucketNames))
 
-	for bucket := range bucketDataCh {
+	for record := range bucketDataCh {
 		// bucketData = append(bucketData, bucket)
+		bucket := record.Bucket
 		user, tenant := NormalizeUserTenant(bucket.Owner, bucket.Tenant)
 		bucketKey := BuildUserTenantBucketKey(user, tenant, bucket.Bucket)
 		seenBucketKeys[bucketKey] = struct{}{}
-		if err := storeBucketInKV(bucket, bucketData); err != nil {
+		if err := storeBucketInKV(record, bucketData); err != nil {
 			bucketsFailed++
 			continue
 		}
@@ -111,39 +128,26 @@ func fetchAllBuckets(co *rgwadmin.API, bucketData nats.KeyValue) error {
 			Msg("Skipping bucket_data KV reconciliation due to partial sync failures")
 	}
 
-	return nil
+	return Completeness{Total: len(bucketNames), Failed: bucketsFailed}, nil
 }
 
-func fetchBucketInfo(co *rgwadmin.API, bucketName string) (rgwadmin.Bucket, error) {
-	const maxRetries = 3
-	var bucketInfo rgwadmin.Bucket
-	var err error
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		bucketInfo, err = co.GetBucketInfo(context.Background(), rgwadmin.Bucket{Bucket: bucketName})
-		if err == nil {
-			return bucketInfo, nil // Success!
-		}
-
-		log.Warn().
+// fetchBucketInfo fetches the info of a bucket, the collector retries
+// temporary failures
+func fetchBucketInfo(ctx context.Context, co Collector, bucketName string) (rgwadmin.Bucket, error) {
+	bucketInfo, err := co.GetBucketInfo(ctx, rgwadmin.Bucket{Bucket: bucketName})
+	if err != nil {
+		log.Error().
 			Str("bucket", bucketName).
-			Int("attempt", attempt).
 			Err(err).
-			Msg("Error fetching bucket info, retrying...")
-
-		// Exponential backoff: wait longer on each retry
-		time.Sleep(time.Duration(attempt*2) * time.Second)
+			Msg("Failed to fetch bucket info")
+		return rgwadmin.Bucket{}, fmt.Errorf("failed to fetch bucket %s: %w", bucketName, err)
 	}
-
-	log.Error().
-		Str("bucket", bucketName).
-		Err(err).
-		Msg("Failed to fetch bucket info after retries")
-	return rgwadmin.Bucket{}, fmt.Errorf("failed to fetch bucket %s after %d retries: %w", bucketName, maxRetries, err)
+	return bucketInfo, nil
 }
 
-func storeBucketInKV(bucket rgwadmin.Bucket, bucketData nats.KeyValue) error {
-	bucketDataJSON, err := json.Marshal(bucket)
+func storeBucketInKV(record bucketRecord, bucketData nats.KeyValue) error {
+	bucket := record.Bucket
+	bucketDataJSON, err := json.Marshal(record)
 	if err != nil {
 		log.Error().
 			Str("bucket", bucket.Bucket).
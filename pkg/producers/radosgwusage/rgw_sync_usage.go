@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
@@ -26,7 +27,7 @@ const (
 // 	Usage       UserUsageSpec `json:"usage"`
 // }
 
-func syncUsage(userUsageData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
+func syncUsage(ctx context.Context, userUsageData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
 	log.Info().Msg("Starting usage sync process")
 
 	// Create a new RadosGW admin client.
@@ -37,7 +38,7 @@ func syncUsage(userUsageData nats.KeyValue, cfg RadosGWUsageConfig, status *Prys
 	}
 
 	// Fetch and store global usage (for all users).
-	err = fetchUserUsageGlobal(co, userUsageData)
+	err = fetchUserUsageGlobal(ctx, co, userUsageData, cfg.KVWriteWorkers)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch global user usage")
 		return err
@@ -47,7 +48,7 @@ func syncUsage(userUsageData nats.KeyValue, cfg RadosGWUsageConfig, status *Prys
 	return nil
 }
 
-func fetchUserUsageGlobal(co *rgwadmin.API, userUsageData nats.KeyValue) error {
+func fetchUserUsageGlobal(ctx context.Context, co Collector, userUsageData nats.KeyValue, writeWorkers int) error {
 	// Fetch the initial global usage data.
 	// globalUsage, err := co.GetUsage(context.Background(), rgwadmin.Usage{
 	// 	ShowEntries: ptr(true),
@@ -60,7 +61,7 @@ func fetchUserUsageGlobal(co *rgwadmin.API, userUsageData nats.KeyValue) error {
 	// if len(globalUsage.Entries) == 0 {
 	// 	return nil
 	// }
-	userIDs, err := co.GetUsers(context.Background())
+	userIDs, err := co.GetUsers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get user list: %v", err)
 	}
@@ -81,7 +82,8 @@ func fetchUserUsageGlobal(co *rgwadmin.API, userUsageData nats.KeyValue) error {
 		go func(userID string) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release token when done
-			fetchUsageDetails(co, userID, usageDataCh, errCh)
+			defer telemetry.RecoverPanic("radosgw-usage.sync-usage")
+			fetchUsageDetails(ctx, co, userID, usageDataCh, errCh)
 
 		}(entry)
 		// }(entry.User)
@@ -94,14 +96,24 @@ func fetchUserUsageGlobal(co *rgwadmin.API, userUsageData nats.KeyValue) error {
 	// var userData []rgwadmin.KVUser
 	var usageProcessed, usageFailed int
 	var usageBucketWriteFailed int
+	var writes []kvWrite
 	seenUsageKeys := make(map[string]struct{})
 
 	for data := range usageDataCh {
 		// userData = append(userData, data)
-		usageBucketWriteFailed += storeUserUsageInKV(data, userUsageData, seenUsageKeys)
+		userWrites, failed := usageWrites(data, seenUsageKeys)
+		writes = append(writes, userWrites...)
+		usageBucketWriteFailed += failed
 		usageProcessed++
 	}
 
+	// One batch for all users, written in parallel
+	failed, err := putKVBatch(userUsageData, writes, writeWorkers)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to update KV for bucket usage")
+	}
+	usageBucketWriteFailed += failed
+
 	for range errCh {
 		usageFailed++
 	}
@@ -123,11 +135,11 @@ func fetchUserUsageGlobal(co *rgwadmin.API, userUsageData nats.KeyValue) error {
 	return nil
 }
 
-func fetchUsageDetails(co *rgwadmin.API, userID string, usageDataCh chan rgwadmin.Usage, errCh chan string) {
+func fetchUsageDetails(ctx context.Context, co Collector, userID string, usageDataCh chan rgwadmin.Usage, errCh chan string) {
 	maxRetries := 3
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		usageData, err := co.GetUsage(context.Background(), rgwadmin.Usage{
+		usageData, err := co.GetUsage(ctx, rgwadmin.Usage{
 			UserID:      userID,
 			ShowEntries: ptr(true),
 		})
@@ -152,7 +164,10 @@ func fetchUsageDetails(co *rgwadmin.API, userID string, usageDataCh chan rgwadmi
 	}
 }
 
-func storeUserUsageInKV(userUsage rgwadmin.Usage, userUsageData nats.KeyValue, seenUsageKeys map[string]struct{}) int {
+// usageWrites returns the KV writes of the buckets of the usage of a user and
+// how many buckets failed to serialize
+func usageWrites(userUsage rgwadmin.Usage, seenUsageKeys map[string]struct{}) ([]kvWrite, int) {
+	var writes []kvWrite
 	bucketsFailed := 0
 	skippedBuckets := 0
 
@@ -189,22 +204,13 @@ func storeUserUsageInKV(userUsage rgwadmin.Usage, userUsageData nats.KeyValue, s
 			bucketKey := BuildUserTenantBucketKey(user, tenant, bucketName)
 			seenUsageKeys[bucketKey] = struct{}{}
 
-			// Write the serialized data to the KV store.
-			if _, err := userUsageData.Put(bucketKey, bucketDataJSON); err != nil {
-				log.Warn().
-					Str("user", entry.User).
-					Str("bucket", bucket.Bucket).
-					Err(err).
-					Msg("Failed to update KV for bucket usage")
-				bucketsFailed++
-				continue
-			}
+			writes = append(writes, kvWrite{key: bucketKey, value: bucketDataJSON})
 		}
 	}
 
 	log.Debug().
 		Int("bucketsFailed", bucketsFailed).
 		Int("skippedBuckets", skippedBuckets).
-		Msg("Completed storing bucket usage in KV")
-	return bucketsFailed
+		Msg("Prepared bucket usage for KV")
+	return writes, bucketsFailed
 }
@@ -11,11 +11,12 @@ import (
 	"time"
 
 	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
 
-func syncUsers(userData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
+func syncUsers(ctx context.Context, userData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStatus) error {
 	log.Info().Msg("Starting user synchronization")
 
 	// Create RadosGW admin client
@@ -25,21 +26,33 @@ func syncUsers(userData nats.KeyValue, cfg RadosGWUsageConfig, status *PrysmStat
 		return err
 	}
 
+	// The users before the sync, to tell the created and removed ones
+	before, err := userKeySet(userData)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list users before sync, lifecycle not recorded")
+	}
+
 	// Fetch and store all users with concurrency control
-	err = fetchAllUsers(co, userData)
+	completeness, err := fetchAllUsers(ctx, co, userData)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch users")
 		return err
 	}
+	status.UpdateUserCompleteness(completeness)
+	if before != nil {
+		recordUserLifecycle(userData, before, status)
+	}
 
 	log.Info().Msg("User synchronization completed")
 	return nil
 }
 
-func fetchAllUsers(co *rgwadmin.API, userData nats.KeyValue) error {
-	userIDs, err := co.GetUsers(context.Background())
+// fetchAllUsers stores the users in the KV and returns how many of them
+// were collected
+func fetchAllUsers(ctx context.Context, co Collector, userData nats.KeyValue) (Completeness, error) {
+	userIDs, err := co.GetUsers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get user list: %v", err)
+		return Completeness{}, fmt.Errorf("failed to get user list: %v", err)
 	}
 
 	userDataCh := make(chan rgwadmin.KVUser, len(userIDs))
@@ -55,7 +68,8 @@ func fetchAllUsers(co *rgwadmin.API, userData nats.KeyValue) error {
 		go func(userName string) {
 			defer wg.Done()
 			defer func() { <-sem }()
-			fetchUserInfo(co, userName, userDataCh, errCh)
+			defer telemetry.RecoverPanic("radosgw-usage.sync-users")
+			fetchUserInfo(ctx, co, userName, userDataCh, errCh)
 		}(userName)
 	}
 
@@ -95,14 +109,14 @@ func fetchAllUsers(co *rgwadmin.API, userData nats.KeyValue) error {
 			Msg("Skipping user_data KV reconciliation due to partial sync failures")
 	}
 
-	return nil
+	return Completeness{Total: len(userIDs), Failed: usersFailed}, nil
 }
 
-func fetchUserInfo(co *rgwadmin.API, userID string, userDataCh chan rgwadmin.KVUser, errCh chan string) {
+func fetchUserInfo(ctx context.Context, co Collector, userID string, userDataCh chan rgwadmin.KVUser, errCh chan string) {
 	maxRetries := 3
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		userInfo, err := co.GetKVUser(context.Background(), rgwadmin.User{ID: userID, GenerateStat: ptr(true)})
+		userInfo, err := co.GetKVUser(ctx, rgwadmin.User{ID: userID, GenerateStat: ptr(true)})
 		if err != nil {
 			log.Error().
 				Str("user", userID).
@@ -125,7 +139,11 @@ func fetchUserInfo(co *rgwadmin.API, userID string, userDataCh chan rgwadmin.KVU
 }
 
 func storeUserInKV(user rgwadmin.KVUser, userData nats.KeyValue) error {
-	userDataJSON, err := json.Marshal(user)
+	normalizedUser, normalizedTenant := NormalizeUserTenant(user.ID, user.Tenant)
+	userKey := BuildUserTenantKey(normalizedUser, normalizedTenant)
+
+	record := userRecord{KVUser: user, FirstSeen: firstSeen(userData, userKey, time.Now().UTC())}
+	userDataJSON, err := json.Marshal(record)
 	if err != nil {
 		log.Error().
 			Str("user", user.ID).
@@ -134,8 +152,6 @@ func storeUserInKV(user rgwadmin.KVUser, userData nats.KeyValue) error {
 		return err
 	}
 
-	normalizedUser, normalizedTenant := NormalizeUserTenant(user.ID, user.Tenant)
-	userKey := BuildUserTenantKey(normalizedUser, normalizedTenant)
 	if _, err := userData.Put(userKey, userDataJSON); err != nil {
 		log.Warn().
 			Str("user", userKey).
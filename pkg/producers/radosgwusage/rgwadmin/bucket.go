@@ -37,9 +37,16 @@ type BucketUsageRgwMultimeta struct {
 	NumObjects     *uint64 `json:"num_objects"`
 }
 
+// BucketUsageRgwNone counts the index entries without data, e.g. the
+// delete markers and the olh entries of versioned objects
+type BucketUsageRgwNone struct {
+	NumObjects *uint64 `json:"num_objects"`
+}
+
 type BucketUsage struct {
 	RgwMain      BucketUsageRgwMain      `json:"rgw.main"`
 	RgwMultimeta BucketUsageRgwMultimeta `json:"rgw.multimeta"`
+	RgwNone      BucketUsageRgwNone      `json:"rgw.none"`
 }
 
 type Bucket struct {
@@ -60,6 +67,7 @@ type Bucket struct {
 	MaxMarker         string            `json:"max_marker"`
 	Usage             BucketUsage       `json:"usage"`
 	BucketQuota       QuotaSpec         `json:"bucket_quota"`
+	Tagset            map[string]string `json:"tagset,omitempty"`
 	Policy            *bool             `url:"policy"`
 	PurgeObject       *bool             `url:"purge-objects"`
 }
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+package rgwadmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+)
+
+// CLI reads the users, buckets and usage with the radosgw-admin CLI, for
+// clusters whose admin API is disabled. It returns the same data as API.
+type CLI struct {
+	Binary  string // radosgw-admin if empty
+	Conf    string // --conf, the CLI default if empty
+	Name    string // --name, e.g. client.admin
+	Keyring string // --keyring
+
+	Retry retry.Policy // Retries of the failed runs, none if zero
+
+	// Run runs the CLI with args and returns its output, the binary if nil
+	Run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewCLI creates a radosgw-admin client.
+func NewCLI(binary, conf, name, keyring string) *CLI {
+	if binary == "" {
+		binary = "radosgw-admin"
+	}
+	return &CLI{Binary: binary, Conf: conf, Name: name, Keyring: keyring, Retry: retry.DefaultPolicy}
+}
+
+// run runs a radosgw-admin command and decodes its JSON output into v
+func (c *CLI) run(ctx context.Context, v any, args ...string) error {
+	args = append(args, "--format", "json")
+	if c.Conf != "" {
+		args = append(args, "--conf", c.Conf)
+	}
+	if c.Name != "" {
+		args = append(args, "--name", c.Name)
+	}
+	if c.Keyring != "" {
+		args = append(args, "--keyring", c.Keyring)
+	}
+
+	run := c.Run
+	if run == nil {
+		run = c.exec
+	}
+	out, err := retry.DoValue(ctx, "radosgw-admin", c.Retry, func(ctx context.Context) ([]byte, error) {
+		out, err := run(ctx, args...)
+		if err != nil && !retryableExit(err) {
+			return nil, retry.Permanent(err)
+		}
+		return out, err
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("%s: %w. Output: %s", unmarshalError, err, string(out))
+	}
+	return nil
+}
+
+func (c *CLI) exec(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Binary, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w: %s", c.Binary, cliCommand(args), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// retryableExit tells if radosgw-admin failed in a way worth retrying: it
+// ran and failed with an error other than a missing user or bucket, denied
+// access or invalid arguments, e.g. timing out on the monitors
+func retryableExit(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	switch syscall.Errno(exitErr.ExitCode()) {
+	case syscall.ENOENT, syscall.EACCES, syscall.EINVAL:
+		return false
+	}
+	return true
+}
+
+// cliCommand returns the command of args without its options, e.g. user info
+func cliCommand(args []string) string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return strings.Join(args[:i], " ")
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// GetUsers retrieves a list of all user IDs with radosgw-admin user list.
+func (c *CLI) GetUsers(ctx context.Context) ([]string, error) {
+	var users []string
+	if err := c.run(ctx, &users, "user", "list"); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// cliUserStats is the output of radosgw-admin user stats
+type cliUserStats struct {
+	Stats struct {
+		Size       *uint64 `json:"size"`
+		SizeActual *uint64 `json:"size_actual"`
+		NumObjects *uint64 `json:"num_objects"`
+	} `json:"stats"`
+}
+
+// GetKVUser retrieves a user with radosgw-admin user info and, if stats
+// are requested, its storage with radosgw-admin user stats.
+func (c *CLI) GetKVUser(ctx context.Context, user User) (KVUser, error) {
+	if user.ID == "" {
+		return KVUser{}, errMissingUserID
+	}
+
+	var info kvUserInfo
+	if err := c.run(ctx, &info, "user", "info", "--uid", user.ID); err != nil {
+		return KVUser{}, err
+	}
+	userInfo := info.kvUser()
+	if user.GenerateStat == nil || !*user.GenerateStat {
+		return userInfo, nil
+	}
+
+	var stats cliUserStats
+	if err := c.run(ctx, &stats, "user", "stats", "--uid", user.ID); err != nil {
+		return KVUser{}, err
+	}
+	userInfo.Stats = UserStat{Size: stats.Stats.Size, SizeRounded: stats.Stats.SizeActual, NumObjects: stats.Stats.NumObjects}
+	return userInfo, nil
+}
+
+// ListBuckets retrieves a list of all buckets with radosgw-admin bucket list.
+func (c *CLI) ListBuckets(ctx context.Context) ([]string, error) {
+	var buckets []string
+	if err := c.run(ctx, &buckets, "bucket", "list"); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetBucketInfo retrieves a bucket with radosgw-admin bucket stats.
+func (c *CLI) GetBucketInfo(ctx context.Context, bucket Bucket) (Bucket, error) {
+	if bucket.Bucket == "" {
+		return Bucket{}, errMissingBucket
+	}
+
+	var bucketInfo Bucket
+	if err := c.run(ctx, &bucketInfo, "bucket", "stats", "--bucket", bucket.Bucket); err != nil {
+		return Bucket{}, err
+	}
+	return bucketInfo, nil
+}
+
+// GetUsage retrieves the usage of the object store with radosgw-admin usage
+// show.
+func (c *CLI) GetUsage(ctx context.Context, usage Usage) (Usage, error) {
+	args := []string{"usage", "show"}
+	if usage.UserID != "" {
+		args = append(args, "--uid", usage.UserID)
+	}
+	if usage.Start != "" {
+		args = append(args, "--start-date", usage.Start)
+	}
+	if usage.End != "" {
+		args = append(args, "--end-date", usage.End)
+	}
+	if usage.ShowEntries != nil {
+		args = append(args, fmt.Sprintf("--show-log-entries=%t", *usage.ShowEntries))
+	}
+	if usage.ShowSummary != nil {
+		args = append(args, fmt.Sprintf("--show-log-sum=%t", *usage.ShowSummary))
+	}
+
+	var usageResponse Usage
+	if err := c.run(ctx, &usageResponse, args...); err != nil {
+		return Usage{}, err
+	}
+	return usageResponse, nil
+}
+
+// GetBucketPolicy retrieves the ACL of a bucket with radosgw-admin policy.
+func (c *CLI) GetBucketPolicy(ctx context.Context, bucket string) (BucketPolicy, error) {
+	var policy BucketPolicy
+	if err := c.run(ctx, &policy, "policy", "--bucket", bucket); err != nil {
+		return BucketPolicy{}, err
+	}
+	return policy, nil
+}
+
+// GetBucketIAMPolicy retrieves the bucket policy document of a bucket from
+// the metadata of its instance, like API.GetBucketIAMPolicy.
+func (c *CLI) GetBucketIAMPolicy(ctx context.Context, bucket Bucket) ([]byte, error) {
+	var metadata bucketInstanceMetadata
+	if err := c.run(ctx, &metadata, "metadata", "get", "bucket.instance:"+bucketInstanceKey(bucket)); err != nil {
+		return nil, err
+	}
+	return metadata.iamPolicy(bucket)
+}
@@ -37,6 +37,7 @@ const (
 	ErrInvalidArgument       errorReason = "InvalidArgument"
 	ErrUnknown               errorReason = "Unknown"
 	ErrSignatureDoesNotMatch errorReason = "SignatureDoesNotMatch"
+	ErrInvalidAccessKeyID    errorReason = "InvalidAccessKeyId"
 
 	unmarshalError = "failed to unmarshal RGW response"
 )
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+package rgwadmin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ACL group types of RGW
+const (
+	ACLGroupNone              = 0
+	ACLGroupAllUsers          = 1
+	ACLGroupAuthenticatedUser = 2
+)
+
+// ACL permission flags of RGW
+const (
+	ACLPermRead        = 0x01
+	ACLPermWrite       = 0x02
+	ACLPermReadACP     = 0x04
+	ACLPermWriteACP    = 0x08
+	ACLPermFullControl = 0x0f
+)
+
+type ACLPermission struct {
+	Flags int `json:"flags"`
+}
+
+type ACLGrantType struct {
+	Type int `json:"type"`
+}
+
+type ACLGrant struct {
+	Type       ACLGrantType  `json:"type"`
+	ID         string        `json:"id"`
+	Email      string        `json:"email"`
+	Permission ACLPermission `json:"permission"`
+	Name       string        `json:"name"`
+	Group      int           `json:"group"`
+	URLSpec    string        `json:"url_spec"`
+}
+
+type ACLGrantEntry struct {
+	ID    string   `json:"id"`
+	Grant ACLGrant `json:"grant"`
+}
+
+type ACLGroupEntry struct {
+	Group int `json:"group"`
+	ACL   int `json:"acl"`
+}
+
+type BucketACL struct {
+	GroupMap []ACLGroupEntry `json:"acl_group_map"`
+	GrantMap []ACLGrantEntry `json:"grant_map"`
+}
+
+type PolicyOwner struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// BucketPolicy is the access control policy (ACL) of a bucket
+type BucketPolicy struct {
+	ACL   BucketACL   `json:"acl"`
+	Owner PolicyOwner `json:"owner"`
+}
+
+// GroupPermissions returns the permission flags granted to an ACL group
+func (p BucketPolicy) GroupPermissions(group int) int {
+	flags := 0
+	for _, entry := range p.ACL.GroupMap {
+		if entry.Group == group {
+			flags |= entry.ACL
+		}
+	}
+	for _, entry := range p.ACL.GrantMap {
+		if entry.Grant.Group == group {
+			flags |= entry.Grant.Permission.Flags
+		}
+	}
+	return flags
+}
+
+// GetBucketPolicy retrieves the ACL of a bucket.
+func (api *API) GetBucketPolicy(ctx context.Context, bucket string) (BucketPolicy, error) {
+	params := url.Values{}
+	params.Add("format", "json")
+	params.Add("policy", "")
+	params.Add("bucket", bucket)
+
+	body, err := api.call(ctx, http.MethodGet, "/bucket", params, nil)
+	if err != nil {
+		return BucketPolicy{}, err
+	}
+
+	var policy BucketPolicy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return BucketPolicy{}, fmt.Errorf("%s: %w. Response: %s", unmarshalError, err, string(body))
+	}
+
+	return policy, nil
+}
+
+type metadataAttr struct {
+	Key string `json:"key"`
+	Val string `json:"val"` // base64 encoded
+}
+
+type bucketInstanceMetadata struct {
+	Data struct {
+		Attrs []metadataAttr `json:"attrs"`
+	} `json:"data"`
+}
+
+// iamPolicyAttr is the bucket instance attribute holding the bucket policy
+const iamPolicyAttr = "user.rgw.iam-policy"
+
+// GetBucketIAMPolicy retrieves the bucket policy document of a bucket, set
+// with S3 PutBucketPolicy, from the metadata of its instance. The bucket needs
+// its name, tenant and ID, as returned by GetBucketInfo. A bucket without a
+// policy returns nil.
+func (api *API) GetBucketIAMPolicy(ctx context.Context, bucket Bucket) ([]byte, error) {
+	params := url.Values{}
+	params.Add("format", "json")
+	params.Add("key", bucketInstanceKey(bucket))
+
+	body, err := api.call(ctx, http.MethodGet, "/metadata/bucket.instance", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata bucketInstanceMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("%s: %w. Response: %s", unmarshalError, err, string(body))
+	}
+	return metadata.iamPolicy(bucket)
+}
+
+// bucketInstanceKey returns the metadata key of the instance of a bucket,
+// "tenant/bucket:id"
+func bucketInstanceKey(bucket Bucket) string {
+	key := bucket.Bucket + ":" + bucket.ID
+	if bucket.Tenant != "" {
+		key = bucket.Tenant + "/" + key
+	}
+	return key
+}
+
+// iamPolicy returns the bucket policy document of the instance, nil if the
+// bucket has none
+func (metadata bucketInstanceMetadata) iamPolicy(bucket Bucket) ([]byte, error) {
+	for _, attr := range metadata.Data.Attrs {
+		if attr.Key != iamPolicyAttr {
+			continue
+		}
+		document, err := base64.StdEncoding.DecodeString(attr.Val)
+		if err != nil {
+			return nil, fmt.Errorf("decoding the bucket policy of %s: %w", bucket.Bucket, err)
+		}
+		return document, nil
+	}
+	return nil, nil
+}
@@ -14,12 +14,14 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
 )
 
 const (
 	authRegion        = "default"
 	service           = "s3"
 	connectionTimeout = 3 * time.Second
+	retryTarget       = "rgw-admin-api"
 )
 
 var (
@@ -45,6 +47,7 @@ type API struct {
 	Auth       AuthConfig
 	Endpoint   string
 	HTTPClient HTTPClient
+	Retry      retry.Policy // Retries of failed requests and server errors, none if zero
 }
 
 // New creates a new Ceph RGW client with basic validation.
@@ -64,6 +67,7 @@ func New(endpoint, accessKey, secretKey string, httpClient HTTPClient) (*API, er
 			SecretKey: secretKey,
 		},
 		HTTPClient: httpClient,
+		Retry:      retry.DefaultPolicy,
 	}, nil
 }
 
@@ -81,19 +85,33 @@ func validateConfig(endpoint, accessKey, secretKey string) error {
 	}
 }
 
-// call performs a signed request to the RGW Admin Ops API.
+// call performs a signed request to the RGW Admin Ops API, retrying it by
+// the Retry policy. Requests with a body are sent once, it can't be read
+// again.
 func (api *API) call(ctx context.Context, method, path string, args url.Values, body io.Reader) ([]byte, error) {
+	policy := api.Retry
+	if body != nil {
+		policy.MaxAttempts = 1
+	}
+	return retry.DoValue(ctx, retryTarget, policy, func(ctx context.Context) ([]byte, error) {
+		return api.do(ctx, method, path, args, body)
+	})
+}
+
+// do performs a signed request once. Only the failures of the request, rate
+// limits and server errors are worth retrying.
+func (api *API) do(ctx context.Context, method, path string, args url.Values, body io.Reader) ([]byte, error) {
 	reqURL := buildQueryPath(api.Endpoint, path, args.Encode())
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
-		return nil, err
+		return nil, retry.Permanent(err)
 	}
 
 	// Sign request using AWS v4 signing
 	if err := api.signRequest(req); err != nil {
-		return nil, err
+		return nil, retry.Permanent(err)
 	}
 
 	// Perform request
@@ -103,7 +121,11 @@ func (api *API) call(ctx context.Context, method, path string, args url.Values,
 	}
 	defer resp.Body.Close()
 
-	return parseResponse(resp)
+	data, err := parseResponse(resp)
+	if err != nil && resp.StatusCode >= 300 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, retry.Permanent(err)
+	}
+	return data, err
 }
 
 // signRequest signs an HTTP request using AWS v4 signing.
@@ -55,6 +55,7 @@ func (u *User) GetKVUser() *KVUser {
 		Type:                u.Type,
 		Tenant:              u.Tenant,
 		Stats:               u.Stat,
+		KeyCount:            len(u.Keys) + len(u.SwiftKeys),
 	}
 }
 
@@ -75,6 +76,21 @@ type KVUser struct {
 	Type                string        `json:"type"`
 	Tenant              string        `json:"tenant"`
 	Stats               UserStat      `json:"stats"`
+	KeyCount            int           `json:"key_count"` // S3 and Swift keys of the user, the keys themselves are not kept
+}
+
+// kvUserInfo is the user info of RGW decoded into a KVUser, with the keys
+// only decoded far enough to count them
+type kvUserInfo struct {
+	KVUser
+	Keys      []json.RawMessage `json:"keys"`
+	SwiftKeys []json.RawMessage `json:"swift_keys"`
+}
+
+func (u kvUserInfo) kvUser() KVUser {
+	user := u.KVUser
+	user.KeyCount = len(u.Keys) + len(u.SwiftKeys)
+	return user
 }
 
 func (user *KVUser) GetUserIdentification() string {
@@ -216,12 +232,12 @@ func (api *API) GetKVUser(ctx context.Context, user User) (KVUser, error) {
 	}
 
 	// Decode response
-	var userInfo KVUser
+	var userInfo kvUserInfo
 	if err := json.Unmarshal(body, &userInfo); err != nil {
 		return KVUser{}, fmt.Errorf("%s: %w. Response: %s", unmarshalError, err, string(body))
 	}
 
-	return userInfo, nil
+	return userInfo.kvUser(), nil
 }
 
 // validateUserRequest ensures that the User struct has the required fields.
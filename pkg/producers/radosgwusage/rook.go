@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/kubeclient"
+	"github.com/rs/zerolog/log"
+)
+
+// rookAdminOpsSecret is the secret Rook keeps the admin ops user of the
+// object stores of a namespace in
+const rookAdminOpsSecret = "rgw-admin-ops-user"
+
+// DefaultRookDiscoveryInterval is the interval the CephObjectStores are
+// listed again
+const DefaultRookDiscoveryInterval = time.Minute
+
+// cephObjectStore is the part of a Rook CephObjectStore the targets are
+// generated from
+type cephObjectStore struct {
+	Metadata struct {
+		Name              string     `json:"name"`
+		Namespace         string     `json:"namespace"`
+		DeletionTimestamp *time.Time `json:"deletionTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		Gateway struct {
+			Port       int `json:"port"`
+			SecurePort int `json:"securePort"`
+		} `json:"gateway"`
+	} `json:"spec"`
+	Status struct {
+		Phase string            `json:"phase"`
+		Info  map[string]string `json:"info"`
+	} `json:"status"`
+}
+
+// adminURL is the endpoint of the store, the one Rook reports in its status
+// or else the one of the service Rook creates for the gateway, TLS first
+func (s cephObjectStore) adminURL() string {
+	if endpoint := s.Status.Info["secureEndpoint"]; endpoint != "" {
+		return endpoint
+	}
+	if endpoint := s.Status.Info["endpoint"]; endpoint != "" {
+		return endpoint
+	}
+	host := fmt.Sprintf("rook-ceph-rgw-%s.%s.svc", s.Metadata.Name, s.Metadata.Namespace)
+	if s.Spec.Gateway.SecurePort > 0 {
+		return "https://" + net.JoinHostPort(host, strconv.Itoa(s.Spec.Gateway.SecurePort))
+	}
+	if s.Spec.Gateway.Port > 0 {
+		return "http://" + net.JoinHostPort(host, strconv.Itoa(s.Spec.Gateway.Port))
+	}
+	return ""
+}
+
+// listObjectStores lists the CephObjectStores of the namespace, of all
+// namespaces if empty
+func listObjectStores(ctx context.Context, kube *kubeclient.Client, namespace string) ([]cephObjectStore, error) {
+	path := "/apis/ceph.rook.io/v1/cephobjectstores"
+	if namespace != "" {
+		path = "/apis/ceph.rook.io/v1/namespaces/" + url.PathEscape(namespace) + "/cephobjectstores"
+	}
+	var list struct {
+		Items []cephObjectStore `json:"items"`
+	}
+	if err := kube.Get(ctx, path, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// adminOpsCredentials reads the keys of the admin ops user Rook created in
+// the namespace
+func adminOpsCredentials(ctx context.Context, kube *kubeclient.Client, namespace string) (accessKey, secretKey string, err error) {
+	var secret struct {
+		Data map[string][]byte `json:"data"` // Decoded from base64
+	}
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets/" + rookAdminOpsSecret
+	if err := kube.Get(ctx, path, &secret); err != nil {
+		return "", "", err
+	}
+	accessKey, secretKey = string(secret.Data["accessKey"]), string(secret.Data["secretKey"])
+	if accessKey == "" || secretKey == "" {
+		return "", "", fmt.Errorf("secret %s/%s has no accessKey or secretKey", namespace, rookAdminOpsSecret)
+	}
+	return accessKey, secretKey, nil
+}
+
+// discoverRookTargets returns a target of the admin API per CephObjectStore
+// that is ready, named after the store. Stores whose admin ops user cannot
+// be read use the credentials of the exporter.
+func discoverRookTargets(ctx context.Context, kube *kubeclient.Client, namespace string) (map[string]ProbeTarget, error) {
+	stores, err := listObjectStores(ctx, kube, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	type credentials struct{ accessKey, secretKey string }
+	namespaceCredentials := make(map[string]credentials)
+	targets := make(map[string]ProbeTarget, len(stores))
+	for _, store := range stores {
+		name := store.Metadata.Name
+		logger := log.With().Str("namespace", store.Metadata.Namespace).Str("object_store", name).Logger()
+		switch {
+		case store.Metadata.DeletionTimestamp != nil:
+			continue
+		case store.Status.Phase != "Ready" && store.Status.Phase != "Connected":
+			logger.Debug().Str("phase", store.Status.Phase).Msg("Object store is not ready, not probed yet")
+			continue
+		}
+		if _, ok := targets[name]; ok {
+			logger.Warn().Msg("Object store of the same name in another namespace, not probed")
+			continue
+		}
+		adminURL := store.adminURL()
+		if adminURL == "" {
+			logger.Warn().Msg("Object store without endpoint, not probed")
+			continue
+		}
+
+		creds, ok := namespaceCredentials[store.Metadata.Namespace]
+		if !ok {
+			creds.accessKey, creds.secretKey, err = adminOpsCredentials(ctx, kube, store.Metadata.Namespace)
+			if err != nil {
+				logger.Warn().Err(err).Msg("Failed to read the admin ops user of Rook, using the credentials of the exporter")
+			}
+			namespaceCredentials[store.Metadata.Namespace] = creds
+		}
+		targets[name] = ProbeTarget{
+			Cluster:   name,
+			Source:    SourceAdminAPI,
+			AdminURL:  adminURL,
+			AccessKey: creds.accessKey,
+			SecretKey: creds.secretKey,
+			timeout:   defaultProbeTimeout,
+		}
+	}
+	return targets, nil
+}
+
+// runRookDiscovery replaces the targets of the handler with the static ones
+// and those of the CephObjectStores every RookDiscoveryInterval, until ctx
+// is done. A static target wins over a store of the same name. If the
+// stores cannot be listed, the targets are kept.
+func runRookDiscovery(ctx context.Context, cfg RadosGWUsageConfig, kube *kubeclient.Client, handler *probeHandler, static map[string]ProbeTarget) {
+	interval := cfg.RookDiscoveryInterval
+	if interval <= 0 {
+		interval = DefaultRookDiscoveryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		discovered, err := discoverRookTargets(ctx, kube, cfg.RookNamespace)
+		if err != nil {
+			log.Error().Err(err).Str("namespace", cfg.RookNamespace).Msg("Failed to list the CephObjectStores, keeping the probe targets")
+		} else {
+			for cluster, target := range static {
+				discovered[cluster] = target
+			}
+			added, removed := handler.setTargets(discovered)
+			if len(added) > 0 || len(removed) > 0 {
+				log.Info().Strs("added", added).Strs("removed", removed).Int("targets", len(discovered)).Msg("Probe targets reconciled with the CephObjectStores")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/kubeclient"
+)
+
+// fakeKubeAPI serves the CephObjectStores of two namespaces and the admin
+// ops user of rook-ceph
+func fakeKubeAPI(t *testing.T) *kubeclient.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, `{"reason":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/apis/ceph.rook.io/v1/cephobjectstores":
+			io.WriteString(w, `{"items":[
+				{"metadata":{"name":"s3","namespace":"rook-ceph"},
+				 "spec":{"gateway":{"port":80}},
+				 "status":{"phase":"Ready","info":{"endpoint":"http://rook-ceph-rgw-s3.rook-ceph.svc:80"}}},
+				{"metadata":{"name":"archive","namespace":"rook-ceph"},
+				 "spec":{"gateway":{"port":80,"securePort":443}},
+				 "status":{"phase":"Ready"}},
+				{"metadata":{"name":"new","namespace":"rook-ceph"},"status":{"phase":"Progressing"}},
+				{"metadata":{"name":"old","namespace":"rook-ceph","deletionTimestamp":"2026-10-01T12:00:00Z"},"status":{"phase":"Ready"}},
+				{"metadata":{"name":"external","namespace":"rook-external"},
+				 "status":{"phase":"Connected","info":{"endpoint":"http://10.0.0.5:8080"}}}
+			]}`)
+		case "/api/v1/namespaces/rook-ceph/secrets/rgw-admin-ops-user":
+			io.WriteString(w, `{"data":{"accessKey":"QUs=","secretKey":"U0s="}}`)
+		default:
+			http.Error(w, `{"reason":"NotFound"}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("test-token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	return kubeclient.New(server.Client(), server.URL, tokenFile)
+}
+
+func TestDiscoverRookTargets(t *testing.T) {
+	kube := fakeKubeAPI(t)
+	targets, err := discoverRookTargets(context.Background(), kube, "")
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	want := map[string]ProbeTarget{
+		"s3": {Cluster: "s3", Source: SourceAdminAPI, AdminURL: "http://rook-ceph-rgw-s3.rook-ceph.svc:80",
+			AccessKey: "AK", SecretKey: "SK", timeout: defaultProbeTimeout},
+		"archive": {Cluster: "archive", Source: SourceAdminAPI, AdminURL: "https://rook-ceph-rgw-archive.rook-ceph.svc:443",
+			AccessKey: "AK", SecretKey: "SK", timeout: defaultProbeTimeout},
+		// Without the secret of Rook, the credentials of the exporter are used
+		"external": {Cluster: "external", Source: SourceAdminAPI, AdminURL: "http://10.0.0.5:8080", timeout: defaultProbeTimeout},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("unexpected targets\n got: %+v\nwant: %+v", targets, want)
+	}
+
+	if _, err := discoverRookTargets(context.Background(), kube, "missing"); err == nil {
+		t.Fatal("expected the stores of an unknown namespace to fail")
+	}
+	kube = kubeclient.New(http.DefaultClient, "http://127.0.0.1:0", filepath.Join(t.TempDir(), "missing"))
+	if _, err := discoverRookTargets(context.Background(), kube, ""); err == nil {
+		t.Fatal("expected the discovery without token to fail")
+	}
+}
+
+func TestProbeHandler_SetTargets(t *testing.T) {
+	handler := &probeHandler{targets: map[string]ProbeTarget{"rgw-a": {Cluster: "rgw-a"}, "rgw-b": {Cluster: "rgw-b"}}}
+	added, removed := handler.setTargets(map[string]ProbeTarget{"rgw-b": {Cluster: "rgw-b"}, "rgw-c": {Cluster: "rgw-c"}})
+	if !reflect.DeepEqual(added, []string{"rgw-c"}) || !reflect.DeepEqual(removed, []string{"rgw-a"}) {
+		t.Fatalf("unexpected reconciliation, added %v, removed %v", added, removed)
+	}
+	if _, ok := handler.target("rgw-a"); ok {
+		t.Fatal("expected the removed target to be gone")
+	}
+
+	w := httptest.NewRecorder()
+	handler.serveTargets(w, httptest.NewRequest(http.MethodGet, "http://radosgw-usage:8080/targets", nil))
+	var groups []httpSDTargetGroup
+	if err := json.Unmarshal(w.Body.Bytes(), &groups); err != nil {
+		t.Fatalf("decode targets: %v", err)
+	}
+	want := []httpSDTargetGroup{
+		{Targets: []string{"radosgw-usage:8080"}, Labels: map[string]string{"__param_cluster": "rgw-b"}},
+		{Targets: []string{"radosgw-usage:8080"}, Labels: map[string]string{"__param_cluster": "rgw-c"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("unexpected target groups %+v", groups)
+	}
+}
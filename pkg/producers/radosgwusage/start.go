@@ -13,21 +13,34 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/remotewrite"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/cobaltcore-dev/prysm/pkg/tracing"
 	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage")
+
 // StartRadosGWUsageExporter starts the process of exporting RadosGW usage metrics.
 // It supports Prometheus output and sync control using NATS-KV.
 func StartRadosGWUsageExporter(cfg RadosGWUsageConfig) {
 	if !cfg.SyncControlNats {
 		log.Fatal().Msg("sync-control-nats=false is not supported by radosgw-usage yet")
 	}
+	if cfg.AdminAPIFaults != "" {
+		log.Warn().Str("admin_api_faults", cfg.AdminAPIFaults).Msg("Injecting faults into the admin API requests, do not use in production")
+	}
 
 	// Initialize Prometheus server if enabled
 	if cfg.Prometheus {
-		go startPrometheusMetricsServer(cfg.PrometheusPort)
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
+		if cfg.RemoteWrite.URL != "" {
+			remotewrite.Start(cfg.RemoteWrite)
+		}
 	}
 	var err error
 
@@ -36,7 +49,7 @@ func StartRadosGWUsageExporter(cfg RadosGWUsageConfig) {
 	var js nats.JetStreamContext
 	// Start NATS based on configuration
 	if cfg.SyncExternalNats {
-		nc, err = nats.Connect(cfg.SyncControlURL)
+		nc, err = natsutil.Connect(cfg.SyncControlURL)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to connect to external NATS")
 		}
@@ -99,32 +112,33 @@ func startEmbeddedNATS() (*server.Server, *nats.Conn, nats.JetStreamContext, err
 }
 
 func initializeKeyValueStores(cfg RadosGWUsageConfig, js nats.JetStreamContext) (map[string]nats.KeyValue, error) {
-	// Define the buckets we need
-	bucketNames := []string{
-		// fmt.Sprintf("%s_sync_control", cfg.SyncControlBucketPrefix),    // Sync control
-		fmt.Sprintf("%s_user_data", cfg.SyncControlBucketPrefix),       // User information
-		fmt.Sprintf("%s_user_usage_data", cfg.SyncControlBucketPrefix), // User Usage information
-		fmt.Sprintf("%s_bucket_data", cfg.SyncControlBucketPrefix),     // Bucket information
-		fmt.Sprintf("%s_user_metrics", cfg.SyncControlBucketPrefix),    // User metrics
-		fmt.Sprintf("%s_bucket_metrics", cfg.SyncControlBucketPrefix),  // Bucket metrics
-		fmt.Sprintf("%s_cluster_metrics", cfg.SyncControlBucketPrefix), // Cluster metrics
+	ttls, err := ParseKVTTLs(cfg.KVTTLs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Map to store Key-Value handles
 	kvStores := make(map[string]nats.KeyValue)
 
 	// Create or access each bucket
-	for _, bucketName := range bucketNames {
+	for _, name := range kvBuckets {
+		bucketName := fmt.Sprintf("%s_%s", cfg.SyncControlBucketPrefix, name)
 		kv, err := js.KeyValue(bucketName)
 		if err != nil {
 			kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
 				Bucket: bucketName,
+				TTL:    ttls[name],
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to create/access bucket %s: %w", bucketName, err)
 			}
+		} else if err := applyKVTTL(js, kv, ttls[name]); err != nil {
+			return nil, err
+		}
+		kvStores[bucketName] = natsutil.RetryKV(kv)
+		if cfg.SyncExternalNats {
+			kvStores[bucketName] = newMaintenanceKV(kvStores[bucketName])
 		}
-		kvStores[bucketName] = kv
 	}
 
 	return kvStores, nil
@@ -181,89 +195,82 @@ func startMetricCollectionLoop(cfg RadosGWUsageConfig, nc *nats.Conn, kvStores m
 
 	userData, userUsageData, bucketData, userMetrics, bucketMetrics, _ := ensureKeyValueStores(cfg, kvStores)
 
-	wg.Go(func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Info().Msg("Stopping metric collection loop")
-				return
-			default:
-			}
-
-			if err := syncUsers(userData, cfg, prysmStatus); err != nil {
-				prysmStatus.IncrementScrapeErrors()
-				log.Error().Err(err).Msg("syncUsers failed")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-				}
-				continue
-			}
-			if err := syncBuckets(bucketData, cfg, prysmStatus); err != nil {
-				prysmStatus.IncrementScrapeErrors()
-				log.Error().Err(err).Msg("syncBuckets failed")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-				}
-				continue
-			}
-			if err := syncUsage(userUsageData, cfg, prysmStatus); err != nil {
-				prysmStatus.IncrementScrapeErrors()
-				log.Error().Err(err).Msg("syncUsage failed")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-				}
-				continue
-			}
-			if err := updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics); err != nil {
-				prysmStatus.IncrementScrapeErrors()
-				log.Error().Err(err).Msg("updateUserMetricsInKV failed")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-				}
-				continue
-			}
-			if err := updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics); err != nil {
-				prysmStatus.IncrementScrapeErrors()
-				log.Error().Err(err).Msg("updateBucketMetricsInKV failed")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-				}
-				continue
-			}
-			if cfg.Prometheus {
-				populateMetricsFromKV(userMetrics, bucketMetrics, cfg)
-			}
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
-			}
+	// Sum the ops log metrics between the cycles
+	var joiner *opsMetricsJoiner
+	if cfg.OpsMetricsJoin {
+		joiner = newOpsMetricsJoiner()
+		sub, err := joiner.subscribe(nc, cfg.OpsMetricsSubject)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to join the ops log metrics")
 		}
+		defer sub.Unsubscribe()
+	}
+
+	stages := syncStages(cfg, prysmStatus, userData, userUsageData, bucketData, userMetrics, bucketMetrics)
+	if joiner != nil {
+		stages = append(stages, collectionStage{name: "joinOpsMetricsInKV", optional: true, run: func(context.Context) error {
+			return joinOpsMetricsInKV(joiner, userMetrics, bucketMetrics)
+		}})
+	}
+	if cfg.ReshardNotify {
+		stages = append(stages, collectionStage{name: "publishReshardRecommendations", optional: true, run: func(context.Context) error {
+			return publishReshardRecommendations(nc, bucketMetrics, cfg, prysmStatus.Partial(cfg.CompletenessPercent))
+		}})
+	}
+	if cfg.AuditBucketAccess && cfg.PublicBucketNotify {
+		tracker := &publicBucketTracker{}
+		stages = append(stages, collectionStage{name: "publishPublicBuckets", optional: true, run: func(context.Context) error {
+			return publishPublicBuckets(nc, bucketMetrics, tracker, cfg, prysmStatus.Partial(cfg.CompletenessPercent))
+		}})
+	}
+	if cfg.TenantAnomalyNotify {
+		detector := newTenantAnomalyDetector(cfg)
+		stages = append(stages, collectionStage{name: "publishTenantAnomalies", optional: true, run: func(context.Context) error {
+			return publishTenantAnomalies(nc, userMetrics, detector, cfg, prysmStatus.Partial(cfg.CompletenessPercent))
+		}})
+	}
+	if cfg.Prometheus {
+		stages = append(stages, collectionStage{name: "populateMetricsFromKV", run: func(context.Context) error {
+			populateMetricsFromKV(userMetrics, bucketMetrics, cfg)
+			populateUserLifecycle(prysmStatus, cfg)
+			populateCompleteness(prysmStatus, cfg)
+			return nil
+		}})
+		stages = append(stages, collectionStage{name: "populateKVStatus", optional: true, run: func(context.Context) error {
+			return populateKVStatus(kvStores)
+		}})
+	}
+
+	wg.Go(func() {
+		telemetry.RunWorker(ctx, "radosgw-usage.collection", func(ctx context.Context) {
+			collectionLoop(ctx, stages, cfg, prysmStatus)
+		})
 	})
 
+	// Purge the keys no cycle wrote for long
+	if cfg.KVCompactAge > 0 {
+		wg.Go(func() {
+			telemetry.RunWorker(ctx, "radosgw-usage.kv-compaction", func(ctx context.Context) {
+				compactionLoop(ctx, kvStores, cfg)
+			})
+		})
+	}
+
 	// Update prysm status
 	if cfg.Prometheus {
 		wg.Go(func() {
-			ticker := time.NewTicker(2 * time.Second)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-ticker.C:
-					populateStatus(prysmStatus)
-				case <-ctx.Done():
-					return
+			telemetry.RunWorker(ctx, "radosgw-usage.status", func(ctx context.Context) {
+				ticker := time.NewTicker(2 * time.Second)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						populateStatus(prysmStatus)
+					case <-ctx.Done():
+						return
+					}
 				}
-			}
+			})
 		})
 	}
 
@@ -282,7 +289,81 @@ func startMetricCollectionLoop(cfg RadosGWUsageConfig, nc *nats.Conn, kvStores m
 	log.Info().Msg("All tasks completed. Exiting.")
 }
 
+// syncStages are the stages of a cycle reading the users, buckets and usage
+// from RGW and computing the user and bucket metrics from them
+func syncStages(cfg RadosGWUsageConfig, prysmStatus *PrysmStatus, userData, userUsageData, bucketData, userMetrics, bucketMetrics nats.KeyValue) []collectionStage {
+	return []collectionStage{
+		{name: "syncUsers", run: func(ctx context.Context) error {
+			return syncUsers(ctx, userData, cfg, prysmStatus)
+		}},
+		{name: "syncBuckets", run: func(ctx context.Context) error {
+			return syncBuckets(ctx, bucketData, cfg, prysmStatus)
+		}},
+		{name: "syncUsage", run: func(ctx context.Context) error {
+			return syncUsage(ctx, userUsageData, cfg, prysmStatus)
+		}},
+		{name: "updateUserMetricsInKV", run: func(context.Context) error {
+			return updateUserMetricsInKV(userData, userUsageData, bucketData, userMetrics, prysmStatus.Partial(cfg.CompletenessPercent))
+		}},
+		{name: "updateBucketMetricsInKV", run: func(context.Context) error {
+			return updateBucketMetricsInKV(bucketData, userUsageData, bucketMetrics, cfg.ReshardObjectsPerShard, cfg.LargeOmapKeysPerShard, prysmStatus.Partial(cfg.CompletenessPercent))
+		}},
+	}
+}
+
+// collectionStage is a step of a collection cycle
+type collectionStage struct {
+	name     string
+	optional bool // A failure is logged, the cycle goes on
+	run      func(ctx context.Context) error
+}
+
+// runCollectionCycle runs the stages in order in a span of the cycle, each
+// stage in a child span. It stops at the first stage that fails and is not
+// optional.
+func runCollectionCycle(ctx context.Context, stages []collectionStage) (err error) {
+	ctx, cycle := tracer.Start(ctx, "radosgw-usage.cycle")
+	defer func() { tracing.End(cycle, err) }()
+
+	for _, stage := range stages {
+		stageCtx, span := tracer.Start(ctx, stage.name)
+		stageErr := stage.run(stageCtx)
+		tracing.End(span, stageErr)
+		if stageErr == nil {
+			continue
+		}
+		log.Error().Err(stageErr).Msgf("%s failed", stage.name)
+		if !stage.optional {
+			return fmt.Errorf("%s: %w", stage.name, stageErr)
+		}
+	}
+	return nil
+}
+
 // Ptr returns a pointer to the given value (generic version for any type)
 func ptr[T any](v T) *T {
 	return &v
 }
+
+// collectionLoop runs the collection cycles until ctx is canceled
+func collectionLoop(ctx context.Context, stages []collectionStage, cfg RadosGWUsageConfig, prysmStatus *PrysmStatus) {
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Stopping metric collection loop")
+			return
+		default:
+		}
+
+		if err := runCollectionCycle(ctx, stages); err != nil {
+			prysmStatus.IncrementScrapeErrors()
+		} else {
+			populateLastSync(cfg)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(cfg.CooldownInterval) * time.Second):
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRunCollectionCycle_StopsAtFailedStage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var ran []string
+	stage := func(name string, optional bool, err error) collectionStage {
+		return collectionStage{name: name, optional: optional, run: func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	err := runCollectionCycle(context.Background(), []collectionStage{
+		stage("syncUsers", false, nil),
+		stage("publishReshardRecommendations", true, errors.New("no stream")),
+		stage("syncBuckets", false, errors.New("admin API down")),
+		stage("syncUsage", false, nil),
+	})
+	if err == nil || !strings.Contains(err.Error(), "syncBuckets: admin API down") {
+		t.Fatalf("expected the syncBuckets error, got %v", err)
+	}
+	if want := []string{"syncUsers", "publishReshardRecommendations", "syncBuckets"}; !slices.Equal(ran, want) {
+		t.Fatalf("ran %v, want %v", ran, want)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected a span per stage run and one of the cycle, got %d", len(spans))
+	}
+	cycle := spans[3]
+	if cycle.Name() != "radosgw-usage.cycle" || cycle.Status().Code != codes.Error {
+		t.Errorf("unexpected cycle span %q with status %v", cycle.Name(), cycle.Status().Code)
+	}
+	for i, name := range ran {
+		if spans[i].Name() != name || spans[i].Parent().SpanID() != cycle.SpanContext().SpanID() {
+			t.Errorf("span %d: expected stage %s as child of the cycle, got %q", i, name, spans[i].Name())
+		}
+	}
+	if spans[0].Status().Code != codes.Unset || spans[1].Status().Code != codes.Error {
+		t.Errorf("unexpected stage statuses %v, %v", spans[0].Status().Code, spans[1].Status().Code)
+	}
+}
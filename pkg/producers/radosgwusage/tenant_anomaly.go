@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// Anomalies of the storage of a tenant between two cycles
+const (
+	AnomalyCapacityGrowth = "capacity_growth" // grew many times faster than in the recent cycles
+	AnomalyMassDeletion   = "mass_deletion"   // lost a large share of its bytes or objects
+)
+
+// noTenant identifies the users without a tenant, as in the ops log
+const noTenant = "none"
+
+// tenantUsage is the storage of a tenant in one cycle
+type tenantUsage struct {
+	bytes   uint64
+	objects uint64
+}
+
+// tenantAnomaly is an anomaly of a tenant detected in a cycle
+type tenantAnomaly struct {
+	tenant   string
+	anomaly  string
+	previous tenantUsage
+	current  tenantUsage
+	baseline float64 // average growth in bytes of the recent cycles
+}
+
+// tenantAnomalyDetector compares the growth of every tenant in a cycle with
+// its growth in the recent cycles, to spot abuse and ransomware-style mass
+// deletions
+type tenantAnomalyDetector struct {
+	history         int     // cycles of growth the baseline is averaged over
+	growthFactor    float64 // growth above this many times the baseline is anomalous
+	deletionPercent float64 // loss of this percentage of bytes or objects is anomalous
+	minBytes        uint64  // growth or loss below is never anomalous
+
+	previous map[string]tenantUsage // nil until the first cycle
+	growth   map[string][]float64   // growth in bytes of the recent cycles
+}
+
+func newTenantAnomalyDetector(cfg RadosGWUsageConfig) *tenantAnomalyDetector {
+	return &tenantAnomalyDetector{
+		history:         cfg.TenantAnomalyHistory,
+		growthFactor:    float64(cfg.TenantAnomalyGrowthFactor),
+		deletionPercent: float64(cfg.TenantAnomalyDeletionPercent),
+		minBytes:        uint64(cfg.TenantAnomalyMinGiB) << 30,
+		growth:          map[string][]float64{},
+	}
+}
+
+// collectTenantUsage sums the storage of the users in the stored metrics by
+// tenant
+func collectTenantUsage(userMetrics nats.KeyValue) (map[string]tenantUsage, error) {
+	keys, err := userMetrics.Keys()
+	if err != nil {
+		if errors.Is(err, nats.ErrNoKeysFound) {
+			return map[string]tenantUsage{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch keys from user metrics: %w", err)
+	}
+
+	usage := map[string]tenantUsage{}
+	for _, key := range keys {
+		entry, err := userMetrics.Get(key)
+		if err != nil {
+			if !errors.Is(err, nats.ErrKeyNotFound) {
+				log.Warn().Str("key", key).Err(err).Msg("Failed to fetch user metric")
+			}
+			continue
+		}
+
+		var metrics UserLevelMetrics
+		if err := json.Unmarshal(entry.Value(), &metrics); err != nil {
+			log.Warn().Str("key", key).Err(err).Msg("Failed to unmarshal user metric")
+			continue
+		}
+		tenant := metrics.Tenant
+		if tenant == "" {
+			tenant = noTenant
+		}
+		total := usage[tenant]
+		total.bytes += metrics.DataSizeTotal
+		total.objects += metrics.ObjectsTotal
+		usage[tenant] = total
+	}
+	return usage, nil
+}
+
+// detect returns the anomalies of the tenants since the previous cycle and
+// adds the growth of the cycle to their history. Tenants new or gone since
+// the previous cycle are not compared, so a partial user listing is not
+// mistaken for a mass deletion.
+func (d *tenantAnomalyDetector) detect(current map[string]tenantUsage) []tenantAnomaly {
+	previous := d.previous
+	d.previous = current
+	if previous == nil {
+		return nil
+	}
+
+	var anomalies []tenantAnomaly
+	for tenant, usage := range current {
+		before, ok := previous[tenant]
+		if !ok {
+			continue
+		}
+		growth := float64(usage.bytes) - float64(before.bytes)
+		recent := d.growth[tenant]
+		baseline := averageGrowth(recent)
+
+		switch {
+		case d.massDeletion(before, usage):
+			anomalies = append(anomalies, tenantAnomaly{tenant: tenant, anomaly: AnomalyMassDeletion, previous: before, current: usage, baseline: baseline})
+		case len(recent) > 0 && growth >= float64(d.minBytes) && growth > d.growthFactor*baseline:
+			anomalies = append(anomalies, tenantAnomaly{tenant: tenant, anomaly: AnomalyCapacityGrowth, previous: before, current: usage, baseline: baseline})
+		}
+
+		recent = append(recent, growth)
+		if len(recent) > d.history {
+			recent = recent[len(recent)-d.history:]
+		}
+		d.growth[tenant] = recent
+	}
+
+	// Forget the tenants that are gone
+	for tenant := range d.growth {
+		if _, ok := current[tenant]; !ok {
+			delete(d.growth, tenant)
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].tenant < anomalies[j].tenant })
+	return anomalies
+}
+
+// massDeletion reports whether a tenant lost at least deletionPercent of its
+// bytes or objects, and at least minBytes
+func (d *tenantAnomalyDetector) massDeletion(before, after tenantUsage) bool {
+	if before.bytes < after.bytes || before.bytes-after.bytes < d.minBytes {
+		return false
+	}
+	lost := func(before, after uint64) float64 {
+		if before == 0 || after >= before {
+			return 0
+		}
+		return float64(before-after) / float64(before) * 100
+	}
+	return lost(before.bytes, after.bytes) >= d.deletionPercent || lost(before.objects, after.objects) >= d.deletionPercent
+}
+
+// averageGrowth returns the average growth of the recent cycles, shrinking
+// cycles counted as no growth
+func averageGrowth(recent []float64) float64 {
+	if len(recent) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, growth := range recent {
+		if growth > 0 {
+			sum += growth
+		}
+	}
+	return sum / float64(len(recent))
+}
+
+// publishTenantAnomalies emits a "tenant_anomaly" event for every anomaly
+// of a tenant since the previous cycle.
+func publishTenantAnomalies(nc *nats.Conn, userMetrics nats.KeyValue, detector *tenantAnomalyDetector, cfg RadosGWUsageConfig, partial bool) error {
+	current, err := collectTenantUsage(userMetrics)
+	if err != nil {
+		return err
+	}
+
+	for _, anomaly := range detector.detect(current) {
+		log.Warn().Str("tenant", anomaly.tenant).Str("anomaly", anomaly.anomaly).
+			Uint64("previous_bytes", anomaly.previous.bytes).Uint64("current_bytes", anomaly.current.bytes).
+			Msg("Tenant usage anomaly detected")
+		err := publishEvent(nc, "tenant_anomaly", "detected", []string{anomaly.tenant}, markPartial(map[string]string{
+			"rgw_cluster_id":        cfg.ClusterID,
+			"anomaly":               anomaly.anomaly,
+			"previous_bytes":        strconv.FormatUint(anomaly.previous.bytes, 10),
+			"current_bytes":         strconv.FormatUint(anomaly.current.bytes, 10),
+			"previous_objects":      strconv.FormatUint(anomaly.previous.objects, 10),
+			"current_objects":       strconv.FormatUint(anomaly.current.objects, 10),
+			"baseline_growth_bytes": strconv.FormatFloat(anomaly.baseline, 'f', 0, 64),
+		}, partial))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
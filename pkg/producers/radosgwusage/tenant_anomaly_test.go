@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const gib = 1 << 30
+
+func newTestDetector() *tenantAnomalyDetector {
+	return newTenantAnomalyDetector(RadosGWUsageConfig{
+		TenantAnomalyHistory:         3,
+		TenantAnomalyGrowthFactor:    10,
+		TenantAnomalyDeletionPercent: 50,
+		TenantAnomalyMinGiB:          1,
+	})
+}
+
+func TestTenantAnomalyDetector_CapacityGrowth(t *testing.T) {
+	detector := newTestDetector()
+
+	// Steady growth of 1 GiB per cycle
+	for cycle := uint64(0); cycle < 4; cycle++ {
+		if anomalies := detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 100*gib + cycle*gib}}); len(anomalies) != 0 {
+			t.Fatalf("cycle %d: expected no anomalies, got %+v", cycle, anomalies)
+		}
+	}
+
+	anomalies := detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 103*gib + 20*gib}})
+	if len(anomalies) != 1 || anomalies[0].anomaly != AnomalyCapacityGrowth || anomalies[0].tenant != "tenant-a" {
+		t.Fatalf("expected capacity growth of tenant-a, got %+v", anomalies)
+	}
+	if anomalies[0].baseline != gib {
+		t.Fatalf("expected a baseline of 1 GiB, got %.0f", anomalies[0].baseline)
+	}
+}
+
+func TestTenantAnomalyDetector_GrowthNeedsHistory(t *testing.T) {
+	detector := newTestDetector()
+	detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 10 * gib}})
+
+	// The first growth has nothing to be compared with
+	if anomalies := detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 100 * gib}}); len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies without history, got %+v", anomalies)
+	}
+	// Growth below the minimum is never anomalous
+	if anomalies := detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 100*gib + gib/2}}); len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies below the minimum, got %+v", anomalies)
+	}
+}
+
+func TestTenantAnomalyDetector_MassDeletion(t *testing.T) {
+	detector := newTestDetector()
+	detector.detect(map[string]tenantUsage{
+		"tenant-a": {bytes: 500 * gib, objects: 120000},
+		"tenant-b": {bytes: 500 * gib, objects: 120000},
+		"tenant-c": {bytes: gib / 2, objects: 10},
+		"tenant-d": {bytes: 50 * gib, objects: 5000},
+	})
+
+	anomalies := detector.detect(map[string]tenantUsage{
+		"tenant-a": {bytes: 10 * gib, objects: 2400},       // lost 98% of its bytes
+		"tenant-b": {bytes: 400 * gib, objects: 120000},    // lost 20%
+		"tenant-c": {bytes: 0, objects: 0},                 // lost everything, but below the minimum
+		"tenant-d": {bytes: 50*gib - 2*gib, objects: 1000}, // lost 80% of its objects
+	})
+	if len(anomalies) != 2 || anomalies[0].tenant != "tenant-a" || anomalies[1].tenant != "tenant-d" {
+		t.Fatalf("expected mass deletions of tenant-a and tenant-d, got %+v", anomalies)
+	}
+	for _, anomaly := range anomalies {
+		if anomaly.anomaly != AnomalyMassDeletion {
+			t.Fatalf("expected a mass deletion, got %+v", anomaly)
+		}
+	}
+}
+
+func TestTenantAnomalyDetector_IgnoresNewAndGoneTenants(t *testing.T) {
+	detector := newTestDetector()
+	detector.detect(map[string]tenantUsage{"tenant-a": {bytes: 500 * gib}})
+
+	if anomalies := detector.detect(map[string]tenantUsage{"tenant-b": {bytes: 500 * gib}}); len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies for new and gone tenants, got %+v", anomalies)
+	}
+	if _, ok := detector.growth["tenant-a"]; ok {
+		t.Fatal("expected the history of the gone tenant to be forgotten")
+	}
+}
+
+func TestCollectTenantUsage(t *testing.T) {
+	seed := map[string][]byte{}
+	for key, metrics := range map[string]UserLevelMetrics{
+		"user-a": {User: "user-a", Tenant: "tenant-a", DataSizeTotal: 100, ObjectsTotal: 1},
+		"user-b": {User: "user-b", Tenant: "tenant-a", DataSizeTotal: 200, ObjectsTotal: 2},
+		"user-c": {User: "user-c", DataSizeTotal: 300, ObjectsTotal: 3},
+	} {
+		data, err := json.Marshal(metrics)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		seed[key] = data
+	}
+
+	usage, err := collectTenantUsage(newTestKV("user_metrics", seed))
+	if err != nil {
+		t.Fatalf("collect tenant usage: %v", err)
+	}
+	if usage["tenant-a"] != (tenantUsage{bytes: 300, objects: 3}) || usage[noTenant] != (tenantUsage{bytes: 300, objects: 3}) || len(usage) != 2 {
+		t.Fatalf("unexpected tenant usage %+v", usage)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// userRecord is the user data stored in KV: the user info of RGW and when
+// the exporter first saw the user
+type userRecord struct {
+	rgwadmin.KVUser
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// firstSeen is when the user stored under key was first seen, now if it is
+// not in KV yet or was stored without the time
+func firstSeen(userData nats.KeyValue, key string, now time.Time) time.Time {
+	entry, err := userData.Get(key)
+	if err != nil {
+		if !errors.Is(err, nats.ErrKeyNotFound) {
+			log.Warn().Str("user", key).Err(err).Msg("Failed to fetch user from KV, first seen reset")
+		}
+		return now
+	}
+
+	var record userRecord
+	if err := json.Unmarshal(entry.Value(), &record); err != nil || record.FirstSeen.IsZero() {
+		return now
+	}
+	return record.FirstSeen
+}
+
+// userKeySet lists the users in KV
+func userKeySet(userData nats.KeyValue) (map[string]struct{}, error) {
+	keys, err := userData.Keys()
+	if err != nil && !errors.Is(err, nats.ErrNoKeysFound) {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set, nil
+}
+
+// userLifecycleChanges counts the users created and removed between two
+// listings. Without users before, the listing is the baseline and no user
+// counts as created.
+func userLifecycleChanges(before, after map[string]struct{}) (created, removed int) {
+	for key := range after {
+		if _, ok := before[key]; !ok && len(before) > 0 {
+			created++
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed++
+		}
+	}
+	return created, removed
+}
+
+// recordUserLifecycle compares the users in KV after a sync with those
+// before it and records the users created and removed in status
+func recordUserLifecycle(userData nats.KeyValue, before map[string]struct{}, status *PrysmStatus) {
+	after, err := userKeySet(userData)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list users after sync, lifecycle not recorded")
+		return
+	}
+
+	created, removed := userLifecycleChanges(before, after)
+	status.UpdateUserLifecycle(created, removed)
+	log.Info().
+		Int("users_created", created).
+		Int("users_removed", removed).
+		Msg("Recorded user lifecycle")
+}
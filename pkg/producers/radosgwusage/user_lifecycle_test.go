@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radosgwusage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage/rgwadmin"
+)
+
+func TestStoreUserInKV_KeepsFirstSeen(t *testing.T) {
+	userData := newTestKV("user_data", nil)
+	key := BuildUserTenantKey("alice", "")
+
+	if err := storeUserInKV(rgwadmin.KVUser{ID: "alice"}, userData); err != nil {
+		t.Fatalf("store user: %v", err)
+	}
+	var first userRecord
+	if err := json.Unmarshal(userData.data[key], &first); err != nil {
+		t.Fatalf("unmarshal user: %v", err)
+	}
+	if first.FirstSeen.IsZero() {
+		t.Fatalf("expected first seen set, got %+v", first)
+	}
+
+	if err := storeUserInKV(rgwadmin.KVUser{ID: "alice", DisplayName: "Alice"}, userData); err != nil {
+		t.Fatalf("store user: %v", err)
+	}
+	var second userRecord
+	if err := json.Unmarshal(userData.data[key], &second); err != nil {
+		t.Fatalf("unmarshal user: %v", err)
+	}
+	if !second.FirstSeen.Equal(first.FirstSeen) || second.DisplayName != "Alice" {
+		t.Fatalf("expected first seen kept and user updated, got %+v", second)
+	}
+}
+
+func TestFirstSeen_UserStoredWithoutTime(t *testing.T) {
+	userJSON, err := json.Marshal(rgwadmin.KVUser{ID: "alice"})
+	if err != nil {
+		t.Fatalf("marshal user: %v", err)
+	}
+	userData := newTestKV("user_data", map[string][]byte{"alice": userJSON})
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := firstSeen(userData, "alice", now); !got.Equal(now) {
+		t.Fatalf("expected now for a user stored without first seen, got %v", got)
+	}
+}
+
+func TestUserLifecycleChanges(t *testing.T) {
+	set := func(keys ...string) map[string]struct{} {
+		s := make(map[string]struct{}, len(keys))
+		for _, k := range keys {
+			s[k] = struct{}{}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name             string
+		before, after    map[string]struct{}
+		created, removed int
+	}{
+		{name: "baseline", before: set(), after: set("alice", "bob"), created: 0, removed: 0},
+		{name: "unchanged", before: set("alice"), after: set("alice"), created: 0, removed: 0},
+		{name: "created and removed", before: set("alice", "bob"), after: set("alice", "carol", "dave"), created: 2, removed: 1},
+		{name: "all removed", before: set("alice", "bob"), after: set(), created: 0, removed: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, removed := userLifecycleChanges(tt.before, tt.after)
+			if created != tt.created || removed != tt.removed {
+				t.Fatalf("got created=%d removed=%d, want created=%d removed=%d", created, removed, tt.created, tt.removed)
+			}
+		})
+	}
+}
+
+func TestRecordUserLifecycle(t *testing.T) {
+	userData := newTestKV("user_data", map[string][]byte{"alice": []byte(`{}`), "carol": []byte(`{}`)})
+	status := &PrysmStatus{}
+
+	recordUserLifecycle(userData, map[string]struct{}{"alice": {}, "bob": {}}, status)
+
+	created, removed := status.GetUserLifecycle()
+	if created != 1 || removed != 1 {
+		t.Fatalf("expected one user created and one removed, got created=%d removed=%d", created, removed)
+	}
+}
+
+func TestProcessUserMetrics_Lifecycle(t *testing.T) {
+	suspended := 1
+	seen := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	userJSON, err := json.Marshal(userRecord{
+		KVUser:    rgwadmin.KVUser{ID: "alice", Suspended: &suspended, KeyCount: 2},
+		FirstSeen: seen,
+	})
+	if err != nil {
+		t.Fatalf("marshal user: %v", err)
+	}
+	key := BuildUserTenantKey("alice", "")
+	userData := newTestKV("user_data", map[string][]byte{key: userJSON})
+	userMetrics := newTestKV("user_metrics", nil)
+
+	processUserMetrics(key, userData, userMetrics, nil, false)
+
+	var got UserLevelMetrics
+	if err := json.Unmarshal(userMetrics.data[key], &got); err != nil {
+		t.Fatalf("unmarshal user metric: %v", err)
+	}
+	if !got.Suspended || got.KeyCount != 2 || !got.FirstSeen.Equal(seen) {
+		t.Fatalf("unexpected lifecycle %+v", got)
+	}
+}
@@ -5,16 +5,10 @@
 package resourceusage
 
 import (
-	"encoding/json"
-
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
 	"github.com/nats-io/nats.go"
 )
 
 func PublishToNATS(nc *nats.Conn, usage ResourceUsage, cfg ResourceUsageConfig) error {
-	data, err := json.Marshal(usage)
-	if err != nil {
-		return err
-	}
-
-	return nc.Publish(cfg.NatsSubject, data)
+	return schema.Publish(nc, cfg.NatsSubject, schema.ResourceUsage, usage)
 }
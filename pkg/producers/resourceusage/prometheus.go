@@ -4,15 +4,7 @@
 
 package resourceusage
 
-import (
-	"fmt"
-	"net/http"
-
-	"github.com/rs/zerolog/log"
-
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-)
+import "github.com/prometheus/client_golang/prometheus"
 
 var (
 	cpuUsageGauge = prometheus.NewGaugeVec(
@@ -73,14 +65,3 @@ func PublishToPrometheus(usage ResourceUsage, cfg ResourceUsageConfig) {
 		"instance": cfg.InstanceID,
 	}).Set(float64(usage.NetworkIO))
 }
-
-func StartPrometheusServer(port int) {
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Info().Msgf("starting prometheus metrics server on :%d", port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-		if err != nil {
-			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
-		}
-	}()
-}
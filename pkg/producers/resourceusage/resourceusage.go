@@ -7,6 +7,8 @@ package resourceusage
 import (
 	"time"
 
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 	"github.com/shirou/gopsutil/cpu"
@@ -68,7 +70,7 @@ func StartMonitoring(cfg ResourceUsageConfig) {
 	var nc *nats.Conn
 	var err error
 	if cfg.UseNats {
-		nc, err = nats.Connect(cfg.NatsURL)
+		nc, err = natsutil.Connect(cfg.NatsURL)
 		if err != nil {
 			log.Fatal().Err(err).Msg("error connecting to nats")
 		}
@@ -76,7 +78,7 @@ func StartMonitoring(cfg ResourceUsageConfig) {
 	}
 
 	if cfg.Prometheus {
-		StartPrometheusServer(cfg.PrometheusPort)
+		telemetry.StartMetricsServer(cfg.PrometheusPort)
 	}
 
 	ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Second)
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package remotewrite
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog/log"
+)
+
+// Start pushes the metrics of the default registry named with the metric
+// prefix and labeled with the exporter identity, what the Prometheus endpoint
+// of a producer serves, every Interval
+func Start(cfg Config) {
+	client := NewClient(cfg)
+	log.Info().Str("url", redactURL(cfg.URL)).Dur("interval", client.cfg.Interval).Msg("starting prometheus remote write")
+	go client.Run(context.Background(), identity.Gatherer(metricnames.Gatherer(prometheus.DefaultGatherer)))
+}
+
+// Run gathers and pushes the metrics every Interval until ctx is done
+func (c *Client) Run(ctx context.Context, gatherer prometheus.Gatherer) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		families, err := gatherer.Gather()
+		if err != nil {
+			// Gather returns what it could gather along with the error
+			log.Error().Err(err).Msg("error gathering metrics for remote write")
+		}
+		series := FromMetricFamilies(families, time.Now().UnixMilli())
+		if err := c.Write(ctx, series); err != nil {
+			log.Error().Err(err).Int("series", len(series)).Msg("error pushing metrics, dropping them")
+		}
+	}
+}
+
+// FromMetricFamilies converts gathered metrics to series, the way Prometheus
+// stores them when scraping: histograms and summaries become several series.
+// Samples without a timestamp get timestamp (milliseconds).
+func FromMetricFamilies(families []*dto.MetricFamily, timestamp int64) []Series {
+	var series []Series
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			add := func(suffix string, value float64, extra ...Label) {
+				labels := []Label{{Name: "__name__", Value: name + suffix}}
+				for _, pair := range m.GetLabel() {
+					labels = append(labels, Label{Name: pair.GetName(), Value: pair.GetValue()})
+				}
+				labels = append(labels, extra...)
+				series = append(series, Series{Labels: labels, Value: value, Timestamp: ts})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				summary := m.GetSummary()
+				for _, q := range summary.GetQuantile() {
+					add("", q.GetValue(), Label{Name: "quantile", Value: formatFloat(q.GetQuantile())})
+				}
+				add("_sum", summary.GetSampleSum())
+				add("_count", float64(summary.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				histogram := m.GetHistogram()
+				infSeen := false
+				for _, bucket := range histogram.GetBucket() {
+					infSeen = infSeen || math.IsInf(bucket.GetUpperBound(), 1)
+					add("_bucket", float64(bucket.GetCumulativeCount()), Label{Name: "le", Value: formatFloat(bucket.GetUpperBound())})
+				}
+				if !infSeen {
+					add("_bucket", float64(histogram.GetSampleCount()), Label{Name: "le", Value: "+Inf"})
+				}
+				add("_sum", histogram.GetSampleSum())
+				add("_count", float64(histogram.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package remotewrite pushes metrics to a Prometheus remote_write endpoint,
+// e.g. Mimir or Thanos Receive, for environments without a scraper.
+// Samples are sent as they are gathered, there is no write-ahead log: what
+// can't be sent after the retries is dropped.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+	"github.com/klauspost/compress/snappy"
+)
+
+// Config of a remote_write client. Basic auth credentials can be given in
+// the URL, Headers carry e.g. the tenant of Mimir (X-Scope-OrgID) or Thanos
+// Receive (THANOS-TENANT).
+type Config struct {
+	URL            string
+	Interval       time.Duration // between pushes of the gathered metrics
+	ExternalLabels map[string]string
+	Headers        map[string]string
+	BatchSize      int           // series per request
+	MaxRetries     int           // of a request failing with a network error, 429 or 5xx
+	Timeout        time.Duration // per request
+}
+
+// Defaults of the optional Config fields
+const (
+	DefaultInterval   = 30 * time.Second
+	DefaultBatchSize  = 2000
+	DefaultMaxRetries = 5
+	DefaultTimeout    = 30 * time.Second
+)
+
+// Backoff between retries, doubled after each of them
+const (
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 30 * time.Second
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Series is a single sample of a time series, Timestamp in milliseconds
+type Series struct {
+	Labels    []Label
+	Value     float64
+	Timestamp int64
+}
+
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Client{cfg: cfg, httpClient: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Write sends the series in batches of BatchSize, with the external labels
+// added. Labels of the series take precedence over external labels.
+func (c *Client) Write(ctx context.Context, series []Series) error {
+	for start := 0; start < len(series); start += c.cfg.BatchSize {
+		end := min(start+c.cfg.BatchSize, len(series))
+		batch := make([]Series, 0, end-start)
+		for _, s := range series[start:end] {
+			batch = append(batch, Series{Labels: c.withExternalLabels(s.Labels), Value: s.Value, Timestamp: s.Timestamp})
+		}
+		if err := c.send(ctx, snappy.Encode(nil, Encode(batch))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) withExternalLabels(labels []Label) []Label {
+	merged := make([]Label, 0, len(labels)+len(c.cfg.ExternalLabels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		merged = append(merged, label)
+		seen[label.Name] = true
+	}
+	for name, value := range c.cfg.ExternalLabels {
+		if !seen[name] {
+			merged = append(merged, Label{Name: name, Value: value})
+		}
+	}
+	// The protocol requires labels sorted by name
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
+
+// send posts a request, retrying failures that may be temporary
+func (c *Client) send(ctx context.Context, body []byte) error {
+	policy := retry.Policy{MaxAttempts: c.cfg.MaxRetries + 1, InitialBackoff: minBackoff, MaxBackoff: maxBackoff, Jitter: 0.2}
+	return retry.Do(ctx, "remote-write", policy, func(ctx context.Context) error {
+		retryable, err := c.post(ctx, body)
+		if err != nil && !retryable {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+}
+
+// post sends one request and tells if a failure is worth retrying
+func (c *Client) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "prysm")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for name, value := range c.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("remote write to %s: %s: %s", redactURL(c.cfg.URL), resp.Status, bytes.TrimSpace(msg))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
+
+// ParseLabels parses comma separated name=value pairs, as given for the
+// external labels and headers on the command line
+func ParseLabels(pairs string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(pairs, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid pair %q, expected name=value", pair)
+		}
+		labels[name] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// redactURL drops credentials from the URL for error messages
+func redactURL(rawURL string) string {
+	if at := strings.Index(rawURL, "@"); at >= 0 {
+		if scheme := strings.Index(rawURL, "://"); scheme >= 0 && scheme < at {
+			return rawURL[:scheme+3] + "***@" + rawURL[at+1:]
+		}
+	}
+	return rawURL
+}
@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// receiver is a remote_write endpoint answering with the given statuses in
+// turn, the last one repeated, and recording the decoded requests
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests [][]decodedSeries
+	headers  []http.Header
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	r := &receiver{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		decoded, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, decodeWriteRequest(t, decoded))
+		r.headers = append(r.headers, req.Header.Clone())
+		status := r.statuses[0]
+		if len(r.statuses) > 1 {
+			r.statuses = r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func testSeries(n int) []Series {
+	series := make([]Series, n)
+	for i := range series {
+		series[i] = Series{Labels: []Label{{Name: "__name__", Value: "up"}}, Value: float64(i), Timestamp: 1000}
+	}
+	return series
+}
+
+func TestWrite(t *testing.T) {
+	r, server := newReceiver(t, http.StatusNoContent)
+	client := NewClient(Config{
+		URL:            server.URL,
+		ExternalLabels: map[string]string{"cluster": "edge-1", "__name__": "ignored"},
+		Headers:        map[string]string{"X-Scope-OrgID": "tenant"},
+	})
+
+	require.NoError(t, client.Write(context.Background(), []Series{{
+		Labels:    []Label{{Name: "__name__", Value: "up"}, {Name: "instance", Value: "node-1"}},
+		Value:     1,
+		Timestamp: 1000,
+	}}))
+	require.Len(t, r.requests, 1)
+	assert.Equal(t, []decodedSeries{{labels: []string{"__name__=up", "cluster=edge-1", "instance=node-1"}, value: 1, timestamp: 1000}}, r.requests[0])
+	assert.Equal(t, "snappy", r.headers[0].Get("Content-Encoding"))
+	assert.Equal(t, "0.1.0", r.headers[0].Get("X-Prometheus-Remote-Write-Version"))
+	assert.Equal(t, "tenant", r.headers[0].Get("X-Scope-OrgID"))
+}
+
+func TestWriteBatches(t *testing.T) {
+	r, server := newReceiver(t, http.StatusOK)
+	client := NewClient(Config{URL: server.URL, BatchSize: 2})
+
+	require.NoError(t, client.Write(context.Background(), testSeries(5)))
+	require.Len(t, r.requests, 3)
+	assert.Len(t, r.requests[0], 2)
+	assert.Len(t, r.requests[2], 1)
+	assert.Equal(t, 4.0, r.requests[2][0].value)
+}
+
+func TestWriteRetries(t *testing.T) {
+	r, server := newReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent)
+	client := NewClient(Config{URL: server.URL, MaxRetries: 2})
+
+	require.NoError(t, client.Write(context.Background(), testSeries(1)))
+	assert.Len(t, r.requests, 3)
+}
+
+func TestWriteGivesUp(t *testing.T) {
+	r, server := newReceiver(t, http.StatusInternalServerError)
+	client := NewClient(Config{URL: server.URL, MaxRetries: 1})
+
+	err := client.Write(context.Background(), testSeries(1))
+	assert.ErrorContains(t, err, "500")
+	assert.Len(t, r.requests, 2)
+}
+
+func TestWriteDoesNotRetryClientErrors(t *testing.T) {
+	r, server := newReceiver(t, http.StatusBadRequest)
+	client := NewClient(Config{URL: server.URL, MaxRetries: 3})
+
+	err := client.Write(context.Background(), testSeries(1))
+	assert.ErrorContains(t, err, "400")
+	assert.Len(t, r.requests, 1)
+}
+
+func TestFromMetricFamilies(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total", Help: "h"}, []string{"bucket"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Help: "h", Buckets: []float64{0.5}})
+	registry.MustRegister(counter, histogram)
+	counter.WithLabelValues("b").Add(3)
+	histogram.Observe(0.25)
+	histogram.Observe(2)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	series := FromMetricFamilies(families, 1000)
+
+	got := map[string]float64{}
+	for _, s := range series {
+		assert.Equal(t, int64(1000), s.Timestamp)
+		key := ""
+		for _, label := range s.Labels {
+			key += label.Name + "=" + label.Value + " "
+		}
+		got[key] = s.Value
+	}
+	assert.Equal(t, map[string]float64{
+		"__name__=latency_seconds_bucket le=0.5 ":  1,
+		"__name__=latency_seconds_bucket le=+Inf ": 2,
+		"__name__=latency_seconds_sum ":            2.25,
+		"__name__=latency_seconds_count ":          2,
+		"__name__=requests_total bucket=b ":        3,
+	}, got)
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels("cluster=edge-1, region = eu ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster": "edge-1", "region": "eu"}, labels)
+
+	_, err = ParseLabels("cluster")
+	assert.Error(t, err)
+}
+
+type decodedSeries struct {
+	labels    []string
+	value     float64
+	timestamp int64
+}
+
+// decodeWriteRequest decodes the fields of a WriteRequest the client sets
+func decodeWriteRequest(t *testing.T, data []byte) []decodedSeries {
+	t.Helper()
+	var result []decodedSeries
+	forEachField(t, data, func(_ protowire.Number, value []byte) {
+		series := decodedSeries{}
+		forEachField(t, value, func(num protowire.Number, value []byte) {
+			switch num {
+			case 1:
+				var label [2]string
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					label[num-1] = string(value)
+				})
+				series.labels = append(series.labels, label[0]+"="+label[1])
+			case 2:
+				forEachField(t, value, func(num protowire.Number, value []byte) {
+					switch num {
+					case 1:
+						bits, _ := protowire.ConsumeFixed64(value)
+						series.value = math.Float64frombits(bits)
+					case 2:
+						ts, _ := protowire.ConsumeVarint(value)
+						series.timestamp = int64(ts)
+					}
+				})
+			}
+		})
+		result = append(result, series)
+	})
+	return result
+}
+
+// forEachField calls fn with the raw value of every field in data; values
+// of fixed64 and varint fields are passed in their wire encoding
+func forEachField(t *testing.T, data []byte, fn func(protowire.Number, []byte)) {
+	t.Helper()
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		require.GreaterOrEqual(t, n, 0)
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		require.GreaterOrEqual(t, n, 0)
+		value := data[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(data)
+		}
+		fn(num, value)
+		data = data[n:]
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retry retries the calls to external systems that may fail for a
+// moment: the RGW admin API and radosgw-admin, NATS publishes and KV
+// operations, and smartctl. A call is retried with an exponential backoff
+// and jitter until it succeeds, fails permanently, runs out of attempts or
+// its context is canceled. The attempts, retries and failures are counted
+// by target, the external system called.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// Policy of the retries of a call
+type Policy struct {
+	MaxAttempts    int           // Attempts including the first one, 1 or less never retries
+	InitialBackoff time.Duration // Backoff after the first attempt, doubled after every further one
+	MaxBackoff     time.Duration // Upper bound of the backoff, unbounded if 0
+	Jitter         float64       // Fraction of the backoff randomized, 0.2 waits 80% to 120% of it
+}
+
+// DefaultPolicy suits calls taking up to a few seconds, e.g. HTTP requests
+var DefaultPolicy = Policy{MaxAttempts: 3, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second, Jitter: 0.2}
+
+// Backoff returns the time to wait after attempt, counted from 1
+func (p Policy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff = time.Duration(float64(backoff) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return backoff
+}
+
+var (
+	attemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prysm_retry_attempts_total",
+		Help: "Calls to external systems by target, including the retries",
+	}, []string{"target"})
+	retriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prysm_retry_retries_total",
+		Help: "Calls to external systems retried after a failure by target",
+	}, []string{"target"})
+	failuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prysm_retry_failures_total",
+		Help: "Calls to external systems failed by target and reason: permanent, exhausted or canceled",
+	}, []string{"target", "reason"})
+)
+
+// Collectors returns the retry metrics, registered with the metrics server
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{attemptsTotal, retriesTotal, failuresTotal}
+}
+
+// permanentError is a failure that is not worth retrying
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. an unknown user or denied
+// access. Do returns err itself.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// Do calls fn until it succeeds, fails with a Permanent error, has been
+// attempted p.MaxAttempts times or ctx is canceled, and returns its last
+// error. target names the external system in the metrics and logs, e.g.
+// smartctl.
+func Do(ctx context.Context, target string, p Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, target, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is Do for calls returning a value, the one of the successful
+// attempt
+func DoValue[T any](ctx context.Context, target string, p Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		attemptsTotal.WithLabelValues(target).Inc()
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		var permanent permanentError
+		switch {
+		case errors.As(err, &permanent):
+			failuresTotal.WithLabelValues(target, "permanent").Inc()
+			return value, unwrapPermanent(err)
+		case ctx.Err() != nil:
+			failuresTotal.WithLabelValues(target, "canceled").Inc()
+			return value, err
+		case attempt >= p.MaxAttempts:
+			failuresTotal.WithLabelValues(target, "exhausted").Inc()
+			return value, err
+		}
+
+		backoff := p.Backoff(attempt)
+		log.Warn().Err(err).Str("target", target).Int("attempt", attempt).Dur("backoff", backoff).Msg("call failed, retrying")
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			failuresTotal.WithLabelValues(target, "canceled").Inc()
+			return value, err
+		case <-timer.C:
+		}
+		retriesTotal.WithLabelValues(target).Inc()
+	}
+}
+
+// unwrapPermanent removes the permanent marks of err, which callers don't
+// need to know about
+func unwrapPermanent(err error) error {
+	if permanent, ok := err.(permanentError); ok {
+		return unwrapPermanent(permanent.err)
+	}
+	return err
+}
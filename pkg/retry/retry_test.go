@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var fast = Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	value, err := DoValue(context.Background(), "test-success", fast, func(context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("unavailable")
+		}
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", value)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3.0, testutil.ToFloat64(attemptsTotal.WithLabelValues("test-success")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(retriesTotal.WithLabelValues("test-success")))
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), "test-exhausted", fast, func(context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(failuresTotal.WithLabelValues("test-exhausted", "exhausted")))
+}
+
+func TestDo_Permanent(t *testing.T) {
+	notFound := errors.New("not found")
+	calls := 0
+	err := Do(context.Background(), "test-permanent", fast, func(context.Context) error {
+		calls++
+		return Permanent(notFound)
+	})
+	assert.Equal(t, notFound, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(failuresTotal.WithLabelValues("test-permanent", "permanent")))
+	assert.NoError(t, Permanent(nil))
+}
+
+func TestDo_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, "test-canceled", Policy{MaxAttempts: 5, InitialBackoff: time.Hour}, func(context.Context) error {
+		calls++
+		cancel()
+		return errors.New("unavailable")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(failuresTotal.WithLabelValues("test-canceled", "canceled")))
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 2*time.Second, p.Backoff(2))
+	assert.Equal(t, 4*time.Second, p.Backoff(3))
+	assert.Equal(t, 5*time.Second, p.Backoff(4))
+	assert.Equal(t, 5*time.Second, p.Backoff(100))
+
+	p.Jitter = 0.2
+	for range 100 {
+		backoff := p.Backoff(1)
+		assert.GreaterOrEqual(t, backoff, 800*time.Millisecond)
+		assert.LessOrEqual(t, backoff, 1200*time.Millisecond)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cobaltcore-dev/prysm/pkg/producers/cephhealth"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/diskhealthmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/kernelmetrics"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/nodeinventory"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/opslog"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/osdperf"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/quotausagemonitor"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/radosgwusage"
+	"github.com/cobaltcore-dev/prysm/pkg/producers/resourceusage"
+	"github.com/cobaltcore-dev/prysm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "write the documents of the current schema versions")
+
+// Go types published with each schema
+var payloads = map[string]any{
+	schema.OpsEvent.Name:           opslog.S3OperationLog{},
+	schema.OpsMetrics.Name:         opslog.AggregatedMetrics{},
+	schema.OpsSecurityEvent.Name:   opslog.SecurityEvent{},
+	schema.RadosGWUsageEvent.Name:  radosgwusage.Event{},
+	schema.QuotaUsage.Name:         []quotausagemonitor.QuotaUsage{},
+	schema.DiskEvent.Name:          diskhealthmetrics.NatsEvent{},
+	schema.DiskChangeEvent.Name:    diskhealthmetrics.DiskChangeEvent{},
+	schema.DiskSnapshot.Name:       diskhealthmetrics.Snapshot{},
+	schema.DiskFirmwareReport.Name: diskhealthmetrics.FirmwareReport{},
+	schema.CephHealth.Name:         cephhealth.ClusterStatus{},
+	schema.CephHealthEvent.Name:    cephhealth.HealthEvent{},
+	schema.OSDPerf.Name:            osdperf.OSDPerf{},
+	schema.KernelMetrics.Name:      kernelmetrics.KernelMetrics{},
+	schema.ResourceUsage.Name:      resourceusage.ResourceUsage{},
+	schema.NodeInventory.Name:      nodeinventory.Inventory{},
+}
+
+// TestDocuments fails when a payload type changed without its document. Run
+// go test ./pkg/schema -update to write the documents of additive changes;
+// breaking changes need a new schema version.
+func TestDocuments(t *testing.T) {
+	for _, s := range schema.All() {
+		t.Run(s.Name, func(t *testing.T) {
+			payload, ok := payloads[s.Name]
+			require.True(t, ok, "no payload type of %s", s)
+
+			generated := schema.Generate(s, payload)
+			data, err := json.MarshalIndent(generated, "", "  ")
+			require.NoError(t, err)
+			data = append(data, '\n')
+
+			path := filepath.Join("schemas", fmt.Sprintf("%s.v%d.json", s.Name, s.Version))
+			if *update {
+				if previous, err := os.ReadFile(path); err == nil {
+					var old schema.JSONSchema
+					require.NoError(t, json.Unmarshal(previous, &old))
+					require.Empty(t, schema.Compatible(&old, generated), "breaking changes of %s, bump its version", s)
+				}
+				require.NoError(t, os.WriteFile(path, data, 0o644))
+				return
+			}
+
+			for version := 1; version < s.Version; version++ {
+				_, err := s.Document(version)
+				assert.NoError(t, err, "documents of earlier versions are kept")
+			}
+
+			document, err := s.Document(s.Version)
+			require.NoError(t, err, "run go test ./pkg/schema -update")
+			var current schema.JSONSchema
+			require.NoError(t, json.Unmarshal(document, &current))
+			assert.Empty(t, schema.Compatible(&current, generated), "breaking changes of %s, bump its version", s)
+			assert.JSONEq(t, string(document), string(data), "%s is outdated, run go test ./pkg/schema -update", path)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoding of the payloads published to NATS
+type Encoding string
+
+const (
+	JSON    Encoding = "json"
+	MsgPack Encoding = "msgpack" // smaller and cheaper to decode, for high-volume subjects
+)
+
+// Encodings lists the supported encodings
+var Encodings = []Encoding{JSON, MsgPack}
+
+// ContentType is the header naming the encoding of a message. Messages
+// without it are JSON.
+const ContentType = "Content-Type"
+
+var contentTypes = map[Encoding]string{
+	JSON:    "application/json",
+	MsgPack: "application/msgpack",
+}
+
+// The encoding of published payloads, set with Configure
+var configured = JSON
+
+// ParseEncoding returns the encoding of name, json if name is empty
+func ParseEncoding(name string) (Encoding, error) {
+	if name == "" {
+		return JSON, nil
+	}
+	for _, e := range Encodings {
+		if string(e) == strings.ToLower(name) {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown NATS payload encoding %q, expected json or msgpack", name)
+}
+
+// Configure sets the encoding of the payloads published from now on.
+// Consumers decode every encoding, whatever is configured.
+func Configure(e Encoding) {
+	configured = e
+}
+
+// marshal encodes v with e. MessagePack uses the names of the JSON tags, so
+// both encodings share the field names of the schema documents.
+func marshal(e Encoding, v any) ([]byte, error) {
+	if e != MsgPack {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshal decodes data of content type contentType into v
+func unmarshal(contentType string, data []byte, v any) error {
+	e, err := encodingOf(contentType)
+	if err != nil {
+		return err
+	}
+	if e != MsgPack {
+		return json.Unmarshal(data, v)
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// encodingOf returns the encoding of a Content-Type header
+func encodingOf(contentType string) (Encoding, error) {
+	if contentType == "" {
+		return JSON, nil
+	}
+	for e, value := range contentTypes {
+		if value == contentType {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown content type %q", ErrIncompatible, contentType)
+}
+
+// toJSON converts data of content type contentType to JSON
+func toJSON(contentType string, data []byte) ([]byte, error) {
+	e, err := encodingOf(contentType)
+	if err != nil || e == JSON {
+		return data, err
+	}
+	var v any
+	if err := unmarshal(contentType, data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
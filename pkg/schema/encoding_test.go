@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEncoding(t *testing.T) {
+	for name, expected := range map[string]Encoding{"": JSON, "json": JSON, "msgpack": MsgPack, "MsgPack": MsgPack} {
+		e, err := ParseEncoding(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, e, name)
+	}
+	_, err := ParseEncoding("protobuf")
+	assert.Error(t, err)
+}
+
+// useEncoding publishes with e until the test ends
+func useEncoding(t *testing.T, e Encoding) {
+	Configure(e)
+	t.Cleanup(func() { Configure(JSON) })
+}
+
+type opsEntry struct {
+	Bucket    string            `json:"bucket"`
+	Object    string            `json:"object,omitempty"`
+	BytesSent int               `json:"bytes_sent"`
+	Time      time.Time         `json:"time"`
+	Tags      map[string]string `json:"tags"`
+	Ignored   string            `json:"-"`
+}
+
+func TestMsgPack(t *testing.T) {
+	useEncoding(t, MsgPack)
+	s := Schema{Name: "test-event", Version: 1}
+	entry := opsEntry{
+		Bucket:    "photos",
+		BytesSent: 1024,
+		Time:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:      map[string]string{"zone": "a"},
+		Ignored:   "secret",
+	}
+
+	msg, err := NewMsg("test", s, entry)
+	require.NoError(t, err)
+	assert.Equal(t, "application/msgpack", msg.Header.Get(ContentType))
+	assert.NotContains(t, string(msg.Data), "secret")
+	jsonData, err := marshal(JSON, entry)
+	require.NoError(t, err)
+	assert.Less(t, len(msg.Data), len(jsonData))
+
+	var decoded opsEntry
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, entry.Bucket, decoded.Bucket)
+	assert.Equal(t, entry.BytesSent, decoded.BytesSent)
+	assert.True(t, entry.Time.Equal(decoded.Time))
+	assert.Equal(t, entry.Tags, decoded.Tags)
+
+	// Fields of the JSON tags, so sinks store the same JSON for both
+	data, err := ToJSON(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bucket":"photos","bytes_sent":1024,"time":"2025-01-02T03:04:05Z","tags":{"zone":"a"}}`, string(data))
+}
+
+func TestMsgPackUpgrades(t *testing.T) {
+	useEncoding(t, MsgPack)
+	msg, err := NewMsg("test", Schema{Name: "test-event", Version: 1}, event{Device: "/dev/sda", Count: 2})
+	require.NoError(t, err)
+
+	s := Schema{Name: "test-event", Version: 2, Upgrades: []func([]byte) ([]byte, error){
+		func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"device"`), []byte(`"device_path"`), 1), nil
+		},
+	}}
+	var decoded struct {
+		DevicePath string `json:"device_path"`
+		Count      int    `json:"count"`
+	}
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, "/dev/sda", decoded.DevicePath)
+	assert.Equal(t, 2, decoded.Count)
+}
+
+func TestUnknownContentType(t *testing.T) {
+	msg := nats.NewMsg("test")
+	msg.Header.Set(ContentType, "application/protobuf")
+	msg.Data = []byte{0x0a}
+
+	var decoded event
+	err := Unmarshal(msg, Schema{Name: "test-event", Version: 1}, &decoded)
+	assert.True(t, errors.Is(err, ErrIncompatible))
+	_, err = ToJSON(msg)
+	assert.True(t, errors.Is(err, ErrIncompatible))
+}
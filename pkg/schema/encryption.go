@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Headers of encrypted messages, naming the algorithm and the key
+const (
+	EncryptionHeader = "Prysm-Encryption"
+	KeyIDHeader      = "Prysm-Key-ID"
+)
+
+// Algorithm of the encrypted payloads, NaCl secretbox (XSalsa20-Poly1305)
+const secretboxAlgorithm = "secretbox"
+
+const nonceSize = 24
+
+// ErrEncrypted is returned for encrypted messages without the key to
+// decrypt them
+var ErrEncrypted = errors.New("payload encrypted with an unknown key")
+
+// ErrPlaintext is returned for plain text messages while keys are
+// configured, unless plain text is allowed
+var ErrPlaintext = errors.New("plain text payload, encryption keys are configured")
+
+// Keyring holds the keys of the payload encryption. The first key encrypts,
+// all keys decrypt, so keys can be rotated without losing messages.
+type Keyring struct {
+	keys []key
+}
+
+type key struct {
+	id     string
+	secret [32]byte
+}
+
+// The keys encrypting published payloads, nil publishes them in plain text,
+// and whether plain text payloads are accepted while keys are configured
+var (
+	keyring        *Keyring
+	allowPlaintext bool
+)
+
+// ConfigureEncryption encrypts the payloads published from now on with the
+// first key of k, and decrypts received payloads with any key of k. Plain
+// text payloads are rejected with ErrPlaintext unless allowPlain is set,
+// e.g. while producers are switched to encryption. A nil keyring disables
+// encryption and accepts plain text.
+func ConfigureEncryption(k *Keyring, allowPlain bool) {
+	keyring = k
+	allowPlaintext = allowPlain
+}
+
+// LoadKeyring reads a keyring from a file of base64 encoded 32 byte keys, one
+// per line. Empty lines and lines starting with # are ignored.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading encryption keys: %w", err)
+	}
+	k, err := ParseKeyring(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return k, nil
+}
+
+// ParseKeyring parses the keys of LoadKeyring
+func ParseKeyring(data []byte) (*Keyring, error) {
+	k := &Keyring{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		secret, err := base64.StdEncoding.DecodeString(text)
+		if err != nil || len(secret) != 32 {
+			return nil, fmt.Errorf("line %d: expected a base64 encoded 32 byte key", line)
+		}
+		sum := sha256.Sum256(secret)
+		next := key{id: hex.EncodeToString(sum[:4])}
+		copy(next.secret[:], secret)
+		k.keys = append(k.keys, next)
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("no encryption keys")
+	}
+	return k, nil
+}
+
+// KeyID returns the ID of the key encrypting payloads, the start of its
+// SHA-256 hash
+func (k *Keyring) KeyID() string {
+	return k.keys[0].id
+}
+
+// seal encrypts data with the first key of k
+func (k *Keyring) seal(data []byte) ([]byte, error) {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	return secretbox.Seal(nonce[:], data, &nonce, &k.keys[0].secret), nil
+}
+
+// open decrypts data sealed with the key of id
+func (k *Keyring) open(id string, data []byte) ([]byte, error) {
+	if k == nil {
+		return nil, fmt.Errorf("%w: no keys configured", ErrEncrypted)
+	}
+	for _, candidate := range k.keys {
+		if candidate.id != id {
+			continue
+		}
+		if len(data) < nonceSize {
+			return nil, errors.New("encrypted payload too short")
+		}
+		var nonce [nonceSize]byte
+		copy(nonce[:], data)
+		plain, ok := secretbox.Open(nil, data[nonceSize:], &nonce, &candidate.secret)
+		if !ok {
+			return nil, fmt.Errorf("decrypting payload with key %s failed", id)
+		}
+		return plain, nil
+	}
+	return nil, fmt.Errorf("%w: key %s", ErrEncrypted, id)
+}
+
+// encrypt encrypts the payload of msg with the configured keys, if any
+func encrypt(msg *nats.Msg) error {
+	if keyring == nil {
+		return nil
+	}
+	sealed, err := keyring.seal(msg.Data)
+	if err != nil {
+		return fmt.Errorf("encrypting payload: %w", err)
+	}
+	msg.Header.Set(EncryptionHeader, secretboxAlgorithm)
+	msg.Header.Set(KeyIDHeader, keyring.KeyID())
+	msg.Data = sealed
+	return nil
+}
+
+// decrypt returns the payload of msg, decrypted if it is encrypted
+func decrypt(msg *nats.Msg) ([]byte, error) {
+	switch algorithm := msg.Header.Get(EncryptionHeader); algorithm {
+	case "":
+		if keyring != nil && !allowPlaintext {
+			return nil, ErrPlaintext
+		}
+		return msg.Data, nil
+	case secretboxAlgorithm:
+		return keyring.open(msg.Header.Get(KeyIDHeader), msg.Data)
+	default:
+		return nil, fmt.Errorf("%w: unknown encryption %q", ErrIncompatible, algorithm)
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+// useKeyring encrypts with the keys until the test ends, rejecting plain
+// text payloads
+func useKeyring(t *testing.T, keys ...string) *Keyring {
+	k, err := ParseKeyring([]byte("# test keys\n" + strings.Join(keys, "\n")))
+	require.NoError(t, err)
+	ConfigureEncryption(k, false)
+	t.Cleanup(func() { ConfigureEncryption(nil, false) })
+	return k
+}
+
+func TestLoadKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte(testKey(1)+"\n\n"+testKey(2)+"\n"), 0o600))
+	k, err := LoadKeyring(path)
+	require.NoError(t, err)
+	assert.Len(t, k.keys, 2)
+	assert.Len(t, k.KeyID(), 8)
+
+	for _, invalid := range []string{"", "# only a comment\n", "not base64\n", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := ParseKeyring([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+	_, err = LoadKeyring(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestEncryption(t *testing.T) {
+	useKeyring(t, testKey(1))
+	s := Schema{Name: "test-event", Version: 1}
+
+	msg, err := NewMsg("test", s, event{Device: "/dev/sda", Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "secretbox", msg.Header.Get(EncryptionHeader))
+	assert.NotEmpty(t, msg.Header.Get(KeyIDHeader))
+	assert.NotContains(t, string(msg.Data), "/dev/sda")
+
+	var decoded event
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, event{Device: "/dev/sda", Count: 1}, decoded)
+	data, err := ToJSON(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"device":"/dev/sda","count":1}`, string(data))
+
+	// Same payload, another nonce
+	again, err := NewMsg("test", s, event{Device: "/dev/sda", Count: 1})
+	require.NoError(t, err)
+	assert.NotEqual(t, msg.Data, again.Data)
+
+	// Tampered payloads fail
+	msg.Data[len(msg.Data)-1] ^= 1
+	assert.Error(t, Unmarshal(msg, s, &decoded))
+}
+
+func TestEncryptionMsgPack(t *testing.T) {
+	useEncoding(t, MsgPack)
+	useKeyring(t, testKey(1))
+	s := Schema{Name: "test-event", Version: 1}
+
+	msg, err := NewMsg("test", s, event{Device: "/dev/sda", Count: 1})
+	require.NoError(t, err)
+	var decoded event
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, event{Device: "/dev/sda", Count: 1}, decoded)
+}
+
+func TestEncryptionKeyRotation(t *testing.T) {
+	s := Schema{Name: "test-event", Version: 1}
+	useKeyring(t, testKey(1))
+	old, err := NewMsg("test", s, event{Device: "/dev/sda"})
+	require.NoError(t, err)
+
+	// The new key encrypts, the old one still decrypts
+	useKeyring(t, testKey(2), testKey(1))
+	current, err := NewMsg("test", s, event{Device: "/dev/sdb"})
+	require.NoError(t, err)
+	assert.NotEqual(t, old.Header.Get(KeyIDHeader), current.Header.Get(KeyIDHeader))
+
+	var decoded event
+	require.NoError(t, Unmarshal(old, s, &decoded))
+	assert.Equal(t, "/dev/sda", decoded.Device)
+	require.NoError(t, Unmarshal(current, s, &decoded))
+	assert.Equal(t, "/dev/sdb", decoded.Device)
+
+	// Consumers without the key
+	useKeyring(t, testKey(3))
+	assert.ErrorIs(t, Unmarshal(current, s, &decoded), ErrEncrypted)
+	ConfigureEncryption(nil, false)
+	assert.ErrorIs(t, Unmarshal(current, s, &decoded), ErrEncrypted)
+}
+
+func TestEncryptionPlaintext(t *testing.T) {
+	s := Schema{Name: "test-event", Version: 1}
+	plain, err := NewMsg("test", s, event{Device: "/dev/sdc"})
+	require.NoError(t, err)
+
+	// Rejected once keys are configured, also as JSON
+	k := useKeyring(t, testKey(1))
+	var decoded event
+	assert.ErrorIs(t, Unmarshal(plain, s, &decoded), ErrPlaintext)
+	_, err = ToJSON(plain)
+	assert.ErrorIs(t, err, ErrPlaintext)
+
+	// Accepted while producers are switched to encryption
+	ConfigureEncryption(k, true)
+	require.NoError(t, Unmarshal(plain, s, &decoded))
+	assert.Equal(t, "/dev/sdc", decoded.Device)
+	encrypted, err := NewMsg("test", s, event{Device: "/dev/sdd"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, encrypted.Header.Get(EncryptionHeader))
+}
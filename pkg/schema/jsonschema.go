@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) describing the
+// payloads: the JSON types of values and the properties of objects. A
+// schema without a type accepts any value.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Generate returns the JSON Schema of the JSON encoding of v with the title
+// of s, derived from the Go type of v the way encoding/json encodes it
+func Generate(s Schema, v any) *JSONSchema {
+	doc := generate(reflect.TypeOf(v), map[reflect.Type]bool{})
+	doc.Schema = "https://json-schema.org/draft/2020-12/schema"
+	doc.Title = s.String()
+	return doc
+}
+
+func generate(t reflect.Type, visiting map[reflect.Type]bool) *JSONSchema {
+	if t == nil {
+		return &JSONSchema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case t == rawMessageType, t.Implements(jsonMarshalerType), reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &JSONSchema{} // Encodes itself, any value
+	case t.Implements(textMarshalerType), reflect.PointerTo(t).Implements(textMarshalerType):
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &JSONSchema{Type: "string", Format: "byte"} // base64
+		}
+		return &JSONSchema{Type: "array", Items: generate(t.Elem(), visiting)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: generate(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &JSONSchema{Type: "object"} // Recursive type
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		doc := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		addProperties(doc, t, visiting)
+		return doc
+	default:
+		return &JSONSchema{} // Interfaces, any value
+	}
+}
+
+// addProperties adds the encoded fields of the struct type t to doc,
+// including those of embedded structs without a JSON name
+func addProperties(doc *JSONSchema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for field := range t.Fields() {
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addProperties(doc, fieldType, visiting)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		doc.Properties[name] = generate(field.Type, visiting)
+	}
+}
+
+// Compatible returns the changes from old to current that break consumers
+// of old: removed properties and changed types. Added properties are
+// compatible, consumers ignore unknown fields.
+func Compatible(old, current *JSONSchema) []string {
+	var changes []string
+	compatible(old, current, "$", &changes)
+	sort.Strings(changes)
+	return changes
+}
+
+func compatible(old, current *JSONSchema, path string, changes *[]string) {
+	if old.Type != "" && old.Type != current.Type {
+		*changes = append(*changes, path+": type changed from "+old.Type+" to "+typeName(current.Type))
+		return
+	}
+	for name, property := range old.Properties {
+		next, ok := current.Properties[name]
+		if !ok {
+			*changes = append(*changes, path+"."+name+": removed")
+			continue
+		}
+		compatible(property, next, path+"."+name, changes)
+	}
+	if old.Items != nil && current.Items != nil {
+		compatible(old.Items, current.Items, path+"[]", changes)
+	}
+	if old.AdditionalProperties != nil && current.AdditionalProperties != nil {
+		compatible(old.AdditionalProperties, current.AdditionalProperties, path+".*", changes)
+	}
+}
+
+func typeName(t string) string {
+	if t == "" {
+		return "any"
+	}
+	return t
+}
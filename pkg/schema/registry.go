@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"embed"
+	"fmt"
+)
+
+// Schemas of the payloads published by the producers
+var (
+	OpsEvent           = Schema{Name: "ops-event", Version: 1}            // ops-log: an RGW ops log entry
+	OpsSecurityEvent   = Schema{Name: "ops-security-event", Version: 1}   // ops-log: a denied or anonymous request
+	RadosGWUsageEvent  = Schema{Name: "radosgw-usage-event", Version: 1}  // radosgw-usage: sync and resharding notifications
+	QuotaUsage         = Schema{Name: "quota-usage", Version: 1}          // quota-usage-monitor: the quota usage of all users
+	DiskEvent          = Schema{Name: "disk-event", Version: 1}           // disk-health-metrics: device health, risk and temperature events
+	DiskChangeEvent    = Schema{Name: "disk-change-event", Version: 1}    // disk-health-metrics: health changes between collections
+	DiskSnapshot       = Schema{Name: "disk-snapshot", Version: 1}        // disk-health-metrics: the inventory of a node
+	DiskFirmwareReport = Schema{Name: "disk-firmware-report", Version: 1} // disk-health-metrics: the firmware versions of a node
+	CephHealth         = Schema{Name: "ceph-health", Version: 1}          // ceph-health: the cluster status
+	CephHealthEvent    = Schema{Name: "ceph-health-event", Version: 1}    // ceph-health: health check changes
+	OSDPerf            = Schema{Name: "osd-perf", Version: 1}             // osd-perf: the perf counters of an OSD
+	KernelMetrics      = Schema{Name: "kernel-metrics", Version: 1}       // kernel-metrics: the kernel statistics of a node
+	ResourceUsage      = Schema{Name: "resource-usage", Version: 1}       // resource-usage: the resource usage of a node
+	NodeInventory      = Schema{Name: "node-inventory", Version: 1}       // node-inventory: the hardware inventory of a node
+)
+
+// OpsMetrics are the aggregated metrics of ops-log. Version 2 counts each
+// window aligned to the wall clock apart and names it in window_start and
+// window_end, with the instance_id and host of the sidecar. Version 1 counted
+// since the start of the sidecar with a log file; its payloads decode without
+// a window.
+var OpsMetrics = Schema{Name: "ops-metrics", Version: 2, Upgrades: []func([]byte) ([]byte, error){
+	func(data []byte) ([]byte, error) { return data, nil },
+}}
+
+// All returns the schemas of all payloads
+func All() []Schema {
+	return []Schema{
+		OpsEvent, OpsMetrics, OpsSecurityEvent, RadosGWUsageEvent, QuotaUsage,
+		DiskEvent, DiskChangeEvent, DiskSnapshot, DiskFirmwareReport,
+		CephHealth, CephHealthEvent, OSDPerf, KernelMetrics, ResourceUsage, NodeInventory,
+	}
+}
+
+// JSON Schema documents of every version of every schema, named
+// <name>.v<version>.json
+//
+//go:embed schemas/*.json
+var documents embed.FS
+
+// Document returns the JSON Schema document of version of s
+func (s Schema) Document(version int) ([]byte, error) {
+	data, err := documents.ReadFile(documentPath(s.Name, version))
+	if err != nil {
+		return nil, fmt.Errorf("no JSON Schema of %s/v%d", s.Name, version)
+	}
+	return data, nil
+}
+
+func documentPath(name string, version int) string {
+	return fmt.Sprintf("schemas/%s.v%d.json", name, version)
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema versions the payloads prysm publishes to NATS. Every
+// message carries the name and version of its schema in the Prysm-Schema
+// header, e.g. "ops-event/v1", and the JSON Schema documents of all versions
+// are embedded, so consumers detect payloads they cannot decode instead of
+// silently reading zero values after a field was renamed. See README.md for
+// the compatibility policy. Payloads are JSON, or MessagePack on high-volume
+// subjects, named in the Content-Type header, and optionally encrypted with a
+// shared key.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/nats-io/nats.go"
+)
+
+// Header of the messages naming their schema
+const Header = "Prysm-Schema"
+
+// ErrIncompatible is returned for messages of another schema or of a newer
+// version than the consumer knows
+var ErrIncompatible = errors.New("incompatible payload schema")
+
+// Schema of a NATS payload. Version is the version producers publish;
+// Upgrades[i] converts a payload of version i+1 into one of version i+2, so
+// consumers decode messages of older producers.
+type Schema struct {
+	Name     string
+	Version  int
+	Upgrades []func(data []byte) ([]byte, error)
+}
+
+// String returns the header value of the schema, e.g. ops-event/v1
+func (s Schema) String() string {
+	return s.Name + "/v" + strconv.Itoa(s.Version)
+}
+
+// parseHeader returns the name and version of a Prysm-Schema header
+func parseHeader(value string) (string, int, error) {
+	name, version, ok := strings.Cut(value, "/v")
+	if !ok || name == "" {
+		return "", 0, fmt.Errorf("invalid %s header %q", Header, value)
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 1 {
+		return "", 0, fmt.Errorf("invalid %s header %q", Header, value)
+	}
+	return name, v, nil
+}
+
+// NewMsg encodes v with the configured encoding into a message on subject
+// tagged with s and the exporter identity, encrypted if keys are configured
+func NewMsg(subject string, s Schema, v any) (*nats.Msg, error) {
+	data, err := marshal(configured, v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s payload: %w", s, err)
+	}
+	msg := nats.NewMsg(subject)
+	msg.Header.Set(Header, s.String())
+	msg.Header.Set(ContentType, contentTypes[configured])
+	identity.Current().SetHeaders(msg.Header)
+	msg.Data = data
+	if err := encrypt(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Publish publishes v on subject as a message of schema s, retried while
+// the connection reconnects
+func Publish(nc *nats.Conn, subject string, s Schema, v any) error {
+	msg, err := NewMsg(subject, s, v)
+	if err != nil {
+		return err
+	}
+	return natsutil.PublishMsg(nc, msg)
+}
+
+// Unmarshal decrypts and decodes the payload of msg into v, whatever its
+// encoding. Messages without the schema header are taken as version 1, they
+// were published before the header existed. Payloads of older versions are
+// upgraded, messages of another schema or a newer version fail with
+// ErrIncompatible.
+func Unmarshal(msg *nats.Msg, s Schema, v any) error {
+	version, err := messageVersion(msg.Header.Get(Header), s)
+	if err != nil {
+		return err
+	}
+	data, err := decrypt(msg)
+	if err != nil {
+		return err
+	}
+	contentType := msg.Header.Get(ContentType)
+	if version < s.Version {
+		// Upgrades work on JSON
+		if data, err = toJSON(contentType, data); err != nil {
+			return err
+		}
+		if data, err = upgrade(data, version, s); err != nil {
+			return err
+		}
+		contentType = ""
+	}
+	return unmarshal(contentType, data, v)
+}
+
+// ToJSON returns the payload of msg as JSON, decrypted and whatever its
+// encoding
+func ToJSON(msg *nats.Msg) ([]byte, error) {
+	data, err := decrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	return toJSON(msg.Header.Get(ContentType), data)
+}
+
+// messageVersion returns the version of s of a message with the schema
+// header
+func messageVersion(header string, s Schema) (int, error) {
+	name, version := s.Name, 1
+	if header != "" {
+		var err error
+		if name, version, err = parseHeader(header); err != nil {
+			return 0, err
+		}
+	}
+
+	switch {
+	case name != s.Name:
+		return 0, fmt.Errorf("%w: message is %s, expected %s", ErrIncompatible, header, s.Name)
+	case version > s.Version:
+		return 0, fmt.Errorf("%w: message is %s, this build supports up to %s", ErrIncompatible, header, s)
+	}
+	return version, nil
+}
+
+// upgrade converts JSON data of version to the version of s
+func upgrade(data []byte, version int, s Schema) ([]byte, error) {
+	for ; version < s.Version; version++ {
+		if version > len(s.Upgrades) {
+			return nil, fmt.Errorf("%w: no upgrade of %s from v%d", ErrIncompatible, s.Name, version)
+		}
+		var err error
+		if data, err = s.Upgrades[version-1](data); err != nil {
+			return nil, fmt.Errorf("upgrading %s from v%d: %w", s.Name, version, err)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	Device string `json:"device"`
+	Count  int    `json:"count"`
+}
+
+func TestNewMsg(t *testing.T) {
+	msg, err := NewMsg("disk.events", DiskEvent, event{Device: "/dev/sda", Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "disk.events", msg.Subject)
+	assert.Equal(t, "disk-event/v1", msg.Header.Get(Header))
+	assert.Equal(t, "application/json", msg.Header.Get(ContentType))
+	assert.Equal(t, identity.Current(), identity.FromHeaders(msg.Header))
+	assert.JSONEq(t, `{"device":"/dev/sda","count":1}`, string(msg.Data))
+}
+
+func TestUnmarshal(t *testing.T) {
+	s := Schema{Name: "test-event", Version: 1}
+	msg, err := NewMsg("test", s, event{Device: "/dev/sda", Count: 1})
+	require.NoError(t, err)
+
+	var decoded event
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, event{Device: "/dev/sda", Count: 1}, decoded)
+
+	// Published before the header existed
+	legacy := &nats.Msg{Subject: "test", Data: []byte(`{"device":"/dev/sdb"}`)}
+	require.NoError(t, Unmarshal(legacy, s, &decoded))
+	assert.Equal(t, "/dev/sdb", decoded.Device)
+}
+
+func TestUnmarshalIncompatible(t *testing.T) {
+	s := Schema{Name: "test-event", Version: 1}
+	for header, incompatible := range map[string]bool{
+		"test-event/v2":  true,
+		"other-event/v1": true,
+		"test-event":     false,
+		"test-event/v0":  false,
+	} {
+		msg := nats.NewMsg("test")
+		msg.Header.Set(Header, header)
+		msg.Data = []byte(`{}`)
+
+		var decoded event
+		err := Unmarshal(msg, s, &decoded)
+		require.Error(t, err, header)
+		assert.Equal(t, incompatible, errors.Is(err, ErrIncompatible), header)
+	}
+}
+
+func TestUnmarshalUpgrades(t *testing.T) {
+	// v2 renamed device to device_path, v3 count to total
+	s := Schema{Name: "test-event", Version: 3, Upgrades: []func([]byte) ([]byte, error){
+		func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"device"`), []byte(`"device_path"`), 1), nil
+		},
+		func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"count"`), []byte(`"total"`), 1), nil
+		},
+	}}
+	type eventV3 struct {
+		DevicePath string `json:"device_path"`
+		Total      int    `json:"total"`
+	}
+
+	for _, version := range []string{"test-event/v1", ""} {
+		msg := nats.NewMsg("test")
+		if version != "" {
+			msg.Header.Set(Header, version)
+		}
+		msg.Data = []byte(`{"device":"/dev/sda","count":2}`)
+
+		var decoded eventV3
+		require.NoError(t, Unmarshal(msg, s, &decoded))
+		assert.Equal(t, eventV3{DevicePath: "/dev/sda", Total: 2}, decoded)
+	}
+
+	msg, err := NewMsg("test", Schema{Name: "test-event", Version: 2}, map[string]any{"device_path": "/dev/sdb", "count": 3})
+	require.NoError(t, err)
+	var decoded eventV3
+	require.NoError(t, Unmarshal(msg, s, &decoded))
+	assert.Equal(t, eventV3{DevicePath: "/dev/sdb", Total: 3}, decoded)
+}
+
+func TestUnmarshalOpsMetricsV1(t *testing.T) {
+	msg := nats.NewMsg("rgw.s3.ops.aggregated.metrics")
+	msg.Header.Set(Header, "ops-metrics/v1")
+	msg.Data = []byte(`{"total_requests":5}`)
+
+	var decoded struct {
+		TotalRequests uint64    `json:"total_requests"`
+		WindowStart   time.Time `json:"window_start"`
+	}
+	require.NoError(t, Unmarshal(msg, OpsMetrics, &decoded))
+	assert.Equal(t, uint64(5), decoded.TotalRequests)
+	assert.True(t, decoded.WindowStart.IsZero(), "v1 payloads have no window")
+}
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type Embedded struct {
+	Zone string `json:"zone,omitempty"`
+}
+
+type payload struct {
+	Embedded
+	ID       string            `json:"id"`
+	Skipped  string            `json:"-"`
+	Untagged int64             //
+	Time     time.Time         `json:"time"`
+	Ratio    *float64          `json:"ratio"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]uint64 `json:"labels"`
+	Inner    *inner            `json:"inner"`
+	Raw      json.RawMessage   `json:"raw"`
+	Data     []byte            `json:"data"`
+	Any      any               `json:"any"`
+	Next     *payload          `json:"next"`
+	private  string
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate(Schema{Name: "test", Version: 1}, payload{private: "unused"})
+
+	assert.Equal(t, "test/v1", doc.Title)
+	assert.Equal(t, "object", doc.Type)
+	assert.ElementsMatch(t, []string{"zone", "id", "Untagged", "time", "ratio", "tags", "labels", "inner", "raw", "data", "any", "next"}, keys(doc.Properties))
+	assert.Equal(t, &JSONSchema{Type: "string", Format: "date-time"}, doc.Properties["time"])
+	assert.Equal(t, "number", doc.Properties["ratio"].Type)
+	assert.Equal(t, &JSONSchema{Type: "array", Items: &JSONSchema{Type: "string"}}, doc.Properties["tags"])
+	assert.Equal(t, &JSONSchema{Type: "object", AdditionalProperties: &JSONSchema{Type: "integer"}}, doc.Properties["labels"])
+	assert.Equal(t, "string", doc.Properties["inner"].Properties["name"].Type)
+	assert.Equal(t, &JSONSchema{}, doc.Properties["raw"])
+	assert.Equal(t, &JSONSchema{Type: "string", Format: "byte"}, doc.Properties["data"])
+	assert.Equal(t, &JSONSchema{}, doc.Properties["any"])
+	assert.Equal(t, &JSONSchema{Type: "object"}, doc.Properties["next"])
+
+	list := Generate(Schema{Name: "test", Version: 1}, []inner{})
+	assert.Equal(t, "array", list.Type)
+	assert.Equal(t, "string", list.Items.Properties["name"].Type)
+}
+
+func keys(m map[string]*JSONSchema) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestCompatible(t *testing.T) {
+	s := Schema{Name: "test", Version: 1}
+	type v1 struct {
+		Device string            `json:"device"`
+		Count  int               `json:"count"`
+		Labels map[string]string `json:"labels"`
+		Inner  []inner           `json:"inner"`
+	}
+	type added struct {
+		Device string            `json:"device"`
+		Count  int               `json:"count"`
+		Labels map[string]string `json:"labels"`
+		Inner  []inner           `json:"inner"`
+		Serial string            `json:"serial"`
+	}
+	type broken struct {
+		DevicePath string            `json:"device_path"`
+		Count      string            `json:"count"`
+		Labels     map[string]int    `json:"labels"`
+		Inner      []struct{ N int } `json:"inner"`
+	}
+
+	assert.Empty(t, Compatible(Generate(s, v1{}), Generate(s, added{})))
+	assert.Equal(t, []string{
+		"$.count: type changed from integer to string",
+		"$.device: removed",
+		"$.inner[].name: removed",
+		"$.labels.*: type changed from string to integer",
+	}, Compatible(Generate(s, v1{}), Generate(s, broken{})))
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selflimit keeps prysm within the CPU and memory it is given, so
+// producers colocated with Ceph daemons never starve them: GOMAXPROCS follows
+// the CPU limit, the Go runtime collects garbage harder as the memory limit
+// nears, and batches shrink under memory pressure.
+package selflimit
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultMemoryPercent is the share of the cgroup memory limit the soft
+// memory limit is set to, leaving room for the memory the runtime does not
+// account, e.g. of cgo
+const DefaultMemoryPercent = 90
+
+// Config of the self-limits. With FromCgroup, the limits are derived from
+// the cgroup of the process; CPUs and MemoryLimit set them explicitly.
+type Config struct {
+	FromCgroup    bool
+	CPUs          float64 // CPU cores, GOMAXPROCS is rounded up from; 0 to derive or keep
+	MemoryLimit   int64   // Soft memory limit in bytes; 0 to derive or keep
+	MemoryPercent int     // Share of the cgroup memory limit, defaults to DefaultMemoryPercent
+}
+
+// Validate fails on negative limits or a share above 100%
+func (c Config) Validate() error {
+	if c.CPUs < 0 {
+		return errors.New("CPU limit must not be negative")
+	}
+	if c.MemoryLimit < 0 {
+		return errors.New("memory limit must not be negative")
+	}
+	if c.MemoryPercent < 0 || c.MemoryPercent > 100 {
+		return errors.New("memory limit percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Limits applied to the process, zero if not limited
+type Limits struct {
+	Procs  int
+	Memory int64
+}
+
+// Root of the cgroup file system, replaced by the tests
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupCPUs returns the CPU cores of the cgroup, 0 if unlimited or unknown.
+// cgroup v2 has the quota and the period in cpu.max, v1 in separate files.
+func cgroupCPUs() float64 {
+	if data, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			return cpus(fields[0], fields[1])
+		}
+		return 0
+	}
+	quota, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0
+	}
+	period, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0
+	}
+	return cpus(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func cpus(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// cgroupMemory returns the memory limit of the cgroup in bytes, 0 if
+// unlimited or unknown. cgroup v1 reports no limit as a huge number.
+func cgroupMemory() int64 {
+	data, err := os.ReadFile(filepath.Join(cgroupRoot, "memory.max"))
+	if err != nil {
+		data, err = os.ReadFile(filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes"))
+		if err != nil {
+			return 0
+		}
+	}
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || limit <= 0 || limit >= math.MaxInt64/2 {
+		return 0
+	}
+	return limit
+}
+
+// derive returns the limits of cfg, with those of the cgroup if FromCgroup.
+// An explicit limit wins over the cgroup's.
+func derive(cfg Config, numCPU int) Limits {
+	var limits Limits
+	cores := cfg.CPUs
+	if cores == 0 && cfg.FromCgroup {
+		cores = cgroupCPUs()
+	}
+	if cores > 0 {
+		limits.Procs = min(max(int(math.Ceil(cores)), 1), numCPU)
+	}
+
+	limits.Memory = cfg.MemoryLimit
+	if limits.Memory == 0 && cfg.FromCgroup {
+		percent := cfg.MemoryPercent
+		if percent == 0 {
+			percent = DefaultMemoryPercent
+		}
+		limits.Memory = cgroupMemory() * int64(percent) / 100
+	}
+	return limits
+}
+
+// Soft memory limit applied, read by BatchSize
+var memoryLimit atomic.Int64
+
+var (
+	procsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prysm_self_limit_gomaxprocs",
+		Help: "GOMAXPROCS of the process, derived from the CPU limit",
+	})
+	memoryGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prysm_self_limit_memory_bytes",
+		Help: "Soft memory limit of the process in bytes, 0 if not limited",
+	})
+	batchesReduced = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prysm_self_limit_batches_reduced_total",
+		Help: "Batches shrunk because the memory in use neared the soft memory limit",
+	})
+)
+
+// Collectors returns the self-limit metrics, registered with the metrics
+// server
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{procsGauge, memoryGauge, batchesReduced}
+}
+
+// Apply sets GOMAXPROCS and the soft memory limit of cfg. GOMAXPROCS and
+// GOMEMLIMIT set in the environment are left to the runtime.
+func Apply(cfg Config) Limits {
+	limits := derive(cfg, runtime.NumCPU())
+	if os.Getenv("GOMAXPROCS") != "" {
+		limits.Procs = 0
+	}
+	if os.Getenv("GOMEMLIMIT") != "" {
+		limits.Memory = 0
+	}
+
+	if limits.Procs > 0 {
+		runtime.GOMAXPROCS(limits.Procs)
+	}
+	if limits.Memory > 0 {
+		debug.SetMemoryLimit(limits.Memory)
+	}
+	memoryLimit.Store(limits.Memory)
+	procsGauge.Set(float64(runtime.GOMAXPROCS(0)))
+	memoryGauge.Set(float64(limits.Memory))
+
+	if limits.Procs > 0 || limits.Memory > 0 {
+		log.Info().Int("gomaxprocs", runtime.GOMAXPROCS(0)).Int64("memory_limit", limits.Memory).Msg("self-limits applied")
+	}
+	return limits
+}
+
+// Memory in use by the runtime: all of it but the heap returned to the OS
+var memorySamples = []metrics.Sample{
+	{Name: "/memory/classes/total:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+}
+
+func memoryInUse() int64 {
+	samples := make([]metrics.Sample, len(memorySamples))
+	copy(samples, memorySamples)
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 || samples[1].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(samples[0].Value.Uint64() - samples[1].Value.Uint64())
+}
+
+// BatchSize returns the size of the next batch: size, halved above 75% of
+// the soft memory limit and quartered above 90%, so a backlog is sent in
+// smaller requests instead of growing the heap further. Without a memory
+// limit, size is returned.
+func BatchSize(size int) int {
+	return adaptBatchSize(size, memoryInUse, memoryLimit.Load())
+}
+
+func adaptBatchSize(size int, inUse func() int64, limit int64) int {
+	if limit <= 0 || size <= 1 {
+		return size
+	}
+	used := inUse()
+	switch {
+	case used*10 >= limit*9:
+		size = max(size/4, 1)
+	case used*4 >= limit*3:
+		size = max(size/2, 1)
+	default:
+		return size
+	}
+	batchesReduced.Inc()
+	return size
+}
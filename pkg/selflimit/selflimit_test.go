@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package selflimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCgroup writes the files of a cgroup below a temporary root
+func fakeCgroup(t *testing.T, files map[string]string) {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	previous := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = previous })
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{FromCgroup: true, CPUs: 0.5, MemoryLimit: 256 << 20, MemoryPercent: 80}.Validate())
+	assert.Error(t, Config{CPUs: -1}.Validate())
+	assert.Error(t, Config{MemoryLimit: -1}.Validate())
+	assert.Error(t, Config{MemoryPercent: 101}.Validate())
+}
+
+func TestDeriveCgroupV2(t *testing.T) {
+	fakeCgroup(t, map[string]string{"cpu.max": "150000 100000\n", "memory.max": "536870912\n"})
+
+	limits := derive(Config{FromCgroup: true}, 16)
+	assert.Equal(t, 2, limits.Procs, "1.5 cores rounded up")
+	assert.Equal(t, int64(536870912*90/100), limits.Memory)
+
+	limits = derive(Config{FromCgroup: true, CPUs: 0.5, MemoryLimit: 128 << 20}, 16)
+	assert.Equal(t, Limits{Procs: 1, Memory: 128 << 20}, limits, "explicit limits win")
+
+	assert.Equal(t, Limits{}, derive(Config{}, 16), "the cgroup is only read with FromCgroup")
+}
+
+func TestDeriveUnlimited(t *testing.T) {
+	fakeCgroup(t, map[string]string{"cpu.max": "max 100000\n", "memory.max": "max\n"})
+	assert.Equal(t, Limits{}, derive(Config{FromCgroup: true}, 16))
+
+	// More cores than the host has
+	assert.Equal(t, 4, derive(Config{CPUs: 8}, 4).Procs)
+}
+
+func TestDeriveCgroupV1(t *testing.T) {
+	fakeCgroup(t, map[string]string{
+		"cpu/cpu.cfs_quota_us":         "300000\n",
+		"cpu/cpu.cfs_period_us":        "100000\n",
+		"memory/memory.limit_in_bytes": "1073741824\n",
+	})
+	limits := derive(Config{FromCgroup: true, MemoryPercent: 50}, 16)
+	assert.Equal(t, Limits{Procs: 3, Memory: 512 << 20}, limits)
+
+	fakeCgroup(t, map[string]string{
+		"cpu/cpu.cfs_quota_us":         "-1\n",
+		"cpu/cpu.cfs_period_us":        "100000\n",
+		"memory/memory.limit_in_bytes": "9223372036854771712\n",
+	})
+	assert.Equal(t, Limits{}, derive(Config{FromCgroup: true}, 16), "no limits in cgroup v1")
+}
+
+func TestAdaptBatchSize(t *testing.T) {
+	inUse := func(used int64) func() int64 { return func() int64 { return used } }
+	assert.Equal(t, 1000, adaptBatchSize(1000, inUse(900), 0), "no memory limit")
+	assert.Equal(t, 1000, adaptBatchSize(1000, inUse(500), 1000))
+	assert.Equal(t, 500, adaptBatchSize(1000, inUse(750), 1000))
+	assert.Equal(t, 250, adaptBatchSize(1000, inUse(950), 1000))
+	assert.Equal(t, 1, adaptBatchSize(2, inUse(950), 1000))
+}
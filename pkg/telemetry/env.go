@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package telemetry holds what every prysm subcommand sets up the same way:
+// configuration from environment variables over flags, logging and the
+// Prometheus metrics server.
+package telemetry
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix of the environment variables read by the GetEnv functions, set by
+// SetEnvPrefix
+var envPrefix string
+
+// SetEnvPrefix prefixes the environment variables read from now on, e.g.
+// with "OSD_PERF_" INTERVAL is read from OSD_PERF_INTERVAL. prysm agent
+// configures each of its producers with the prefix of the producer, so their
+// environment variables do not collide; "" reads them unprefixed again.
+func SetEnvPrefix(prefix string) {
+	envPrefix = prefix
+}
+
+// Keys of the environment variables looked up while TraceEnv runs, nil
+// otherwise
+var (
+	envTraceMu sync.Mutex
+	envTrace   map[string]bool
+)
+
+func lookupEnv(key string) (string, bool) {
+	envTraceMu.Lock()
+	tracing := envTrace != nil
+	if tracing {
+		envTrace[envPrefix+key] = true
+	}
+	envTraceMu.Unlock()
+	if tracing {
+		return "", false
+	}
+	return os.LookupEnv(envPrefix + key)
+}
+
+// TraceEnv runs read and returns the environment variables it looks up with
+// the GetEnv functions, sorted, e.g. to document the environment variables
+// of a command. They are reported unset while read runs, so it sees the
+// flag values whatever the environment is.
+func TraceEnv(read func()) []string {
+	envTraceMu.Lock()
+	envTrace = map[string]bool{}
+	envTraceMu.Unlock()
+
+	defer func() {
+		envTraceMu.Lock()
+		envTrace = nil
+		envTraceMu.Unlock()
+	}()
+	read()
+
+	envTraceMu.Lock()
+	defer envTraceMu.Unlock()
+	keys := make([]string, 0, len(envTrace))
+	for key := range envTrace {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getEnv(key string) string {
+	value, _ := lookupEnv(key)
+	return value
+}
+
+// GetEnv returns the environment variable key, or fallback if it is not set.
+// Environment variables take precedence over flags, which are the fallback.
+func GetEnv(key, fallback string) string {
+	if value, exists := lookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+func GetEnvInt(key string, defaultValue int) int {
+	valueStr := getEnv(key)
+	if value, err := strconv.Atoi(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func GetEnvInt64(key string, defaultValue int64) int64 {
+	valueStr := getEnv(key)
+	if value, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func GetEnvFloat(key string, defaultValue float64) float64 {
+	if value, exists := lookupEnv(key); exists {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := lookupEnv(key); exists {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+func GetEnvInt64Slice(key string, defaultValue []int64) []int64 {
+	valueStr := getEnv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	values := strings.Split(valueStr, ",")
+	result := make([]int64, len(values))
+	for i, v := range values {
+		value, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return defaultValue
+		}
+		result[i] = value
+	}
+	return result
+}
+
+func GetEnvBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key)
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
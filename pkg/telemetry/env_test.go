@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnv(t *testing.T) {
+	key := "TEST_KEY"
+	fallback := "default_value"
+
+	// Test when the environment variable is not set
+	value := GetEnv(key, fallback)
+	assert.Equal(t, fallback, value)
+
+	// Test when the environment variable is set
+	expectedValue := "expected_value"
+	os.Setenv(key, expectedValue)
+	value = GetEnv(key, fallback)
+	assert.Equal(t, expectedValue, value)
+
+	// Clean up
+	os.Unsetenv(key)
+}
+
+func TestGetEnvInvalidValues(t *testing.T) {
+	t.Setenv("TEST_INT", "not-a-number")
+	t.Setenv("TEST_BOOL", "maybe")
+	t.Setenv("TEST_DURATION", "10")
+
+	assert.Equal(t, 42, GetEnvInt("TEST_INT", 42))
+	assert.True(t, GetEnvBool("TEST_BOOL", true))
+	assert.Equal(t, time.Minute, GetEnvDuration("TEST_DURATION", time.Minute))
+}
+
+func TestGetEnvDuration(t *testing.T) {
+	t.Setenv("TEST_DURATION", "30s")
+	assert.Equal(t, 30*time.Second, GetEnvDuration("TEST_DURATION", time.Minute))
+	assert.Equal(t, time.Minute, GetEnvDuration("MISSING", time.Minute))
+}
+
+func TestMergeMetricsEnv(t *testing.T) {
+	enabled, port := MergeMetricsEnv(false, 8080)
+	assert.False(t, enabled)
+	assert.Equal(t, 8080, port)
+
+	t.Setenv("PROMETHEUS_ENABLED", "true")
+	t.Setenv("PROMETHEUS_PORT", "9090")
+	enabled, port = MergeMetricsEnv(false, 8080)
+	assert.True(t, enabled)
+	assert.Equal(t, 9090, port)
+}
+
+func TestSetEnvPrefix(t *testing.T) {
+	t.Setenv("INTERVAL", "10")
+	t.Setenv("OSD_PERF_INTERVAL", "20")
+	t.Setenv("OSD_PERF_NATS_SUBJECT", "osd.perf")
+	defer SetEnvPrefix("")
+
+	SetEnvPrefix("OSD_PERF_")
+	assert.Equal(t, 20, GetEnvInt("INTERVAL", 0))
+	assert.Equal(t, "osd.perf", GetEnv("NATS_SUBJECT", ""))
+	assert.Equal(t, "fallback", GetEnv("MISSING", "fallback"))
+
+	SetEnvPrefix("")
+	assert.Equal(t, 10, GetEnvInt("INTERVAL", 0))
+}
+
+func TestTraceEnv(t *testing.T) {
+	t.Setenv("NATS_URL", "nats://nats:4222")
+	defer SetEnvPrefix("")
+
+	var natsURL string
+	keys := TraceEnv(func() {
+		natsURL = GetEnv("NATS_URL", "flag")
+		MergeMetricsEnv(false, 8080)
+		SetEnvPrefix("OSD_PERF_")
+		GetEnvDuration("INTERVAL", 0)
+		SetEnvPrefix("")
+	})
+
+	// The environment is not read while tracing
+	assert.Equal(t, "flag", natsURL)
+	assert.Equal(t, []string{"NATS_URL", "OSD_PERF_INTERVAL", "PROMETHEUS_ENABLED", "PROMETHEUS_PORT"}, keys)
+	assert.Equal(t, "nats://nats:4222", GetEnv("NATS_URL", "flag"))
+}
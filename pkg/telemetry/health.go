@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// HealthCheck reports whether a producer works, with a message telling why
+// not
+type HealthCheck func() (bool, string)
+
+// Health checks of the producers of the process, by producer
+var healthChecks = struct {
+	sync.Mutex
+	live  map[string]HealthCheck
+	ready map[string]HealthCheck
+}{
+	live:  map[string]HealthCheck{},
+	ready: map[string]HealthCheck{},
+}
+
+// RegisterHealthChecks serves the liveness and readiness checks of a
+// producer on /healthz and /readyz of the metrics server. The endpoints
+// succeed when the checks of all producers do, so producers running together
+// in prysm agent share them.
+func RegisterHealthChecks(producer string, live, ready HealthCheck) {
+	healthChecks.Lock()
+	defer healthChecks.Unlock()
+	healthChecks.live[producer] = live
+	healthChecks.ready[producer] = ready
+}
+
+// healthHandler runs the checks of all producers, printing one line per
+// producer. Without checks it succeeds.
+func healthHandler(checks map[string]HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		healthChecks.Lock()
+		producers := make([]string, 0, len(checks))
+		for producer := range checks {
+			producers = append(producers, producer)
+		}
+		sort.Strings(producers)
+		results := make([]string, 0, len(producers))
+		healthy := true
+		for _, producer := range producers {
+			ok, message := checks[producer]()
+			healthy = healthy && ok
+			results = append(results, fmt.Sprintf("%s: %s", producer, message))
+		}
+		healthChecks.Unlock()
+
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(w, "ok")
+		}
+		for _, result := range results {
+			fmt.Fprintln(w, result)
+		}
+	}
+}
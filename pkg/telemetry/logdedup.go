@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultLogDedupWindow is the window repeats of a warning or error are
+// collapsed in
+const DefaultLogDedupWindow = 10 * time.Second
+
+// logDedupKey identifies repeats of a line by level and message, so e.g.
+// the fetch failures of many buckets collapse into one line
+type logDedupKey struct {
+	level   zerolog.Level
+	message string
+}
+
+// logRepeats are the repeats of a line in the current window
+type logRepeats struct {
+	since      time.Time
+	suppressed int
+}
+
+// logDeduplicator is a zerolog hook that lets the first warning or error of
+// a message through in every window and drops its repeats, logging a
+// "message repeated N times" summary of them when the window ends
+type logDeduplicator struct {
+	window  time.Duration
+	summary zerolog.Logger // without the hook, so summaries are never dropped
+	now     func() time.Time
+
+	mu    sync.Mutex
+	lines map[logDedupKey]*logRepeats
+	stop  chan struct{}
+}
+
+func newLogDeduplicator(summary zerolog.Logger, window time.Duration) *logDeduplicator {
+	return &logDeduplicator{
+		window:  window,
+		summary: summary,
+		now:     time.Now,
+		lines:   map[logDedupKey]*logRepeats{},
+		stop:    make(chan struct{}),
+	}
+}
+
+// Run drops the event if it repeats a line of the current window
+func (d *logDeduplicator) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < zerolog.WarnLevel || level == zerolog.NoLevel || msg == "" {
+		return
+	}
+	key := logDedupKey{level: level, message: msg}
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	repeats, ok := d.lines[key]
+	if ok && now.Sub(repeats.since) < d.window {
+		repeats.suppressed++
+		e.Discard()
+		return
+	}
+	if ok {
+		d.report(key, repeats)
+	}
+	d.lines[key] = &logRepeats{since: now}
+}
+
+// start flushes the summaries of the ended windows until close, so repeats
+// are reported even if the line is not logged again
+func (d *logDeduplicator) start() {
+	ticker := time.NewTicker(d.window)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.flush()
+			}
+		}
+	}()
+}
+
+// close stops the flushing and reports the repeats of the current windows
+func (d *logDeduplicator) close() {
+	close(d.stop)
+	d.flushBefore(time.Time{})
+}
+
+// flush reports and forgets the lines whose window ended
+func (d *logDeduplicator) flush() {
+	d.flushBefore(d.now().Add(-d.window))
+}
+
+// flushBefore reports and forgets the lines whose window started before
+// since, all of them if it is zero
+func (d *logDeduplicator) flushBefore(since time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, repeats := range d.lines {
+		if since.IsZero() || !repeats.since.After(since) {
+			d.report(key, repeats)
+			delete(d.lines, key)
+		}
+	}
+}
+
+// report logs the summary of the repeats of a window, if there were any
+func (d *logDeduplicator) report(key logDedupKey, repeats *logRepeats) {
+	if repeats.suppressed == 0 {
+		return
+	}
+	d.summary.WithLevel(key.level).
+		Str("repeated_message", key.message).
+		Int("repeated", repeats.suppressed).
+		Dur("window", d.window).
+		Msgf("message repeated %d times", repeats.suppressed)
+}
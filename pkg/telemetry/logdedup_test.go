@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logLines decodes the JSON lines of out
+func logLines(t *testing.T, out *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		line := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLogDeduplicator_CollapsesRepeats(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Unix(0, 0)
+	base := zerolog.New(&out)
+	dedup := newLogDeduplicator(base, 10*time.Second)
+	dedup.now = func() time.Time { return now }
+	logger := base.Hook(dedup)
+
+	for _, bucket := range []string{"bucket-a", "bucket-b", "bucket-c"} {
+		logger.Error().Str("bucket", bucket).Msg("Failed to fetch bucket")
+	}
+	logger.Error().Msg("Failed to fetch user")
+	logger.Info().Msg("Synced buckets")
+	logger.Info().Msg("Synced buckets")
+
+	lines := logLines(t, &out)
+	require.Len(t, lines, 4, "repeated errors are dropped, info lines kept")
+	assert.Equal(t, "bucket-a", lines[0]["bucket"])
+	assert.Equal(t, "Failed to fetch user", lines[1]["message"])
+
+	// The window ends: the repeats are summarized and the next line passes
+	now = now.Add(10 * time.Second)
+	logger.Error().Str("bucket", "bucket-d").Msg("Failed to fetch bucket")
+
+	lines = logLines(t, &out)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "message repeated 2 times", lines[0]["message"])
+	assert.Equal(t, "error", lines[0]["level"])
+	assert.Equal(t, "Failed to fetch bucket", lines[0]["repeated_message"])
+	assert.Equal(t, float64(2), lines[0]["repeated"])
+	assert.Equal(t, "bucket-d", lines[1]["bucket"])
+}
+
+func TestLogDeduplicator_Flush(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Unix(0, 0)
+	base := zerolog.New(&out)
+	dedup := newLogDeduplicator(base, 10*time.Second)
+	dedup.now = func() time.Time { return now }
+	logger := base.Hook(dedup)
+
+	logger.Warn().Msg("Slow response")
+	logger.Warn().Msg("Slow response")
+	logger.Warn().Msg("Slow response")
+	logger.Warn().Msg("Timed out")
+	out.Reset()
+
+	dedup.flush()
+	assert.Empty(t, out.String(), "the window has not ended")
+
+	now = now.Add(time.Minute)
+	dedup.flush()
+	lines := logLines(t, &out)
+	require.Len(t, lines, 1, "lines without repeats have no summary")
+	assert.Equal(t, "message repeated 2 times", lines[0]["message"])
+	assert.Equal(t, "warn", lines[0]["level"])
+	assert.Empty(t, dedup.lines)
+}
+
+func TestSetupLoggingDedup(t *testing.T) {
+	defer func(logger zerolog.Logger, level zerolog.Level) {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	}(log.Logger, zerolog.GlobalLevel())
+
+	var out bytes.Buffer
+	require.NoError(t, SetupLoggingOn(&out, "info", LogFormatJSON, time.Minute))
+	log.Error().Msg("failed")
+	log.Error().Msg("failed")
+	assert.Len(t, logLines(t, &out), 1)
+
+	// Closing the deduplicator reports the pending repeats
+	require.NoError(t, SetupLoggingOn(&out, "info", LogFormatJSON, 0))
+	lines := logLines(t, &out)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "message repeated 1 times", lines[0]["message"])
+
+	log.Error().Msg("failed")
+	log.Error().Msg("failed")
+	assert.Len(t, logLines(t, &out), 2)
+
+	assert.Error(t, SetupLoggingOn(&out, "info", LogFormatJSON, -time.Second))
+}
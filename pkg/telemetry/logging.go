@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Log formats
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// LogLevels lists the accepted log levels, most verbose first
+var LogLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
+
+// logDedup collapses the repeated warnings and errors of the global logger
+var logDedup *logDeduplicator
+
+// SetupLogging sets the global log level and the format of the log output
+// on stdout: JSON lines for log collectors, or human readable. Repeats of a
+// warning or error within dedupWindow are collapsed into a summary, none if
+// it is 0.
+func SetupLogging(level, format string, dedupWindow time.Duration) error {
+	return SetupLoggingOn(os.Stdout, level, format, dedupWindow)
+}
+
+// SetupLoggingOn is SetupLogging with the log output on out, e.g. on stderr
+// for a command printing its result on stdout
+func SetupLoggingOn(out io.Writer, level, format string, dedupWindow time.Duration) error {
+	if !slices.Contains(LogLevels, level) {
+		return fmt.Errorf("unknown log level %q, expected one of %s", level, strings.Join(LogLevels, ", "))
+	}
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case LogFormatJSON, "":
+	case LogFormatConsole:
+		out = zerolog.ConsoleWriter{Out: out}
+	default:
+		return fmt.Errorf("unknown log format %q, expected %s or %s", format, LogFormatJSON, LogFormatConsole)
+	}
+
+	if dedupWindow < 0 {
+		return fmt.Errorf("invalid log dedup window %s, expected 0 or more", dedupWindow)
+	}
+
+	zerolog.SetGlobalLevel(lvl)
+	logger := zerolog.New(out).With().Timestamp().Logger()
+	if logDedup != nil {
+		logDedup.close()
+		logDedup = nil
+	}
+	if dedupWindow > 0 {
+		logDedup = newLogDeduplicator(logger, dedupWindow)
+		logDedup.start()
+		logger = logger.Hook(logDedup)
+	}
+	log.Logger = logger
+	return nil
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupLogging(t *testing.T) {
+	defer func(logger zerolog.Logger, level zerolog.Level) {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	}(log.Logger, zerolog.GlobalLevel())
+
+	var out bytes.Buffer
+	require.NoError(t, SetupLoggingOn(&out, "info", LogFormatJSON, 0))
+	log.Debug().Msg("hidden")
+	log.Info().Str("key", "value").Msg("shown")
+
+	entry := map[string]any{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "shown", entry["message"])
+	assert.Equal(t, "value", entry["key"])
+
+	out.Reset()
+	require.NoError(t, SetupLoggingOn(&out, "info", LogFormatConsole, 0))
+	log.Info().Msg("shown")
+	assert.Contains(t, out.String(), "INF")
+	assert.False(t, json.Valid(out.Bytes()))
+}
+
+func TestSetupLoggingLevels(t *testing.T) {
+	defer func(logger zerolog.Logger, level zerolog.Level) {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	}(log.Logger, zerolog.GlobalLevel())
+
+	var out bytes.Buffer
+	require.NoError(t, SetupLoggingOn(&out, "trace", LogFormatJSON, 0))
+	log.Trace().Msg("shown")
+	assert.Contains(t, out.String(), `"level":"trace"`)
+
+	out.Reset()
+	require.NoError(t, SetupLoggingOn(&out, "error", LogFormatJSON, 0))
+	log.Warn().Msg("hidden")
+	assert.Empty(t, out.String())
+
+	// Accepted by --verbosity before --log-level existed
+	out.Reset()
+	require.NoError(t, SetupLoggingOn(&out, "fatal", LogFormatJSON, 0))
+	log.Error().Msg("hidden")
+	assert.Empty(t, out.String())
+	require.NoError(t, SetupLoggingOn(&out, "panic", LogFormatJSON, 0))
+}
+
+func TestSetupLoggingInvalid(t *testing.T) {
+	assert.Error(t, SetupLoggingOn(&bytes.Buffer{}, "loud", LogFormatJSON, 0))
+	assert.Error(t, SetupLoggingOn(&bytes.Buffer{}, "", LogFormatJSON, 0))
+	assert.Error(t, SetupLoggingOn(&bytes.Buffer{}, "info", "xml", 0))
+}
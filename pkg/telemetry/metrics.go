@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/identity"
+	"github.com/cobaltcore-dev/prysm/pkg/metricnames"
+	"github.com/cobaltcore-dev/prysm/pkg/natsutil"
+	"github.com/cobaltcore-dev/prysm/pkg/retry"
+	"github.com/cobaltcore-dev/prysm/pkg/selflimit"
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+// MetricsTLS is the certificate the metrics server is served with, HTTPS
+// when both files are set
+type MetricsTLS struct {
+	CertFile string
+	KeyFile  string
+}
+
+func (t MetricsTLS) enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// Validate fails unless both or none of the files are set
+func (t MetricsTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("metrics TLS requires both a certificate and a key")
+	}
+	return nil
+}
+
+// TLS of the metrics server, set once on startup by ConfigureMetricsTLS
+var metricsTLS MetricsTLS
+
+func ConfigureMetricsTLS(t MetricsTLS) {
+	metricsTLS = t
+}
+
+// MergeMetricsEnv returns whether the metrics server is enabled and its port,
+// PROMETHEUS_ENABLED and PROMETHEUS_PORT taking precedence over the flags
+func MergeMetricsEnv(enabled bool, port int) (bool, int) {
+	return GetEnvBool("PROMETHEUS_ENABLED", enabled), GetEnvInt("PROMETHEUS_PORT", port)
+}
+
+// Ports of the metrics servers started, the handlers and the build info
+// metric are registered with the first one
+var metricsServers = struct {
+	sync.Mutex
+	ports map[int]bool
+}{ports: map[int]bool{}}
+
+// newBuildInfo returns the prysm_build_info metric of a build, always 1 with
+// the build metadata as labels
+func newBuildInfo(info version.Info) prometheus.Gauge {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prysm_build_info",
+		Help: "Build metadata of prysm: version, commit, build date, Go version and features",
+		ConstLabels: prometheus.Labels{
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"build_date": info.BuildDate,
+			"go_version": info.GoVersion,
+			"features":   strings.Join(info.Features, ","),
+		},
+	})
+	gauge.Set(1)
+	return gauge
+}
+
+// StartMetricsServer serves the metrics of the default registry on /metrics,
+// named with the metric prefix and labeled with the exporter identity, and the health checks on /healthz and
+// /readyz, along with the handlers registered on http.DefaultServeMux, in
+// the background, behind the access control of ConfigureMetricsAccess. A port is served once, so producers sharing it in prysm
+// agent share the server. Failing to listen is fatal.
+func StartMetricsServer(port int) {
+	metricsServers.Lock()
+	defer metricsServers.Unlock()
+	if metricsServers.ports[port] {
+		return
+	}
+	if len(metricsServers.ports) == 0 {
+		prometheus.MustRegister(newBuildInfo(version.Get()), panicsTotal, metricsRequestsRejected)
+		prometheus.MustRegister(retry.Collectors()...)
+		prometheus.MustRegister(natsutil.Collectors()...)
+		prometheus.MustRegister(selflimit.Collectors()...)
+		// promhttp.Handler with the metric prefix and the labels of the exporter identity
+		http.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+			promhttp.HandlerFor(identity.Gatherer(metricnames.Gatherer(prometheus.DefaultGatherer)), promhttp.HandlerOpts{})))
+		http.Handle("/healthz", healthHandler(healthChecks.live))
+		http.Handle("/readyz", healthHandler(healthChecks.ready))
+	}
+	metricsServers.ports[port] = true
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           metricsAccess.guard(http.DefaultServeMux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		var err error
+		if metricsTLS.enabled() {
+			log.Info().Msgf("starting prometheus metrics server on :%d with tls", port)
+			err = server.ListenAndServeTLS(metricsTLS.CertFile, metricsTLS.KeyFile)
+		} else {
+			log.Info().Msgf("starting prometheus metrics server on :%d", port)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("error starting prometheus metrics server")
+		}
+	}()
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/version"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsTLSValidate(t *testing.T) {
+	assert.NoError(t, MetricsTLS{}.Validate())
+	assert.NoError(t, MetricsTLS{CertFile: "tls.crt", KeyFile: "tls.key"}.Validate())
+	assert.Error(t, MetricsTLS{CertFile: "tls.crt"}.Validate())
+}
+
+func TestBuildInfo(t *testing.T) {
+	info := version.Info{
+		Version:   "v1.2.3",
+		Commit:    "abc123",
+		BuildDate: "2025-01-02T03:04:05Z",
+		GoVersion: "go1.26.0",
+		Features:  []string{"smartctl", "nvme-cli"},
+	}
+	expected := `
+# HELP prysm_build_info Build metadata of prysm: version, commit, build date, Go version and features
+# TYPE prysm_build_info gauge
+prysm_build_info{build_date="2025-01-02T03:04:05Z",commit="abc123",features="smartctl,nvme-cli",go_version="go1.26.0",version="v1.2.3"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(newBuildInfo(info), strings.NewReader(expected)))
+}
+
+func TestHealthHandler(t *testing.T) {
+	checks := map[string]HealthCheck{}
+	handler := healthHandler(checks)
+	get := func() (int, string) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return recorder.Code, recorder.Body.String()
+	}
+
+	code, body := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok\n", body)
+
+	checks["osd-perf"] = func() (bool, string) { return true, "ok" }
+	checks["disk-health-metrics"] = func() (bool, string) { return false, "first scan has not completed" }
+	code, body = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "disk-health-metrics: first scan has not completed\nosd-perf: ok\n", body)
+}
+
+func TestMetricsAccessValidate(t *testing.T) {
+	assert.NoError(t, MetricsAccess{}.Validate())
+	assert.NoError(t, MetricsAccess{Username: "prometheus", Password: "secret", RateLimit: 2, RateBurst: 5}.Validate())
+	assert.Error(t, MetricsAccess{Username: "prometheus"}.Validate())
+	assert.Error(t, MetricsAccess{RateLimit: -1}.Validate())
+	assert.Error(t, MetricsAccess{RateLimit: 2}.Validate())
+}
+
+func TestMetricsAccessGuard(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	serve := func(handler http.Handler, path string, setAuth func(*http.Request)) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if setAuth != nil {
+			setAuth(request)
+		}
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	handler := MetricsAccess{Username: "prometheus", Password: "secret", BearerToken: "t0ken"}.guard(ok)
+	recorder := serve(handler, "/metrics", nil)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, `Basic realm="prysm"`, recorder.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") }).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "secret") }).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer t0ken") }).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, "/healthz", nil).Code, "open for the kubelet")
+
+	// Three requests at once, then one a second
+	handler = MetricsAccess{RateLimit: 1, RateBurst: 3}.guard(ok)
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serve(handler, "/metrics", nil).Code)
+	}
+	recorder = serve(handler, "/metrics", nil)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serve(handler, "/readyz", nil).Code)
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newClientRateLimiter(0.5, 2)
+	assert.True(t, limiter.allow("10.0.0.1", now))
+	assert.True(t, limiter.allow("10.0.0.1", now))
+	assert.False(t, limiter.allow("10.0.0.1", now))
+	assert.True(t, limiter.allow("10.0.0.2", now), "limited per client address")
+	assert.False(t, limiter.allow("10.0.0.1", now.Add(time.Second)))
+	assert.True(t, limiter.allow("10.0.0.1", now.Add(2*time.Second)))
+
+	// Clients with a full bucket again are forgotten
+	limiter.allow("10.0.0.3", now.Add(2*time.Minute))
+	assert.Len(t, limiter.clients, 1)
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMetricsRateBurst is the number of requests a client address may
+// send at once before --metrics-rate-limit applies
+const DefaultMetricsRateBurst = 10
+
+// MetricsAccess protects the metrics server of producers on shared
+// networks. Requests authenticate with basic auth or a bearer token when set,
+// either is accepted when both are, and every client address is limited to
+// RateLimit requests a second. The health checks on /healthz and /readyz
+// stay open for the kubelet.
+type MetricsAccess struct {
+	Username    string
+	Password    string
+	BearerToken string
+	RateLimit   float64 // requests a second per client address, unlimited if 0
+	RateBurst   int
+}
+
+// Validate fails unless both or none of the username and password are set,
+// and on a negative rate or a rate without burst
+func (a MetricsAccess) Validate() error {
+	if (a.Username == "") != (a.Password == "") {
+		return errors.New("metrics basic auth requires both a username and a password")
+	}
+	if a.RateLimit < 0 {
+		return errors.New("metrics rate limit must not be negative")
+	}
+	if a.RateLimit > 0 && a.RateBurst < 1 {
+		return errors.New("metrics rate burst must be positive")
+	}
+	return nil
+}
+
+func (a MetricsAccess) authenticated() bool {
+	return a.Username != "" || a.BearerToken != ""
+}
+
+// Access control of the metrics server, set once on startup by
+// ConfigureMetricsAccess
+var metricsAccess MetricsAccess
+
+func ConfigureMetricsAccess(a MetricsAccess) {
+	metricsAccess = a
+}
+
+// GuardMetricsAccess wraps a handler served besides the metrics, e.g. by the
+// debug server, with the access control of the metrics server
+func GuardMetricsAccess(next http.Handler) http.Handler {
+	return metricsAccess.guard(next)
+}
+
+// metricsRequestsRejected counts the requests of the metrics server
+// rejected, registered with the metrics server
+var metricsRequestsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "prysm_metrics_requests_rejected_total",
+	Help: "Requests of the metrics server rejected, unauthorized or rate limited",
+}, []string{"reason"})
+
+// Paths served without authentication and rate limit
+var openMetricsPaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// guard wraps the handler of the metrics server with the access control
+func (a MetricsAccess) guard(next http.Handler) http.Handler {
+	if !a.authenticated() && a.RateLimit == 0 {
+		return next
+	}
+	var limiter *clientRateLimiter
+	if a.RateLimit > 0 {
+		limiter = newClientRateLimiter(a.RateLimit, a.RateBurst)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if openMetricsPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if limiter != nil && !limiter.allow(clientHost(r.RemoteAddr), time.Now()) {
+			metricsRequestsRejected.WithLabelValues("rate_limited").Inc()
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		if a.authenticated() && !a.authorize(r) {
+			metricsRequestsRejected.WithLabelValues("unauthorized").Inc()
+			if a.Username != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="prysm"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorize checks the credentials of a request in constant time
+func (a MetricsAccess) authorize(r *http.Request) bool {
+	if a.Username != "" {
+		if username, password, ok := r.BasicAuth(); ok {
+			return equalSecret(username, a.Username) && equalSecret(password, a.Password)
+		}
+	}
+	if a.BearerToken != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			return equalSecret(strings.TrimSpace(token), a.BearerToken)
+		}
+	}
+	return false
+}
+
+func equalSecret(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
+
+// clientHost strips the port of the remote address of a request. The
+// address is the one connecting, X-Forwarded-For is not trusted.
+func clientHost(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// clientRateLimiter is a token bucket per client address: a client may send
+// burst requests at once, refilled at rate a second
+type clientRateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newClientRateLimiter(rate float64, burst int) *clientRateLimiter {
+	return &clientRateLimiter{rate: rate, burst: float64(burst), clients: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token of the client, false if it has none left
+func (l *clientRateLimiter) allow(client string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	bucket := l.clients[client]
+	if bucket == nil {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = bucket
+	}
+	if elapsed := now.Sub(bucket.last).Seconds(); elapsed > 0 {
+		bucket.tokens = min(l.burst, bucket.tokens+elapsed*l.rate)
+		bucket.last = now
+	}
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// sweep forgets the clients with a full bucket again, at most once a minute
+func (l *clientRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for client, bucket := range l.clients {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
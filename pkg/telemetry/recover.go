@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// panicsTotal counts the panics recovered from the workers, registered with
+// the metrics server
+var panicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "prysm_panics_total",
+	Help: "Panics recovered from long-running workers, which were restarted or dropped instead of killing the process",
+}, []string{"worker"})
+
+// Delays before restarting a worker that panicked, doubling from the first
+// to the last on consecutive panics
+var (
+	workerRestartDelay    = time.Second
+	maxWorkerRestartDelay = time.Minute
+)
+
+// ReportPanic logs the structured report of a panic recovered from worker,
+// with the stack of the panicking goroutine, and counts it in
+// prysm_panics_total. Call it from the deferred function that recovered.
+func ReportPanic(worker string, r any) {
+	panicsTotal.WithLabelValues(worker).Inc()
+	log.Error().
+		Str("worker", worker).
+		Str("panic", fmt.Sprint(r)).
+		Str("stack", string(debug.Stack())).
+		Msg("worker_panicked")
+}
+
+// RecoverPanic reports a panic of the calling goroutine instead of killing
+// the process, for goroutines that are not restarted, e.g. of a connection:
+//
+//	defer telemetry.RecoverPanic("ops-log.connection")
+func RecoverPanic(worker string) {
+	if r := recover(); r != nil {
+		ReportPanic(worker, r)
+	}
+}
+
+// RunWorker runs a long-running worker, e.g. a sync loop, a tailer or a
+// scanner, until it returns or ctx is canceled. A panic of the worker is
+// reported and the worker restarted after a delay, growing while it keeps
+// panicking right after starting, instead of killing the process.
+func RunWorker(ctx context.Context, worker string, run func(ctx context.Context)) {
+	delay := workerRestartDelay
+	for {
+		started := time.Now()
+		if !runRecovered(ctx, worker, run) || ctx.Err() != nil {
+			return
+		}
+
+		// A worker that ran a while before panicking starts over with the
+		// first delay
+		if time.Since(started) > maxWorkerRestartDelay {
+			delay = workerRestartDelay
+		}
+		log.Warn().Str("worker", worker).Dur("delay", delay).Msg("worker_restarting")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxWorkerRestartDelay)
+	}
+}
+
+// runRecovered runs the worker once and reports whether it panicked
+func runRecovered(ctx context.Context, worker string, run func(ctx context.Context)) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ReportPanic(worker, r)
+			panicked = true
+		}
+	}()
+	run(ctx)
+	return false
+}
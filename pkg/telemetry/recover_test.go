@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWorker_RestartsAfterPanic(t *testing.T) {
+	workerRestartDelay, maxWorkerRestartDelay = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { workerRestartDelay, maxWorkerRestartDelay = time.Second, time.Minute })
+
+	runs := 0
+	RunWorker(context.Background(), "test.restart", func(context.Context) {
+		runs++
+		if runs < 3 {
+			panic("boom")
+		}
+	})
+
+	assert.Equal(t, 3, runs, "the worker returned after two panics")
+	assert.Equal(t, 2.0, testutil.ToFloat64(panicsTotal.WithLabelValues("test.restart")))
+}
+
+func TestRunWorker_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	RunWorker(ctx, "test.cancel", func(context.Context) {
+		runs++
+		cancel()
+		panic("boom")
+	})
+	assert.Equal(t, 1, runs, "a canceled worker is not restarted")
+}
+
+func TestRecoverPanic(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer RecoverPanic("test.recover")
+		panic("boom")
+	}()
+	<-done
+	assert.Equal(t, 1.0, testutil.ToFloat64(panicsTotal.WithLabelValues("test.recover")))
+}
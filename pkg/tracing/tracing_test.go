@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Endpoint: "http://tempo:4318", SampleRatio: 1}.Validate())
+	assert.Error(t, Config{Endpoint: "tempo:4318", SampleRatio: 1}.Validate())
+	assert.Error(t, Config{Endpoint: "https://tempo:4318", SampleRatio: 1.5}.Validate())
+}
+
+func TestTraceURL(t *testing.T) {
+	assert.Equal(t, "http://tempo:4318/v1/traces", traceURL("http://tempo:4318"))
+	assert.Equal(t, "http://tempo:4318/v1/traces", traceURL("http://tempo:4318/"))
+	assert.Equal(t, "https://otel/custom/traces", traceURL("https://otel/custom/traces"))
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = otel.Tracer("test").Start(context.Background(), "failed")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "cycle")
+	for _, path := range []string{"/admin/user?uid=secret", "/admin/broken"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	user, broken := spans[0], spans[1]
+	assert.Equal(t, "HTTP GET", user.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), user.Parent().SpanID())
+	assert.Contains(t, user.Attributes(), attribute.String("url.path", "/admin/user"))
+	assert.Contains(t, user.Attributes(), attribute.Int("http.response.status_code", 200))
+	assert.Equal(t, codes.Unset, user.Status().Code)
+	assert.Equal(t, codes.Error, broken.Status().Code)
+}
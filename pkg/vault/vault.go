@@ -0,0 +1,449 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vault reads the secrets of the prysm subcommands from HashiCorp
+// Vault instead of plaintext flags and environment variables. A secret is
+// referenced as vault:<path>#<field>, e.g.
+// vault:secret/data/prysm/rgw#access_key for the field access_key of the KV
+// v2 secret prysm/rgw. The secrets are read once on startup; the token and
+// the leases of dynamic secrets are renewed while the process runs.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cobaltcore-dev/prysm/pkg/telemetry"
+	"github.com/rs/zerolog/log"
+)
+
+// Prefix of the values that reference a secret in Vault
+const RefPrefix = "vault:"
+
+// Defaults of the Kubernetes auth method
+const (
+	DefaultKubernetesMount     = "kubernetes"
+	DefaultServiceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Config of the Vault server and how prysm authenticates with it: with
+// Token or the token in TokenFile, e.g. written by the Vault agent, or by
+// logging in with the service account token of the pod as KubernetesRole
+type Config struct {
+	Address         string // e.g. https://vault:8200; empty disables Vault
+	Token           string
+	TokenFile       string
+	KubernetesRole  string
+	KubernetesMount string // Mount of the Kubernetes auth method, defaults to kubernetes
+	Namespace       string // Vault Enterprise namespace
+	CACert          string // CA file verifying the server
+}
+
+// Validate fails on an address without a way to authenticate
+func (c Config) Validate() error {
+	if c.Address == "" {
+		return nil
+	}
+	if c.Token == "" && c.TokenFile == "" && c.KubernetesRole == "" {
+		return errors.New("vault requires a token, a token file or a Kubernetes role")
+	}
+	return nil
+}
+
+// IsRef reports whether the value references a secret in Vault
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// ParseRef splits a reference into the path and the field of the secret
+func ParseRef(ref string) (path, field string, err error) {
+	path, field, ok := strings.Cut(strings.TrimPrefix(ref, RefPrefix), "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || field == "" {
+		return "", "", fmt.Errorf("invalid vault reference %q, expected vault:<path>#<field>", ref)
+	}
+	return path, field, nil
+}
+
+// Client reads the secrets and renews the token and the leases
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu     sync.Mutex
+	token  lease // The token, its lease ID unused
+	leases map[string]*lease
+	paths  map[string]map[string]any // Secrets read, by path
+}
+
+// lease of the token or of a dynamic secret, renewed at two thirds of its
+// duration
+type lease struct {
+	id        string
+	renewable bool
+	expires   time.Time // Zero if it never expires
+	renewAt   time.Time
+}
+
+func newLease(id string, renewable bool, duration int, now time.Time) lease {
+	l := lease{id: id, renewable: renewable}
+	if duration > 0 {
+		d := time.Duration(duration) * time.Second
+		l.expires = now.Add(d)
+		l.renewAt = now.Add(d * 2 / 3)
+	}
+	return l
+}
+
+// Response of the Vault API, of reads, logins and renewals
+type response struct {
+	LeaseID       string         `json:"lease_id"`
+	Renewable     bool           `json:"renewable"`
+	LeaseDuration int            `json:"lease_duration"`
+	Data          map[string]any `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		Renewable     bool   `json:"renewable"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// New authenticates with the server of cfg
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	if cfg.KubernetesMount == "" {
+		cfg.KubernetesMount = DefaultKubernetesMount
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the vault CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in the vault CA %s", cfg.CACert)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	c := &Client{
+		cfg:    cfg,
+		http:   &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		leases: make(map[string]*lease),
+		paths:  make(map[string]map[string]any),
+	}
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// authenticate logs in with the Kubernetes role, or looks up the lease of
+// the configured token
+func (c *Client) authenticate(ctx context.Context) error {
+	now := time.Now()
+	if c.cfg.KubernetesRole != "" {
+		jwt, err := os.ReadFile(DefaultServiceAccountToken)
+		if err != nil {
+			return fmt.Errorf("error reading the service account token: %w", err)
+		}
+		body := map[string]string{"role": c.cfg.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))}
+		resp, err := c.do(ctx, http.MethodPost, "auth/"+c.cfg.KubernetesMount+"/login", "", body)
+		if err != nil {
+			return fmt.Errorf("error logging in to vault as %s: %w", c.cfg.KubernetesRole, err)
+		}
+		if resp.Auth == nil || resp.Auth.ClientToken == "" {
+			return errors.New("vault login returned no token")
+		}
+		c.setToken(resp.Auth.ClientToken, newLease("", resp.Auth.Renewable, resp.Auth.LeaseDuration, now))
+		return nil
+	}
+
+	token := c.cfg.Token
+	if token == "" {
+		data, err := os.ReadFile(c.cfg.TokenFile)
+		if err != nil {
+			return fmt.Errorf("error reading the vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	resp, err := c.do(ctx, http.MethodGet, "auth/token/lookup-self", token, nil)
+	if err != nil {
+		return fmt.Errorf("error looking up the vault token: %w", err)
+	}
+	ttl, _ := resp.Data["ttl"].(float64)
+	renewable, _ := resp.Data["renewable"].(bool)
+	c.setToken(token, newLease("", renewable, int(ttl), now))
+	return nil
+}
+
+func (c *Client) setToken(token string, l lease) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l.id = token
+	c.token = l
+}
+
+func (c *Client) currentToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token.id
+}
+
+// Read returns the field of the secret at path, read once per path. The
+// data of KV v2 secrets is unwrapped.
+func (c *Client) Read(ctx context.Context, path, field string) (string, error) {
+	c.mu.Lock()
+	data, found := c.paths[path]
+	c.mu.Unlock()
+	if !found {
+		resp, err := c.do(ctx, http.MethodGet, path, c.currentToken(), nil)
+		if err != nil {
+			return "", fmt.Errorf("error reading %s from vault: %w", path, err)
+		}
+		data = resp.Data
+		if inner, ok := data["data"].(map[string]any); ok && data["metadata"] != nil {
+			data = inner
+		}
+
+		c.mu.Lock()
+		c.paths[path] = data
+		if resp.LeaseID != "" {
+			l := newLease(resp.LeaseID, resp.Renewable, resp.LeaseDuration, time.Now())
+			c.leases[path] = &l
+		}
+		c.mu.Unlock()
+	}
+
+	switch value := data[field].(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "", fmt.Errorf("no field %s in the vault secret %s", field, path)
+	default:
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+}
+
+// Resolve returns the secret a reference points to, other values as they are
+func (c *Client) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	path, field, err := ParseRef(value)
+	if err != nil {
+		return "", err
+	}
+	return c.Read(ctx, path, field)
+}
+
+// do sends a request to the Vault API
+func (c *Client) do(ctx context.Context, method, path, token string, body any) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.cfg.Address, "/")+"/v1/"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.cfg.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var resp response
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&resp); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid response (%s): %w", res.Status, err)
+	}
+	if res.StatusCode >= 300 {
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("%s: %s", res.Status, strings.Join(resp.Errors, "; "))
+		}
+		return nil, errors.New(res.Status)
+	}
+	return &resp, nil
+}
+
+// Run renews the token and the leases until ctx is done. A token that can
+// no longer be renewed is replaced by logging in again with the Kubernetes
+// role; a secret whose lease can no longer be renewed keeps its value until
+// the process is restarted.
+func (c *Client) Run(ctx context.Context) {
+	for {
+		next := c.renewDue(ctx, time.Now())
+		wait := time.Minute
+		if !next.IsZero() {
+			wait = max(time.Until(next), time.Second)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// renewDue renews the token and the leases due at now and returns when the
+// next one is due, zero if none is
+func (c *Client) renewDue(ctx context.Context, now time.Time) time.Time {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if !token.renewAt.IsZero() && !now.Before(token.renewAt) {
+		c.renewToken(ctx, token, now)
+	}
+
+	c.mu.Lock()
+	due := make(map[string]lease)
+	for path, l := range c.leases {
+		if !l.renewAt.IsZero() && !now.Before(l.renewAt) {
+			due[path] = *l
+		}
+	}
+	c.mu.Unlock()
+	for path, l := range due {
+		c.renewLease(ctx, path, l, now)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next := c.token.renewAt
+	for _, l := range c.leases {
+		if !l.renewAt.IsZero() && (next.IsZero() || l.renewAt.Before(next)) {
+			next = l.renewAt
+		}
+	}
+	return next
+}
+
+func (c *Client) renewToken(ctx context.Context, token lease, now time.Time) {
+	if token.renewable {
+		resp, err := c.do(ctx, http.MethodPost, "auth/token/renew-self", token.id, struct{}{})
+		if err == nil && resp.Auth != nil {
+			c.setToken(token.id, newLease("", resp.Auth.Renewable, resp.Auth.LeaseDuration, now))
+			log.Debug().Int("lease_duration", resp.Auth.LeaseDuration).Msg("renewed the vault token")
+			return
+		}
+		log.Warn().Err(err).Msg("failed to renew the vault token")
+	}
+	if c.cfg.KubernetesRole != "" {
+		if err := c.authenticate(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to log in to vault again")
+			c.retryTokenLater(now)
+		}
+		return
+	}
+	if token.expires.After(now) {
+		c.retryTokenLater(now)
+		return
+	}
+	log.Error().Time("expired", token.expires).Msg("the vault token expired and cannot be renewed, secrets are no longer read or renewed")
+	c.mu.Lock()
+	c.token.renewAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// retryTokenLater retries renewing the token in a minute, or not at all once
+// it expired
+func (c *Client) retryTokenLater(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token.expires.IsZero() || now.Add(time.Minute).Before(c.token.expires) {
+		c.token.renewAt = now.Add(time.Minute)
+	} else {
+		c.token.renewAt = time.Time{}
+	}
+}
+
+func (c *Client) renewLease(ctx context.Context, path string, l lease, now time.Time) {
+	var renewed lease
+	if l.renewable {
+		resp, err := c.do(ctx, http.MethodPut, "sys/leases/renew", c.currentToken(), map[string]string{"lease_id": l.id})
+		if err == nil {
+			renewed = newLease(resp.LeaseID, resp.Renewable, resp.LeaseDuration, now)
+			log.Debug().Str("path", path).Int("lease_duration", resp.LeaseDuration).Msg("renewed the lease of a vault secret")
+		} else {
+			log.Warn().Err(err).Str("path", path).Msg("failed to renew the lease of a vault secret")
+		}
+	}
+	if renewed.id == "" {
+		// Not renewed, e.g. at its maximum TTL: retried until it expires
+		renewed = l
+		if now.Add(time.Minute).Before(l.expires) {
+			renewed.renewAt = now.Add(time.Minute)
+		} else {
+			renewed.renewAt = time.Time{}
+			log.Error().Str("path", path).Time("expires", l.expires).Msg("the lease of a vault secret expires and cannot be renewed, restart to read the secret again")
+		}
+	}
+	c.mu.Lock()
+	c.leases[path] = &renewed
+	c.mu.Unlock()
+}
+
+// Client of the process, set once on startup by Configure
+var (
+	client *Client
+	cancel context.CancelFunc = func() {}
+)
+
+// Configure authenticates with the Vault server of cfg, if it has an
+// address, and renews the token and the leases in the background
+func Configure(cfg Config) error {
+	cancel()
+	client, cancel = nil, func() {}
+	if cfg.Address == "" {
+		return nil
+	}
+	c, err := New(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	go telemetry.RunWorker(ctx, "vault.renewal", c.Run)
+	client, cancel = c, stop
+	log.Info().Str("vault_addr", cfg.Address).Msg("vault configured")
+	return nil
+}
+
+// Resolve returns the secret a reference points to with the configured
+// client, other values as they are
+func Resolve(value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	if client == nil {
+		return "", fmt.Errorf("vault reference %q requires --vault-addr or VAULT_ADDR", value)
+	}
+	ctx, done := context.WithTimeout(context.Background(), time.Minute)
+	defer done()
+	return client.Resolve(ctx, value)
+}
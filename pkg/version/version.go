@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package version holds the build metadata of the prysm binary, injected at
+// build time:
+//
+//	go build -ldflags "-X github.com/cobaltcore-dev/prysm/pkg/version.Version=v1.2.3 \
+//	  -X github.com/cobaltcore-dev/prysm/pkg/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/cobaltcore-dev/prysm/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//	  -X github.com/cobaltcore-dev/prysm/pkg/version.Features=smartctl,nvme-cli"
+//
+// Without ldflags, the commit and build date are taken from the VCS
+// information the go command embeds when building from a git checkout.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set with -ldflags "-X ..."
+var (
+	Version   = "development"
+	Commit    = ""
+	BuildDate = ""
+	Features  = "" // Comma-separated features of the build, e.g. the tools shipped in the image
+)
+
+// Info is the build metadata
+type Info struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	BuildDate string   `json:"build_date"`
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Features  []string `json:"features"`
+}
+
+// Get returns the build metadata, with "unknown" for what is not known
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  []string{},
+	}
+	for _, feature := range strings.Split(Features, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			info.Features = append(info.Features, feature)
+		}
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+func (i Info) String() string {
+	features := "none"
+	if len(i.Features) > 0 {
+		features = strings.Join(i.Features, ", ")
+	}
+	return fmt.Sprintf("prysm %s\n  commit:     %s\n  build date: %s\n  go:         %s %s\n  features:   %s\n",
+		i.Version, i.Commit, i.BuildDate, i.GoVersion, i.Platform, features)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and prysm contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version, commit, date, features string) {
+		Version, Commit, BuildDate, Features = version, commit, date, features
+	}(Version, Commit, BuildDate, Features)
+
+	Version, Commit, BuildDate, Features = "v1.2.3", "abc123", "2025-01-02T03:04:05Z", "smartctl, nvme-cli,"
+	info := Get()
+	assert.Equal(t, Info{
+		Version:   "v1.2.3",
+		Commit:    "abc123",
+		BuildDate: "2025-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  []string{"smartctl", "nvme-cli"},
+	}, info)
+	assert.Contains(t, info.String(), "prysm v1.2.3\n")
+	assert.Contains(t, info.String(), "features:   smartctl, nvme-cli\n")
+}
+
+func TestGetWithoutLdflags(t *testing.T) {
+	defer func(commit, date, features string) {
+		Commit, BuildDate, Features = commit, date, features
+	}(Commit, BuildDate, Features)
+
+	Commit, BuildDate, Features = "", "", ""
+	info := Get()
+	// Test binaries carry no VCS information
+	assert.Equal(t, "unknown", info.Commit)
+	assert.Equal(t, "unknown", info.BuildDate)
+	assert.Empty(t, info.Features)
+	assert.Contains(t, info.String(), "features:   none\n")
+}